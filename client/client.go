@@ -0,0 +1,274 @@
+// Package client is a Go client of the finality provider daemon (fpd) for
+// third-party integrators, e.g., staking dashboards. It wraps the generated
+// gRPC stubs with typed helpers, retries of the queries on transient errors,
+// and the management of the connection.
+//
+//	c, err := client.New("127.0.0.1:12581")
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	fps, err := c.ListFinalityProviders(ctx)
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+const (
+	defaultTimeout    = 10 * time.Second
+	defaultMaxRetries = 3
+	defaultRetryDelay = 500 * time.Millisecond
+	keepaliveInterval = 30 * time.Second
+)
+
+// Client is a client of fpd, which is safe for concurrent use
+type Client struct {
+	conn   *grpc.ClientConn
+	client proto.FinalityProvidersClient
+
+	timeout     time.Duration
+	maxRetries  uint
+	retryDelay  time.Duration
+	dialOptions []grpc.DialOption
+}
+
+// Option configures the client
+type Option func(*Client)
+
+// WithTimeout sets the timeout of each call whose context has no deadline
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries sets the maximum number of retries of the queries on transient
+// errors and the initial delay between them, which backs off exponentially.
+// The calls changing the state of fpd are never retried
+func WithRetries(maxRetries uint, delay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = delay
+	}
+}
+
+// WithDialOptions appends the options of the gRPC connection, e.g., the
+// transport credentials, which is insecure by default
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(c *Client) {
+		c.dialOptions = append(c.dialOptions, opts...)
+	}
+}
+
+// New connects to fpd at the given address. The connection is established
+// lazily and re-established on failures, so New does not fail if fpd is down
+func New(addr string, opts ...Option) (*Client, error) {
+	c := &Client{
+		timeout:    defaultTimeout,
+		maxRetries: defaultMaxRetries,
+		retryDelay: defaultRetryDelay,
+		dialOptions: []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithKeepaliveParams(keepalive.ClientParameters{
+				Time:                keepaliveInterval,
+				PermitWithoutStream: false,
+			}),
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	conn, err := grpc.Dial(addr, c.dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build gRPC connection to %s: %w", addr, err)
+	}
+	c.conn = conn
+	c.client = proto.NewFinalityProvidersClient(conn)
+
+	return c, nil
+}
+
+// Close closes the connection to fpd
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Raw returns the generated gRPC client for the calls without a helper
+func (c *Client) Raw() proto.FinalityProvidersClient {
+	return c.client
+}
+
+// GetInfo returns the general information of fpd
+func (c *Client) GetInfo(ctx context.Context) (*proto.GetInfoResponse, error) {
+	return query(ctx, c, func(ctx context.Context) (*proto.GetInfoResponse, error) {
+		return c.client.GetInfo(ctx, &proto.GetInfoRequest{})
+	})
+}
+
+// ListFinalityProviders returns all the finality providers stored in fpd
+func (c *Client) ListFinalityProviders(ctx context.Context) ([]*proto.FinalityProviderInfo, error) {
+	res, err := query(ctx, c, func(ctx context.Context) (*proto.QueryFinalityProviderListResponse, error) {
+		return c.client.QueryFinalityProviderList(ctx, &proto.QueryFinalityProviderListRequest{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.FinalityProviders, nil
+}
+
+// GetFinalityProvider returns the finality provider with the given hex BTC
+// public key
+func (c *Client) GetFinalityProvider(ctx context.Context, btcPkHex string) (*proto.FinalityProviderInfo, error) {
+	res, err := query(ctx, c, func(ctx context.Context) (*proto.QueryFinalityProviderResponse, error) {
+		return c.client.QueryFinalityProvider(ctx, &proto.QueryFinalityProviderRequest{BtcPk: btcPkHex})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.FinalityProvider, nil
+}
+
+// GetFinalizedBlocks returns the latest finalized blocks of the consumer chain
+// in the config of fpd annotated with the votes of the finality providers in
+// fpd
+func (c *Client) GetFinalizedBlocks(ctx context.Context, limit uint64) ([]*proto.FinalizedBlock, error) {
+	res, err := query(ctx, c, func(ctx context.Context) (*proto.QueryFinalizedBlocksResponse, error) {
+		return c.client.QueryFinalizedBlocks(ctx, &proto.QueryFinalizedBlocksRequest{Limit: limit})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Blocks, nil
+}
+
+// GetFinalityProofs returns the finality proofs of the blocks from the start
+// height to the end height, or of the block at the start height only if the
+// end height is 0, for light clients to verify the finality of the blocks. The
+// blocks are of the consumer chain in the config of fpd
+func (c *Client) GetFinalityProofs(ctx context.Context, startHeight, endHeight uint64) ([]*proto.FinalityProof, error) {
+	res, err := query(ctx, c, func(ctx context.Context) (*proto.ExportFinalityProofsResponse, error) {
+		return c.client.ExportFinalityProofs(ctx, &proto.ExportFinalityProofsRequest{
+			StartHeight: startHeight,
+			EndHeight:   endHeight,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Proofs, nil
+}
+
+// GetNetworkParticipation returns the participation of all the finality
+// providers on the consumer chain, which requires fpd in observer mode
+func (c *Client) GetNetworkParticipation(ctx context.Context) (*proto.QueryNetworkParticipationResponse, error) {
+	return query(ctx, c, func(ctx context.Context) (*proto.QueryNetworkParticipationResponse, error) {
+		return c.client.QueryNetworkParticipation(ctx, &proto.QueryNetworkParticipationRequest{})
+	})
+}
+
+// GetStatus returns the tip of the consumer chain and the live status of all
+// the finality providers in fpd
+func (c *Client) GetStatus(ctx context.Context) (*proto.QueryStatusResponse, error) {
+	return query(ctx, c, func(ctx context.Context) (*proto.QueryStatusResponse, error) {
+		return c.client.QueryStatus(ctx, &proto.QueryStatusRequest{})
+	})
+}
+
+// VerifyMessage verifies the ADR-36 signature of a message by the signer,
+// e.g., to check that the operator of a finality provider controls its
+// account. An invalid signature is reported in the response with its reason
+func (c *Client) VerifyMessage(ctx context.Context, req *proto.VerifyMessageRequest) (*proto.VerifyMessageResponse, error) {
+	return query(ctx, c, func(ctx context.Context) (*proto.VerifyMessageResponse, error) {
+		return c.client.VerifyMessage(ctx, req)
+	})
+}
+
+// RegisterFinalityProvider registers a created finality provider to the
+// consumer chain and returns the tx hash. It is not retried, as the
+// registration may have succeeded despite an error
+func (c *Client) RegisterFinalityProvider(ctx context.Context, req *proto.RegisterFinalityProviderRequest) (string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.client.RegisterFinalityProvider(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return res.TxHash, nil
+}
+
+// SetRewardAddress sets the address receiving the rewards of a finality
+// provider and returns the tx hash. It is not retried for the same reason as
+// the registration
+func (c *Client) SetRewardAddress(ctx context.Context, req *proto.SetRewardAddressRequest) (string, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	res, err := c.client.SetRewardAddress(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return res.TxHash, nil
+}
+
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// query runs the query with retries on transient errors, each attempt of
+// which is bounded by the timeout of the client
+func query[T any](ctx context.Context, c *Client, call func(context.Context) (T, error)) (T, error) {
+	var res T
+	err := retry.Do(
+		func() error {
+			callCtx, cancel := c.withTimeout(ctx)
+			defer cancel()
+
+			var err error
+			res, err = call(callCtx)
+			return err
+		},
+		retry.Context(ctx),
+		retry.Attempts(c.maxRetries+1),
+		retry.Delay(c.retryDelay),
+		retry.RetryIf(IsTransient),
+		retry.LastErrorOnly(true),
+	)
+
+	return res, err
+}
+
+// IsTransient returns true if the error is likely to go away by retrying,
+// e.g., fpd is restarting or rate limiting the calls
+func IsTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
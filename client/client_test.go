@@ -0,0 +1,135 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/client"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// flakyServer fails the calls with the given error until it is called the
+// given number of times
+type flakyServer struct {
+	proto.UnimplementedFinalityProvidersServer
+	failures uint32
+	err      error
+	calls    atomic.Uint32
+}
+
+func (s *flakyServer) fail() error {
+	if s.calls.Inc() <= s.failures {
+		return s.err
+	}
+	return nil
+}
+
+func (s *flakyServer) QueryFinalityProviderList(context.Context, *proto.QueryFinalityProviderListRequest) (*proto.QueryFinalityProviderListResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return &proto.QueryFinalityProviderListResponse{
+		FinalityProviders: []*proto.FinalityProviderInfo{{BtcPkHex: "btc-pk"}},
+	}, nil
+}
+
+func (s *flakyServer) RegisterFinalityProvider(context.Context, *proto.RegisterFinalityProviderRequest) (*proto.RegisterFinalityProviderResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return &proto.RegisterFinalityProviderResponse{TxHash: "tx-hash"}, nil
+}
+
+func (s *flakyServer) GetInfo(ctx context.Context, _ *proto.GetInfoRequest) (*proto.GetInfoResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func newTestClient(t *testing.T, s *flakyServer, opts ...client.Option) *client.Client {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	proto.RegisterFinalityProvidersServer(server, s)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	c, err := client.New(lis.Addr().String(), append([]client.Option{client.WithRetries(2, time.Millisecond)}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+
+	return c
+}
+
+func TestClientRetries(t *testing.T) {
+	unavailableErr := status.Error(codes.Unavailable, "fpd is restarting")
+
+	t.Run("a query is retried on transient errors", func(t *testing.T) {
+		s := &flakyServer{failures: 2, err: unavailableErr}
+		c := newTestClient(t, s)
+
+		fps, err := c.ListFinalityProviders(context.Background())
+		require.NoError(t, err)
+		require.Len(t, fps, 1)
+		require.Equal(t, "btc-pk", fps[0].BtcPkHex)
+		require.Equal(t, uint32(3), s.calls.Load())
+	})
+
+	t.Run("a query fails once the retries are exhausted", func(t *testing.T) {
+		s := &flakyServer{failures: 3, err: unavailableErr}
+		c := newTestClient(t, s)
+
+		_, err := c.ListFinalityProviders(context.Background())
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, uint32(3), s.calls.Load())
+	})
+
+	t.Run("a query is not retried on other errors", func(t *testing.T) {
+		s := &flakyServer{failures: 1, err: status.Error(codes.NotFound, "the finality provider is not found")}
+		c := newTestClient(t, s)
+
+		_, err := c.ListFinalityProviders(context.Background())
+		require.Equal(t, codes.NotFound, status.Code(err))
+		require.Equal(t, uint32(1), s.calls.Load())
+	})
+
+	t.Run("a registration is never retried", func(t *testing.T) {
+		s := &flakyServer{failures: 1, err: unavailableErr}
+		c := newTestClient(t, s)
+
+		_, err := c.RegisterFinalityProvider(context.Background(), &proto.RegisterFinalityProviderRequest{})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, uint32(1), s.calls.Load())
+
+		txHash, err := c.RegisterFinalityProvider(context.Background(), &proto.RegisterFinalityProviderRequest{})
+		require.NoError(t, err)
+		require.Equal(t, "tx-hash", txHash)
+	})
+
+	t.Run("each attempt is bounded by the timeout of the client", func(t *testing.T) {
+		c := newTestClient(t, &flakyServer{}, client.WithTimeout(10*time.Millisecond), client.WithRetries(0, 0))
+
+		_, err := c.GetInfo(context.Background())
+		require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	require.True(t, client.IsTransient(status.Error(codes.Unavailable, "")))
+	require.True(t, client.IsTransient(status.Error(codes.ResourceExhausted, "")))
+	require.False(t, client.IsTransient(status.Error(codes.InvalidArgument, "")))
+	require.False(t, client.IsTransient(errors.New("not a gRPC error")))
+	require.False(t, client.IsTransient(nil))
+}
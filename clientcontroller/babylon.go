@@ -3,10 +3,12 @@ package clientcontroller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	sdkErr "cosmossdk.io/errors"
 	"cosmossdk.io/math"
+	bbnapp "github.com/babylonchain/babylon/app"
 	bbnclient "github.com/babylonchain/babylon/client/client"
 	bbntypes "github.com/babylonchain/babylon/types"
 	btcctypes "github.com/babylonchain/babylon/x/btccheckpoint/types"
@@ -18,9 +20,13 @@ import (
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	cmtcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+	cmttypes "github.com/cometbft/cometbft/types"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	sdkquery "github.com/cosmos/cosmos-sdk/types/query"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	distrtypes "github.com/cosmos/cosmos-sdk/x/distribution/types"
 	sttypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/cosmos/relayer/v2/relayer/provider"
 	"go.uber.org/zap"
@@ -33,11 +39,29 @@ var _ ClientController = &BabylonController{}
 
 var emptyErrs = []*sdkErr.Error{}
 
+const (
+	// finalitySigSearchWindow is the number of the blocks after a height
+	// within which its finality signatures are searched
+	finalitySigSearchWindow = 1000
+	// finalitySigSearchPageSize is the number of the txs searched per page,
+	// which is the maximum of CometBFT
+	finalitySigSearchPageSize = 100
+)
+
+// bankAllBalancesPath is the gRPC path of the query of all the balances of an
+// account
+const bankAllBalancesPath = "/cosmos.bank.v1beta1.Query/AllBalances"
+
 type BabylonController struct {
 	bbnClient *bbnclient.Client
 	cfg       *fpcfg.BBNConfig
 	btcParams *chaincfg.Params
 	logger    *zap.Logger
+
+	// txSender signs and broadcasts all the txs, so that the signed txs of
+	// the finality signatures, which are kept until they are included, take
+	// their sequences from the same provider as the other txs
+	txSender *txSender
 }
 
 func NewBabylonController(
@@ -60,11 +84,41 @@ func NewBabylonController(
 		return nil, fmt.Errorf("failed to create Babylon client: %w", err)
 	}
 
+	memo, err := cfg.RenderTxMemo()
+	if err != nil {
+		return nil, err
+	}
+	broadcastMode := cfg.BroadcastMode
+	if broadcastMode == "" {
+		broadcastMode = fpcfg.BroadcastModeSync
+	}
+	ts, err := newTxSender(&bbnConfig, memo, broadcastMode, logger)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.HasDynamicGasPrice() {
+		ts.gasPrices, err = newGasPriceOracle(cfg, ts.provider, logger)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("the gas price of the transactions will be picked from a source",
+			zap.String("source", cfg.GasPriceSource),
+			zap.String("min_gas_price", cfg.MinGasPrice),
+			zap.String("max_gas_price", cfg.MaxGasPrice))
+	}
+	if memo != "" {
+		logger.Info("the transactions will be tagged with a memo", zap.String("memo", memo))
+	}
+	if broadcastMode != fpcfg.BroadcastModeSync {
+		logger.Info("the transactions will be broadcast in a mode other than sync", zap.String("broadcast_mode", broadcastMode))
+	}
+
 	return &BabylonController{
-		bc,
-		cfg,
-		btcParams,
-		logger,
+		bbnClient: bc,
+		cfg:       cfg,
+		btcParams: btcParams,
+		logger:    logger,
+		txSender:  ts,
 	}, nil
 }
 
@@ -99,13 +153,29 @@ func (bc *BabylonController) reliablySendMsg(msg sdk.Msg, expectedErrs []*sdkErr
 	return bc.reliablySendMsgs([]sdk.Msg{msg}, expectedErrs, unrecoverableErrs)
 }
 
+// reliablySendMsgs sends the messages through the sender of the txs, whose
+// failures are told apart by the callers through IsUnrecoverable
 func (bc *BabylonController) reliablySendMsgs(msgs []sdk.Msg, expectedErrs []*sdkErr.Error, unrecoverableErrs []*sdkErr.Error) (*provider.RelayerTxResponse, error) {
-	return bc.bbnClient.ReliablySendMsgs(
-		context.Background(),
-		msgs,
-		expectedErrs,
-		unrecoverableErrs,
-	)
+	return bc.txSender.sendMsgs(context.Background(), msgs, bc.gasAdjustment(msgs), expectedErrs)
+}
+
+// gasAdjustment returns the gas adjustment of the kind of the messages, which
+// falls back to the gas adjustment of the config
+func (bc *BabylonController) gasAdjustment(msgs []sdk.Msg) float64 {
+	var adjustment float64
+	switch msgs[0].(type) {
+	case *btcstakingtypes.MsgCreateFinalityProvider:
+		adjustment = bc.cfg.RegistrationGasAdjustment
+	case *finalitytypes.MsgCommitPubRandList:
+		adjustment = bc.cfg.PubRandGasAdjustment
+	case *finalitytypes.MsgAddFinalitySig:
+		adjustment = bc.cfg.FinalitySigGasAdjustment
+	}
+	if adjustment == 0 {
+		return bc.cfg.GasAdjustment
+	}
+
+	return adjustment
 }
 
 // RegisterFinalityProvider registers a finality provider via a MsgCreateFinalityProvider to Babylon
@@ -141,7 +211,30 @@ func (bc *BabylonController) RegisterFinalityProvider(
 		return nil, err
 	}
 
-	return &types.TxResponse{TxHash: res.TxHash, Events: res.Events}, nil
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
+}
+
+// SetRewardAddress sets the withdraw address of the account of the chain key
+// via a MsgSetWithdrawAddress to Babylon, so that the rewards are received by
+// another account than the one paying the fees
+func (bc *BabylonController) SetRewardAddress(chainPk []byte, rewardAddr string) (*types.TxResponse, error) {
+	chainAddr := sdk.AccAddress((&secp256k1.PubKey{Key: chainPk}).Address())
+	if !chainAddr.Equals(bc.GetKeyAddress()) {
+		return nil, fmt.Errorf("the reward address of the account %s can only be set by its key, but the txs are signed by %s",
+			sdk.MustBech32ifyAddressBytes(bc.cfg.AccountPrefix, chainAddr), bc.mustGetTxSigner())
+	}
+
+	msg := &distrtypes.MsgSetWithdrawAddress{
+		DelegatorAddress: bc.mustGetTxSigner(),
+		WithdrawAddress:  rewardAddr,
+	}
+
+	res, err := bc.reliablySendMsg(msg, emptyErrs, emptyErrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
 }
 
 // CommitPubRandList commits a list of Schnorr public randomness via a MsgCommitPubRand to Babylon
@@ -167,6 +260,9 @@ func (bc *BabylonController) CommitPubRandList(
 		finalitytypes.ErrTooFewPubRand,
 		finalitytypes.ErrNoPubRandYet,
 		btcstakingtypes.ErrFpNotFound,
+		// an oversized commit is split by the finality provider instead
+		sdkerrors.ErrTxTooLarge,
+		sdkerrors.ErrOutOfGas,
 	}
 
 	res, err := bc.reliablySendMsg(msg, emptyErrs, unrecoverableErrs)
@@ -174,7 +270,7 @@ func (bc *BabylonController) CommitPubRandList(
 		return nil, err
 	}
 
-	return &types.TxResponse{TxHash: res.TxHash, Events: res.Events}, nil
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
 }
 
 // SubmitFinalitySig submits the finality signature via a MsgAddVote to Babylon
@@ -185,12 +281,38 @@ func (bc *BabylonController) SubmitFinalitySig(
 	proof []byte, // TODO: have a type for proof
 	sig *btcec.ModNScalar,
 ) (*types.TxResponse, error) {
+	msg, err := bc.newMsgAddFinalitySig(fpPk, block, pubRand, proof, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	unrecoverableErrs := []*sdkErr.Error{
+		finalitytypes.ErrInvalidFinalitySig,
+		finalitytypes.ErrPubRandNotFound,
+		btcstakingtypes.ErrFpAlreadySlashed,
+	}
+
+	res, err := bc.reliablySendMsg(msg, emptyErrs, unrecoverableErrs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
+}
+
+func (bc *BabylonController) newMsgAddFinalitySig(
+	fpPk *btcec.PublicKey,
+	block *types.BlockInfo,
+	pubRand *btcec.FieldVal,
+	proof []byte,
+	sig *btcec.ModNScalar,
+) (*finalitytypes.MsgAddFinalitySig, error) {
 	cmtProof := cmtcrypto.Proof{}
 	if err := cmtProof.Unmarshal(proof); err != nil {
 		return nil, err
 	}
 
-	msg := &finalitytypes.MsgAddFinalitySig{
+	return &finalitytypes.MsgAddFinalitySig{
 		Signer:       bc.mustGetTxSigner(),
 		FpBtcPk:      bbntypes.NewBIP340PubKeyFromBTCPK(fpPk),
 		BlockHeight:  block.Height,
@@ -198,20 +320,53 @@ func (bc *BabylonController) SubmitFinalitySig(
 		Proof:        &cmtProof,
 		BlockAppHash: block.Hash,
 		FinalitySig:  bbntypes.NewSchnorrEOTSSigFromModNScalar(sig),
+	}, nil
+}
+
+// SignFinalitySigTx signs the tx of the finality signature without
+// broadcasting it. The tx takes the next sequence of the account, so it has to
+// be broadcast before the sequence is taken by another tx
+func (bc *BabylonController) SignFinalitySigTx(
+	fpPk *btcec.PublicKey,
+	block *types.BlockInfo,
+	pubRand *btcec.FieldVal,
+	proof []byte,
+	sig *btcec.ModNScalar,
+) (*types.SignedTx, error) {
+	msg, err := bc.newMsgAddFinalitySig(fpPk, block, pubRand, proof, sig)
+	if err != nil {
+		return nil, err
 	}
 
-	unrecoverableErrs := []*sdkErr.Error{
-		finalitytypes.ErrInvalidFinalitySig,
-		finalitytypes.ErrPubRandNotFound,
-		btcstakingtypes.ErrFpAlreadySlashed,
+	msgs := []sdk.Msg{msg}
+	txBytes, err := bc.txSender.signMsgs(context.Background(), msgs, bc.gasAdjustment(msgs))
+	if err != nil {
+		return nil, err
 	}
 
-	res, err := bc.reliablySendMsg(msg, emptyErrs, unrecoverableErrs)
+	return &types.SignedTx{Bytes: txBytes, Hash: cmttypes.Tx(txBytes).Hash()}, nil
+}
+
+// BroadcastSignedTx broadcasts the signed tx and waits for its inclusion
+func (bc *BabylonController) BroadcastSignedTx(tx *types.SignedTx) (*types.TxResponse, error) {
+	res, err := bc.txSender.broadcastBuiltTx(context.Background(), tx.Bytes, tx.Accepted)
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.TxResponse{TxHash: res.TxHash, Events: res.Events}, nil
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
+}
+
+// QueryTx returns the response of the included tx with the given hash, or
+// ErrTxNotFound if the tx is not included. The tx included with a failure
+// returns the error it failed with
+func (bc *BabylonController) QueryTx(hash []byte) (*types.TxResponse, error) {
+	res, err := bc.txSender.queryTx(context.Background(), hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
 }
 
 // SubmitBatchFinalitySigs submits a batch of finality signatures to Babylon
@@ -222,27 +377,9 @@ func (bc *BabylonController) SubmitBatchFinalitySigs(
 	proofList [][]byte,
 	sigs []*btcec.ModNScalar,
 ) (*types.TxResponse, error) {
-	if len(blocks) != len(sigs) {
-		return nil, fmt.Errorf("the number of blocks %v should match the number of finality signatures %v", len(blocks), len(sigs))
-	}
-
-	msgs := make([]sdk.Msg, 0, len(blocks))
-	for i, b := range blocks {
-		cmtProof := cmtcrypto.Proof{}
-		if err := cmtProof.Unmarshal(proofList[i]); err != nil {
-			return nil, err
-		}
-
-		msg := &finalitytypes.MsgAddFinalitySig{
-			Signer:       bc.mustGetTxSigner(),
-			FpBtcPk:      bbntypes.NewBIP340PubKeyFromBTCPK(fpPk),
-			BlockHeight:  b.Height,
-			PubRand:      bbntypes.NewSchnorrPubRandFromFieldVal(pubRandList[i]),
-			Proof:        &cmtProof,
-			BlockAppHash: b.Hash,
-			FinalitySig:  bbntypes.NewSchnorrEOTSSigFromModNScalar(sigs[i]),
-		}
-		msgs = append(msgs, msg)
+	msgs, err := bc.newMsgsAddFinalitySig(fpPk, blocks, pubRandList, proofList, sigs)
+	if err != nil {
+		return nil, err
 	}
 
 	unrecoverableErrs := []*sdkErr.Error{
@@ -256,7 +393,53 @@ func (bc *BabylonController) SubmitBatchFinalitySigs(
 		return nil, err
 	}
 
-	return &types.TxResponse{TxHash: res.TxHash, Events: res.Events}, nil
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
+}
+
+// SignBatchFinalitySigsTx signs the tx of a batch of finality signatures
+// without broadcasting it. The tx takes the next sequence of the account, so
+// it has to be broadcast before the sequence is taken by another tx
+func (bc *BabylonController) SignBatchFinalitySigsTx(
+	fpPk *btcec.PublicKey,
+	blocks []*types.BlockInfo,
+	pubRandList []*btcec.FieldVal,
+	proofList [][]byte,
+	sigs []*btcec.ModNScalar,
+) (*types.SignedTx, error) {
+	msgs, err := bc.newMsgsAddFinalitySig(fpPk, blocks, pubRandList, proofList, sigs)
+	if err != nil {
+		return nil, err
+	}
+
+	txBytes, err := bc.txSender.signMsgs(context.Background(), msgs, bc.gasAdjustment(msgs))
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SignedTx{Bytes: txBytes, Hash: cmttypes.Tx(txBytes).Hash()}, nil
+}
+
+func (bc *BabylonController) newMsgsAddFinalitySig(
+	fpPk *btcec.PublicKey,
+	blocks []*types.BlockInfo,
+	pubRandList []*btcec.FieldVal,
+	proofList [][]byte,
+	sigs []*btcec.ModNScalar,
+) ([]sdk.Msg, error) {
+	if len(blocks) != len(sigs) || len(blocks) != len(pubRandList) || len(blocks) != len(proofList) {
+		return nil, fmt.Errorf("the number of blocks %v should match the number of finality signatures %v", len(blocks), len(sigs))
+	}
+
+	msgs := make([]sdk.Msg, 0, len(blocks))
+	for i, b := range blocks {
+		msg, err := bc.newMsgAddFinalitySig(fpPk, b, pubRandList[i], proofList[i], sigs[i])
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
 }
 
 func (bc *BabylonController) QueryFinalityProviderSlashed(fpPk *btcec.PublicKey) (bool, error) {
@@ -271,6 +454,68 @@ func (bc *BabylonController) QueryFinalityProviderSlashed(fpPk *btcec.PublicKey)
 	return slashed, nil
 }
 
+// QueryRegisteredFinalityProvider queries the finality provider from Babylon,
+// where a finality provider not found is not registered
+func (bc *BabylonController) QueryRegisteredFinalityProvider(fpPk *btcec.PublicKey) (*types.RegisteredFinalityProvider, error) {
+	fpPubKey := bbntypes.NewBIP340PubKeyFromBTCPK(fpPk)
+	res, err := bc.bbnClient.QueryClient.FinalityProvider(fpPubKey.MarshalHex())
+	if err != nil {
+		// cannot use errors.Is as the error is decoded from the response of
+		// the chain
+		if strings.Contains(err.Error(), btcstakingtypes.ErrFpNotFound.Error()) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query the finality provider %s: %w", fpPubKey.MarshalHex(), err)
+	}
+
+	fp := res.FinalityProvider
+	var chainPk []byte
+	if fp.BabylonPk != nil {
+		chainPk = fp.BabylonPk.Key
+	}
+
+	return &types.RegisteredFinalityProvider{
+		BtcPk:            fpPk,
+		ChainPk:          chainPk,
+		Description:      fp.Description,
+		Commission:       fp.Commission,
+		SlashedBtcHeight: fp.SlashedBtcHeight,
+	}, nil
+}
+
+// QueryRegisteredFinalityProviders queries all the finality providers
+// registered on Babylon
+func (bc *BabylonController) QueryRegisteredFinalityProviders() ([]*types.RegisteredFinalityProvider, error) {
+	fps, err := bc.QueryFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	registered := make([]*types.RegisteredFinalityProvider, 0, len(fps))
+	for _, fp := range fps {
+		if fp.BtcPk == nil {
+			continue
+		}
+		btcPk, err := fp.BtcPk.ToBTCPK()
+		if err != nil {
+			return nil, fmt.Errorf("invalid BTC public key of the finality provider %s: %w", fp.BtcPk.MarshalHex(), err)
+		}
+		var chainPk []byte
+		if fp.BabylonPk != nil {
+			chainPk = fp.BabylonPk.Key
+		}
+		registered = append(registered, &types.RegisteredFinalityProvider{
+			BtcPk:            btcPk,
+			ChainPk:          chainPk,
+			Description:      fp.Description,
+			Commission:       fp.Commission,
+			SlashedBtcHeight: fp.SlashedBtcHeight,
+		})
+	}
+
+	return registered, nil
+}
+
 // QueryFinalityProviderVotingPower queries the voting power of the finality provider at a given height
 func (bc *BabylonController) QueryFinalityProviderVotingPower(fpPk *btcec.PublicKey, blockHeight uint64) (uint64, error) {
 	res, err := bc.bbnClient.QueryClient.FinalityProviderPowerAtHeight(
@@ -288,6 +533,130 @@ func (bc *BabylonController) QueryLatestFinalizedBlocks(count uint64) ([]*types.
 	return bc.queryLatestBlocks(nil, count, finalitytypes.QueriedBlockStatus_FINALIZED, true)
 }
 
+func (bc *BabylonController) QueryVotesAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	res, err := bc.bbnClient.QueryClient.VotesAtHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the votes at height %d: %w", height, err)
+	}
+
+	return res.BtcPks, nil
+}
+
+// QueryActiveFinalityProvidersAtHeight returns the BTC public keys of the
+// finality providers with voting power at the given height
+func (bc *BabylonController) QueryActiveFinalityProvidersAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	var fpPks []bbntypes.BIP340PubKey
+	pagination := &sdkquery.PageRequest{
+		Limit: 100,
+	}
+
+	for {
+		res, err := bc.bbnClient.QueryClient.ActiveFinalityProvidersAtHeight(height, pagination)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the active finality providers at height %d: %w", height, err)
+		}
+		for _, fp := range res.FinalityProviders {
+			fpPks = append(fpPks, *fp.BtcPkHex)
+		}
+		if res.Pagination == nil || res.Pagination.NextKey == nil {
+			break
+		}
+
+		pagination.Key = res.Pagination.NextKey
+	}
+
+	return fpPks, nil
+}
+
+// QueryVotingPowersAtHeight returns the voting powers of the finality
+// providers with voting power at the given height
+func (bc *BabylonController) QueryVotingPowersAtHeight(height uint64) ([]*types.FinalityProviderPower, error) {
+	var powers []*types.FinalityProviderPower
+	pagination := &sdkquery.PageRequest{
+		Limit: 100,
+	}
+
+	for {
+		res, err := bc.bbnClient.QueryClient.ActiveFinalityProvidersAtHeight(height, pagination)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the active finality providers at height %d: %w", height, err)
+		}
+		for _, fp := range res.FinalityProviders {
+			btcPk, err := fp.BtcPkHex.ToBTCPK()
+			if err != nil {
+				return nil, fmt.Errorf("invalid BTC public key of finality provider: %w", err)
+			}
+			powers = append(powers, &types.FinalityProviderPower{
+				FpBtcPk:     btcPk,
+				VotingPower: fp.VotingPower,
+			})
+		}
+		if res.Pagination == nil || res.Pagination.NextKey == nil {
+			break
+		}
+
+		pagination.Key = res.Pagination.NextKey
+	}
+
+	return powers, nil
+}
+
+// QueryFinalitySigsAtHeight returns the finality signatures on the block at
+// the given height. Babylon only keeps the voters in its state, so the
+// signatures are searched among the txs included within
+// finalitySigSearchWindow blocks after the height
+func (bc *BabylonController) QueryFinalitySigsAtHeight(height uint64) ([]*types.FinalitySig, error) {
+	query := fmt.Sprintf("message.action='%s' AND tx.height>%d AND tx.height<=%d",
+		sdk.MsgTypeURL(&finalitytypes.MsgAddFinalitySig{}), height, height+finalitySigSearchWindow)
+	txDecoder := bbnapp.GetEncodingConfig().TxConfig.TxDecoder()
+
+	var sigs []*types.FinalitySig
+	perPage := finalitySigSearchPageSize
+	for page := 1; ; page++ {
+		ctx, cancel := getContextWithCancel(bc.cfg.Timeout)
+		res, err := bc.bbnClient.RPCClient.TxSearch(ctx, query, false, &page, &perPage, "asc")
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to search the finality signatures at height %d: %w", height, err)
+		}
+
+		for _, txRes := range res.Txs {
+			// the signatures of the failed txs are not accepted
+			if txRes.TxResult.Code != 0 {
+				continue
+			}
+			tx, err := txDecoder(txRes.Tx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode tx %s: %w", txRes.Hash, err)
+			}
+			for _, msg := range tx.GetMsgs() {
+				sigMsg, ok := msg.(*finalitytypes.MsgAddFinalitySig)
+				if !ok || sigMsg.BlockHeight != height {
+					continue
+				}
+				btcPk, err := sigMsg.FpBtcPk.ToBTCPK()
+				if err != nil {
+					return nil, fmt.Errorf("invalid BTC public key of finality provider: %w", err)
+				}
+				sigs = append(sigs, &types.FinalitySig{
+					FpBtcPk:   btcPk,
+					Height:    sigMsg.BlockHeight,
+					BlockHash: sigMsg.BlockAppHash,
+					PubRand:   *sigMsg.PubRand,
+					Sig:       *sigMsg.FinalitySig,
+					TxHash:    txRes.Hash.String(),
+				})
+			}
+		}
+
+		if page*perPage >= res.TotalCount {
+			break
+		}
+	}
+
+	return sigs, nil
+}
+
 // QueryLastCommittedPublicRand returns the last public randomness commitments
 func (bc *BabylonController) QueryLastCommittedPublicRand(fpPk *btcec.PublicKey, count uint64) (map[uint64]*finalitytypes.PubRandCommitResponse, error) {
 	fpBtcPk := bbntypes.NewBIP340PubKeyFromBTCPK(fpPk)
@@ -368,6 +737,59 @@ func (bc *BabylonController) QueryActivatedHeight() (uint64, error) {
 	return res.Height, nil
 }
 
+func (bc *BabylonController) QueryChainParams() (*types.ChainParams, error) {
+	stakingParamRes, err := bc.bbnClient.QueryClient.BTCStakingParams()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query staking params: %w", err)
+	}
+
+	var finalityParams finalitytypes.Params
+	err = bc.bbnClient.QueryClient.QueryFinality(func(ctx context.Context, queryClient finalitytypes.QueryClient) error {
+		res, err := queryClient.Params(ctx, &finalitytypes.QueryParamsRequest{})
+		if err != nil {
+			return err
+		}
+		finalityParams = res.Params
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query finality params: %w", err)
+	}
+
+	return &types.ChainParams{
+		MinCommissionRate: stakingParamRes.Params.MinCommissionRate,
+		MinPubRand:        finalityParams.MinPubRand,
+	}, nil
+}
+
+// QueryBalance queries the balance of the given address via an ABCI query to
+// the bank module, which the Babylon query client does not cover
+func (bc *BabylonController) QueryBalance(address string) (sdk.Coins, error) {
+	req := &banktypes.QueryAllBalancesRequest{Address: address}
+	reqBz, err := req.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := getContextWithCancel(bc.cfg.Timeout)
+	defer cancel()
+
+	res, err := bc.bbnClient.RPCClient.ABCIQuery(ctx, bankAllBalancesPath, reqBz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the balance of %s: %w", address, err)
+	}
+	if !res.Response.IsOK() {
+		return nil, fmt.Errorf("failed to query the balance of %s: %s", address, res.Response.Log)
+	}
+
+	var balanceRes banktypes.QueryAllBalancesResponse
+	if err := balanceRes.Unmarshal(res.Response.Value); err != nil {
+		return nil, fmt.Errorf("invalid balance response: %w", err)
+	}
+
+	return balanceRes.Balances, nil
+}
+
 func (bc *BabylonController) QueryBestBlock() (*types.BlockInfo, error) {
 	blocks, err := bc.queryLatestBlocks(nil, 1, finalitytypes.QueriedBlockStatus_ANY, true)
 	if err != nil || len(blocks) != 1 {
@@ -378,6 +800,21 @@ func (bc *BabylonController) QueryBestBlock() (*types.BlockInfo, error) {
 	return blocks[0], nil
 }
 
+func (bc *BabylonController) QueryBestBlockTime() (*types.BlockTime, error) {
+	ctx, cancel := getContextWithCancel(bc.cfg.Timeout)
+	status, err := bc.bbnClient.RPCClient.Status(ctx)
+	defer cancel()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the status of the consumer chain: %w", err)
+	}
+
+	return &types.BlockTime{
+		Height: uint64(status.SyncInfo.LatestBlockHeight),
+		Time:   status.SyncInfo.LatestBlockTime,
+	}, nil
+}
+
 func (bc *BabylonController) queryCometBestBlock() (*types.BlockInfo, error) {
 	ctx, cancel := getContextWithCancel(bc.cfg.Timeout)
 	// this will return 20 items at max in the descending order (highest first)
@@ -397,6 +834,10 @@ func (bc *BabylonController) queryCometBestBlock() (*types.BlockInfo, error) {
 }
 
 func (bc *BabylonController) Close() error {
+	if err := bc.txSender.close(); err != nil {
+		return err
+	}
+
 	if !bc.bbnClient.IsRunning() {
 		return nil
 	}
@@ -451,7 +892,7 @@ func (bc *BabylonController) CreateBTCDelegation(
 		return nil, err
 	}
 
-	return &types.TxResponse{TxHash: res.TxHash}, nil
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height)}, nil
 }
 
 func (bc *BabylonController) InsertBtcBlockHeaders(headers []bbntypes.BTCHeaderBytes) (*provider.RelayerTxResponse, error) {
@@ -499,15 +940,6 @@ func (bc *BabylonController) QueryBtcLightClientTip() (*btclctypes.BTCHeaderInfo
 	return res.Header, nil
 }
 
-func (bc *BabylonController) QueryVotesAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
-	res, err := bc.bbnClient.QueryClient.VotesAtHeight(height)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query BTC delegations: %w", err)
-	}
-
-	return res.BtcPks, nil
-}
-
 func (bc *BabylonController) QueryPendingDelegations(limit uint64) ([]*btcstakingtypes.BTCDelegationResponse, error) {
 	return bc.queryDelegationsWithStatus(btcstakingtypes.BTCDelegationStatus_PENDING, limit)
 }
@@ -593,5 +1025,5 @@ func (bc *BabylonController) SubmitCovenantSigs(
 		return nil, err
 	}
 
-	return &types.TxResponse{TxHash: res.TxHash, Events: res.Events}, nil
+	return &types.TxResponse{TxHash: res.TxHash, Height: uint64(res.Height), Events: res.Events}, nil
 }
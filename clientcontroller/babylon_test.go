@@ -0,0 +1,54 @@
+package clientcontroller
+
+import (
+	"testing"
+
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	finalitytypes "github.com/babylonchain/babylon/x/finality/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+)
+
+func TestGasAdjustment(t *testing.T) {
+	bc := &BabylonController{cfg: &fpcfg.BBNConfig{
+		GasAdjustment:             1.5,
+		RegistrationGasAdjustment: 2,
+		FinalitySigGasAdjustment:  1.2,
+	}}
+
+	testCases := []struct {
+		name               string
+		msgs               []sdk.Msg
+		expectedAdjustment float64
+	}{
+		{
+			name:               "a registration has its own gas adjustment",
+			msgs:               []sdk.Msg{&btcstakingtypes.MsgCreateFinalityProvider{}},
+			expectedAdjustment: 2,
+		},
+		{
+			name:               "a batch of finality signatures has the gas adjustment of its first message",
+			msgs:               []sdk.Msg{&finalitytypes.MsgAddFinalitySig{}, &finalitytypes.MsgAddFinalitySig{}},
+			expectedAdjustment: 1.2,
+		},
+		{
+			name:               "an unset gas adjustment falls back to the default one",
+			msgs:               []sdk.Msg{&finalitytypes.MsgCommitPubRandList{}},
+			expectedAdjustment: 1.5,
+		},
+		{
+			name:               "another kind of message has the default gas adjustment",
+			msgs:               []sdk.Msg{&banktypes.MsgSend{}},
+			expectedAdjustment: 1.5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedAdjustment, bc.gasAdjustment(tc.msgs))
+		})
+	}
+}
@@ -0,0 +1,465 @@
+package clientcontroller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sdkErr "cosmossdk.io/errors"
+	abci "github.com/cometbft/cometbft/abci/types"
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+)
+
+const (
+	// txEventSubscriber is the name the subscription to the events of the
+	// txs is made under
+	txEventSubscriber = "fpd-tx-sender"
+	// defaultBroadcastTimeout bounds the wait for the inclusion of a tx if
+	// the block timeout is not set
+	defaultBroadcastTimeout = time.Minute
+	// txConfirmPollInterval is the interval at which a broadcast tx is looked
+	// up by its hash, in case its event is missed
+	txConfirmPollInterval = time.Second
+	// txEventCapacity is the number of the events of the txs buffered for
+	// the dispatch
+	txEventCapacity = 100
+	// resubscribeTimeout bounds the subscription to the events of the txs
+	// made again once the previous one is closed
+	resubscribeTimeout = 10 * time.Second
+	// maxUnconfirmedTxs is the maximum number of the txs in the mempool that
+	// CometBFT lists at once
+	maxUnconfirmedTxs = 100
+	// droppedTxChecks is the number of the consecutive polls a tx has to be
+	// found neither included nor in the mempool to be reported as dropped, as
+	// the tx leaves the mempool once its block is committed but before it is
+	// indexed
+	droppedTxChecks = 2
+)
+
+var (
+	// ErrTxDropped is returned if a broadcast tx leaves the mempool without
+	// being included, e.g., if CheckTx rejected the tx broadcast in the async
+	// mode
+	ErrTxDropped = errors.New("the tx left the mempool without being included")
+	// ErrTxNotFound is returned if the tx looked up is not included
+	ErrTxNotFound = errors.New("the tx is not found")
+)
+
+// broadcastClient is the part of the CometBFT client the txs are broadcast
+// and confirmed through
+type broadcastClient interface {
+	Start() error
+	Stop() error
+	IsRunning() bool
+	Subscribe(ctx context.Context, subscriber, query string, outCapacity ...int) (<-chan coretypes.ResultEvent, error)
+	UnsubscribeAll(ctx context.Context, subscriber string) error
+	BroadcastTxSync(ctx context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error)
+	BroadcastTxAsync(ctx context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error)
+	BroadcastTxCommit(ctx context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTxCommit, error)
+	Tx(ctx context.Context, hash []byte, prove bool) (*coretypes.ResultTx, error)
+	UnconfirmedTxs(ctx context.Context, limit *int) (*coretypes.ResultUnconfirmedTxs, error)
+}
+
+// newBroadcastClient creates the CometBFT client broadcasting the txs, which
+// is started for the async mode to receive the events of the txs over the
+// websocket
+func newBroadcastClient(rpcAddr, broadcastMode string) (*rpchttp.HTTP, error) {
+	c, err := rpchttp.New(rpcAddr, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client broadcasting the txs: %w", err)
+	}
+	if broadcastMode == fpcfg.BroadcastModeAsync {
+		if err := c.Start(); err != nil {
+			return nil, fmt.Errorf("failed to subscribe to the events of the txs: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// txEventDispatcher receives the events of all the txs of the signer over a
+// single subscription, and delivers each event to the broadcast awaiting the
+// hash of its tx. A subscription per tx would soon exceed the subscriptions a
+// client may hold on the node once many txs are awaited at the same time
+type txEventDispatcher struct {
+	client broadcastClient
+	query  string
+	logger *zap.Logger
+
+	mu         sync.Mutex
+	subscribed bool
+	waiters    map[string]chan cmttypes.EventDataTx
+	quit       chan struct{}
+	// closedSubscriptions counts the subscriptions closed under the
+	// dispatcher, after each of which the txs are looked up by their hashes
+	// until the subscription is made again
+	closedSubscriptions uint64
+}
+
+func newTxEventDispatcher(client broadcastClient, signer string, logger *zap.Logger) *txEventDispatcher {
+	return &txEventDispatcher{
+		client:  client,
+		query:   fmt.Sprintf("tm.event='Tx' AND message.sender='%s'", signer),
+		logger:  logger,
+		waiters: make(map[string]chan cmttypes.EventDataTx),
+		quit:    make(chan struct{}),
+	}
+}
+
+// await registers the broadcast awaiting the event of the tx with the given
+// hash, which must be done before the tx is broadcast, and subscribes to the
+// events of the txs unless subscribed. If the subscription fails, the tx is
+// only looked up by its hash, and the subscription is tried again on the next
+// broadcast. The returned function unregisters the broadcast
+func (d *txEventDispatcher) await(ctx context.Context, hash []byte) (<-chan cmttypes.EventDataTx, func()) {
+	key := string(hash)
+	events := make(chan cmttypes.EventDataTx, 1)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.waiters[key] = events
+	if !d.subscribed {
+		if err := d.subscribe(ctx); err != nil {
+			d.logger.Debug("failed to subscribe to the events of the txs, looking them up instead", zap.Error(err))
+		}
+	}
+
+	return events, func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		delete(d.waiters, key)
+	}
+}
+
+// subscribe subscribes to the events of the txs of the signer, which must be
+// called with mu held
+func (d *txEventDispatcher) subscribe(ctx context.Context) error {
+	events, err := d.client.Subscribe(ctx, txEventSubscriber, d.query, txEventCapacity)
+	if err != nil {
+		return err
+	}
+	d.subscribed = true
+
+	go d.dispatch(events)
+
+	return nil
+}
+
+// dispatch delivers the events of the txs to the broadcasts awaiting them
+// until the dispatcher or the subscription is closed. The events of the txs
+// nobody awaits, e.g., the ones given up on, are dropped
+func (d *txEventDispatcher) dispatch(events <-chan coretypes.ResultEvent) {
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				d.resubscribe()
+				return
+			}
+			txEvent, ok := event.Data.(cmttypes.EventDataTx)
+			if !ok {
+				continue
+			}
+			hash := cmttypes.Tx(txEvent.Tx).Hash()
+
+			d.mu.Lock()
+			if waiter, ok := d.waiters[string(hash)]; ok {
+				select {
+				case waiter <- txEvent:
+				default:
+				}
+			}
+			d.mu.Unlock()
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// resubscribe subscribes to the events of the txs again once the subscription
+// is closed, e.g., if the node drops the websocket without the client making
+// the subscription again on the reconnection. If the subscription fails, the
+// txs are only looked up by their hashes, and the subscription is tried again
+// on the next broadcast
+func (d *txEventDispatcher) resubscribe() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.subscribed = false
+	select {
+	case <-d.quit:
+		return
+	default:
+	}
+
+	d.closedSubscriptions++
+	d.logger.Warn("the subscription to the events of the txs is closed, looking the txs up until it is made again",
+		zap.Uint64("closed_subscriptions", d.closedSubscriptions))
+
+	ctx, cancel := context.WithTimeout(context.Background(), resubscribeTimeout)
+	defer cancel()
+	if err := d.subscribe(ctx); err != nil {
+		d.logger.Warn("failed to subscribe to the events of the txs again", zap.Error(err))
+	}
+}
+
+func (d *txEventDispatcher) close() {
+	close(d.quit)
+}
+
+// broadcastBuiltTx broadcasts the tx built with the next sequence of the
+// account in the mode of the sender, and waits for its inclusion. The sequence
+// of the account is tracked by the sender, since the txs broadcast in the
+// async mode may not have entered the mempool yet when the next one is built.
+// accepted, if not nil, is called once the node accepts the broadcast, before
+// the inclusion of the tx is awaited
+func (s *txSender) broadcastBuiltTx(ctx context.Context, txBytes []byte, accepted func()) (*provider.RelayerTxResponse, error) {
+	if accepted == nil {
+		accepted = func() {}
+	}
+
+	var (
+		res *provider.RelayerTxResponse
+		err error
+	)
+	switch s.broadcastMode {
+	case fpcfg.BroadcastModeSync:
+		res, err = s.broadcastSync(ctx, txBytes, accepted)
+	case fpcfg.BroadcastModeAsync:
+		res, err = s.broadcastAsync(ctx, txBytes, accepted)
+	case fpcfg.BroadcastModeBlock:
+		res, err = s.broadcastBlock(ctx, txBytes, accepted)
+	default:
+		err = fmt.Errorf("unsupported broadcast mode %s", s.broadcastMode)
+	}
+	if err != nil {
+		// a tx failing the check of the mempool, which is not reported in
+		// the async mode, leaves a gap in the sequences, so the sequence is
+		// taken from the chain again
+		s.resetSequence()
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// buildTx returns the signed tx of the messages with the next sequence of the
+// account, which must be called with mu held
+func (s *txSender) buildTx(ctx context.Context, msgs []sdk.Msg, gasAdjustment float64, gasPrices string) ([]byte, error) {
+	done := s.provider.SetSDKContext()
+	defer done()
+
+	s.provider.PCfg.GasAdjustment = gasAdjustment
+	s.provider.PCfg.GasPrices = gasPrices
+	txf, err := s.provider.PrepareFactory(s.provider.TxFactory(), s.provider.PCfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	if s.memo != "" {
+		txf = txf.WithMemo(s.memo)
+	}
+	if txf.Sequence() < s.nextSequence {
+		txf = txf.WithSequence(s.nextSequence)
+	}
+	s.nextSequence = txf.Sequence()
+
+	_, gas, err := s.provider.CalculateGas(ctx, txf, s.provider.PCfg.Key, msgs...)
+	if err != nil {
+		return nil, err
+	}
+	txf = txf.WithGas(gas)
+
+	txb, err := txf.BuildUnsignedTx(msgs...)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Sign(ctx, txf, s.provider.PCfg.Key, txb, false); err != nil {
+		return nil, err
+	}
+
+	return s.provider.Cdc.TxConfig.TxEncoder()(txb.GetTx())
+}
+
+// resetSequence makes the next tx take the sequence of the account on chain
+func (s *txSender) resetSequence() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSequence = 0
+}
+
+// broadcastSync broadcasts the tx once it passes the check of the mempool,
+// and awaits its inclusion
+func (s *txSender) broadcastSync(ctx context.Context, txBytes []byte, accepted func()) (*provider.RelayerTxResponse, error) {
+	res, err := s.rpcClient.BroadcastTxSync(ctx, txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast the tx: %w", err)
+	}
+	if res.Code != 0 {
+		return nil, sdkErr.ABCIError(res.Codespace, res.Code, res.Log)
+	}
+	accepted()
+
+	return s.awaitInclusion(ctx, txBytes, nil)
+}
+
+// broadcastAsync broadcasts the tx without waiting for the check of the
+// mempool, and awaits its inclusion. The tx is awaited before the broadcast
+// so that its event is not missed
+func (s *txSender) broadcastAsync(ctx context.Context, txBytes []byte, accepted func()) (*provider.RelayerTxResponse, error) {
+	events, done := s.events.await(ctx, cmttypes.Tx(txBytes).Hash())
+	defer done()
+
+	if _, err := s.rpcClient.BroadcastTxAsync(ctx, txBytes); err != nil {
+		return nil, fmt.Errorf("failed to broadcast the tx: %w", err)
+	}
+	accepted()
+
+	return s.awaitInclusion(ctx, txBytes, events)
+}
+
+// queryTx returns the response of the included tx with the given hash, or
+// ErrTxNotFound if the tx is not included
+func (s *txSender) queryTx(ctx context.Context, hash []byte) (*provider.RelayerTxResponse, error) {
+	res, err := s.rpcClient.Tx(ctx, hash, false)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, fmt.Errorf("%w: %X", ErrTxNotFound, hash)
+		}
+		return nil, fmt.Errorf("failed to query the tx %X: %w", hash, err)
+	}
+
+	return newRelayerTxResponse(hash, res.Height, &res.TxResult)
+}
+
+// awaitInclusion waits for the broadcast tx to be included through its event,
+// and looks it up by its hash at every poll in case the event is missed, e.g.,
+// on a reconnection of the websocket. As CheckTx does not report the txs it
+// rejects in the async mode, the tx is reported as dropped once it is found
+// neither included nor in the mempool, rather than on the block timeout
+func (s *txSender) awaitInclusion(ctx context.Context, txBytes []byte, events <-chan cmttypes.EventDataTx) (*provider.RelayerTxResponse, error) {
+	hash := cmttypes.Tx(txBytes).Hash()
+
+	ctx, cancel := context.WithTimeout(ctx, s.blockTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(s.confirmPollInterval)
+	defer ticker.Stop()
+
+	var missing int
+	for {
+		select {
+		case txEvent := <-events:
+			return newRelayerTxResponse(hash, txEvent.Height, &txEvent.Result)
+		case <-ticker.C:
+			if res, err := s.rpcClient.Tx(ctx, hash, false); err == nil {
+				return newRelayerTxResponse(hash, res.Height, &res.TxResult)
+			}
+			inMempool, known, err := s.inMempool(ctx, hash)
+			if err != nil || inMempool || !known {
+				missing = 0
+				continue
+			}
+			missing++
+			if missing >= droppedTxChecks {
+				return nil, fmt.Errorf("%w: %X", ErrTxDropped, hash)
+			}
+		case <-ctx.Done():
+			return nil, fmt.Errorf("the tx %X is not included within %v", hash, s.blockTimeout)
+		}
+	}
+}
+
+// inMempool returns whether the tx with the given hash is in the mempool of
+// the node, and whether this is known, which is not the case if the mempool
+// holds more txs than CometBFT lists
+func (s *txSender) inMempool(ctx context.Context, hash []byte) (bool, bool, error) {
+	limit := maxUnconfirmedTxs
+	res, err := s.rpcClient.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return false, false, err
+	}
+	for _, tx := range res.Txs {
+		if bytes.Equal(tx.Hash(), hash) {
+			return true, true, nil
+		}
+	}
+
+	return false, res.Total <= res.Count, nil
+}
+
+// broadcastBlock broadcasts the tx and waits on the node until it is included.
+// The node does not report the acceptance of the tx apart from its inclusion,
+// so accepted is only called once the tx is included
+func (s *txSender) broadcastBlock(ctx context.Context, txBytes []byte, accepted func()) (*provider.RelayerTxResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.blockTimeout)
+	defer cancel()
+
+	res, err := s.rpcClient.BroadcastTxCommit(ctx, txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to broadcast the tx: %w", err)
+	}
+	if res.CheckTx.Code != 0 {
+		return nil, sdkErr.ABCIError(res.CheckTx.Codespace, res.CheckTx.Code, res.CheckTx.Log)
+	}
+	accepted()
+
+	return newRelayerTxResponse(res.Hash, res.Height, &res.TxResult)
+}
+
+// newRelayerTxResponse returns the response of the included tx, or the error
+// it failed with
+func newRelayerTxResponse(hash []byte, height int64, result *abci.ExecTxResult) (*provider.RelayerTxResponse, error) {
+	if result.Code != 0 {
+		return nil, sdkErr.ABCIError(result.Codespace, result.Code, result.Log)
+	}
+
+	events := make([]provider.RelayerEvent, 0, len(result.Events))
+	for _, event := range result.Events {
+		attributes := make(map[string]string, len(event.Attributes))
+		for _, attribute := range event.Attributes {
+			attributes[attribute.Key] = attribute.Value
+		}
+		events = append(events, provider.RelayerEvent{
+			EventType:  event.Type,
+			Attributes: attributes,
+		})
+	}
+
+	return &provider.RelayerTxResponse{
+		Height:    height,
+		TxHash:    fmt.Sprintf("%X", hash),
+		Codespace: result.Codespace,
+		Code:      result.Code,
+		Data:      fmt.Sprintf("%X", result.Data),
+		Events:    events,
+	}, nil
+}
+
+// close stops the subscription to the events of the txs
+func (s *txSender) close() error {
+	if s.events != nil {
+		s.events.close()
+	}
+	if s.rpcClient == nil || !s.rpcClient.IsRunning() {
+		return nil
+	}
+	// the subscription is dropped by the node once the websocket is closed
+	_ = s.rpcClient.UnsubscribeAll(context.Background(), txEventSubscriber)
+
+	return s.rpcClient.Stop()
+}
@@ -0,0 +1,197 @@
+package clientcontroller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+)
+
+// fakeBroadcastClient includes the txs broadcast to it unless they are
+// rejected, and emits their events over the last subscription unless the
+// events are missed
+type fakeBroadcastClient struct {
+	mu            sync.Mutex
+	subscriptions int
+	events        chan coretypes.ResultEvent
+	included      map[string]int64
+	height        int64
+	reject        bool
+	missEvents    bool
+}
+
+func newFakeBroadcastClient() *fakeBroadcastClient {
+	return &fakeBroadcastClient{
+		included: make(map[string]int64),
+	}
+}
+
+func (c *fakeBroadcastClient) Start() error    { return nil }
+func (c *fakeBroadcastClient) Stop() error     { return nil }
+func (c *fakeBroadcastClient) IsRunning() bool { return true }
+
+func (c *fakeBroadcastClient) Subscribe(_ context.Context, _, _ string, _ ...int) (<-chan coretypes.ResultEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.subscriptions++
+	c.events = make(chan coretypes.ResultEvent, txEventCapacity)
+	return c.events, nil
+}
+
+// closeSubscription closes the last subscription, as the node does when it
+// drops the websocket
+func (c *fakeBroadcastClient) closeSubscription() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	close(c.events)
+	c.events = nil
+}
+
+func (c *fakeBroadcastClient) UnsubscribeAll(_ context.Context, _ string) error { return nil }
+
+func (c *fakeBroadcastClient) BroadcastTxAsync(_ context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// a tx rejected by CheckTx is not reported in the async mode
+	if c.reject {
+		return &coretypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+	}
+	c.height++
+	c.included[string(tx.Hash())] = c.height
+	if !c.missEvents && c.events != nil {
+		c.events <- coretypes.ResultEvent{Data: cmttypes.EventDataTx{TxResult: abci.TxResult{Height: c.height, Tx: tx}}}
+	}
+
+	return &coretypes.ResultBroadcastTx{Hash: tx.Hash()}, nil
+}
+
+func (c *fakeBroadcastClient) BroadcastTxSync(ctx context.Context, tx cmttypes.Tx) (*coretypes.ResultBroadcastTx, error) {
+	return c.BroadcastTxAsync(ctx, tx)
+}
+
+func (c *fakeBroadcastClient) BroadcastTxCommit(_ context.Context, _ cmttypes.Tx) (*coretypes.ResultBroadcastTxCommit, error) {
+	return nil, errors.New("unsupported")
+}
+
+func (c *fakeBroadcastClient) Tx(_ context.Context, hash []byte, _ bool) (*coretypes.ResultTx, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	height, ok := c.included[string(hash)]
+	if !ok {
+		return nil, errors.New("tx not found")
+	}
+	return &coretypes.ResultTx{Hash: hash, Height: height}, nil
+}
+
+func (c *fakeBroadcastClient) UnconfirmedTxs(_ context.Context, _ *int) (*coretypes.ResultUnconfirmedTxs, error) {
+	return &coretypes.ResultUnconfirmedTxs{}, nil
+}
+
+func newAsyncTxSender(c *fakeBroadcastClient) *txSender {
+	return &txSender{
+		broadcastMode:       fpcfg.BroadcastModeAsync,
+		rpcClient:           c,
+		events:              newTxEventDispatcher(c, "bbn1signer", zap.NewNop()),
+		blockTimeout:        time.Minute,
+		confirmPollInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestBroadcastAsync(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("the txs are confirmed over a single subscription", func(t *testing.T) {
+		c := newFakeBroadcastClient()
+		s := newAsyncTxSender(c)
+		defer s.close()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				res, err := s.broadcastBuiltTx(ctx, []byte{byte(i)}, nil)
+				require.NoError(t, err)
+				require.Positive(t, res.Height)
+			}(i)
+		}
+		wg.Wait()
+		require.Equal(t, 1, c.subscriptions)
+	})
+
+	t.Run("a tx whose event is missed is looked up", func(t *testing.T) {
+		c := newFakeBroadcastClient()
+		c.missEvents = true
+		s := newAsyncTxSender(c)
+		defer s.close()
+
+		res, err := s.broadcastBuiltTx(ctx, []byte("tx"), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), res.Height)
+	})
+
+	t.Run("the acceptance of the broadcast is reported before the inclusion", func(t *testing.T) {
+		c := newFakeBroadcastClient()
+		c.missEvents = true
+		s := newAsyncTxSender(c)
+		defer s.close()
+
+		accepted := 0
+		res, err := s.broadcastBuiltTx(ctx, []byte("tx"), func() {
+			accepted++
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), res.Height)
+		require.Equal(t, 1, accepted)
+	})
+
+	t.Run("the events are subscribed to again once the subscription is closed", func(t *testing.T) {
+		c := newFakeBroadcastClient()
+		s := newAsyncTxSender(c)
+		defer s.close()
+
+		_, err := s.broadcastBuiltTx(ctx, []byte("tx1"), nil)
+		require.NoError(t, err)
+
+		c.closeSubscription()
+		require.Eventually(t, func() bool {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.subscriptions == 2
+		}, time.Second, 10*time.Millisecond)
+
+		s.events.mu.Lock()
+		require.True(t, s.events.subscribed)
+		require.Equal(t, uint64(1), s.events.closedSubscriptions)
+		s.events.mu.Unlock()
+
+		res, err := s.broadcastBuiltTx(ctx, []byte("tx2"), nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), res.Height)
+	})
+
+	t.Run("a dropped tx resets the sequence", func(t *testing.T) {
+		c := newFakeBroadcastClient()
+		c.reject = true
+		s := newAsyncTxSender(c)
+		defer s.close()
+		s.nextSequence = 5
+
+		_, err := s.broadcastBuiltTx(ctx, []byte("tx"), nil)
+		require.ErrorIs(t, err, ErrTxDropped)
+		require.Zero(t, s.nextSequence)
+	})
+}
@@ -0,0 +1,235 @@
+package clientcontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	finalitytypes "github.com/babylonchain/babylon/x/finality/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// ErrChainCallTimeout is returned if a call to the consumer chain does not
+// return within the timeout of the DeadlineController
+var ErrChainCallTimeout = errors.New("the call to the consumer chain timed out")
+
+var _ ClientController = &DeadlineController{}
+
+// DeadlineController bounds every call to the wrapped client controller by
+// a timeout and by the context returned by ctxFn, so that the caller is never
+// blocked by a hung RPC. The abandoned call keeps running in the background
+// until the underlying client returns, and a tx it was sending may still be
+// included on chain
+type DeadlineController struct {
+	ClientController
+	timeout time.Duration
+	ctxFn   func() context.Context
+}
+
+// NewDeadlineController wraps the client controller, where a timeout of 0
+// leaves the calls only bounded by the context
+func NewDeadlineController(cc ClientController, timeout time.Duration, ctxFn func() context.Context) *DeadlineController {
+	return &DeadlineController{ClientController: cc, timeout: timeout, ctxFn: ctxFn}
+}
+
+func withDeadline[T any](dc *DeadlineController, method string, call func() (T, error)) (T, error) {
+	parent := dc.ctxFn()
+	ctx := parent
+	if dc.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, dc.timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		v   T
+		err error
+	}
+	// buffered so that the abandoned call does not leak its goroutine
+	// once it returns
+	resChan := make(chan result, 1)
+	go func() {
+		v, err := call()
+		resChan <- result{v: v, err: err}
+	}()
+
+	select {
+	case res := <-resChan:
+		return res.v, res.err
+	case <-ctx.Done():
+		var zero T
+		if parent.Err() == nil {
+			return zero, fmt.Errorf("%w: %s did not return within %v", ErrChainCallTimeout, method, dc.timeout)
+		}
+		return zero, fmt.Errorf("%s is aborted: %w", method, parent.Err())
+	}
+}
+
+func (dc *DeadlineController) RegisterFinalityProvider(
+	chainPk []byte,
+	fpPk *btcec.PublicKey,
+	pop []byte,
+	commission *math.LegacyDec,
+	description []byte,
+) (*types.TxResponse, error) {
+	return withDeadline(dc, "RegisterFinalityProvider", func() (*types.TxResponse, error) {
+		return dc.ClientController.RegisterFinalityProvider(chainPk, fpPk, pop, commission, description)
+	})
+}
+
+func (dc *DeadlineController) CommitPubRandList(fpPk *btcec.PublicKey, startHeight uint64, numPubRand uint64, commitment []byte, sig *schnorr.Signature) (*types.TxResponse, error) {
+	return withDeadline(dc, "CommitPubRandList", func() (*types.TxResponse, error) {
+		return dc.ClientController.CommitPubRandList(fpPk, startHeight, numPubRand, commitment, sig)
+	})
+}
+
+func (dc *DeadlineController) SubmitFinalitySig(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.TxResponse, error) {
+	return withDeadline(dc, "SubmitFinalitySig", func() (*types.TxResponse, error) {
+		return dc.ClientController.SubmitFinalitySig(fpPk, block, pubRand, proof, sig)
+	})
+}
+
+func (dc *DeadlineController) SignFinalitySigTx(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.SignedTx, error) {
+	return withDeadline(dc, "SignFinalitySigTx", func() (*types.SignedTx, error) {
+		return dc.ClientController.SignFinalitySigTx(fpPk, block, pubRand, proof, sig)
+	})
+}
+
+func (dc *DeadlineController) BroadcastSignedTx(tx *types.SignedTx) (*types.TxResponse, error) {
+	return withDeadline(dc, "BroadcastSignedTx", func() (*types.TxResponse, error) {
+		return dc.ClientController.BroadcastSignedTx(tx)
+	})
+}
+
+func (dc *DeadlineController) QueryTx(hash []byte) (*types.TxResponse, error) {
+	return withDeadline(dc, "QueryTx", func() (*types.TxResponse, error) {
+		return dc.ClientController.QueryTx(hash)
+	})
+}
+
+func (dc *DeadlineController) SubmitBatchFinalitySigs(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.TxResponse, error) {
+	return withDeadline(dc, "SubmitBatchFinalitySigs", func() (*types.TxResponse, error) {
+		return dc.ClientController.SubmitBatchFinalitySigs(fpPk, blocks, pubRandList, proofList, sigs)
+	})
+}
+
+func (dc *DeadlineController) SignBatchFinalitySigsTx(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.SignedTx, error) {
+	return withDeadline(dc, "SignBatchFinalitySigsTx", func() (*types.SignedTx, error) {
+		return dc.ClientController.SignBatchFinalitySigsTx(fpPk, blocks, pubRandList, proofList, sigs)
+	})
+}
+
+func (dc *DeadlineController) SetRewardAddress(chainPk []byte, rewardAddr string) (*types.TxResponse, error) {
+	return withDeadline(dc, "SetRewardAddress", func() (*types.TxResponse, error) {
+		return dc.ClientController.SetRewardAddress(chainPk, rewardAddr)
+	})
+}
+
+func (dc *DeadlineController) QueryFinalityProviderVotingPower(fpPk *btcec.PublicKey, blockHeight uint64) (uint64, error) {
+	return withDeadline(dc, "QueryFinalityProviderVotingPower", func() (uint64, error) {
+		return dc.ClientController.QueryFinalityProviderVotingPower(fpPk, blockHeight)
+	})
+}
+
+func (dc *DeadlineController) QueryFinalityProviderSlashed(fpPk *btcec.PublicKey) (bool, error) {
+	return withDeadline(dc, "QueryFinalityProviderSlashed", func() (bool, error) {
+		return dc.ClientController.QueryFinalityProviderSlashed(fpPk)
+	})
+}
+
+func (dc *DeadlineController) QueryRegisteredFinalityProvider(fpPk *btcec.PublicKey) (*types.RegisteredFinalityProvider, error) {
+	return withDeadline(dc, "QueryRegisteredFinalityProvider", func() (*types.RegisteredFinalityProvider, error) {
+		return dc.ClientController.QueryRegisteredFinalityProvider(fpPk)
+	})
+}
+
+func (dc *DeadlineController) QueryRegisteredFinalityProviders() ([]*types.RegisteredFinalityProvider, error) {
+	return withDeadline(dc, "QueryRegisteredFinalityProviders", func() ([]*types.RegisteredFinalityProvider, error) {
+		return dc.ClientController.QueryRegisteredFinalityProviders()
+	})
+}
+
+func (dc *DeadlineController) QueryLatestFinalizedBlocks(count uint64) ([]*types.BlockInfo, error) {
+	return withDeadline(dc, "QueryLatestFinalizedBlocks", func() ([]*types.BlockInfo, error) {
+		return dc.ClientController.QueryLatestFinalizedBlocks(count)
+	})
+}
+
+func (dc *DeadlineController) QueryVotesAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	return withDeadline(dc, "QueryVotesAtHeight", func() ([]bbntypes.BIP340PubKey, error) {
+		return dc.ClientController.QueryVotesAtHeight(height)
+	})
+}
+
+func (dc *DeadlineController) QueryActiveFinalityProvidersAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	return withDeadline(dc, "QueryActiveFinalityProvidersAtHeight", func() ([]bbntypes.BIP340PubKey, error) {
+		return dc.ClientController.QueryActiveFinalityProvidersAtHeight(height)
+	})
+}
+
+func (dc *DeadlineController) QueryVotingPowersAtHeight(height uint64) ([]*types.FinalityProviderPower, error) {
+	return withDeadline(dc, "QueryVotingPowersAtHeight", func() ([]*types.FinalityProviderPower, error) {
+		return dc.ClientController.QueryVotingPowersAtHeight(height)
+	})
+}
+
+func (dc *DeadlineController) QueryFinalitySigsAtHeight(height uint64) ([]*types.FinalitySig, error) {
+	return withDeadline(dc, "QueryFinalitySigsAtHeight", func() ([]*types.FinalitySig, error) {
+		return dc.ClientController.QueryFinalitySigsAtHeight(height)
+	})
+}
+
+func (dc *DeadlineController) QueryLastCommittedPublicRand(fpPk *btcec.PublicKey, count uint64) (map[uint64]*finalitytypes.PubRandCommitResponse, error) {
+	return withDeadline(dc, "QueryLastCommittedPublicRand", func() (map[uint64]*finalitytypes.PubRandCommitResponse, error) {
+		return dc.ClientController.QueryLastCommittedPublicRand(fpPk, count)
+	})
+}
+
+func (dc *DeadlineController) QueryBlock(height uint64) (*types.BlockInfo, error) {
+	return withDeadline(dc, "QueryBlock", func() (*types.BlockInfo, error) {
+		return dc.ClientController.QueryBlock(height)
+	})
+}
+
+func (dc *DeadlineController) QueryBlocks(startHeight, endHeight, limit uint64) ([]*types.BlockInfo, error) {
+	return withDeadline(dc, "QueryBlocks", func() ([]*types.BlockInfo, error) {
+		return dc.ClientController.QueryBlocks(startHeight, endHeight, limit)
+	})
+}
+
+func (dc *DeadlineController) QueryBestBlock() (*types.BlockInfo, error) {
+	return withDeadline(dc, "QueryBestBlock", func() (*types.BlockInfo, error) {
+		return dc.ClientController.QueryBestBlock()
+	})
+}
+
+func (dc *DeadlineController) QueryBestBlockTime() (*types.BlockTime, error) {
+	return withDeadline(dc, "QueryBestBlockTime", func() (*types.BlockTime, error) {
+		return dc.ClientController.QueryBestBlockTime()
+	})
+}
+
+func (dc *DeadlineController) QueryActivatedHeight() (uint64, error) {
+	return withDeadline(dc, "QueryActivatedHeight", func() (uint64, error) {
+		return dc.ClientController.QueryActivatedHeight()
+	})
+}
+
+func (dc *DeadlineController) QueryBalance(address string) (sdk.Coins, error) {
+	return withDeadline(dc, "QueryBalance", func() (sdk.Coins, error) {
+		return dc.ClientController.QueryBalance(address)
+	})
+}
+
+func (dc *DeadlineController) QueryChainParams() (*types.ChainParams, error) {
+	return withDeadline(dc, "QueryChainParams", func() (*types.ChainParams, error) {
+		return dc.ClientController.QueryChainParams()
+	})
+}
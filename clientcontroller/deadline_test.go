@@ -0,0 +1,50 @@
+package clientcontroller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// hangingController blocks the queries of the best block until unblocked
+type hangingController struct {
+	ClientController
+	unblock chan struct{}
+}
+
+func (hc *hangingController) QueryBestBlock() (*types.BlockInfo, error) {
+	<-hc.unblock
+	return &types.BlockInfo{Height: 1}, nil
+}
+
+func TestDeadlineController(t *testing.T) {
+	hc := &hangingController{unblock: make(chan struct{})}
+	defer close(hc.unblock)
+
+	// the hung call times out
+	dc := NewDeadlineController(hc, 10*time.Millisecond, context.Background)
+	_, err := dc.QueryBestBlock()
+	require.ErrorIs(t, err, ErrChainCallTimeout)
+
+	// the hung call is aborted once the context is cancelled, even without
+	// a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	dc = NewDeadlineController(hc, 0, func() context.Context { return ctx })
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = dc.QueryBestBlock()
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, errors.Is(err, ErrChainCallTimeout))
+
+	// the call returning in time is passed through
+	responsive := &hangingController{unblock: make(chan struct{})}
+	close(responsive.unblock)
+	dc = NewDeadlineController(responsive, time.Second, context.Background)
+	b, err := dc.QueryBestBlock()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), b.Height)
+}
@@ -0,0 +1,186 @@
+package clientcontroller
+
+import (
+	"cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	finalitytypes "github.com/babylonchain/babylon/x/finality/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/babylonchain/finality-provider/finality-provider/faultinject"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+var _ ClientController = &FaultInjectingController{}
+
+// FaultInjectingController injects the faults of the faultinject package
+// into the requests to the wrapped client controller, which drops the txs
+// and delays all the requests
+type FaultInjectingController struct {
+	ClientController
+}
+
+func NewFaultInjectingController(cc ClientController) *FaultInjectingController {
+	return &FaultInjectingController{ClientController: cc}
+}
+
+func (fc *FaultInjectingController) RegisterFinalityProvider(
+	chainPk []byte,
+	fpPk *btcec.PublicKey,
+	pop []byte,
+	commission *math.LegacyDec,
+	description []byte,
+) (*types.TxResponse, error) {
+	faultinject.MaybeDelay()
+	if err := faultinject.MaybeDropTx(); err != nil {
+		return nil, err
+	}
+
+	return fc.ClientController.RegisterFinalityProvider(chainPk, fpPk, pop, commission, description)
+}
+
+func (fc *FaultInjectingController) CommitPubRandList(fpPk *btcec.PublicKey, startHeight uint64, numPubRand uint64, commitment []byte, sig *schnorr.Signature) (*types.TxResponse, error) {
+	faultinject.MaybeDelay()
+	if err := faultinject.MaybeDropTx(); err != nil {
+		return nil, err
+	}
+
+	return fc.ClientController.CommitPubRandList(fpPk, startHeight, numPubRand, commitment, sig)
+}
+
+func (fc *FaultInjectingController) SetRewardAddress(chainPk []byte, rewardAddr string) (*types.TxResponse, error) {
+	faultinject.MaybeDelay()
+	if err := faultinject.MaybeDropTx(); err != nil {
+		return nil, err
+	}
+
+	return fc.ClientController.SetRewardAddress(chainPk, rewardAddr)
+}
+
+func (fc *FaultInjectingController) SubmitFinalitySig(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.TxResponse, error) {
+	faultinject.MaybeDelay()
+	if err := faultinject.MaybeDropTx(); err != nil {
+		return nil, err
+	}
+
+	return fc.ClientController.SubmitFinalitySig(fpPk, block, pubRand, proof, sig)
+}
+
+func (fc *FaultInjectingController) SignFinalitySigTx(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.SignedTx, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.SignFinalitySigTx(fpPk, block, pubRand, proof, sig)
+}
+
+func (fc *FaultInjectingController) BroadcastSignedTx(tx *types.SignedTx) (*types.TxResponse, error) {
+	faultinject.MaybeDelay()
+	if err := faultinject.MaybeDropTx(); err != nil {
+		return nil, err
+	}
+
+	return fc.ClientController.BroadcastSignedTx(tx)
+}
+
+func (fc *FaultInjectingController) QueryTx(hash []byte) (*types.TxResponse, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryTx(hash)
+}
+
+func (fc *FaultInjectingController) SubmitBatchFinalitySigs(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.TxResponse, error) {
+	faultinject.MaybeDelay()
+	if err := faultinject.MaybeDropTx(); err != nil {
+		return nil, err
+	}
+
+	return fc.ClientController.SubmitBatchFinalitySigs(fpPk, blocks, pubRandList, proofList, sigs)
+}
+
+func (fc *FaultInjectingController) SignBatchFinalitySigsTx(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.SignedTx, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.SignBatchFinalitySigsTx(fpPk, blocks, pubRandList, proofList, sigs)
+}
+
+func (fc *FaultInjectingController) QueryFinalityProviderVotingPower(fpPk *btcec.PublicKey, blockHeight uint64) (uint64, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryFinalityProviderVotingPower(fpPk, blockHeight)
+}
+
+func (fc *FaultInjectingController) QueryFinalityProviderSlashed(fpPk *btcec.PublicKey) (bool, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryFinalityProviderSlashed(fpPk)
+}
+
+func (fc *FaultInjectingController) QueryRegisteredFinalityProvider(fpPk *btcec.PublicKey) (*types.RegisteredFinalityProvider, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryRegisteredFinalityProvider(fpPk)
+}
+
+func (fc *FaultInjectingController) QueryRegisteredFinalityProviders() ([]*types.RegisteredFinalityProvider, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryRegisteredFinalityProviders()
+}
+
+func (fc *FaultInjectingController) QueryLatestFinalizedBlocks(count uint64) ([]*types.BlockInfo, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryLatestFinalizedBlocks(count)
+}
+
+func (fc *FaultInjectingController) QueryVotesAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryVotesAtHeight(height)
+}
+
+func (fc *FaultInjectingController) QueryActiveFinalityProvidersAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryActiveFinalityProvidersAtHeight(height)
+}
+
+func (fc *FaultInjectingController) QueryVotingPowersAtHeight(height uint64) ([]*types.FinalityProviderPower, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryVotingPowersAtHeight(height)
+}
+
+func (fc *FaultInjectingController) QueryFinalitySigsAtHeight(height uint64) ([]*types.FinalitySig, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryFinalitySigsAtHeight(height)
+}
+
+func (fc *FaultInjectingController) QueryLastCommittedPublicRand(fpPk *btcec.PublicKey, count uint64) (map[uint64]*finalitytypes.PubRandCommitResponse, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryLastCommittedPublicRand(fpPk, count)
+}
+
+func (fc *FaultInjectingController) QueryBlock(height uint64) (*types.BlockInfo, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryBlock(height)
+}
+
+func (fc *FaultInjectingController) QueryBlocks(startHeight, endHeight, limit uint64) ([]*types.BlockInfo, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryBlocks(startHeight, endHeight, limit)
+}
+
+func (fc *FaultInjectingController) QueryBestBlock() (*types.BlockInfo, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryBestBlock()
+}
+
+func (fc *FaultInjectingController) QueryBestBlockTime() (*types.BlockTime, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryBestBlockTime()
+}
+
+func (fc *FaultInjectingController) QueryActivatedHeight() (uint64, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryActivatedHeight()
+}
+
+func (fc *FaultInjectingController) QueryBalance(address string) (sdk.Coins, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryBalance(address)
+}
+
+func (fc *FaultInjectingController) QueryChainParams() (*types.ChainParams, error) {
+	faultinject.MaybeDelay()
+	return fc.ClientController.QueryChainParams()
+}
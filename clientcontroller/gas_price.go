@@ -0,0 +1,200 @@
+package clientcontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	nodeservice "github.com/cosmos/cosmos-sdk/client/grpc/node"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+)
+
+// maxGasPriceResponseSize bounds the body of the responses of the oracle
+const maxGasPriceResponseSize = 1 << 16
+
+// gasPriceOracle picks the gas price of each tx from the source of the config
+// within its bounds. If the source fails, the last gas price picked is used,
+// or the gas price of the config if none is picked yet
+type gasPriceOracle struct {
+	source     string
+	oracleURL  string
+	timeout    time.Duration
+	httpClient *http.Client
+	// node queries the minimum gas price of the node for the chain source
+	node nodeservice.ServiceClient
+
+	static   sdk.DecCoin
+	minPrice *sdk.DecCoin
+	maxPrice *sdk.DecCoin
+
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	last *sdk.DecCoin
+}
+
+func newGasPriceOracle(cfg *fpcfg.BBNConfig, conn gogogrpc.ClientConn, logger *zap.Logger) (*gasPriceOracle, error) {
+	static, minPrice, maxPrice, err := cfg.GasPriceBounds()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gasPriceOracle{
+		source:     cfg.GasPriceSource,
+		oracleURL:  cfg.GasPriceOracleURL,
+		timeout:    cfg.GasPriceTimeout,
+		httpClient: &http.Client{Timeout: cfg.GasPriceTimeout},
+		node:       nodeservice.NewServiceClient(conn),
+		static:     static,
+		minPrice:   minPrice,
+		maxPrice:   maxPrice,
+		logger:     logger,
+	}, nil
+}
+
+// gasPrices returns the gas prices of the next tx in the format of the
+// gas-prices of the config
+func (o *gasPriceOracle) gasPrices(ctx context.Context) string {
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	price, err := o.query(ctx)
+	if err != nil {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+
+		fallback := o.static
+		if o.last != nil {
+			fallback = *o.last
+		}
+		o.logger.Warn("failed to query the gas price, falling back to the last one",
+			zap.String("source", o.source), zap.String("gas_price", fallback.String()), zap.Error(err))
+
+		return fallback.String()
+	}
+
+	price = o.bound(price)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.last == nil || !o.last.IsEqual(price) {
+		o.logger.Debug("the gas price is updated",
+			zap.String("source", o.source), zap.String("gas_price", price.String()))
+	}
+	o.last = &price
+
+	return price.String()
+}
+
+// query returns the gas price from the source in the denom of the config
+func (o *gasPriceOracle) query(ctx context.Context) (sdk.DecCoin, error) {
+	switch o.source {
+	case fpcfg.GasPriceSourceChain:
+		return o.queryNode(ctx)
+	case fpcfg.GasPriceSourceOracle:
+		return o.queryOracle(ctx)
+	default:
+		return o.static, nil
+	}
+}
+
+// queryNode returns the minimum gas price the node accepts the txs with
+func (o *gasPriceOracle) queryNode(ctx context.Context) (sdk.DecCoin, error) {
+	res, err := o.node.Config(ctx, &nodeservice.ConfigRequest{})
+	if err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("failed to query the config of the node: %w", err)
+	}
+	prices, err := sdk.ParseDecCoins(res.MinimumGasPrice)
+	if err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("invalid minimum gas price of the node %q: %w", res.MinimumGasPrice, err)
+	}
+	for _, p := range prices {
+		if p.Denom == o.static.Denom {
+			return p, nil
+		}
+	}
+
+	return sdk.DecCoin{}, fmt.Errorf("the node has no minimum gas price in %s", o.static.Denom)
+}
+
+// queryOracle returns the gas price served by the oracle, whose amount is in
+// the denom of the config if it has no denom
+func (o *gasPriceOracle) queryOracle(ctx context.Context) (sdk.DecCoin, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.oracleURL, nil)
+	if err != nil {
+		return sdk.DecCoin{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("failed to query the gas price oracle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGasPriceResponseSize))
+	if err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("failed to read the response of the gas price oracle: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return sdk.DecCoin{}, fmt.Errorf("the gas price oracle responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var res struct {
+		GasPrice json.RawMessage `json:"gas_price"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("invalid response of the gas price oracle: %w", err)
+	}
+	// the gas price is either a string or a number
+	var value string
+	if err := json.Unmarshal(res.GasPrice, &value); err != nil {
+		value = string(res.GasPrice)
+	}
+
+	return o.parsePrice(value)
+}
+
+// parsePrice parses the gas price with or without a denom, which must be the
+// denom of the config
+func (o *gasPriceOracle) parsePrice(value string) (sdk.DecCoin, error) {
+	value = strings.TrimSpace(value)
+	if price, err := sdk.ParseDecCoin(value); err == nil {
+		if price.Denom != o.static.Denom {
+			return sdk.DecCoin{}, fmt.Errorf("the gas price %s is not in %s", price, o.static.Denom)
+		}
+		return price, nil
+	}
+
+	amount, err := sdkmath.LegacyNewDecFromStr(value)
+	if err != nil {
+		return sdk.DecCoin{}, fmt.Errorf("invalid gas price %q: %w", value, err)
+	}
+	if amount.IsNegative() {
+		return sdk.DecCoin{}, fmt.Errorf("invalid gas price %q: should not be negative", value)
+	}
+
+	return sdk.NewDecCoinFromDec(o.static.Denom, amount), nil
+}
+
+// bound returns the gas price within the bounds of the config
+func (o *gasPriceOracle) bound(price sdk.DecCoin) sdk.DecCoin {
+	if o.minPrice != nil && price.Amount.LT(o.minPrice.Amount) {
+		return *o.minPrice
+	}
+	if o.maxPrice != nil && price.Amount.GT(o.maxPrice.Amount) {
+		return *o.maxPrice
+	}
+
+	return price
+}
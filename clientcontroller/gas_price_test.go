@@ -0,0 +1,61 @@
+package clientcontroller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+)
+
+func TestGasPriceOracle(t *testing.T) {
+	var response atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := response.Load().(string)
+		if body == "" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	cfg := fpcfg.DefaultBBNConfig()
+	cfg.GasPrices = "0.002ubbn"
+	cfg.GasPriceSource = fpcfg.GasPriceSourceOracle
+	cfg.GasPriceOracleURL = srv.URL
+	cfg.GasPriceTimeout = time.Second
+	cfg.MinGasPrice = "0.001ubbn"
+	cfg.MaxGasPrice = "0.1ubbn"
+	o, err := newGasPriceOracle(&cfg, nil, zap.NewNop())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// the gas price of the config is used until a gas price is picked
+	response.Store("")
+	require.Equal(t, "0.002000000000000000ubbn", o.gasPrices(ctx))
+
+	// the gas price is taken with or without a denom
+	response.Store(`{"gas_price": "0.005ubbn"}`)
+	require.Equal(t, "0.005000000000000000ubbn", o.gasPrices(ctx))
+	response.Store(`{"gas_price": 0.006}`)
+	require.Equal(t, "0.006000000000000000ubbn", o.gasPrices(ctx))
+
+	// the last gas price is used once the oracle fails
+	response.Store("")
+	require.Equal(t, "0.006000000000000000ubbn", o.gasPrices(ctx))
+	response.Store(`{"gas_price": "0.005uatom"}`)
+	require.Equal(t, "0.006000000000000000ubbn", o.gasPrices(ctx))
+
+	// the gas price is kept within the bounds
+	response.Store(`{"gas_price": "5ubbn"}`)
+	require.Equal(t, "0.100000000000000000ubbn", o.gasPrices(ctx))
+	response.Store(`{"gas_price": "0"}`)
+	require.Equal(t, "0.001000000000000000ubbn", o.gasPrices(ctx))
+}
@@ -7,8 +7,10 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/chaincfg"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"go.uber.org/zap"
 
+	bbntypes "github.com/babylonchain/babylon/types"
 	finalitytypes "github.com/babylonchain/babylon/x/finality/types"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	"github.com/babylonchain/finality-provider/types"
@@ -37,9 +39,30 @@ type ClientController interface {
 	// SubmitFinalitySig submits the finality signature to the consumer chain
 	SubmitFinalitySig(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.TxResponse, error)
 
+	// SignFinalitySigTx signs the tx of the finality signature without
+	// broadcasting it, or returns ErrSignedTxUnsupported if the finality
+	// signatures are only submitted through SubmitFinalitySig
+	SignFinalitySigTx(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.SignedTx, error)
+
+	// BroadcastSignedTx broadcasts the signed tx and waits for its inclusion
+	BroadcastSignedTx(tx *types.SignedTx) (*types.TxResponse, error)
+
+	// QueryTx returns the response of the included tx with the given hash,
+	// or ErrTxNotFound if the tx is not included
+	QueryTx(hash []byte) (*types.TxResponse, error)
+
 	// SubmitBatchFinalitySigs submits a batch of finality signatures to the consumer chain
 	SubmitBatchFinalitySigs(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.TxResponse, error)
 
+	// SignBatchFinalitySigsTx signs the tx of a batch of finality signatures
+	// without broadcasting it, or returns ErrSignedTxUnsupported if the
+	// finality signatures are only submitted through SubmitBatchFinalitySigs
+	SignBatchFinalitySigsTx(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.SignedTx, error)
+
+	// SetRewardAddress sets the address receiving the rewards of the account
+	// of the chain key, which must be the key signing the txs
+	SetRewardAddress(chainPk []byte, rewardAddr string) (*types.TxResponse, error)
+
 	// Note: the following queries are only for PoC
 
 	// QueryFinalityProviderVotingPower queries the voting power of the finality provider at a given height
@@ -48,9 +71,33 @@ type ClientController interface {
 	// QueryFinalityProviderSlashed queries if the finality provider is slashed
 	QueryFinalityProviderSlashed(fpPk *btcec.PublicKey) (bool, error)
 
+	// QueryRegisteredFinalityProvider returns the record of the finality
+	// provider registered on the consumer chain, or nil if it is not registered
+	QueryRegisteredFinalityProvider(fpPk *btcec.PublicKey) (*types.RegisteredFinalityProvider, error)
+
+	// QueryRegisteredFinalityProviders returns the records of all the
+	// finality providers registered on the consumer chain
+	QueryRegisteredFinalityProviders() ([]*types.RegisteredFinalityProvider, error)
+
 	// QueryLatestFinalizedBlocks returns the latest finalized blocks
 	QueryLatestFinalizedBlocks(count uint64) ([]*types.BlockInfo, error)
 
+	// QueryVotesAtHeight returns the BTC public keys of the finality providers
+	// that have voted on the block at the given height
+	QueryVotesAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error)
+
+	// QueryActiveFinalityProvidersAtHeight returns the BTC public keys of the
+	// finality providers with voting power at the given height
+	QueryActiveFinalityProvidersAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error)
+
+	// QueryVotingPowersAtHeight returns the voting powers of the finality
+	// providers with voting power at the given height
+	QueryVotingPowersAtHeight(height uint64) ([]*types.FinalityProviderPower, error)
+
+	// QueryFinalitySigsAtHeight returns the finality signatures on the block
+	// at the given height accepted by the consumer chain
+	QueryFinalitySigsAtHeight(height uint64) ([]*types.FinalitySig, error)
+
 	// QueryLastCommittedPublicRand returns the last committed public randomness
 	QueryLastCommittedPublicRand(fpPk *btcec.PublicKey, count uint64) (map[uint64]*finalitytypes.PubRandCommitResponse, error)
 
@@ -63,10 +110,22 @@ type ClientController interface {
 	// QueryBestBlock queries the tip block of the consumer chain
 	QueryBestBlock() (*types.BlockInfo, error)
 
+	// QueryBestBlockTime queries the height and the timestamp of the tip block
+	// of the consumer chain, which are read from the same block
+	QueryBestBlockTime() (*types.BlockTime, error)
+
 	// QueryActivatedHeight returns the activated height of the consumer chain
 	// error will be returned if the consumer chain has not been activated
 	QueryActivatedHeight() (uint64, error)
 
+	// QueryChainParams queries the parameters of the consumer chain that
+	// finality providers must comply with
+	QueryChainParams() (*types.ChainParams, error)
+
+	// QueryBalance queries the balance of the given bech32 address on the
+	// consumer chain
+	QueryBalance(address string) (sdk.Coins, error)
+
 	Close() error
 }
 
@@ -0,0 +1,162 @@
+package clientcontroller
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+const relayerFinalitySigsPath = "/finality-sigs"
+
+// ErrSignedTxUnsupported is returned by the client controllers that do not
+// sign the txs of the finality signatures themselves
+var ErrSignedTxUnsupported = errors.New("the txs of the finality signatures are not signed by the client controller")
+
+var _ ClientController = &RelayerController{}
+
+// RelayerController hands signed finality messages to an external relayer
+// service instead of broadcasting them to the consumer chain, so that the
+// broadcasting, fee management, and batching can be centralized. All the
+// other operations are served by the wrapped client controller
+type RelayerController struct {
+	ClientController
+
+	url        string
+	httpClient *http.Client
+}
+
+// RelayerFinalitySig is a signed finality message handed to the relayer
+type RelayerFinalitySig struct {
+	Height    uint64 `json:"height"`
+	BlockHash string `json:"block_hash"`
+	PubRand   string `json:"pub_rand"`
+	Proof     string `json:"proof"`
+	Sig       string `json:"sig"`
+}
+
+// RelayerFinalitySigsRequest is the body of the request sent to the relayer
+type RelayerFinalitySigsRequest struct {
+	FpBtcPk    string                `json:"fp_btc_pk"`
+	Signatures []*RelayerFinalitySig `json:"signatures"`
+}
+
+// RelayerFinalitySigsResponse is the body of the response from the relayer
+type RelayerFinalitySigsResponse struct {
+	TxHash string `json:"tx_hash"`
+	Error  string `json:"error,omitempty"`
+}
+
+func NewRelayerController(cc ClientController, url string, timeout time.Duration) (*RelayerController, error) {
+	if url == "" {
+		return nil, fmt.Errorf("the relayer URL is empty")
+	}
+
+	return &RelayerController{
+		ClientController: cc,
+		url:              url,
+		httpClient:       &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// SubmitFinalitySig hands the finality signature to the relayer
+func (rc *RelayerController) SubmitFinalitySig(
+	fpPk *btcec.PublicKey,
+	block *types.BlockInfo,
+	pubRand *btcec.FieldVal,
+	proof []byte,
+	sig *btcec.ModNScalar,
+) (*types.TxResponse, error) {
+	return rc.SubmitBatchFinalitySigs(fpPk, []*types.BlockInfo{block}, []*btcec.FieldVal{pubRand}, [][]byte{proof}, []*btcec.ModNScalar{sig})
+}
+
+// SignFinalitySigTx returns ErrSignedTxUnsupported, as the txs of the finality
+// signatures are built and tracked by the relayer
+func (rc *RelayerController) SignFinalitySigTx(
+	fpPk *btcec.PublicKey,
+	block *types.BlockInfo,
+	pubRand *btcec.FieldVal,
+	proof []byte,
+	sig *btcec.ModNScalar,
+) (*types.SignedTx, error) {
+	return nil, ErrSignedTxUnsupported
+}
+
+// SubmitBatchFinalitySigs hands a batch of finality signatures to the relayer
+func (rc *RelayerController) SubmitBatchFinalitySigs(
+	fpPk *btcec.PublicKey,
+	blocks []*types.BlockInfo,
+	pubRandList []*btcec.FieldVal,
+	proofList [][]byte,
+	sigs []*btcec.ModNScalar,
+) (*types.TxResponse, error) {
+	if len(blocks) != len(sigs) || len(blocks) != len(pubRandList) || len(blocks) != len(proofList) {
+		return nil, fmt.Errorf("the number of blocks %v should match the number of finality signatures %v", len(blocks), len(sigs))
+	}
+
+	req := &RelayerFinalitySigsRequest{
+		FpBtcPk:    bbntypes.NewBIP340PubKeyFromBTCPK(fpPk).MarshalHex(),
+		Signatures: make([]*RelayerFinalitySig, 0, len(blocks)),
+	}
+	for i, b := range blocks {
+		pubRandBytes := pubRandList[i].Bytes()
+		sigBytes := sigs[i].Bytes()
+		req.Signatures = append(req.Signatures, &RelayerFinalitySig{
+			Height:    b.Height,
+			BlockHash: hex.EncodeToString(b.Hash),
+			PubRand:   hex.EncodeToString(pubRandBytes[:]),
+			Proof:     hex.EncodeToString(proofList[i]),
+			Sig:       hex.EncodeToString(sigBytes[:]),
+		})
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRes, err := rc.httpClient.Post(rc.url+relayerFinalitySigsPath, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send finality signatures to the relayer: %w", err)
+	}
+	defer httpRes.Body.Close()
+
+	resBody, err := io.ReadAll(httpRes.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response from the relayer: %w", err)
+	}
+
+	var res RelayerFinalitySigsResponse
+	if err := json.Unmarshal(resBody, &res); err != nil {
+		return nil, fmt.Errorf("invalid response from the relayer with status %s: %w", httpRes.Status, err)
+	}
+
+	if httpRes.StatusCode != http.StatusOK {
+		// the error message from the relayer is passed through as is
+		// so that it can be classified by IsUnrecoverable
+		return nil, fmt.Errorf("the relayer rejected the finality signatures with status %s: %s", httpRes.Status, res.Error)
+	}
+
+	return &types.TxResponse{TxHash: res.TxHash}, nil
+}
+
+// SignBatchFinalitySigsTx returns ErrSignedTxUnsupported, as the txs of the
+// finality signatures are built and tracked by the relayer
+func (rc *RelayerController) SignBatchFinalitySigsTx(
+	fpPk *btcec.PublicKey,
+	blocks []*types.BlockInfo,
+	pubRandList []*btcec.FieldVal,
+	proofList [][]byte,
+	sigs []*btcec.ModNScalar,
+) (*types.SignedTx, error) {
+	return nil, ErrSignedTxUnsupported
+}
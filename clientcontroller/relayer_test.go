@@ -0,0 +1,53 @@
+package clientcontroller
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestRelayerController_SubmitFinalitySig(t *testing.T) {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	var received RelayerFinalitySigsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, relayerFinalitySigsPath, r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Signatures[0].Height == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(&RelayerFinalitySigsResponse{Error: "invalid height"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&RelayerFinalitySigsResponse{TxHash: "hash"})
+	}))
+	defer server.Close()
+
+	rc, err := NewRelayerController(nil, server.URL, time.Second)
+	require.NoError(t, err)
+
+	var pubRand btcec.FieldVal
+	pubRand.SetInt(1)
+	var sig btcec.ModNScalar
+	sig.SetInt(2)
+	block := &types.BlockInfo{Height: 10, Hash: []byte{1, 2, 3}}
+
+	res, err := rc.SubmitFinalitySig(privKey.PubKey(), block, &pubRand, []byte{4}, &sig)
+	require.NoError(t, err)
+	require.Equal(t, "hash", res.TxHash)
+	require.Len(t, received.Signatures, 1)
+	require.Equal(t, block.Height, received.Signatures[0].Height)
+	require.Equal(t, hex.EncodeToString(block.Hash), received.Signatures[0].BlockHash)
+
+	block.Height = 0
+	_, err = rc.SubmitFinalitySig(privKey.PubKey(), block, &pubRand, []byte{4}, &sig)
+	require.ErrorContains(t, err, "invalid height")
+}
@@ -7,6 +7,7 @@ import (
 	sdkErr "cosmossdk.io/errors"
 	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
 	finalitytypes "github.com/babylonchain/babylon/x/finality/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
 // these errors are considered unrecoverable because these indicate
@@ -33,6 +34,39 @@ func IsUnrecoverable(err error) bool {
 	return false
 }
 
+// oversizedTxErrors are the messages of the errors of a tx exceeding the max
+// tx size or the gas limit of the chain, which are raised by either the Cosmos
+// SDK or the mempool of CometBFT
+var oversizedTxErrors = []string{
+	sdkerrors.ErrTxTooLarge.Error(),
+	sdkerrors.ErrOutOfGas.Error(),
+	"is greater than max gas",
+}
+
+// IsTxOversized returns true when the error indicates that the tx exceeds the
+// max tx size or the gas limit of the chain, in which case it can only go
+// through once split into smaller txs
+func IsTxOversized(err error) bool {
+	// the mempool of CometBFT capitalizes its error
+	msg := strings.ToLower(err.Error())
+	for _, e := range oversizedTxErrors {
+		if strings.Contains(msg, e) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTxRejected returns true when the error indicates that the tx is rejected
+// by the chain, either by the check of the mempool or once included, or has
+// left the mempool without being included, in which case the tx will never be
+// included as is
+func IsTxRejected(err error) bool {
+	var abciErr *sdkErr.Error
+	return errors.Is(err, ErrTxDropped) || errors.As(err, &abciErr)
+}
+
 type ExpectedError struct {
 	error
 }
@@ -13,3 +13,10 @@ func TestExpectedErr(t *testing.T) {
 	wrappedErr := fmt.Errorf("expected: %w", expectedErr)
 	require.True(t, IsExpected(wrappedErr))
 }
+
+func TestIsTxOversized(t *testing.T) {
+	require.True(t, IsTxOversized(fmt.Errorf("failed to broadcast: Tx too large. Max size is 1048576, but got 1048577")))
+	require.True(t, IsTxOversized(fmt.Errorf("out of gas in location: WriteFlat; gasWanted: 200000, gasUsed: 200001: out of gas")))
+	require.True(t, IsTxOversized(fmt.Errorf("gas wanted 50000001 is greater than max gas 50000000")))
+	require.False(t, IsTxOversized(fmt.Errorf("account sequence mismatch")))
+}
@@ -0,0 +1,159 @@
+package clientcontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sdkErr "cosmossdk.io/errors"
+	bbnapp "github.com/babylonchain/babylon/app"
+	bbncfg "github.com/babylonchain/babylon/client/config"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/relayer/v2/relayer/chains/cosmos"
+	"github.com/cosmos/relayer/v2/relayer/provider"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+)
+
+// txSender sends the txs with the memo of the config and the gas adjustment
+// of their kind of message. The Babylon client always sends the txs with an
+// empty memo, a single gas adjustment and a static gas price, so the txs go
+// through a provider of their own. All the txs go through the sender so that
+// the account sequence is tracked in a single place, including the txs that
+// are signed before they are broadcast
+type txSender struct {
+	provider *cosmos.CosmosProvider
+	memo     string
+
+	// broadcastMode is the mode the txs are broadcast by rpcClient in. The
+	// txs broadcast in the async mode are confirmed through events, and the
+	// txs broadcast in the async and the sync modes are looked up every
+	// confirmPollInterval
+	broadcastMode       string
+	rpcClient           broadcastClient
+	events              *txEventDispatcher
+	blockTimeout        time.Duration
+	confirmPollInterval time.Duration
+
+	// gasPrices picks the gas price of each tx, which is nil if the gas
+	// prices of the config, staticGasPrices, are used
+	gasPrices       *gasPriceOracle
+	staticGasPrices string
+
+	// mu guards the gas adjustment and the gas prices of the provider, which
+	// are read while the txs are built and simulated, and the next sequence
+	// of the account
+	mu           sync.Mutex
+	nextSequence uint64
+}
+
+func newTxSender(cfg *bbncfg.BabylonConfig, memo, broadcastMode string, logger *zap.Logger) (*txSender, error) {
+	cp, err := cfg.ToCosmosProviderConfig().NewProvider(logger, "", cfg.Debug, "babylon")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the provider of the txs: %w", err)
+	}
+	p := cp.(*cosmos.CosmosProvider)
+	p.PCfg.KeyDirectory = cfg.KeyDirectory
+
+	// the messages of Babylon are not registered in the codec of the relayer
+	encCfg := bbnapp.GetEncodingConfig()
+	p.Cdc = cosmos.Codec{
+		InterfaceRegistry: encCfg.InterfaceRegistry,
+		Marshaler:         encCfg.Codec,
+		TxConfig:          encCfg.TxConfig,
+		Amino:             encCfg.Amino,
+	}
+
+	if err := p.Init(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to initialize the provider of the txs: %w", err)
+	}
+
+	s := &txSender{
+		provider:            p,
+		memo:                memo,
+		broadcastMode:       broadcastMode,
+		blockTimeout:        cfg.BlockTimeout,
+		confirmPollInterval: txConfirmPollInterval,
+		staticGasPrices:     cfg.GasPrices,
+	}
+	if s.blockTimeout == 0 {
+		s.blockTimeout = defaultBroadcastTimeout
+	}
+	s.rpcClient, err = newBroadcastClient(cfg.RPCAddr, broadcastMode)
+	if err != nil {
+		return nil, err
+	}
+	if broadcastMode == fpcfg.BroadcastModeAsync {
+		signer, err := p.ShowAddress(p.PCfg.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the address of the key %s: %w", p.PCfg.Key, err)
+		}
+		s.events = newTxEventDispatcher(s.rpcClient, signer, logger)
+	}
+
+	return s, nil
+}
+
+// sendMsgs sends the messages in a tx with the memo and the given gas
+// adjustment, and waits for its inclusion. Only the building of the tx holds
+// the lock, so the broadcasts and the waits of the txs overlap. As the Babylon
+// client does, the expected errors are swallowed with a nil response
+func (s *txSender) sendMsgs(ctx context.Context, msgs []sdk.Msg, gasAdjustment float64, expectedErrs []*sdkErr.Error) (*provider.RelayerTxResponse, error) {
+	txBytes, err := s.signMsgs(ctx, msgs, gasAdjustment)
+	if err != nil {
+		return nil, swallowExpectedErr(err, expectedErrs)
+	}
+
+	res, err := s.broadcastBuiltTx(ctx, txBytes, nil)
+	if err != nil {
+		return nil, swallowExpectedErr(err, expectedErrs)
+	}
+
+	return res, nil
+}
+
+// signMsgs returns the signed tx of the messages with the memo and the given
+// gas adjustment, which takes the next sequence of the account and has to be
+// broadcast by broadcastBuiltTx
+func (s *txSender) signMsgs(ctx context.Context, msgs []sdk.Msg, gasAdjustment float64) ([]byte, error) {
+	gasPrices := s.nextGasPrices(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txBytes, err := s.buildTx(ctx, msgs, gasAdjustment, gasPrices)
+	if err != nil {
+		return nil, err
+	}
+	// the next tx takes the next sequence even before this one is broadcast,
+	// so that the broadcasts do not hold the lock
+	s.nextSequence++
+
+	return txBytes, nil
+}
+
+// nextGasPrices returns the gas prices of the next tx, which are picked from
+// the source before the lock is taken, as the source may be slow
+func (s *txSender) nextGasPrices(ctx context.Context) string {
+	if s.gasPrices == nil {
+		return s.staticGasPrices
+	}
+
+	return s.gasPrices.gasPrices(ctx)
+}
+
+// swallowExpectedErr returns nil if the error is one of the expected errors
+func swallowExpectedErr(err error, expectedErrs []*sdkErr.Error) error {
+	for _, e := range expectedErrs {
+		// cannot use errors.Is as the errors are decoded from the
+		// response of the chain
+		if strings.Contains(err.Error(), e.Error()) {
+			return nil
+		}
+	}
+
+	return err
+}
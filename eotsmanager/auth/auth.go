@@ -0,0 +1,291 @@
+// Package auth authenticates the requests to the EOTS manager daemon with
+// HMAC tokens and restricts each client to the EOTS keys it is granted, so
+// that only the authorized finality provider daemon can request signatures
+// for a given key
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+)
+
+const (
+	HeaderClientID  = "x-eots-client-id"
+	HeaderTimestamp = "x-eots-timestamp"
+	HeaderNonce     = "x-eots-nonce"
+	HeaderSignature = "x-eots-signature"
+
+	// AllKeys grants a client all the EOTS keys
+	AllKeys = "*"
+	// CreateKeys grants a client the creation of EOTS keys, which is not
+	// implied by AllKeys
+	CreateKeys = "create"
+
+	// maxClockSkew bounds the age of a signed request, limiting the window
+	// in which a captured request can be replayed
+	maxClockSkew = 5 * time.Minute
+)
+
+// Sign returns the HMAC of the request to the method at the unix time in
+// seconds with the nonce under the secret of the client
+func Sign(secret []byte, method string, timestamp int64, nonce string, req interface{}) (string, error) {
+	var reqBytes []byte
+	if msg, ok := req.(pm.Message); ok {
+		bz, err := pm.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return "", err
+		}
+		reqBytes = bz
+	}
+	reqHash := sha256.Sum256(reqBytes)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(method))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(nonce))
+	mac.Write([]byte{'\n'})
+	mac.Write(reqHash[:])
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// newNonce returns a random nonce, which makes the signatures of identical
+// requests within the same second differ
+func newNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(nonce), nil
+}
+
+// UnaryClientInterceptor signs each request with the secret of the client
+func UnaryClientInterceptor(clientID, secret string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		timestamp := time.Now().Unix()
+		nonce, err := newNonce()
+		if err != nil {
+			return fmt.Errorf("failed to generate the nonce: %w", err)
+		}
+		sig, err := Sign([]byte(secret), method, timestamp, nonce, req)
+		if err != nil {
+			return fmt.Errorf("failed to sign the request: %w", err)
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			HeaderClientID, clientID,
+			HeaderTimestamp, strconv.FormatInt(timestamp, 10),
+			HeaderNonce, nonce,
+			HeaderSignature, sig,
+		)
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// Authenticator verifies the requests of the clients and their access to
+// the EOTS keys
+type Authenticator struct {
+	secrets map[string][]byte
+	// keys are the hex EOTS public keys granted to each client
+	keys map[string]map[string]struct{}
+
+	mu sync.Mutex
+	// seen keeps the signatures of the accepted requests until they expire,
+	// so that a captured request cannot be replayed within the clock skew
+	seen      map[string]time.Time
+	nextPrune time.Time
+}
+
+// NewAuthenticator creates an authenticator from the secrets of the clients
+// keyed by the client IDs, and the comma-separated hex EOTS public keys
+// granted to the clients, where AllKeys grants all the keys and CreateKeys
+// grants the creation of keys. A client is granted no key unless it is listed
+func NewAuthenticator(secrets map[string]string, grants map[string]string) (*Authenticator, error) {
+	a := &Authenticator{
+		secrets: make(map[string][]byte, len(secrets)),
+		keys:    make(map[string]map[string]struct{}, len(grants)),
+		seen:    make(map[string]time.Time),
+	}
+	for clientID, secret := range secrets {
+		if secret == "" {
+			return nil, fmt.Errorf("the secret of the client %s is empty", clientID)
+		}
+		a.secrets[clientID] = []byte(secret)
+	}
+
+	for clientID, grant := range grants {
+		if _, ok := a.secrets[clientID]; !ok {
+			return nil, fmt.Errorf("the keys are granted to the unknown client %s", clientID)
+		}
+		keys := make(map[string]struct{})
+		for _, pkHex := range strings.Split(grant, ",") {
+			pkHex = strings.ToLower(strings.TrimSpace(pkHex))
+			if pkHex != AllKeys && pkHex != CreateKeys {
+				if bz, err := hex.DecodeString(pkHex); err != nil || len(bz) != 32 {
+					return nil, fmt.Errorf("invalid EOTS public key %s granted to the client %s", pkHex, clientID)
+				}
+			}
+			keys[pkHex] = struct{}{}
+		}
+		a.keys[clientID] = keys
+	}
+
+	return a, nil
+}
+
+// UnaryServerInterceptor rejects the unauthenticated and replayed requests,
+// the requests for the keys not granted to the client, and the creation of
+// keys unless granted. The keys not granted are also filtered out of the
+// listed keys
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		clientID, err := a.authenticate(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		if _, ok := req.(*proto.CreateKeyRequest); ok && !a.granted(clientID, CreateKeys) {
+			return nil, status.Errorf(codes.PermissionDenied,
+				"the creation of keys is not granted to the client %s", clientID)
+		}
+
+		if r, ok := req.(interface{ GetUid() []byte }); ok {
+			if !a.granted(clientID, hex.EncodeToString(r.GetUid())) {
+				return nil, status.Errorf(codes.PermissionDenied,
+					"the key %x is not granted to the client %s", r.GetUid(), clientID)
+			}
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if res, ok := resp.(*proto.ListKeysResponse); ok {
+			granted := make([]*proto.KeyInfo, 0, len(res.Keys))
+			for _, k := range res.Keys {
+				if a.granted(clientID, hex.EncodeToString(k.Pk)) {
+					granted = append(granted, k)
+				}
+			}
+			res.Keys = granted
+		}
+
+		return resp, nil
+	}
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, method string, req interface{}) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", fmt.Errorf("missing credentials")
+	}
+	clientID, timestampStr, nonce, sig := first(md, HeaderClientID), first(md, HeaderTimestamp),
+		first(md, HeaderNonce), first(md, HeaderSignature)
+	if clientID == "" || timestampStr == "" || nonce == "" || sig == "" {
+		return "", fmt.Errorf("missing credentials")
+	}
+
+	secret, ok := a.secrets[clientID]
+	if !ok {
+		return "", fmt.Errorf("unknown client %s", clientID)
+	}
+
+	timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid timestamp %s", timestampStr)
+	}
+	if skew := time.Since(time.Unix(timestamp, 0)); skew > maxClockSkew || skew < -maxClockSkew {
+		return "", fmt.Errorf("the request is signed %v away from the server time", skew)
+	}
+
+	expectedSig, err := Sign(secret, method, timestamp, nonce, req)
+	if err != nil {
+		return "", err
+	}
+	if !hmac.Equal([]byte(sig), []byte(expectedSig)) {
+		return "", fmt.Errorf("invalid signature of the client %s", clientID)
+	}
+
+	if !a.markSeen(expectedSig, time.Unix(timestamp, 0).Add(maxClockSkew)) {
+		return "", fmt.Errorf("the request of the client %s is replayed", clientID)
+	}
+
+	return clientID, nil
+}
+
+// markSeen records the signature of an accepted request until it expires,
+// and returns false if the signature has been seen. The expired signatures
+// are pruned at most once every maxClockSkew, after which the requests
+// carrying them are refused as stale anyway
+func (a *Authenticator) markSeen(sig string, expiry time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if now.After(a.nextPrune) {
+		for s, exp := range a.seen {
+			if now.After(exp) {
+				delete(a.seen, s)
+			}
+		}
+		a.nextPrune = now.Add(maxClockSkew)
+	}
+
+	if _, ok := a.seen[sig]; ok {
+		return false
+	}
+	a.seen[sig] = expiry
+
+	return true
+}
+
+func (a *Authenticator) granted(clientID, grant string) bool {
+	keys := a.keys[clientID]
+	if _, ok := keys[AllKeys]; ok && grant != CreateKeys {
+		return true
+	}
+	_, ok := keys[grant]
+
+	return ok
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
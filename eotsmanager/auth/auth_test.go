@@ -0,0 +1,110 @@
+package auth_test
+
+import (
+	"context"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/auth"
+	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+)
+
+const method = "/proto.EOTSManager/SignEOTS"
+
+var (
+	grantedPk = make([]byte, 32)
+	otherPk   = append(make([]byte, 31), 1)
+)
+
+func signedCtx(t *testing.T, clientID, secret string, timestamp int64, req interface{}) context.Context {
+	return signedCtxWithNonce(t, clientID, secret, timestamp, strconv.FormatInt(time.Now().UnixNano(), 10), req)
+}
+
+func signedCtxWithNonce(t *testing.T, clientID, secret string, timestamp int64, nonce string, req interface{}) context.Context {
+	sig, err := auth.Sign([]byte(secret), method, timestamp, nonce, req)
+	require.NoError(t, err)
+
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		auth.HeaderClientID, clientID,
+		auth.HeaderTimestamp, strconv.FormatInt(timestamp, 10),
+		auth.HeaderNonce, nonce,
+		auth.HeaderSignature, sig,
+	))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	a, err := auth.NewAuthenticator(
+		map[string]string{"fpd": "secret", "admin": "admin-secret"},
+		map[string]string{"fpd": hex.EncodeToString(grantedPk), "admin": auth.AllKeys + "," + auth.CreateKeys},
+	)
+	require.NoError(t, err)
+	interceptor := a.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &proto.ListKeysResponse{Keys: []*proto.KeyInfo{{Pk: grantedPk}, {Pk: otherPk}}}, nil
+	}
+	now := time.Now().Unix()
+
+	req := &proto.SignEOTSRequest{Uid: grantedPk, Height: 1}
+	_, err = interceptor(signedCtx(t, "fpd", "secret", now, req), req, info, handler)
+	require.NoError(t, err)
+
+	// the key not granted is refused
+	otherReq := &proto.SignEOTSRequest{Uid: otherPk, Height: 1}
+	_, err = interceptor(signedCtx(t, "fpd", "secret", now, otherReq), otherReq, info, handler)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = interceptor(signedCtx(t, "admin", "admin-secret", now, otherReq), otherReq, info, handler)
+	require.NoError(t, err)
+
+	// the wrong secret, a tampered request, a stale signature and missing
+	// credentials are refused
+	_, err = interceptor(signedCtx(t, "fpd", "wrong", now, req), req, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	tampered := &proto.SignEOTSRequest{Uid: grantedPk, Height: 2}
+	_, err = interceptor(signedCtx(t, "fpd", "secret", now, req), tampered, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = interceptor(signedCtx(t, "fpd", "secret", now-3600, req), req, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = interceptor(context.Background(), req, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the keys not granted are filtered out of the listed keys
+	listReq := &proto.ListKeysRequest{}
+	res, err := interceptor(signedCtx(t, "fpd", "secret", now, listReq), listReq, info, handler)
+	require.NoError(t, err)
+	require.Len(t, res.(*proto.ListKeysResponse).Keys, 1)
+	require.Equal(t, grantedPk, res.(*proto.ListKeysResponse).Keys[0].Pk)
+
+	// a replayed request is refused, while the same request with another
+	// nonce is accepted
+	replayedCtx := signedCtxWithNonce(t, "fpd", "secret", now, "nonce", req)
+	_, err = interceptor(replayedCtx, req, info, handler)
+	require.NoError(t, err)
+	_, err = interceptor(replayedCtx, req, info, handler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = interceptor(signedCtxWithNonce(t, "fpd", "secret", now, "another-nonce", req), req, info, handler)
+	require.NoError(t, err)
+
+	// the creation of keys is only granted explicitly
+	createReq := &proto.CreateKeyRequest{Name: "key"}
+	_, err = interceptor(signedCtx(t, "fpd", "secret", now, createReq), createReq, info, handler)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = interceptor(signedCtx(t, "admin", "admin-secret", now, createReq), createReq, info, handler)
+	require.NoError(t, err)
+
+	allKeys, err := auth.NewAuthenticator(
+		map[string]string{"admin": "admin-secret"},
+		map[string]string{"admin": auth.AllKeys},
+	)
+	require.NoError(t, err)
+	_, err = allKeys.UnaryServerInterceptor()(signedCtx(t, "admin", "admin-secret", now, createReq), createReq, info, handler)
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	minConnectTimeout = 5 * time.Second
+	keepAliveTimeout  = 20 * time.Second
+)
+
+// connPool spreads the calls over a pool of connections to the EOTS
+// manager daemon in a round-robin manner
+type connPool struct {
+	conns []*grpc.ClientConn
+	next  uint32
+}
+
+var _ grpc.ClientConnInterface = &connPool{}
+
+func newConnPool(remoteAddr string, size int, dialOpts []grpc.DialOption) (*connPool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("the pool size should be positive, got %d", size)
+	}
+
+	p := &connPool{conns: make([]*grpc.ClientConn, 0, size)}
+	for i := 0; i < size; i++ {
+		conn, err := grpc.Dial(remoteAddr, dialOpts...)
+		if err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+		p.conns = append(p.conns, conn)
+	}
+
+	return p, nil
+}
+
+func (p *connPool) pick() *grpc.ClientConn {
+	n := atomic.AddUint32(&p.next, 1)
+	return p.conns[n%uint32(len(p.conns))]
+}
+
+func (p *connPool) Invoke(ctx context.Context, method string, args interface{}, reply interface{}, opts ...grpc.CallOption) error {
+	return p.pick().Invoke(ctx, method, args, reply, opts...)
+}
+
+func (p *connPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return p.pick().NewStream(ctx, desc, method, opts...)
+}
+
+func (p *connPool) Close() error {
+	var errs []error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func latencyInterceptor(observe func(method, code string, latency time.Duration)) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observe(method, status.Code(err).String(), time.Since(start))
+
+		return err
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+)
+
+type pingServer struct {
+	proto.UnimplementedEOTSManagerServer
+}
+
+func (s *pingServer) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
+	return &proto.PingResponse{}, nil
+}
+
+func startPingServer(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	proto.RegisterEOTSManagerServer(server, &pingServer{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestConnPool(t *testing.T) {
+	addr := startPingServer(t)
+
+	t.Run("the calls are spread over the connections", func(t *testing.T) {
+		p, err := newConnPool(addr, 3, []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())})
+		require.NoError(t, err)
+		defer p.Close()
+
+		picked := make(map[*grpc.ClientConn]int)
+		for i := 0; i < 6; i++ {
+			picked[p.pick()]++
+		}
+		require.Len(t, picked, 3)
+		for _, n := range picked {
+			require.Equal(t, 2, n)
+		}
+	})
+
+	t.Run("an empty pool is rejected", func(t *testing.T) {
+		_, err := newConnPool(addr, 0, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestEOTSManagerGRpcClientPool(t *testing.T) {
+	addr := startPingServer(t)
+
+	var (
+		mu      sync.Mutex
+		methods []string
+		codes   []string
+	)
+	observe := func(method, code string, latency time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		methods = append(methods, method)
+		codes = append(codes, code)
+	}
+
+	c, err := NewEOTSManagerGRpcClient(addr, WithPoolSize(2), WithKeepAlive(time.Minute), WithLatencyObserver(observe))
+	require.NoError(t, err)
+	require.Len(t, c.conns.conns, 2)
+
+	require.NoError(t, c.Ping())
+	require.NoError(t, c.Close())
+
+	// the ping of the client on creation is observed as well
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"/proto.EOTSManager/Ping", "/proto.EOTSManager/Ping"}, methods)
+	require.Equal(t, []string{"OK", "OK"}, codes)
+}
@@ -3,13 +3,17 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/auth"
 	"github.com/babylonchain/finality-provider/eotsmanager/proto"
 	"github.com/babylonchain/finality-provider/eotsmanager/types"
 )
@@ -18,21 +22,116 @@ var _ eotsmanager.EOTSManager = &EOTSManagerGRpcClient{}
 
 type EOTSManagerGRpcClient struct {
 	client proto.EOTSManagerClient
-	conn   *grpc.ClientConn
+	conns  *connPool
 }
 
-func NewEOTSManagerGRpcClient(remoteAddr string) (*EOTSManagerGRpcClient, error) {
-	conn, err := grpc.Dial(remoteAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+type options struct {
+	dialOpts       []grpc.DialOption
+	poolSize       int
+	keepAlive      time.Duration
+	maxBackoff     time.Duration
+	connectTimeout time.Duration
+}
+
+// Option configures the connections to the EOTS manager daemon
+type Option func(opts *options)
+
+// WithAuth signs the requests with the HMAC secret of the client
+func WithAuth(clientID, secret string) Option {
+	return func(opts *options) {
+		opts.dialOpts = append(opts.dialOpts, grpc.WithChainUnaryInterceptor(auth.UnaryClientInterceptor(clientID, secret)))
+	}
+}
+
+// WithPoolSize spreads the requests over the given number of connections
+func WithPoolSize(size int) Option {
+	return func(opts *options) {
+		opts.poolSize = size
+	}
+}
+
+// WithKeepAlive pings the EOTS manager daemon after the given interval of
+// inactivity to detect the broken connections, which is disabled if the
+// interval is 0
+func WithKeepAlive(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.keepAlive = interval
+	}
+}
+
+// WithMaxBackoff bounds the exponential backoff between the attempts to
+// reconnect to the EOTS manager daemon
+func WithMaxBackoff(maxBackoff time.Duration) Option {
+	return func(opts *options) {
+		opts.maxBackoff = maxBackoff
+	}
+}
+
+// WithConnectTimeout bounds the time of each attempt to connect to the EOTS
+// manager daemon
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(opts *options) {
+		opts.connectTimeout = timeout
+	}
+}
+
+// WithMaxMsgSize sets the maximum sizes in bytes of the messages received
+// from and sent to the EOTS manager daemon
+func WithMaxMsgSize(maxRecv, maxSend int) Option {
+	return func(opts *options) {
+		opts.dialOpts = append(opts.dialOpts, grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(maxRecv),
+			grpc.MaxCallSendMsgSize(maxSend),
+		))
+	}
+}
+
+// WithLatencyObserver passes the latency of each call with its method and
+// gRPC status code to the observer
+func WithLatencyObserver(observe func(method, code string, latency time.Duration)) Option {
+	return func(opts *options) {
+		opts.dialOpts = append(opts.dialOpts, grpc.WithChainUnaryInterceptor(latencyInterceptor(observe)))
+	}
+}
+
+func NewEOTSManagerGRpcClient(remoteAddr string, opts ...Option) (*EOTSManagerGRpcClient, error) {
+	o := &options{
+		dialOpts:       []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+		poolSize:       1,
+		maxBackoff:     backoff.DefaultConfig.MaxDelay,
+		connectTimeout: minConnectTimeout,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// the connections reconnect on their own, backing off exponentially
+	backoffCfg := backoff.DefaultConfig
+	backoffCfg.MaxDelay = o.maxBackoff
+	o.dialOpts = append(o.dialOpts, grpc.WithConnectParams(grpc.ConnectParams{
+		Backoff:           backoffCfg,
+		MinConnectTimeout: o.connectTimeout,
+	}))
+	if o.keepAlive > 0 {
+		o.dialOpts = append(o.dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.keepAlive,
+			Timeout:             keepAliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	conns, err := newConnPool(remoteAddr, o.poolSize, o.dialOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build gRPC connection to %s: %w", remoteAddr, err)
 	}
 
 	gClient := &EOTSManagerGRpcClient{
-		client: proto.NewEOTSManagerClient(conn),
-		conn:   conn,
+		client: proto.NewEOTSManagerClient(conns),
+		conns:  conns,
 	}
 
 	if err := gClient.Ping(); err != nil {
+		_ = conns.Close()
 		return nil, fmt.Errorf("the EOTS manager server is not responding: %w", err)
 	}
 
@@ -83,6 +182,20 @@ func (c *EOTSManagerGRpcClient) CreateRandomnessPairList(uid, chainID []byte, st
 	return pubRandFieldValList, nil
 }
 
+func (c *EOTSManagerGRpcClient) SeedRandomness(uid, chainID []byte, startHeight uint64) error {
+	req := &proto.SeedRandomnessRequest{Uid: uid, ChainId: chainID, StartHeight: startHeight}
+	_, err := c.client.SeedRandomness(context.Background(), req)
+
+	return err
+}
+
+func (c *EOTSManagerGRpcClient) ShredRandomness(uid, chainID []byte, belowHeight uint64) error {
+	req := &proto.ShredRandomnessRequest{Uid: uid, ChainId: chainID, BelowHeight: belowHeight}
+	_, err := c.client.ShredRandomness(context.Background(), req)
+
+	return err
+}
+
 func (c *EOTSManagerGRpcClient) KeyRecord(uid []byte, passphrase string) (*types.KeyRecord, error) {
 	req := &proto.KeyRecordRequest{Uid: uid, Passphrase: passphrase}
 
@@ -133,6 +246,34 @@ func (c *EOTSManagerGRpcClient) SignSchnorrSig(uid, msg []byte, passphrase strin
 	return sig, nil
 }
 
+func (c *EOTSManagerGRpcClient) ListKeys() ([]*types.KeyInfo, error) {
+	res, err := c.client.ListKeys(context.Background(), &proto.ListKeysRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*types.KeyInfo, 0, len(res.Keys))
+	for _, k := range res.Keys {
+		keys = append(keys, &types.KeyInfo{
+			Name:          k.Name,
+			PubKey:        k.Pk,
+			CreatedAt:     timeOrZero(k.CreatedAt),
+			LastUsedAt:    timeOrZero(k.LastUsedAt),
+			NumSignatures: k.NumSignatures,
+		})
+	}
+
+	return keys, nil
+}
+
+func timeOrZero(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(unix, 0)
+}
+
 func (c *EOTSManagerGRpcClient) Close() error {
-	return c.conn.Close()
+	return c.conns.Close()
 }
@@ -0,0 +1,202 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"cosmossdk.io/math"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+	"github.com/babylonchain/finality-provider/log"
+)
+
+// CreateKeysCmd creates keys in bulk for provisioning a fleet of finality
+// providers. The keys are either created in the local keyring, or through
+// fpd, which also creates the matching finality providers in CREATED state
+var CreateKeysCmd = cli.Command{
+	Name:  "create-keys",
+	Usage: "Create a number of EOTS keys named with a prefix and a sequence number.",
+	UsageText: fmt.Sprintf("create-keys --%s [N] --%s [name]",
+		countFlag, prefixFlag),
+	Description: fmt.Sprintf(`Creates the keys <prefix>-0 to <prefix>-(N-1) in the local keyring,
+	which requires the EOTS manager daemon to be stopped. If --%s is set,
+	the keys are created through fpd instead, which in turn creates them in the running
+	EOTS manager and stores a finality provider in CREATED state for each of them.`,
+		fpdDaemonAddressFlag),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "Path to the keyring directory",
+			Value: config.DefaultEOTSDir,
+		},
+		cli.UintFlag{
+			Name:     countFlag,
+			Usage:    "The number of keys to be created",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     prefixFlag,
+			Usage:    "The prefix of the names of the keys to be created",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to encrypt the keys",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  hdPathFlag,
+			Usage: "The hd path used to derive the private keys",
+			Value: defaultHdPath,
+		},
+		cli.StringFlag{
+			Name:  keyringBackendFlag,
+			Usage: "The backend of the keyring",
+			Value: defaultKeyringBackend,
+		},
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd, through which the finality providers are created along with the keys",
+		},
+		cli.StringFlag{
+			Name:  chainIdFlag,
+			Usage: "The identifier of the consumer chain of the finality providers (only used with fpd)",
+		},
+		cli.StringFlag{
+			Name:  commissionRateFlag,
+			Usage: "The commission rate of the finality providers, e.g., 0.05 (only used with fpd)",
+			Value: defaultCommissionRate,
+		},
+	},
+	Action: createKeys,
+}
+
+func createKeys(ctx *cli.Context) error {
+	count := ctx.Uint(countFlag)
+	if count == 0 {
+		return fmt.Errorf("the number of keys should be positive")
+	}
+	prefix := ctx.String(prefixFlag)
+
+	keyNames := make([]string, 0, count)
+	for i := uint(0); i < count; i++ {
+		keyNames = append(keyNames, fmt.Sprintf("%s-%d", prefix, i))
+	}
+
+	var (
+		keys []KeyOutput
+		err  error
+	)
+	if ctx.String(fpdDaemonAddressFlag) != "" {
+		keys, err = createKeysWithFpd(ctx, keyNames)
+	} else {
+		keys, err = createKeysLocally(ctx, keyNames)
+	}
+	// print the created keys even if some failed, so that their mnemonics
+	// are not lost
+	if len(keys) > 0 {
+		jsonBytes, jsonErr := json.MarshalIndent(keys, "", "    ")
+		if jsonErr != nil {
+			return fmt.Errorf("failed to encode the created keys: %w", jsonErr)
+		}
+		fmt.Printf("%s\n", jsonBytes)
+	}
+
+	return err
+}
+
+func createKeysLocally(ctx *cli.Context, keyNames []string) ([]KeyOutput, error) {
+	homePath, err := getHomeFlag(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load home flag: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(homePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config at %s: %w", homePath, err)
+	}
+
+	logger, err := log.NewRootLoggerWithFile(config.LogFile(homePath), cfg.LogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the logger")
+	}
+
+	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create db backend: %w", err)
+	}
+	defer dbBackend.Close()
+
+	eotsManager, err := eotsmanager.NewLocalEOTSManager(homePath, ctx.String(keyringBackendFlag), dbBackend, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EOTS manager: %w", err)
+	}
+	eotsManager.SetKeyringSecurity(cfg.Keyring)
+
+	keys := make([]KeyOutput, 0, len(keyNames))
+	for _, keyName := range keyNames {
+		mnemonic, err := eotsmanager.NewMnemonic()
+		if err != nil {
+			return keys, err
+		}
+		eotsPk, err := eotsManager.CreateKeyWithMnemonic(keyName, ctx.String(passphraseFlag), ctx.String(hdPathFlag), mnemonic)
+		if err != nil {
+			return keys, fmt.Errorf("failed to create key %s: %w", keyName, err)
+		}
+
+		keys = append(keys, KeyOutput{
+			Name:      keyName,
+			PubKeyHex: eotsPk.MarshalHex(),
+			Mnemonic:  mnemonic,
+		})
+	}
+
+	return keys, nil
+}
+
+func createKeysWithFpd(ctx *cli.Context, keyNames []string) ([]KeyOutput, error) {
+	chainID := ctx.String(chainIdFlag)
+	if chainID == "" {
+		return nil, fmt.Errorf("the chain ID is required to create the finality providers")
+	}
+
+	commissionRate, err := math.LegacyNewDecFromStr(ctx.String(commissionRateFlag))
+	if err != nil {
+		return nil, fmt.Errorf("invalid commission rate: %w", err)
+	}
+
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	client, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to fpd at %s: %w", daemonAddress, err)
+	}
+	defer cleanUp()
+
+	keys := make([]KeyOutput, 0, len(keyNames))
+	for _, keyName := range keyNames {
+		res, err := client.CreateFinalityProvider(
+			context.Background(),
+			keyName,
+			chainID,
+			ctx.String(passphraseFlag),
+			ctx.String(hdPathFlag),
+			stakingtypes.Description{Moniker: keyName},
+			&commissionRate,
+		)
+		if err != nil {
+			return keys, fmt.Errorf("failed to create finality provider %s: %w", keyName, err)
+		}
+
+		keys = append(keys, KeyOutput{
+			Name:      keyName,
+			PubKeyHex: res.FinalityProvider.BtcPkHex,
+		})
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,60 @@
+package daemon_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dcli "github.com/babylonchain/finality-provider/eotsmanager/cmd/eotsd/daemon"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzCreateKeys tests creating the keys in bulk in the local keyring
+func FuzzCreateKeys(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		app := testApp()
+
+		hFlag := fmt.Sprintf("--home=%s", homeDir)
+		err := app.Run([]string{"eotsd", "init", hFlag})
+		require.NoError(t, err)
+
+		count := r.Intn(5) + 1
+		prefix := testutil.GenRandomHexStr(r, 10)
+		countFlag := fmt.Sprintf("--count=%d", count)
+		prefixFlag := fmt.Sprintf("--prefix=%s", prefix)
+		output := appRunWithOutput(r, t, app, []string{"eotsd", "create-keys", hFlag, countFlag, prefixFlag})
+
+		var keys []dcli.KeyOutput
+		err = json.Unmarshal([]byte(output), &keys)
+		require.NoError(t, err)
+		require.Len(t, keys, count)
+		pks := make(map[string]struct{}, count)
+		for i, key := range keys {
+			require.Equal(t, fmt.Sprintf("%s-%d", prefix, i), key.Name)
+			require.NotEmpty(t, key.Mnemonic)
+			pks[key.PubKeyHex] = struct{}{}
+		}
+		require.Len(t, pks, count)
+
+		// the keys are in the keyring, so they cannot be created again
+		err = app.Run([]string{"eotsd", "create-keys", hFlag, countFlag, prefixFlag})
+		require.Error(t, err)
+
+		// no key is created without a count
+		err = app.Run([]string{"eotsd", "create-keys", hFlag, "--count=0", prefixFlag})
+		require.Error(t, err)
+
+		// the finality providers cannot be created through fpd without the
+		// chain ID
+		err = app.Run([]string{"eotsd", "create-keys", hFlag, countFlag, prefixFlag, "--fpd-daemon-address=127.0.0.1:12581"})
+		require.ErrorContains(t, err, "chain ID")
+	})
+}
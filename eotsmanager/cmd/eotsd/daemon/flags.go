@@ -16,7 +16,15 @@ const (
 	keyringBackendFlag = "keyring-backend"
 	recoverFlag        = "recover"
 
+	// flags for bulk key creation
+	countFlag            = "count"
+	prefixFlag           = "prefix"
+	fpdDaemonAddressFlag = "fpd-daemon-address"
+	chainIdFlag          = "chain-id"
+	commissionRateFlag   = "commission"
+
 	defaultKeyringBackend = keyring.BackendTest
 	defaultHdPath         = ""
 	defaultPassphrase     = ""
+	defaultCommissionRate = "0.05"
 )
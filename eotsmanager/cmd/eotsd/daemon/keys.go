@@ -101,6 +101,7 @@ func addKey(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create EOTS manager: %w", err)
 	}
+	eotsManager.SetKeyringSecurity(cfg.Keyring)
 
 	eotsPk, mnemonic, err := createKey(ctx, eotsManager, keyName)
 	if err != nil {
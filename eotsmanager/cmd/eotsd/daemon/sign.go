@@ -162,6 +162,7 @@ func SignSchnorr(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create EOTS manager: %w", err)
 	}
+	eotsManager.SetKeyringSecurity(cfg.Keyring)
 
 	hashOfMsgToSign, err := hashFromFile(inputFilePath)
 	if err != nil {
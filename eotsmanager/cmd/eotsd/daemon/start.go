@@ -44,6 +44,14 @@ func startFn(ctx *cli.Context) error {
 		return fmt.Errorf("failed to load config at %s: %w", homePath, err)
 	}
 
+	// a second daemon on the same store would not see the signing records
+	// of the first one, so the database directory is locked
+	dbLock, err := util.LockDir(util.CleanAndExpandPath(cfg.DatabaseConfig.DBPath))
+	if err != nil {
+		return fmt.Errorf("failed to lock the database directory, is another eotsd running with the same home? %w", err)
+	}
+	defer dbLock.Release()
+
 	rpcListener := ctx.String(rpcListenerFlag)
 	if rpcListener != "" {
 		_, err := net.ResolveTCPAddr("tcp", rpcListener)
@@ -67,6 +75,8 @@ func startFn(ctx *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create EOTS manager: %w", err)
 	}
+	eotsManager.SetKeyringSecurity(cfg.Keyring)
+	eotsManager.SetAllowDoubleSign(cfg.AllowDoubleSign)
 
 	// Hook interceptor for os signals.
 	shutdownInterceptor, err := signal.Intercept()
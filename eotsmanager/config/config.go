@@ -11,6 +11,7 @@ import (
 	"github.com/jessevdk/go-flags"
 
 	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/profiling"
 	"github.com/babylonchain/finality-provider/util"
 )
 
@@ -40,7 +41,19 @@ type Config struct {
 	RpcListener    string          `long:"rpclistener" description:"the listener for RPC connections, e.g., 127.0.0.1:1234"`
 	Metrics        *metrics.Config `group:"metrics" namespace:"metrics"`
 
+	Profiling *profiling.Config `group:"profiling" namespace:"profiling"`
+
 	DatabaseConfig *DBConfig `group:"dbconfig" namespace:"dbconfig"`
+
+	Keyring *KeyringSecurityConfig `group:"keyring" namespace:"keyring"`
+
+	// AllowDoubleSign disables the refusal of signing different messages at
+	// the same height, which leaks the private key
+	AllowDoubleSign bool `long:"allowdoublesign" description:"Allow signing different messages at the same height, which leaks the EOTS private key; only meant for testing slashing"`
+
+	// AuthClients enables the authentication of the requests when non-empty
+	AuthClients map[string]string `long:"authclient" description:"The HMAC secret of a client allowed to call the daemon as client-id:secret, where the secret may be a secret URI; the requests are not authenticated if no client is set" secret:"true"`
+	AuthKeys    map[string]string `long:"authkeys" description:"The comma-separated hex EOTS public keys a client may use as client-id:pk1,pk2, or client-id:* for all the keys, and create to allow creating keys, which * does not imply; a client may use no key unless set"`
 }
 
 // LoadConfig initializes and parses the config using a config file and command
@@ -68,6 +81,10 @@ func LoadConfig(homePath string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := util.ResolveSecrets(&cfg); err != nil {
+		return nil, err
+	}
+
 	// Make sure everything we just loaded makes sense.
 	if err := cfg.Validate(); err != nil {
 		return nil, err
@@ -97,6 +114,28 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("invalid metrics config")
 	}
 
+	// the config files predating the profiling have no such group
+	if cfg.Profiling == nil {
+		cfg.Profiling = profiling.DefaultConfig()
+	}
+	if err := cfg.Profiling.Validate(); err != nil {
+		return err
+	}
+
+	// the config files predating the keyring security have no such group
+	if cfg.Keyring == nil {
+		cfg.Keyring = DefaultKeyringSecurityConfig()
+	}
+	if err := cfg.Keyring.Validate(); err != nil {
+		return fmt.Errorf("invalid keyring config: %w", err)
+	}
+
+	for clientID := range cfg.AuthKeys {
+		if _, ok := cfg.AuthClients[clientID]; !ok {
+			return fmt.Errorf("the keys are granted to the client %s with no secret", clientID)
+		}
+	}
+
 	return nil
 }
 
@@ -127,6 +166,8 @@ func DefaultConfigWithHomePath(homePath string) *Config {
 		DatabaseConfig: DefaultDBConfigWithHomePath(homePath),
 		RpcListener:    defaultRpcListener,
 		Metrics:        metrics.DefaultEotsConfig(),
+		Profiling:      profiling.DefaultConfig(),
+		Keyring:        DefaultKeyringSecurityConfig(),
 	}
 	if err := cfg.Validate(); err != nil {
 		panic(err)
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/util"
 )
 
 const (
@@ -67,5 +69,9 @@ func (db *DBConfig) DBConfigToBoltBackendConfig() *kvdb.BoltBackendConfig {
 }
 
 func (db *DBConfig) GetDbBackend() (kvdb.Backend, error) {
+	if err := util.CheckDBFileSize(db.DBPath, db.DBFileName); err != nil {
+		return nil, err
+	}
+
 	return kvdb.GetBoltBackend(db.DBConfigToBoltBackendConfig())
 }
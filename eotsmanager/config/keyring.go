@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	KDFNone     = "none"
+	KDFArgon2id = "argon2id"
+
+	defaultArgon2Time      = 3
+	defaultArgon2MemoryKiB = 64 * 1024
+	defaultArgon2Threads   = 4
+)
+
+// KeyringSecurityConfig configures the protection of the EOTS keys in the
+// file keyring, which are encrypted under the passphrases of the keys
+type KeyringSecurityConfig struct {
+	MinPassphraseLength uint32 `long:"minpassphraselength" description:"The minimum length of the passphrases of new keys in the file keyring, where 0 disables the check"`
+	MinCharClasses      uint32 `long:"mincharclasses" description:"The minimum number of character classes (lowercase, uppercase, digits, symbols) in the passphrases of new keys in the file keyring"`
+	KDF                 string `long:"kdf" description:"The KDF hardening the passphrases before they encrypt the file keyring; existing keyrings are migrated to argon2id on the first unlock" choice:"none" choice:"argon2id"`
+	Argon2Time          uint32 `long:"argon2time" description:"The number of passes of argon2id"`
+	Argon2MemoryKiB     uint32 `long:"argon2memory" description:"The memory of argon2id in KiB"`
+	Argon2Threads       uint8  `long:"argon2threads" description:"The number of threads of argon2id"`
+	MlockKeyCache       bool   `long:"mlockkeycache" description:"Lock the cached passphrases hardened by the KDF in memory so that they are never swapped to disk, which requires a sufficient limit of locked memory"`
+}
+
+func DefaultKeyringSecurityConfig() *KeyringSecurityConfig {
+	return &KeyringSecurityConfig{
+		KDF:             KDFNone,
+		Argon2Time:      defaultArgon2Time,
+		Argon2MemoryKiB: defaultArgon2MemoryKiB,
+		Argon2Threads:   defaultArgon2Threads,
+	}
+}
+
+func (cfg *KeyringSecurityConfig) Validate() error {
+	if cfg.MinCharClasses > 4 {
+		return fmt.Errorf("the minimum number of character classes %d exceeds 4", cfg.MinCharClasses)
+	}
+
+	switch cfg.KDF {
+	case "":
+		cfg.KDF = KDFNone
+	case KDFNone:
+	case KDFArgon2id:
+		if cfg.Argon2Time == 0 {
+			cfg.Argon2Time = defaultArgon2Time
+		}
+		if cfg.Argon2MemoryKiB == 0 {
+			cfg.Argon2MemoryKiB = defaultArgon2MemoryKiB
+		}
+		if cfg.Argon2Threads == 0 {
+			cfg.Argon2Threads = defaultArgon2Threads
+		}
+		// argon2 requires at least 8 KiB per thread
+		if cfg.Argon2MemoryKiB < 8*uint32(cfg.Argon2Threads) {
+			return fmt.Errorf("the argon2id memory %d KiB is less than 8 KiB per thread", cfg.Argon2MemoryKiB)
+		}
+	default:
+		return fmt.Errorf("unknown KDF %s", cfg.KDF)
+	}
+
+	return nil
+}
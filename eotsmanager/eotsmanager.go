@@ -18,11 +18,24 @@ type EOTSManager interface {
 	// It fails if the finality provider does not exist or a randomness pair has been created before
 	// or passPhrase is incorrect
 	// NOTE: the randomness is deterministically generated based on the EOTS key, chainID and
-	// block height
+	// block height, as well as the seed of the height if the randomness is seeded
 	CreateRandomnessPairList(uid []byte, chainID []byte, startHeight uint64, num uint32, passphrase string) ([]*btcec.FieldVal, error)
 
+	// SeedRandomness generates the randomness of the given chain from startHeight on
+	// with random seeds persisted in storage, so that it can be shredded once the
+	// heights are finalized. Only the first call takes effect, and startHeight must be
+	// above every height whose randomness has been committed
+	// It fails if the finality provider does not exist
+	SeedRandomness(uid []byte, chainID []byte, startHeight uint64) error
+
+	// ShredRandomness deletes the seeds of the randomness of the given chain below
+	// belowHeight, after which the randomness of these heights can no longer be
+	// derived, even with the EOTS key. The seeds are deleted in whole epochs
+	ShredRandomness(uid []byte, chainID []byte, belowHeight uint64) error
+
 	// KeyRecord returns the finality provider record
 	// It fails if the finality provider does not exist or passPhrase is incorrect
+	// The caller should zero the private key in the record once it is used
 	KeyRecord(uid []byte, passphrase string) (*types.KeyRecord, error)
 
 	// SignEOTS signs an EOTS using the private key of the finality provider and the corresponding
@@ -36,5 +49,10 @@ type EOTSManager interface {
 	// or passPhrase is incorrect
 	SignSchnorrSig(uid []byte, msg []byte, passphrase string) (*schnorr.Signature, error)
 
+	// ListKeys returns the name and the public key of every EOTS key that
+	// exists in both the store and the keyring
+	// NOTE: no private key material is returned
+	ListKeys() ([]*types.KeyInfo, error)
+
 	Close() error
 }
@@ -0,0 +1,286 @@
+package eotsmanager
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/argon2"
+
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+	"github.com/babylonchain/finality-provider/util"
+	"github.com/babylonchain/finality-provider/util/securemem"
+)
+
+const (
+	// keyringFileDirName is the directory of the file keyring under the
+	// home directory, which is determined by the cosmos-sdk keyring
+	keyringFileDirName = "keyring-file"
+	// keyringKDFFileName holds the KDF parameters of a keyring encrypted
+	// under the hardened passphrases
+	keyringKDFFileName     = "keyring-kdf.json"
+	keyringMigrationDir    = "keyring-migration"
+	keyringLegacyDirSuffix = ".legacy"
+
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// keyringKDF is the KDF hardening the passphrases before they encrypt the
+// file keyring
+type keyringKDF struct {
+	KDF       string `json:"kdf"`
+	Salt      []byte `json:"salt"`
+	Time      uint32 `json:"time"`
+	MemoryKiB uint32 `json:"memory_kib"`
+	Threads   uint8  `json:"threads"`
+}
+
+// derive hardens the passphrase into a buffer, which is locked in memory if
+// lock is set. The buffer should be destroyed once it is no longer needed
+func (k *keyringKDF) derive(passphrase string, lock bool) (*securemem.Buffer, error) {
+	passphraseBytes := []byte(passphrase)
+	key := argon2.IDKey(passphraseBytes, k.Salt, k.Time, k.MemoryKiB, k.Threads, argon2KeyLen)
+	securemem.Zero(passphraseBytes)
+	defer securemem.Zero(key)
+
+	buf, err := securemem.NewBuffer(hex.EncodedLen(len(key)), lock)
+	if err != nil {
+		return nil, err
+	}
+	hex.Encode(buf.Bytes(), key)
+
+	return buf, nil
+}
+
+// loadKeyringKDF loads the KDF of the keyring, which is nil if the keyring
+// is encrypted under the passphrases as is
+func loadKeyringKDF(homeDir string) (*keyringKDF, error) {
+	kdfFile := filepath.Join(homeDir, keyringKDFFileName)
+	if !util.FileExists(kdfFile) {
+		return nil, nil
+	}
+
+	bz, err := os.ReadFile(kdfFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the keyring KDF: %w", err)
+	}
+	var kdf keyringKDF
+	if err := json.Unmarshal(bz, &kdf); err != nil {
+		return nil, fmt.Errorf("invalid keyring KDF %s: %w", kdfFile, err)
+	}
+	if kdf.KDF != config.KDFArgon2id {
+		return nil, fmt.Errorf("unsupported keyring KDF %s", kdf.KDF)
+	}
+
+	return &kdf, nil
+}
+
+func saveKeyringKDF(homeDir string, kdf *keyringKDF) error {
+	bz, err := json.Marshal(kdf)
+	if err != nil {
+		return err
+	}
+
+	kdfFile := filepath.Join(homeDir, keyringKDFFileName)
+	tmpFile := kdfFile + ".tmp"
+	if err := os.WriteFile(tmpFile, bz, 0600); err != nil {
+		return fmt.Errorf("failed to write the keyring KDF: %w", err)
+	}
+
+	return os.Rename(tmpFile, kdfFile)
+}
+
+// SetKeyringSecurity sets the passphrase policy and the KDF of the file
+// keyring. The keyring is migrated to the configured KDF on the first unlock
+func (lm *LocalEOTSManager) SetKeyringSecurity(cfg *config.KeyringSecurityConfig) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	lm.security = cfg
+}
+
+// checkPassphrase checks the passphrase of a new key against the policy,
+// which only applies to the file keyring as the others ignore passphrases
+func (lm *LocalEOTSManager) checkPassphrase(passphrase string) error {
+	lm.mu.Lock()
+	cfg := lm.security
+	lm.mu.Unlock()
+
+	if lm.backend != keyring.BackendFile || cfg == nil {
+		return nil
+	}
+
+	if uint32(len([]rune(passphrase))) < cfg.MinPassphraseLength {
+		return fmt.Errorf("%w: the passphrase should have at least %d characters",
+			eotstypes.ErrWeakPassphrase, cfg.MinPassphraseLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range passphrase {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	var classes uint32
+	for _, has := range []bool{lower, upper, digit, symbol} {
+		if has {
+			classes++
+		}
+	}
+	if classes < cfg.MinCharClasses {
+		return fmt.Errorf("%w: the passphrase should mix at least %d of lowercase letters, "+
+			"uppercase letters, digits, and symbols", eotstypes.ErrWeakPassphrase, cfg.MinCharClasses)
+	}
+
+	return nil
+}
+
+// keyringPassphrase returns the passphrase encrypting the file keyring for
+// the given passphrase, which is hardened by the KDF of the keyring
+func (lm *LocalEOTSManager) keyringPassphrase(passphrase string) (string, error) {
+	if lm.backend != keyring.BackendFile {
+		return passphrase, nil
+	}
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	if lm.kdf == nil {
+		if lm.security == nil || lm.security.KDF != config.KDFArgon2id {
+			return passphrase, nil
+		}
+		if err := lm.upgradeKeyringKDF(passphrase); err != nil {
+			return "", err
+		}
+	}
+
+	// the derivation is expensive by design, so the derived passphrases
+	// are cached by the digests of the passphrases
+	digest := sha256.Sum256([]byte(passphrase))
+	if derived, ok := lm.derived[digest]; ok {
+		return string(derived.Bytes()), nil
+	}
+	derived, err := lm.kdf.derive(passphrase, lm.security != nil && lm.security.MlockKeyCache)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive the keyring passphrase: %w", err)
+	}
+	lm.derived[digest] = derived
+
+	return string(derived.Bytes()), nil
+}
+
+// wipeKeyCache wipes the cached passphrases derived by the KDF
+func (lm *LocalEOTSManager) wipeKeyCache() {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	for digest, derived := range lm.derived {
+		derived.Destroy()
+		delete(lm.derived, digest)
+	}
+}
+
+// upgradeKeyringKDF hardens the passphrase of the file keyring with the
+// configured KDF, re-encrypting the existing keys under the hardened
+// passphrase. The legacy keyring is kept as a backup
+func (lm *LocalEOTSManager) upgradeKeyringKDF(passphrase string) error {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	kdf := &keyringKDF{
+		KDF:       config.KDFArgon2id,
+		Salt:      salt,
+		Time:      lm.security.Argon2Time,
+		MemoryKiB: lm.security.Argon2MemoryKiB,
+		Threads:   lm.security.Argon2Threads,
+	}
+
+	fileDir := filepath.Join(lm.homeDir, keyringFileDirName)
+	if !util.FileExists(filepath.Join(fileDir, "keyhash")) {
+		// the keyring is not created yet
+		if err := saveKeyringKDF(lm.homeDir, kdf); err != nil {
+			return err
+		}
+		lm.kdf = kdf
+		return nil
+	}
+
+	lm.input.Reset(passphrase)
+	records, err := lm.kr.List()
+	if err != nil {
+		return fmt.Errorf("failed to unlock the keyring to migrate it to %s: %w", kdf.KDF, err)
+	}
+
+	derivedBuf, err := kdf.derive(passphrase, false)
+	if err != nil {
+		return err
+	}
+	defer derivedBuf.Destroy()
+	derived := string(derivedBuf.Bytes())
+	migrationDir := filepath.Join(lm.homeDir, keyringMigrationDir)
+	if err := os.RemoveAll(migrationDir); err != nil {
+		return err
+	}
+	migrationInput := strings.NewReader("")
+	migratedKr, err := initKeyring(migrationDir, lm.backend, migrationInput)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		armor, err := lm.kr.ExportPrivKeyArmor(r.Name, derived)
+		if err != nil {
+			return fmt.Errorf("failed to export the key %s: %w", r.Name, err)
+		}
+		migrationInput.Reset(derived + "\n" + derived)
+		if err := migratedKr.ImportPrivKey(r.Name, armor, derived); err != nil {
+			return fmt.Errorf("failed to re-encrypt the key %s: %w", r.Name, err)
+		}
+	}
+
+	legacyDir := fileDir + keyringLegacyDirSuffix
+	if err := os.Rename(fileDir, legacyDir); err != nil {
+		return fmt.Errorf("failed to back up the legacy keyring: %w", err)
+	}
+	if err := os.Rename(filepath.Join(migrationDir, keyringFileDirName), fileDir); err != nil {
+		return fmt.Errorf("failed to replace the legacy keyring, which is backed up at %s: %w", legacyDir, err)
+	}
+	if err := saveKeyringKDF(lm.homeDir, kdf); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(migrationDir); err != nil {
+		return err
+	}
+
+	kr, err := initKeyring(lm.homeDir, lm.backend, lm.input)
+	if err != nil {
+		return err
+	}
+	lm.kr = kr
+	lm.kdf = kdf
+
+	lm.logger.Info("migrated the keyring to the hardened passphrase",
+		zap.String("kdf", kdf.KDF),
+		zap.Int("num_keys", len(records)),
+		zap.String("legacy_backup", legacyDir),
+	)
+
+	return nil
+}
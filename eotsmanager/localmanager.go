@@ -1,9 +1,13 @@
 package eotsmanager
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/babylonchain/finality-provider/metrics"
 
@@ -18,9 +22,11 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/babylonchain/finality-provider/codec"
+	"github.com/babylonchain/finality-provider/eotsmanager/config"
 	"github.com/babylonchain/finality-provider/eotsmanager/randgenerator"
 	"github.com/babylonchain/finality-provider/eotsmanager/store"
 	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+	"github.com/babylonchain/finality-provider/util/securemem"
 )
 
 const (
@@ -37,6 +43,19 @@ type LocalEOTSManager struct {
 	// input is to send passphrase to kr
 	input   *strings.Reader
 	metrics *metrics.EotsMetrics
+	homeDir string
+	backend string
+
+	mu       sync.Mutex
+	security *config.KeyringSecurityConfig
+	// kdf is the KDF of the file keyring, which is nil if the keyring is
+	// encrypted under the passphrases as is
+	kdf     *keyringKDF
+	derived map[[sha256.Size]byte]*securemem.Buffer
+
+	// allowDoubleSign disables the refusal of different messages at the
+	// same height, which is only meant for testing slashing
+	allowDoubleSign bool
 }
 
 func NewLocalEOTSManager(homeDir, keyringBackend string, dbbackend kvdb.Backend, logger *zap.Logger) (*LocalEOTSManager, error) {
@@ -52,6 +71,11 @@ func NewLocalEOTSManager(homeDir, keyringBackend string, dbbackend kvdb.Backend,
 		return nil, fmt.Errorf("failed to initialize keyring: %w", err)
 	}
 
+	kdf, err := loadKeyringKDF(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
 	eotsMetrics := metrics.NewEotsMetrics()
 
 	return &LocalEOTSManager{
@@ -60,6 +84,10 @@ func NewLocalEOTSManager(homeDir, keyringBackend string, dbbackend kvdb.Backend,
 		logger:  logger,
 		input:   inputReader,
 		metrics: eotsMetrics,
+		homeDir: homeDir,
+		backend: keyringBackend,
+		kdf:     kdf,
+		derived: make(map[[sha256.Size]byte]*securemem.Buffer),
 	}, nil
 }
 
@@ -107,6 +135,15 @@ func (lm *LocalEOTSManager) CreateKeyWithMnemonic(name, passphrase, hdPath, mnem
 		return nil, eotstypes.ErrFinalityProviderAlreadyExisted
 	}
 
+	if err := lm.checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	krPassphrase, err := lm.keyringPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
 	keyringAlgos, _ := lm.kr.SupportedAlgorithms()
 	algo, err := keyring.NewSigningAlgoFromString(secp256k1Type, keyringAlgos)
 	if err != nil {
@@ -116,7 +153,7 @@ func (lm *LocalEOTSManager) CreateKeyWithMnemonic(name, passphrase, hdPath, mnem
 	// we need to repeat the passphrase to mock the re-entry
 	// as when creating an account, passphrase will be asked twice
 	// by the keyring
-	lm.input.Reset(passphrase + "\n" + passphrase)
+	lm.input.Reset(krPassphrase + "\n" + krPassphrase)
 	record, err := lm.kr.NewAccount(name, mnemonic, passphrase, hdPath, algo)
 	if err != nil {
 		return nil, err
@@ -183,22 +220,75 @@ func (lm *LocalEOTSManager) CreateRandomnessPairList(fpPk []byte, chainID []byte
 	return prList, nil
 }
 
+func (lm *LocalEOTSManager) SeedRandomness(fpPk []byte, chainID []byte, startHeight uint64) error {
+	if _, err := lm.es.GetEOTSKeyName(fpPk); err != nil {
+		return fmt.Errorf("failed to get EOTS key name: %w", err)
+	}
+
+	return lm.es.StartRandSeeds(fpPk, chainID, startHeight)
+}
+
+func (lm *LocalEOTSManager) ShredRandomness(fpPk []byte, chainID []byte, belowHeight uint64) error {
+	shredded, err := lm.es.ShredRandSeeds(fpPk, chainID, belowHeight)
+	if err != nil {
+		return err
+	}
+
+	if shredded > 0 {
+		lm.logger.Debug("shredded the randomness seeds",
+			zap.String("pk", hex.EncodeToString(fpPk)),
+			zap.Uint64("below_height", belowHeight),
+			zap.Int("num_seeds", shredded),
+		)
+	}
+
+	return nil
+}
+
 func (lm *LocalEOTSManager) SignEOTS(fpPk []byte, chainID []byte, msg []byte, height uint64, passphrase string) (*btcec.ModNScalar, error) {
 	privRand, _, err := lm.getRandomnessPair(fpPk, chainID, height, passphrase)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get private randomness: %w", err)
 	}
 
+	defer privRand.Zero()
+
 	privKey, err := lm.getEOTSPrivKey(fpPk, passphrase)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get EOTS private key: %w", err)
 	}
+	defer privKey.Zero()
+
+	// the signature is recorded before it is produced, so that a crash in
+	// between cannot let a different message be signed at the height
+	if !lm.allowDoubleSign {
+		if err := lm.es.SaveSignRecord(fpPk, chainID, height, msg); err != nil {
+			lm.logger.Error("refused to sign the EOTS signature",
+				zap.String("pk", hex.EncodeToString(fpPk)),
+				zap.Uint64("height", height),
+				zap.Error(err),
+			)
+			return nil, err
+		}
+	}
+
+	sig, err := eots.Sign(privKey, privRand, msg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Update metrics
 	lm.metrics.IncrementEotsFpTotalEotsSignCounter(hex.EncodeToString(fpPk))
 	lm.metrics.SetEotsFpLastEotsSignHeight(hex.EncodeToString(fpPk), float64(height))
+	lm.recordKeyUse(fpPk)
+
+	return sig, nil
+}
 
-	return eots.Sign(privKey, privRand, msg)
+// SetAllowDoubleSign allows signing different messages at the same height,
+// which leaks the private key and is only meant for testing slashing
+func (lm *LocalEOTSManager) SetAllowDoubleSign(allow bool) {
+	lm.allowDoubleSign = allow
 }
 
 func (lm *LocalEOTSManager) SignSchnorrSig(fpPk []byte, msg []byte, passphrase string) (*schnorr.Signature, error) {
@@ -206,19 +296,43 @@ func (lm *LocalEOTSManager) SignSchnorrSig(fpPk []byte, msg []byte, passphrase s
 	if err != nil {
 		return nil, fmt.Errorf("failed to get EOTS private key: %w", err)
 	}
+	defer privKey.Zero()
 
 	return lm.signSchnorrSigFromPrivKey(privKey, fpPk, msg)
 }
 
 // signSchnorrSigFromPrivKey signs a Schnorr signature using the private key and updates metrics by the fpPk
 func (lm *LocalEOTSManager) signSchnorrSigFromPrivKey(privKey *btcec.PrivateKey, fpPk []byte, msg []byte) (*schnorr.Signature, error) {
+	sig, err := schnorr.Sign(privKey, msg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Update metrics
 	lm.metrics.IncrementEotsFpTotalSchnorrSignCounter(hex.EncodeToString(fpPk))
-	return schnorr.Sign(privKey, msg)
+	lm.recordKeyUse(fpPk)
+
+	return sig, nil
+}
+
+// recordKeyUse records the usage of the key, where a failure does not fail
+// the signing as the usage is only informative
+func (lm *LocalEOTSManager) recordKeyUse(fpPk []byte) {
+	if err := lm.es.RecordKeyUse(fpPk, time.Now()); err != nil {
+		lm.logger.Warn("failed to record the usage of the EOTS key",
+			zap.String("pk", hex.EncodeToString(fpPk)),
+			zap.Error(err),
+		)
+	}
 }
 
 func (lm *LocalEOTSManager) SignSchnorrSigFromKeyname(keyName, passphrase string, msg []byte) (*schnorr.Signature, *bbntypes.BIP340PubKey, error) {
-	lm.input.Reset(passphrase)
+	krPassphrase, err := lm.keyringPassphrase(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lm.input.Reset(krPassphrase)
 	k, err := lm.kr.Key(keyName)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load keyring record for key %s: %w", keyName, err)
@@ -233,6 +347,7 @@ func (lm *LocalEOTSManager) SignSchnorrSigFromKeyname(keyName, passphrase string
 	if err != nil {
 		return nil, nil, err
 	}
+	defer privKey.Zero()
 
 	signature, err := lm.signSchnorrSigFromPrivKey(privKey, *eotsPk, msg)
 	if err != nil {
@@ -241,17 +356,81 @@ func (lm *LocalEOTSManager) SignSchnorrSigFromKeyname(keyName, passphrase string
 	return signature, eotsPk, nil
 }
 
+func (lm *LocalEOTSManager) ListKeys() ([]*eotstypes.KeyInfo, error) {
+	keyNames, err := lm.es.GetAllEOTSKeyNames()
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := lm.es.GetAllKeyUsages()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*eotstypes.KeyInfo, 0, len(keyNames))
+	for pkHex, name := range keyNames {
+		if !lm.keyExists(name) {
+			lm.logger.Warn(
+				"the EOTS key is recorded in the store but missing in the keyring",
+				zap.String("key name", name),
+				zap.String("pk", pkHex),
+			)
+			continue
+		}
+
+		pk, err := hex.DecodeString(pkHex)
+		if err != nil {
+			return nil, err
+		}
+
+		keyInfo := &eotstypes.KeyInfo{
+			Name:   name,
+			PubKey: pk,
+		}
+		if usage, ok := usages[pkHex]; ok {
+			keyInfo.CreatedAt = usage.CreatedAt
+			keyInfo.LastUsedAt = usage.LastUsedAt
+			keyInfo.NumSignatures = usage.NumSignatures
+		}
+		keys = append(keys, keyInfo)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	return keys, nil
+}
+
 func (lm *LocalEOTSManager) Close() error {
+	lm.wipeKeyCache()
+
 	return nil
 }
 
-// getRandomnessPair returns a randomness pair generated based on the given finality provider key, chainID and height
+// getRandomnessPair returns a randomness pair generated based on the given finality provider key, chainID and height,
+// which is also derived from the seed of the height if the randomness is seeded. It returns ErrRandomnessShredded
+// if the seed of the height has been shredded
 func (lm *LocalEOTSManager) getRandomnessPair(fpPk []byte, chainID []byte, height uint64, passphrase string) (*eots.PrivateRand, *eots.PublicRand, error) {
 	record, err := lm.KeyRecord(fpPk, passphrase)
 	if err != nil {
 		return nil, nil, err
 	}
-	privRand, pubRand := randgenerator.GenerateRandomness(record.PrivKey.Serialize(), chainID, height)
+	privKeyBytes := record.PrivKey.Serialize()
+	record.PrivKey.Zero()
+	defer securemem.Zero(privKeyBytes)
+
+	seed, err := lm.es.GetRandSeed(fpPk, chainID, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if seed == nil {
+		privRand, pubRand := randgenerator.GenerateRandomness(privKeyBytes, chainID, height)
+		return privRand, pubRand, nil
+	}
+	defer securemem.Zero(seed)
+
+	privRand, pubRand := randgenerator.GenerateRandomnessWithSeed(privKeyBytes, seed, chainID, height)
 	return privRand, pubRand, nil
 }
 
@@ -278,7 +457,12 @@ func (lm *LocalEOTSManager) getEOTSPrivKey(fpPk []byte, passphrase string) (*btc
 		return nil, err
 	}
 
-	lm.input.Reset(passphrase)
+	krPassphrase, err := lm.keyringPassphrase(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	lm.input.Reset(krPassphrase)
 	k, err := lm.kr.Key(keyName)
 	if err != nil {
 		return nil, err
@@ -294,6 +478,9 @@ func eotsPrivKeyFromRecord(k *keyring.Record) (*btcec.PrivateKey, error) {
 	switch v := privKeyCached.(type) {
 	case *secp256k1.PrivKey:
 		privKey, _ = btcec.PrivKeyFromBytes(v.Key)
+		// the record is decoded from the keyring for each load, so its
+		// copy of the key is wiped once converted
+		securemem.Zero(v.Key)
 		return privKey, nil
 	default:
 		return nil, fmt.Errorf("unsupported key type in keyring")
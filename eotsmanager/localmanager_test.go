@@ -7,11 +7,13 @@ import (
 	"testing"
 
 	"github.com/babylonchain/babylon/testutil/datagen"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	eotscfg "github.com/babylonchain/finality-provider/eotsmanager/config"
+	"github.com/babylonchain/finality-provider/eotsmanager/store"
 	"github.com/babylonchain/finality-provider/eotsmanager/types"
 	"github.com/babylonchain/finality-provider/testutil"
 )
@@ -86,9 +88,158 @@ func FuzzCreateRandomnessPairList(f *testing.F) {
 		require.Len(t, pubRandList, num)
 
 		for i := 0; i < num; i++ {
-			sig, err := lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), startHeight+uint64(i), passphrase)
+			msg := datagen.GenRandomByteArray(r, 32)
+			sig, err := lm.SignEOTS(fpPk, chainID, msg, startHeight+uint64(i), passphrase)
 			require.NoError(t, err)
 			require.NotNil(t, sig)
+
+			// the same message can be signed again but not a different one
+			sig2, err := lm.SignEOTS(fpPk, chainID, msg, startHeight+uint64(i), passphrase)
+			require.NoError(t, err)
+			require.True(t, sig.Equals(sig2))
+			_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), startHeight+uint64(i), passphrase)
+			require.ErrorIs(t, err, store.ErrDoubleSign)
 		}
 	})
 }
+
+func FuzzShredRandomness(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer dbBackend.Close()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+
+		chainID := datagen.GenRandomByteArray(r, 10)
+		committedHeight := datagen.RandomInt(r, 100) + 1
+		committed, err := lm.CreateRandomnessPairList(fpPk, chainID, committedHeight, 1, passphrase)
+		require.NoError(t, err)
+
+		// the randomness committed before the seeding is unchanged
+		startHeight := committedHeight + 1
+		err = lm.SeedRandomness(fpPk, chainID, startHeight)
+		require.NoError(t, err)
+		stillCommitted, err := lm.CreateRandomnessPairList(fpPk, chainID, committedHeight, 1, passphrase)
+		require.NoError(t, err)
+		require.True(t, committed[0].Equals(stillCommitted[0]))
+
+		seeded, err := lm.CreateRandomnessPairList(fpPk, chainID, startHeight, 1, passphrase)
+		require.NoError(t, err)
+		sameSeeded, err := lm.CreateRandomnessPairList(fpPk, chainID, startHeight, 1, passphrase)
+		require.NoError(t, err)
+		require.True(t, seeded[0].Equals(sameSeeded[0]))
+
+		// the seeded randomness can no longer be derived once shredded
+		err = lm.ShredRandomness(fpPk, chainID, startHeight+2*store.RandSeedEpochSize)
+		require.NoError(t, err)
+		_, err = lm.CreateRandomnessPairList(fpPk, chainID, startHeight, 1, passphrase)
+		require.ErrorIs(t, err, store.ErrRandomnessShredded)
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), startHeight, passphrase)
+		require.ErrorIs(t, err, store.ErrRandomnessShredded)
+	})
+}
+
+// FuzzSignShreddedHeights tests that none of the shredded heights can be
+// signed, including the ones signed before, while the heights above them can
+func FuzzSignShreddedHeights(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		fpName := testutil.GenRandomHexStr(r, 4)
+		homeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer dbBackend.Close()
+		lm, err := eotsmanager.NewLocalEOTSManager(homeDir, eotsCfg.KeyringBackend, dbBackend, zap.NewNop())
+		require.NoError(t, err)
+
+		fpPk, err := lm.CreateKey(fpName, passphrase, hdPath)
+		require.NoError(t, err)
+
+		chainID := datagen.GenRandomByteArray(r, 10)
+		startHeight := datagen.RandomInt(r, 100) + 1
+		err = lm.SeedRandomness(fpPk, chainID, startHeight)
+		require.NoError(t, err)
+
+		signedHeight := startHeight + datagen.RandomInt(r, int(store.RandSeedEpochSize))
+		msg := datagen.GenRandomByteArray(r, 32)
+		_, err = lm.SignEOTS(fpPk, chainID, msg, signedHeight, passphrase)
+		require.NoError(t, err)
+
+		belowHeight := (startHeight/store.RandSeedEpochSize + 2) * store.RandSeedEpochSize
+		err = lm.ShredRandomness(fpPk, chainID, belowHeight)
+		require.NoError(t, err)
+
+		// the height signed before cannot be signed again, even for the same message
+		_, err = lm.SignEOTS(fpPk, chainID, msg, signedHeight, passphrase)
+		require.ErrorIs(t, err, store.ErrRandomnessShredded)
+
+		shreddedHeights := []uint64{startHeight, belowHeight - 1, startHeight + datagen.RandomInt(r, int(belowHeight-startHeight))}
+		for _, height := range shreddedHeights {
+			_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), height, passphrase)
+			require.ErrorIs(t, err, store.ErrRandomnessShredded)
+		}
+
+		_, err = lm.SignEOTS(fpPk, chainID, datagen.GenRandomByteArray(r, 32), belowHeight, passphrase)
+		require.NoError(t, err)
+	})
+}
+
+// TestKeyringSecurity tests the passphrase policy and the migration of the
+// file keyring to the hardened passphrase
+func TestKeyringSecurity(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	homeDir := filepath.Join(t.TempDir(), "eots-home")
+	eotsCfg := eotscfg.DefaultConfigWithHomePath(homeDir)
+	dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	defer dbBackend.Close()
+
+	lm, err := eotsmanager.NewLocalEOTSManager(homeDir, keyring.BackendFile, dbBackend, zap.NewNop())
+	require.NoError(t, err)
+	securityCfg := eotscfg.DefaultKeyringSecurityConfig()
+	securityCfg.MinPassphraseLength = 12
+	securityCfg.MinCharClasses = 3
+	lm.SetKeyringSecurity(securityCfg)
+
+	// weak passphrases are rejected
+	_, err = lm.CreateKey("weak", passphrase, hdPath)
+	require.ErrorIs(t, err, types.ErrWeakPassphrase)
+	_, err = lm.CreateKey("weak", "testpasstestpass", hdPath)
+	require.ErrorIs(t, err, types.ErrWeakPassphrase)
+
+	strongPassphrase := "Test-pass-1234"
+	fpPk, err := lm.CreateKey("strong", strongPassphrase, hdPath)
+	require.NoError(t, err)
+
+	// the keyring is migrated on the first unlock after enabling argon2id
+	securityCfg.KDF = eotscfg.KDFArgon2id
+	securityCfg.Argon2Time = 1
+	securityCfg.Argon2MemoryKiB = 64
+	securityCfg.Argon2Threads = 1
+	lm.SetKeyringSecurity(securityCfg)
+	sig, err := lm.SignSchnorrSig(fpPk, datagen.GenRandomByteArray(r, 32), strongPassphrase)
+	require.NoError(t, err)
+	require.NotNil(t, sig)
+	require.FileExists(t, filepath.Join(homeDir, "keyring-kdf.json"))
+	require.DirExists(t, filepath.Join(homeDir, "keyring-file.legacy"))
+
+	// the migrated keyring is unlocked by the same passphrase after restart
+	lm, err = eotsmanager.NewLocalEOTSManager(homeDir, keyring.BackendFile, dbBackend, zap.NewNop())
+	require.NoError(t, err)
+	fpRecord, err := lm.KeyRecord(fpPk, strongPassphrase)
+	require.NoError(t, err)
+	require.Equal(t, "strong", fpRecord.Name)
+}
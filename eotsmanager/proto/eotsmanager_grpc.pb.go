@@ -23,12 +23,19 @@ type EOTSManagerClient interface {
 	CreateKey(ctx context.Context, in *CreateKeyRequest, opts ...grpc.CallOption) (*CreateKeyResponse, error)
 	// CreateRandomnessPairList returns a list of Schnorr randomness pairs
 	CreateRandomnessPairList(ctx context.Context, in *CreateRandomnessPairListRequest, opts ...grpc.CallOption) (*CreateRandomnessPairListResponse, error)
+	// SeedRandomness generates the randomness from a height on with seeds that
+	// can be shredded
+	SeedRandomness(ctx context.Context, in *SeedRandomnessRequest, opts ...grpc.CallOption) (*SeedRandomnessResponse, error)
+	// ShredRandomness deletes the seeds of the randomness below a height
+	ShredRandomness(ctx context.Context, in *ShredRandomnessRequest, opts ...grpc.CallOption) (*ShredRandomnessResponse, error)
 	// KeyRecord returns the key record
 	KeyRecord(ctx context.Context, in *KeyRecordRequest, opts ...grpc.CallOption) (*KeyRecordResponse, error)
 	// SignEOTS signs an EOTS with the EOTS private key and the relevant randomness
 	SignEOTS(ctx context.Context, in *SignEOTSRequest, opts ...grpc.CallOption) (*SignEOTSResponse, error)
 	// SignSchnorrSig signs a Schnorr sig with the EOTS private key
 	SignSchnorrSig(ctx context.Context, in *SignSchnorrSigRequest, opts ...grpc.CallOption) (*SignSchnorrSigResponse, error)
+	// ListKeys returns the public information of all the EOTS keys
+	ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
 }
 
 type eOTSManagerClient struct {
@@ -66,6 +73,24 @@ func (c *eOTSManagerClient) CreateRandomnessPairList(ctx context.Context, in *Cr
 	return out, nil
 }
 
+func (c *eOTSManagerClient) SeedRandomness(ctx context.Context, in *SeedRandomnessRequest, opts ...grpc.CallOption) (*SeedRandomnessResponse, error) {
+	out := new(SeedRandomnessResponse)
+	err := c.cc.Invoke(ctx, "/proto.EOTSManager/SeedRandomness", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eOTSManagerClient) ShredRandomness(ctx context.Context, in *ShredRandomnessRequest, opts ...grpc.CallOption) (*ShredRandomnessResponse, error) {
+	out := new(ShredRandomnessResponse)
+	err := c.cc.Invoke(ctx, "/proto.EOTSManager/ShredRandomness", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eOTSManagerClient) KeyRecord(ctx context.Context, in *KeyRecordRequest, opts ...grpc.CallOption) (*KeyRecordResponse, error) {
 	out := new(KeyRecordResponse)
 	err := c.cc.Invoke(ctx, "/proto.EOTSManager/KeyRecord", in, out, opts...)
@@ -93,6 +118,15 @@ func (c *eOTSManagerClient) SignSchnorrSig(ctx context.Context, in *SignSchnorrS
 	return out, nil
 }
 
+func (c *eOTSManagerClient) ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, "/proto.EOTSManager/ListKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // EOTSManagerServer is the server API for EOTSManager service.
 // All implementations must embed UnimplementedEOTSManagerServer
 // for forward compatibility
@@ -102,12 +136,19 @@ type EOTSManagerServer interface {
 	CreateKey(context.Context, *CreateKeyRequest) (*CreateKeyResponse, error)
 	// CreateRandomnessPairList returns a list of Schnorr randomness pairs
 	CreateRandomnessPairList(context.Context, *CreateRandomnessPairListRequest) (*CreateRandomnessPairListResponse, error)
+	// SeedRandomness generates the randomness from a height on with seeds that
+	// can be shredded
+	SeedRandomness(context.Context, *SeedRandomnessRequest) (*SeedRandomnessResponse, error)
+	// ShredRandomness deletes the seeds of the randomness below a height
+	ShredRandomness(context.Context, *ShredRandomnessRequest) (*ShredRandomnessResponse, error)
 	// KeyRecord returns the key record
 	KeyRecord(context.Context, *KeyRecordRequest) (*KeyRecordResponse, error)
 	// SignEOTS signs an EOTS with the EOTS private key and the relevant randomness
 	SignEOTS(context.Context, *SignEOTSRequest) (*SignEOTSResponse, error)
 	// SignSchnorrSig signs a Schnorr sig with the EOTS private key
 	SignSchnorrSig(context.Context, *SignSchnorrSigRequest) (*SignSchnorrSigResponse, error)
+	// ListKeys returns the public information of all the EOTS keys
+	ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error)
 	mustEmbedUnimplementedEOTSManagerServer()
 }
 
@@ -124,6 +165,12 @@ func (UnimplementedEOTSManagerServer) CreateKey(context.Context, *CreateKeyReque
 func (UnimplementedEOTSManagerServer) CreateRandomnessPairList(context.Context, *CreateRandomnessPairListRequest) (*CreateRandomnessPairListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateRandomnessPairList not implemented")
 }
+func (UnimplementedEOTSManagerServer) SeedRandomness(context.Context, *SeedRandomnessRequest) (*SeedRandomnessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SeedRandomness not implemented")
+}
+func (UnimplementedEOTSManagerServer) ShredRandomness(context.Context, *ShredRandomnessRequest) (*ShredRandomnessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShredRandomness not implemented")
+}
 func (UnimplementedEOTSManagerServer) KeyRecord(context.Context, *KeyRecordRequest) (*KeyRecordResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method KeyRecord not implemented")
 }
@@ -133,6 +180,9 @@ func (UnimplementedEOTSManagerServer) SignEOTS(context.Context, *SignEOTSRequest
 func (UnimplementedEOTSManagerServer) SignSchnorrSig(context.Context, *SignSchnorrSigRequest) (*SignSchnorrSigResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignSchnorrSig not implemented")
 }
+func (UnimplementedEOTSManagerServer) ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
 func (UnimplementedEOTSManagerServer) mustEmbedUnimplementedEOTSManagerServer() {}
 
 // UnsafeEOTSManagerServer may be embedded to opt out of forward compatibility for this service.
@@ -200,6 +250,42 @@ func _EOTSManager_CreateRandomnessPairList_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EOTSManager_SeedRandomness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SeedRandomnessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).SeedRandomness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EOTSManager/SeedRandomness",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).SeedRandomness(ctx, req.(*SeedRandomnessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EOTSManager_ShredRandomness_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShredRandomnessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).ShredRandomness(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EOTSManager/ShredRandomness",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).ShredRandomness(ctx, req.(*ShredRandomnessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EOTSManager_KeyRecord_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(KeyRecordRequest)
 	if err := dec(in); err != nil {
@@ -254,6 +340,24 @@ func _EOTSManager_SignSchnorrSig_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EOTSManager_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EOTSManagerServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.EOTSManager/ListKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EOTSManagerServer).ListKeys(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // EOTSManager_ServiceDesc is the grpc.ServiceDesc for EOTSManager service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -273,6 +377,14 @@ var EOTSManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateRandomnessPairList",
 			Handler:    _EOTSManager_CreateRandomnessPairList_Handler,
 		},
+		{
+			MethodName: "SeedRandomness",
+			Handler:    _EOTSManager_SeedRandomness_Handler,
+		},
+		{
+			MethodName: "ShredRandomness",
+			Handler:    _EOTSManager_ShredRandomness_Handler,
+		},
 		{
 			MethodName: "KeyRecord",
 			Handler:    _EOTSManager_KeyRecord_Handler,
@@ -285,6 +397,10 @@ var EOTSManager_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SignSchnorrSig",
 			Handler:    _EOTSManager_SignSchnorrSig_Handler,
 		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _EOTSManager_ListKeys_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "eotsmanager.proto",
@@ -12,12 +12,32 @@ import (
 
 // GenerateRandomness generates a random scalar with the given key and src
 // the result is deterministic with each given input
+//
+// NOTE: anyone holding the EOTS key can derive the randomness of any height
+// generated this way, which is why the randomness of the heights committed
+// after the randomness shredding is enabled is generated with a seed instead
 func GenerateRandomness(key []byte, chainID []byte, height uint64) (*eots.PrivateRand, *eots.PublicRand) {
 	// calculate the randomn hash of the key concatenated with chainID and height
 	digest := hmac.New(sha256.New, key)
 	digest.Write(append(sdk.Uint64ToBigEndian(height), chainID...))
 	randPre := digest.Sum(nil)
 
+	return randomnessFromHash(randPre)
+}
+
+// GenerateRandomnessWithSeed generates a random scalar with the given key,
+// seed and src, which is deterministic with each given input. Once the seed
+// is deleted, the randomness cannot be derived again, even with the key
+func GenerateRandomnessWithSeed(key []byte, seed []byte, chainID []byte, height uint64) (*eots.PrivateRand, *eots.PublicRand) {
+	digest := hmac.New(sha256.New, key)
+	digest.Write(seed)
+	digest.Write(append(sdk.Uint64ToBigEndian(height), chainID...))
+	randPre := digest.Sum(nil)
+
+	return randomnessFromHash(randPre)
+}
+
+func randomnessFromHash(randPre []byte) (*eots.PrivateRand, *eots.PublicRand) {
 	// convert the hash into private random
 	var randScalar btcec.ModNScalar
 	randScalar.SetByteSlice(randPre)
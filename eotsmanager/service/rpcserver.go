@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	"github.com/babylonchain/finality-provider/eotsmanager/proto"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
 )
 
 // rpcServer is the main RPC server for the EOTS daemon that handles
@@ -15,15 +17,20 @@ type rpcServer struct {
 	proto.UnimplementedEOTSManagerServer
 
 	em eotsmanager.EOTSManager
+	// authEnabled is set if the requests are authenticated, without which
+	// the randomness seeds are not managed over RPC
+	authEnabled bool
 }
 
 // newRPCServer creates a new RPC sever from the set of input dependencies.
 func newRPCServer(
 	em eotsmanager.EOTSManager,
+	authEnabled bool,
 ) *rpcServer {
 
 	return &rpcServer{
-		em: em,
+		em:          em,
+		authEnabled: authEnabled,
 	}
 }
 
@@ -72,6 +79,40 @@ func (r *rpcServer) CreateRandomnessPairList(ctx context.Context, req *proto.Cre
 	}, nil
 }
 
+// SeedRandomness generates the randomness from a height on with seeds that
+// can be shredded. It is refused unless the requests are authenticated, as
+// any client could otherwise make the randomness unrecoverable from the key
+func (r *rpcServer) SeedRandomness(ctx context.Context, req *proto.SeedRandomnessRequest) (
+	*proto.SeedRandomnessResponse, error) {
+
+	if !r.authEnabled {
+		return nil, types.ErrAuthRequired
+	}
+
+	if err := r.em.SeedRandomness(req.Uid, req.ChainId, req.StartHeight); err != nil {
+		return nil, err
+	}
+
+	return &proto.SeedRandomnessResponse{}, nil
+}
+
+// ShredRandomness deletes the seeds of the randomness below a height. It is
+// refused unless the requests are authenticated, as any client could
+// otherwise stop the finality provider from signing the heights
+func (r *rpcServer) ShredRandomness(ctx context.Context, req *proto.ShredRandomnessRequest) (
+	*proto.ShredRandomnessResponse, error) {
+
+	if !r.authEnabled {
+		return nil, types.ErrAuthRequired
+	}
+
+	if err := r.em.ShredRandomness(req.Uid, req.ChainId, req.BelowHeight); err != nil {
+		return nil, err
+	}
+
+	return &proto.ShredRandomnessResponse{}, nil
+}
+
 // KeyRecord returns the key record
 func (r *rpcServer) KeyRecord(ctx context.Context, req *proto.KeyRecordRequest) (
 	*proto.KeyRecordResponse, error) {
@@ -80,6 +121,7 @@ func (r *rpcServer) KeyRecord(ctx context.Context, req *proto.KeyRecordRequest)
 	if err != nil {
 		return nil, err
 	}
+	defer record.PrivKey.Zero()
 
 	res := &proto.KeyRecordResponse{
 		Name:       record.Name,
@@ -114,3 +156,34 @@ func (r *rpcServer) SignSchnorrSig(ctx context.Context, req *proto.SignSchnorrSi
 
 	return &proto.SignSchnorrSigResponse{Sig: sig.Serialize()}, nil
 }
+
+// ListKeys returns the public information of all the EOTS keys
+func (r *rpcServer) ListKeys(ctx context.Context, req *proto.ListKeysRequest) (
+	*proto.ListKeysResponse, error) {
+
+	keys, err := r.em.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &proto.ListKeysResponse{Keys: make([]*proto.KeyInfo, 0, len(keys))}
+	for _, k := range keys {
+		res.Keys = append(res.Keys, &proto.KeyInfo{
+			Name:          k.Name,
+			Pk:            k.PubKey,
+			CreatedAt:     unixOrZero(k.CreatedAt),
+			LastUsedAt:    unixOrZero(k.LastUsedAt),
+			NumSignatures: k.NumSignatures,
+		})
+	}
+
+	return res, nil
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
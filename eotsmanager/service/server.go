@@ -6,18 +6,26 @@ import (
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/profiling"
 
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/signal"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 
 	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/auth"
 	"github.com/babylonchain/finality-provider/eotsmanager/config"
 )
 
+// minKeepAliveInterval is the shortest keep-alive interval of the clients
+// tolerated before their connections are closed
+const minKeepAliveInterval = 10 * time.Second
+
 // Server is the main daemon construct for the EOTS manager server. It handles
 // spinning up the RPC sever, the database, and any other components that the
 // EOTS manager server needs to function.
@@ -39,7 +47,7 @@ func NewEOTSManagerServer(cfg *config.Config, l *zap.Logger, em eotsmanager.EOTS
 	return &Server{
 		cfg:         cfg,
 		logger:      l,
-		rpcServer:   newRPCServer(em),
+		rpcServer:   newRPCServer(em, len(cfg.AuthClients) > 0),
 		db:          db,
 		interceptor: sig,
 		quit:        make(chan struct{}, 1),
@@ -72,6 +80,14 @@ func (s *Server) RunUntilShutdown() error {
 		s.logger.Info("Metrics server stopped")
 	}()
 
+	if s.cfg.Profiling.Enabled() {
+		profiler, err := profiling.Start(s.cfg.Profiling, "eotsd", s.logger)
+		if err != nil {
+			return fmt.Errorf("failed to start the profiling: %w", err)
+		}
+		defer profiler.Stop()
+	}
+
 	listenAddr := s.cfg.RpcListener
 	// we create listeners from the RPCListeners defined
 	// in the config.
@@ -81,7 +97,22 @@ func (s *Server) RunUntilShutdown() error {
 	}
 	defer lis.Close()
 
-	grpcServer := grpc.NewServer()
+	// allow the keep-alive pings of the pooled client connections
+	serverOpts := []grpc.ServerOption{grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+		MinTime:             minKeepAliveInterval,
+		PermitWithoutStream: true,
+	})}
+	if len(s.cfg.AuthClients) > 0 {
+		authenticator, err := auth.NewAuthenticator(s.cfg.AuthClients, s.cfg.AuthKeys)
+		if err != nil {
+			return fmt.Errorf("failed to create the authenticator: %w", err)
+		}
+		serverOpts = append(serverOpts, grpc.ChainUnaryInterceptor(authenticator.UnaryServerInterceptor()))
+	} else {
+		s.logger.Warn("the requests are not authenticated as no client is set in the config")
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	defer grpcServer.Stop()
 
 	if err := s.rpcServer.RegisterWithGrpcServer(grpcServer); err != nil {
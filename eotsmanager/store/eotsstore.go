@@ -1,7 +1,9 @@
 package store
 
 import (
+	"encoding/hex"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -33,7 +35,18 @@ func (s *EOTSStore) initBuckets() error {
 			return err
 		}
 
-		return nil
+		_, err = tx.CreateTopLevelBucket(signRecordBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(keyUsageBucketName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(randSeedBucketName)
+		return err
 	})
 }
 
@@ -54,6 +67,10 @@ func (s *EOTSStore) AddEOTSKeyName(
 			return ErrDuplicateEOTSKeyName
 		}
 
+		if err := saveKeyCreation(tx, pkBytes, time.Now()); err != nil {
+			return err
+		}
+
 		return saveEOTSKeyName(eotsBucket, pkBytes, keyName)
 	})
 }
@@ -93,3 +110,28 @@ func (s *EOTSStore) GetEOTSKeyName(pk []byte) (string, error) {
 
 	return keyName, nil
 }
+
+// GetAllEOTSKeyNames returns all the EOTS key names keyed by the hex string
+// of the BTC public key following BIP-340 spec
+func (s *EOTSStore) GetAllEOTSKeyNames() (map[string]string, error) {
+	keyNames := make(map[string]string)
+	err := s.db.View(func(tx kvdb.RTx) error {
+		eotsBucket := tx.ReadBucket(eotsBucketName)
+		if eotsBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		return eotsBucket.ForEach(func(k, v []byte) error {
+			keyNames[hex.EncodeToString(k)] = string(v)
+			return nil
+		})
+	}, func() {
+		keyNames = make(map[string]string)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keyNames, nil
+}
@@ -1,9 +1,11 @@
 package store_test
 
 import (
+	"encoding/hex"
 	"math/rand"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/babylonchain/babylon/testutil/datagen"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
@@ -61,5 +63,108 @@ func FuzzEOTSStore(f *testing.F) {
 		require.NoError(t, err)
 		_, err = vs.GetEOTSKeyName(schnorr.SerializePubKey(randomBtcPk))
 		require.ErrorIs(t, err, store.ErrEOTSKeyNameNotFound)
+
+		// the usage of the key is tracked from its creation
+		pkHex := hex.EncodeToString(schnorr.SerializePubKey(btcPk))
+		usages, err := vs.GetAllKeyUsages()
+		require.NoError(t, err)
+		require.False(t, usages[pkHex].CreatedAt.IsZero())
+		require.True(t, usages[pkHex].LastUsedAt.IsZero())
+		require.Zero(t, usages[pkHex].NumSignatures)
+
+		usedAt := time.Unix(time.Now().Unix(), 0)
+		err = vs.RecordKeyUse(schnorr.SerializePubKey(btcPk), usedAt)
+		require.NoError(t, err)
+		usages, err = vs.GetAllKeyUsages()
+		require.NoError(t, err)
+		require.Equal(t, usedAt, usages[pkHex].LastUsedAt)
+		require.Equal(t, uint64(1), usages[pkHex].NumSignatures)
+	})
+}
+
+// FuzzRandSeeds tests the randomness seeds are created from the start height
+// and shredded in whole epochs
+func FuzzRandSeeds(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		homePath := t.TempDir()
+		cfg := config.DefaultDBConfigWithHomePath(homePath)
+
+		dbBackend, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		defer dbBackend.Close()
+
+		vs, err := store.NewEOTSStore(dbBackend)
+		require.NoError(t, err)
+
+		_, btcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+		pk := schnorr.SerializePubKey(btcPk)
+		chainID := []byte(testutil.GenRandomHexStr(r, 10))
+		startHeight := uint64(r.Int63n(1000)) + 1
+
+		// the randomness is not seeded before the start height is set
+		seed, err := vs.GetRandSeed(pk, chainID, startHeight)
+		require.NoError(t, err)
+		require.Nil(t, seed)
+
+		err = vs.StartRandSeeds(pk, chainID, startHeight)
+		require.NoError(t, err)
+		// only the first start height takes effect
+		err = vs.StartRandSeeds(pk, chainID, startHeight+store.RandSeedEpochSize)
+		require.NoError(t, err)
+
+		seed, err = vs.GetRandSeed(pk, chainID, startHeight-1)
+		require.NoError(t, err)
+		require.Nil(t, seed)
+
+		seed, err = vs.GetRandSeed(pk, chainID, startHeight)
+		require.NoError(t, err)
+		require.Len(t, seed, 32)
+		sameSeed, err := vs.GetRandSeed(pk, chainID, startHeight)
+		require.NoError(t, err)
+		require.Equal(t, seed, sameSeed)
+
+		nextHeight := startHeight + store.RandSeedEpochSize
+		nextSeed, err := vs.GetRandSeed(pk, chainID, nextHeight)
+		require.NoError(t, err)
+		require.NotEqual(t, seed, nextSeed)
+
+		// the sign records of the shredded heights are pruned, while the
+		// ones of the heights not seeded or not shredded are kept
+		signedHeights := []uint64{startHeight - 1, startHeight, nextHeight}
+		for _, h := range signedHeights {
+			err = vs.SaveSignRecord(pk, chainID, h, []byte("msg"))
+			require.NoError(t, err)
+		}
+
+		// the epoch of the height is not complete, so nothing is shredded
+		shredded, err := vs.ShredRandSeeds(pk, chainID, startHeight)
+		require.NoError(t, err)
+		require.Zero(t, shredded)
+
+		shredded, err = vs.ShredRandSeeds(pk, chainID, nextHeight)
+		require.NoError(t, err)
+		require.Equal(t, 1, shredded)
+
+		_, err = vs.GetRandSeed(pk, chainID, startHeight)
+		require.ErrorIs(t, err, store.ErrRandomnessShredded)
+		// the heights below the start height are not seeded
+		seed, err = vs.GetRandSeed(pk, chainID, startHeight-1)
+		require.NoError(t, err)
+		require.Nil(t, seed)
+
+		sameNextSeed, err := vs.GetRandSeed(pk, chainID, nextHeight)
+		require.NoError(t, err)
+		require.Equal(t, nextSeed, sameNextSeed)
+
+		err = vs.SaveSignRecord(pk, chainID, startHeight, []byte("other msg"))
+		require.NoError(t, err)
+		for _, h := range []uint64{startHeight - 1, nextHeight} {
+			err = vs.SaveSignRecord(pk, chainID, h, []byte("other msg"))
+			require.ErrorIs(t, err, store.ErrDoubleSign)
+		}
 	})
 }
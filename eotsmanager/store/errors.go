@@ -11,4 +11,10 @@ var (
 
 	// ErrEOTSKeyNameNotFound The EOTS key name we try to fetch is not found in db
 	ErrEOTSKeyNameNotFound = errors.New("EOTS key name not found")
+
+	// ErrDoubleSign The key has signed a different message at the same height
+	ErrDoubleSign = errors.New("refused to sign a different message at a height already signed")
+
+	// ErrRandomnessShredded The seed of the randomness at the height has been deleted
+	ErrRandomnessShredded = errors.New("the randomness at the height has been shredded")
 )
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping pk -> created at || last used at || number of signatures
+	keyUsageBucketName = []byte("keyUsage")
+)
+
+const keyUsageLen = 24
+
+// KeyUsage is the usage of an EOTS key
+type KeyUsage struct {
+	// CreatedAt is zero if the key is created before the usage is tracked
+	CreatedAt time.Time
+	// LastUsedAt is zero if the key has never signed
+	LastUsedAt    time.Time
+	NumSignatures uint64
+}
+
+func (u *KeyUsage) marshal() []byte {
+	bz := make([]byte, 0, keyUsageLen)
+	bz = binary.BigEndian.AppendUint64(bz, unixOrZero(u.CreatedAt))
+	bz = binary.BigEndian.AppendUint64(bz, unixOrZero(u.LastUsedAt))
+
+	return binary.BigEndian.AppendUint64(bz, u.NumSignatures)
+}
+
+func unmarshalKeyUsage(bz []byte) (*KeyUsage, error) {
+	if len(bz) != keyUsageLen {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	return &KeyUsage{
+		CreatedAt:     timeOrZero(binary.BigEndian.Uint64(bz[:8])),
+		LastUsedAt:    timeOrZero(binary.BigEndian.Uint64(bz[8:16])),
+		NumSignatures: binary.BigEndian.Uint64(bz[16:]),
+	}, nil
+}
+
+func unixOrZero(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.Unix())
+}
+
+func timeOrZero(unix uint64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(unix), 0)
+}
+
+func saveKeyCreation(tx kvdb.RwTx, pk []byte, createdAt time.Time) error {
+	keyUsageBucket := tx.ReadWriteBucket(keyUsageBucketName)
+	if keyUsageBucket == nil {
+		return ErrCorruptedEOTSDb
+	}
+
+	usage := &KeyUsage{CreatedAt: createdAt}
+
+	return keyUsageBucket.Put(pk, usage.marshal())
+}
+
+// RecordKeyUse counts a signature by the key at the given time
+func (s *EOTSStore) RecordKeyUse(pk []byte, usedAt time.Time) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		keyUsageBucket := tx.ReadWriteBucket(keyUsageBucketName)
+		if keyUsageBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		usage, err := getKeyUsage(keyUsageBucket, pk)
+		if err != nil {
+			return err
+		}
+		usage.LastUsedAt = usedAt
+		usage.NumSignatures++
+
+		return keyUsageBucket.Put(pk, usage.marshal())
+	})
+}
+
+func getKeyUsage(keyUsageBucket walletdb.ReadBucket, pk []byte) (*KeyUsage, error) {
+	bz := keyUsageBucket.Get(pk)
+	if bz == nil {
+		// the key is created before the usage is tracked
+		return &KeyUsage{}, nil
+	}
+
+	return unmarshalKeyUsage(bz)
+}
+
+// GetAllKeyUsages returns the usage of all the EOTS keys keyed by the hex
+// string of the BTC public key following BIP-340 spec
+func (s *EOTSStore) GetAllKeyUsages() (map[string]*KeyUsage, error) {
+	usages := make(map[string]*KeyUsage)
+	err := s.db.View(func(tx kvdb.RTx) error {
+		keyUsageBucket := tx.ReadBucket(keyUsageBucketName)
+		if keyUsageBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		return keyUsageBucket.ForEach(func(k, v []byte) error {
+			usage, err := unmarshalKeyUsage(v)
+			if err != nil {
+				return err
+			}
+			usages[hex.EncodeToString(k)] = usage
+			return nil
+		})
+	}, func() {
+		usages = make(map[string]*KeyUsage)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return usages, nil
+}
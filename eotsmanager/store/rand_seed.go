@@ -0,0 +1,266 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/util/securemem"
+)
+
+var (
+	// mapping pk || chain ID -> (seed range -> start height || shredded below height,
+	// epoch -> seed)
+	randSeedBucketName = []byte("randSeeds")
+
+	randSeedRangeKey = []byte("range")
+)
+
+const (
+	// RandSeedEpochSize is the number of consecutive heights whose randomness
+	// is derived from the same seed, i.e., the granularity of the shredding
+	RandSeedEpochSize = 100
+
+	randSeedLen      = 32
+	randSeedRangeLen = 16
+)
+
+// randSeedRange is the range of the heights whose randomness is derived from
+// the seeds. The randomness of the heights below the start is derived from the
+// EOTS key alone, and the seeds of the heights below the shredded height are
+// deleted
+type randSeedRange struct {
+	start         uint64
+	shreddedBelow uint64
+}
+
+func (r *randSeedRange) marshal() []byte {
+	bz := make([]byte, 0, randSeedRangeLen)
+	bz = binary.BigEndian.AppendUint64(bz, r.start)
+
+	return binary.BigEndian.AppendUint64(bz, r.shreddedBelow)
+}
+
+func unmarshalRandSeedRange(bz []byte) (*randSeedRange, error) {
+	if len(bz) != randSeedRangeLen {
+		return nil, ErrCorruptedEOTSDb
+	}
+
+	return &randSeedRange{
+		start:         binary.BigEndian.Uint64(bz[:8]),
+		shreddedBelow: binary.BigEndian.Uint64(bz[8:]),
+	}, nil
+}
+
+func getRandSeedRange(seedBucket walletdb.ReadBucket) (*randSeedRange, error) {
+	bz := seedBucket.Get(randSeedRangeKey)
+	if bz == nil {
+		return nil, nil
+	}
+
+	return unmarshalRandSeedRange(bz)
+}
+
+// StartRandSeeds derives the randomness of the given chain from the seeds from
+// the given height on. Only the first call sets the start height, as the
+// randomness of the heights above it may be committed afterwards
+func (s *EOTSStore) StartRandSeeds(pk, chainID []byte, startHeight uint64) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		randSeedBucket := tx.ReadWriteBucket(randSeedBucketName)
+		if randSeedBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		seedBucket, err := randSeedBucket.CreateBucketIfNotExists(randSeedBucketKey(pk, chainID))
+		if err != nil {
+			return err
+		}
+
+		r, err := getRandSeedRange(seedBucket)
+		if err != nil || r != nil {
+			return err
+		}
+
+		r = &randSeedRange{start: startHeight}
+
+		return seedBucket.Put(randSeedRangeKey, r.marshal())
+	})
+}
+
+// GetRandSeed returns the seed of the randomness of the given chain at the
+// given height, which is created if it does not exist yet. It returns nil if
+// the randomness at the height is derived from the EOTS key alone, and
+// ErrRandomnessShredded if the seed is deleted. The caller should zero the
+// seed once it is used
+func (s *EOTSStore) GetRandSeed(pk, chainID []byte, height uint64) ([]byte, error) {
+	seed, found, err := s.lookUpRandSeed(pk, chainID, height)
+	if err != nil || found {
+		return seed, err
+	}
+
+	newSeed := make([]byte, randSeedLen)
+	if _, err := rand.Read(newSeed); err != nil {
+		return nil, err
+	}
+
+	err = kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		randSeedBucket := tx.ReadWriteBucket(randSeedBucketName)
+		if randSeedBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		seedBucket := randSeedBucket.NestedReadWriteBucket(randSeedBucketKey(pk, chainID))
+		if seedBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		r, err := getRandSeedRange(seedBucket)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			return ErrCorruptedEOTSDb
+		}
+		if height < r.shreddedBelow {
+			return ErrRandomnessShredded
+		}
+
+		// the seed may be created concurrently
+		epochKey := randSeedEpochKey(height)
+		if existing := seedBucket.Get(epochKey); existing != nil {
+			seed = append([]byte(nil), existing...)
+			return nil
+		}
+		seed = append([]byte(nil), newSeed...)
+
+		return seedBucket.Put(epochKey, newSeed)
+	})
+	securemem.Zero(newSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	return seed, nil
+}
+
+// lookUpRandSeed returns the seed of the randomness at the given height and
+// whether the lookup is conclusive, i.e., the seed is not to be created
+func (s *EOTSStore) lookUpRandSeed(pk, chainID []byte, height uint64) (seed []byte, found bool, err error) {
+	err = s.db.View(func(tx kvdb.RTx) error {
+		randSeedBucket := tx.ReadBucket(randSeedBucketName)
+		if randSeedBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		seedBucket := randSeedBucket.NestedReadBucket(randSeedBucketKey(pk, chainID))
+		if seedBucket == nil {
+			found = true
+			return nil
+		}
+
+		r, err := getRandSeedRange(seedBucket)
+		if err != nil {
+			return err
+		}
+		if r == nil || height < r.start {
+			found = true
+			return nil
+		}
+		if height < r.shreddedBelow {
+			return ErrRandomnessShredded
+		}
+
+		if bz := seedBucket.Get(randSeedEpochKey(height)); bz != nil {
+			seed = append([]byte(nil), bz...)
+			found = true
+		}
+		return nil
+	}, func() {
+		seed, found = nil, false
+	})
+
+	return seed, found, err
+}
+
+// ShredRandSeeds deletes the seeds of the randomness of the given chain whose
+// heights are all below the given height, so that the randomness of these
+// heights cannot be derived again. The sign records of the seeded heights are
+// pruned along with the seeds, as nothing can be signed at these heights any
+// more. It returns the number of the seeds deleted
+// NOTE: the deleted seeds are overwritten before they are deleted, but the
+// pages freed by the db may keep copies of them until the pages are reused
+func (s *EOTSStore) ShredRandSeeds(pk, chainID []byte, belowHeight uint64) (int, error) {
+	// only the whole epochs are shredded
+	belowHeight -= belowHeight % RandSeedEpochSize
+
+	var shredded int
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		shredded = 0
+
+		randSeedBucket := tx.ReadWriteBucket(randSeedBucketName)
+		if randSeedBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		seedBucket := randSeedBucket.NestedReadWriteBucket(randSeedBucketKey(pk, chainID))
+		if seedBucket == nil {
+			return nil
+		}
+
+		r, err := getRandSeedRange(seedBucket)
+		if err != nil {
+			return err
+		}
+		if r == nil || belowHeight <= r.shreddedBelow {
+			return nil
+		}
+
+		var epochKeys [][]byte
+		endKey := randSeedEpochKey(belowHeight)
+		err = seedBucket.ForEach(func(k, _ []byte) error {
+			if len(k) == len(endKey) && string(k) < string(endKey) {
+				epochKeys = append(epochKeys, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range epochKeys {
+			if err := seedBucket.Put(k, make([]byte, randSeedLen)); err != nil {
+				return err
+			}
+			if err := seedBucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		shredded = len(epochKeys)
+
+		// the heights below the start are not seeded and can still be
+		// signed, so their sign records are kept
+		pruneFrom := max(r.start, r.shreddedBelow)
+		if err := pruneSignRecords(tx, pk, chainID, pruneFrom, belowHeight); err != nil {
+			return err
+		}
+
+		r.shreddedBelow = belowHeight
+
+		return seedBucket.Put(randSeedRangeKey, r.marshal())
+	})
+
+	return shredded, err
+}
+
+func randSeedBucketKey(pk, chainID []byte) []byte {
+	key := make([]byte, 0, len(pk)+len(chainID))
+	key = append(key, pk...)
+
+	return append(key, chainID...)
+}
+
+func randSeedEpochKey(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, height/RandSeedEpochSize)
+}
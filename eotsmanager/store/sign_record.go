@@ -0,0 +1,77 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping pk || height || chain ID -> hash of the signed message
+	signRecordBucketName = []byte("signRecords")
+)
+
+// SaveSignRecord records that the key has signed the message at the height
+// of the chain. It refuses a different message at a height already signed,
+// as two EOTS signatures at the same height leak the private key, while the
+// same message can be signed again, e.g., when a submission is retried
+func (s *EOTSStore) SaveSignRecord(pk, chainID []byte, height uint64, msg []byte) error {
+	msgHash := sha256.Sum256(msg)
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		signRecordBucket := tx.ReadWriteBucket(signRecordBucketName)
+		if signRecordBucket == nil {
+			return ErrCorruptedEOTSDb
+		}
+
+		key := signRecordKey(pk, chainID, height)
+		if signedHash := signRecordBucket.Get(key); signedHash != nil {
+			if !bytes.Equal(signedHash, msgHash[:]) {
+				return ErrDoubleSign
+			}
+			return nil
+		}
+
+		return signRecordBucket.Put(key, msgHash[:])
+	})
+}
+
+func signRecordKey(pk, chainID []byte, height uint64) []byte {
+	key := make([]byte, 0, len(pk)+8+len(chainID))
+	key = append(key, pk...)
+	key = binary.BigEndian.AppendUint64(key, height)
+
+	return append(key, chainID...)
+}
+
+// pruneSignRecords deletes the sign records of the key on the chain at the
+// heights in [fromHeight, belowHeight), which must be called within the tx
+// shredding the seeds of these heights, as no message can be signed at them
+// afterwards
+func pruneSignRecords(tx kvdb.RwTx, pk, chainID []byte, fromHeight, belowHeight uint64) error {
+	signRecordBucket := tx.ReadWriteBucket(signRecordBucketName)
+	if signRecordBucket == nil {
+		return ErrCorruptedEOTSDb
+	}
+
+	// the records of the key are ordered by height, and those of the other
+	// chains in between are skipped
+	var keys [][]byte
+	endKey := signRecordKey(pk, nil, belowHeight)
+	c := signRecordBucket.ReadWriteCursor()
+	for k, _ := c.Seek(signRecordKey(pk, nil, fromHeight)); k != nil && bytes.Compare(k, endKey) < 0; k, _ = c.Next() {
+		if len(k) == len(pk)+8+len(chainID) && bytes.HasPrefix(k, pk) && bytes.HasSuffix(k, chainID) {
+			keys = append(keys, append([]byte(nil), k...))
+		}
+	}
+
+	for _, k := range keys {
+		if err := signRecordBucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
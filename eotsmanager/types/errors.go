@@ -4,4 +4,6 @@ import "errors"
 
 var (
 	ErrFinalityProviderAlreadyExisted = errors.New("the finality provider has already existed")
+	ErrWeakPassphrase                 = errors.New("the passphrase does not meet the policy")
+	ErrAuthRequired                   = errors.New("the request requires the authentication of the clients")
 )
@@ -1,8 +1,24 @@
 package types
 
-import "github.com/btcsuite/btcd/btcec/v2"
+import (
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
 
 type KeyRecord struct {
 	Name    string
 	PrivKey *btcec.PrivateKey
 }
+
+// KeyInfo is the public information of an EOTS key
+type KeyInfo struct {
+	Name string
+	// PubKey is the EOTS public key following BIP-340 spec
+	PubKey []byte
+	// CreatedAt is zero if the key is created before its usage is tracked
+	CreatedAt time.Time
+	// LastUsedAt is zero if the key has never signed
+	LastUsedAt    time.Time
+	NumSignatures uint64
+}
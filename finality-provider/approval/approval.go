@@ -0,0 +1,149 @@
+package approval
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+const (
+	// OperationRegisterFinalityProvider is the operation of registering a
+	// finality provider, whose payload is the hex BTC public key
+	OperationRegisterFinalityProvider = "register-finality-provider"
+	// OperationSignMessageFromChainKey is the operation of signing a message
+	// with a chain key, whose payload is given by SignMessagePayload
+	OperationSignMessageFromChainKey = "sign-message-from-chain-key"
+	// OperationExportState is the operation of exporting the state of the
+	// daemon, whose payload is given by ExportStatePayload
+	OperationExportState = "export-state"
+	// OperationImportState is the operation of importing a state archive,
+	// whose payload is the hex SHA-256 hash of the archive
+	OperationImportState = "import-state"
+
+	tokenSeparator = "."
+	msgPrefix      = "fpd-approval"
+)
+
+var (
+	ErrApprovalRequired = errors.New("the operation requires an approval token")
+	ErrInvalidApproval  = errors.New("invalid approval token")
+)
+
+// SignMessagePayload returns the approval payload of signing the given message
+// with the chain key of the given name
+func SignMessagePayload(keyName string, msg []byte) string {
+	msgHash := sha256.Sum256(msg)
+	return keyName + ":" + hex.EncodeToString(msgHash[:])
+}
+
+// ExportStatePayload returns the approval payload of exporting the state of
+// the daemon, which tells whether the chain keys are included
+func ExportStatePayload(includeKeys bool) string {
+	return "include_keys=" + strconv.FormatBool(includeKeys)
+}
+
+// approvalHash returns the hash that an approver signs to approve the
+// operation with the given payload until the expiry
+func approvalHash(operation, payload string, expiresAt int64) []byte {
+	msg := strings.Join([]string{msgPrefix, operation, payload, strconv.FormatInt(expiresAt, 10)}, "|")
+	h := sha256.Sum256([]byte(msg))
+	return h[:]
+}
+
+// NewToken creates an approval token of the operation with the given payload,
+// which is valid until the expiry. The token is in the form of
+// <approver BIP-340 pk hex>.<expiry unix seconds>.<Schnorr signature hex>
+func NewToken(approverSk *btcec.PrivateKey, operation, payload string, expiresAt time.Time) (string, error) {
+	sig, err := schnorr.Sign(approverSk, approvalHash(operation, payload, expiresAt.Unix()))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		hex.EncodeToString(schnorr.SerializePubKey(approverSk.PubKey())),
+		strconv.FormatInt(expiresAt.Unix(), 10),
+		hex.EncodeToString(sig.Serialize()),
+	}, tokenSeparator), nil
+}
+
+// Verifier checks approval tokens against a set of approvers
+type Verifier struct {
+	// approvers keyed by the hex BIP-340 public key
+	approvers map[string]*btcec.PublicKey
+}
+
+// NewVerifier returns a verifier accepting tokens signed by any of the
+// approvers given by their hex BIP-340 public keys
+func NewVerifier(approverPkHexes []string) (*Verifier, error) {
+	if len(approverPkHexes) == 0 {
+		return nil, fmt.Errorf("no approver is specified")
+	}
+
+	approvers := make(map[string]*btcec.PublicKey, len(approverPkHexes))
+	for _, pkHex := range approverPkHexes {
+		pk, err := parsePubKey(pkHex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid approver public key %s: %w", pkHex, err)
+		}
+		approvers[strings.ToLower(pkHex)] = pk
+	}
+
+	return &Verifier{approvers: approvers}, nil
+}
+
+// Verify checks that the token is signed by one of the approvers over the
+// operation with the given payload, and that it has not expired
+func (v *Verifier) Verify(token, operation, payload string) error {
+	if token == "" {
+		return fmt.Errorf("%w: %s", ErrApprovalRequired, operation)
+	}
+
+	parts := strings.Split(token, tokenSeparator)
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: malformed token", ErrInvalidApproval)
+	}
+
+	approverPk, ok := v.approvers[strings.ToLower(parts[0])]
+	if !ok {
+		return fmt.Errorf("%w: unknown approver %s", ErrInvalidApproval, parts[0])
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid expiry: %v", ErrInvalidApproval, err)
+	}
+	if time.Now().Unix() > expiresAt {
+		return fmt.Errorf("%w: the token has expired", ErrInvalidApproval)
+	}
+
+	sigBytes, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: invalid signature: %v", ErrInvalidApproval, err)
+	}
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return fmt.Errorf("%w: invalid signature: %v", ErrInvalidApproval, err)
+	}
+
+	if !sig.Verify(approvalHash(operation, payload, expiresAt), approverPk) {
+		return fmt.Errorf("%w: the signature does not match the operation", ErrInvalidApproval)
+	}
+
+	return nil
+}
+
+func parsePubKey(pkHex string) (*btcec.PublicKey, error) {
+	pkBytes, err := hex.DecodeString(pkHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return schnorr.ParsePubKey(pkBytes)
+}
@@ -0,0 +1,50 @@
+package approval_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/approval"
+)
+
+func TestApprovalToken(t *testing.T) {
+	approverSk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	otherSk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	v, err := approval.NewVerifier([]string{hex.EncodeToString(schnorr.SerializePubKey(approverSk.PubKey()))})
+	require.NoError(t, err)
+
+	op := approval.OperationRegisterFinalityProvider
+	payload := "fp-pk"
+
+	token, err := approval.NewToken(approverSk, op, payload, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, v.Verify(token, op, payload))
+
+	// missing token
+	require.True(t, errors.Is(v.Verify("", op, payload), approval.ErrApprovalRequired))
+
+	// token for another payload
+	require.True(t, errors.Is(v.Verify(token, op, "other-fp-pk"), approval.ErrInvalidApproval))
+
+	// token for another operation
+	require.True(t, errors.Is(v.Verify(token, approval.OperationSignMessageFromChainKey, payload), approval.ErrInvalidApproval))
+
+	// token from an unknown approver
+	otherToken, err := approval.NewToken(otherSk, op, payload, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.True(t, errors.Is(v.Verify(otherToken, op, payload), approval.ErrInvalidApproval))
+
+	// expired token
+	expiredToken, err := approval.NewToken(approverSk, op, payload, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.True(t, errors.Is(v.Verify(expiredToken, op, payload), approval.ErrInvalidApproval))
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/finality-provider/approval"
+)
+
+const (
+	approverKeyFileFlag = "approver-key-file"
+	operationFlag       = "operation"
+	payloadFlag         = "payload"
+	ttlFlag             = "ttl"
+
+	defaultApprovalTTL = 10 * time.Minute
+)
+
+// CreateApprovalCmd signs an approval token with the approver's key, which is
+// run by the approver rather than the operator of fpd
+var CreateApprovalCmd = cli.Command{
+	Name:      "create-approval",
+	ShortName: "ca",
+	Usage:     "Create an approval token for an operation that requires a second authorization in approval mode.",
+	UsageText: fmt.Sprintf("create-approval --%s [file] --%s [%s|%s] --%s [payload]",
+		approverKeyFileFlag, operationFlag,
+		approval.OperationRegisterFinalityProvider, approval.OperationSignMessageFromChainKey,
+		payloadFlag),
+	Description: `The payload of registering a finality provider is its hex BTC public key,
+	and the payload of signing a message is reported by fpd when the approval is missing.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     approverKeyFileFlag,
+			Usage:    "The path to the file of the approver's hex secp256k1 private key",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     operationFlag,
+			Usage:    "The operation to approve",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     payloadFlag,
+			Usage:    "The payload of the operation to approve",
+			Required: true,
+		},
+		cli.DurationFlag{
+			Name:  ttlFlag,
+			Usage: "The duration for which the approval token is valid",
+			Value: defaultApprovalTTL,
+		},
+	},
+	Action: createApproval,
+}
+
+func createApproval(ctx *cli.Context) error {
+	operation := ctx.String(operationFlag)
+	switch operation {
+	case approval.OperationRegisterFinalityProvider, approval.OperationSignMessageFromChainKey:
+	default:
+		return fmt.Errorf("unsupported operation: %s", operation)
+	}
+
+	skBytes, err := os.ReadFile(ctx.String(approverKeyFileFlag))
+	if err != nil {
+		return fmt.Errorf("failed to read the approver key file: %w", err)
+	}
+	skRaw, err := hex.DecodeString(strings.TrimSpace(string(skBytes)))
+	if err != nil {
+		return fmt.Errorf("invalid approver private key: %w", err)
+	}
+	sk, _ := btcec.PrivKeyFromBytes(skRaw)
+
+	expiresAt := time.Now().Add(ctx.Duration(ttlFlag))
+	token, err := approval.NewToken(sk, operation, ctx.String(payloadFlag), expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create the approval token: %w", err)
+	}
+
+	printRespJSON(map[string]string{
+		"approval_token": token,
+		"expires_at":     expiresAt.UTC().Format(time.RFC3339),
+	})
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+)
+
+// completionTimeout bounds the queries to fpd for completing flag values, so
+// that a stopped daemon does not hang the shell
+const completionTimeout = 2 * time.Second
+
+const bashCompletionTemplate = `# bash completion for %[1]s, load it with: source <(%[1]s completion bash)
+_%[1]s_bash_autocomplete() {
+  local cur opts
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ "$cur" == "-"* ]]; then
+    opts=$( ${COMP_WORDS[@]:0:$COMP_CWORD} ${cur} --generate-bash-completion )
+  else
+    opts=$( ${COMP_WORDS[@]:0:$COMP_CWORD} --generate-bash-completion )
+  fi
+  COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+  return 0
+}
+
+complete -o bashdefault -o default -o nospace -F _%[1]s_bash_autocomplete %[1]s
+`
+
+const zshCompletionTemplate = `#compdef %[1]s
+# zsh completion for %[1]s, load it with: source <(%[1]s completion zsh)
+_%[1]s_zsh_autocomplete() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(_CLI_ZSH_AUTOCOMPLETE_HACK=1 ${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion)}")
+  else
+    opts=("${(@f)$(_CLI_ZSH_AUTOCOMPLETE_HACK=1 ${words[@]:0:#words[@]-1} --generate-bash-completion)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+
+compdef _%[1]s_zsh_autocomplete %[1]s
+`
+
+// CompletionCmd prints the shell completion scripts. The completions of bash
+// and zsh are generated by fpcli on the fly, which also completes the BTC
+// public keys from the finality providers stored in fpd
+var CompletionCmd = cli.Command{
+	Name:  "completion",
+	Usage: "Print the shell completion script of fpcli.",
+	Subcommands: []cli.Command{
+		{
+			Name:  "bash",
+			Usage: "Print the bash completion script, e.g., source <(fpcli completion bash)",
+			Action: func(ctx *cli.Context) error {
+				fmt.Fprintf(ctx.App.Writer, bashCompletionTemplate, ctx.App.Name)
+				return nil
+			},
+		},
+		{
+			Name:  "zsh",
+			Usage: "Print the zsh completion script, e.g., source <(fpcli completion zsh)",
+			Action: func(ctx *cli.Context) error {
+				fmt.Fprintf(ctx.App.Writer, zshCompletionTemplate, ctx.App.Name)
+				return nil
+			},
+		},
+		{
+			Name:  "fish",
+			Usage: "Print the fish completion script, e.g., fpcli completion fish | source",
+			Action: func(ctx *cli.Context) error {
+				script, err := ctx.App.ToFishCompletion()
+				if err != nil {
+					return fmt.Errorf("failed to generate the fish completion: %w", err)
+				}
+				fmt.Fprint(ctx.App.Writer, script)
+				return nil
+			},
+		},
+	},
+}
+
+// completeBtcPk completes the value of --btc-pk with the finality providers
+// stored in fpd, and falls back to the default completion otherwise
+func completeBtcPk(ctx *cli.Context) {
+	if len(os.Args) < 3 || strings.TrimLeft(os.Args[len(os.Args)-2], "-") != fpBTCPkFlag {
+		cli.DefaultCompleteWithFlags(&ctx.Command)(ctx)
+		return
+	}
+
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(ctx.String(fpdDaemonAddressFlag))
+	if err != nil {
+		return
+	}
+	defer cleanUp()
+
+	queryCtx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	res, err := rpcClient.QueryFinalityProviderList(queryCtx)
+	if err != nil {
+		return
+	}
+
+	for _, fp := range res.FinalityProviders {
+		fmt.Fprintln(ctx.App.Writer, fp.BtcPkHex)
+	}
+}
@@ -0,0 +1,108 @@
+package daemon_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+	"google.golang.org/grpc"
+
+	dcli "github.com/babylonchain/finality-provider/finality-provider/cmd/fpcli/daemon"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+type fpListServer struct {
+	proto.UnimplementedFinalityProvidersServer
+	btcPks []string
+}
+
+func (s *fpListServer) QueryFinalityProviderList(context.Context, *proto.QueryFinalityProviderListRequest) (*proto.QueryFinalityProviderListResponse, error) {
+	res := &proto.QueryFinalityProviderListResponse{}
+	for _, pk := range s.btcPks {
+		res.FinalityProviders = append(res.FinalityProviders, &proto.FinalityProviderInfo{BtcPkHex: pk})
+	}
+	return res, nil
+}
+
+func startFpListServer(t *testing.T, btcPks ...string) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	proto.RegisterFinalityProvidersServer(s, &fpListServer{btcPks: btcPks})
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
+
+func completionTestApp() (*cli.App, *bytes.Buffer) {
+	var out bytes.Buffer
+	app := cli.NewApp()
+	app.Name = "fpcli"
+	app.EnableBashCompletion = true
+	app.Writer = &out
+	app.Commands = append(app.Commands, dcli.RegisterFpDaemonCmd, dcli.CompletionCmd)
+
+	return app, &out
+}
+
+// runCompletion runs the app the way the completion scripts do, which pass the
+// command line through os.Args as well
+func runCompletion(t *testing.T, app *cli.App, args ...string) {
+	args = append(append([]string{app.Name}, args...), "--generate-bash-completion")
+	osArgs := os.Args
+	os.Args = args
+	t.Cleanup(func() {
+		os.Args = osArgs
+	})
+
+	require.NoError(t, app.Run(args))
+}
+
+func TestCompletionScripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		t.Run(shell, func(t *testing.T) {
+			app, out := completionTestApp()
+
+			require.NoError(t, app.Run([]string{app.Name, "completion", shell}))
+			require.Contains(t, out.String(), app.Name)
+		})
+	}
+}
+
+func TestCompleteBtcPk(t *testing.T) {
+	btcPks := []string{"btc-pk-1", "btc-pk-2"}
+
+	t.Run("the BTC public keys are completed from fpd", func(t *testing.T) {
+		app, out := completionTestApp()
+		addr := startFpListServer(t, btcPks...)
+
+		runCompletion(t, app, "register", "--daemon-address="+addr, "--btc-pk")
+		require.Equal(t, btcPks, strings.Fields(out.String()))
+	})
+
+	t.Run("nothing is completed if fpd is unreachable", func(t *testing.T) {
+		app, out := completionTestApp()
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := lis.Addr().String()
+		require.NoError(t, lis.Close())
+
+		runCompletion(t, app, "register", "--daemon-address="+addr, "--btc-pk")
+		require.Empty(t, out.String())
+	})
+
+	t.Run("the flags are completed otherwise", func(t *testing.T) {
+		app, out := completionTestApp()
+
+		runCompletion(t, app, "register", "--pass")
+		require.Equal(t, []string{"--passphrase"}, strings.Fields(out.String()))
+	})
+}
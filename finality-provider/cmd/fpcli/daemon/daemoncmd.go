@@ -1,19 +1,22 @@
 package daemon
 
 import (
-	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 
 	"cosmossdk.io/math"
-	bbntypes "github.com/babylonchain/babylon/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/urfave/cli"
 
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+	"github.com/babylonchain/finality-provider/types"
 )
 
 var (
@@ -24,6 +27,7 @@ var (
 var GetDaemonInfoCmd = cli.Command{
 	Name:      "get-info",
 	ShortName: "gi",
+	Aliases:   []string{"info"},
 	Usage:     "Get information of the running daemon.",
 	Action:    getInfo,
 	Flags: []cli.Flag{
@@ -32,6 +36,7 @@ var GetDaemonInfoCmd = cli.Command{
 			Usage: "The RPC server address of fpd",
 			Value: defaultFpdDaemonAddress,
 		},
+		rpcTimeoutFlag,
 	},
 }
 
@@ -43,7 +48,10 @@ func getInfo(ctx *cli.Context) error {
 	}
 	defer cleanUp()
 
-	info, err := client.GetInfo(context.Background())
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	info, err := client.GetInfo(rpcCtx)
 
 	if err != nil {
 		return err
@@ -57,6 +65,7 @@ func getInfo(ctx *cli.Context) error {
 var CreateFpDaemonCmd = cli.Command{
 	Name:      "create-finality-provider",
 	ShortName: "cfp",
+	Aliases:   []string{"create"},
 	Usage:     "Create a finality provider object and save it in database.",
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -64,6 +73,7 @@ var CreateFpDaemonCmd = cli.Command{
 			Usage: "The RPC server address of fpd",
 			Value: defaultFpdDaemonAddress,
 		},
+		rpcTimeoutFlag,
 		cli.StringFlag{
 			Name:  keyNameFlag,
 			Usage: "The unique name of the finality provider key",
@@ -146,8 +156,11 @@ func createFpDaemon(ctx *cli.Context) error {
 	}
 	defer cleanUp()
 
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
 	info, err := client.CreateFinalityProvider(
-		context.Background(),
+		rpcCtx,
 		keyName,
 		ctx.String(chainIdFlag),
 		ctx.String(passphraseFlag),
@@ -161,6 +174,10 @@ func createFpDaemon(ctx *cli.Context) error {
 	}
 
 	printRespJSON(info.FinalityProvider)
+	if len(info.MonikerCollisions) > 0 {
+		fmt.Fprintf(os.Stderr, "The moniker %q is already used by the registered finality providers %s\n",
+			description.Moniker, strings.Join(info.MonikerCollisions, ", "))
+	}
 
 	return nil
 }
@@ -181,6 +198,7 @@ func getDescriptionFromContext(ctx *cli.Context) (stakingtypes.Description, erro
 var LsFpDaemonCmd = cli.Command{
 	Name:      "list-finality-providers",
 	ShortName: "ls",
+	Aliases:   []string{"list"},
 	Usage:     "List finality providers stored in the database.",
 	Action:    lsFpDaemon,
 	Flags: []cli.Flag{
@@ -189,6 +207,19 @@ var LsFpDaemonCmd = cli.Command{
 			Usage: "The RPC server address of fpd",
 			Value: defaultFpdDaemonAddress,
 		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:  chainPkFlag,
+			Usage: "Only list the finality provider with the given hex string of the chain public key",
+		},
+		cli.StringFlag{
+			Name:  addressFlag,
+			Usage: "Only list the finality provider with the given chain address",
+		},
+		cli.StringFlag{
+			Name:  monikerFlag,
+			Usage: "Only list the finality providers whose moniker contains the given string, ignoring the case",
+		},
 	},
 }
 
@@ -200,7 +231,14 @@ func lsFpDaemon(ctx *cli.Context) error {
 	}
 	defer cleanUp()
 
-	resp, err := rpcClient.QueryFinalityProviderList(context.Background())
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	resp, err := rpcClient.SearchFinalityProviders(rpcCtx, &proto.QueryFinalityProviderListRequest{
+		ChainPk: ctx.String(chainPkFlag),
+		Address: ctx.String(addressFlag),
+		Moniker: ctx.String(monikerFlag),
+	})
 	if err != nil {
 		return err
 	}
@@ -213,20 +251,36 @@ func lsFpDaemon(ctx *cli.Context) error {
 var FpInfoDaemonCmd = cli.Command{
 	Name:      "finality-provider-info",
 	ShortName: "fpi",
+	Aliases:   []string{"show"},
 	Usage:     "Show the information of the finality provider.",
+	Description: `The finality provider is looked up by its BTC public key, or otherwise by its chain public
+	key, chain address or a substring of its moniker, which must match a single finality provider.`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  fpdDaemonAddressFlag,
 			Usage: "The RPC server address of fpd",
 			Value: defaultFpdDaemonAddress,
 		},
+		rpcTimeoutFlag,
 		cli.StringFlag{
-			Name:     fpBTCPkFlag,
-			Usage:    "The hex string of the BTC public key",
-			Required: true,
+			Name:  fpBTCPkFlag,
+			Usage: "The hex string of the BTC public key, in either the BIP-340 or the compressed encoding",
+		},
+		cli.StringFlag{
+			Name:  chainPkFlag,
+			Usage: "The hex string of the chain public key",
+		},
+		cli.StringFlag{
+			Name:  addressFlag,
+			Usage: "The chain address",
+		},
+		cli.StringFlag{
+			Name:  monikerFlag,
+			Usage: "A substring of the moniker, ignoring the case",
 		},
 	},
-	Action: fpInfoDaemon,
+	Action:       fpInfoDaemon,
+	BashComplete: completeBtcPk,
 }
 
 func fpInfoDaemon(ctx *cli.Context) error {
@@ -237,12 +291,15 @@ func fpInfoDaemon(ctx *cli.Context) error {
 	}
 	defer cleanUp()
 
-	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(ctx.String(fpBTCPkFlag))
-	if err != nil {
-		return err
-	}
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
 
-	resp, err := rpcClient.QueryFinalityProviderInfo(context.Background(), fpPk)
+	resp, err := rpcClient.LookupFinalityProvider(rpcCtx, &proto.QueryFinalityProviderRequest{
+		BtcPk:   ctx.String(fpBTCPkFlag),
+		ChainPk: ctx.String(chainPkFlag),
+		Address: ctx.String(addressFlag),
+		Moniker: ctx.String(monikerFlag),
+	})
 	if err != nil {
 		return err
 	}
@@ -255,6 +312,7 @@ func fpInfoDaemon(ctx *cli.Context) error {
 var RegisterFpDaemonCmd = cli.Command{
 	Name:      "register-finality-provider",
 	ShortName: "rfp",
+	Aliases:   []string{"register"},
 	Usage:     "Register a created finality provider to Babylon.",
 	UsageText: fmt.Sprintf("register-finality-provider --%s [btc-pk]", fpBTCPkFlag),
 	Flags: []cli.Flag{
@@ -263,9 +321,10 @@ var RegisterFpDaemonCmd = cli.Command{
 			Usage: "The RPC server address of fpd",
 			Value: defaultFpdDaemonAddress,
 		},
+		rpcTimeoutFlag,
 		cli.StringFlag{
 			Name:     fpBTCPkFlag,
-			Usage:    "The hex string of the finality provider BTC public key",
+			Usage:    "The hex string of the finality provider BTC public key, in either the BIP-340 or the compressed encoding",
 			Required: true,
 		},
 		cli.StringFlag{
@@ -273,13 +332,22 @@ var RegisterFpDaemonCmd = cli.Command{
 			Usage: "The pass phrase used to encrypt the keys",
 			Value: defaultPassphrase,
 		},
+		cli.StringFlag{
+			Name:  approvalTokenFlag,
+			Usage: "The token co-signed by an approver, which is required if fpd runs in approval mode",
+		},
+		cli.Uint64Flag{
+			Name:  activationHeightFlag,
+			Usage: "The height of the consumer chain from which the finality provider starts voting, it starts right away if not set",
+		},
 	},
-	Action: registerFp,
+	Action:       registerFp,
+	BashComplete: completeBtcPk,
 }
 
 func registerFp(ctx *cli.Context) error {
 	fpPkStr := ctx.String(fpBTCPkFlag)
-	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(fpPkStr)
+	fpPk, err := types.ParseBTCPkHex(fpPkStr)
 	if err != nil {
 		return fmt.Errorf("invalid BTC public key: %w", err)
 	}
@@ -291,7 +359,18 @@ func registerFp(ctx *cli.Context) error {
 	}
 	defer cleanUp()
 
-	res, err := rpcClient.RegisterFinalityProvider(context.Background(), fpPk, ctx.String(passphraseFlag))
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	fmt.Fprintln(os.Stderr, "If the registration key of fpd is on a Ledger device, confirm the transaction on the device")
+
+	res, err := rpcClient.RegisterFinalityProvider(
+		rpcCtx,
+		fpPk,
+		ctx.String(passphraseFlag),
+		ctx.String(approvalTokenFlag),
+		ctx.Uint64(activationHeightFlag),
+	)
 	if err != nil {
 		return err
 	}
@@ -306,7 +385,7 @@ func registerFp(ctx *cli.Context) error {
 var AddFinalitySigDaemonCmd = cli.Command{
 	Name:      "add-finality-sig",
 	ShortName: "afs",
-	Usage:     "Send a finality signature to the consumer chain. This command should only be used for presentation/testing purposes",
+	Usage:     "Send a finality signature to the consumer chain. This command should only be used for presentation/testing purposes and requires fpd to be started with --enable-test-rpc",
 	UsageText: fmt.Sprintf("add-finality-sig --%s [btc_pk_hex]", fpBTCPkFlag),
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -314,9 +393,10 @@ var AddFinalitySigDaemonCmd = cli.Command{
 			Usage: "The RPC server address of fpd",
 			Value: defaultFpdDaemonAddress,
 		},
+		rpcTimeoutFlag,
 		cli.StringFlag{
 			Name:     fpBTCPkFlag,
-			Usage:    "The hex string of the BTC public key",
+			Usage:    "The hex string of the BTC public key, in either the BIP-340 or the compressed encoding",
 			Required: true,
 		},
 		cli.Uint64Flag{
@@ -329,8 +409,13 @@ var AddFinalitySigDaemonCmd = cli.Command{
 			Usage: "The last commit hash of the chain block",
 			Value: defaultAppHashStr,
 		},
+		cli.BoolFlag{
+			Name:  forceFlag,
+			Usage: "Sign the block even if it is already voted at the height or conflicts with the chain, which may get the finality provider slashed",
+		},
 	},
-	Action: addFinalitySig,
+	Action:       addFinalitySig,
+	BashComplete: completeBtcPk,
 }
 
 func addFinalitySig(ctx *cli.Context) error {
@@ -341,7 +426,10 @@ func addFinalitySig(ctx *cli.Context) error {
 	}
 	defer cleanUp()
 
-	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(ctx.String(fpBTCPkFlag))
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	fpPk, err := types.ParseBTCPkHex(ctx.String(fpBTCPkFlag))
 	if err != nil {
 		return err
 	}
@@ -352,7 +440,330 @@ func addFinalitySig(ctx *cli.Context) error {
 	}
 
 	res, err := rpcClient.AddFinalitySignature(
-		context.Background(), fpPk.MarshalHex(), ctx.Uint64(blockHeightFlag), appHash)
+		rpcCtx, fpPk.MarshalHex(), ctx.Uint64(blockHeightFlag), appHash, ctx.Bool(forceFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+// SignFinalityDaemonCmd returns a finality signature for an external coordinator to broadcast
+var SignFinalityDaemonCmd = cli.Command{
+	Name:      "sign-finality",
+	ShortName: "sf",
+	Usage:     "Sign a block with a running finality provider without broadcasting the finality signature.",
+	UsageText: fmt.Sprintf("sign-finality --%s [btc_pk_hex] --%s [height] --%s [hash_hex]", fpBTCPkFlag, blockHeightFlag, appHashFlag),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.Uint64Flag{
+			Name:     blockHeightFlag,
+			Usage:    "The height of the chain block",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     appHashFlag,
+			Usage:    "The hex string of the hash of the chain block",
+			Required: true,
+		},
+	},
+	Action:       signFinality,
+	BashComplete: completeBtcPk,
+}
+
+func signFinality(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	fpPk, err := types.ParseBTCPkHex(ctx.String(fpBTCPkFlag))
+	if err != nil {
+		return err
+	}
+
+	blockHash, err := hex.DecodeString(ctx.String(appHashFlag))
+	if err != nil {
+		return err
+	}
+
+	res, err := rpcClient.SignFinality(
+		rpcCtx, fpPk.MarshalHex(), ctx.Uint64(blockHeightFlag), blockHash)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var ValidateStateDaemonCmd = cli.Command{
+	Name:      "validate-state",
+	ShortName: "vs",
+	Usage:     "Check the stored finality providers against the keys in the EOTS manager.",
+	Action:    validateState,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+	},
+}
+
+func validateState(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.ValidateState(rpcCtx)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var ListKeysDaemonCmd = cli.Command{
+	Name:      "list-keys",
+	ShortName: "lk",
+	Usage:     "List the keys in the EOTS manager with the finality providers using them and their usage.",
+	Action:    listKeys,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+	},
+}
+
+func listKeys(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.ListKeys(rpcCtx)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var FinalizedBlocksDaemonCmd = cli.Command{
+	Name:      "finalized-blocks",
+	ShortName: "fb",
+	Usage:     "Show the latest finalized blocks and whether the finality providers in fpd have voted on them.",
+	Action:    queryFinalizedBlocks,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.Uint64Flag{
+			Name:  limitFlag,
+			Usage: "The number of the latest finalized blocks to show (at most 100)",
+			Value: 10,
+		},
+		cli.StringFlag{
+			Name:  chainIdFlag,
+			Usage: "The identifier of the consumer chain, which is the chain in the config of fpd if not set",
+		},
+	},
+}
+
+func queryFinalizedBlocks(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.QueryFinalizedBlocks(rpcCtx, ctx.String(chainIdFlag), ctx.Uint64(limitFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var ExportFinalityProofsDaemonCmd = cli.Command{
+	Name:      "export-finality-proofs",
+	ShortName: "efp",
+	Usage:     "Export the finality signatures and the voting powers of the blocks in a range of heights, for light clients to verify their finality.",
+	Action:    exportFinalityProofs,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.Uint64Flag{
+			Name:     blockHeightFlag,
+			Usage:    "The height of the first block to export",
+			Required: true,
+		},
+		cli.Uint64Flag{
+			Name:  endHeightFlag,
+			Usage: "The height of the last block to export (at most 100 blocks after the first one), which is the first one if not set",
+		},
+		cli.StringFlag{
+			Name:  chainIdFlag,
+			Usage: "The identifier of the consumer chain, which is the chain in the config of fpd if not set",
+		},
+	},
+}
+
+func exportFinalityProofs(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.ExportFinalityProofs(rpcCtx, ctx.String(chainIdFlag), ctx.Uint64(blockHeightFlag), ctx.Uint64(endHeightFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var NetworkParticipationDaemonCmd = cli.Command{
+	Name:      "network-participation",
+	ShortName: "np",
+	Usage:     "Compare the participation of the finality providers in fpd with the whole network (requires fpd in observer mode).",
+	Action:    queryNetworkParticipation,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+	},
+}
+
+func queryNetworkParticipation(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.QueryNetworkParticipation(rpcCtx)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var VotingHistoryDaemonCmd = cli.Command{
+	Name:      "voting-history",
+	ShortName: "vh",
+	Usage:     "Show the finality signatures submitted by fpd for a finality provider with their txs, and its votes and randomness commits accepted by the consumer chain if the vote indexer of fpd is enabled.",
+	Action:    queryVotingHistory,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.Uint64Flag{
+			Name:  fromHeightFlag,
+			Usage: "The lowest height of the submissions, votes and commits to show",
+		},
+		cli.Uint64Flag{
+			Name:  limitFlag,
+			Usage: "The maximum number of each of the submissions, votes and commits to show (at most 1000)",
+			Value: 100,
+		},
+	},
+	BashComplete: completeBtcPk,
+}
+
+func queryVotingHistory(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.QueryVotingHistory(
+		rpcCtx,
+		ctx.String(fpBTCPkFlag),
+		ctx.Uint64(fromHeightFlag),
+		ctx.Uint64(limitFlag),
+	)
 	if err != nil {
 		return err
 	}
@@ -392,3 +803,318 @@ func loadKeyName(ctx *cli.Context) (string, error) {
 	}
 	return keyName, nil
 }
+
+var ResumeFpDaemonCmd = cli.Command{
+	Name:      "resume-finality-provider",
+	ShortName: "rfp",
+	Aliases:   []string{"resume"},
+	Usage:     "Resume a finality provider in safe mode, which it enters after repeated non-retryable submission failures or when paused.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the finality provider BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to unlock the EOTS key",
+			Value: defaultPassphrase,
+		},
+	},
+	Action:       resumeFp,
+	BashComplete: completeBtcPk,
+}
+
+func resumeFp(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.ResumeFinalityProvider(rpcCtx, ctx.String(fpBTCPkFlag), ctx.String(passphraseFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var PauseFpDaemonCmd = cli.Command{
+	Name:      "pause-finality-provider",
+	ShortName: "pfp",
+	Aliases:   []string{"pause"},
+	Usage:     "Stop a running finality provider by switching it to safe mode until it is resumed.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the finality provider BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+	},
+	Action:       pauseFp,
+	BashComplete: completeBtcPk,
+}
+
+func pauseFp(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.PauseFinalityProvider(rpcCtx, ctx.String(fpBTCPkFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var SetRewardAddressDaemonCmd = cli.Command{
+	Name:      "set-reward-address",
+	ShortName: "sra",
+	Usage:     "Set the address receiving the rewards and commission of a finality provider, which can differ from the account paying the fees.",
+	UsageText: fmt.Sprintf("set-reward-address --%s [btc_pk_hex] --%s [bech32_address]", fpBTCPkFlag, rewardAddressFlag),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the finality provider BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     rewardAddressFlag,
+			Usage:    "The bech32 address to receive the rewards",
+			Required: true,
+		},
+	},
+	Action:       setRewardAddress,
+	BashComplete: completeBtcPk,
+}
+
+func setRewardAddress(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.SetRewardAddress(rpcCtx, ctx.String(fpBTCPkFlag), ctx.String(rewardAddressFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var CommitPubRandDaemonCmd = cli.Command{
+	Name:      "commit-pubrand",
+	ShortName: "cpr",
+	Usage:     "Commit the public randomness of a finality provider right away, e.g., before a planned maintenance.",
+	UsageText: fmt.Sprintf("commit-pubrand --%s [btc_pk_hex] [--%s [height]]", fpBTCPkFlag, targetHeightFlag),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the finality provider BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.Uint64Flag{
+			Name:  targetHeightFlag,
+			Usage: "The height up to which the public randomness is committed, 0 means the lookahead of the config",
+		},
+	},
+	Action:       commitPubRand,
+	BashComplete: completeBtcPk,
+}
+
+func commitPubRand(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.CommitPubRand(rpcCtx, ctx.String(fpBTCPkFlag), ctx.Uint64(targetHeightFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var ExportStateDaemonCmd = cli.Command{
+	Name:      "export-state",
+	ShortName: "es",
+	Usage:     "Export the config, the store, and optionally the keys of the daemon into an encrypted archive.",
+	UsageText: "fpcli export-state --output [archive-file] --passphrase [passphrase]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     outputFlag,
+			Usage:    "The file to write the encrypted archive to",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     passphraseFlag,
+			Usage:    "The passphrase used to encrypt the archive",
+			Required: true,
+		},
+		cli.BoolFlag{
+			Name:  includeKeysFlag,
+			Usage: "Include the chain keys, which is only supported by the test and file keyring backends",
+		},
+		cli.StringFlag{
+			Name:  approvalTokenFlag,
+			Usage: "The token co-signed by an approver for the export, which is required if fpd runs in approval mode",
+		},
+	},
+	Action: exportState,
+}
+
+func exportState(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	archive, hash, err := rpcClient.ExportState(
+		rpcCtx, ctx.String(passphraseFlag), ctx.Bool(includeKeysFlag), ctx.String(approvalTokenFlag))
+	if err != nil {
+		return err
+	}
+	if sum := sha256.Sum256(archive); hex.EncodeToString(sum[:]) != hash {
+		return fmt.Errorf("the received archive does not match the SHA-256 hash %s", hash)
+	}
+
+	output := ctx.String(outputFlag)
+	if err := os.WriteFile(output, archive, 0600); err != nil {
+		return fmt.Errorf("failed to write the archive to %s: %w", output, err)
+	}
+
+	printRespJSON(map[string]string{
+		"archive": output,
+		"sha256":  hash,
+	})
+
+	return nil
+}
+
+var ImportStateDaemonCmd = cli.Command{
+	Name:      "import-state",
+	ShortName: "is",
+	Usage:     "Import an encrypted archive exported by export-state, which is restored on the next start of the daemon.",
+	UsageText: "fpcli import-state --input [archive-file] --passphrase [passphrase] --sha256 [hash]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     inputFlag,
+			Usage:    "The file of the encrypted archive",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     passphraseFlag,
+			Usage:    "The passphrase used to encrypt the archive",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  sha256Flag,
+			Usage: "The expected SHA-256 hash of the archive printed by export-state, which is not verified if empty",
+		},
+		cli.StringFlag{
+			Name:  approvalTokenFlag,
+			Usage: "The token co-signed by an approver over the SHA-256 hash of the archive, which is required if fpd runs in approval mode",
+		},
+	},
+	Action: importState,
+}
+
+func importState(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	input := ctx.String(inputFlag)
+	archive, err := os.ReadFile(input)
+	if err != nil {
+		return fmt.Errorf("failed to read the archive from %s: %w", input, err)
+	}
+
+	res, err := rpcClient.ImportState(
+		rpcCtx, archive, ctx.String(passphraseFlag), ctx.String(sha256Flag), ctx.String(approvalTokenFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
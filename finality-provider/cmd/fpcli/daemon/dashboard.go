@@ -0,0 +1,438 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli"
+	"golang.org/x/term"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+	"github.com/babylonchain/finality-provider/util"
+)
+
+const (
+	// enterAltScreen switches to the alternate screen of the terminal and
+	// hides the cursor, which exitAltScreen reverts
+	enterAltScreen = "\033[?1049h\033[?25l"
+	exitAltScreen  = "\033[?25h\033[?1049l"
+	// numRecentVotes is the number of the recent votes of the selected
+	// finality provider shown in the dashboard
+	numRecentVotes = 5
+	// recentVotesRange is the number of heights below the last voted height
+	// searched for the recent votes
+	recentVotesRange = 100
+	// logTailBytes is the size of the tail of the log file read for the logs
+	// shown in the dashboard
+	logTailBytes = 64 * 1024
+	// defaultTermWidth and defaultTermHeight are used if the size of the
+	// terminal is unknown
+	defaultTermWidth  = 120
+	defaultTermHeight = 40
+)
+
+var DashboardDaemonCmd = cli.Command{
+	Name:      "dashboard",
+	ShortName: "db",
+	Usage: "Open an interactive console of the finality providers in fpd with their recent votes, pending txs and logs, " +
+		"from which the finality providers can be paused and resumed.",
+	Action: runDashboard,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "The home path of the finality provider daemon (fpd), whose log file is shown",
+			Value: fpcfg.DefaultFpdDir,
+		},
+		cli.DurationFlag{
+			Name:  intervalFlag,
+			Usage: "The interval between the refreshes of the dashboard",
+			Value: defaultWatchInterval,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to unlock the EOTS keys when resuming the finality providers",
+			Value: defaultPassphrase,
+		},
+	},
+}
+
+// dashboardKey is a key pressed in the dashboard
+type dashboardKey int
+
+const (
+	keyUnknown dashboardKey = iota
+	keyUp
+	keyDown
+	keyPause
+	keyResume
+	keyRefresh
+	keyQuit
+)
+
+// dashboard is the state of the interactive console, which is refreshed from
+// fpd at every interval and redrawn after every refresh or key
+type dashboard struct {
+	rpcClient     *dc.FinalityProviderServiceGRpcClient
+	daemonAddress string
+	logFile       string
+	passphrase    string
+	interval      time.Duration
+	// callTimeout bounds each call to fpd
+	callTimeout time.Duration
+
+	status    *proto.QueryStatusResponse
+	statusErr error
+	votes     []*proto.IndexedVote
+	votesErr  error
+	logs      []string
+	logsErr   error
+	selected  int
+	updatedAt time.Time
+	// message is the result of the last action shown in the footer
+	message string
+}
+
+func runDashboard(ctx *cli.Context) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("the dashboard can only run in a terminal, use the status command instead")
+	}
+
+	interval := ctx.Duration(intervalFlag)
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval %s: it should be positive", interval)
+	}
+
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	d := &dashboard{
+		rpcClient:     rpcClient,
+		daemonAddress: daemonAddress,
+		logFile:       fpcfg.LogFile(util.CleanAndExpandPath(ctx.String(homeFlag))),
+		passphrase:    ctx.String(passphraseFlag),
+		interval:      interval,
+		callTimeout:   callTimeout(ctx, interval),
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer func() {
+		_ = term.Restore(fd, oldState)
+	}()
+	fmt.Fprint(os.Stdout, enterAltScreen)
+	defer fmt.Fprint(os.Stdout, exitAltScreen)
+
+	keys := make(chan dashboardKey)
+	go readDashboardKeys(os.Stdin, keys)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	d.refresh()
+	d.render(os.Stdout)
+	for {
+		select {
+		case <-ticker.C:
+			d.refresh()
+		case k := <-keys:
+			if k == keyQuit {
+				return nil
+			}
+			d.handleKey(k)
+		}
+		d.render(os.Stdout)
+	}
+}
+
+// readDashboardKeys reads the keys from the terminal in raw mode until it
+// fails. The unknown keys are dropped
+func readDashboardKeys(r io.Reader, keys chan<- dashboardKey) {
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			keys <- keyQuit
+			return
+		}
+
+		var k dashboardKey
+		switch in := buf[:n]; {
+		case bytes.Equal(in, []byte("\033[A")), bytes.Equal(in, []byte("k")):
+			k = keyUp
+		case bytes.Equal(in, []byte("\033[B")), bytes.Equal(in, []byte("j")):
+			k = keyDown
+		case bytes.Equal(in, []byte("p")):
+			k = keyPause
+		case bytes.Equal(in, []byte("r")):
+			k = keyResume
+		case bytes.Equal(in, []byte(" ")):
+			k = keyRefresh
+		// Ctrl+C is not turned into a signal in raw mode
+		case bytes.Equal(in, []byte("q")), bytes.Equal(in, []byte{0x03}):
+			k = keyQuit
+		default:
+			continue
+		}
+		keys <- k
+	}
+}
+
+func (d *dashboard) handleKey(k dashboardKey) {
+	switch k {
+	case keyUp:
+		if d.selected > 0 {
+			d.selected--
+			d.refreshVotes()
+		}
+	case keyDown:
+		if d.status != nil && d.selected < len(d.status.FinalityProviders)-1 {
+			d.selected++
+			d.refreshVotes()
+		}
+	case keyPause:
+		d.act("paused", func(ctx context.Context, pkHex string) error {
+			_, err := d.rpcClient.PauseFinalityProvider(ctx, pkHex)
+			return err
+		})
+	case keyResume:
+		d.act("resumed", func(ctx context.Context, pkHex string) error {
+			_, err := d.rpcClient.ResumeFinalityProvider(ctx, pkHex, d.passphrase)
+			return err
+		})
+	case keyRefresh:
+		d.refresh()
+	}
+}
+
+// act runs the action on the selected finality provider and refreshes the
+// dashboard to show its effect
+func (d *dashboard) act(done string, action func(ctx context.Context, pkHex string) error) {
+	st := d.selectedFp()
+	if st == nil {
+		d.message = "no finality provider is selected"
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.callTimeout)
+	err := action(ctx, st.FinalityProvider.BtcPkHex)
+	cancel()
+	if err != nil {
+		d.message = fmt.Sprintf("failed: %v", err)
+	} else {
+		d.message = fmt.Sprintf("%s %s", done, shortPk(st.FinalityProvider.BtcPkHex))
+	}
+
+	d.refresh()
+}
+
+func (d *dashboard) selectedFp() *proto.FinalityProviderStatusInfo {
+	if d.status == nil || d.selected >= len(d.status.FinalityProviders) {
+		return nil
+	}
+
+	return d.status.FinalityProviders[d.selected]
+}
+
+// refresh queries the status of fpd and the recent votes of the selected
+// finality provider, and reads the tail of the log file
+func (d *dashboard) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), d.callTimeout)
+	d.status, d.statusErr = d.rpcClient.QueryStatus(ctx)
+	cancel()
+
+	if d.status != nil && d.selected >= len(d.status.FinalityProviders) {
+		d.selected = max(len(d.status.FinalityProviders)-1, 0)
+	}
+	d.refreshVotes()
+	d.logs, d.logsErr = tailLines(d.logFile, logTailBytes)
+	d.updatedAt = time.Now()
+}
+
+// refreshVotes queries the recent votes of the selected finality provider,
+// which requires the vote indexer of fpd
+func (d *dashboard) refreshVotes() {
+	d.votes, d.votesErr = nil, nil
+	st := d.selectedFp()
+	if st == nil {
+		return
+	}
+
+	var fromHeight uint64
+	if lastVoted := st.FinalityProvider.LastVotedHeight; lastVoted > recentVotesRange {
+		fromHeight = lastVoted - recentVotesRange
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.callTimeout)
+	defer cancel()
+	res, err := d.rpcClient.QueryVotingHistory(ctx, st.FinalityProvider.BtcPkHex, fromHeight, recentVotesRange+1)
+	if err != nil {
+		d.votesErr = err
+		return
+	}
+
+	d.votes = res.Votes
+	if len(d.votes) > numRecentVotes {
+		d.votes = d.votes[len(d.votes)-numRecentVotes:]
+	}
+}
+
+// render redraws the whole dashboard. The lines are cut to the width of the
+// terminal, and the logs fill the rows left
+func (d *dashboard) render(w io.Writer) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = defaultTermWidth, defaultTermHeight
+	}
+
+	var buf bytes.Buffer
+	tip := "unknown"
+	if d.status != nil && d.status.TipHeight > 0 {
+		tip = fmt.Sprintf("%d", d.status.TipHeight)
+	}
+	fmt.Fprintf(&buf, "fpd %s | tip height %s | updated at %s\n\n",
+		d.daemonAddress, tip, d.updatedAt.Format(time.TimeOnly))
+
+	fmt.Fprintln(&buf, "FINALITY PROVIDERS")
+	switch {
+	case d.statusErr != nil:
+		fmt.Fprintf(&buf, "  failed to query the status: %v\n", d.statusErr)
+	case len(d.status.FinalityProviders) == 0:
+		fmt.Fprintln(&buf, "  no finality provider in fpd")
+	default:
+		tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  "+strings.Join(fpStatusHeader, "\t"))
+		for i, st := range d.status.FinalityProviders {
+			marker := "  "
+			if i == d.selected {
+				marker = "> "
+			}
+			fmt.Fprintln(tw, marker+strings.Join(fpStatusRow(st), "\t"))
+		}
+		tw.Flush()
+	}
+
+	if st := d.selectedFp(); st != nil {
+		fmt.Fprintf(&buf, "\nRECENT VOTES of %s\n", shortPk(st.FinalityProvider.BtcPkHex))
+		switch {
+		case d.votesErr != nil:
+			fmt.Fprintf(&buf, "  unavailable: %v\n", d.votesErr)
+		case len(d.votes) == 0:
+			fmt.Fprintln(&buf, "  no vote")
+		default:
+			for _, v := range d.votes {
+				txHash := v.TxHash
+				if txHash == "" {
+					txHash = "-"
+				}
+				fmt.Fprintf(&buf, "  height %d  tx %s\n", v.Height, txHash)
+			}
+		}
+
+		fmt.Fprintf(&buf, "\nPENDING TXS of %s\n", shortPk(st.FinalityProvider.BtcPkHex))
+		if len(st.PendingFinalitySigs) == 0 {
+			fmt.Fprintln(&buf, "  no pending tx")
+		}
+		for _, ps := range st.PendingFinalitySigs {
+			fmt.Fprintf(&buf, "  finality sig at height %d  block %s  tx %s  broadcast at %s\n",
+				ps.Height, shortPk(hex.EncodeToString(ps.BlockHash)), shortPk(hex.EncodeToString(ps.TxHash)),
+				time.Unix(ps.BroadcastTime, 0).Format(time.TimeOnly))
+		}
+	}
+
+	footer := "[up/k] [down/j] select  [p] pause  [r] resume  [space] refresh  [q] quit"
+	if d.message != "" {
+		footer += "  | " + d.message
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	// the logs fill the rows between the panels and the footer
+	lines = append(lines, "", fmt.Sprintf("LOGS of %s", d.logFile))
+	numLogs := height - len(lines) - 2
+	switch {
+	case d.logsErr != nil:
+		lines = append(lines, fmt.Sprintf("  unavailable: %v", d.logsErr))
+	case numLogs > 0:
+		logs := d.logs
+		if len(logs) > numLogs {
+			logs = logs[len(logs)-numLogs:]
+		}
+		for _, l := range logs {
+			lines = append(lines, "  "+l)
+		}
+	}
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer)
+	if len(lines) > height {
+		lines = append(lines[:height-1], footer)
+	}
+
+	var out strings.Builder
+	out.WriteString(clearScreen)
+	for i, l := range lines {
+		if r := []rune(l); len(r) > width {
+			l = string(r[:width])
+		}
+		out.WriteString(l)
+		// the newlines are not translated in raw mode
+		if i < len(lines)-1 {
+			out.WriteString("\r\n")
+		}
+	}
+	fmt.Fprint(w, out.String())
+}
+
+// tailLines returns the complete lines in the last maxBytes of the file
+func tailLines(file string, maxBytes int64) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := max(info.Size()-maxBytes, 0)
+	bz := make([]byte, info.Size()-offset)
+	if _, err := f.ReadAt(bz, offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// the first line may be cut by the offset
+	if offset > 0 {
+		if i := bytes.IndexByte(bz, '\n'); i >= 0 {
+			bz = bz[i+1:]
+		}
+	}
+	bz = bytes.TrimRight(bz, "\n")
+	if len(bz) == 0 {
+		return nil, nil
+	}
+
+	return strings.Split(string(bz), "\n"), nil
+}
@@ -1,17 +1,16 @@
 package daemon
 
 import (
-	"context"
 	"encoding/hex"
 	"fmt"
 
 	"cosmossdk.io/math"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+	fptypes "github.com/babylonchain/finality-provider/types"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	"github.com/cosmos/cosmos-sdk/x/staking/types"
 
-	bbn "github.com/babylonchain/babylon/types"
 	btcstktypes "github.com/babylonchain/babylon/x/btcstaking/types"
 
 	"github.com/urfave/cli"
@@ -29,6 +28,7 @@ type FinalityProviderSigned struct {
 var ExportFinalityProvider = cli.Command{
 	Name:      "export-finality-provider",
 	ShortName: "exfp",
+	Aliases:   []string{"export"},
 	Usage:     "It exports the finality provider by the given BTC public key.",
 	Description: `Fetches the finality provider from the database and exports it
 	by printing the json structure on the stdout.`,
@@ -38,6 +38,7 @@ var ExportFinalityProvider = cli.Command{
 			Usage: "The RPC server address of fpd",
 			Value: defaultFpdDaemonAddress,
 		},
+		rpcTimeoutFlag,
 		cli.StringFlag{
 			Name:     fpBTCPkFlag,
 			Usage:    "The hex string of the BTC public key",
@@ -68,8 +69,13 @@ var ExportFinalityProvider = cli.Command{
 			Usage: "The hd path used to derive the private key",
 			Value: defaultHdPath,
 		},
+		cli.StringFlag{
+			Name:  approvalTokenFlag,
+			Usage: "The token co-signed by an approver for signing the export, which is required if fpd runs in approval mode",
+		},
 	},
-	Action: exportFp,
+	Action:       exportFp,
+	BashComplete: completeBtcPk,
 }
 
 func exportFp(ctx *cli.Context) error {
@@ -80,13 +86,16 @@ func exportFp(ctx *cli.Context) error {
 	}
 	defer cleanUp()
 
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
 	fpBtcPkHex := ctx.String(fpBTCPkFlag)
-	fpPk, err := bbn.NewBIP340PubKeyFromHex(fpBtcPkHex)
+	fpPk, err := fptypes.ParseBTCPkHex(fpBtcPkHex)
 	if err != nil {
 		return fmt.Errorf("invalid fp btc pk hex %s: %w", fpBtcPkHex, err)
 	}
 
-	fpInfoResp, err := client.QueryFinalityProviderInfo(context.Background(), fpPk)
+	fpInfoResp, err := client.QueryFinalityProviderInfo(rpcCtx, fpPk)
 	if err != nil {
 		return fmt.Errorf("failed to query fp info from %s: %w", fpBtcPkHex, err)
 	}
@@ -138,11 +147,12 @@ func exportFp(ctx *cli.Context) error {
 	}
 
 	resp, err := client.SignMessageFromChainKey(
-		context.Background(),
+		rpcCtx,
 		keyName,
 		ctx.String(passphraseFlag),
 		ctx.String(hdPathFlag),
 		fpbz,
+		ctx.String(approvalTokenFlag),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to sign finality provider: %w", err)
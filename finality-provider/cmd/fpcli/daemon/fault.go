@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+)
+
+const (
+	faultKindFlag  = "kind"
+	faultTimesFlag = "times"
+	faultDelayFlag = "delay"
+	faultClearFlag = "clear"
+)
+
+var InjectFaultDaemonCmd = cli.Command{
+	Name:  "inject-fault",
+	Usage: "Inject or clear a fault for chaos testing.",
+	Description: `Injects a fault into fpd to validate its recovery logic, which is one of
+	drop-tx, delay-rpc, and corrupt-store-write. It is only available if fpd is
+	built with the faultinject build tag and must not be used in production.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:  faultKindFlag,
+			Usage: "The kind of the fault (drop-tx, delay-rpc, corrupt-store-write)",
+		},
+		cli.UintFlag{
+			Name:  faultTimesFlag,
+			Usage: "The number of times the fault hits, where 0 means until it is cleared",
+		},
+		cli.DurationFlag{
+			Name:  faultDelayFlag,
+			Usage: "The delay of the requests, only used by the delay-rpc fault",
+		},
+		cli.BoolFlag{
+			Name:  faultClearFlag,
+			Usage: "Clear the fault of the kind instead, or all the faults if the kind is not given",
+		},
+	},
+	Action: injectFault,
+}
+
+func injectFault(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.InjectFault(rpcCtx, &proto.InjectFaultRequest{
+		Kind:    ctx.String(faultKindFlag),
+		Times:   uint32(ctx.Uint(faultTimesFlag)),
+		DelayMs: uint64(ctx.Duration(faultDelayFlag).Milliseconds()),
+		Clear:   ctx.Bool(faultClearFlag),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
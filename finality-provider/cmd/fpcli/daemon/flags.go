@@ -11,6 +11,30 @@ const (
 	hdPathFlag           = "hd-path"
 	chainIdFlag          = "chain-id"
 	signedFlag           = "signed"
+	approvalTokenFlag    = "approval-token"
+	activationHeightFlag = "activation-height"
+	limitFlag            = "limit"
+	fromHeightFlag       = "from-height"
+	forceFlag            = "force"
+	outputFlag           = "output"
+	inputFlag            = "input"
+	includeKeysFlag      = "include-keys"
+	sha256Flag           = "sha256"
+	chainPkFlag          = "chain-pk"
+	addressFlag          = "address"
+	rewardAddressFlag    = "reward-address"
+	watchFlag            = "watch"
+	intervalFlag         = "interval"
+	messageFlag          = "message"
+	signerFlag           = "signer"
+	pubKeyFlag           = "pub-key"
+	signatureFlag        = "signature"
+	challengeFlag        = "challenge"
+	targetHeightFlag     = "target-height"
+	levelFlag            = "level"
+	subsystemFlag        = "subsystem"
+	endHeightFlag        = "end-height"
+	timeoutFlag          = "timeout"
 	defaultPassphrase    = ""
 	defaultHdPath        = ""
 
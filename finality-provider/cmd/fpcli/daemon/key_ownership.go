@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// KeyOwnershipProof is the proof of the ownership of the BTC key of a
+// finality provider handed to a third party
+type KeyOwnershipProof struct {
+	BtcPkHex     string `json:"btc_pk_hex"`
+	Challenge    string `json:"challenge"`
+	Tag          string `json:"tag"`
+	SignatureHex string `json:"signature_hex"`
+}
+
+var ProveKeyOwnershipDaemonCmd = cli.Command{
+	Name:      "prove-key-ownership",
+	ShortName: "pko",
+	Usage:     "Sign a challenge with the BTC key of a finality provider to prove the ownership of the key.",
+	UsageText: fmt.Sprintf("prove-key-ownership --%s [btc_pk_hex] --%s [challenge]", fpBTCPkFlag, challengeFlag),
+	Description: `The signature is a BIP-340 Schnorr signature over a tagged hash of the key and the
+	challenge, so it cannot be used as the proof-of-possession or any other signature of
+	the key, whatever the challenge. The proof is verified through verify-key-ownership.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     challengeFlag,
+			Usage:    "The challenge supplied by the third party",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to decrypt the EOTS key",
+			Value: defaultPassphrase,
+		},
+	},
+	Action:       proveKeyOwnership,
+	BashComplete: completeBtcPk,
+}
+
+func proveKeyOwnership(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	fpPk, err := types.ParseBTCPkHex(ctx.String(fpBTCPkFlag))
+	if err != nil {
+		return err
+	}
+
+	challenge := ctx.String(challengeFlag)
+	res, err := rpcClient.ProveKeyOwnership(
+		rpcCtx, fpPk.MarshalHex(), []byte(challenge), ctx.String(passphraseFlag))
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(KeyOwnershipProof{
+		BtcPkHex:     res.BtcPkHex,
+		Challenge:    challenge,
+		Tag:          res.Tag,
+		SignatureHex: hex.EncodeToString(res.Signature),
+	})
+
+	return nil
+}
+
+var VerifyKeyOwnershipCmd = cli.Command{
+	Name:      "verify-key-ownership",
+	ShortName: "vko",
+	Usage:     "Verify a proof of the ownership of a BTC key, which does not require fpd.",
+	UsageText: fmt.Sprintf("verify-key-ownership --%s [btc_pk_hex] --%s [challenge] --%s [signature_hex]",
+		fpBTCPkFlag, challengeFlag, signatureFlag),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     challengeFlag,
+			Usage:    "The challenge that is signed",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     signatureFlag,
+			Usage:    "The hex string of the signature",
+			Required: true,
+		},
+	},
+	Action: verifyKeyOwnership,
+}
+
+func verifyKeyOwnership(ctx *cli.Context) error {
+	fpPk, err := types.ParseBTCPkHex(ctx.String(fpBTCPkFlag))
+	if err != nil {
+		return err
+	}
+
+	sig, err := hex.DecodeString(ctx.String(signatureFlag))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	if err := types.VerifyKeyOwnershipProof(fpPk.MustToBTCPK(), []byte(ctx.String(challengeFlag)), sig); err != nil {
+		return err
+	}
+
+	fmt.Printf("the proof of the ownership of %s is valid\n", fpPk.MarshalHex())
+
+	return nil
+}
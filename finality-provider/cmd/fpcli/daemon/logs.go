@@ -0,0 +1,83 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+)
+
+var TailLogsDaemonCmd = cli.Command{
+	Name:      "tail-logs",
+	ShortName: "tl",
+	Usage:     "Stream the live logs of fpd until interrupted, without shell access to its host.",
+	UsageText: fmt.Sprintf("tail-logs [--%s debug] [--%s fp.poller]", levelFlag, subsystemFlag),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:  levelFlag,
+			Usage: "The minimum level of the logs, i.e., debug, info, warn or error, regardless of the log level of fpd",
+			Value: "info",
+		},
+		cli.StringFlag{
+			Name:  subsystemFlag,
+			Usage: "Only stream the logs of the subsystem and the ones nested in it, e.g., fp, fp.poller, chain, observer or indexer",
+		},
+	},
+	Action: tailLogs,
+}
+
+func tailLogs(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	// the logs are streamed until interrupted, or for the timeout if set
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	err = rpcClient.TailLogs(rpcCtx, ctx.String(levelFlag), ctx.String(subsystemFlag), func(entry *proto.TailLogsResponse) error {
+		fmt.Fprintln(os.Stdout, formatLogEntry(entry))
+		return nil
+	})
+	// the stream is cancelled once interrupted or timed out
+	if err != nil && rpcCtx.Err() == nil {
+		return err
+	}
+
+	return nil
+}
+
+// formatLogEntry formats the entry as a tab-separated line like the console
+// logs of fpd
+func formatLogEntry(entry *proto.TailLogsResponse) string {
+	var sb strings.Builder
+	if entry.Dropped > 0 {
+		fmt.Fprintf(&sb, "... %d entries dropped as the stream could not keep up\n", entry.Dropped)
+	}
+
+	sb.WriteString(time.Unix(0, entry.Timestamp).UTC().Format("2006-01-02T15:04:05.000000Z07:00"))
+	sb.WriteString("\t" + entry.Level)
+	if entry.Subsystem != "" {
+		sb.WriteString("\t" + entry.Subsystem)
+	}
+	sb.WriteString("\t" + entry.Message)
+	if entry.Fields != "" {
+		sb.WriteString("\t" + entry.Fields)
+	}
+
+	return sb.String()
+}
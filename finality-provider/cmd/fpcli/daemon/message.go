@@ -0,0 +1,157 @@
+package daemon
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/urfave/cli"
+
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+var SignMessageDaemonCmd = cli.Command{
+	Name:      "sign-message",
+	ShortName: "sm",
+	Usage:     "Sign an arbitrary message with the chain key of a finality provider to prove the control of its account.",
+	UsageText: fmt.Sprintf("sign-message --%s [btc_pk_hex] --%s [message]", fpBTCPkFlag, messageFlag),
+	Description: `The message is signed as specified by ADR-36, i.e., the signature is over a sign doc
+	that can never be broadcast as a tx, and can be verified by the same tools as the
+	signatures of the wallets, e.g., Keplr. The public key and the signature are encoded
+	in base64.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     fpBTCPkFlag,
+			Usage:    "The hex string of the BTC public key, in either the BIP-340 or the compressed encoding",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     messageFlag,
+			Usage:    "The message to sign",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  passphraseFlag,
+			Usage: "The pass phrase used to decrypt the private key",
+			Value: defaultPassphrase,
+		},
+		cli.StringFlag{
+			Name:  hdPathFlag,
+			Usage: "The hd path used to derive the private key",
+			Value: defaultHdPath,
+		},
+	},
+	Action:       signMessage,
+	BashComplete: completeBtcPk,
+}
+
+func signMessage(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	fpPk, err := types.ParseBTCPkHex(ctx.String(fpBTCPkFlag))
+	if err != nil {
+		return err
+	}
+
+	res, err := rpcClient.SignMessage(
+		rpcCtx,
+		fpPk.MarshalHex(),
+		ctx.String(passphraseFlag),
+		ctx.String(hdPathFlag),
+		[]byte(ctx.String(messageFlag)),
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
+
+var VerifyMessageDaemonCmd = cli.Command{
+	Name:      "verify-message",
+	ShortName: "vm",
+	Usage:     "Verify an ADR-36 signature of an arbitrary message.",
+	UsageText: fmt.Sprintf("verify-message --%s [bech32_address] --%s [base64] --%s [base64] --%s [message]",
+		signerFlag, pubKeyFlag, signatureFlag, messageFlag),
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.StringFlag{
+			Name:     signerFlag,
+			Usage:    "The bech32 address of the signer",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     pubKeyFlag,
+			Usage:    "The base64 of the compressed secp256k1 public key of the signer",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     signatureFlag,
+			Usage:    "The base64 of the signature",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     messageFlag,
+			Usage:    "The signed message",
+			Required: true,
+		},
+	},
+	Action: verifyMessage,
+}
+
+func verifyMessage(ctx *cli.Context) error {
+	pubKey, err := base64.StdEncoding.DecodeString(ctx.String(pubKeyFlag))
+	if err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(ctx.String(signatureFlag))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	rpcCtx, cancel := rpcContext(ctx)
+	defer cancel()
+
+	res, err := rpcClient.VerifyMessage(
+		rpcCtx,
+		ctx.String(signerFlag),
+		pubKey,
+		[]byte(ctx.String(messageFlag)),
+		sig,
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+
+	return nil
+}
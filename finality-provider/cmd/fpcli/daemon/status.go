@@ -0,0 +1,182 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+)
+
+const (
+	defaultWatchInterval = 5 * time.Second
+	// clearScreen moves the cursor home and clears the terminal
+	clearScreen = "\033[H\033[2J"
+	// shortPkLen is the number of hex characters of the BTC public keys shown
+	// in the watch view
+	shortPkLen = 16
+)
+
+var StatusDaemonCmd = cli.Command{
+	Name:      "status",
+	ShortName: "st",
+	Usage:     "Show the heights, status, randomness runway, and balance of all the finality providers in fpd.",
+	UsageText: fmt.Sprintf("status [--%s] [--%s 5s]", watchFlag, intervalFlag),
+	Action:    queryStatus,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  fpdDaemonAddressFlag,
+			Usage: "The RPC server address of fpd",
+			Value: defaultFpdDaemonAddress,
+		},
+		rpcTimeoutFlag,
+		cli.BoolFlag{
+			Name:  watchFlag,
+			Usage: "Keep polling fpd and render a live-updating view until interrupted",
+		},
+		cli.DurationFlag{
+			Name:  intervalFlag,
+			Usage: "The interval between the polls in watch mode",
+			Value: defaultWatchInterval,
+		},
+	},
+}
+
+func queryStatus(ctx *cli.Context) error {
+	daemonAddress := ctx.String(fpdDaemonAddressFlag)
+	rpcClient, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(daemonAddress)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	if !ctx.Bool(watchFlag) {
+		rpcCtx, cancel := rpcContext(ctx)
+		defer cancel()
+
+		res, err := rpcClient.QueryStatus(rpcCtx)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(res)
+
+		return nil
+	}
+
+	interval := ctx.Duration(intervalFlag)
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval %s: it should be positive", interval)
+	}
+
+	return watchStatus(rpcClient, daemonAddress, interval, callTimeout(ctx, interval))
+}
+
+// watchStatus polls the status at every interval and redraws it until
+// interrupted. A failed poll is shown in place of the view, so that the watch
+// survives the restarts of fpd. Each poll is bounded by the poll timeout
+func watchStatus(rpcClient *dc.FinalityProviderServiceGRpcClient, daemonAddress string, interval, pollTimeout time.Duration) error {
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pollCtx, cancel := context.WithTimeout(sigCtx, pollTimeout)
+		res, err := rpcClient.QueryStatus(pollCtx)
+		cancel()
+
+		fmt.Fprint(os.Stdout, clearScreen)
+		fmt.Fprintf(os.Stdout, "fpd %s, every %s, updated at %s (Ctrl+C to quit)\n\n",
+			daemonAddress, interval, time.Now().Format(time.TimeOnly))
+		if err != nil {
+			fmt.Fprintf(os.Stdout, "failed to query the status: %v\n", err)
+		} else {
+			renderStatus(os.Stdout, res)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-sigCtx.Done():
+			fmt.Fprintln(os.Stdout)
+			return nil
+		}
+	}
+}
+
+// renderStatus writes the status as a table of the finality providers
+func renderStatus(w io.Writer, res *proto.QueryStatusResponse) {
+	if res.TipHeight == 0 {
+		fmt.Fprintf(w, "tip height: unknown\n\n")
+	} else {
+		fmt.Fprintf(w, "tip height: %d\n\n", res.TipHeight)
+	}
+
+	if len(res.FinalityProviders) == 0 {
+		fmt.Fprintln(w, "no finality provider in fpd")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(fpStatusHeader, "\t"))
+	for _, st := range res.FinalityProviders {
+		fmt.Fprintln(tw, strings.Join(fpStatusRow(st), "\t"))
+	}
+	tw.Flush()
+}
+
+// fpStatusHeader is the header of the columns of fpStatusRow
+var fpStatusHeader = []string{
+	"MONIKER", "BTC PK", "STATUS", "RUNNING", "LAST VOTED", "LAST PROCESSED", "FINALIZED", "RAND RUNWAY", "BALANCE",
+}
+
+// fpStatusRow returns the columns of the status of a finality provider shown
+// in the watch view and the dashboard
+func fpStatusRow(st *proto.FinalityProviderStatusInfo) []string {
+	fp := st.FinalityProvider
+	var moniker string
+	if fp.Description != nil {
+		moniker = fp.Description.Moniker
+	}
+
+	runway := "-"
+	if st.LastCommittedRandHeight > 0 {
+		runway = strconv.FormatUint(st.RandRunway, 10)
+	}
+
+	balance := st.Balance
+	if balance == "" {
+		balance = "-"
+	}
+
+	return []string{
+		moniker,
+		shortPk(fp.BtcPkHex),
+		fp.Status,
+		strconv.FormatBool(fp.IsRunning),
+		strconv.FormatUint(fp.LastVotedHeight, 10),
+		strconv.FormatUint(st.LastProcessedHeight, 10),
+		strconv.FormatUint(fp.FinalizedHeight, 10),
+		runway,
+		balance,
+	}
+}
+
+func shortPk(pkHex string) string {
+	if len(pkHex) <= shortPkLen {
+		return pkHex
+	}
+
+	return pkHex[:shortPkLen] + "..."
+}
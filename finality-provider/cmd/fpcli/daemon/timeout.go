@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli"
+)
+
+// rpcTimeoutFlag is shared by the commands calling fpd, so that the scripts
+// running them do not hang forever once fpd is wedged
+var rpcTimeoutFlag = cli.DurationFlag{
+	Name: timeoutFlag,
+	Usage: "The time to wait for fpd to respond before giving up, e.g., 30s, where 0 waits until interrupted. " +
+		"The commands running until interrupted bound each call to fpd instead",
+}
+
+// rpcContext returns the context of the calls of the command to fpd, which is
+// cancelled once the command is interrupted or its timeout expires
+func rpcContext(ctx *cli.Context) (context.Context, context.CancelFunc) {
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	timeout := ctx.Duration(timeoutFlag)
+	if timeout <= 0 {
+		return sigCtx, stop
+	}
+
+	rpcCtx, cancel := context.WithTimeout(sigCtx, timeout)
+	return rpcCtx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// callTimeout returns the time each call to fpd is bounded by in the commands
+// running until interrupted, which is the timeout if set or the fallback
+func callTimeout(ctx *cli.Context, fallback time.Duration) time.Duration {
+	if timeout := ctx.Duration(timeoutFlag); timeout > 0 {
+		return timeout
+	}
+
+	return fallback
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli"
+)
+
+func timeoutTestContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	rpcTimeoutFlag.Apply(set)
+	require.NoError(t, set.Parse(args))
+
+	return cli.NewContext(cli.NewApp(), set, nil)
+}
+
+func TestRPCContext(t *testing.T) {
+	t.Run("the context has no deadline without a timeout", func(t *testing.T) {
+		rpcCtx, cancel := rpcContext(timeoutTestContext(t))
+		_, ok := rpcCtx.Deadline()
+		require.False(t, ok)
+
+		cancel()
+		require.ErrorIs(t, rpcCtx.Err(), context.Canceled)
+	})
+
+	t.Run("the context expires once the timeout is reached", func(t *testing.T) {
+		rpcCtx, cancel := rpcContext(timeoutTestContext(t, "--"+timeoutFlag, "10ms"))
+		defer cancel()
+		_, ok := rpcCtx.Deadline()
+		require.True(t, ok)
+
+		select {
+		case <-rpcCtx.Done():
+			require.ErrorIs(t, rpcCtx.Err(), context.DeadlineExceeded)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the context did not expire")
+		}
+	})
+}
+
+func TestCallTimeout(t *testing.T) {
+	fallback := time.Minute
+
+	require.Equal(t, fallback, callTimeout(timeoutTestContext(t), fallback))
+	require.Equal(t, fallback, callTimeout(timeoutTestContext(t, "--"+timeoutFlag, "0s"), fallback))
+	require.Equal(t, 5*time.Second, callTimeout(timeoutTestContext(t, "--"+timeoutFlag, "5s"), fallback))
+}
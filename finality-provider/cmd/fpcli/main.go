@@ -17,6 +17,7 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "fpcli"
 	app.Usage = "Control plane for the Finality Provider Daemon (fpd)."
+	app.EnableBashCompletion = true
 
 	app.Commands = append(app.Commands,
 		dcli.GetDaemonInfoCmd,
@@ -26,6 +27,29 @@ func main() {
 		dcli.RegisterFpDaemonCmd,
 		dcli.AddFinalitySigDaemonCmd,
 		dcli.ExportFinalityProvider,
+		dcli.ValidateStateDaemonCmd,
+		dcli.ListKeysDaemonCmd,
+		dcli.SignFinalityDaemonCmd,
+		dcli.SignMessageDaemonCmd,
+		dcli.VerifyMessageDaemonCmd,
+		dcli.ProveKeyOwnershipDaemonCmd,
+		dcli.VerifyKeyOwnershipCmd,
+		dcli.FinalizedBlocksDaemonCmd,
+		dcli.ExportFinalityProofsDaemonCmd,
+		dcli.NetworkParticipationDaemonCmd,
+		dcli.StatusDaemonCmd,
+		dcli.DashboardDaemonCmd,
+		dcli.VotingHistoryDaemonCmd,
+		dcli.ResumeFpDaemonCmd,
+		dcli.PauseFpDaemonCmd,
+		dcli.SetRewardAddressDaemonCmd,
+		dcli.CommitPubRandDaemonCmd,
+		dcli.TailLogsDaemonCmd,
+		dcli.InjectFaultDaemonCmd,
+		dcli.ExportStateDaemonCmd,
+		dcli.ImportStateDaemonCmd,
+		dcli.CreateApprovalCmd,
+		dcli.CompletionCmd,
 	)
 
 	if err := app.Run(os.Args); err != nil {
@@ -3,18 +3,30 @@ package daemon
 import "github.com/cosmos/cosmos-sdk/crypto/keyring"
 
 const (
-	homeFlag           = "home"
-	forceFlag          = "force"
-	passphraseFlag     = "passphrase"
-	fpPkFlag           = "btc-pk"
-	keyNameFlag        = "key-name"
-	hdPathFlag         = "hd-path"
-	chainIdFlag        = "chain-id"
-	keyringBackendFlag = "keyring-backend"
-	rpcListenerFlag    = "rpc-listener"
-	recoverFlag        = "recover"
+	homeFlag                = "home"
+	forceFlag               = "force"
+	passphraseFlag          = "passphrase"
+	fpPkFlag                = "btc-pk"
+	keyNameFlag             = "key-name"
+	hdPathFlag              = "hd-path"
+	chainIdFlag             = "chain-id"
+	keyringBackendFlag      = "keyring-backend"
+	rpcListenerFlag         = "rpc-listener"
+	recoverFlag             = "recover"
+	requireRemoteSignerFlag = "require-remote-signer"
+	reRegisterFlag          = "re-register"
+	genesisFileFlag         = "genesis"
+	watchOnlyFlag           = "watch-only"
+	enableTestRPCFlag       = "enable-test-rpc"
+	autoCorrectStartFlag    = "auto-correct-start-height"
+	yesFlag                 = "yes"
+	ledgerFlag              = "ledger"
+	accountFlag             = "account"
+	indexFlag               = "index"
+	accountPrefixFlag       = "acc-prefix"
 
 	defaultKeyringBackend = keyring.BackendTest
 	defaultHdPath         = ""
 	defaultPassphrase     = ""
+	defaultAccountPrefix  = "bbn"
 )
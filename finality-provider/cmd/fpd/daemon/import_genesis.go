@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/service"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/util"
+)
+
+var ImportGenesisCommand = cli.Command{
+	Name:  "import-genesis",
+	Usage: "Import the registered finality providers from a genesis or a snapshot of the consumer chain.",
+	Description: `Pre-populates the local store with the finality providers registered in a genesis,
+	a snapshot of the form {"finality_providers": [...]}, or a list of finality providers.
+	With --watch-only, all of them are tracked without being run. Otherwise, only the finality
+	providers whose EOTS keys are held by the EOTS manager are imported to be run by the daemon.
+	The daemon should not be running during the import.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "The path to the finality-provider home directory",
+			Value: fpcfg.DefaultFpdDir,
+		},
+		cli.StringFlag{
+			Name:     genesisFileFlag,
+			Usage:    "The path to the genesis or snapshot file",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:  keyNameFlag,
+			Usage: "The name of the chain key of the imported finality providers, which is the key in the config if not set",
+		},
+		cli.StringFlag{
+			Name:  chainIdFlag,
+			Usage: "The identifier of the consumer chain, which is the chain ID in the config if not set",
+		},
+		cli.BoolFlag{
+			Name:  watchOnlyFlag,
+			Usage: "Import the finality providers to be tracked without being run, which requires no EOTS keys",
+		},
+	},
+	Action: importGenesis,
+}
+
+func importGenesis(ctx *cli.Context) error {
+	homePath, err := filepath.Abs(ctx.String(homeFlag))
+	if err != nil {
+		return err
+	}
+	homePath = util.CleanAndExpandPath(homePath)
+
+	cfg, err := fpcfg.LoadConfig(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	genesis, err := os.ReadFile(ctx.String(genesisFileFlag))
+	if err != nil {
+		return fmt.Errorf("failed to read the genesis: %w", err)
+	}
+
+	keyName := ctx.String(keyNameFlag)
+	if keyName == "" {
+		keyName = cfg.BabylonConfig.Key
+	}
+	chainID := ctx.String(chainIdFlag)
+	if chainID == "" {
+		chainID = cfg.BabylonConfig.ChainID
+	}
+	watchOnly := ctx.Bool(watchOnlyFlag)
+
+	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	if err != nil {
+		return fmt.Errorf("failed to create db backend: %w", err)
+	}
+	defer dbBackend.Close()
+
+	fps, err := store.NewFinalityProviderStore(dbBackend)
+	if err != nil {
+		return fmt.Errorf("failed to initiate finality provider store: %w", err)
+	}
+
+	// the EOTS keys are only required for finality providers to be run
+	var em eotsmanager.EOTSManager
+	if !watchOnly {
+		emClient, err := service.NewEOTSManagerClientFromConfig(cfg)
+		if err != nil {
+			return err
+		}
+		defer emClient.Close()
+		em = emClient
+	}
+
+	res, err := service.ImportGenesisFinalityProviders(fps, em, genesis, keyName, chainID, watchOnly)
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, err := json.MarshalIndent(res, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+
+	return nil
+}
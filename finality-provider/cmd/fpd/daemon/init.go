@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/jessevdk/go-flags"
 	"github.com/urfave/cli"
 
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
@@ -52,7 +51,5 @@ func initHome(c *cli.Context) error {
 	}
 
 	defaultConfig := fpcfg.DefaultConfigWithHome(homePath)
-	fileParser := flags.NewParser(&defaultConfig, flags.Default)
-
-	return flags.NewIniParser(fileParser).WriteFile(fpcfg.ConfigFile(homePath), flags.IniIncludeComments|flags.IniIncludeDefaults)
+	return fpcfg.WriteConfigFile(&defaultConfig, homePath)
 }
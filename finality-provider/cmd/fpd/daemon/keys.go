@@ -9,7 +9,6 @@ import (
 
 	"github.com/cosmos/cosmos-sdk/client/input"
 	"github.com/cosmos/go-bip39"
-	"github.com/jessevdk/go-flags"
 	"github.com/urfave/cli"
 
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
@@ -70,6 +69,24 @@ var AddKeyCmd = cli.Command{
 			Name:  recoverFlag,
 			Usage: "Provide seed phrase to recover existing key instead of creating",
 		},
+		cli.BoolFlag{
+			Name: ledgerFlag,
+			Usage: "Store a reference to a key on the Ledger device connected, which becomes the registration key " +
+				"as it can only sign transactions (the binary must be built with the ledger build tag)",
+		},
+		cli.UintFlag{
+			Name:  accountFlag,
+			Usage: "The account number of the key on the Ledger device",
+		},
+		cli.UintFlag{
+			Name:  indexFlag,
+			Usage: "The address index of the key on the Ledger device",
+		},
+		cli.StringFlag{
+			Name:  accountPrefixFlag,
+			Usage: "The prefix of the address of the key on the Ledger device",
+			Value: defaultAccountPrefix,
+		},
 	},
 	Action: addKey,
 }
@@ -83,6 +100,10 @@ func addKey(ctx *cli.Context) error {
 	hdPath := ctx.String(hdPathFlag)
 	keyBackend := ctx.String(keyringBackendFlag)
 
+	if ctx.Bool(ledgerFlag) {
+		return addLedgerKey(ctx)
+	}
+
 	var (
 		mnemonic string
 		err      error
@@ -129,9 +150,53 @@ func addKey(ctx *cli.Context) error {
 	// write the updated config into the config file
 	cfg.BabylonConfig.Key = keyName
 	cfg.BabylonConfig.KeyringBackend = keyBackend
-	fileParser := flags.NewParser(cfg, flags.Default)
+	return fpcfg.WriteConfigFile(cfg, homePath)
+}
+
+// addLedgerKey stores a reference to a key on the Ledger device, which is set
+// as the registration key in the config file as the Ledger devices cannot sign
+// the proof of possession and the finality votes
+func addLedgerKey(ctx *cli.Context) error {
+	homePath := ctx.String(homeFlag)
+	keyName := ctx.String(keyNameFlag)
+	keyBackend := ctx.String(keyringBackendFlag)
+
+	if ctx.Bool(recoverFlag) {
+		return fmt.Errorf("--%s cannot be used with --%s as the key is derived on the device", recoverFlag, ledgerFlag)
+	}
 
-	return flags.NewIniParser(fileParser).WriteFile(fpcfg.ConfigFile(homePath), flags.IniIncludeComments|flags.IniIncludeDefaults)
+	fmt.Println("Confirm the address on the Ledger device...")
+
+	keyInfo, err := service.CreateLedgerChainKey(
+		homePath,
+		ctx.String(chainIdFlag),
+		keyName,
+		keyBackend,
+		ctx.String(accountPrefixFlag),
+		uint32(ctx.Uint(accountFlag)),
+		uint32(ctx.Uint(indexFlag)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add the Ledger key: %w", err)
+	}
+
+	jsonBytes, err := json.MarshalIndent(KeyOutput{
+		Name:    keyName,
+		Address: keyInfo.AccAddress.String(),
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("New key on the Ledger device is added:\n%s\n", jsonBytes)
+
+	cfg, err := fpcfg.LoadConfig(homePath)
+	if err != nil {
+		return nil // config does not exist, so does not update it
+	}
+
+	cfg.BabylonConfig.RegistrationKey = keyName
+	cfg.BabylonConfig.KeyringBackend = keyBackend
+	return fpcfg.WriteConfigFile(cfg, homePath)
 }
 
 func printRespJSON(resp interface{}) {
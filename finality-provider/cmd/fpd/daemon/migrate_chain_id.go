@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/babylonchain/babylon/types"
+	"github.com/urfave/cli"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/util"
+)
+
+var MigrateChainIDCommand = cli.Command{
+	Name:  "migrate-chain-id",
+	Usage: "Move a finality provider to a new chain ID after an upgrade of the consumer chain changing its ID.",
+	Description: `Moves the local record of the finality provider to the new chain ID, keeping its keys.
+	The heights processed and the blocks signed on the old chain are forgotten and the finality
+	provider is brought back to the CREATED status, so that it is registered to the chain with the
+	new ID. The operator is asked to confirm the migration by typing the new chain ID, unless --yes
+	is set. The daemon should not be running during the migration.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "The path to the finality-provider home directory",
+			Value: fpcfg.DefaultFpdDir,
+		},
+		cli.StringFlag{
+			Name:     fpPkFlag,
+			Usage:    "The hex string of the BTC public key of the finality provider to migrate",
+			Required: true,
+		},
+		cli.StringFlag{
+			Name:     chainIdFlag,
+			Usage:    "The new identifier of the consumer chain",
+			Required: true,
+		},
+		cli.BoolFlag{
+			Name:  yesFlag,
+			Usage: "Skip the confirmation of the migration",
+		},
+	},
+	Action: migrateChainID,
+}
+
+type migrateChainIDResult struct {
+	BtcPk           string `json:"btc_pk_hex"`
+	OldChainID      string `json:"old_chain_id"`
+	NewChainID      string `json:"new_chain_id"`
+	LastVotedHeight uint64 `json:"last_voted_height_on_old_chain"`
+}
+
+func migrateChainID(ctx *cli.Context) error {
+	homePath, err := filepath.Abs(ctx.String(homeFlag))
+	if err != nil {
+		return err
+	}
+	homePath = util.CleanAndExpandPath(homePath)
+
+	cfg, err := fpcfg.LoadConfig(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	fpPk, err := types.NewBIP340PubKeyFromHex(ctx.String(fpPkFlag))
+	if err != nil {
+		return err
+	}
+	newChainID := strings.TrimSpace(ctx.String(chainIdFlag))
+	if newChainID == "" {
+		return fmt.Errorf("the new chain ID should not be empty")
+	}
+
+	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	if err != nil {
+		return fmt.Errorf("failed to create db backend: %w", err)
+	}
+	defer dbBackend.Close()
+
+	fps, err := store.NewFinalityProviderStore(dbBackend)
+	if err != nil {
+		return fmt.Errorf("failed to initiate finality provider store: %w", err)
+	}
+
+	storedFp, err := fps.GetFinalityProvider(fpPk.MustToBTCPK())
+	if err != nil {
+		return fmt.Errorf("failed to get the finality provider %s: %w", fpPk.MarshalHex(), err)
+	}
+	if storedFp.ChainID == newChainID {
+		return fmt.Errorf("the finality provider %s is already on the chain %s", fpPk.MarshalHex(), newChainID)
+	}
+
+	if !ctx.Bool(yesFlag) {
+		if err := confirmChainIDMigration(storedFp, newChainID); err != nil {
+			return err
+		}
+	}
+
+	if err := fps.MigrateFinalityProviderChainID(storedFp.BtcPk, newChainID); err != nil {
+		return fmt.Errorf("failed to migrate the finality provider %s: %w", fpPk.MarshalHex(), err)
+	}
+
+	jsonBytes, err := json.MarshalIndent(&migrateChainIDResult{
+		BtcPk:           fpPk.MarshalHex(),
+		OldChainID:      storedFp.ChainID,
+		NewChainID:      newChainID,
+		LastVotedHeight: storedFp.LastVotedHeight,
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(jsonBytes))
+
+	if cfg.BabylonConfig.ChainID != newChainID {
+		fmt.Fprintf(os.Stderr, "the chain ID in the config is %s, which should be changed to %s before the daemon is started\n",
+			cfg.BabylonConfig.ChainID, newChainID)
+	}
+
+	return nil
+}
+
+// confirmChainIDMigration asks the operator to type the new chain ID to
+// confirm the migration, as the state on the old chain is forgotten
+func confirmChainIDMigration(fp *store.StoredFinalityProvider, newChainID string) error {
+	fmt.Fprintf(os.Stderr, "The finality provider %s will be moved from the chain %s to the chain %s.\n",
+		fp.GetBIP340BTCPK().MarshalHex(), fp.ChainID, newChainID)
+	fmt.Fprintf(os.Stderr, "Its status %s and the last voted height %d on the old chain will be reset, and it will have to be registered again.\n",
+		fp.Status.String(), fp.LastVotedHeight)
+	fmt.Fprintf(os.Stderr, "Type the new chain ID to confirm: ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to read the confirmation: %w", err)
+	}
+	if strings.TrimSpace(answer) != newChainID {
+		return fmt.Errorf("the migration is not confirmed")
+	}
+
+	return nil
+}
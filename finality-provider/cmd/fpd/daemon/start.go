@@ -13,6 +13,7 @@ import (
 
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
 	"github.com/babylonchain/finality-provider/finality-provider/service"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
 	"github.com/babylonchain/finality-provider/log"
 	"github.com/babylonchain/finality-provider/util"
 )
@@ -40,6 +41,23 @@ var StartCommand = cli.Command{
 			Name:  rpcListenerFlag,
 			Usage: "The address that the RPC server listens to",
 		},
+		cli.BoolFlag{
+			Name:  requireRemoteSignerFlag,
+			Usage: "Require a remote EOTS manager and never load EOTS private keys within the daemon",
+		},
+		cli.BoolFlag{
+			Name: reRegisterFlag,
+			Usage: "Reset and register again the finality providers if the consumer chain is reset with the same chain ID, " +
+				"which is only meant for devnets; eotsd refuses to sign again the heights signed before the reset unless allowdoublesign is set",
+		},
+		cli.BoolFlag{
+			Name:  enableTestRPCFlag,
+			Usage: "Serve the RPC paths meant for presentation and testing, which can expose private keys and must never be used in production",
+		},
+		cli.BoolFlag{
+			Name:  autoCorrectStartFlag,
+			Usage: "Raise a static start height below the last voted height or the activation height of a finality provider instead of failing to start",
+		},
 	},
 	Action: start,
 }
@@ -52,6 +70,21 @@ func start(ctx *cli.Context) error {
 	homePath = util.CleanAndExpandPath(homePath)
 	rpcListener := ctx.String(rpcListenerFlag)
 
+	// a second daemon on the same store would sign with the same keys and
+	// state, so the data directory is locked before anything is touched
+	dataLock, err := util.LockDir(fpcfg.DataDir(homePath))
+	if err != nil {
+		return fmt.Errorf("failed to lock the data directory, is another fpd running with the same home? %w", err)
+	}
+	defer dataLock.Release()
+
+	// the state imported from another host is restored before the config
+	// is loaded and the database is opened
+	restored, err := service.ApplyStagedRestore(homePath)
+	if err != nil {
+		return fmt.Errorf("failed to restore the imported state: %w", err)
+	}
+
 	cfg, err := fpcfg.LoadConfig(homePath)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
@@ -65,20 +98,51 @@ func start(ctx *cli.Context) error {
 		cfg.RpcListener = rpcListener
 	}
 
+	if ctx.Bool(requireRemoteSignerFlag) {
+		cfg.RequireRemoteSigner = true
+	}
+
+	if ctx.Bool(enableTestRPCFlag) {
+		cfg.EnableTestRPC = true
+	}
+
+	if ctx.Bool(autoCorrectStartFlag) {
+		cfg.PollerConfig.AutoCorrectStartHeight = true
+	}
+
+	// the database may be configured out of the data directory
+	if dbPath := util.CleanAndExpandPath(cfg.DatabaseConfig.DBPath); dbPath != fpcfg.DataDir(homePath) {
+		dbLock, err := util.LockDir(dbPath)
+		if err != nil {
+			return fmt.Errorf("failed to lock the database directory, is another fpd running with the same database? %w", err)
+		}
+		defer dbLock.Release()
+	}
+
 	logger, err := log.NewRootLoggerWithFile(fpcfg.LogFile(homePath), cfg.LogLevel)
 	if err != nil {
 		return fmt.Errorf("failed to initialize the logger: %w", err)
 	}
+	// the live logs can be tailed through the RPC
+	logTail := log.NewTail()
+	logger = logTail.Wrap(logger)
+
+	if restored {
+		logger.Info("restored the state imported from another host")
+	}
 
-	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	boltBackend, err := cfg.DatabaseConfig.GetDbBackend()
 	if err != nil {
 		return fmt.Errorf("failed to create db backend: %w", err)
 	}
+	// the database is compacted in the background while the daemon runs
+	dbBackend := store.NewCompactableDB(boltBackend, cfg.DatabaseConfig.DBConfigToBoltBackendConfig())
 
 	fpApp, err := loadApp(ctx, logger, cfg, dbBackend)
 	if err != nil {
 		return fmt.Errorf("failed to load app: %w", err)
 	}
+	fpApp.SetLogTail(logTail)
 
 	if err := startApp(ctx, fpApp); err != nil {
 		return fmt.Errorf("failed to start app: %w", err)
@@ -106,11 +170,25 @@ func loadApp(
 		return nil, fmt.Errorf("failed to create finality-provider app: %v", err)
 	}
 
+	// the discovered finality providers are stored before their statuses
+	// are synced; a standby replicates them from the active daemon instead
+	if cfg.DiscoverFps && !fpApp.IsStandby() {
+		if _, err := fpApp.DiscoverFinalityProviders(); err != nil {
+			return nil, fmt.Errorf("failed to discover the registered finality providers: %w", err)
+		}
+	}
+
 	// sync finality-provider status
 	if err := fpApp.SyncFinalityProviderStatus(); err != nil {
 		return nil, fmt.Errorf("failed to sync finality-provider status: %w", err)
 	}
 
+	// check the stored finality providers against the EOTS keys, orphaned
+	// records are logged and finality providers without keys are not started
+	if _, err := fpApp.ValidateState(); err != nil {
+		return nil, fmt.Errorf("failed to validate finality-provider state: %w", err)
+	}
+
 	return fpApp, nil
 }
 
@@ -125,6 +203,22 @@ func startApp(
 		return fmt.Errorf("failed to start the finality-provider app: %w", err)
 	}
 
+	// a finality provider must not keep running with the state of a chain
+	// that is reset, so it is either registered again or the daemon stops
+	resetFps, err := fpApp.DetectChainReset()
+	if err != nil {
+		return fmt.Errorf("failed to detect whether the consumer chain is reset: %w", err)
+	}
+	if len(resetFps) > 0 {
+		if !ctx.Bool(reRegisterFlag) {
+			return fmt.Errorf("%w: %d finality providers have processed heights beyond the tip of the chain, "+
+				"restart with --%s to register them again", service.ErrChainReset, len(resetFps), reRegisterFlag)
+		}
+		if err := fpApp.ReRegisterFinalityProviders(resetFps); err != nil {
+			return err
+		}
+	}
+
 	fpPkStr := ctx.String(fpPkFlag)
 	if fpPkStr != "" {
 		// start the finality-provider instance with the given public key
@@ -17,7 +17,7 @@ func main() {
 	app := cli.NewApp()
 	app.Name = "fpd"
 	app.Usage = "Finality Provider Daemon (fpd)."
-	app.Commands = append(app.Commands, dcli.StartCommand, dcli.InitCommand)
+	app.Commands = append(app.Commands, dcli.StartCommand, dcli.InitCommand, dcli.ImportGenesisCommand, dcli.MigrateChainIDCommand)
 	app.Commands = append(app.Commands, dcli.KeysCommands...)
 
 	if err := app.Run(os.Args); err != nil {
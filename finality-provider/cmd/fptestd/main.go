@@ -0,0 +1,201 @@
+// fptestd runs the finality provider daemon against an in-process mock
+// consumer chain and EOTS manager, whose events are scripted by a scenario,
+// so that the operators can rehearse the incident response, e.g., jailing,
+// reorgs, and fee spikes, without a chain. It must never be used in
+// production
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/lightningnetwork/lnd/signal"
+	"github.com/urfave/cli"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/service"
+	"github.com/babylonchain/finality-provider/log"
+	"github.com/babylonchain/finality-provider/testutil/fakes"
+)
+
+const (
+	homeFlag          = "home"
+	scenarioFlag      = "scenario"
+	blockIntervalFlag = "block-interval"
+	finalityLagFlag   = "finality-lag"
+	votingPowerFlag   = "voting-power"
+	numFpsFlag        = "num-fps"
+	rpcListenerFlag   = "rpc-listener"
+
+	defaultBlockInterval = 2 * time.Second
+	defaultFinalityLag   = 2
+	defaultVotingPower   = 100
+	harnessChainID       = "fptestd-1"
+)
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "[fptestd] %v\n", err)
+	os.Exit(1)
+}
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "fptestd"
+	app.Usage = "Finality provider test harness daemon (fptestd), running fpd against a scripted mock chain."
+	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:  homeFlag,
+			Usage: "The home directory of the daemon, which is a temporary directory if not set",
+		},
+		cli.StringFlag{
+			Name:  scenarioFlag,
+			Usage: "The JSON file of the scenario played on the mock chain",
+		},
+		cli.DurationFlag{
+			Name:  blockIntervalFlag,
+			Usage: "The interval between the blocks of the mock chain",
+			Value: defaultBlockInterval,
+		},
+		cli.Uint64Flag{
+			Name:  finalityLagFlag,
+			Usage: "The number of blocks between the tip and the last finalized block",
+			Value: defaultFinalityLag,
+		},
+		cli.Uint64Flag{
+			Name:  votingPowerFlag,
+			Usage: "The voting power of the finality providers",
+			Value: defaultVotingPower,
+		},
+		cli.IntFlag{
+			Name:  numFpsFlag,
+			Usage: "The number of finality providers created and registered",
+			Value: 1,
+		},
+		cli.StringFlag{
+			Name:  rpcListenerFlag,
+			Usage: "The address that the RPC server listens to, which fpcli connects to",
+			Value: fpcfg.DefaultRpcListener,
+		},
+	}
+	app.Action = run
+
+	if err := app.Run(os.Args); err != nil {
+		fatal(err)
+	}
+}
+
+func run(ctx *cli.Context) error {
+	homePath := ctx.String(homeFlag)
+	if homePath == "" {
+		tmpDir, err := os.MkdirTemp("", "fptestd-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+		homePath = tmpDir
+	}
+	homePath, err := filepath.Abs(homePath)
+	if err != nil {
+		return err
+	}
+
+	var sc scenario
+	if file := ctx.String(scenarioFlag); file != "" {
+		loaded, err := loadScenario(file)
+		if err != nil {
+			return err
+		}
+		sc = *loaded
+	}
+
+	cfg := fpcfg.DefaultConfigWithHome(homePath)
+	cfg.BabylonConfig.ChainID = harnessChainID
+	cfg.RpcListener = ctx.String(rpcListenerFlag)
+	cfg.PollerConfig.PollInterval = ctx.Duration(blockIntervalFlag) / 2
+
+	logger, err := log.NewRootLoggerWithFile(fpcfg.LogFile(homePath), cfg.LogLevel)
+	if err != nil {
+		return fmt.Errorf("failed to initialize the logger: %w", err)
+	}
+	logger.Warn("fptestd runs against a mock chain and must never be used in production")
+
+	dbBackend, err := cfg.DatabaseConfig.GetDbBackend()
+	if err != nil {
+		return fmt.Errorf("failed to create db backend: %w", err)
+	}
+
+	// the finality providers are started on a chain with a tip
+	cc := fakes.NewClientController(1)
+	cc.ProduceBlocks(1)
+	em := fakes.NewEOTSManager()
+	fpApp, err := service.NewFinalityProviderApp(&cfg, cc, em, dbBackend, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create finality-provider app: %w", err)
+	}
+	if err := fpApp.Start(); err != nil {
+		return fmt.Errorf("failed to start the finality-provider app: %w", err)
+	}
+
+	votingPower := ctx.Uint64(votingPowerFlag)
+	fpPks, err := setUpFinalityProviders(fpApp, cc, ctx.Int(numFpsFlag), votingPower)
+	if err != nil {
+		return err
+	}
+
+	chain := &mockChain{
+		cc:          cc,
+		fpPks:       fpPks,
+		votingPower: votingPower,
+		finalityLag: ctx.Uint64(finalityLagFlag),
+		logger:      logger,
+		steps:       sc.Steps,
+	}
+	quit := make(chan struct{})
+	defer close(quit)
+	go chain.run(ctx.Duration(blockIntervalFlag), quit)
+
+	shutdownInterceptor, err := signal.Intercept()
+	if err != nil {
+		return err
+	}
+
+	fpServer := service.NewFinalityProviderServer(&cfg, logger, fpApp, dbBackend, shutdownInterceptor)
+	return fpServer.RunUntilShutdown()
+}
+
+// setUpFinalityProviders creates, registers, and starts the finality
+// providers with voting power on the mock chain
+func setUpFinalityProviders(fpApp *service.FinalityProviderApp, cc *fakes.ClientController, num int, votingPower uint64) ([]*btcec.PublicKey, error) {
+	commission := sdkmath.LegacyZeroDec()
+	fpPks := make([]*btcec.PublicKey, 0, num)
+	for i := 0; i < num; i++ {
+		keyName := fmt.Sprintf("fptestd-fp-%d", i)
+		res, err := fpApp.CreateFinalityProvider(keyName, harnessChainID, "", "",
+			&stakingtypes.Description{Moniker: keyName}, &commission)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the finality provider %s: %w", keyName, err)
+		}
+
+		fpPk, err := bbntypes.NewBIP340PubKeyFromHex(res.FpInfo.BtcPkHex)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fpApp.RegisterFinalityProvider(fpPk.MarshalHex()); err != nil {
+			return nil, fmt.Errorf("failed to register the finality provider %s: %w", keyName, err)
+		}
+		cc.SetVotingPower(fpPk.MustToBTCPK(), votingPower)
+
+		if err := fpApp.StartHandlingFinalityProvider(fpPk, ""); err != nil {
+			return nil, fmt.Errorf("failed to start the finality provider %s: %w", keyName, err)
+		}
+		fpPks = append(fpPks, fpPk.MustToBTCPK())
+	}
+
+	return fpPks, nil
+}
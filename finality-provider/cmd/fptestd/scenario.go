@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/testutil/fakes"
+)
+
+const (
+	actionJail     = "jail"
+	actionUnjail   = "unjail"
+	actionSlash    = "slash"
+	actionReorg    = "reorg"
+	actionFeeSpike = "fee-spike"
+	actionHalt     = "halt"
+)
+
+// errInsufficientFee is the error of the txs during a fee spike, which is the
+// error of the mempool of the Cosmos SDK chains
+var errInsufficientFee = errors.New("insufficient fee: the gas price is below the minimum of the mempool")
+
+// txMethods are the methods of the client controller sending txs, which fail
+// during a fee spike
+var txMethods = []string{
+	"RegisterFinalityProvider",
+	"CommitPubRandList",
+	"SubmitFinalitySig",
+	"BroadcastSignedTx",
+	"SubmitBatchFinalitySigs",
+}
+
+// scenario is the script of the events played on the mock chain
+type scenario struct {
+	Steps []*step `json:"steps"`
+}
+
+// step is an event of the mock chain triggered once the chain reaches the
+// height of the step
+type step struct {
+	Height uint64 `json:"height"`
+	// Action is one of jail, unjail, slash, reorg, fee-spike, and halt
+	Action string `json:"action"`
+	// Depth is the number of blocks replaced by a reorg
+	Depth uint64 `json:"depth,omitempty"`
+	// Count is the number of txs failing during a fee spike, or all the txs
+	// for the duration if not set
+	Count int `json:"count,omitempty"`
+	// Duration is the duration of a fee spike or a halt, e.g., 30s
+	Duration string `json:"duration,omitempty"`
+
+	duration time.Duration
+}
+
+func loadScenario(file string) (*scenario, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the scenario: %w", err)
+	}
+
+	var sc scenario
+	if err := json.Unmarshal(bz, &sc); err != nil {
+		return nil, fmt.Errorf("invalid scenario: %w", err)
+	}
+
+	for i, st := range sc.Steps {
+		if st.Duration != "" {
+			st.duration, err = time.ParseDuration(st.Duration)
+			if err != nil {
+				return nil, fmt.Errorf("invalid duration of step %d: %w", i, err)
+			}
+		}
+
+		switch st.Action {
+		case actionJail, actionUnjail, actionSlash:
+		case actionReorg:
+			if st.Depth == 0 {
+				return nil, fmt.Errorf("invalid step %d: the depth of a reorg should be positive", i)
+			}
+		case actionFeeSpike:
+			if st.Count <= 0 && st.duration <= 0 {
+				return nil, fmt.Errorf("invalid step %d: a fee spike needs a count or a duration", i)
+			}
+		case actionHalt:
+			if st.duration <= 0 {
+				return nil, fmt.Errorf("invalid step %d: a halt needs a duration", i)
+			}
+		default:
+			return nil, fmt.Errorf("invalid step %d: unknown action %q", i, st.Action)
+		}
+	}
+
+	sort.SliceStable(sc.Steps, func(i, j int) bool { return sc.Steps[i].Height < sc.Steps[j].Height })
+
+	return &sc, nil
+}
+
+// mockChain produces the blocks of the fake client controller and plays the
+// steps of the scenario on the finality providers of the harness
+type mockChain struct {
+	cc          *fakes.ClientController
+	fpPks       []*btcec.PublicKey
+	votingPower uint64
+	finalityLag uint64
+	logger      *zap.Logger
+
+	steps []*step
+	// haltedUntil is the time until which no block is produced
+	haltedUntil time.Time
+}
+
+// run produces a block at every interval until quit is closed
+func (mc *mockChain) run(interval time.Duration, quit <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if time.Now().Before(mc.haltedUntil) {
+				continue
+			}
+
+			b := mc.cc.ProduceBlocks(1)[0]
+			if b.Height > mc.finalityLag {
+				mc.cc.FinalizeBlocks(b.Height - mc.finalityLag)
+			}
+			mc.playSteps(b.Height)
+		case <-quit:
+			return
+		}
+	}
+}
+
+// playSteps plays the steps due at the height
+func (mc *mockChain) playSteps(height uint64) {
+	for len(mc.steps) > 0 && mc.steps[0].Height <= height {
+		st := mc.steps[0]
+		mc.steps = mc.steps[1:]
+		mc.logger.Info("playing the scenario step", zap.Uint64("height", height), zap.String("action", st.Action))
+
+		switch st.Action {
+		case actionJail:
+			for _, pk := range mc.fpPks {
+				mc.cc.SetVotingPower(pk, 0)
+			}
+		case actionUnjail:
+			for _, pk := range mc.fpPks {
+				mc.cc.SetVotingPower(pk, mc.votingPower)
+			}
+		case actionSlash:
+			for _, pk := range mc.fpPks {
+				mc.cc.SetSlashed(pk)
+			}
+		case actionReorg:
+			replaced := mc.cc.Reorg(st.Depth)
+			mc.logger.Info("reorged the chain", zap.Int("replaced_blocks", len(replaced)))
+		case actionFeeSpike:
+			for _, m := range txMethods {
+				mc.cc.InjectFault(m, errInsufficientFee, st.Count)
+			}
+			if st.duration > 0 {
+				time.AfterFunc(st.duration, func() {
+					for _, m := range txMethods {
+						mc.cc.ClearFault(m)
+					}
+					mc.logger.Info("the fee spike is over")
+				})
+			}
+		case actionHalt:
+			mc.haltedUntil = time.Now().Add(st.duration)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/testutil/fakes"
+)
+
+func writeScenario(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "scenario.json")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0600))
+
+	return file
+}
+
+func TestLoadScenario(t *testing.T) {
+	t.Run("the steps are sorted by height", func(t *testing.T) {
+		sc, err := loadScenario(writeScenario(t, `{"steps": [
+			{"height": 20, "action": "halt", "duration": "30s"},
+			{"height": 10, "action": "reorg", "depth": 2},
+			{"height": 15, "action": "fee-spike", "count": 3}
+		]}`))
+		require.NoError(t, err)
+		require.Len(t, sc.Steps, 3)
+		require.Equal(t, actionReorg, sc.Steps[0].Action)
+		require.Equal(t, actionFeeSpike, sc.Steps[1].Action)
+		require.Equal(t, actionHalt, sc.Steps[2].Action)
+		require.Equal(t, 30*time.Second, sc.Steps[2].duration)
+	})
+
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid JSON", content: `{"steps": [`},
+		{name: "an unknown action", content: `{"steps": [{"height": 1, "action": "explode"}]}`},
+		{name: "an invalid duration", content: `{"steps": [{"height": 1, "action": "halt", "duration": "soon"}]}`},
+		{name: "a reorg without depth", content: `{"steps": [{"height": 1, "action": "reorg"}]}`},
+		{name: "a fee spike without count or duration", content: `{"steps": [{"height": 1, "action": "fee-spike"}]}`},
+		{name: "a halt without duration", content: `{"steps": [{"height": 1, "action": "halt"}]}`},
+	}
+	for _, tc := range testCases {
+		t.Run("a scenario with "+tc.name+" is rejected", func(t *testing.T) {
+			_, err := loadScenario(writeScenario(t, tc.content))
+			require.Error(t, err)
+		})
+	}
+
+	_, err := loadScenario(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestPlaySteps(t *testing.T) {
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fpPk := sk.PubKey()
+	cc := fakes.NewClientController(1)
+	cc.SetVotingPower(fpPk, 100)
+	blocks := cc.ProduceBlocks(5)
+	chain := &mockChain{
+		cc:          cc,
+		fpPks:       []*btcec.PublicKey{fpPk},
+		votingPower: 100,
+		logger:      zap.NewNop(),
+		steps: []*step{
+			{Height: 2, Action: actionJail},
+			{Height: 3, Action: actionUnjail},
+			{Height: 4, Action: actionReorg, Depth: 2},
+			{Height: 5, Action: actionFeeSpike, Count: 1},
+			{Height: 6, Action: actionHalt, duration: time.Hour},
+			{Height: 7, Action: actionSlash},
+		},
+	}
+
+	// the steps are played once the chain reaches their heights
+	chain.playSteps(2)
+	power, err := cc.QueryFinalityProviderVotingPower(fpPk, 5)
+	require.NoError(t, err)
+	require.Zero(t, power)
+	require.Len(t, chain.steps, 5)
+
+	chain.playSteps(3)
+	power, err = cc.QueryFinalityProviderVotingPower(fpPk, 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), power)
+
+	chain.playSteps(4)
+	reorged, err := cc.QueryBlock(5)
+	require.NoError(t, err)
+	require.NotEqual(t, blocks[4].Hash, reorged.Hash)
+
+	chain.playSteps(5)
+	_, err = cc.SubmitBatchFinalitySigs(fpPk, nil, nil, nil, nil)
+	require.ErrorIs(t, err, errInsufficientFee)
+	_, err = cc.SubmitBatchFinalitySigs(fpPk, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	chain.playSteps(6)
+	require.True(t, time.Now().Before(chain.haltedUntil))
+
+	chain.playSteps(7)
+	slashed, err := cc.QueryFinalityProviderSlashed(fpPk)
+	require.NoError(t, err)
+	require.True(t, slashed)
+	require.Empty(t, chain.steps)
+}
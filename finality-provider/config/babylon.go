@@ -1,16 +1,23 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
 	"time"
 
 	bbncfg "github.com/babylonchain/babylon/client/config"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/babylonchain/finality-provider/version"
 )
 
 type BBNConfig struct {
 	Key            string        `long:"key" description:"name of the key to sign transactions with"`
 	ChainID        string        `long:"chain-id" description:"chain id of the chain to connect to"`
-	RPCAddr        string        `long:"rpc-address" description:"address of the rpc server to connect to"`
-	GRPCAddr       string        `long:"grpc-address" description:"address of the grpc server to connect to"`
+	RPCAddr        string        `long:"rpc-address" description:"address of the rpc server to connect to" secret:"true"`
+	GRPCAddr       string        `long:"grpc-address" description:"address of the grpc server to connect to" secret:"true"`
 	AccountPrefix  string        `long:"acc-prefix" description:"account prefix to use for addresses"`
 	KeyringBackend string        `long:"keyring-type" description:"type of keyring to use"`
 	GasAdjustment  float64       `long:"gas-adjustment" description:"adjustment factor when using gas estimation"`
@@ -21,6 +28,198 @@ type BBNConfig struct {
 	BlockTimeout   time.Duration `long:"block-timeout" description:"block timeout when waiting for block events"`
 	OutputFormat   string        `long:"output-format" description:"default output when printint responses"`
 	SignModeStr    string        `long:"sign-mode" description:"sign mode to use"`
+	// RegistrationKey is the key signing the registration of the finality
+	// providers, which may live on a Ledger device as it only signs rarely
+	RegistrationKey string `long:"registration-key" description:"name of the key to sign the registration transactions with, which may be a Ledger key; defaults to key"`
+	// TxMemo tags the txs of the daemon so that the on-chain analytics can
+	// attribute them to the operator
+	TxMemo string `long:"tx-memo" description:"template of the memo of the transactions, e.g., 'acme fpd/{{.Version}}', with the fields Version, ChainID and Key"`
+	// the gas profiles of the messages differ, so the gas estimation of each
+	// kind of message can be adjusted by a factor of its own
+	RegistrationGasAdjustment float64 `long:"registration-gas-adjustment" description:"adjustment factor of the gas estimation of the registration transactions; defaults to gas-adjustment"`
+	PubRandGasAdjustment      float64 `long:"pub-rand-gas-adjustment" description:"adjustment factor of the gas estimation of the public randomness commit transactions; defaults to gas-adjustment"`
+	FinalitySigGasAdjustment  float64 `long:"finality-sig-gas-adjustment" description:"adjustment factor of the gas estimation of the finality signature transactions; defaults to gas-adjustment"`
+	// BroadcastMode sets how long the broadcast of a tx waits on the node,
+	// while the inclusion of the tx is awaited in all the modes
+	BroadcastMode string `long:"broadcast-mode" description:"how the transactions are broadcast: sync waits for the check of the mempool, async returns right away and awaits the inclusion through the events of the node, block waits on the node for the inclusion" choice:"sync" choice:"async" choice:"block"`
+	// the static gas price goes stale during congestion, so the gas price can
+	// be picked before each broadcast from a source within the bounds
+	GasPriceSource    string        `long:"gas-price-source" description:"where the gas price of the transactions is taken from before each broadcast: static uses gas-prices, chain the minimum gas price of the node, oracle the gas-price-oracle-url; gas-prices is used whenever the source fails" choice:"static" choice:"chain" choice:"oracle"`
+	GasPriceOracleURL string        `long:"gas-price-oracle-url" description:"the URL responding to a GET with the gas price in JSON, e.g., {\"gas_price\": \"0.0025ubbn\"}, where an amount without a denom is in the denom of gas-prices (only used by the oracle source)" secret:"true"`
+	GasPriceTimeout   time.Duration `long:"gas-price-timeout" description:"the timeout of each query of the gas price source"`
+	MinGasPrice       string        `long:"min-gas-price" description:"the lower bound of the gas price picked from the source, in the denom of gas-prices, e.g., 0.002ubbn; unbounded if empty"`
+	MaxGasPrice       string        `long:"max-gas-price" description:"the upper bound of the gas price picked from the source, in the denom of gas-prices, e.g., 0.1ubbn; unbounded if empty"`
+}
+
+const (
+	// BroadcastModeSync waits for the tx to pass the check of the mempool,
+	// and then polls the node for its inclusion
+	BroadcastModeSync = "sync"
+	// BroadcastModeAsync does not wait for the check of the mempool, and
+	// awaits the inclusion of the tx through a subscription to its event
+	BroadcastModeAsync = "async"
+	// BroadcastModeBlock waits on the node until the tx is included, within
+	// the timeout_broadcast_tx_commit of the node
+	BroadcastModeBlock = "block"
+
+	defaultBroadcastMode = BroadcastModeSync
+)
+
+const (
+	// GasPriceSourceStatic uses the gas prices of the config
+	GasPriceSourceStatic = "static"
+	// GasPriceSourceChain uses the minimum gas price of the node the txs are
+	// broadcast to
+	GasPriceSourceChain = "chain"
+	// GasPriceSourceOracle uses the gas price served by an HTTP oracle
+	GasPriceSourceOracle = "oracle"
+
+	defaultGasPriceSource  = GasPriceSourceStatic
+	defaultGasPriceTimeout = 5 * time.Second
+)
+
+func (bc *BBNConfig) validateBroadcastMode() error {
+	switch bc.BroadcastMode {
+	case "":
+		bc.BroadcastMode = defaultBroadcastMode
+	case BroadcastModeSync, BroadcastModeAsync, BroadcastModeBlock:
+	default:
+		return fmt.Errorf("invalid broadcast mode: %v", bc.BroadcastMode)
+	}
+
+	return nil
+}
+
+// HasDynamicGasPrice returns whether the gas price is picked from a source
+// before each broadcast rather than taken from the config
+func (bc *BBNConfig) HasDynamicGasPrice() bool {
+	return bc.GasPriceSource != "" && bc.GasPriceSource != GasPriceSourceStatic
+}
+
+// GasPriceBounds returns the gas price of the config, which the source falls
+// back to, and the bounds of the gas price picked from the source, where an
+// unset bound is nil
+func (bc *BBNConfig) GasPriceBounds() (sdk.DecCoin, *sdk.DecCoin, *sdk.DecCoin, error) {
+	static, err := sdk.ParseDecCoin(bc.GasPrices)
+	if err != nil {
+		return sdk.DecCoin{}, nil, nil, fmt.Errorf("invalid gas-prices %q: a single gas price is needed by the gas price source: %w", bc.GasPrices, err)
+	}
+
+	bound := func(name, value string) (*sdk.DecCoin, error) {
+		if value == "" {
+			return nil, nil
+		}
+		b, err := sdk.ParseDecCoin(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", name, value, err)
+		}
+		if b.Denom != static.Denom {
+			return nil, fmt.Errorf("invalid %s %q: should be in the denom of gas-prices, %s", name, value, static.Denom)
+		}
+		return &b, nil
+	}
+	minPrice, err := bound("min-gas-price", bc.MinGasPrice)
+	if err != nil {
+		return sdk.DecCoin{}, nil, nil, err
+	}
+	maxPrice, err := bound("max-gas-price", bc.MaxGasPrice)
+	if err != nil {
+		return sdk.DecCoin{}, nil, nil, err
+	}
+	if minPrice != nil && maxPrice != nil && minPrice.Amount.GT(maxPrice.Amount) {
+		return sdk.DecCoin{}, nil, nil, fmt.Errorf("invalid gas price bounds: min-gas-price %s is above max-gas-price %s", minPrice, maxPrice)
+	}
+
+	return static, minPrice, maxPrice, nil
+}
+
+func (bc *BBNConfig) validateGasPriceSource() error {
+	switch bc.GasPriceSource {
+	case "":
+		bc.GasPriceSource = defaultGasPriceSource
+	case GasPriceSourceStatic, GasPriceSourceChain:
+	case GasPriceSourceOracle:
+		if _, err := url.ParseRequestURI(bc.GasPriceOracleURL); err != nil {
+			return fmt.Errorf("invalid gas-price-oracle-url: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid gas-price-source: %v", bc.GasPriceSource)
+	}
+	if bc.GasPriceTimeout < 0 {
+		return fmt.Errorf("invalid gas-price-timeout %v: should not be negative", bc.GasPriceTimeout)
+	}
+	if bc.GasPriceTimeout == 0 {
+		bc.GasPriceTimeout = defaultGasPriceTimeout
+	}
+	if !bc.HasDynamicGasPrice() {
+		return nil
+	}
+
+	_, _, _, err := bc.GasPriceBounds()
+	return err
+}
+
+// HasMsgGasAdjustments returns whether any kind of message has a gas
+// adjustment of its own
+func (bc *BBNConfig) HasMsgGasAdjustments() bool {
+	return bc.RegistrationGasAdjustment != 0 || bc.PubRandGasAdjustment != 0 || bc.FinalitySigGasAdjustment != 0
+}
+
+func (bc *BBNConfig) validateGasAdjustments() error {
+	adjustments := []struct {
+		name  string
+		value float64
+	}{
+		{"gas-adjustment", bc.GasAdjustment},
+		{"registration-gas-adjustment", bc.RegistrationGasAdjustment},
+		{"pub-rand-gas-adjustment", bc.PubRandGasAdjustment},
+		{"finality-sig-gas-adjustment", bc.FinalitySigGasAdjustment},
+	}
+	for _, a := range adjustments {
+		if a.value < 0 {
+			return fmt.Errorf("invalid %s %v: should not be negative", a.name, a.value)
+		}
+	}
+
+	return nil
+}
+
+// maxTxMemoLength is the default maximum length of the memos of the Cosmos SDK
+// chains
+const maxTxMemoLength = 256
+
+// TxMemoData is the data the template of the memo is rendered with
+type TxMemoData struct {
+	Version string
+	ChainID string
+	Key     string
+}
+
+// RenderTxMemo renders the template of the memo of the txs, which is empty if
+// no template is set
+func (bc *BBNConfig) RenderTxMemo() (string, error) {
+	if bc.TxMemo == "" {
+		return "", nil
+	}
+
+	tmpl, err := template.New("tx-memo").Option("missingkey=error").Parse(bc.TxMemo)
+	if err != nil {
+		return "", fmt.Errorf("invalid tx memo template: %w", err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, &TxMemoData{
+		Version: version.Version(),
+		ChainID: bc.ChainID,
+		Key:     bc.Key,
+	}); err != nil {
+		return "", fmt.Errorf("invalid tx memo template: %w", err)
+	}
+	if buf.Len() > maxTxMemoLength {
+		return "", fmt.Errorf("invalid tx memo template: the memo should be at most %d characters, got %d", maxTxMemoLength, buf.Len())
+	}
+
+	return buf.String(), nil
 }
 
 func DefaultBBNConfig() BBNConfig {
@@ -39,9 +238,13 @@ func DefaultBBNConfig() BBNConfig {
 		Timeout:        dc.Timeout,
 		// Setting this to relatively low value, out currnet babylon client (lens) will
 		// block for this amout of time to wait for transaction inclusion in block
-		BlockTimeout: 1 * time.Minute,
-		OutputFormat: dc.OutputFormat,
-		SignModeStr:  dc.SignModeStr,
+		BlockTimeout:  1 * time.Minute,
+		OutputFormat:  dc.OutputFormat,
+		SignModeStr:   dc.SignModeStr,
+		BroadcastMode: defaultBroadcastMode,
+		// the gas price is static unless a source is configured
+		GasPriceSource:  defaultGasPriceSource,
+		GasPriceTimeout: defaultGasPriceTimeout,
 	}
 }
 
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/version"
+)
+
+func TestRenderTxMemo(t *testing.T) {
+	testCases := []struct {
+		name         string
+		template     string
+		expectedMemo string
+		expectErr    bool
+	}{
+		{
+			name: "no memo is set without a template",
+		},
+		{
+			name:         "the fields are rendered",
+			template:     "acme fpd/{{.Version}} {{.ChainID}} {{.Key}}",
+			expectedMemo: "acme fpd/" + version.Version() + " chain-test my-key",
+		},
+		{
+			name:      "an invalid template is rejected",
+			template:  "acme {{.Version",
+			expectErr: true,
+		},
+		{
+			name:      "an unknown field is rejected",
+			template:  "acme {{.Moniker}}",
+			expectErr: true,
+		},
+		{
+			name:      "a memo too long is rejected",
+			template:  strings.Repeat("a", maxTxMemoLength+1),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &BBNConfig{ChainID: "chain-test", Key: "my-key", TxMemo: tc.template}
+
+			memo, err := cfg.RenderTxMemo()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMemo, memo)
+		})
+	}
+}
+
+func TestGasAdjustments(t *testing.T) {
+	cfg := &BBNConfig{GasAdjustment: 1.5}
+	require.False(t, cfg.HasMsgGasAdjustments())
+	require.NoError(t, cfg.validateGasAdjustments())
+
+	cfg.PubRandGasAdjustment = 2
+	require.True(t, cfg.HasMsgGasAdjustments())
+	require.NoError(t, cfg.validateGasAdjustments())
+
+	cfg.FinalitySigGasAdjustment = -1
+	require.Error(t, cfg.validateGasAdjustments())
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// chainSectionPrefix is the prefix of the names of the sections of the config
+// file configuring the client of a chain, e.g., [chain.babylon-devnet]
+const chainSectionPrefix = "chain."
+
+// ChainConfig overrides the babylon config for the finality providers of a
+// chain, whose chain ID is the name of its section. The unset options fall
+// back to the ones of the babylon config
+type ChainConfig struct {
+	RPCAddr       string        `long:"rpc-address" description:"address of the rpc server of the chain to connect to" secret:"true"`
+	GRPCAddr      string        `long:"grpc-address" description:"address of the grpc server of the chain to connect to" secret:"true"`
+	GasAdjustment float64       `long:"gas-adjustment" description:"adjustment factor when using gas estimation"`
+	GasPrices     string        `long:"gas-prices" description:"comma separated minimum gas prices to accept for transactions"`
+	Timeout       time.Duration `long:"timeout" description:"client timeout when doing queries"`
+	BlockTimeout  time.Duration `long:"block-timeout" description:"block timeout when waiting for block events"`
+}
+
+func (cc *ChainConfig) validate() error {
+	if cc.GasAdjustment < 0 {
+		return fmt.Errorf("invalid gas-adjustment %v: should not be negative", cc.GasAdjustment)
+	}
+	if cc.Timeout < 0 {
+		return fmt.Errorf("invalid timeout %v: should not be negative", cc.Timeout)
+	}
+	if cc.BlockTimeout < 0 {
+		return fmt.Errorf("invalid block-timeout %v: should not be negative", cc.BlockTimeout)
+	}
+
+	return nil
+}
+
+// ChainBBNConfig returns the babylon config overridden by the config of the
+// given chain, or the babylon config itself if the chain is not configured
+func (cfg *Config) ChainBBNConfig(chainID string) *BBNConfig {
+	chainCfg, ok := cfg.Chains[chainID]
+	if !ok {
+		return cfg.BabylonConfig
+	}
+
+	bbnCfg := *cfg.BabylonConfig
+	bbnCfg.ChainID = chainID
+	if chainCfg.RPCAddr != "" {
+		bbnCfg.RPCAddr = chainCfg.RPCAddr
+	}
+	if chainCfg.GRPCAddr != "" {
+		bbnCfg.GRPCAddr = chainCfg.GRPCAddr
+	}
+	if chainCfg.GasAdjustment != 0 {
+		bbnCfg.GasAdjustment = chainCfg.GasAdjustment
+	}
+	if chainCfg.GasPrices != "" {
+		bbnCfg.GasPrices = chainCfg.GasPrices
+	}
+	if chainCfg.Timeout != 0 {
+		bbnCfg.Timeout = chainCfg.Timeout
+	}
+	if chainCfg.BlockTimeout != 0 {
+		bbnCfg.BlockTimeout = chainCfg.BlockTimeout
+	}
+
+	return &bbnCfg
+}
+
+// ChainIDs returns the IDs of the chains configured by their own sections in
+// a deterministic order
+func (cfg *Config) ChainIDs() []string {
+	chainIDs := make([]string, 0, len(cfg.Chains))
+	for chainID := range cfg.Chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+
+	return chainIDs
+}
+
+// splitChainSections separates the chain sections from the rest of the config
+// file, which is returned to be parsed as usual, as the sections are named
+// after the chain IDs and thus cannot be declared as option groups
+func splitChainSections(r io.Reader) (string, map[string]string, error) {
+	var (
+		rest     strings.Builder
+		sections = make(map[string]*strings.Builder)
+		current  *strings.Builder
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if !strings.HasPrefix(name, chainSectionPrefix) {
+				current = nil
+			} else {
+				chainID := strings.TrimPrefix(name, chainSectionPrefix)
+				if chainID == "" {
+					return "", nil, fmt.Errorf("invalid chain section [%s]: empty chain ID", name)
+				}
+				if _, exists := sections[chainID]; exists {
+					return "", nil, fmt.Errorf("duplicate chain section [%s]", name)
+				}
+				current = &strings.Builder{}
+				sections[chainID] = current
+				continue
+			}
+		}
+
+		if current != nil {
+			current.WriteString(line + "\n")
+		} else {
+			rest.WriteString(line + "\n")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+
+	bodies := make(map[string]string, len(sections))
+	for chainID, body := range sections {
+		bodies[chainID] = body.String()
+	}
+
+	return rest.String(), bodies, nil
+}
+
+// parseChainSections parses the bodies of the chain sections keyed by their
+// chain IDs
+func parseChainSections(bodies map[string]string) (map[string]*ChainConfig, error) {
+	chains := make(map[string]*ChainConfig, len(bodies))
+	for chainID, body := range bodies {
+		var chainCfg ChainConfig
+		parser := flags.NewParser(&chainCfg, flags.Default)
+		err := flags.NewIniParser(parser).Parse(strings.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain section [%s%s]: %w", chainSectionPrefix, chainID, err)
+		}
+		chains[chainID] = &chainCfg
+	}
+
+	return chains, nil
+}
+
+// writeChainSection writes the section of the given chain with the options
+// set in its config
+func writeChainSection(w io.Writer, chainID string, chainCfg *ChainConfig) {
+	var buf strings.Builder
+	flags.NewIniParser(flags.NewParser(chainCfg, flags.Default)).Write(&buf, flags.IniIncludeComments)
+	body := strings.TrimPrefix(buf.String(), "[Application Options]\n")
+	body = strings.TrimRight(body, "\n") + "\n"
+
+	fmt.Fprintf(w, "\n[%s%s]\n%s", chainSectionPrefix, chainID, body)
+}
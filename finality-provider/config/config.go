@@ -1,10 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"math"
 	"net"
+	"net/url"
+	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
@@ -12,7 +18,10 @@ import (
 	"github.com/jessevdk/go-flags"
 
 	eotscfg "github.com/babylonchain/finality-provider/eotsmanager/config"
+	"github.com/babylonchain/finality-provider/finality-provider/approval"
+	"github.com/babylonchain/finality-provider/finality-provider/maintenance"
 	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/profiling"
 	"github.com/babylonchain/finality-provider/util"
 )
 
@@ -27,16 +36,65 @@ const (
 	defaultNumPubRand              = 100
 	defaultNumPubRandMax           = 200
 	defaultMinRandHeightGap        = 20
+	defaultRandRunwayMargin        = 10
+	defaultRandGapScanDepth        = 100
+	defaultStateFileInterval       = 10 * time.Second
+	defaultMaxImportStateSize      = 1 << 30
 	defaultStatusUpdateInterval    = 20 * time.Second
 	defaultRandomInterval          = 30 * time.Second
 	defaultSubmitRetryInterval     = 1 * time.Second
+	defaultChainCallTimeout        = 2 * time.Minute
+	defaultShutdownDrainTimeout    = 30 * time.Second
 	defaultFastSyncInterval        = 10 * time.Second
+	defaultFinalizedHeightInterval = 30 * time.Second
 	defaultFastSyncLimit           = 10
 	defaultFastSyncGap             = 3
+	defaultCatchUpGap              = 100
+	defaultCatchUpBatchSize        = 100
 	defaultMaxSubmissionRetries    = 20
+	defaultSafeModeThreshold       = 5
 	defaultBitcoinNetwork          = "signet"
 	defaultDataDirname             = "data"
 	defaultMaxNumFinalityProviders = 3
+	defaultSubmissionOrder         = SubmissionOrderOldestFirst
+	defaultClockSkewCheckInterval  = 1 * time.Minute
+	defaultMaxClockSkew            = 1 * time.Minute
+	defaultChainHaltThreshold      = 5 * time.Minute
+	defaultPendingTxPolicy         = PendingTxPolicyResubmit
+	defaultSubmissionMode          = SubmissionModeDirect
+	defaultRelayerTimeout          = 10 * time.Second
+	defaultPolicyReloadInterval    = 10 * time.Second
+	defaultHookTimeout             = 10 * time.Second
+	defaultChainParamsRefresh      = 10 * time.Minute
+	defaultMaintenanceLeadTime     = 30 * time.Minute
+	defaultMaintenanceRandGap      = 1000
+	defaultVoteSLOMaxLatency       = 1
+	defaultVoteSLOWindow           = 100
+	defaultObserverWindow          = 100
+	defaultEOTSManagerPoolSize     = 2
+	defaultEOTSManagerKeepAlive    = 30 * time.Second
+	defaultEOTSManagerMaxBackoff   = 30 * time.Second
+	minEOTSManagerKeepAlive        = 10 * time.Second
+)
+
+const (
+	// SubmissionOrderOldestFirst processes pending heights strictly in ascending order
+	SubmissionOrderOldestFirst = "oldest-first"
+	// SubmissionOrderNewestFirst votes on the newest pending height first and then
+	// backfills (or skips) the older pending heights
+	SubmissionOrderNewestFirst = "newest-first"
+
+	// PendingTxPolicyResubmit broadcasts again the txs of the finality signatures
+	// that were broadcast but not included before the daemon restarted
+	PendingTxPolicyResubmit = "resubmit"
+	// PendingTxPolicyAbandon drops the finality signatures whose txs were
+	// broadcast but not included before the daemon restarted
+	PendingTxPolicyAbandon = "abandon"
+
+	// SubmissionModeDirect broadcasts finality signatures to the consumer chain
+	SubmissionModeDirect = "direct"
+	// SubmissionModeRelayer hands finality signatures to an external relayer service
+	SubmissionModeRelayer = "relayer"
 )
 
 var (
@@ -58,16 +116,119 @@ type Config struct {
 	ChainName                string        `long:"chainname" description:"the name of the consumer chain" choice:"babylon"`
 	NumPubRand               uint64        `long:"numPubRand" description:"The number of Schnorr public randomness for each commitment"`
 	NumPubRandMax            uint64        `long:"numpubrandmax" description:"The upper bound of the number of Schnorr public randomness for each commitment"`
+	PubRandChunkSize         uint64        `long:"pubrandchunksize" description:"The maximum number of Schnorr public randomness in a commit tx, above which a commitment is split into commits of contiguous heights; the chunks are also halved when the chain rejects a commit for exceeding its max tx size or gas limit; 0 means no maximum"`
 	MinRandHeightGap         uint64        `long:"minrandheightgap" description:"The minimum gap between the last committed rand height and the current Babylon block height"`
+	RandRunwayMargin         uint64        `long:"randrunwaymargin" description:"The number of blocks of randomness remaining ahead of the tip below which the rand-runway-low event is fired, which should be lower than the min rand height gap and is disabled if the value is 0"`
+	RandGapScanDepth         uint64        `long:"randgapscandepth" description:"The number of the last public randomness commits scanned for gaps when a finality provider instance starts, which is disabled if the value is 0"`
+	RandomnessRetention      uint64        `long:"randomnessretention" description:"The number of blocks below the finalized height whose private randomness is kept by the EOTS manager, below which the randomness is shredded so that a later compromise of the EOTS key cannot forge the signatures of older heights; the randomness committed once it is set is derived from seeds in the EOTS database, which cannot be recovered from the mnemonic; requires the EOTS manager daemon to authenticate the requests; disabled if the value is 0"`
 	StatusUpdateInterval     time.Duration `long:"statusupdateinterval" description:"The interval between each update of finality-provider status"`
 	RandomnessCommitInterval time.Duration `long:"randomnesscommitinterval" description:"The interval between each attempt to commit public randomness"`
 	SubmissionRetryInterval  time.Duration `long:"submissionretryinterval" description:"The interval between each attempt to submit finality signature or public randomness after a failure"`
 	MaxSubmissionRetries     uint64        `long:"maxsubmissionretries" description:"The maximum number of retries to submit finality signature or public randomness"`
+	ChainCallTimeout         time.Duration `long:"chaincalltimeout" description:"The maximum duration of each call of a finality-provider instance to the consumer chain, after which the call is abandoned and handled as a failure; calls are only aborted when the instance stops if the value is 0"`
+	SafeModeThreshold        uint32        `long:"safemodethreshold" description:"The number of consecutive submissions failed with non-retryable errors after which the finality provider enters safe mode, stopping broadcasting until it is resumed; a non-retryable error terminates the daemon instead if the value is 0"`
 	FastSyncInterval         time.Duration `long:"fastsyncinterval" description:"The interval between each try of fast sync, which is disabled if the value is 0"`
+	FinalizedHeightInterval  time.Duration `long:"finalizedheightinterval" description:"The interval between each update of the finalized height of the finality providers, i.e., the highest processed height finalized on the consumer chain, after which the processing resumes on restart"`
 	FastSyncLimit            uint64        `long:"fastsynclimit" description:"The maximum number of blocks to catch up for each fast sync"`
 	FastSyncGap              uint64        `long:"fastsyncgap" description:"The block gap that will trigger the fast sync"`
+	CatchUpGap               uint64        `long:"catchupgap" description:"The block gap beyond which the finality provider switches to catch-up mode, voting on the newest blocks first and fast syncing in larger batches until it is back within the fast sync gap; catch-up mode is disabled if the value is 0"`
+	CatchUpBatchSize         uint64        `long:"catchupbatchsize" description:"The maximum number of blocks to catch up for each fast sync in catch-up mode"`
 	EOTSManagerAddress       string        `long:"eotsmanageraddress" description:"The address of the remote EOTS manager; Empty if the EOTS manager is running locally"`
 	MaxNumFinalityProviders  uint32        `long:"maxnumfinalityproviders" description:"The maximum number of finality-provider instances running concurrently within the daemon"`
+	RequireRemoteSigner      bool          `long:"requireremotesigner" description:"Require a remote EOTS manager and never load EOTS private keys within the daemon"`
+	ClockSkewCheckInterval   time.Duration `long:"clockskewcheckinterval" description:"The interval between each check of the skew between the local clock and the timestamp of the latest block, which is disabled if the value is 0"`
+	MaxClockSkew             time.Duration `long:"maxclockskew" description:"The maximum tolerated skew between the local clock and the timestamp of the latest block"`
+	HaltOnClockSkew          bool          `long:"haltonclockskew" description:"Halt the daemon instead of warning when the clock skew exceeds the maximum"`
+	ChainHaltThreshold       time.Duration `long:"chainhaltthreshold" description:"The duration without a new block after which the consumer chain is considered halted and its finality providers switch to STANDBY, suppressing the retries and alerts until blocks are produced again; the detection is disabled if the value is 0"`
+	SubmissionOrder          string        `long:"submissionorder" description:"The order in which pending heights are voted on; newest-first votes on the newest pending height before the older ones" choice:"oldest-first" choice:"newest-first"`
+	SkipOlderPendingHeights  bool          `long:"skipolderpendingheights" description:"Skip the older pending heights instead of backfilling them after the newest one is voted on (only used in newest-first mode)"`
+	MaxConcurrentSubmissions uint32        `long:"maxconcurrentsubmissions" description:"The maximum number of finality-provider instances signing and submitting finality signatures at the same time, which is unlimited if the value is 0"`
+	SubmissionStagger        time.Duration `long:"submissionstagger" description:"The minimum interval between two finality signature submissions across all the finality-provider instances, which is disabled if the value is 0"`
+	MaxVoteJitter            time.Duration `long:"maxvotejitter" description:"The upper bound of the random delay before submitting each finality signature, which is disabled if the value is 0"`
+	PendingTxPolicy          string        `long:"pendingtxpolicy" description:"What to do on restart with the finality signatures that were broadcast but not confirmed" choice:"resubmit" choice:"abandon"`
+	PendingTxMaxAge          time.Duration `long:"pendingtxmaxage" description:"The maximum age of a pending finality signature to be resubmitted on restart, which is unlimited if the value is 0"`
+	SubmissionMode           string        `long:"submissionmode" description:"How finality signatures are submitted; relayer hands them to an external relayer service instead of broadcasting them directly" choice:"direct" choice:"relayer"`
+	RelayerURL               string        `long:"relayerurl" description:"The base URL of the relayer service, e.g., http://127.0.0.1:8080 (only used in relayer mode)" secret:"true"`
+	RelayerTimeout           time.Duration `long:"relayertimeout" description:"The timeout of each request to the relayer service (only used in relayer mode)"`
+	SigningPolicyFile        string        `long:"signingpolicyfile" description:"The path to the JSON file of the policy evaluated before each finality signature; Empty if no policy is enforced"`
+	PolicyReloadInterval     time.Duration `long:"policyreloadinterval" description:"The minimum interval between each check of the signing policy file for changes"`
+	SubmissionScript         string        `long:"submissionscript" description:"The path to a Starlark script defining decide(block, fp), which returns sign, skip or delay for each finality signature; Empty if no script is run"`
+	StandbyOf                string        `long:"standbyof" description:"The RPC address of the active daemon whose state is replicated, which makes this daemon a cold standby that does not run finality providers; Empty if this daemon is active"`
+	AuditLogFile             string        `long:"auditlogfile" description:"The path to the file where every RPC call is recorded with its caller, redacted parameters, latency and result; Empty if RPC calls are not audited"`
+	RPCRateLimit             float64       `long:"rpcratelimit" description:"The maximum number of RPC requests per second accepted from all clients, which is unlimited if the value is 0"`
+	RPCRateBurst             int           `long:"rpcrateburst" description:"The maximum burst of RPC requests accepted from all clients"`
+	RPCClientRateLimit       float64       `long:"rpcclientratelimit" description:"The maximum number of RPC requests per second accepted from each client host, which is unlimited if the value is 0"`
+	RPCClientRateBurst       int           `long:"rpcclientrateburst" description:"The maximum burst of RPC requests accepted from each client host"`
+	ParamsRefreshInterval    time.Duration `long:"paramsrefreshinterval" description:"The interval after which the cached on-chain parameters used for local validation are refreshed"`
+	HookTimeout              time.Duration `long:"hooktimeout" description:"The timeout of each run of a hook command or webhook"`
+	VoteSLOTarget            float64       `long:"voteslotarget" description:"The target ratio of the recent votes within the maximum latency, e.g., 0.95, below which the vote-slo-breached event is fired; the SLO is not tracked if the value is 0"`
+	VoteSLOMaxLatency        uint64        `long:"voteslomaxlatency" description:"The maximum number of blocks produced after the voted block by the time a vote within the SLO is submitted"`
+	VoteSLOWindow            uint32        `long:"voteslowindow" description:"The number of the most recent votes of each finality provider over which the SLO compliance is computed"`
+	ObserverMode             bool          `long:"observermode" description:"Track the participation of all the finality providers on the consumer chain to tell whether missed votes are local or network-wide"`
+	ObserverWindow           uint32        `long:"observerwindow" description:"The number of the most recent blocks over which the participation is reported (only used in observer mode)"`
+	VoteIndexer              bool          `long:"voteindexer" description:"Index the votes and randomness commits of the finality providers accepted by the consumer chain to serve their voting history locally"`
+	CloneDetection           bool          `long:"clonedetection" description:"Watch the consumer chain for the votes of the running finality providers not submitted by this daemon, which mean that a cloned daemon is signing with the same keys, and switch those finality providers to safe mode"`
+	DiscoverFps              bool          `long:"discoverfps" description:"On startup, store the finality providers whose EOTS keys are held by the EOTS manager and which are registered on the consumer chain, e.g., after restoring the keys from their mnemonics"`
+	IdleUnloadDelay          time.Duration `long:"idleunloaddelay" description:"The duration after which the instance of an INACTIVE finality provider is unloaded from memory, stopping its goroutines while it stays queryable from the database, until it regains voting power or is needed by an RPC; the INACTIVE finality providers are also left unloaded on startup; disabled if the value is 0 (only used if the status update is enabled)"`
+
+	// HookCommands and HookURLs receive the lifecycle events of the finality-provider
+	// instances, e.g., instance start and stop, vote submission, status change and error
+	HookCommands []string `long:"hookcommand" description:"The path to an executable run on each lifecycle event with the event type as the argument and the event in JSON on stdin; can be specified multiple times"`
+	HookURLs     []string `long:"hookurl" description:"The URL to which each lifecycle event is posted in JSON; can be specified multiple times" secret:"true"`
+
+	// MaintenanceWindows are the recurring periods during which the operator
+	// restarts the nodes. Ahead of each window, the randomness is committed to
+	// last through it, and the randomness commits that are not needed to keep
+	// voting are deferred until it ends
+	MaintenanceWindows       []string      `long:"maintenancewindow" description:"A recurring maintenance window in the form of '<cron expression>|<duration>' in UTC, e.g., '0 3 * * SUN|1h'; can be specified multiple times"`
+	MaintenanceLeadTime      time.Duration `long:"maintenanceleadtime" description:"How long before a maintenance window the randomness is committed to last through it"`
+	MaintenanceRandHeightGap uint64        `long:"maintenancerandheightgap" description:"The minimum gap between the last committed rand height and the current block height to be reached before a maintenance window"`
+
+	// EOTSManagerAuthClient and EOTSManagerAuthSecret authenticate the requests
+	// to the remote EOTS manager configured with the same client
+	EOTSManagerAuthClient string `long:"eotsmanagerauthclient" description:"The client ID with which the requests to the EOTS manager are authenticated; Empty if the EOTS manager does not authenticate requests"`
+	EOTSManagerAuthSecret string `long:"eotsmanagerauthsecret" description:"The HMAC secret of the client authenticating the requests to the EOTS manager" secret:"true"`
+
+	// EOTSManagerPoolSize connections are kept alive to the remote EOTS manager
+	// and reconnected with an exponential backoff when broken
+	EOTSManagerPoolSize   int           `long:"eotsmanagerpoolsize" description:"The number of connections over which the requests to the EOTS manager are spread"`
+	EOTSManagerKeepAlive  time.Duration `long:"eotsmanagerkeepalive" description:"The interval of inactivity after which the connections to the EOTS manager are pinged to detect the broken ones, which is disabled if the value is 0"`
+	EOTSManagerMaxBackoff time.Duration `long:"eotsmanagermaxbackoff" description:"The maximum backoff between the attempts to reconnect to the EOTS manager"`
+
+	// SignFinalityRPC lets external coordinators obtain the finality signatures
+	// over the RPC and broadcast them on their own
+	SignFinalityRPC bool `long:"signfinalityrpc" description:"Serve the SignFinality RPC, which returns the finality signatures of the running finality providers without broadcasting them"`
+
+	// EnableTestRPC exposes the RPC paths meant for presentation and testing,
+	// e.g., AddFinalitySignature which returns the extracted private key
+	EnableTestRPC bool `long:"enabletestrpc" description:"Serve the RPC paths meant for presentation and testing, which can sign conflicting blocks and expose private keys and must never be enabled in production"`
+
+	// ShutdownDrainTimeout bounds how long the shutdown waits for the
+	// in-flight submissions once no new block is accepted
+	ShutdownDrainTimeout time.Duration `long:"shutdowndraintimeout" description:"How long the shutdown waits for the in-flight finality signature and randomness submissions to finish before aborting them"`
+
+	// StateFileInterval is the interval of writing the state of the daemon
+	// to a JSON file in the data directory, so that scripts and node managers
+	// can check its status without gRPC
+	StateFileInterval time.Duration `long:"statefileinterval" description:"The interval between each write of the state file of the daemon in the data directory, which is disabled if the value is 0"`
+
+	// MaxImportStateSize bounds the memory taken by a state archive received
+	// over the RPC, which is held in full until it is verified
+	MaxImportStateSize uint64 `long:"maximportstatesize" description:"The maximum size in bytes of a state archive imported over the RPC"`
+
+	// AllowedChainIDs guards against creating or registering a finality provider
+	// with a chain ID it is not meant for, e.g., a mainnet key against a testnet
+	AllowedChainIDs []string `long:"allowedchainid" description:"A chain ID that finality providers can be created and registered with; can be specified multiple times, and any chain ID is allowed if none is specified"`
+
+	// ConfirmationDepth trades the latency of the votes for the safety against
+	// reorgs, by waiting for blocks on top of a block before signing it
+	ConfirmationDepth       uint64            `long:"confirmationdepth" description:"The number of blocks required on top of a block before it is signed, which is disabled if the value is 0"`
+	ChainConfirmationDepths map[string]uint64 `long:"chainconfirmationdepth" description:"The confirmation depth for the finality providers of a chain in the format <chain ID>:<depth>, which overrides the confirmation depth; can be specified multiple times"`
+
+	// ApprovalMode enforces the two-person rule in custodial environments,
+	// where sensitive operations over the RPC require a token co-signed by an approver
+	ApprovalMode bool     `long:"approvalmode" description:"Require an approval token signed by one of the approvers for registering finality providers, signing messages with chain keys, and exporting and importing the state"`
+	ApproverPks  []string `long:"approverpk" description:"The hex BIP-340 public key of an approver; can be specified multiple times (only used in approval mode)"`
 
 	BitcoinNetwork string `long:"bitcoinnetwork" description:"Bitcoin network to run on" choise:"mainnet" choice:"regtest" choice:"testnet" choice:"simnet" choice:"signet"`
 
@@ -79,9 +240,24 @@ type Config struct {
 
 	BabylonConfig *BBNConfig `group:"babylon" namespace:"babylon"`
 
+	// Chains are the configs of the chains other than the one of the babylon
+	// config, or overriding it, which are loaded from the [chain.<chain ID>]
+	// sections of the config file and used by the finality providers of the
+	// chains
+	Chains map[string]*ChainConfig `no-flag:"true"`
+
 	RpcListener string `long:"rpclistener" description:"the listener for RPC connections, e.g., 127.0.0.1:1234"`
 
+	HealthListener string `long:"healthlistener" description:"the listener for the /livez and /readyz HTTP probes, e.g., 127.0.0.1:2113; Empty if the probes are disabled"`
+
 	Metrics *metrics.Config `group:"metrics" namespace:"metrics"`
+
+	Profiling *profiling.Config `group:"profiling" namespace:"profiling"`
+
+	GRPCConfig *GRPCConfig `group:"grpc" namespace:"grpc"`
+
+	// homePath is the home directory the config is loaded from
+	homePath string
 }
 
 func DefaultConfigWithHome(homePath string) Config {
@@ -89,6 +265,7 @@ func DefaultConfigWithHome(homePath string) Config {
 	bbnCfg.Key = defaultFinalityProviderKeyName
 	bbnCfg.KeyDirectory = homePath
 	pollerCfg := DefaultChainPollerConfig()
+	grpcCfg := DefaultGRPCConfig()
 	cfg := Config{
 		ChainName:                defaultChainName,
 		LogLevel:                 defaultLogLevel,
@@ -98,20 +275,51 @@ func DefaultConfigWithHome(homePath string) Config {
 		NumPubRand:               defaultNumPubRand,
 		NumPubRandMax:            defaultNumPubRandMax,
 		MinRandHeightGap:         defaultMinRandHeightGap,
+		RandRunwayMargin:         defaultRandRunwayMargin,
+		RandGapScanDepth:         defaultRandGapScanDepth,
 		StatusUpdateInterval:     defaultStatusUpdateInterval,
 		RandomnessCommitInterval: defaultRandomInterval,
 		SubmissionRetryInterval:  defaultSubmitRetryInterval,
+		ChainCallTimeout:         defaultChainCallTimeout,
+		ShutdownDrainTimeout:     defaultShutdownDrainTimeout,
+		StateFileInterval:        defaultStateFileInterval,
+		MaxImportStateSize:       defaultMaxImportStateSize,
 		FastSyncInterval:         defaultFastSyncInterval,
+		FinalizedHeightInterval:  defaultFinalizedHeightInterval,
 		FastSyncLimit:            defaultFastSyncLimit,
 		FastSyncGap:              defaultFastSyncGap,
+		CatchUpGap:               defaultCatchUpGap,
+		CatchUpBatchSize:         defaultCatchUpBatchSize,
 		MaxSubmissionRetries:     defaultMaxSubmissionRetries,
+		SafeModeThreshold:        defaultSafeModeThreshold,
 		BitcoinNetwork:           defaultBitcoinNetwork,
 		BTCNetParams:             defaultBTCNetParams,
 		EOTSManagerAddress:       defaultEOTSManagerAddress,
 		RpcListener:              DefaultRpcListener,
 		MaxNumFinalityProviders:  defaultMaxNumFinalityProviders,
+		SubmissionOrder:          defaultSubmissionOrder,
+		PendingTxPolicy:          defaultPendingTxPolicy,
+		SubmissionMode:           defaultSubmissionMode,
+		RelayerTimeout:           defaultRelayerTimeout,
+		PolicyReloadInterval:     defaultPolicyReloadInterval,
+		HookTimeout:              defaultHookTimeout,
+		ParamsRefreshInterval:    defaultChainParamsRefresh,
+		MaintenanceLeadTime:      defaultMaintenanceLeadTime,
+		MaintenanceRandHeightGap: defaultMaintenanceRandGap,
+		VoteSLOMaxLatency:        defaultVoteSLOMaxLatency,
+		VoteSLOWindow:            defaultVoteSLOWindow,
+		ObserverWindow:           defaultObserverWindow,
+		ClockSkewCheckInterval:   defaultClockSkewCheckInterval,
+		MaxClockSkew:             defaultMaxClockSkew,
+		ChainHaltThreshold:       defaultChainHaltThreshold,
+		EOTSManagerPoolSize:      defaultEOTSManagerPoolSize,
+		EOTSManagerKeepAlive:     defaultEOTSManagerKeepAlive,
+		EOTSManagerMaxBackoff:    defaultEOTSManagerMaxBackoff,
 		Metrics:                  metrics.DefaultFpConfig(),
+		Profiling:                profiling.DefaultConfig(),
+		GRPCConfig:               &grpcCfg,
 	}
+	cfg.homePath = homePath
 
 	if err := cfg.Validate(); err != nil {
 		panic(err)
@@ -157,22 +365,67 @@ func LoadConfig(homePath string) (*Config, error) {
 			"not exist in %s", cfgFile)
 	}
 
+	cfgBytes, err := os.ReadFile(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+	// the chain sections are parsed on their own
+	rest, chainSections, err := splitChainSections(bytes.NewReader(cfgBytes))
+	if err != nil {
+		return nil, err
+	}
+
 	// Next, load any additional configuration options from the file.
 	var cfg Config
 	fileParser := flags.NewParser(&cfg, flags.Default)
-	err := flags.NewIniParser(fileParser).ParseFile(cfgFile)
+	err = flags.NewIniParser(fileParser).Parse(strings.NewReader(rest))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Chains, err = parseChainSections(chainSections)
 	if err != nil {
 		return nil, err
 	}
 
+	// Resolve the secrets referenced by URIs instead of held in plaintext,
+	// e.g., the credentials embedded in the endpoints
+	if err := util.ResolveSecrets(&cfg); err != nil {
+		return nil, err
+	}
+	for _, chainCfg := range cfg.Chains {
+		if err := util.ResolveSecrets(chainCfg); err != nil {
+			return nil, err
+		}
+	}
+
 	// Make sure everything we just loaded makes sense.
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
+	cfg.homePath = homePath
 
 	return &cfg, nil
 }
 
+// WriteConfigFile writes the given config into the config file under the
+// given home directory, followed by the chain sections if any
+func WriteConfigFile(cfg *Config, homePath string) error {
+	var buf bytes.Buffer
+	fileParser := flags.NewParser(cfg, flags.Default)
+	flags.NewIniParser(fileParser).Write(&buf, flags.IniIncludeComments|flags.IniIncludeDefaults)
+	for _, chainID := range cfg.ChainIDs() {
+		writeChainSection(&buf, chainID, cfg.Chains[chainID])
+	}
+
+	return os.WriteFile(ConfigFile(homePath), buf.Bytes(), 0644)
+}
+
+// HomePath returns the home directory the config is loaded from, which is
+// empty if the config is not loaded from a home directory
+func (cfg *Config) HomePath() string {
+	return cfg.homePath
+}
+
 // Validate checks the given configuration to be sane. This makes sure no
 // illegal values or combination of values are set. All file system paths are
 // normalized. The cleaned up config is returned on success.
@@ -180,6 +433,46 @@ func (cfg *Config) Validate() error {
 	if cfg.EOTSManagerAddress == "" {
 		return fmt.Errorf("EOTS manager address not specified")
 	}
+	if cfg.EOTSManagerAuthClient != "" && cfg.EOTSManagerAuthSecret == "" {
+		return fmt.Errorf("the secret of the EOTS manager client %s is not specified", cfg.EOTSManagerAuthClient)
+	}
+	// the config files predating the connection pool have no such options
+	if cfg.EOTSManagerPoolSize <= 0 {
+		cfg.EOTSManagerPoolSize = defaultEOTSManagerPoolSize
+	}
+	if cfg.EOTSManagerMaxBackoff <= 0 {
+		cfg.EOTSManagerMaxBackoff = defaultEOTSManagerMaxBackoff
+	}
+	if cfg.EOTSManagerKeepAlive != 0 && cfg.EOTSManagerKeepAlive < minEOTSManagerKeepAlive {
+		return fmt.Errorf("the EOTS manager keep-alive interval should be at least %v", minEOTSManagerKeepAlive)
+	}
+	if cfg.ChainCallTimeout < 0 {
+		return fmt.Errorf("invalid chain call timeout: should not be negative")
+	}
+	if cfg.ShutdownDrainTimeout <= 0 {
+		cfg.ShutdownDrainTimeout = defaultShutdownDrainTimeout
+	}
+
+	if cfg.FinalizedHeightInterval < 0 {
+		return fmt.Errorf("invalid finalized height interval: should not be negative")
+	}
+	// the config files predating the finalized height have no such interval
+	if cfg.FinalizedHeightInterval == 0 {
+		cfg.FinalizedHeightInterval = defaultFinalizedHeightInterval
+	}
+
+	if cfg.StateFileInterval < 0 {
+		return fmt.Errorf("invalid state file interval: should not be negative")
+	}
+	if cfg.MaxImportStateSize == 0 {
+		return fmt.Errorf("invalid max import state size: should be positive")
+	}
+	if cfg.DatabaseConfig.CompactionInterval < 0 {
+		return fmt.Errorf("invalid compaction interval: should not be negative")
+	}
+	if cfg.IdleUnloadDelay < 0 {
+		return fmt.Errorf("invalid idle unload delay: should not be negative")
+	}
 	// Multiple networks can't be selected simultaneously.  Count number of
 	// network flags passed; assign active network params
 	// while we're at it.
@@ -198,11 +491,236 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("invalid network: %v", cfg.BitcoinNetwork)
 	}
 
+	if cfg.PollerConfig != nil {
+		if err := cfg.PollerConfig.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.BabylonConfig != nil && cfg.BabylonConfig.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout of the queries of the consumer chain: should be positive")
+	}
+
+	if cfg.BabylonConfig != nil {
+		if _, err := cfg.BabylonConfig.RenderTxMemo(); err != nil {
+			return err
+		}
+		if err := cfg.BabylonConfig.validateGasAdjustments(); err != nil {
+			return err
+		}
+		if err := cfg.BabylonConfig.validateBroadcastMode(); err != nil {
+			return err
+		}
+		if err := cfg.BabylonConfig.validateGasPriceSource(); err != nil {
+			return err
+		}
+	}
+
+	// the config files predating the profiling have no such group
+	if cfg.Profiling == nil {
+		cfg.Profiling = profiling.DefaultConfig()
+	}
+	if err := cfg.Profiling.Validate(); err != nil {
+		return err
+	}
+
+	// the config files predating the gRPC options have no such group
+	if cfg.GRPCConfig == nil {
+		grpcCfg := DefaultGRPCConfig()
+		cfg.GRPCConfig = &grpcCfg
+	}
+	if err := cfg.GRPCConfig.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.CatchUpGap > 0 && cfg.CatchUpGap <= cfg.FastSyncGap {
+		return fmt.Errorf("invalid catch-up gap: %d, should be larger than the fast sync gap %d", cfg.CatchUpGap, cfg.FastSyncGap)
+	}
+	if cfg.CatchUpBatchSize == 0 {
+		cfg.CatchUpBatchSize = defaultCatchUpBatchSize
+	}
+
+	switch cfg.SubmissionOrder {
+	case "":
+		// config files created before the option was introduced
+		cfg.SubmissionOrder = defaultSubmissionOrder
+	case SubmissionOrderOldestFirst, SubmissionOrderNewestFirst:
+	default:
+		return fmt.Errorf("invalid submission order: %v", cfg.SubmissionOrder)
+	}
+
+	switch cfg.PendingTxPolicy {
+	case "":
+		cfg.PendingTxPolicy = defaultPendingTxPolicy
+	case PendingTxPolicyResubmit, PendingTxPolicyAbandon:
+	default:
+		return fmt.Errorf("invalid pending tx policy: %v", cfg.PendingTxPolicy)
+	}
+
+	switch cfg.SubmissionMode {
+	case "":
+		cfg.SubmissionMode = defaultSubmissionMode
+	case SubmissionModeDirect:
+	case SubmissionModeRelayer:
+		if _, err := url.ParseRequestURI(cfg.RelayerURL); err != nil {
+			return fmt.Errorf("invalid relayer URL %s: %w", cfg.RelayerURL, err)
+		}
+		if cfg.RelayerTimeout == 0 {
+			cfg.RelayerTimeout = defaultRelayerTimeout
+		}
+	default:
+		return fmt.Errorf("invalid submission mode: %v", cfg.SubmissionMode)
+	}
+
+	if cfg.SigningPolicyFile != "" {
+		cfg.SigningPolicyFile = util.CleanAndExpandPath(cfg.SigningPolicyFile)
+		if cfg.PolicyReloadInterval == 0 {
+			cfg.PolicyReloadInterval = defaultPolicyReloadInterval
+		}
+	}
+
+	if cfg.ParamsRefreshInterval < 0 {
+		return fmt.Errorf("invalid params refresh interval: %v, should not be negative", cfg.ParamsRefreshInterval)
+	}
+	if cfg.ParamsRefreshInterval == 0 {
+		cfg.ParamsRefreshInterval = defaultChainParamsRefresh
+	}
+
+	if cfg.StandbyOf != "" {
+		if _, err := net.ResolveTCPAddr("tcp", cfg.StandbyOf); err != nil {
+			return fmt.Errorf("invalid address of the active daemon %s: %w", cfg.StandbyOf, err)
+		}
+	}
+
+	if cfg.SubmissionScript != "" {
+		cfg.SubmissionScript = util.CleanAndExpandPath(cfg.SubmissionScript)
+	}
+
+	if cfg.AuditLogFile != "" {
+		cfg.AuditLogFile = util.CleanAndExpandPath(cfg.AuditLogFile)
+	}
+
+	if cfg.RPCRateLimit < 0 || cfg.RPCClientRateLimit < 0 {
+		return fmt.Errorf("invalid RPC rate limits: %v, %v, should not be negative", cfg.RPCRateLimit, cfg.RPCClientRateLimit)
+	}
+	if cfg.RPCRateBurst < 0 || cfg.RPCClientRateBurst < 0 {
+		return fmt.Errorf("invalid RPC rate bursts: %v, %v, should not be negative", cfg.RPCRateBurst, cfg.RPCClientRateBurst)
+	}
+	cfg.setDefaultRPCRateBursts()
+
+	for i, hookCmd := range cfg.HookCommands {
+		cfg.HookCommands[i] = util.CleanAndExpandPath(hookCmd)
+	}
+	for _, hookURL := range cfg.HookURLs {
+		if _, err := url.ParseRequestURI(hookURL); err != nil {
+			return fmt.Errorf("invalid hook URL %s: %w", hookURL, err)
+		}
+	}
+	if len(cfg.HookCommands)+len(cfg.HookURLs) > 0 && cfg.HookTimeout == 0 {
+		cfg.HookTimeout = defaultHookTimeout
+	}
+
+	if cfg.VoteSLOTarget < 0 || cfg.VoteSLOTarget > 1 {
+		return fmt.Errorf("invalid vote SLO target: %v, should be within [0, 1]", cfg.VoteSLOTarget)
+	}
+	if cfg.VoteSLOTarget > 0 && cfg.VoteSLOWindow == 0 {
+		cfg.VoteSLOWindow = defaultVoteSLOWindow
+	}
+	// the windows are sized as ints, which are 32 bits on 32-bit platforms,
+	// e.g., armv7
+	if uint64(cfg.VoteSLOWindow) > math.MaxInt {
+		return fmt.Errorf("invalid vote SLO window: %d, should not exceed %d", cfg.VoteSLOWindow, math.MaxInt)
+	}
+
+	if cfg.RandRunwayMargin > 0 && cfg.RandRunwayMargin >= cfg.MinRandHeightGap {
+		return fmt.Errorf("invalid rand runway margin: %d, should be lower than the min rand height gap %d",
+			cfg.RandRunwayMargin, cfg.MinRandHeightGap)
+	}
+
+	if cfg.ObserverMode && cfg.ObserverWindow == 0 {
+		cfg.ObserverWindow = defaultObserverWindow
+	}
+	if uint64(cfg.ObserverWindow) > math.MaxInt {
+		return fmt.Errorf("invalid observer window: %d, should not exceed %d", cfg.ObserverWindow, math.MaxInt)
+	}
+
+	if len(cfg.MaintenanceWindows) > 0 {
+		if _, err := maintenance.NewSchedule(cfg.MaintenanceWindows); err != nil {
+			return err
+		}
+		if cfg.MaintenanceLeadTime < 0 {
+			return fmt.Errorf("invalid maintenance lead time: %v, should not be negative", cfg.MaintenanceLeadTime)
+		}
+		if cfg.MaintenanceLeadTime == 0 {
+			cfg.MaintenanceLeadTime = defaultMaintenanceLeadTime
+		}
+		if cfg.MaintenanceRandHeightGap == 0 {
+			cfg.MaintenanceRandHeightGap = defaultMaintenanceRandGap
+		}
+		if cfg.MaintenanceRandHeightGap < cfg.MinRandHeightGap {
+			return fmt.Errorf("invalid maintenance rand height gap: %d, should not be lower than the min rand height gap %d",
+				cfg.MaintenanceRandHeightGap, cfg.MinRandHeightGap)
+		}
+	}
+
+	if cfg.ApprovalMode {
+		if _, err := approval.NewVerifier(cfg.ApproverPks); err != nil {
+			return fmt.Errorf("invalid approvers in approval mode: %w", err)
+		}
+	}
+
+	if cfg.ClockSkewCheckInterval > 0 && cfg.MaxClockSkew <= 0 {
+		return fmt.Errorf("invalid max clock skew: %v, should be positive if the clock skew check is enabled", cfg.MaxClockSkew)
+	}
+
+	if cfg.ChainHaltThreshold < 0 {
+		return fmt.Errorf("invalid chain halt threshold: %v, should not be negative", cfg.ChainHaltThreshold)
+	}
+
+	if cfg.SubmissionStagger < 0 {
+		return fmt.Errorf("invalid submission stagger: %v, should not be negative", cfg.SubmissionStagger)
+	}
+
+	if cfg.MaxVoteJitter < 0 {
+		return fmt.Errorf("invalid max vote jitter: %v, should not be negative", cfg.MaxVoteJitter)
+	}
+
+	for _, chainID := range cfg.AllowedChainIDs {
+		if chainID == "" {
+			return fmt.Errorf("invalid allowed chain ID: empty chain ID")
+		}
+	}
+
+	for chainID := range cfg.ChainConfirmationDepths {
+		if chainID == "" {
+			return fmt.Errorf("invalid chain confirmation depth: empty chain ID")
+		}
+	}
+
+	for chainID, chainCfg := range cfg.Chains {
+		if err := chainCfg.validate(); err != nil {
+			return fmt.Errorf("invalid config of chain %s: %w", chainID, err)
+		}
+		// the gas prices of the chain are bounded by the bounds of the
+		// babylon config
+		if cfg.BabylonConfig != nil && cfg.BabylonConfig.HasDynamicGasPrice() {
+			if _, _, _, err := cfg.ChainBBNConfig(chainID).GasPriceBounds(); err != nil {
+				return fmt.Errorf("invalid config of chain %s: %w", chainID, err)
+			}
+		}
+	}
+
 	_, err := net.ResolveTCPAddr("tcp", cfg.RpcListener)
 	if err != nil {
 		return fmt.Errorf("invalid RPC listener address %s, %w", cfg.RpcListener, err)
 	}
 
+	if cfg.HealthListener != "" {
+		if _, err := net.ResolveTCPAddr("tcp", cfg.HealthListener); err != nil {
+			return fmt.Errorf("invalid health listener address %s, %w", cfg.HealthListener, err)
+		}
+	}
+
 	if cfg.Metrics == nil {
 		return fmt.Errorf("empty metrics config")
 	}
@@ -214,3 +732,95 @@ func (cfg *Config) Validate() error {
 	// All good, return the sanitized result.
 	return nil
 }
+
+// EnabledFeatures returns the names of the optional features enabled by the
+// config, for the version info of the daemon
+func (cfg *Config) EnabledFeatures() []string {
+	features := []struct {
+		name    string
+		enabled bool
+	}{
+		{"require-remote-signer", cfg.RequireRemoteSigner},
+		{"halt-on-clock-skew", cfg.HaltOnClockSkew},
+		{"relayer-submission", cfg.SubmissionMode == SubmissionModeRelayer},
+		{"signing-policy", cfg.SigningPolicyFile != ""},
+		{"chain-halt-detection", cfg.ChainHaltThreshold > 0},
+		{"observer-mode", cfg.ObserverMode},
+		{"vote-indexer", cfg.VoteIndexer},
+		{"clone-detection", cfg.CloneDetection},
+		{"discover-fps", cfg.DiscoverFps},
+		{"sign-finality-rpc", cfg.SignFinalityRPC},
+		{"test-rpc", cfg.EnableTestRPC},
+		{"approval-mode", cfg.ApprovalMode},
+		{"health-probes", cfg.HealthListener != ""},
+		{"idle-unload", cfg.IdleUnloadDelay > 0},
+		{"dynamic-gas-price", cfg.BabylonConfig != nil && cfg.BabylonConfig.HasDynamicGasPrice()},
+	}
+
+	var enabled []string
+	for _, f := range features {
+		if f.enabled {
+			enabled = append(enabled, f.name)
+		}
+	}
+
+	return enabled
+}
+
+// AllChainIDs returns the sorted IDs of the chain of the babylon config and
+// the chains configured by their own sections
+func (cfg *Config) AllChainIDs() []string {
+	chainIDs := make(map[string]struct{}, len(cfg.Chains)+1)
+	if cfg.BabylonConfig != nil {
+		chainIDs[cfg.BabylonConfig.ChainID] = struct{}{}
+	}
+	for chainID := range cfg.Chains {
+		chainIDs[chainID] = struct{}{}
+	}
+
+	sorted := make([]string, 0, len(chainIDs))
+	for chainID := range chainIDs {
+		sorted = append(sorted, chainID)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// setDefaultRPCRateBursts sets the bursts of the RPC rate limits left unset
+// to the rates rounded up, as a limit without a burst would reject every
+// request
+func (cfg *Config) setDefaultRPCRateBursts() {
+	if cfg.RPCRateLimit > 0 && cfg.RPCRateBurst == 0 {
+		cfg.RPCRateBurst = int(math.Ceil(cfg.RPCRateLimit))
+	}
+	if cfg.RPCClientRateLimit > 0 && cfg.RPCClientRateBurst == 0 {
+		cfg.RPCClientRateBurst = int(math.Ceil(cfg.RPCClientRateLimit))
+	}
+}
+
+// IsChainIDAllowed returns whether finality providers can be created and
+// registered with the given chain ID
+func (cfg *Config) IsChainIDAllowed(chainID string) bool {
+	if len(cfg.AllowedChainIDs) == 0 {
+		return true
+	}
+
+	for _, allowed := range cfg.AllowedChainIDs {
+		if allowed == chainID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChainConfirmationDepth returns the number of blocks required on top of a
+// block of the given chain before it is signed
+func (cfg *Config) ChainConfirmationDepth(chainID string) uint64 {
+	if depth, ok := cfg.ChainConfirmationDepths[chainID]; ok {
+		return depth
+	}
+
+	return cfg.ConfirmationDepth
+}
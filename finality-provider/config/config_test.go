@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDefaultRPCRateBursts(t *testing.T) {
+	testCases := []struct {
+		name                string
+		rate                float64
+		burst               int
+		expectedBurst       int
+		clientRate          float64
+		clientBurst         int
+		expectedClientBurst int
+	}{
+		{
+			name: "no burst is set for the unlimited rates",
+		},
+		{
+			name:                "the unset bursts are the rates rounded up",
+			rate:                2.5,
+			expectedBurst:       3,
+			clientRate:          0.5,
+			expectedClientBurst: 1,
+		},
+		{
+			name:                "the bursts set are kept",
+			rate:                10,
+			burst:               20,
+			expectedBurst:       20,
+			clientRate:          1,
+			clientBurst:         5,
+			expectedClientBurst: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				RPCRateLimit:       tc.rate,
+				RPCRateBurst:       tc.burst,
+				RPCClientRateLimit: tc.clientRate,
+				RPCClientRateBurst: tc.clientBurst,
+			}
+
+			cfg.setDefaultRPCRateBursts()
+			require.Equal(t, tc.expectedBurst, cfg.RPCRateBurst)
+			require.Equal(t, tc.expectedClientBurst, cfg.RPCClientRateBurst)
+		})
+	}
+}
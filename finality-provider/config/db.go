@@ -1,13 +1,17 @@
 package config
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/util"
 )
 
 const (
-	defaultDbName = "finality-provider.db"
+	defaultDbName             = "finality-provider.db"
+	defaultCompactionInterval = 24 * time.Hour
 )
 
 type DBConfig struct {
@@ -38,6 +42,11 @@ type DBConfig struct {
 	// DBTimeout specifies the timeout value to use when opening the wallet
 	// database.
 	DBTimeout time.Duration `long:"dbtimeout" description:"Specifies the timeout value to use when opening the wallet database."`
+
+	// CompactionInterval is the interval between each compaction of the
+	// database while the daemon is running. The database is unavailable
+	// for the duration of the compaction.
+	CompactionInterval time.Duration `long:"compactioninterval" description:"The interval between each compaction of the database while the daemon is running, which is disabled if the value is 0. The database is unavailable for the duration of the compaction."`
 }
 
 func DefaultDBConfig() *DBConfig {
@@ -46,12 +55,13 @@ func DefaultDBConfig() *DBConfig {
 
 func DefaultDBConfigWithHomePath(homePath string) *DBConfig {
 	return &DBConfig{
-		DBPath:            DataDir(homePath),
-		DBFileName:        defaultDbName,
-		NoFreelistSync:    true,
-		AutoCompact:       false,
-		AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
-		DBTimeout:         kvdb.DefaultDBTimeout,
+		DBPath:             DataDir(homePath),
+		DBFileName:         defaultDbName,
+		NoFreelistSync:     true,
+		AutoCompact:        false,
+		AutoCompactMinAge:  kvdb.DefaultBoltAutoCompactMinAge,
+		DBTimeout:          kvdb.DefaultDBTimeout,
+		CompactionInterval: defaultCompactionInterval,
 	}
 
 }
@@ -67,6 +77,15 @@ func (db *DBConfig) DBConfigToBoltBackendConfig() *kvdb.BoltBackendConfig {
 	}
 }
 
+// DBFile returns the path of the database file
+func (db *DBConfig) DBFile() string {
+	return filepath.Join(db.DBPath, db.DBFileName)
+}
+
 func (db *DBConfig) GetDbBackend() (kvdb.Backend, error) {
+	if err := util.CheckDBFileSize(db.DBPath, db.DBFileName); err != nil {
+		return nil, err
+	}
+
 	return kvdb.GetBoltBackend(db.DBConfigToBoltBackendConfig())
 }
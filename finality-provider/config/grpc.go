@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	defaultGRPCMaxMsgSize        = 16 * 1024 * 1024
+	defaultGRPCKeepAliveTimeout  = 20 * time.Second
+	defaultGRPCConnectionTimeout = 20 * time.Second
+	// minGRPCKeepAliveInterval is the minimum interval of the keep-alive
+	// pings, below which the pings are rejected by the gRPC servers
+	minGRPCKeepAliveInterval = 10 * time.Second
+)
+
+// GRPCConfig configures the gRPC server of the daemon and the gRPC clients it
+// connects to the EOTS manager and the active daemon with
+type GRPCConfig struct {
+	MaxRecvMsgSize    int           `long:"maxrecvmsgsize" description:"The maximum size in bytes of a gRPC message that can be received"`
+	MaxSendMsgSize    int           `long:"maxsendmsgsize" description:"The maximum size in bytes of a gRPC message that can be sent"`
+	KeepAliveInterval time.Duration `long:"keepaliveinterval" description:"The interval of inactivity after which the server pings the clients to detect the broken connections, which is disabled if the value is 0"`
+	KeepAliveTimeout  time.Duration `long:"keepalivetimeout" description:"The time to wait for the response to a keep-alive ping before closing the connection"`
+	ConnectionTimeout time.Duration `long:"connectiontimeout" description:"The timeout of establishing a gRPC connection"`
+}
+
+func DefaultGRPCConfig() GRPCConfig {
+	return GRPCConfig{
+		MaxRecvMsgSize:    defaultGRPCMaxMsgSize,
+		MaxSendMsgSize:    defaultGRPCMaxMsgSize,
+		KeepAliveTimeout:  defaultGRPCKeepAliveTimeout,
+		ConnectionTimeout: defaultGRPCConnectionTimeout,
+	}
+}
+
+// Validate fills the options missing from the config files predating them
+// with the defaults
+func (cfg *GRPCConfig) Validate() error {
+	if cfg.MaxRecvMsgSize < 0 || cfg.MaxSendMsgSize < 0 {
+		return fmt.Errorf("invalid gRPC message size: should not be negative")
+	}
+	if cfg.MaxRecvMsgSize == 0 {
+		cfg.MaxRecvMsgSize = defaultGRPCMaxMsgSize
+	}
+	if cfg.MaxSendMsgSize == 0 {
+		cfg.MaxSendMsgSize = defaultGRPCMaxMsgSize
+	}
+	if cfg.KeepAliveInterval != 0 && cfg.KeepAliveInterval < minGRPCKeepAliveInterval {
+		return fmt.Errorf("invalid gRPC keep-alive interval: should be at least %v", minGRPCKeepAliveInterval)
+	}
+	if cfg.KeepAliveTimeout <= 0 {
+		cfg.KeepAliveTimeout = defaultGRPCKeepAliveTimeout
+	}
+	if cfg.ConnectionTimeout <= 0 {
+		cfg.ConnectionTimeout = defaultGRPCConnectionTimeout
+	}
+
+	return nil
+}
+
+// ServerOptions returns the options of the gRPC server of the daemon
+func (cfg *GRPCConfig) ServerOptions() []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxSendMsgSize),
+		grpc.ConnectionTimeout(cfg.ConnectionTimeout),
+		// allow the keep-alive pings of the clients
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             minGRPCKeepAliveInterval,
+			PermitWithoutStream: true,
+		}),
+	}
+	if cfg.KeepAliveInterval > 0 {
+		opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.KeepAliveInterval,
+			Timeout: cfg.KeepAliveTimeout,
+		}))
+	}
+
+	return opts
+}
+
+// DialOptions returns the options of the gRPC clients of the daemon
+func (cfg *GRPCConfig) DialOptions() []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(cfg.MaxRecvMsgSize),
+			grpc.MaxCallSendMsgSize(cfg.MaxSendMsgSize),
+		),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: cfg.ConnectionTimeout,
+		}),
+	}
+	if cfg.KeepAliveInterval > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepAliveInterval,
+			Timeout:             cfg.KeepAliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	return opts
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       GRPCConfig
+		expectErr bool
+		expected  GRPCConfig
+	}{
+		{
+			name:     "the options missing from an old config file are set to the defaults",
+			cfg:      GRPCConfig{},
+			expected: DefaultGRPCConfig(),
+		},
+		{
+			name: "the options set are kept",
+			cfg: GRPCConfig{
+				MaxRecvMsgSize:    1024,
+				MaxSendMsgSize:    2048,
+				KeepAliveInterval: time.Minute,
+				KeepAliveTimeout:  time.Second,
+				ConnectionTimeout: time.Second,
+			},
+			expected: GRPCConfig{
+				MaxRecvMsgSize:    1024,
+				MaxSendMsgSize:    2048,
+				KeepAliveInterval: time.Minute,
+				KeepAliveTimeout:  time.Second,
+				ConnectionTimeout: time.Second,
+			},
+		},
+		{
+			name:      "a negative message size is rejected",
+			cfg:       GRPCConfig{MaxRecvMsgSize: -1},
+			expectErr: true,
+		},
+		{
+			name:      "a keep-alive interval the servers reject is rejected",
+			cfg:       GRPCConfig{KeepAliveInterval: time.Second},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, tc.cfg)
+		})
+	}
+}
+
+func TestGRPCConfigMaxMsgSize(t *testing.T) {
+	maxMsgSize := 1024
+	cfg := DefaultGRPCConfig()
+	cfg.MaxRecvMsgSize = maxMsgSize
+	cfg.KeepAliveInterval = minGRPCKeepAliveInterval
+	require.NoError(t, cfg.Validate())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(cfg.ServerOptions()...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial(lis.Addr().String(), append(cfg.DialOptions(), grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+	client := healthpb.NewHealthClient(conn)
+
+	res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	// a message larger than the limit of the server is rejected
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: strings.Repeat("s", maxMsgSize)})
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
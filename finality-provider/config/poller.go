@@ -1,11 +1,22 @@
 package config
 
-import "time"
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
 
 var (
-	defaultBufferSize        = uint32(1000)
-	defaultPollingInterval   = 20 * time.Second
-	defaultStaticStartHeight = uint64(1)
+	defaultBufferSize         = uint32(1000)
+	defaultPollingInterval    = 20 * time.Second
+	defaultStaticStartHeight  = uint64(1)
+	defaultReorgCheckDepth    = uint64(10)
+	defaultQueryRetryAttempts = uint(5)
+	defaultQueryRetryDelay    = 400 * time.Millisecond
+	defaultMaxFailedCycles    = uint32(20)
 )
 
 type ChainPollerConfig struct {
@@ -13,6 +24,15 @@ type ChainPollerConfig struct {
 	PollInterval                   time.Duration `long:"pollinterval" description:"The interval between each polling of Babylon blocks"`
 	StaticChainScanningStartHeight uint64        `long:"staticchainscanningstartheight" description:"The static height from which we start polling the chain"`
 	AutoChainScanningMode          bool          `long:"autochainscanningmode" description:"Automatically discover the height from which to start polling the chain"`
+	ReorgCheckDepth                uint64        `long:"reorgcheckdepth" description:"The number of recently polled blocks whose hashes are checked against the chain to detect reorgs, which is disabled if the value is 0"`
+	AutoCorrectStartHeight         bool          `long:"autocorrectstartheight" description:"Raise a static start height below the last voted height of a finality provider or the finality activation height of the chain to the lowest useful height instead of failing to start"`
+	QueryRetryAttempts             uint          `long:"queryretryattempts" description:"The number of attempts of each query of the consumer chain before it fails"`
+	QueryRetryDelay                time.Duration `long:"queryretrydelay" description:"The delay between the attempts of a query of the consumer chain"`
+	MaxFailedCycles                uint32        `long:"maxfailedcycles" description:"The number of consecutive polling cycles failing to retrieve a block after which the daemon exits"`
+
+	// StaticStartHeights overrides StaticChainScanningStartHeight for the
+	// finality providers registered at different times
+	StaticStartHeights map[string]uint64 `long:"staticstartheight" description:"The static height from which the chain is polled for a finality provider in the format <hex BTC public key>:<height>, which overrides the static chain scanning start height; can be specified multiple times"`
 }
 
 func DefaultChainPollerConfig() ChainPollerConfig {
@@ -21,5 +41,70 @@ func DefaultChainPollerConfig() ChainPollerConfig {
 		PollInterval:                   defaultPollingInterval,
 		StaticChainScanningStartHeight: defaultStaticStartHeight,
 		AutoChainScanningMode:          true,
+		ReorgCheckDepth:                defaultReorgCheckDepth,
+		QueryRetryAttempts:             defaultQueryRetryAttempts,
+		QueryRetryDelay:                defaultQueryRetryDelay,
+		MaxFailedCycles:                defaultMaxFailedCycles,
+	}
+}
+
+// Validate fills the options missing from the config files predating them
+// with the defaults, and checks the per finality provider start heights and
+// normalizes their keys to lower case, so that they match the hex BTC public
+// keys
+func (cfg *ChainPollerConfig) Validate() error {
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.PollInterval < 0 {
+		return fmt.Errorf("invalid poll interval: should not be negative")
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = defaultPollingInterval
+	}
+	if cfg.QueryRetryAttempts == 0 {
+		cfg.QueryRetryAttempts = defaultQueryRetryAttempts
+	}
+	if cfg.QueryRetryDelay < 0 {
+		return fmt.Errorf("invalid query retry delay: should not be negative")
+	}
+	if cfg.QueryRetryDelay == 0 {
+		cfg.QueryRetryDelay = defaultQueryRetryDelay
 	}
+	if cfg.MaxFailedCycles == 0 {
+		cfg.MaxFailedCycles = defaultMaxFailedCycles
+	}
+
+	if len(cfg.StaticStartHeights) == 0 {
+		return nil
+	}
+
+	startHeights := make(map[string]uint64, len(cfg.StaticStartHeights))
+	for pkHex, height := range cfg.StaticStartHeights {
+		pkHex = strings.ToLower(pkHex)
+		pkBytes, err := hex.DecodeString(pkHex)
+		if err != nil {
+			return fmt.Errorf("invalid BTC public key %s of the static start height: %w", pkHex, err)
+		}
+		if _, err := schnorr.ParsePubKey(pkBytes); err != nil {
+			return fmt.Errorf("invalid BTC public key %s of the static start height: %w", pkHex, err)
+		}
+		if height == 0 {
+			return fmt.Errorf("invalid static start height of %s: should be positive", pkHex)
+		}
+		startHeights[pkHex] = height
+	}
+	cfg.StaticStartHeights = startHeights
+
+	return nil
+}
+
+// StaticStartHeight returns the static height from which the chain is
+// polled for the finality provider with the given hex BTC public key
+func (cfg *ChainPollerConfig) StaticStartHeight(fpPkHex string) uint64 {
+	if height, ok := cfg.StaticStartHeights[fpPkHex]; ok {
+		return height
+	}
+
+	return cfg.StaticChainScanningStartHeight
 }
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticStartHeights(t *testing.T) {
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pkHex := strings.ToUpper(hex.EncodeToString(schnorr.SerializePubKey(sk.PubKey())))
+
+	t.Run("the start heights of the finality providers override the default one", func(t *testing.T) {
+		cfg := DefaultChainPollerConfig()
+		cfg.StaticChainScanningStartHeight = 10
+		cfg.StaticStartHeights = map[string]uint64{pkHex: 100}
+
+		require.NoError(t, cfg.Validate())
+		// the keys are matched in lower case
+		require.Equal(t, uint64(100), cfg.StaticStartHeight(strings.ToLower(pkHex)))
+		require.Equal(t, uint64(10), cfg.StaticStartHeight("other-fp"))
+	})
+
+	t.Run("an invalid BTC public key is rejected", func(t *testing.T) {
+		cfg := DefaultChainPollerConfig()
+		cfg.StaticStartHeights = map[string]uint64{"not-hex": 100}
+		require.Error(t, cfg.Validate())
+
+		cfg.StaticStartHeights = map[string]uint64{"0102": 100}
+		require.Error(t, cfg.Validate())
+	})
+
+	t.Run("a zero start height is rejected", func(t *testing.T) {
+		cfg := DefaultChainPollerConfig()
+		cfg.StaticStartHeights = map[string]uint64{pkHex: 0}
+		require.Error(t, cfg.Validate())
+	})
+}
+
+func TestChainPollerConfigValidate(t *testing.T) {
+	t.Run("the options missing from an old config file get the defaults", func(t *testing.T) {
+		cfg := ChainPollerConfig{}
+		require.NoError(t, cfg.Validate())
+
+		defaultCfg := DefaultChainPollerConfig()
+		require.Equal(t, defaultCfg.BufferSize, cfg.BufferSize)
+		require.Equal(t, defaultCfg.PollInterval, cfg.PollInterval)
+		require.Equal(t, defaultCfg.QueryRetryAttempts, cfg.QueryRetryAttempts)
+		require.Equal(t, defaultCfg.QueryRetryDelay, cfg.QueryRetryDelay)
+		require.Equal(t, defaultCfg.MaxFailedCycles, cfg.MaxFailedCycles)
+	})
+
+	t.Run("the configured options are kept", func(t *testing.T) {
+		cfg := DefaultChainPollerConfig()
+		cfg.QueryRetryAttempts = 2
+		cfg.QueryRetryDelay = time.Second
+		cfg.MaxFailedCycles = 3
+		require.NoError(t, cfg.Validate())
+
+		require.Equal(t, uint(2), cfg.QueryRetryAttempts)
+		require.Equal(t, time.Second, cfg.QueryRetryDelay)
+		require.Equal(t, uint32(3), cfg.MaxFailedCycles)
+	})
+
+	t.Run("a negative interval is rejected", func(t *testing.T) {
+		cfg := DefaultChainPollerConfig()
+		cfg.PollInterval = -time.Second
+		require.Error(t, cfg.Validate())
+
+		cfg = DefaultChainPollerConfig()
+		cfg.QueryRetryDelay = -time.Second
+		require.Error(t, cfg.Validate())
+	})
+}
@@ -0,0 +1,181 @@
+package eventbus
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Bus is an in-process bus of typed events, which decouples the subsystems
+// publishing the events from those observing them. Each subscription
+// receives the events of its type in the order they are published, one at
+// a time on its own goroutine, so that a slow subscriber never blocks the
+// publishers or the other subscribers
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[reflect.Type][]*subscription
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+func New() *Bus {
+	return &Bus{
+		subs: make(map[reflect.Type][]*subscription),
+	}
+}
+
+// Subscribe invokes the handler on each event of type T published after the
+// subscription, until the returned function is called or the bus is closed
+func Subscribe[T any](b *Bus, handler func(T)) (unsubscribe func()) {
+	t := typeOf[T]()
+	sub := newSubscription(func(ev any) {
+		handler(ev.(T))
+	})
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return func() {}
+	}
+	b.subs[t] = append(b.subs[t], sub)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		sub.loop()
+	}()
+
+	return func() {
+		b.remove(t, sub)
+		sub.stop()
+	}
+}
+
+// Publish queues the event for the subscribers of its type without blocking
+// and returns the number of the subscribers, which is 0 if the event is
+// dropped for having no subscriber
+func Publish[T any](b *Bus, ev T) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	subs := b.subs[typeOf[T]()]
+	for _, sub := range subs {
+		sub.push(ev)
+	}
+
+	return len(subs)
+}
+
+// Close stops all the subscriptions and waits for their handlers to return.
+// The events still queued are dropped, and the events published afterwards
+// have no subscriber
+func (b *Bus) Close() {
+	b.mu.Lock()
+	var subs []*subscription
+	if !b.closed {
+		b.closed = true
+		for _, s := range b.subs {
+			subs = append(subs, s...)
+		}
+		b.subs = make(map[reflect.Type][]*subscription)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.stop()
+	}
+	b.wg.Wait()
+}
+
+func (b *Bus) remove(t reflect.Type, sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[t]
+	for i, s := range subs {
+		if s == sub {
+			b.subs[t] = append(subs[:i:i], subs[i+1:]...)
+			return
+		}
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// subscription queues the events for its handler without bound, so that
+// publishing never blocks
+type subscription struct {
+	handle func(any)
+
+	mu    sync.Mutex
+	queue []any
+
+	signal   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newSubscription(handle func(any)) *subscription {
+	return &subscription{
+		handle: handle,
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+}
+
+func (s *subscription) push(ev any) {
+	s.mu.Lock()
+	s.queue = append(s.queue, ev)
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription) pop() (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		return nil, false
+	}
+	ev := s.queue[0]
+	s.queue[0] = nil
+	s.queue = s.queue[1:]
+
+	return ev, true
+}
+
+func (s *subscription) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *subscription) loop() {
+	for {
+		select {
+		case <-s.signal:
+		case <-s.done:
+			return
+		}
+
+		for {
+			ev, ok := s.pop()
+			if !ok {
+				break
+			}
+			select {
+			case <-s.done:
+				return
+			default:
+			}
+			s.handle(ev)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package eventbus_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/eventbus"
+)
+
+type testEvent struct {
+	n int
+}
+
+type otherEvent struct{}
+
+func TestPublishSubscribe(t *testing.T) {
+	bus := eventbus.New()
+	defer bus.Close()
+
+	const numEvents = 100
+	var (
+		mu       sync.Mutex
+		received []int
+	)
+	done := make(chan struct{})
+	eventbus.Subscribe(bus, func(ev *testEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, ev.n)
+		if len(received) == numEvents {
+			close(done)
+		}
+	})
+	// the subscribers of other types are not invoked
+	eventbus.Subscribe(bus, func(ev *otherEvent) {
+		t.Error("unexpected event")
+	})
+
+	for i := 0; i < numEvents; i++ {
+		require.Equal(t, 1, eventbus.Publish(bus, &testEvent{n: i}))
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the events")
+	}
+
+	// the events are received in the order they are published
+	for i, n := range received {
+		require.Equal(t, i, n)
+	}
+}
+
+func TestUnsubscribeAndClose(t *testing.T) {
+	bus := eventbus.New()
+
+	unsubscribe := eventbus.Subscribe(bus, func(ev *testEvent) {})
+	require.Equal(t, 1, eventbus.Publish(bus, &testEvent{}))
+
+	unsubscribe()
+	require.Zero(t, eventbus.Publish(bus, &testEvent{}))
+
+	eventbus.Subscribe(bus, func(ev *testEvent) {})
+	bus.Close()
+	require.Zero(t, eventbus.Publish(bus, &testEvent{}))
+
+	// subscribing to a closed bus has no effect
+	eventbus.Subscribe(bus, func(ev *testEvent) {})
+	require.Zero(t, eventbus.Publish(bus, &testEvent{}))
+}
@@ -0,0 +1,6 @@
+//go:build faultinject
+
+package faultinject
+
+// Enabled tells whether the binary is built with the faultinject build tag
+const Enabled = true
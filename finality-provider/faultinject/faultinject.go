@@ -0,0 +1,160 @@
+// Package faultinject injects faults into the finality provider daemon to
+// validate its recovery logic in integration environments. The faults are
+// only injected in binaries built with the faultinject build tag, e.g.,
+// `make build BUILD_TAGS=faultinject`, and the injection points are no-ops
+// otherwise
+package faultinject
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Kind is the kind of a fault
+type Kind string
+
+const (
+	// DropTx drops the txs to the consumer chain instead of sending them
+	DropTx Kind = "drop-tx"
+	// DelayRPC delays the requests to the consumer chain
+	DelayRPC Kind = "delay-rpc"
+	// CorruptStoreWrite corrupts the finality provider records written to
+	// the store, so that they can no longer be decoded
+	CorruptStoreWrite Kind = "corrupt-store-write"
+)
+
+var (
+	// ErrDisabled is returned when injecting faults into a binary built
+	// without the faultinject build tag
+	ErrDisabled = errors.New("fault injection is not enabled in this build")
+	// ErrTxDropped is returned for the txs dropped by an injected fault
+	ErrTxDropped = errors.New("the tx is dropped by fault injection")
+)
+
+// Fault is an injected fault
+type Fault struct {
+	Kind Kind
+	// Times is the number of times the fault is left to hit, where zero
+	// means the fault hits until it is cleared
+	Times uint32
+	// Delay is the delay of the requests, only used by DelayRPC
+	Delay time.Duration
+}
+
+var (
+	mu     sync.Mutex
+	faults = make(map[Kind]*Fault)
+)
+
+// ParseKind parses the kind of a fault
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case DropTx, DelayRPC, CorruptStoreWrite:
+		return k, nil
+	default:
+		return "", fmt.Errorf("unknown fault kind %q, expected one of %s, %s, %s",
+			s, DropTx, DelayRPC, CorruptStoreWrite)
+	}
+}
+
+// Inject injects the fault, replacing the previous fault of the same kind
+func Inject(f Fault) error {
+	if !Enabled {
+		return ErrDisabled
+	}
+	if _, err := ParseKind(string(f.Kind)); err != nil {
+		return err
+	}
+	if f.Kind == DelayRPC && f.Delay <= 0 {
+		return fmt.Errorf("the delay of the %s fault should be positive", DelayRPC)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	faults[f.Kind] = &f
+
+	return nil
+}
+
+// Clear clears the fault of the given kind, or all the faults if the kind is
+// empty
+func Clear(kind Kind) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if kind == "" {
+		faults = make(map[Kind]*Fault)
+		return
+	}
+	delete(faults, kind)
+}
+
+// Active returns the injected faults sorted by kind
+func Active() []Fault {
+	mu.Lock()
+	defer mu.Unlock()
+
+	active := make([]Fault, 0, len(faults))
+	for _, f := range faults {
+		active = append(active, *f)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].Kind < active[j].Kind
+	})
+
+	return active
+}
+
+// hit returns the fault of the given kind if it is injected, consuming one
+// of its remaining times
+func hit(kind Kind) (Fault, bool) {
+	if !Enabled {
+		return Fault{}, false
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	f, ok := faults[kind]
+	if !ok {
+		return Fault{}, false
+	}
+	if f.Times > 0 {
+		f.Times--
+		if f.Times == 0 {
+			delete(faults, kind)
+		}
+	}
+
+	return *f, true
+}
+
+// MaybeDropTx returns ErrTxDropped if the DropTx fault is injected
+func MaybeDropTx() error {
+	if _, ok := hit(DropTx); ok {
+		return ErrTxDropped
+	}
+
+	return nil
+}
+
+// MaybeDelay blocks for the delay of the DelayRPC fault if it is injected
+func MaybeDelay() {
+	if f, ok := hit(DelayRPC); ok {
+		time.Sleep(f.Delay)
+	}
+}
+
+// MaybeCorrupt returns a corrupted copy of the data if the CorruptStoreWrite
+// fault is injected, or the data as is otherwise
+func MaybeCorrupt(data []byte) []byte {
+	if _, ok := hit(CorruptStoreWrite); !ok {
+		return data
+	}
+
+	// a leading zero byte is an invalid protobuf tag
+	return append([]byte{0}, data...)
+}
@@ -0,0 +1,48 @@
+package faultinject_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/faultinject"
+)
+
+// TestFaultInjection runs with and without the faultinject build tag
+func TestFaultInjection(t *testing.T) {
+	defer faultinject.Clear("")
+
+	err := faultinject.Inject(faultinject.Fault{Kind: faultinject.DropTx, Times: 2})
+	if !faultinject.Enabled {
+		require.ErrorIs(t, err, faultinject.ErrDisabled)
+		require.NoError(t, faultinject.MaybeDropTx())
+		require.Equal(t, []byte{1}, faultinject.MaybeCorrupt([]byte{1}))
+		return
+	}
+	require.NoError(t, err)
+
+	// the fault is cleared after hitting the given times
+	require.ErrorIs(t, faultinject.MaybeDropTx(), faultinject.ErrTxDropped)
+	require.ErrorIs(t, faultinject.MaybeDropTx(), faultinject.ErrTxDropped)
+	require.NoError(t, faultinject.MaybeDropTx())
+	require.Empty(t, faultinject.Active())
+
+	// the delay fault requires a positive delay
+	err = faultinject.Inject(faultinject.Fault{Kind: faultinject.DelayRPC})
+	require.Error(t, err)
+	err = faultinject.Inject(faultinject.Fault{Kind: faultinject.DelayRPC, Delay: time.Millisecond})
+	require.NoError(t, err)
+	_, err = faultinject.ParseKind("unknown")
+	require.Error(t, err)
+
+	// faults without times hit until they are cleared
+	err = faultinject.Inject(faultinject.Fault{Kind: faultinject.CorruptStoreWrite})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, []byte{0, 1}, faultinject.MaybeCorrupt([]byte{1}))
+	}
+	require.Len(t, faultinject.Active(), 2)
+	faultinject.Clear(faultinject.CorruptStoreWrite)
+	require.Equal(t, []byte{1}, faultinject.MaybeCorrupt([]byte{1}))
+}
@@ -0,0 +1,101 @@
+package hooks
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// eventQueueSize is the number of events buffered for the hooks, beyond
+// which new events are dropped rather than blocking the instances
+const eventQueueSize = 100
+
+// Dispatcher delivers the events to the registered hooks in the background
+type Dispatcher struct {
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	hooks   []Hook
+	stopped bool
+
+	events chan *Event
+	wg     sync.WaitGroup
+}
+
+func NewDispatcher(logger *zap.Logger, hooks ...Hook) *Dispatcher {
+	d := &Dispatcher{
+		logger: logger,
+		hooks:  hooks,
+		events: make(chan *Event, eventQueueSize),
+	}
+
+	d.wg.Add(1)
+	go d.loop()
+
+	return d
+}
+
+// Register adds a hook that is invoked on the following events
+func (d *Dispatcher) Register(h Hook) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.hooks = append(d.hooks, h)
+}
+
+// Dispatch queues the event for the hooks without blocking
+func (d *Dispatcher) Dispatch(e *Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.stopped || len(d.hooks) == 0 {
+		return
+	}
+
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	select {
+	case d.events <- e:
+	default:
+		d.logger.Warn("the hook event queue is full, dropping the event",
+			zap.String("event", string(e.Type)), zap.String("pk", e.FpBtcPk))
+	}
+}
+
+// Stop delivers the queued events and stops the dispatcher, after which
+// no event can be dispatched
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.events)
+	}
+	d.mu.Unlock()
+
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) loop() {
+	defer d.wg.Done()
+
+	for e := range d.events {
+		d.mu.RLock()
+		hooks := make([]Hook, len(d.hooks))
+		copy(hooks, d.hooks)
+		d.mu.RUnlock()
+
+		for _, h := range hooks {
+			if err := h.OnEvent(e); err != nil {
+				d.logger.Warn("failed to run the hook",
+					zap.String("hook", h.Name()),
+					zap.String("event", string(e.Type)),
+					zap.String("pk", e.FpBtcPk),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
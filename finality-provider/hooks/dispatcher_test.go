@@ -0,0 +1,83 @@
+package hooks_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+)
+
+// recordingHook records the events it handles, and fails them if err is set
+type recordingHook struct {
+	name string
+	err  error
+
+	mu     sync.Mutex
+	events []*hooks.Event
+}
+
+func (h *recordingHook) Name() string {
+	return h.name
+}
+
+func (h *recordingHook) OnEvent(e *hooks.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events = append(h.events, e)
+
+	return h.err
+}
+
+func (h *recordingHook) received() []*hooks.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.events
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Run("the events are delivered in order to all the hooks", func(t *testing.T) {
+		failing := &recordingHook{name: "failing", err: errors.New("the hook failed")}
+		working := &recordingHook{name: "working"}
+		d := hooks.NewDispatcher(zap.NewNop(), failing)
+		d.Register(working)
+
+		for h := uint64(1); h <= 10; h++ {
+			d.Dispatch(&hooks.Event{Type: hooks.EventVoteSubmitted, Height: h})
+		}
+		d.Stop()
+
+		for _, hook := range []*recordingHook{failing, working} {
+			events := hook.received()
+			require.Len(t, events, 10)
+			for i, e := range events {
+				require.Equal(t, uint64(i+1), e.Height)
+				require.False(t, e.Timestamp.IsZero())
+			}
+		}
+	})
+
+	t.Run("no event is dispatched once stopped", func(t *testing.T) {
+		hook := &recordingHook{name: "hook"}
+		d := hooks.NewDispatcher(zap.NewNop(), hook)
+		d.Stop()
+		d.Stop()
+
+		d.Dispatch(&hooks.Event{Type: hooks.EventInstanceStopped})
+		require.Empty(t, hook.received())
+	})
+
+	t.Run("the events are not queued without hooks", func(t *testing.T) {
+		d := hooks.NewDispatcher(zap.NewNop())
+		defer d.Stop()
+
+		e := &hooks.Event{Type: hooks.EventInstanceStarted}
+		d.Dispatch(e)
+		require.True(t, e.Timestamp.IsZero())
+	})
+}
@@ -0,0 +1,61 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// execWaitDelay bounds the wait for the output of the executable once it is
+// killed on the timeout
+const execWaitDelay = time.Second
+
+// ExecHook runs an executable on each event with the event type as the only
+// argument and the event encoded in JSON on stdin. The event type and the
+// finality provider are also set in the environment variables FPD_EVENT and
+// FPD_FP_BTC_PK for simple shell scripts
+type ExecHook struct {
+	path    string
+	timeout time.Duration
+}
+
+func NewExecHook(path string, timeout time.Duration) *ExecHook {
+	return &ExecHook{
+		path:    path,
+		timeout: timeout,
+	}
+}
+
+func (h *ExecHook) Name() string {
+	return h.path
+}
+
+func (h *ExecHook) OnEvent(e *Event) error {
+	input, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode the event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.path, string(e.Type))
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(),
+		"FPD_EVENT="+string(e.Type),
+		"FPD_FP_BTC_PK="+e.FpBtcPk,
+	)
+	// the children of the executable, e.g., of a shell script, may outlive it
+	// on the timeout, holding its output open
+	cmd.WaitDelay = execWaitDelay
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("the hook failed: %w, output: %s", err, output)
+	}
+
+	return nil
+}
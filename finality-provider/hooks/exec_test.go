@@ -0,0 +1,62 @@
+package hooks_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+)
+
+// writeScript writes an executable shell script running the given commands
+func writeScript(t *testing.T, commands string) string {
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+commands+"\n"), 0o700))
+
+	return path
+}
+
+func TestExecHook(t *testing.T) {
+	e := &hooks.Event{Type: hooks.EventVoteSubmitted, FpBtcPk: "fp-pk", Height: 10, TxHash: "tx-hash"}
+
+	t.Run("the event is passed as the argument, in the environment and on stdin", func(t *testing.T) {
+		outDir := t.TempDir()
+		script := writeScript(t, `echo "$1" > `+outDir+`/arg
+echo "$FPD_EVENT $FPD_FP_BTC_PK" > `+outDir+`/env
+cat > `+outDir+`/stdin`)
+
+		require.NoError(t, hooks.NewExecHook(script, time.Minute).OnEvent(e))
+
+		arg, err := os.ReadFile(filepath.Join(outDir, "arg"))
+		require.NoError(t, err)
+		require.Equal(t, "vote-submitted\n", string(arg))
+		env, err := os.ReadFile(filepath.Join(outDir, "env"))
+		require.NoError(t, err)
+		require.Equal(t, "vote-submitted fp-pk\n", string(env))
+		stdin, err := os.ReadFile(filepath.Join(outDir, "stdin"))
+		require.NoError(t, err)
+		var decoded hooks.Event
+		require.NoError(t, json.Unmarshal(stdin, &decoded))
+		require.Equal(t, *e, decoded)
+	})
+
+	t.Run("a failing executable fails the hook with its output", func(t *testing.T) {
+		script := writeScript(t, `echo "the alert is down"
+exit 1`)
+
+		err := hooks.NewExecHook(script, time.Minute).OnEvent(e)
+		require.ErrorContains(t, err, "the alert is down")
+	})
+
+	t.Run("a slow executable is killed on the timeout", func(t *testing.T) {
+		script := writeScript(t, `sleep 10`)
+
+		start := time.Now()
+		require.Error(t, hooks.NewExecHook(script, 100*time.Millisecond).OnEvent(e))
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+}
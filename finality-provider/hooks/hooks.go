@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"time"
+)
+
+// EventType is the type of the lifecycle events of finality-provider instances
+type EventType string
+
+const (
+	// EventInstanceStarted is emitted after an instance is started
+	EventInstanceStarted EventType = "instance-started"
+	// EventInstanceStopped is emitted after an instance is stopped
+	EventInstanceStopped EventType = "instance-stopped"
+	// EventVoteSubmitted is emitted after a finality signature is submitted
+	EventVoteSubmitted EventType = "vote-submitted"
+	// EventPubRandCommitted is emitted after a commit of public randomness
+	// is included on the consumer chain
+	EventPubRandCommitted EventType = "pub-rand-committed"
+	// EventCatchUpStarted is emitted when an instance falls so far behind the
+	// tip that it switches to catch-up mode
+	EventCatchUpStarted EventType = "catch-up-started"
+	// EventCatchUpFinished is emitted when an instance in catch-up mode is
+	// back in sync with the tip
+	EventCatchUpFinished EventType = "catch-up-finished"
+	// EventStatusChanged is emitted after the status of a finality provider changes
+	EventStatusChanged EventType = "status-changed"
+	// EventError is emitted on a critical error of an instance
+	EventError EventType = "error"
+	// EventVoteSLOBreached is emitted when the rolling compliance of the vote
+	// latency drops below the SLO target
+	EventVoteSLOBreached EventType = "vote-slo-breached"
+	// EventVoteSLORecovered is emitted when the rolling compliance of the
+	// vote latency is back to the SLO target after a breach
+	EventVoteSLORecovered EventType = "vote-slo-recovered"
+	// EventRandRunwayLow is emitted when the randomness committed ahead of
+	// the tip drops below the safety margin
+	EventRandRunwayLow EventType = "rand-runway-low"
+	// EventRandRunwayRecovered is emitted when the randomness committed ahead
+	// of the tip is back to the safety margin after a drop
+	EventRandRunwayRecovered EventType = "rand-runway-recovered"
+	// EventRandGapRepaired is emitted after a gap between the commits of
+	// public randomness is filled
+	EventRandGapRepaired EventType = "rand-gap-repaired"
+	// EventRandGapUnmitigable is emitted for a gap between the commits of
+	// public randomness that cannot be filled, whose heights cannot be voted
+	EventRandGapUnmitigable EventType = "rand-gap-unmitigable"
+	// EventChainHalted is emitted when the consumer chain stops producing
+	// blocks, after which the finality provider is in STANDBY
+	EventChainHalted EventType = "chain-halted"
+	// EventChainResumed is emitted when the consumer chain produces blocks
+	// again after a halt
+	EventChainResumed EventType = "chain-resumed"
+	// EventCloneDetected is emitted when a vote of the finality provider not
+	// submitted by this daemon is found on the consumer chain, after which
+	// the finality provider is in safe mode
+	EventCloneDetected EventType = "clone-detected"
+)
+
+// Event is a lifecycle event of a finality-provider instance
+type Event struct {
+	Type      EventType `json:"type"`
+	FpBtcPk   string    `json:"fp_btc_pk"`
+	Timestamp time.Time `json:"timestamp"`
+	// Height and TxHash are only set for EventVoteSubmitted,
+	// EventPubRandCommitted and EventRandGapRepaired, where Height is the
+	// highest voted height and the start height of the commit respectively.
+	// Height is also set to the tip height for EventCatchUpStarted,
+	// EventCatchUpFinished, EventRandRunwayLow, EventRandRunwayRecovered,
+	// EventChainHalted and EventChainResumed, to the start height of the gap
+	// for EventRandGapUnmitigable, and to the height of the vote for
+	// EventCloneDetected
+	Height uint64 `json:"height,omitempty"`
+	TxHash string `json:"tx_hash,omitempty"`
+	// FromHeight is the lowest voted height of a batch of votes submitted in
+	// one tx, which is only set for EventVoteSubmitted
+	FromHeight uint64 `json:"from_height,omitempty"`
+	// NumPubRand is only set for EventPubRandCommitted, EventRandGapRepaired
+	// and EventRandGapUnmitigable
+	NumPubRand uint64 `json:"num_pub_rand,omitempty"`
+	// BlocksBehind is the gap between the tip and the last processed height,
+	// which is only set for EventCatchUpStarted and EventCatchUpFinished
+	BlocksBehind uint64 `json:"blocks_behind,omitempty"`
+	// OldStatus and Status are only set for EventStatusChanged
+	OldStatus string `json:"old_status,omitempty"`
+	Status    string `json:"status,omitempty"`
+	// Error is only set for EventError, EventCloneDetected and
+	// EventRandGapUnmitigable, where it is the reason the gap cannot be filled
+	Error string `json:"error,omitempty"`
+	// Compliance is the ratio of the recent votes within the latency SLO,
+	// which is only set for EventVoteSLOBreached and EventVoteSLORecovered
+	Compliance float64 `json:"compliance,omitempty"`
+	// RandRunway is the number of blocks of randomness remaining ahead of the
+	// tip, which is only set for EventRandRunwayLow and EventRandRunwayRecovered
+	RandRunway uint64 `json:"rand_runway,omitempty"`
+}
+
+// Hook is invoked on the lifecycle events of finality-provider instances.
+// Hooks are invoked one at a time off the instance loops, so a slow hook
+// delays the following events but never the instances
+type Hook interface {
+	// Name identifies the hook in the logs
+	Name() string
+	// OnEvent handles the event, where the returned error is only logged
+	OnEvent(e *Event) error
+}
@@ -0,0 +1,60 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookHook posts each event encoded in JSON to a URL, e.g., an alerting
+// service. Any response status other than 2xx fails the hook
+type WebhookHook struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+}
+
+func NewWebhookHook(url string, timeout time.Duration) *WebhookHook {
+	return &WebhookHook{
+		url:     url,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+func (h *WebhookHook) Name() string {
+	return h.url
+}
+
+func (h *WebhookHook) OnEvent(e *Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode the event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("the webhook failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("the webhook responded with status %d: %s", res.StatusCode, resBody)
+	}
+
+	return nil
+}
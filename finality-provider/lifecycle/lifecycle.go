@@ -0,0 +1,292 @@
+// Package lifecycle starts the components of the daemon in the order of their
+// dependencies and stops them in the reverse order, each within a timeout
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrStopped = errors.New("the components are stopped and cannot be started again")
+	ErrTimeout = errors.New("the component did not finish in time")
+)
+
+// Component is a subsystem of the daemon. The components it depends on are
+// started before it and stopped after it
+type Component struct {
+	Name      string
+	DependsOn []string
+	// Start and Stop are called once each, and may be nil if there is
+	// nothing to do. They should return once the context is done
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+	// Timeout bounds the start and the stop of the component, which is the
+	// default timeout of the graph if it is 0
+	Timeout time.Duration
+}
+
+// State is the state of a component in the graph
+type State string
+
+const (
+	StatePending State = "pending"
+	StateRunning State = "running"
+	StateStopped State = "stopped"
+	StateFailed  State = "failed"
+	StateSkipped State = "skipped"
+)
+
+// Status is the state of a component, along with the error it failed with
+type Status struct {
+	Name  string
+	State State
+	Err   error
+}
+
+// StartError reports a partial start, where the components before the failed
+// one were started and then stopped, and the ones after it were skipped
+type StartError struct {
+	Component string
+	Started   []string
+	Skipped   []string
+	Err       error
+}
+
+func (e *StartError) Error() string {
+	return fmt.Sprintf("failed to start %s after starting [%s], skipping [%s]: %v",
+		e.Component, strings.Join(e.Started, ", "), strings.Join(e.Skipped, ", "), e.Err)
+}
+
+func (e *StartError) Unwrap() error {
+	return e.Err
+}
+
+// Graph is the dependency graph of the components of the daemon
+type Graph struct {
+	logger         *zap.Logger
+	defaultTimeout time.Duration
+
+	mu         sync.Mutex
+	components []*Component
+	byName     map[string]*Component
+	statuses   map[string]*Status
+	// started are the started components in the order of their start
+	started []*Component
+	// running is true once the graph is started until it is stopped
+	running bool
+	stopped bool
+}
+
+func New(logger *zap.Logger, defaultTimeout time.Duration) *Graph {
+	return &Graph{
+		logger:         logger,
+		defaultTimeout: defaultTimeout,
+		byName:         make(map[string]*Component),
+		statuses:       make(map[string]*Status),
+	}
+}
+
+// Add adds the component to the graph, which must be called before the graph
+// is started
+func (g *Graph) Add(c Component) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if c.Name == "" {
+		return fmt.Errorf("the component has no name")
+	}
+	if _, exists := g.byName[c.Name]; exists {
+		return fmt.Errorf("duplicate component %s", c.Name)
+	}
+	if g.running || g.stopped {
+		return fmt.Errorf("cannot add component %s to a started graph", c.Name)
+	}
+
+	g.components = append(g.components, &c)
+	g.byName[c.Name] = &c
+	g.statuses[c.Name] = &Status{Name: c.Name, State: StatePending}
+
+	return nil
+}
+
+// order sorts the components so that each one comes after its dependencies,
+// keeping the order they are added in otherwise so that it is deterministic
+func (g *Graph) order() ([]*Component, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make(map[string]int, len(g.components))
+	ordered := make([]*Component, 0, len(g.components))
+
+	var visit func(c *Component, path []string) error
+	visit = func(c *Component, path []string) error {
+		switch marks[c.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s -> %s", strings.Join(path, " -> "), c.Name)
+		}
+		marks[c.Name] = visiting
+		for _, dep := range c.DependsOn {
+			depC, ok := g.byName[dep]
+			if !ok {
+				return fmt.Errorf("component %s depends on unknown component %s", c.Name, dep)
+			}
+			if err := visit(depC, append(path, c.Name)); err != nil {
+				return err
+			}
+		}
+		marks[c.Name] = visited
+		ordered = append(ordered, c)
+
+		return nil
+	}
+
+	for _, c := range g.components {
+		if err := visit(c, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// Start starts the components in the order of their dependencies. If one
+// fails, the started ones are stopped in the reverse order and a StartError
+// reports the partial start. Starting a running graph is a no-op
+func (g *Graph) Start(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		return ErrStopped
+	}
+	if g.running {
+		return nil
+	}
+
+	ordered, err := g.order()
+	if err != nil {
+		return err
+	}
+
+	for i, c := range ordered {
+		g.logger.Debug("starting component", zap.String("component", c.Name))
+		if err := g.run(ctx, c, c.Start); err != nil {
+			g.statuses[c.Name].State = StateFailed
+			g.statuses[c.Name].Err = err
+
+			startErr := &StartError{Component: c.Name, Err: err}
+			for _, s := range g.started {
+				startErr.Started = append(startErr.Started, s.Name)
+			}
+			for _, s := range ordered[i+1:] {
+				startErr.Skipped = append(startErr.Skipped, s.Name)
+				g.statuses[s.Name].State = StateSkipped
+			}
+			g.logger.Error("failed to start component, stopping the started ones",
+				zap.String("component", c.Name),
+				zap.Strings("started", startErr.Started),
+				zap.Strings("skipped", startErr.Skipped),
+				zap.Error(err))
+
+			if stopErr := g.stopStarted(ctx); stopErr != nil {
+				g.logger.Error("failed to stop the started components", zap.Error(stopErr))
+			}
+			g.stopped = true
+
+			return startErr
+		}
+		g.started = append(g.started, c)
+		g.statuses[c.Name].State = StateRunning
+	}
+	g.running = true
+
+	return nil
+}
+
+// Stop stops the started components in the reverse order of their start,
+// carrying on when one fails, and returns the errors of all the failed ones.
+// Stopping a graph that is not running is a no-op
+func (g *Graph) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.running {
+		return nil
+	}
+	g.running = false
+	g.stopped = true
+
+	return g.stopStarted(ctx)
+}
+
+func (g *Graph) stopStarted(ctx context.Context) error {
+	var errs []error
+	for i := len(g.started) - 1; i >= 0; i-- {
+		c := g.started[i]
+		g.logger.Debug("stopping component", zap.String("component", c.Name))
+		if err := g.run(ctx, c, c.Stop); err != nil {
+			g.statuses[c.Name].State = StateFailed
+			g.statuses[c.Name].Err = err
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", c.Name, err))
+			continue
+		}
+		g.statuses[c.Name].State = StateStopped
+	}
+	g.started = nil
+
+	return errors.Join(errs...)
+}
+
+// run calls the start or the stop of the component within its timeout. The
+// call is abandoned, rather than awaited, once the timeout elapses
+func (g *Graph) run(ctx context.Context, c *Component, fn func(context.Context) error) error {
+	if fn == nil {
+		return nil
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = g.defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %v", ErrTimeout, timeout)
+		}
+		return ctx.Err()
+	}
+}
+
+// Statuses returns the states of the components in the order they are added
+func (g *Graph) Statuses() []Status {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	statuses := make([]Status, 0, len(g.components))
+	for _, c := range g.components {
+		statuses = append(statuses, *g.statuses[c.Name])
+	}
+
+	return statuses
+}
@@ -0,0 +1,131 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/lifecycle"
+)
+
+// recorder records the order in which the components are started and stopped
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) component(name string, dependsOn ...string) lifecycle.Component {
+	return lifecycle.Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start:     r.record("start " + name),
+		Stop:      r.record("stop " + name),
+	}
+}
+
+func (r *recorder) record(event string) func(context.Context) error {
+	return func(context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.events = append(r.events, event)
+		return nil
+	}
+}
+
+func TestGraphOrder(t *testing.T) {
+	g := lifecycle.New(zap.NewNop(), time.Second)
+	r := &recorder{}
+
+	// the components are added out of the order of their dependencies
+	require.NoError(t, g.Add(r.component("rpc", "instances")))
+	require.NoError(t, g.Add(r.component("instances", "eots", "chain")))
+	require.NoError(t, g.Add(r.component("chain", "store")))
+	require.NoError(t, g.Add(r.component("eots", "store")))
+	require.NoError(t, g.Add(r.component("store")))
+	require.Error(t, g.Add(r.component("store")))
+
+	require.NoError(t, g.Start(context.Background()))
+	// starting again is a no-op
+	require.NoError(t, g.Start(context.Background()))
+	require.NoError(t, g.Stop(context.Background()))
+	require.NoError(t, g.Stop(context.Background()))
+	require.ErrorIs(t, g.Start(context.Background()), lifecycle.ErrStopped)
+
+	require.Equal(t, []string{
+		"start store", "start eots", "start chain", "start instances", "start rpc",
+		"stop rpc", "stop instances", "stop chain", "stop eots", "stop store",
+	}, r.events)
+	for _, s := range g.Statuses() {
+		require.Equal(t, lifecycle.StateStopped, s.State, s.Name)
+	}
+}
+
+func TestGraphPartialStart(t *testing.T) {
+	g := lifecycle.New(zap.NewNop(), time.Second)
+	r := &recorder{}
+	failure := errors.New("failure")
+
+	require.NoError(t, g.Add(r.component("store")))
+	require.NoError(t, g.Add(r.component("eots", "store")))
+	chain := r.component("chain", "eots")
+	chain.Start = func(context.Context) error { return failure }
+	require.NoError(t, g.Add(chain))
+	require.NoError(t, g.Add(r.component("rpc", "chain")))
+
+	err := g.Start(context.Background())
+	var startErr *lifecycle.StartError
+	require.ErrorAs(t, err, &startErr)
+	require.ErrorIs(t, err, failure)
+	require.Equal(t, "chain", startErr.Component)
+	require.Equal(t, []string{"store", "eots"}, startErr.Started)
+	require.Equal(t, []string{"rpc"}, startErr.Skipped)
+
+	// the started components are stopped in the reverse order
+	require.Equal(t, []string{"start store", "start eots", "stop eots", "stop store"}, r.events)
+	require.Equal(t, []lifecycle.Status{
+		{Name: "store", State: lifecycle.StateStopped},
+		{Name: "eots", State: lifecycle.StateStopped},
+		{Name: "chain", State: lifecycle.StateFailed, Err: failure},
+		{Name: "rpc", State: lifecycle.StateSkipped},
+	}, g.Statuses())
+}
+
+func TestGraphTimeout(t *testing.T) {
+	g := lifecycle.New(zap.NewNop(), time.Second)
+	r := &recorder{}
+
+	require.NoError(t, g.Add(r.component("store")))
+	require.NoError(t, g.Add(lifecycle.Component{
+		Name:      "hanging",
+		DependsOn: []string{"store"},
+		Stop: func(ctx context.Context) error {
+			// ignores the context
+			select {}
+		},
+		Timeout: 10 * time.Millisecond,
+	}))
+
+	require.NoError(t, g.Start(context.Background()))
+	err := g.Stop(context.Background())
+	require.ErrorIs(t, err, lifecycle.ErrTimeout)
+	// the components it depends on are stopped regardless
+	require.Equal(t, []string{"start store", "stop store"}, r.events)
+}
+
+func TestGraphInvalid(t *testing.T) {
+	g := lifecycle.New(zap.NewNop(), time.Second)
+	r := &recorder{}
+	require.NoError(t, g.Add(r.component("a", "b")))
+	require.NoError(t, g.Add(r.component("b", "a")))
+	require.ErrorContains(t, g.Start(context.Background()), "dependency cycle")
+
+	g = lifecycle.New(zap.NewNop(), time.Second)
+	require.NoError(t, g.Add(r.component("a", "unknown")))
+	require.ErrorContains(t, g.Start(context.Background()), "unknown component")
+	require.Empty(t, r.events)
+}
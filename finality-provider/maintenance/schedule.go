@@ -0,0 +1,114 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// windowSeparator separates the cron expression from the duration of a window
+const windowSeparator = "|"
+
+// Window is a recurring period of time during which the operator may restart
+// the nodes, e.g., "0 3 * * SUN|1h" starts at 03:00 every Sunday and lasts an
+// hour. The cron expression has the standard five fields and is in UTC unless
+// prefixed with CRON_TZ=
+type Window struct {
+	schedule cron.Schedule
+	duration time.Duration
+	spec     string
+}
+
+// ParseWindow parses a window in the form of "<cron expression>|<duration>"
+func ParseWindow(spec string) (*Window, error) {
+	cronSpec, durationStr, found := strings.Cut(spec, windowSeparator)
+	if !found {
+		return nil, fmt.Errorf("the maintenance window %q should be in the form of '<cron expression>%s<duration>'",
+			spec, windowSeparator)
+	}
+
+	schedule, err := cron.ParseStandard(strings.TrimSpace(cronSpec))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression of the maintenance window %q: %w", spec, err)
+	}
+
+	duration, err := time.ParseDuration(strings.TrimSpace(durationStr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration of the maintenance window %q: %w", spec, err)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("the duration of the maintenance window %q should be positive", spec)
+	}
+
+	return &Window{
+		schedule: schedule,
+		duration: duration,
+		spec:     spec,
+	}, nil
+}
+
+// Active returns true if the given time falls into an occurrence of the window
+func (w *Window) Active(t time.Time) bool {
+	// an occurrence covers t iff it starts within (t - duration, t]
+	return !w.schedule.Next(t.Add(-w.duration)).After(t)
+}
+
+// StartsWithin returns true if an occurrence of the window starts within the
+// given lead time after t
+func (w *Window) StartsWithin(t time.Time, lead time.Duration) bool {
+	return !w.schedule.Next(t).After(t.Add(lead))
+}
+
+func (w *Window) String() string {
+	return w.spec
+}
+
+// Schedule is a set of maintenance windows
+type Schedule struct {
+	windows []*Window
+}
+
+// NewSchedule parses the given windows, the schedule of which is empty if
+// there are none
+func NewSchedule(specs []string) (*Schedule, error) {
+	windows := make([]*Window, 0, len(specs))
+	for _, spec := range specs {
+		w, err := ParseWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+
+	return &Schedule{windows: windows}, nil
+}
+
+// InWindow returns true if the given time falls into any of the windows
+func (s *Schedule) InWindow(t time.Time) bool {
+	for _, w := range s.windows {
+		if w.Active(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Upcoming returns true if any of the windows starts within the given lead
+// time after t, which is when the daemon prepares for the maintenance
+func (s *Schedule) Upcoming(t time.Time, lead time.Duration) bool {
+	for _, w := range s.windows {
+		if w.StartsWithin(t, lead) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsEmpty returns true if there are no windows
+func (s *Schedule) IsEmpty() bool {
+	return len(s.windows) == 0
+}
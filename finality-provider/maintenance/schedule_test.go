@@ -0,0 +1,50 @@
+package maintenance_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/maintenance"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	// 03:00 every Sunday for an hour
+	w, err := maintenance.ParseWindow("0 3 * * SUN|1h")
+	require.NoError(t, err)
+
+	// 2024-06-02 is a Sunday
+	start := time.Date(2024, 6, 2, 3, 0, 0, 0, time.UTC)
+
+	require.False(t, w.Active(start.Add(-time.Second)))
+	require.True(t, w.Active(start))
+	require.True(t, w.Active(start.Add(59*time.Minute)))
+	require.False(t, w.Active(start.Add(time.Hour)))
+	require.False(t, w.Active(start.Add(24*time.Hour)))
+
+	require.True(t, w.StartsWithin(start.Add(-30*time.Minute), 30*time.Minute))
+	require.False(t, w.StartsWithin(start.Add(-31*time.Minute), 30*time.Minute))
+
+	s, err := maintenance.NewSchedule([]string{"0 3 * * SUN|1h", "30 12 * * *|15m"})
+	require.NoError(t, err)
+	require.True(t, s.InWindow(start.Add(30*time.Minute)))
+	require.True(t, s.InWindow(time.Date(2024, 6, 4, 12, 40, 0, 0, time.UTC)))
+	require.False(t, s.InWindow(time.Date(2024, 6, 4, 12, 50, 0, 0, time.UTC)))
+	require.True(t, s.Upcoming(time.Date(2024, 6, 4, 12, 0, 0, 0, time.UTC), time.Hour))
+
+	empty, err := maintenance.NewSchedule(nil)
+	require.NoError(t, err)
+	require.True(t, empty.IsEmpty())
+	require.False(t, empty.InWindow(start))
+
+	for _, spec := range []string{
+		"0 3 * * SUN",
+		"0 3 * *|1h",
+		"0 3 * * SUN|1 hour",
+		"0 3 * * SUN|0s",
+	} {
+		_, err := maintenance.ParseWindow(spec)
+		require.Error(t, err, spec)
+	}
+}
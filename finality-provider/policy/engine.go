@@ -0,0 +1,153 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrSigningDenied is returned if a rule denies the signing request
+	ErrSigningDenied = errors.New("signing is denied by the policy")
+	// ErrSigningDeferred is returned if the signing request is not allowed for
+	// now but might be later, i.e., the rate limit is hit or the block does not
+	// have enough confirmations
+	ErrSigningDeferred = errors.New("signing is deferred by the policy")
+)
+
+const rateWindow = time.Minute
+
+// SignRequest describes a finality signature to be signed
+type SignRequest struct {
+	FpBtcPkHex string
+	ChainID    string
+	Height     uint64
+	// TipHeight is the height of the tip of the consumer chain, which is
+	// only needed if the policy requires confirmations
+	TipHeight uint64
+}
+
+// Engine evaluates signing requests against the policy loaded from a file.
+// The file is hot-reloaded: its modification time is checked at most once
+// per reload interval upon evaluation, and a policy that fails to load is
+// ignored in favor of the previous one
+type Engine struct {
+	path           string
+	reloadInterval time.Duration
+	logger         *zap.Logger
+
+	mu        sync.Mutex
+	policy    *Policy
+	modTime   time.Time
+	lastCheck time.Time
+	// signing times of each finality provider within the rate window
+	recentSigs map[string][]time.Time
+}
+
+func NewEngine(path string, reloadInterval time.Duration, logger *zap.Logger) (*Engine, error) {
+	e := &Engine{
+		path:           path,
+		reloadInterval: reloadInterval,
+		logger:         logger,
+		recentSigs:     make(map[string][]time.Time),
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access the signing policy file %s: %w", path, err)
+	}
+
+	p, err := LoadPolicy(path)
+	if err != nil {
+		return nil, err
+	}
+
+	e.policy = p
+	e.modTime = info.ModTime()
+	e.lastCheck = time.Now()
+
+	return e, nil
+}
+
+// MinConfirmations returns the number of confirmations required by the current policy
+func (e *Engine) MinConfirmations() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.maybeReload()
+
+	return e.policy.MinConfirmations
+}
+
+// Evaluate checks the signing request against the policy. If it is allowed,
+// the signature is counted towards the rate limit of the finality provider
+func (e *Engine) Evaluate(req *SignRequest) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.maybeReload()
+	p := e.policy
+
+	action := p.DefaultAction
+	for _, r := range p.Rules {
+		if r.matches(req) {
+			action = r.Action
+			break
+		}
+	}
+	if action == ActionDeny {
+		return fmt.Errorf("%w: height %d, chain ID %s", ErrSigningDenied, req.Height, req.ChainID)
+	}
+
+	if p.MinConfirmations > 0 && req.TipHeight < req.Height+p.MinConfirmations {
+		return fmt.Errorf("%w: block %d has less than %d confirmations at tip %d",
+			ErrSigningDeferred, req.Height, p.MinConfirmations, req.TipHeight)
+	}
+
+	now := time.Now()
+	sigs := e.recentSigs[req.FpBtcPkHex]
+	for len(sigs) > 0 && now.Sub(sigs[0]) >= rateWindow {
+		sigs = sigs[1:]
+	}
+	if p.MaxSignaturesPerMinute > 0 && uint32(len(sigs)) >= p.MaxSignaturesPerMinute {
+		e.recentSigs[req.FpBtcPkHex] = sigs
+		return fmt.Errorf("%w: reached %d signatures per minute", ErrSigningDeferred, p.MaxSignaturesPerMinute)
+	}
+	e.recentSigs[req.FpBtcPkHex] = append(sigs, now)
+
+	return nil
+}
+
+// maybeReload reloads the policy if the file has been modified since the last load
+// NOTE: the caller must hold the lock
+func (e *Engine) maybeReload() {
+	if time.Since(e.lastCheck) < e.reloadInterval {
+		return
+	}
+	e.lastCheck = time.Now()
+
+	info, err := os.Stat(e.path)
+	if err != nil {
+		e.logger.Error("failed to access the signing policy file, keeping the current policy",
+			zap.String("path", e.path), zap.Error(err))
+		return
+	}
+	if info.ModTime().Equal(e.modTime) {
+		return
+	}
+
+	p, err := LoadPolicy(e.path)
+	if err != nil {
+		e.logger.Error("failed to reload the signing policy, keeping the current policy",
+			zap.String("path", e.path), zap.Error(err))
+		return
+	}
+
+	e.policy = p
+	e.modTime = info.ModTime()
+	e.logger.Info("reloaded the signing policy", zap.String("path", e.path))
+}
@@ -0,0 +1,80 @@
+package policy_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/policy"
+)
+
+func writePolicy(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy(t, path, `{
+		"rules": [
+			{"action": "deny", "max_height": 100},
+			{"action": "allow", "chain_ids": ["chain-test"]}
+		],
+		"default_action": "deny",
+		"max_signatures_per_minute": 2,
+		"min_confirmations": 1
+	}`)
+
+	e, err := policy.NewEngine(path, 0, zap.NewNop())
+	require.NoError(t, err)
+
+	// denied by the height range rule
+	err = e.Evaluate(&policy.SignRequest{FpBtcPkHex: "fp", ChainID: "chain-test", Height: 50, TipHeight: 60})
+	require.True(t, errors.Is(err, policy.ErrSigningDenied))
+
+	// denied by the default action
+	err = e.Evaluate(&policy.SignRequest{FpBtcPkHex: "fp", ChainID: "other", Height: 200, TipHeight: 210})
+	require.True(t, errors.Is(err, policy.ErrSigningDenied))
+
+	// deferred as the block has no confirmation
+	err = e.Evaluate(&policy.SignRequest{FpBtcPkHex: "fp", ChainID: "chain-test", Height: 200, TipHeight: 200})
+	require.True(t, errors.Is(err, policy.ErrSigningDeferred))
+
+	// allowed until the rate limit is reached
+	for h := uint64(200); h < 202; h++ {
+		err = e.Evaluate(&policy.SignRequest{FpBtcPkHex: "fp", ChainID: "chain-test", Height: h, TipHeight: 210})
+		require.NoError(t, err)
+	}
+	err = e.Evaluate(&policy.SignRequest{FpBtcPkHex: "fp", ChainID: "chain-test", Height: 202, TipHeight: 210})
+	require.True(t, errors.Is(err, policy.ErrSigningDeferred))
+
+	// the rate limit is per finality provider
+	err = e.Evaluate(&policy.SignRequest{FpBtcPkHex: "other-fp", ChainID: "chain-test", Height: 202, TipHeight: 210})
+	require.NoError(t, err)
+}
+
+func TestEngine_HotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicy(t, path, `{"default_action": "deny"}`)
+
+	e, err := policy.NewEngine(path, 0, zap.NewNop())
+	require.NoError(t, err)
+
+	req := &policy.SignRequest{FpBtcPkHex: "fp", ChainID: "chain-test", Height: 1}
+	require.True(t, errors.Is(e.Evaluate(req), policy.ErrSigningDenied))
+
+	writePolicy(t, path, `{"default_action": "allow"}`)
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	require.NoError(t, e.Evaluate(req))
+
+	// an invalid policy is ignored and the current one is kept
+	writePolicy(t, path, `{"default_action": "maybe"}`)
+	later = later.Add(time.Second)
+	require.NoError(t, os.Chtimes(path, later, later))
+	require.NoError(t, e.Evaluate(req))
+}
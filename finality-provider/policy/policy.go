@@ -0,0 +1,118 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	ActionAllow = "allow"
+	ActionDeny  = "deny"
+)
+
+// Policy defines the rules evaluated before each finality signature is signed.
+// It is loaded from a JSON file, e.g.,
+//
+//	{
+//	  "rules": [
+//	    {"action": "deny", "max_height": 1000},
+//	    {"action": "allow", "chain_ids": ["chain-test"]}
+//	  ],
+//	  "default_action": "deny",
+//	  "max_signatures_per_minute": 60,
+//	  "min_confirmations": 1
+//	}
+type Policy struct {
+	// Rules are evaluated in order and the first matching rule decides
+	Rules []*Rule `json:"rules"`
+	// DefaultAction applies if no rule matches, which is allow if empty
+	DefaultAction string `json:"default_action"`
+	// MaxSignaturesPerMinute caps the signatures of each finality provider
+	// within any minute, which is unlimited if 0
+	MaxSignaturesPerMinute uint32 `json:"max_signatures_per_minute"`
+	// MinConfirmations is the number of blocks required on top of a block
+	// before it can be signed
+	MinConfirmations uint64 `json:"min_confirmations"`
+}
+
+// Rule matches a signing request if all of its non-empty conditions are met
+type Rule struct {
+	Action string `json:"action"`
+	// MinHeight and MaxHeight bound the height of the block, where
+	// MaxHeight of 0 means unbounded
+	MinHeight uint64 `json:"min_height"`
+	MaxHeight uint64 `json:"max_height"`
+	// ChainIDs matches any chain ID if empty
+	ChainIDs []string `json:"chain_ids"`
+	// FpBtcPks are the hex BTC public keys of the finality providers,
+	// which matches any finality provider if empty
+	FpBtcPks []string `json:"fp_btc_pks"`
+}
+
+// LoadPolicy reads and validates the policy from the given JSON file
+func LoadPolicy(path string) (*Policy, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the signing policy file %s: %w", path, err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(bz, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse the signing policy file %s: %w", path, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid signing policy: %w", err)
+	}
+
+	return &p, nil
+}
+
+func (p *Policy) Validate() error {
+	switch p.DefaultAction {
+	case "":
+		p.DefaultAction = ActionAllow
+	case ActionAllow, ActionDeny:
+	default:
+		return fmt.Errorf("invalid default action: %s", p.DefaultAction)
+	}
+
+	for i, r := range p.Rules {
+		if r.Action != ActionAllow && r.Action != ActionDeny {
+			return fmt.Errorf("invalid action of rule %d: %s", i, r.Action)
+		}
+		if r.MaxHeight != 0 && r.MaxHeight < r.MinHeight {
+			return fmt.Errorf("invalid height range of rule %d: [%d, %d]", i, r.MinHeight, r.MaxHeight)
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) matches(req *SignRequest) bool {
+	if req.Height < r.MinHeight {
+		return false
+	}
+	if r.MaxHeight != 0 && req.Height > r.MaxHeight {
+		return false
+	}
+	if len(r.ChainIDs) != 0 && !contains(r.ChainIDs, req.ChainID) {
+		return false
+	}
+	if len(r.FpBtcPks) != 0 && !contains(r.FpBtcPks, req.FpBtcPkHex) {
+		return false
+	}
+
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+
+	return false
+}
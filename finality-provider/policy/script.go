@@ -0,0 +1,124 @@
+package policy
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.uber.org/zap"
+)
+
+const (
+	// scriptDecideFunc is the function the script must define, which is
+	// called as decide(block, fp) and returns one of the decisions below
+	scriptDecideFunc = "decide"
+
+	DecisionSign  = "sign"
+	DecisionSkip  = "skip"
+	DecisionDelay = "delay"
+
+	// scriptMaxSteps bounds each evaluation so that a buggy script cannot
+	// stall the submission loop
+	scriptMaxSteps = 1_000_000
+)
+
+// ScriptRequest describes the block to be signed and the state of the
+// finality provider passed to the script
+type ScriptRequest struct {
+	FpBtcPkHex          string
+	ChainID             string
+	Status              string
+	LastVotedHeight     uint64
+	LastProcessedHeight uint64
+
+	Height    uint64
+	BlockHash []byte
+}
+
+// Script evaluates signing requests with a Starlark script, which receives
+//
+//	block: struct(height, hash)
+//	fp:    struct(btc_pk, chain_id, status, last_voted_height, last_processed_height)
+//
+// and returns "sign", "skip" or "delay", which are mapped to nil,
+// ErrSigningDenied and ErrSigningDeferred, respectively
+type Script struct {
+	path   string
+	decide starlark.Callable
+	logger *zap.Logger
+}
+
+func NewScript(path string, logger *zap.Logger) (*Script, error) {
+	thread := &starlark.Thread{
+		Name:  path,
+		Print: scriptPrinter(logger),
+	}
+	globals, err := starlark.ExecFile(thread, path, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the script %s: %w", path, err)
+	}
+	// frozen values can be shared among the threads of the instances
+	globals.Freeze()
+
+	decide, ok := globals[scriptDecideFunc].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("the script %s does not define the function %s(block, fp)", path, scriptDecideFunc)
+	}
+
+	return &Script{
+		path:   path,
+		decide: decide,
+		logger: logger,
+	}, nil
+}
+
+// Evaluate runs the script over the request and returns nil if the block
+// should be signed. An error in the script is returned as is, so that it is
+// treated as a failed submission instead of a decision
+func (s *Script) Evaluate(req *ScriptRequest) error {
+	thread := &starlark.Thread{
+		Name:  s.path,
+		Print: scriptPrinter(s.logger),
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+
+	block := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"height": starlark.MakeUint64(req.Height),
+		"hash":   starlark.String(hex.EncodeToString(req.BlockHash)),
+	})
+	fp := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"btc_pk":                starlark.String(req.FpBtcPkHex),
+		"chain_id":              starlark.String(req.ChainID),
+		"status":                starlark.String(req.Status),
+		"last_voted_height":     starlark.MakeUint64(req.LastVotedHeight),
+		"last_processed_height": starlark.MakeUint64(req.LastProcessedHeight),
+	})
+
+	res, err := starlark.Call(thread, s.decide, starlark.Tuple{block, fp}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to run the script %s: %w", s.path, err)
+	}
+
+	decision, ok := starlark.AsString(res)
+	if !ok {
+		return fmt.Errorf("the script %s returned %s instead of a decision", s.path, res.String())
+	}
+
+	switch decision {
+	case DecisionSign:
+		return nil
+	case DecisionSkip:
+		return fmt.Errorf("%w: skipped by the script at height %d", ErrSigningDenied, req.Height)
+	case DecisionDelay:
+		return fmt.Errorf("%w: delayed by the script at height %d", ErrSigningDeferred, req.Height)
+	default:
+		return fmt.Errorf("the script %s returned an unknown decision: %s", s.path, decision)
+	}
+}
+
+func scriptPrinter(logger *zap.Logger) func(*starlark.Thread, string) {
+	return func(thread *starlark.Thread, msg string) {
+		logger.Info("submission script output", zap.String("script", thread.Name), zap.String("msg", msg))
+	}
+}
@@ -0,0 +1,62 @@
+package policy_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/policy"
+)
+
+func TestScript_Evaluate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.star")
+	writePolicy(t, path, `
+def decide(block, fp):
+    if fp.status != "ACTIVE":
+        return "skip"
+    if block.height > fp.last_processed_height + 10:
+        return "delay"
+    return "sign"
+`)
+
+	s, err := policy.NewScript(path, zap.NewNop())
+	require.NoError(t, err)
+
+	req := &policy.ScriptRequest{
+		FpBtcPkHex:          "fp",
+		Status:              "ACTIVE",
+		LastProcessedHeight: 100,
+		Height:              101,
+		BlockHash:           []byte{0x01},
+	}
+	require.NoError(t, s.Evaluate(req))
+
+	req.Height = 200
+	require.True(t, errors.Is(s.Evaluate(req), policy.ErrSigningDeferred))
+
+	req.Status = "INACTIVE"
+	require.True(t, errors.Is(s.Evaluate(req), policy.ErrSigningDenied))
+
+	// a script that never returns is aborted
+	loopPath := filepath.Join(t.TempDir(), "loop.star")
+	writePolicy(t, loopPath, `
+def decide(block, fp):
+    for i in range(1000000000):
+        pass
+    return "sign"
+`)
+	s, err = policy.NewScript(loopPath, zap.NewNop())
+	require.NoError(t, err)
+	err = s.Evaluate(req)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, policy.ErrSigningDenied))
+
+	// a script without the decide function is rejected
+	badPath := filepath.Join(t.TempDir(), "bad.star")
+	writePolicy(t, badPath, `x = 1`)
+	_, err = policy.NewScript(badPath, zap.NewNop())
+	require.Error(t, err)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	"github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	fptypes "github.com/babylonchain/finality-provider/types"
 )
 
 func (sfp *FinalityProvider) GetChainPK() *secp256k1.PubKey {
@@ -40,8 +42,9 @@ func NewFinalityProviderInfo(sfp *FinalityProvider) (*FinalityProviderInfo, erro
 		return nil, err
 	}
 	return &FinalityProviderInfo{
-		ChainPkHex: sfp.GetChainPkHexString(),
-		BtcPkHex:   sfp.MustGetBIP340BTCPK().MarshalHex(),
+		ChainPkHex:         sfp.GetChainPkHexString(),
+		BtcPkHex:           sfp.MustGetBIP340BTCPK().MarshalHex(),
+		BtcPkCompressedHex: fptypes.CompressedBTCPkHex(sfp.MustGetBIP340BTCPK().MarshalHex()),
 		Description: &Description{
 			Moniker:         des.Moniker,
 			Identity:        des.Identity,
@@ -49,7 +52,10 @@ func NewFinalityProviderInfo(sfp *FinalityProvider) (*FinalityProviderInfo, erro
 			SecurityContact: des.SecurityContact,
 			Details:         des.Details,
 		},
-		LastVotedHeight: sfp.LastVotedHeight,
-		Status:          sfp.Status.String(),
+		LastVotedHeight:    sfp.LastVotedHeight,
+		Status:             sfp.Status.String(),
+		RegistrationTxHash: sfp.RegistrationTxHash,
+		RewardAddress:      sfp.RewardAddress,
+		FinalizedHeight:    sfp.FinalizedHeight,
 	}, nil
 }
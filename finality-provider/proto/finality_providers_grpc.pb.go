@@ -34,6 +34,76 @@ type FinalityProvidersClient interface {
 	QueryFinalityProviderList(ctx context.Context, in *QueryFinalityProviderListRequest, opts ...grpc.CallOption) (*QueryFinalityProviderListResponse, error)
 	// SignMessageFromChainKey signs a message from the chain keyring.
 	SignMessageFromChainKey(ctx context.Context, in *SignMessageFromChainKeyRequest, opts ...grpc.CallOption) (*SignMessageFromChainKeyResponse, error)
+	// SignMessage signs an arbitrary message with the chain key of a
+	// finality provider as specified by ADR-36
+	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	// VerifyMessage verifies an ADR-36 signature of an arbitrary message
+	VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error)
+	// ProveKeyOwnership signs a challenge with the BTC key of a finality
+	// provider to prove the ownership of the key to a third party
+	ProveKeyOwnership(ctx context.Context, in *ProveKeyOwnershipRequest, opts ...grpc.CallOption) (*ProveKeyOwnershipResponse, error)
+	// ValidateState checks the stored finality providers against the keys
+	// in the EOTS manager and reports the orphaned records
+	ValidateState(ctx context.Context, in *ValidateStateRequest, opts ...grpc.CallOption) (*ValidateStateResponse, error)
+	// SyncState streams the state of the finality providers to a standby
+	// daemon, i.e., the stored finality providers and the signed blocks
+	SyncState(ctx context.Context, in *SyncStateRequest, opts ...grpc.CallOption) (FinalityProviders_SyncStateClient, error)
+	// QueryFinalizedBlocks queries the latest finalized blocks of the
+	// consumer chain annotated with the votes of the finality providers
+	// in the daemon
+	QueryFinalizedBlocks(ctx context.Context, in *QueryFinalizedBlocksRequest, opts ...grpc.CallOption) (*QueryFinalizedBlocksResponse, error)
+	// QueryNetworkParticipation reports the participation of all the
+	// finality providers on the consumer chain, which is only available
+	// in observer mode
+	QueryNetworkParticipation(ctx context.Context, in *QueryNetworkParticipationRequest, opts ...grpc.CallOption) (*QueryNetworkParticipationResponse, error)
+	// QueryVotingHistory queries the finality signatures submitted by the
+	// daemon for a finality provider, along with its votes and public
+	// randomness commits accepted by the consumer chain, which are served
+	// from the local index and only available if the vote indexer is enabled
+	QueryVotingHistory(ctx context.Context, in *QueryVotingHistoryRequest, opts ...grpc.CallOption) (*QueryVotingHistoryResponse, error)
+	// ResumeFinalityProvider restarts a finality provider in safe mode,
+	// which it enters after repeated non-retryable submission failures
+	ResumeFinalityProvider(ctx context.Context, in *ResumeFinalityProviderRequest, opts ...grpc.CallOption) (*ResumeFinalityProviderResponse, error)
+	// PauseFinalityProvider stops a running finality provider by switching
+	// it to safe mode, from which it is resumed by ResumeFinalityProvider
+	PauseFinalityProvider(ctx context.Context, in *PauseFinalityProviderRequest, opts ...grpc.CallOption) (*PauseFinalityProviderResponse, error)
+	// InjectFault injects or clears a fault for chaos testing, which is only
+	// available if the daemon is built with the faultinject build tag
+	InjectFault(ctx context.Context, in *InjectFaultRequest, opts ...grpc.CallOption) (*InjectFaultResponse, error)
+	// ListKeys lists the EOTS keys in the EOTS manager with the finality
+	// providers using them and their usage, e.g., to find the orphaned or
+	// stale keys
+	ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
+	// SignFinality returns the finality signature of a finality provider over
+	// a block without broadcasting it, so that an external coordinator can
+	// broadcast it, which is only available if enabled in the config
+	SignFinality(ctx context.Context, in *SignFinalityRequest, opts ...grpc.CallOption) (*SignFinalityResponse, error)
+	// ExportState streams an encrypted archive of the store, the config,
+	// and optionally the keys of the daemon to be restored on another host
+	ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (FinalityProviders_ExportStateClient, error)
+	// ImportState verifies an archive exported by ExportState and stages it
+	// to be restored on the next start of the daemon
+	ImportState(ctx context.Context, opts ...grpc.CallOption) (FinalityProviders_ImportStateClient, error)
+	// SetRewardAddress sends a transaction to the consumer chain to set the
+	// address receiving the rewards and commission of a finality provider,
+	// which can differ from the account paying the fees
+	SetRewardAddress(ctx context.Context, in *SetRewardAddressRequest, opts ...grpc.CallOption) (*SetRewardAddressResponse, error)
+	// QueryStatus returns the tip of the consumer chain and the status of all
+	// the finality providers of the daemon, including the randomness runway
+	// and the balance, for live monitoring
+	QueryStatus(ctx context.Context, in *QueryStatusRequest, opts ...grpc.CallOption) (*QueryStatusResponse, error)
+	// CommitPubRand commits the public randomness of a finality provider
+	// right away instead of waiting for the commitment loop, optionally up to
+	// a target height, e.g., before a planned maintenance
+	CommitPubRand(ctx context.Context, in *CommitPubRandRequest, opts ...grpc.CallOption) (*CommitPubRandResponse, error)
+	// TailLogs streams the live logs of the daemon at or above a level,
+	// optionally of a subsystem only, until the client cancels the stream
+	TailLogs(ctx context.Context, in *TailLogsRequest, opts ...grpc.CallOption) (FinalityProviders_TailLogsClient, error)
+	// ExportFinalityProofs exports the finality proofs of the blocks in a
+	// range of heights, i.e., the finality signatures and the voting powers
+	// fetched from the consumer chain, for light clients to verify the
+	// finality of the blocks
+	ExportFinalityProofs(ctx context.Context, in *ExportFinalityProofsRequest, opts ...grpc.CallOption) (*ExportFinalityProofsResponse, error)
 }
 
 type finalityProvidersClient struct {
@@ -107,6 +177,280 @@ func (c *finalityProvidersClient) SignMessageFromChainKey(ctx context.Context, i
 	return out, nil
 }
 
+func (c *finalityProvidersClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
+	out := new(SignMessageResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/SignMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) VerifyMessage(ctx context.Context, in *VerifyMessageRequest, opts ...grpc.CallOption) (*VerifyMessageResponse, error) {
+	out := new(VerifyMessageResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/VerifyMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) ProveKeyOwnership(ctx context.Context, in *ProveKeyOwnershipRequest, opts ...grpc.CallOption) (*ProveKeyOwnershipResponse, error) {
+	out := new(ProveKeyOwnershipResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/ProveKeyOwnership", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) ValidateState(ctx context.Context, in *ValidateStateRequest, opts ...grpc.CallOption) (*ValidateStateResponse, error) {
+	out := new(ValidateStateResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/ValidateState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) SyncState(ctx context.Context, in *SyncStateRequest, opts ...grpc.CallOption) (FinalityProviders_SyncStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FinalityProviders_ServiceDesc.Streams[0], "/proto.FinalityProviders/SyncState", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &finalityProvidersSyncStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FinalityProviders_SyncStateClient interface {
+	Recv() (*SyncStateResponse, error)
+	grpc.ClientStream
+}
+
+type finalityProvidersSyncStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *finalityProvidersSyncStateClient) Recv() (*SyncStateResponse, error) {
+	m := new(SyncStateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *finalityProvidersClient) QueryFinalizedBlocks(ctx context.Context, in *QueryFinalizedBlocksRequest, opts ...grpc.CallOption) (*QueryFinalizedBlocksResponse, error) {
+	out := new(QueryFinalizedBlocksResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/QueryFinalizedBlocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) QueryNetworkParticipation(ctx context.Context, in *QueryNetworkParticipationRequest, opts ...grpc.CallOption) (*QueryNetworkParticipationResponse, error) {
+	out := new(QueryNetworkParticipationResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/QueryNetworkParticipation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) QueryVotingHistory(ctx context.Context, in *QueryVotingHistoryRequest, opts ...grpc.CallOption) (*QueryVotingHistoryResponse, error) {
+	out := new(QueryVotingHistoryResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/QueryVotingHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) ResumeFinalityProvider(ctx context.Context, in *ResumeFinalityProviderRequest, opts ...grpc.CallOption) (*ResumeFinalityProviderResponse, error) {
+	out := new(ResumeFinalityProviderResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/ResumeFinalityProvider", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) PauseFinalityProvider(ctx context.Context, in *PauseFinalityProviderRequest, opts ...grpc.CallOption) (*PauseFinalityProviderResponse, error) {
+	out := new(PauseFinalityProviderResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/PauseFinalityProvider", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) InjectFault(ctx context.Context, in *InjectFaultRequest, opts ...grpc.CallOption) (*InjectFaultResponse, error) {
+	out := new(InjectFaultResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/InjectFault", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/ListKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) SignFinality(ctx context.Context, in *SignFinalityRequest, opts ...grpc.CallOption) (*SignFinalityResponse, error) {
+	out := new(SignFinalityResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/SignFinality", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) ExportState(ctx context.Context, in *ExportStateRequest, opts ...grpc.CallOption) (FinalityProviders_ExportStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FinalityProviders_ServiceDesc.Streams[1], "/proto.FinalityProviders/ExportState", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &finalityProvidersExportStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FinalityProviders_ExportStateClient interface {
+	Recv() (*ExportStateResponse, error)
+	grpc.ClientStream
+}
+
+type finalityProvidersExportStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *finalityProvidersExportStateClient) Recv() (*ExportStateResponse, error) {
+	m := new(ExportStateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *finalityProvidersClient) ImportState(ctx context.Context, opts ...grpc.CallOption) (FinalityProviders_ImportStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FinalityProviders_ServiceDesc.Streams[2], "/proto.FinalityProviders/ImportState", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &finalityProvidersImportStateClient{stream}
+	return x, nil
+}
+
+type FinalityProviders_ImportStateClient interface {
+	Send(*ImportStateRequest) error
+	CloseAndRecv() (*ImportStateResponse, error)
+	grpc.ClientStream
+}
+
+type finalityProvidersImportStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *finalityProvidersImportStateClient) Send(m *ImportStateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *finalityProvidersImportStateClient) CloseAndRecv() (*ImportStateResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportStateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *finalityProvidersClient) SetRewardAddress(ctx context.Context, in *SetRewardAddressRequest, opts ...grpc.CallOption) (*SetRewardAddressResponse, error) {
+	out := new(SetRewardAddressResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/SetRewardAddress", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) QueryStatus(ctx context.Context, in *QueryStatusRequest, opts ...grpc.CallOption) (*QueryStatusResponse, error) {
+	out := new(QueryStatusResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/QueryStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) CommitPubRand(ctx context.Context, in *CommitPubRandRequest, opts ...grpc.CallOption) (*CommitPubRandResponse, error) {
+	out := new(CommitPubRandResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/CommitPubRand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *finalityProvidersClient) TailLogs(ctx context.Context, in *TailLogsRequest, opts ...grpc.CallOption) (FinalityProviders_TailLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FinalityProviders_ServiceDesc.Streams[3], "/proto.FinalityProviders/TailLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &finalityProvidersTailLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FinalityProviders_TailLogsClient interface {
+	Recv() (*TailLogsResponse, error)
+	grpc.ClientStream
+}
+
+type finalityProvidersTailLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *finalityProvidersTailLogsClient) Recv() (*TailLogsResponse, error) {
+	m := new(TailLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *finalityProvidersClient) ExportFinalityProofs(ctx context.Context, in *ExportFinalityProofsRequest, opts ...grpc.CallOption) (*ExportFinalityProofsResponse, error) {
+	out := new(ExportFinalityProofsResponse)
+	err := c.cc.Invoke(ctx, "/proto.FinalityProviders/ExportFinalityProofs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FinalityProvidersServer is the server API for FinalityProviders service.
 // All implementations must embed UnimplementedFinalityProvidersServer
 // for forward compatibility
@@ -127,6 +471,76 @@ type FinalityProvidersServer interface {
 	QueryFinalityProviderList(context.Context, *QueryFinalityProviderListRequest) (*QueryFinalityProviderListResponse, error)
 	// SignMessageFromChainKey signs a message from the chain keyring.
 	SignMessageFromChainKey(context.Context, *SignMessageFromChainKeyRequest) (*SignMessageFromChainKeyResponse, error)
+	// SignMessage signs an arbitrary message with the chain key of a
+	// finality provider as specified by ADR-36
+	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
+	// VerifyMessage verifies an ADR-36 signature of an arbitrary message
+	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
+	// ProveKeyOwnership signs a challenge with the BTC key of a finality
+	// provider to prove the ownership of the key to a third party
+	ProveKeyOwnership(context.Context, *ProveKeyOwnershipRequest) (*ProveKeyOwnershipResponse, error)
+	// ValidateState checks the stored finality providers against the keys
+	// in the EOTS manager and reports the orphaned records
+	ValidateState(context.Context, *ValidateStateRequest) (*ValidateStateResponse, error)
+	// SyncState streams the state of the finality providers to a standby
+	// daemon, i.e., the stored finality providers and the signed blocks
+	SyncState(*SyncStateRequest, FinalityProviders_SyncStateServer) error
+	// QueryFinalizedBlocks queries the latest finalized blocks of the
+	// consumer chain annotated with the votes of the finality providers
+	// in the daemon
+	QueryFinalizedBlocks(context.Context, *QueryFinalizedBlocksRequest) (*QueryFinalizedBlocksResponse, error)
+	// QueryNetworkParticipation reports the participation of all the
+	// finality providers on the consumer chain, which is only available
+	// in observer mode
+	QueryNetworkParticipation(context.Context, *QueryNetworkParticipationRequest) (*QueryNetworkParticipationResponse, error)
+	// QueryVotingHistory queries the finality signatures submitted by the
+	// daemon for a finality provider, along with its votes and public
+	// randomness commits accepted by the consumer chain, which are served
+	// from the local index and only available if the vote indexer is enabled
+	QueryVotingHistory(context.Context, *QueryVotingHistoryRequest) (*QueryVotingHistoryResponse, error)
+	// ResumeFinalityProvider restarts a finality provider in safe mode,
+	// which it enters after repeated non-retryable submission failures
+	ResumeFinalityProvider(context.Context, *ResumeFinalityProviderRequest) (*ResumeFinalityProviderResponse, error)
+	// PauseFinalityProvider stops a running finality provider by switching
+	// it to safe mode, from which it is resumed by ResumeFinalityProvider
+	PauseFinalityProvider(context.Context, *PauseFinalityProviderRequest) (*PauseFinalityProviderResponse, error)
+	// InjectFault injects or clears a fault for chaos testing, which is only
+	// available if the daemon is built with the faultinject build tag
+	InjectFault(context.Context, *InjectFaultRequest) (*InjectFaultResponse, error)
+	// ListKeys lists the EOTS keys in the EOTS manager with the finality
+	// providers using them and their usage, e.g., to find the orphaned or
+	// stale keys
+	ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error)
+	// SignFinality returns the finality signature of a finality provider over
+	// a block without broadcasting it, so that an external coordinator can
+	// broadcast it, which is only available if enabled in the config
+	SignFinality(context.Context, *SignFinalityRequest) (*SignFinalityResponse, error)
+	// ExportState streams an encrypted archive of the store, the config,
+	// and optionally the keys of the daemon to be restored on another host
+	ExportState(*ExportStateRequest, FinalityProviders_ExportStateServer) error
+	// ImportState verifies an archive exported by ExportState and stages it
+	// to be restored on the next start of the daemon
+	ImportState(FinalityProviders_ImportStateServer) error
+	// SetRewardAddress sends a transaction to the consumer chain to set the
+	// address receiving the rewards and commission of a finality provider,
+	// which can differ from the account paying the fees
+	SetRewardAddress(context.Context, *SetRewardAddressRequest) (*SetRewardAddressResponse, error)
+	// QueryStatus returns the tip of the consumer chain and the status of all
+	// the finality providers of the daemon, including the randomness runway
+	// and the balance, for live monitoring
+	QueryStatus(context.Context, *QueryStatusRequest) (*QueryStatusResponse, error)
+	// CommitPubRand commits the public randomness of a finality provider
+	// right away instead of waiting for the commitment loop, optionally up to
+	// a target height, e.g., before a planned maintenance
+	CommitPubRand(context.Context, *CommitPubRandRequest) (*CommitPubRandResponse, error)
+	// TailLogs streams the live logs of the daemon at or above a level,
+	// optionally of a subsystem only, until the client cancels the stream
+	TailLogs(*TailLogsRequest, FinalityProviders_TailLogsServer) error
+	// ExportFinalityProofs exports the finality proofs of the blocks in a
+	// range of heights, i.e., the finality signatures and the voting powers
+	// fetched from the consumer chain, for light clients to verify the
+	// finality of the blocks
+	ExportFinalityProofs(context.Context, *ExportFinalityProofsRequest) (*ExportFinalityProofsResponse, error)
 	mustEmbedUnimplementedFinalityProvidersServer()
 }
 
@@ -155,6 +569,66 @@ func (UnimplementedFinalityProvidersServer) QueryFinalityProviderList(context.Co
 func (UnimplementedFinalityProvidersServer) SignMessageFromChainKey(context.Context, *SignMessageFromChainKeyRequest) (*SignMessageFromChainKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignMessageFromChainKey not implemented")
 }
+func (UnimplementedFinalityProvidersServer) SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignMessage not implemented")
+}
+func (UnimplementedFinalityProvidersServer) VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMessage not implemented")
+}
+func (UnimplementedFinalityProvidersServer) ProveKeyOwnership(context.Context, *ProveKeyOwnershipRequest) (*ProveKeyOwnershipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProveKeyOwnership not implemented")
+}
+func (UnimplementedFinalityProvidersServer) ValidateState(context.Context, *ValidateStateRequest) (*ValidateStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateState not implemented")
+}
+func (UnimplementedFinalityProvidersServer) SyncState(*SyncStateRequest, FinalityProviders_SyncStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method SyncState not implemented")
+}
+func (UnimplementedFinalityProvidersServer) QueryFinalizedBlocks(context.Context, *QueryFinalizedBlocksRequest) (*QueryFinalizedBlocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryFinalizedBlocks not implemented")
+}
+func (UnimplementedFinalityProvidersServer) QueryNetworkParticipation(context.Context, *QueryNetworkParticipationRequest) (*QueryNetworkParticipationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryNetworkParticipation not implemented")
+}
+func (UnimplementedFinalityProvidersServer) QueryVotingHistory(context.Context, *QueryVotingHistoryRequest) (*QueryVotingHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryVotingHistory not implemented")
+}
+func (UnimplementedFinalityProvidersServer) ResumeFinalityProvider(context.Context, *ResumeFinalityProviderRequest) (*ResumeFinalityProviderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeFinalityProvider not implemented")
+}
+func (UnimplementedFinalityProvidersServer) PauseFinalityProvider(context.Context, *PauseFinalityProviderRequest) (*PauseFinalityProviderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseFinalityProvider not implemented")
+}
+func (UnimplementedFinalityProvidersServer) InjectFault(context.Context, *InjectFaultRequest) (*InjectFaultResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectFault not implemented")
+}
+func (UnimplementedFinalityProvidersServer) ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedFinalityProvidersServer) SignFinality(context.Context, *SignFinalityRequest) (*SignFinalityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignFinality not implemented")
+}
+func (UnimplementedFinalityProvidersServer) ExportState(*ExportStateRequest, FinalityProviders_ExportStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportState not implemented")
+}
+func (UnimplementedFinalityProvidersServer) ImportState(FinalityProviders_ImportStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportState not implemented")
+}
+func (UnimplementedFinalityProvidersServer) SetRewardAddress(context.Context, *SetRewardAddressRequest) (*SetRewardAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRewardAddress not implemented")
+}
+func (UnimplementedFinalityProvidersServer) QueryStatus(context.Context, *QueryStatusRequest) (*QueryStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryStatus not implemented")
+}
+func (UnimplementedFinalityProvidersServer) CommitPubRand(context.Context, *CommitPubRandRequest) (*CommitPubRandResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitPubRand not implemented")
+}
+func (UnimplementedFinalityProvidersServer) TailLogs(*TailLogsRequest, FinalityProviders_TailLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method TailLogs not implemented")
+}
+func (UnimplementedFinalityProvidersServer) ExportFinalityProofs(context.Context, *ExportFinalityProofsRequest) (*ExportFinalityProofsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportFinalityProofs not implemented")
+}
 func (UnimplementedFinalityProvidersServer) mustEmbedUnimplementedFinalityProvidersServer() {}
 
 // UnsafeFinalityProvidersServer may be embedded to opt out of forward compatibility for this service.
@@ -294,6 +768,383 @@ func _FinalityProviders_SignMessageFromChainKey_Handler(srv interface{}, ctx con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FinalityProviders_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).SignMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/SignMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).SignMessage(ctx, req.(*SignMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_ProveKeyOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProveKeyOwnershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).ProveKeyOwnership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/ProveKeyOwnership",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).ProveKeyOwnership(ctx, req.(*ProveKeyOwnershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_ValidateState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).ValidateState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/ValidateState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).ValidateState(ctx, req.(*ValidateStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_SyncState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SyncStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FinalityProvidersServer).SyncState(m, &finalityProvidersSyncStateServer{stream})
+}
+
+type FinalityProviders_SyncStateServer interface {
+	Send(*SyncStateResponse) error
+	grpc.ServerStream
+}
+
+type finalityProvidersSyncStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *finalityProvidersSyncStateServer) Send(m *SyncStateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FinalityProviders_QueryFinalizedBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryFinalizedBlocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).QueryFinalizedBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/QueryFinalizedBlocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).QueryFinalizedBlocks(ctx, req.(*QueryFinalizedBlocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_QueryNetworkParticipation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryNetworkParticipationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).QueryNetworkParticipation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/QueryNetworkParticipation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).QueryNetworkParticipation(ctx, req.(*QueryNetworkParticipationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_QueryVotingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryVotingHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).QueryVotingHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/QueryVotingHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).QueryVotingHistory(ctx, req.(*QueryVotingHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_ResumeFinalityProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeFinalityProviderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).ResumeFinalityProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/ResumeFinalityProvider",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).ResumeFinalityProvider(ctx, req.(*ResumeFinalityProviderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_PauseFinalityProvider_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseFinalityProviderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).PauseFinalityProvider(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/PauseFinalityProvider",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).PauseFinalityProvider(ctx, req.(*PauseFinalityProviderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_InjectFault_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectFaultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).InjectFault(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/InjectFault",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).InjectFault(ctx, req.(*InjectFaultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/ListKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).ListKeys(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_SignFinality_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignFinalityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).SignFinality(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/SignFinality",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).SignFinality(ctx, req.(*SignFinalityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_ExportState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FinalityProvidersServer).ExportState(m, &finalityProvidersExportStateServer{stream})
+}
+
+type FinalityProviders_ExportStateServer interface {
+	Send(*ExportStateResponse) error
+	grpc.ServerStream
+}
+
+type finalityProvidersExportStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *finalityProvidersExportStateServer) Send(m *ExportStateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FinalityProviders_ImportState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FinalityProvidersServer).ImportState(&finalityProvidersImportStateServer{stream})
+}
+
+type FinalityProviders_ImportStateServer interface {
+	SendAndClose(*ImportStateResponse) error
+	Recv() (*ImportStateRequest, error)
+	grpc.ServerStream
+}
+
+type finalityProvidersImportStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *finalityProvidersImportStateServer) SendAndClose(m *ImportStateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *finalityProvidersImportStateServer) Recv() (*ImportStateRequest, error) {
+	m := new(ImportStateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FinalityProviders_SetRewardAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRewardAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).SetRewardAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/SetRewardAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).SetRewardAddress(ctx, req.(*SetRewardAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_QueryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).QueryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/QueryStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).QueryStatus(ctx, req.(*QueryStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_CommitPubRand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitPubRandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).CommitPubRand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/CommitPubRand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).CommitPubRand(ctx, req.(*CommitPubRandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FinalityProviders_TailLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FinalityProvidersServer).TailLogs(m, &finalityProvidersTailLogsServer{stream})
+}
+
+type FinalityProviders_TailLogsServer interface {
+	Send(*TailLogsResponse) error
+	grpc.ServerStream
+}
+
+type finalityProvidersTailLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *finalityProvidersTailLogsServer) Send(m *TailLogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FinalityProviders_ExportFinalityProofs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportFinalityProofsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FinalityProvidersServer).ExportFinalityProofs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.FinalityProviders/ExportFinalityProofs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FinalityProvidersServer).ExportFinalityProofs(ctx, req.(*ExportFinalityProofsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // FinalityProviders_ServiceDesc is the grpc.ServiceDesc for FinalityProviders service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -329,7 +1180,92 @@ var FinalityProviders_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SignMessageFromChainKey",
 			Handler:    _FinalityProviders_SignMessageFromChainKey_Handler,
 		},
+		{
+			MethodName: "SignMessage",
+			Handler:    _FinalityProviders_SignMessage_Handler,
+		},
+		{
+			MethodName: "VerifyMessage",
+			Handler:    _FinalityProviders_VerifyMessage_Handler,
+		},
+		{
+			MethodName: "ProveKeyOwnership",
+			Handler:    _FinalityProviders_ProveKeyOwnership_Handler,
+		},
+		{
+			MethodName: "ValidateState",
+			Handler:    _FinalityProviders_ValidateState_Handler,
+		},
+		{
+			MethodName: "QueryFinalizedBlocks",
+			Handler:    _FinalityProviders_QueryFinalizedBlocks_Handler,
+		},
+		{
+			MethodName: "QueryNetworkParticipation",
+			Handler:    _FinalityProviders_QueryNetworkParticipation_Handler,
+		},
+		{
+			MethodName: "QueryVotingHistory",
+			Handler:    _FinalityProviders_QueryVotingHistory_Handler,
+		},
+		{
+			MethodName: "ResumeFinalityProvider",
+			Handler:    _FinalityProviders_ResumeFinalityProvider_Handler,
+		},
+		{
+			MethodName: "PauseFinalityProvider",
+			Handler:    _FinalityProviders_PauseFinalityProvider_Handler,
+		},
+		{
+			MethodName: "InjectFault",
+			Handler:    _FinalityProviders_InjectFault_Handler,
+		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _FinalityProviders_ListKeys_Handler,
+		},
+		{
+			MethodName: "SignFinality",
+			Handler:    _FinalityProviders_SignFinality_Handler,
+		},
+		{
+			MethodName: "SetRewardAddress",
+			Handler:    _FinalityProviders_SetRewardAddress_Handler,
+		},
+		{
+			MethodName: "QueryStatus",
+			Handler:    _FinalityProviders_QueryStatus_Handler,
+		},
+		{
+			MethodName: "CommitPubRand",
+			Handler:    _FinalityProviders_CommitPubRand_Handler,
+		},
+		{
+			MethodName: "ExportFinalityProofs",
+			Handler:    _FinalityProviders_ExportFinalityProofs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SyncState",
+			Handler:       _FinalityProviders_SyncState_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportState",
+			Handler:       _FinalityProviders_ExportState_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportState",
+			Handler:       _FinalityProviders_ImportState_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "TailLogs",
+			Handler:       _FinalityProviders_TailLogs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "finality_providers.proto",
 }
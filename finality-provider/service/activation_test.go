@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestIsActivated(t *testing.T) {
+	tipHeight := uint64(100)
+
+	testCases := []struct {
+		name             string
+		activationHeight uint64
+		expectQuery      bool
+		expected         bool
+	}{
+		{
+			name:     "a finality provider without an activation height is active",
+			expected: true,
+		},
+		{
+			name:             "a finality provider is idle below its activation height",
+			activationHeight: tipHeight + 1,
+			expectQuery:      true,
+		},
+		{
+			name:             "a finality provider is active from its activation height",
+			activationHeight: tipHeight,
+			expectQuery:      true,
+			expected:         true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cc := mocks.NewMockClientController(gomock.NewController(t))
+			if tc.expectQuery {
+				cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: tipHeight}, nil).Times(1)
+			}
+
+			sk, err := btcec.NewPrivateKey()
+			require.NoError(t, err)
+			pollerCfg := fpcfg.DefaultChainPollerConfig()
+			pollerCfg.QueryRetryAttempts = 1
+			fp := &FinalityProviderInstance{
+				cfg: &fpcfg.Config{PollerConfig: &pollerCfg},
+				fpState: NewFpState(&store.StoredFinalityProvider{
+					BtcPk:            sk.PubKey(),
+					ActivationHeight: tc.activationHeight,
+				}, nil),
+				cc:      cc,
+				metrics: metrics.NewFpMetrics(),
+				logger:  zap.NewNop(),
+				ctx:     context.Background(),
+			}
+
+			activated, err := fp.isActivated()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, activated)
+		})
+	}
+}
@@ -1,16 +1,19 @@
 package service
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	sdkmath "cosmossdk.io/math"
 	bbntypes "github.com/babylonchain/babylon/types"
 	bstypes "github.com/babylonchain/babylon/x/btcstaking/types"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
@@ -20,37 +23,69 @@ import (
 	"github.com/babylonchain/finality-provider/clientcontroller"
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	"github.com/babylonchain/finality-provider/eotsmanager/client"
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+	"github.com/babylonchain/finality-provider/finality-provider/approval"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/eventbus"
+	"github.com/babylonchain/finality-provider/finality-provider/faultinject"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/lifecycle"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/store"
 	fpkr "github.com/babylonchain/finality-provider/keyring"
+	"github.com/babylonchain/finality-provider/log"
 	"github.com/babylonchain/finality-provider/metrics"
 	"github.com/babylonchain/finality-provider/types"
+	"github.com/babylonchain/finality-provider/util/securemem"
 )
 
 type FinalityProviderApp struct {
-	startOnce sync.Once
-	stopOnce  sync.Once
+	// lifecycle starts and stops the components of the app in the order of
+	// their dependencies
+	lifecycle *lifecycle.Graph
 
 	wg   sync.WaitGroup
 	quit chan struct{}
+	// halt receives the reason the app requests the daemon to shut down,
+	// which is then stopped gracefully by the server
+	halt chan error
 
 	cc           clientcontroller.ClientController
 	kr           keyring.Keyring
 	fps          *store.FinalityProviderStore
 	pubRandStore *store.PubRandProofStore
+	db           kvdb.Backend
 	config       *fpcfg.Config
 	logger       *zap.Logger
 	input        *strings.Reader
 
+	// registrationCC signs the registration txs with the registration key,
+	// which is nil if the registration key is the key of cc
+	registrationCC clientcontroller.ClientController
+
 	fpManager   *FinalityProviderManager
 	eotsManager eotsmanager.EOTSManager
 
 	metrics *metrics.FpMetrics
 
-	createFinalityProviderRequestChan   chan *createFinalityProviderRequest
-	registerFinalityProviderRequestChan chan *registerFinalityProviderRequest
-	finalityProviderRegisteredEventChan chan *finalityProviderRegisteredEvent
+	// approver is nil unless the daemon runs in approval mode
+	approver *approval.Verifier
+	// observer is nil unless the daemon runs in observer mode
+	observer *networkObserver
+	// indexer is nil unless the vote indexer is enabled
+	indexer *voteIndexer
+
+	// logTail streams the logs to the TailLogs RPC, which is nil if the
+	// logs cannot be tailed
+	logTail *log.Tail
+
+	// bus carries the requests to their handlers and the events of the app
+	// to the subsystems observing them
+	bus *eventbus.Bus
+
+	// dbStats are the last statistics of the database collected by the
+	// maintenance loop, which are nil until the first collection
+	dbStats atomic.Pointer[store.DBStats]
 }
 
 func NewFinalityProviderAppFromConfig(
@@ -58,21 +93,101 @@ func NewFinalityProviderAppFromConfig(
 	db kvdb.Backend,
 	logger *zap.Logger,
 ) (*FinalityProviderApp, error) {
-	cc, err := clientcontroller.NewClientController(cfg.ChainName, cfg.BabylonConfig, &cfg.BTCNetParams, logger)
+	// the section of the chain of the babylon config, if any, overrides it
+	cc, err := newChainController(cfg, cfg.ChainBBNConfig(cfg.BabylonConfig.ChainID), logger.Named("chain"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create rpc client for the consumer chain %s: %v", cfg.ChainName, err)
+		return nil, err
+	}
+
+	if cfg.SubmissionMode == fpcfg.SubmissionModeRelayer {
+		logger.Info("finality signatures will be handed to the relayer", zap.String("url", cfg.RelayerURL))
+	}
+
+	if faultinject.Enabled {
+		logger.Warn("the daemon is built with fault injection, which must not be used in production")
 	}
 
 	// if the EOTSManagerAddress is empty, run a local EOTS manager;
 	// otherwise connect a remote one with a gRPC client
-	em, err := client.NewEOTSManagerGRpcClient(cfg.EOTSManagerAddress)
+	em, err := NewEOTSManagerClientFromConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create EOTS manager client: %w", err)
+		return nil, err
 	}
 
 	logger.Info("successfully connected to a remote EOTS manager", zap.String("address", cfg.EOTSManagerAddress))
 
-	return NewFinalityProviderApp(cfg, cc, em, db, logger)
+	app, err := NewFinalityProviderApp(cfg, cc, em, db, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, chainID := range cfg.ChainIDs() {
+		if chainID == cfg.BabylonConfig.ChainID {
+			continue
+		}
+		chainCC, err := newChainController(cfg, cfg.ChainBBNConfig(chainID), logger.Named("chain"))
+		if err != nil {
+			return nil, err
+		}
+		app.fpManager.addChainClient(chainID, chainCC)
+		logger.Info("the finality providers of the chain will use its own client", zap.String("chain_id", chainID))
+	}
+
+	if cfg.BabylonConfig.RegistrationKey != "" && cfg.BabylonConfig.RegistrationKey != cfg.BabylonConfig.Key {
+		regCC, err := newRegistrationController(cfg, app.kr, logger)
+		if err != nil {
+			return nil, err
+		}
+		app.registrationCC = regCC
+	}
+
+	return app, nil
+}
+
+// newRegistrationController creates the client controller signing with the
+// registration key, switching to the sign mode of the Ledger devices for the
+// Ledger keys
+func newRegistrationController(cfg *fpcfg.Config, kr keyring.Keyring, logger *zap.Logger) (clientcontroller.ClientController, error) {
+	bbnCfg := *cfg.BabylonConfig
+	bbnCfg.Key = bbnCfg.RegistrationKey
+
+	isLedger, err := fpkr.IsLedgerKey(kr, bbnCfg.Key)
+	if err != nil {
+		return nil, err
+	}
+	if isLedger {
+		bbnCfg.SignModeStr = fpkr.LedgerSignMode
+		logger.Info("the registration transactions will be signed on the Ledger device", zap.String("key", bbnCfg.Key))
+	}
+
+	cc, err := clientcontroller.NewClientController(cfg.ChainName, &bbnCfg, &cfg.BTCNetParams, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the client with the registration key %s: %w", bbnCfg.Key, err)
+	}
+
+	return cc, nil
+}
+
+// NewEOTSManagerClientFromConfig connects the remote EOTS manager with the
+// pool, keep-alive and authentication settings of the config
+func NewEOTSManagerClientFromConfig(cfg *fpcfg.Config) (*client.EOTSManagerGRpcClient, error) {
+	emOpts := []client.Option{
+		client.WithPoolSize(cfg.EOTSManagerPoolSize),
+		client.WithKeepAlive(cfg.EOTSManagerKeepAlive),
+		client.WithMaxBackoff(cfg.EOTSManagerMaxBackoff),
+		client.WithConnectTimeout(cfg.GRPCConfig.ConnectionTimeout),
+		client.WithMaxMsgSize(cfg.GRPCConfig.MaxRecvMsgSize, cfg.GRPCConfig.MaxSendMsgSize),
+		client.WithLatencyObserver(metrics.NewFpMetrics().RecordEotsCallLatency),
+	}
+	if cfg.EOTSManagerAuthClient != "" {
+		emOpts = append(emOpts, client.WithAuth(cfg.EOTSManagerAuthClient, cfg.EOTSManagerAuthSecret))
+	}
+	em, err := client.NewEOTSManagerGRpcClient(cfg.EOTSManagerAddress, emOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EOTS manager client: %w", err)
+	}
+
+	return em, nil
 }
 
 func NewFinalityProviderApp(
@@ -82,6 +197,18 @@ func NewFinalityProviderApp(
 	db kvdb.Backend,
 	logger *zap.Logger,
 ) (*FinalityProviderApp, error) {
+	if config.RequireRemoteSigner {
+		remoteEm, err := newRemoteSignerEOTSManager(em)
+		if err != nil {
+			return nil, err
+		}
+		em = remoteEm
+	}
+
+	if config.EnableTestRPC {
+		logger.Warn("the test RPC is enabled, which can expose private keys and must not be used in production")
+	}
+
 	fpStore, err := store.NewFinalityProviderStore(db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initiate finality provider store: %w", err)
@@ -104,27 +231,94 @@ func NewFinalityProviderApp(
 
 	fpMetrics := metrics.NewFpMetrics()
 
-	fpm, err := NewFinalityProviderManager(fpStore, pubRandStore, config, cc, em, fpMetrics, logger)
+	fpm, err := NewFinalityProviderManager(fpStore, pubRandStore, config, cc, em, fpMetrics, logger.Named("fp"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create finality-provider manager: %w", err)
 	}
 
-	return &FinalityProviderApp{
-		cc:                                  cc,
-		fps:                                 fpStore,
-		pubRandStore:                        pubRandStore,
-		kr:                                  kr,
-		config:                              config,
-		logger:                              logger,
-		input:                               input,
-		fpManager:                           fpm,
-		eotsManager:                         em,
-		metrics:                             fpMetrics,
-		quit:                                make(chan struct{}),
-		createFinalityProviderRequestChan:   make(chan *createFinalityProviderRequest),
-		registerFinalityProviderRequestChan: make(chan *registerFinalityProviderRequest),
-		finalityProviderRegisteredEventChan: make(chan *finalityProviderRegisteredEvent),
-	}, nil
+	var approver *approval.Verifier
+	if config.ApprovalMode {
+		approver, err = approval.NewVerifier(config.ApproverPks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create approval verifier: %w", err)
+		}
+	}
+
+	var observer *networkObserver
+	if config.ObserverMode {
+		observer = newNetworkObserver(cc, config.ObserverWindow, logger.Named("observer"))
+	}
+
+	var indexer *voteIndexer
+	if config.VoteIndexer {
+		if err := fpStore.MigrateLastIndexedHeight(config.BabylonConfig.ChainID); err != nil {
+			return nil, fmt.Errorf("failed to migrate the vote index: %w", err)
+		}
+		indexer = newVoteIndexer(fpm.ccOf, fpStore, logger.Named("indexer"))
+		fpm.RegisterHook(indexer)
+	}
+
+	// the lifecycle events of the instances are published on the bus as well
+	bus := eventbus.New()
+	fpm.RegisterHook(newBusHook(bus))
+
+	app := &FinalityProviderApp{
+		cc:           cc,
+		fps:          fpStore,
+		pubRandStore: pubRandStore,
+		db:           db,
+		kr:           kr,
+		config:       config,
+		logger:       logger,
+		input:        input,
+		fpManager:    fpm,
+		eotsManager:  em,
+		metrics:      fpMetrics,
+		approver:     approver,
+		observer:     observer,
+		indexer:      indexer,
+		quit:         make(chan struct{}),
+		halt:         make(chan error, 1),
+		bus:          bus,
+	}
+	app.lifecycle, err = newAppLifecycle(app)
+	if err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// CheckApproval verifies the approval token of the operation with the given
+// payload, which always passes if the daemon does not run in approval mode
+func (app *FinalityProviderApp) CheckApproval(token, operation, payload string) error {
+	if app.approver == nil {
+		return nil
+	}
+
+	if err := app.approver.Verify(token, operation, payload); err != nil {
+		app.logger.Warn("rejected an operation without valid approval",
+			zap.String("operation", operation),
+			zap.String("payload", payload),
+			zap.Error(err),
+		)
+		// the payload is returned so that it can be handed to an approver
+		return fmt.Errorf("%w (operation: %s, payload: %s)", err, operation, payload)
+	}
+
+	app.logger.Info("approved an operation",
+		zap.String("operation", operation),
+		zap.String("payload", payload),
+	)
+
+	return nil
+}
+
+// RegisterHook adds a hook invoked on the lifecycle events of the
+// finality-provider instances, which allows embedding the daemon with
+// custom automation in addition to the configured hook commands
+func (app *FinalityProviderApp) RegisterHook(h hooks.Hook) {
+	app.fpManager.RegisterHook(h)
 }
 
 func (app *FinalityProviderApp) GetConfig() *fpcfg.Config {
@@ -159,8 +353,13 @@ func (app *FinalityProviderApp) GetFinalityProviderInfo(fpPk *bbntypes.BIP340Pub
 	return app.fpManager.FinalityProviderInfo(fpPk)
 }
 
-// GetFinalityProviderInstance returns the finality-provider instance with the given Babylon public key
+// GetFinalityProviderInstance returns the finality-provider instance with the given Babylon public key,
+// which is reloaded if it is unloaded as idle
 func (app *FinalityProviderApp) GetFinalityProviderInstance(fpPk *bbntypes.BIP340PubKey) (*FinalityProviderInstance, error) {
+	if app.fpManager.IsFinalityProviderUnloaded(fpPk) {
+		return app.fpManager.reloadFinalityProviderInstance(fpPk)
+	}
+
 	return app.fpManager.GetFinalityProviderInstance(fpPk)
 }
 
@@ -179,6 +378,19 @@ func (app *FinalityProviderApp) RegisterFinalityProvider(fpPkStr string) (*Regis
 		return nil, fmt.Errorf("finality-provider is already registered")
 	}
 
+	if !app.config.IsChainIDAllowed(fp.ChainID) {
+		return nil, fmt.Errorf("%w: %s", ErrChainIDNotAllowed, fp.ChainID)
+	}
+
+	if err := app.fpManager.chainParamsOf(fp.ChainID).checkCommission(fp.Commission); err != nil {
+		return nil, err
+	}
+
+	if err := app.checkNotRegistered(fp); err != nil {
+		return nil, err
+	}
+	app.warnMonikerCollisions(fp)
+
 	btcSig, err := bbntypes.NewBIP340Signature(fp.Pop.BtcSig)
 	if err != nil {
 		return nil, err
@@ -196,11 +408,14 @@ func (app *FinalityProviderApp) RegisterFinalityProvider(fpPkStr string) (*Regis
 		pop:             pop,
 		description:     fp.Description,
 		commission:      fp.Commission,
+		chainID:         fp.ChainID,
 		errResponse:     make(chan error, 1),
 		successResponse: make(chan *RegisterFinalityProviderResponse, 1),
 	}
 
-	app.registerFinalityProviderRequestChan <- request
+	if eventbus.Publish(app.bus, request) == 0 {
+		return nil, ErrAppNotStarted
+	}
 
 	select {
 	case err := <-request.errResponse:
@@ -212,13 +427,52 @@ func (app *FinalityProviderApp) RegisterFinalityProvider(fpPkStr string) (*Regis
 	}
 }
 
+// SetActivationHeight sets the height of the consumer chain before which the
+// finality provider stays idle, it takes effect when the finality provider is
+// started next time
+func (app *FinalityProviderApp) SetActivationHeight(fpPk *bbntypes.BIP340PubKey, activationHeight uint64) error {
+	return app.fps.SetFpActivationHeight(fpPk.MustToBTCPK(), activationHeight)
+}
+
+// SignFinality returns the finality signature of the running finality provider
+// over the given block without broadcasting it
+func (app *FinalityProviderApp) SignFinality(
+	fpPk *bbntypes.BIP340PubKey,
+	height uint64,
+	blockHash []byte,
+) (*proto.SignFinalityResponse, error) {
+	if !app.config.SignFinalityRPC {
+		return nil, ErrSignFinalityDisabled
+	}
+
+	fpi, err := app.GetFinalityProviderInstance(fpPk)
+	if err != nil {
+		return nil, err
+	}
+
+	return fpi.SignFinality(&types.BlockInfo{
+		Height: height,
+		Hash:   blockHash,
+	})
+}
+
 // StartHandlingFinalityProvider starts a finality-provider instance with the given Babylon public key
 // Note: this should be called right after the finality-provider is registered
 func (app *FinalityProviderApp) StartHandlingFinalityProvider(fpPk *bbntypes.BIP340PubKey, passphrase string) error {
+	if app.IsStandby() {
+		return ErrStandbyMode
+	}
+
 	return app.fpManager.StartFinalityProvider(fpPk, passphrase)
 }
 
 func (app *FinalityProviderApp) StartHandlingAll() error {
+	if app.IsStandby() {
+		app.logger.Info("not starting the finality providers in standby mode",
+			zap.String("active", app.config.StandbyOf))
+		return nil
+	}
+
 	return app.fpManager.StartAll()
 }
 
@@ -234,18 +488,25 @@ func (app *FinalityProviderApp) getFpPrivKey(fpPk []byte) (*btcec.PrivateKey, er
 
 // SyncFinalityProviderStatus syncs the status of the finality-providers
 func (app *FinalityProviderApp) SyncFinalityProviderStatus() error {
-	latestBlock, err := app.cc.QueryBestBlock()
-	if err != nil {
-		return err
-	}
-
 	fps, err := app.fps.GetAllStoredFinalityProviders()
 	if err != nil {
 		return err
 	}
 
+	tips := app.fpManager.newChainTips()
+
 	for _, fp := range fps {
-		vp, err := app.cc.QueryFinalityProviderVotingPower(fp.BtcPk, latestBlock.Height)
+		// a finality provider in safe mode stays so until it is resumed
+		if fp.Status == proto.FinalityProviderStatus_SAFE_MODE {
+			continue
+		}
+
+		latestBlock, err := tips.of(fp.ChainID)
+		if err != nil {
+			return err
+		}
+
+		vp, err := app.fpManager.ccOf(fp.ChainID).QueryFinalityProviderVotingPower(fp.BtcPk, latestBlock.Height)
 		if err != nil {
 			// if error occured then the finality-provider is not registered in the Babylon chain yet
 			continue
@@ -272,6 +533,13 @@ func (app *FinalityProviderApp) SyncFinalityProviderStatus() error {
 				if err != nil {
 					return err
 				}
+			case proto.FinalityProviderStatus_STANDBY:
+				// the standby of a previous run is switched again if the
+				// chain is still halted
+				err = app.fps.SetFpStatus(fp.BtcPk, proto.FinalityProviderStatus_REGISTERED)
+				if err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -279,47 +547,205 @@ func (app *FinalityProviderApp) SyncFinalityProviderStatus() error {
 	return nil
 }
 
+// ValidateState checks that every stored finality provider has a corresponding
+// key in the EOTS manager and vice versa, and reports the orphaned records
+func (app *FinalityProviderApp) ValidateState() (*StateValidationResult, error) {
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := app.eotsManager.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the EOTS keys: %w", err)
+	}
+
+	keysByPk := make(map[string]*eotstypes.KeyInfo, len(keys))
+	for _, k := range keys {
+		keysByPk[hex.EncodeToString(k.PubKey)] = k
+	}
+
+	res := &StateValidationResult{
+		FpsMissingKeys: make([]string, 0),
+		OrphanedKeys:   make([]*eotstypes.KeyInfo, 0),
+	}
+
+	fpPks := make(map[string]struct{}, len(storedFps))
+	for _, fp := range storedFps {
+		pkHex := fp.GetBIP340BTCPK().MarshalHex()
+		fpPks[pkHex] = struct{}{}
+		if _, ok := keysByPk[pkHex]; !ok && !fp.WatchOnly {
+			app.logger.Warn("the stored finality provider has no key in the EOTS manager",
+				zap.String("btc_pk", pkHex))
+			res.FpsMissingKeys = append(res.FpsMissingKeys, pkHex)
+		}
+	}
+
+	for _, k := range keys {
+		pkHex := hex.EncodeToString(k.PubKey)
+		if _, ok := fpPks[pkHex]; !ok {
+			app.logger.Warn("the EOTS key has no stored finality provider",
+				zap.String("key_name", k.Name),
+				zap.String("btc_pk", pkHex))
+			res.OrphanedKeys = append(res.OrphanedKeys, k)
+		}
+	}
+
+	return res, nil
+}
+
+// ListKeys lists the keys in the EOTS manager with the statuses of the stored
+// finality providers using them and their usage
+func (app *FinalityProviderApp) ListKeys() ([]*proto.EOTSKeyInfo, error) {
+	keys, err := app.eotsManager.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the EOTS keys: %w", err)
+	}
+
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+	fpStatuses := make(map[string]proto.FinalityProviderStatus, len(storedFps))
+	for _, fp := range storedFps {
+		fpStatuses[fp.GetBIP340BTCPK().MarshalHex()] = fp.Status
+	}
+
+	res := make([]*proto.EOTSKeyInfo, 0, len(keys))
+	for _, k := range keys {
+		keyInfo := &proto.EOTSKeyInfo{
+			KeyName:       k.Name,
+			BtcPkHex:      hex.EncodeToString(k.PubKey),
+			NumSignatures: k.NumSignatures,
+		}
+		keyInfo.BtcPkCompressedHex = types.CompressedBTCPkHex(keyInfo.BtcPkHex)
+		if status, ok := fpStatuses[keyInfo.BtcPkHex]; ok {
+			keyInfo.FpStatus = status.String()
+		}
+		if !k.CreatedAt.IsZero() {
+			keyInfo.CreatedAt = k.CreatedAt.Unix()
+		}
+		if !k.LastUsedAt.IsZero() {
+			keyInfo.LastUsedAt = k.LastUsedAt.Unix()
+		}
+		res = append(res, keyInfo)
+	}
+
+	return res, nil
+}
+
 // Start starts only the finality-provider daemon without any finality-provider instances
 func (app *FinalityProviderApp) Start() error {
-	var startErr error
-	app.startOnce.Do(func() {
-		app.logger.Info("Starting FinalityProviderApp")
-
-		app.wg.Add(3)
-		go app.eventLoop()
-		go app.registrationLoop()
-		go app.metricsUpdateLoop()
-	})
+	app.logger.Info("Starting FinalityProviderApp")
 
-	return startErr
+	return app.lifecycle.Start(context.Background())
 }
 
+// Stop stops the components of the app in the reverse order of their start,
+// i.e., the loops, the finality-provider instances, the clients of the chains
+// and the EOTS manager
 func (app *FinalityProviderApp) Stop() error {
-	var stopErr error
-	app.stopOnce.Do(func() {
-		app.logger.Info("Stopping FinalityProviderApp")
-
-		// Always stop the submission loop first to not generate additional events and actions
-		app.logger.Debug("Stopping submission loop")
-		close(app.quit)
-		app.wg.Wait()
-
-		app.logger.Debug("Stopping finality providers")
-		if err := app.fpManager.Stop(); err != nil {
-			stopErr = err
-			return
-		}
+	app.logger.Info("Stopping FinalityProviderApp")
 
-		app.logger.Debug("Stopping EOTS manager")
-		if err := app.eotsManager.Close(); err != nil {
-			stopErr = err
-			return
+	if err := app.lifecycle.Stop(context.Background()); err != nil {
+		return err
+	}
+
+	app.logger.Debug("FinalityProviderApp successfully stopped")
+
+	return nil
+}
+
+// defaultComponentTimeout bounds the start and the stop of each component of
+// the daemon, unless it has a timeout of its own
+const defaultComponentTimeout = 30 * time.Second
+
+// newAppLifecycle returns the components of the app. The loops depend on the
+// instances so that they are stopped first, not to generate additional events
+// and actions for the instances being stopped
+func newAppLifecycle(app *FinalityProviderApp) (*lifecycle.Graph, error) {
+	g := lifecycle.New(app.logger, defaultComponentTimeout)
+	components := []lifecycle.Component{
+		{
+			Name: "eots",
+			Stop: func(context.Context) error {
+				return app.eotsManager.Close()
+			},
+		},
+		{
+			Name:      "chain",
+			DependsOn: []string{"eots"},
+			Stop: func(context.Context) error {
+				if err := app.fpManager.closeChainClients(); err != nil {
+					return err
+				}
+				if app.registrationCC != nil {
+					return app.registrationCC.Close()
+				}
+				return nil
+			},
+		},
+		{
+			// the instances are started on demand, after the app is started
+			Name:      "instances",
+			DependsOn: []string{"eots", "chain"},
+			Stop: func(context.Context) error {
+				// the manager is not started if no finality provider has been started
+				if !app.fpManager.isStarted.Load() {
+					return nil
+				}
+				return app.fpManager.Stop()
+			},
+			// the instances drain their in-flight submissions on shutdown
+			Timeout: app.config.ShutdownDrainTimeout + defaultComponentTimeout,
+		},
+		{
+			Name:      "loops",
+			DependsOn: []string{"instances"},
+			Start: func(context.Context) error {
+				app.startLoops()
+				return nil
+			},
+			Stop: func(context.Context) error {
+				close(app.quit)
+				app.wg.Wait()
+				app.bus.Close()
+				return nil
+			},
+		},
+	}
+	for _, c := range components {
+		if err := g.Add(c); err != nil {
+			return nil, err
 		}
+	}
 
-		app.logger.Debug("FinalityProviderApp successfully stopped")
+	return g, nil
+}
 
-	})
-	return stopErr
+func (app *FinalityProviderApp) startLoops() {
+	app.subscribeHandlers()
+
+	app.wg.Add(4)
+	go app.metricsUpdateLoop()
+	go app.clockSkewCheckLoop()
+	go app.stateFileLoop()
+	go app.dbMaintenanceLoop()
+
+	if app.IsStandby() {
+		app.wg.Add(1)
+		go app.standbySyncLoop()
+	}
+
+	if app.observer != nil {
+		app.wg.Add(1)
+		go app.networkObserverLoop()
+	}
+
+	if app.indexer != nil {
+		app.wg.Add(1)
+		go app.voteIndexerLoop()
+	}
 }
 
 func (app *FinalityProviderApp) CreateFinalityProvider(
@@ -327,6 +753,9 @@ func (app *FinalityProviderApp) CreateFinalityProvider(
 	description *stakingtypes.Description,
 	commission *sdkmath.LegacyDec,
 ) (*CreateFinalityProviderResult, error) {
+	if !app.config.IsChainIDAllowed(chainID) {
+		return nil, fmt.Errorf("%w: %s", ErrChainIDNotAllowed, chainID)
+	}
 
 	req := &createFinalityProviderRequest{
 		keyName:         keyName,
@@ -339,7 +768,9 @@ func (app *FinalityProviderApp) CreateFinalityProvider(
 		successResponse: make(chan *createFinalityProviderResponse, 1),
 	}
 
-	app.createFinalityProviderRequestChan <- req
+	if eventbus.Publish(app.bus, req) == 0 {
+		return nil, ErrAppNotStarted
+	}
 
 	select {
 	case err := <-req.errResponse:
@@ -359,6 +790,11 @@ func (app *FinalityProviderApp) handleCreateFinalityProviderRequest(req *createF
 	if err != nil {
 		return nil, err
 	}
+	// the Ledger Cosmos app cannot sign the proof of possession, which is
+	// not a tx, so only the registration key may be on a Ledger device
+	if isLedger, err := kr.IsLedgerKey(); err == nil && isLedger {
+		return nil, fmt.Errorf("%w: %s", ErrLedgerChainKey, req.keyName)
+	}
 	chainSk, err := kr.GetChainPrivKey(req.passPhrase)
 	if err != nil {
 		// the chain key does not exist, should create the chain key first
@@ -367,8 +803,10 @@ func (app *FinalityProviderApp) handleCreateFinalityProviderRequest(req *createF
 			return nil, fmt.Errorf("failed to create chain key %s: %w", req.keyName, err)
 		}
 		chainSk = &secp256k1.PrivKey{Key: keyInfo.PrivateKey.Serialize()}
+		keyInfo.PrivateKey.Zero()
 	}
 	chainPk := &secp256k1.PubKey{Key: chainSk.PubKey().Bytes()}
+	securemem.Zero(chainSk.Key)
 
 	// 2. create EOTS key
 	fpPkBytes, err := app.eotsManager.CreateKey(req.keyName, req.passPhrase, req.hdPath)
@@ -379,13 +817,9 @@ func (app *FinalityProviderApp) handleCreateFinalityProviderRequest(req *createF
 	if err != nil {
 		return nil, err
 	}
-	fpRecord, err := app.eotsManager.KeyRecord(fpPk.MustMarshal(), req.passPhrase)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get finality-provider record: %w", err)
-	}
 
 	// 3. create proof-of-possession
-	pop, err := kr.CreatePop(fpRecord.PrivKey, req.passPhrase)
+	pop, err := app.createPop(kr, fpPk, req.passPhrase)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create proof-of-possession of the finality-provider: %w", err)
 	}
@@ -393,7 +827,6 @@ func (app *FinalityProviderApp) handleCreateFinalityProviderRequest(req *createF
 	if err := app.fps.CreateFinalityProvider(chainPk, fpPk.MustToBTCPK(), req.description, req.commission, req.keyName, req.chainID, pop.BabylonSig, pop.BtcSig); err != nil {
 		return nil, fmt.Errorf("failed to save finality-provider: %w", err)
 	}
-	app.fpManager.metrics.RecordFpStatus(fpPk.MarshalHex(), proto.FinalityProviderStatus_CREATED)
 
 	app.logger.Info("successfully created a finality-provider",
 		zap.String("btc_pk", fpPk.MarshalHex()),
@@ -411,6 +844,29 @@ func (app *FinalityProviderApp) handleCreateFinalityProviderRequest(req *createF
 	}, nil
 }
 
+// createPop creates the proof-of-possession of the finality provider. If a remote
+// signer is required, the BTC signature is requested from the EOTS manager instead
+// of loading the EOTS private key
+func (app *FinalityProviderApp) createPop(
+	kr *fpkr.ChainKeyringController,
+	fpPk *bbntypes.BIP340PubKey,
+	passphrase string,
+) (*bstypes.ProofOfPossession, error) {
+	if app.config.RequireRemoteSigner {
+		return kr.CreatePopWithBTCSigner(fpPk.MustToBTCPK(), passphrase, func(msg []byte) (*schnorr.Signature, error) {
+			return app.eotsManager.SignSchnorrSig(fpPk.MustMarshal(), msg, passphrase)
+		})
+	}
+
+	fpRecord, err := app.eotsManager.KeyRecord(fpPk.MustMarshal(), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get finality-provider record: %w", err)
+	}
+	defer fpRecord.PrivKey.Zero()
+
+	return kr.CreatePop(fpRecord.PrivKey, passphrase)
+}
+
 // SignRawMsg loads the keyring private key and signs a message.
 func (app *FinalityProviderApp) SignRawMsg(
 	keyName, passPhrase, hdPath string,
@@ -420,6 +876,7 @@ func (app *FinalityProviderApp) SignRawMsg(
 	if err != nil {
 		return nil, err
 	}
+	defer securemem.Zero(chainSk.Key)
 
 	return chainSk.Sign(rawMsgToSign)
 }
@@ -440,6 +897,7 @@ func (app *FinalityProviderApp) loadChainKeyring(
 			return nil, nil, fmt.Errorf("failed to create chain key %s: %w", keyName, err)
 		}
 		chainSk = &secp256k1.PrivKey{Key: keyInfo.PrivateKey.Serialize()}
+		keyInfo.PrivateKey.Zero()
 	}
 
 	return kr, chainSk, nil
@@ -451,12 +909,17 @@ func (app *FinalityProviderApp) StoreFinalityProvider(
 	description *stakingtypes.Description,
 	commission *sdkmath.LegacyDec,
 ) (*store.StoredFinalityProvider, error) {
+	if !app.config.IsChainIDAllowed(chainID) {
+		return nil, fmt.Errorf("%w: %s", ErrChainIDNotAllowed, chainID)
+	}
+
 	// 1. check if the chain key exists
 	kr, chainSk, err := app.loadChainKeyring(keyName, passPhrase, hdPath)
 	if err != nil {
 		return nil, err
 	}
 	chainPk := &secp256k1.PubKey{Key: chainSk.PubKey().Bytes()}
+	securemem.Zero(chainSk.Key)
 
 	// 2. create EOTS key
 	fpPkBytes, err := app.eotsManager.CreateKey(keyName, passPhrase, hdPath)
@@ -467,13 +930,9 @@ func (app *FinalityProviderApp) StoreFinalityProvider(
 	if err != nil {
 		return nil, err
 	}
-	fpRecord, err := app.eotsManager.KeyRecord(fpPk.MustMarshal(), passPhrase)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get finality-provider record: %w", err)
-	}
 
 	// 3. create proof-of-possession
-	pop, err := kr.CreatePop(fpRecord.PrivKey, passPhrase)
+	pop, err := app.createPop(kr, fpPk, passPhrase)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create proof-of-possession of the finality provider: %w", err)
 	}
@@ -517,103 +976,119 @@ func CreateChainKey(keyringDir, chainID, keyName, backend, passphrase, hdPath, m
 	return krController.CreateChainKey(passphrase, hdPath, mnemonic)
 }
 
-// main event loop for the finality-provider app
-func (app *FinalityProviderApp) eventLoop() {
-	defer app.wg.Done()
-
-	for {
-		select {
-		case req := <-app.createFinalityProviderRequestChan:
-			res, err := app.handleCreateFinalityProviderRequest(req)
-			if err != nil {
-				req.errResponse <- err
-				continue
-			}
+// CreateLedgerChainKey saves a reference to the chain key at the given account
+// and index of the Ledger device connected
+func CreateLedgerChainKey(keyringDir, chainID, keyName, backend, hrp string, account, index uint32) (*types.ChainKeyInfo, error) {
+	sdkCtx, err := fpkr.CreateClientCtx(
+		keyringDir, chainID,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-			req.successResponse <- &createFinalityProviderResponse{FpInfo: res.FpInfo}
+	krController, err := fpkr.NewChainKeyringController(
+		sdkCtx,
+		keyName,
+		backend,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		case ev := <-app.finalityProviderRegisteredEventChan:
-			// change the status of the finality-provider to registered
-			err := app.fps.SetFpStatus(ev.btcPubKey.MustToBTCPK(), proto.FinalityProviderStatus_REGISTERED)
-			if err != nil {
-				app.logger.Fatal("failed to set finality-provider status to REGISTERED",
-					zap.String("pk", ev.btcPubKey.MarshalHex()),
-					zap.Error(err),
-				)
-			}
-			app.fpManager.metrics.RecordFpStatus(ev.btcPubKey.MarshalHex(), proto.FinalityProviderStatus_REGISTERED)
+	return krController.CreateLedgerChainKey(hrp, account, index)
+}
 
-			// return to the caller
-			ev.successResponse <- &RegisterFinalityProviderResponse{
-				bbnPubKey: ev.bbnPubKey,
-				btcPubKey: ev.btcPubKey,
-				TxHash:    ev.txHash,
-			}
+// handleRegisterFinalityProviderRequest registers the finality provider on
+// the consumer chain. It does not retry, as the failure is most likely due
+// to a user error returned to the caller
+func (app *FinalityProviderApp) handleRegisterFinalityProviderRequest(req *registerFinalityProviderRequest) {
+	// TODO: need to start passing context here to be able to cancel the request in case of app quiting
+	popBytes, err := req.pop.Marshal()
+	if err != nil {
+		req.errResponse <- err
+		return
+	}
 
-		case <-app.quit:
-			app.logger.Debug("exiting main event loop")
-			return
+	desBytes, err := req.description.Marshal()
+	if err != nil {
+		req.errResponse <- err
+		return
+	}
+	// the registration key only signs for the chain of the babylon config
+	regCC := app.fpManager.ccOf(req.chainID)
+	if app.registrationCC != nil && regCC == app.cc {
+		regCC = app.registrationCC
+		if isLedger, err := fpkr.IsLedgerKey(app.kr, app.config.BabylonConfig.RegistrationKey); err == nil && isLedger {
+			app.logger.Info("confirm the registration transaction on the Ledger device",
+				zap.String("key", app.config.BabylonConfig.RegistrationKey),
+				zap.String("btc_pk", req.btcPubKey.MarshalHex()))
 		}
 	}
-}
+	res, err := regCC.RegisterFinalityProvider(
+		req.bbnPubKey.Key,
+		req.btcPubKey.MustToBTCPK(),
+		popBytes,
+		req.commission,
+		desBytes,
+	)
 
-func (app *FinalityProviderApp) registrationLoop() {
-	defer app.wg.Done()
-	for {
-		select {
-		case req := <-app.registerFinalityProviderRequestChan:
-			// we won't do any retries here to not block the loop for more important messages.
-			// Most probably it fails due so some user error so we just return the error to the user.
-			// TODO: need to start passing context here to be able to cancel the request in case of app quiting
-			popBytes, err := req.pop.Marshal()
-			if err != nil {
-				req.errResponse <- err
-				continue
-			}
+	if err != nil {
+		app.logger.Error(
+			"failed to register finality-provider",
+			zap.String("pk", req.btcPubKey.MarshalHex()),
+			zap.Error(err),
+		)
+		req.errResponse <- err
+		return
+	}
 
-			desBytes, err := req.description.Marshal()
-			if err != nil {
-				req.errResponse <- err
-				continue
-			}
-			res, err := app.cc.RegisterFinalityProvider(
-				req.bbnPubKey.Key,
-				req.btcPubKey.MustToBTCPK(),
-				popBytes,
-				req.commission,
-				desBytes,
-			)
+	app.logger.Info(
+		"successfully registered finality-provider on babylon",
+		zap.String("btc_pk", req.btcPubKey.MarshalHex()),
+		zap.String("babylon_pk", hex.EncodeToString(req.bbnPubKey.Key)),
+		zap.String("txHash", res.TxHash),
+	)
 
-			if err != nil {
-				app.logger.Error(
-					"failed to register finality-provider",
-					zap.String("pk", req.btcPubKey.MarshalHex()),
-					zap.Error(err),
-				)
-				req.errResponse <- err
-				continue
-			}
+	eventbus.Publish(app.bus, &finalityProviderRegisteredEvent{
+		btcPubKey: req.btcPubKey,
+		bbnPubKey: req.bbnPubKey,
+		txHash:    res.TxHash,
+		// pass the channel to the event so that we can send the response to the user which requested
+		// the registration
+		successResponse: req.successResponse,
+	})
+}
 
-			app.logger.Info(
-				"successfully registered finality-provider on babylon",
-				zap.String("btc_pk", req.btcPubKey.MarshalHex()),
-				zap.String("babylon_pk", hex.EncodeToString(req.bbnPubKey.Key)),
-				zap.String("txHash", res.TxHash),
-			)
-
-			app.finalityProviderRegisteredEventChan <- &finalityProviderRegisteredEvent{
-				btcPubKey: req.btcPubKey,
-				bbnPubKey: req.bbnPubKey,
-				txHash:    res.TxHash,
-				// pass the channel to the event so that we can send the response to the user which requested
-				// the registration
-				successResponse: req.successResponse,
-			}
-		case <-app.quit:
-			app.logger.Debug("exiting registration loop")
-			return
-		}
+// handleFinalityProviderRegisteredEvent saves the registration of the
+// finality provider and returns it to the caller
+func (app *FinalityProviderApp) handleFinalityProviderRegisteredEvent(ev *finalityProviderRegisteredEvent) {
+	// change the status of the finality-provider to registered
+	err := app.fps.SetFpStatus(ev.btcPubKey.MustToBTCPK(), proto.FinalityProviderStatus_REGISTERED)
+	if err != nil {
+		app.logger.Fatal("failed to set finality-provider status to REGISTERED",
+			zap.String("pk", ev.btcPubKey.MarshalHex()),
+			zap.Error(err),
+		)
 	}
+	if err := app.fps.SetFpRegistrationTxHash(ev.btcPubKey.MustToBTCPK(), ev.txHash); err != nil {
+		app.logger.Error("failed to save the registration tx hash of the finality-provider",
+			zap.String("pk", ev.btcPubKey.MarshalHex()),
+			zap.String("tx_hash", ev.txHash),
+			zap.Error(err),
+		)
+	}
+
+	// return to the caller
+	ev.successResponse <- &RegisterFinalityProviderResponse{
+		bbnPubKey: ev.bbnPubKey,
+		btcPubKey: ev.btcPubKey,
+		TxHash:    ev.txHash,
+	}
+
+	eventbus.Publish(app.bus, &FinalityProviderRegisteredEvent{
+		BtcPk:  ev.btcPubKey,
+		TxHash: ev.txHash,
+	})
 }
 
 func (app *FinalityProviderApp) metricsUpdateLoop() {
@@ -640,3 +1115,79 @@ func (app *FinalityProviderApp) metricsUpdateLoop() {
 		}
 	}
 }
+
+// Halted returns the channel receiving the reason the app requests the daemon
+// to shut down, upon which the daemon is stopped as on a shutdown signal
+func (app *FinalityProviderApp) Halted() <-chan error {
+	return app.halt
+}
+
+// requestHalt requests the daemon to shut down, which only keeps the first
+// reason
+func (app *FinalityProviderApp) requestHalt(reason error) {
+	select {
+	case app.halt <- reason:
+	default:
+	}
+}
+
+// clockSkewCheckLoop periodically compares the local clock with the timestamp of
+// the latest block. A skewed clock leads to premature or late submissions, so the
+// daemon warns about it or halts if configured to do so. As a stalled consumer
+// chain also looks like a local clock running ahead, the check is skipped while
+// the chain is halted or its tip does not advance between two checks
+func (app *FinalityProviderApp) clockSkewCheckLoop() {
+	defer app.wg.Done()
+
+	if app.config.ClockSkewCheckInterval == 0 {
+		app.logger.Info("the clock skew check is disabled")
+		return
+	}
+
+	checkTicker := time.NewTicker(app.config.ClockSkewCheckInterval)
+	defer checkTicker.Stop()
+
+	checker := newClockSkewChecker(app.config.MaxClockSkew)
+	for {
+		select {
+		case <-checkTicker.C:
+			if app.fpManager.isChainHalted(app.config.BabylonConfig.ChainID) {
+				app.logger.Debug("skipped the clock skew check as the consumer chain is halted")
+				continue
+			}
+
+			// the height and the timestamp are read from the same block
+			tip, err := app.cc.QueryBestBlockTime()
+			if err != nil {
+				app.logger.Debug("failed to query the timestamp of the latest block", zap.Error(err))
+				continue
+			}
+
+			skew, measured := checker.observe(tip.Height, tip.Time, time.Now())
+			if !measured {
+				continue
+			}
+			app.metrics.RecordClockSkew(skew)
+
+			if !checker.exceeds(skew) {
+				continue
+			}
+
+			fields := []zap.Field{
+				zap.Duration("skew", skew),
+				zap.Duration("max_skew", app.config.MaxClockSkew),
+				zap.Time("block_time", tip.Time),
+			}
+			if app.config.HaltOnClockSkew {
+				app.logger.Error("the local clock is skewed from the consumer chain, halting", fields...)
+				app.requestHalt(fmt.Errorf("%w: the skew %v exceeds %v", ErrClockSkewed, skew, app.config.MaxClockSkew))
+				return
+			}
+			app.logger.Warn("the local clock is skewed from the consumer chain", fields...)
+
+		case <-app.quit:
+			app.logger.Debug("exiting clock skew check loop")
+			return
+		}
+	}
+}
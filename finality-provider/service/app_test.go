@@ -8,6 +8,7 @@ import (
 
 	bbntypes "github.com/babylonchain/babylon/types"
 	bstypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -97,6 +98,8 @@ func FuzzRegisterFinalityProvider(f *testing.F) {
 		require.Equal(t, fpInfo.BtcPkHex, fpListInfo[0].BtcPkHex)
 
 		txHash := testutil.GenRandomHexStr(r, 32)
+		mockClientController.EXPECT().QueryRegisteredFinalityProvider(fp.BtcPk).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().QueryRegisteredFinalityProviders().Return(nil, nil).AnyTimes()
 		mockClientController.EXPECT().
 			RegisterFinalityProvider(
 				fp.ChainPk.Key,
@@ -122,5 +125,262 @@ func FuzzRegisterFinalityProvider(f *testing.F) {
 		require.NoError(t, err)
 		require.Equal(t, proto.FinalityProviderStatus_name[1], fpInfo.Status)
 		require.Equal(t, true, fpInfo.IsRunning)
+		require.Equal(t, txHash, fpInfo.RegistrationTxHash)
+	})
+}
+
+// TestAllowedChainIDs tests that the finality providers are only created,
+// stored and registered with the allowed chain IDs
+func TestAllowedChainIDs(t *testing.T) {
+	chainID := "allowed-chain"
+
+	testCases := []struct {
+		name      string
+		allowed   []string
+		expectErr bool
+	}{
+		{
+			name: "any chain ID is allowed with an empty list",
+		},
+		{
+			name:    "an allowed chain ID is accepted",
+			allowed: []string{"other-chain", chainID},
+		},
+		{
+			name:      "a chain ID not allowed is refused",
+			allowed:   []string{"other-chain"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			logger := zap.NewNop()
+
+			eotsHomeDir := filepath.Join(t.TempDir(), "eots-home")
+			eotsCfg := eotscfg.DefaultConfigWithHomePath(eotsHomeDir)
+			dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+			require.NoError(t, err)
+			defer dbBackend.Close()
+			em, err := eotsmanager.NewLocalEOTSManager(eotsHomeDir, eotsCfg.KeyringBackend, dbBackend, logger)
+			require.NoError(t, err)
+
+			mockClientController := testutil.PrepareMockedClientController(t, r, 1, 2)
+			mockClientController.EXPECT().QueryRegisteredFinalityProvider(gomock.Any()).Return(nil, nil).AnyTimes()
+			mockClientController.EXPECT().QueryRegisteredFinalityProviders().Return(nil, nil).AnyTimes()
+			if !tc.expectErr {
+				mockClientController.EXPECT().
+					RegisterFinalityProvider(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&types.TxResponse{TxHash: testutil.GenRandomHexStr(r, 32)}, nil).Times(1)
+			}
+
+			fpHomeDir := filepath.Join(t.TempDir(), "fp-home")
+			fpCfg := config.DefaultConfigWithHome(fpHomeDir)
+			fpdb, err := fpCfg.DatabaseConfig.GetDbBackend()
+			require.NoError(t, err)
+			defer fpdb.Close()
+			app, err := service.NewFinalityProviderApp(&fpCfg, mockClientController, em, fpdb, logger)
+			require.NoError(t, err)
+			require.NoError(t, app.Start())
+			defer func() {
+				require.NoError(t, app.Stop())
+			}()
+
+			keyNames := make([]string, 3)
+			for i := range keyNames {
+				keyNames[i] = testutil.GenRandomHexStr(r, 4)
+				_, err = service.CreateChainKey(fpCfg.BabylonConfig.KeyDirectory, fpCfg.BabylonConfig.ChainID,
+					keyNames[i], keyring.BackendTest, passphrase, hdPath, "")
+				require.NoError(t, err)
+			}
+
+			// the finality provider to register is stored before the chain IDs are restricted
+			toRegister, err := app.StoreFinalityProvider(keyNames[0], passphrase, hdPath, chainID,
+				testutil.RandomDescription(r), testutil.ZeroCommissionRate())
+			require.NoError(t, err)
+			fpCfg.AllowedChainIDs = tc.allowed
+
+			_, err = app.CreateFinalityProvider(keyNames[1], chainID, passphrase, hdPath,
+				testutil.RandomDescription(r), testutil.ZeroCommissionRate())
+			checkChainIDErr(t, tc.expectErr, err)
+
+			_, err = app.StoreFinalityProvider(keyNames[2], passphrase, hdPath, chainID,
+				testutil.RandomDescription(r), testutil.ZeroCommissionRate())
+			checkChainIDErr(t, tc.expectErr, err)
+
+			_, err = app.RegisterFinalityProvider(toRegister.GetBIP340BTCPK().MarshalHex())
+			checkChainIDErr(t, tc.expectErr, err)
+		})
+	}
+}
+
+func checkChainIDErr(t *testing.T, expectErr bool, err error) {
+	if expectErr {
+		require.ErrorIs(t, err, service.ErrChainIDNotAllowed)
+		return
+	}
+	require.NoError(t, err)
+}
+
+// FuzzValidateState tests that the stored finality providers without EOTS keys
+// and the EOTS keys without stored finality providers are reported, and that
+// the finality providers without EOTS keys are not started
+func FuzzValidateState(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		logger := zap.NewNop()
+		eotsHomeDir := filepath.Join(t.TempDir(), "eots-home")
+		eotsCfg := eotscfg.DefaultConfigWithHomePath(eotsHomeDir)
+		dbBackend, err := eotsCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		em, err := eotsmanager.NewLocalEOTSManager(eotsHomeDir, eotsCfg.KeyringBackend, dbBackend, logger)
+		require.NoError(t, err)
+		defer func() {
+			dbBackend.Close()
+		}()
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+2)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+
+		fpCfg := config.DefaultConfigWithHome(filepath.Join(t.TempDir(), "fp-home"))
+		fpdb, err := fpCfg.DatabaseConfig.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			err = fpdb.Close()
+			require.NoError(t, err)
+		}()
+		app, err := service.NewFinalityProviderApp(&fpCfg, mockClientController, em, fpdb, logger)
+		require.NoError(t, err)
+		err = app.Start()
+		require.NoError(t, err)
+		defer func() {
+			err = app.Stop()
+			require.NoError(t, err)
+		}()
+
+		// a finality provider with its EOTS key
+		validFp := testutil.GenStoredFinalityProvider(r, t, app, passphrase, hdPath)
+
+		// a registered finality provider whose EOTS key is missing
+		missingKeyFp := testutil.GenRandomFinalityProvider(r, t)
+		fpStore := app.GetFinalityProviderStore()
+		err = fpStore.CreateFinalityProvider(
+			missingKeyFp.ChainPk,
+			missingKeyFp.BtcPk,
+			missingKeyFp.Description,
+			missingKeyFp.Commission,
+			missingKeyFp.KeyName,
+			missingKeyFp.ChainID,
+			missingKeyFp.Pop.ChainSig,
+			missingKeyFp.Pop.BtcSig,
+		)
+		require.NoError(t, err)
+		err = fpStore.SetFpStatus(missingKeyFp.BtcPk, proto.FinalityProviderStatus_REGISTERED)
+		require.NoError(t, err)
+
+		// an EOTS key without a finality provider
+		orphanedPk, err := em.CreateKey(testutil.GenRandomHexStr(r, 4), passphrase, hdPath)
+		require.NoError(t, err)
+
+		res, err := app.ValidateState()
+		require.NoError(t, err)
+		require.Equal(t, []string{missingKeyFp.GetBIP340BTCPK().MarshalHex()}, res.FpsMissingKeys)
+		require.Len(t, res.OrphanedKeys, 1)
+		require.Equal(t, orphanedPk, res.OrphanedKeys[0].PubKey)
+		require.NotContains(t, res.FpsMissingKeys, validFp.GetBIP340BTCPK().MarshalHex())
+
+		err = app.StartHandlingAll()
+		require.NoError(t, err)
+		require.Empty(t, app.ListFinalityProviderInstances())
+	})
+}
+
+// FuzzResumeFinalityProvider tests that only a finality provider in safe mode
+// is resumed, which is then running with the status set back to REGISTERED
+func FuzzResumeFinalityProvider(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+1)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryBlock(gomock.Any()).Return(&types.BlockInfo{Height: currentHeight}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&types.TxResponse{TxHash: ""}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), gomock.Any()).Return(uint64(0), nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderSlashed(gomock.Any()).Return(false, nil).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+		fpPk := fpIns.GetBtcPkBIP340()
+
+		// a finality provider not in safe mode cannot be resumed
+		err := app.ResumeFinalityProvider(fpPk, passphrase)
+		require.ErrorIs(t, err, service.ErrNotInSafeMode)
+
+		err = app.GetFinalityProviderStore().SetFpStatus(fpPk.MustToBTCPK(), proto.FinalityProviderStatus_SAFE_MODE)
+		require.NoError(t, err)
+		err = app.ResumeFinalityProvider(fpPk, passphrase)
+		require.NoError(t, err)
+
+		resumedIns, err := app.GetFinalityProviderInstance(fpPk)
+		require.NoError(t, err)
+		require.True(t, resumedIns.IsRunning())
+		require.Equal(t, proto.FinalityProviderStatus_REGISTERED, resumedIns.GetStatus())
+		storedFp, err := app.GetFinalityProviderStore().GetFinalityProvider(fpPk.MustToBTCPK())
+		require.NoError(t, err)
+		require.Equal(t, proto.FinalityProviderStatus_REGISTERED, storedFp.Status)
+
+		// a running finality provider cannot be resumed again
+		err = app.ResumeFinalityProvider(fpPk, passphrase)
+		require.ErrorIs(t, err, service.ErrNotInSafeMode)
+	})
+}
+
+// FuzzPauseFinalityProvider tests that a paused finality provider is stopped
+// in safe mode until it is resumed
+func FuzzPauseFinalityProvider(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+1)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryBlock(gomock.Any()).Return(&types.BlockInfo{Height: currentHeight}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&types.TxResponse{TxHash: ""}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), gomock.Any()).Return(uint64(0), nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderSlashed(gomock.Any()).Return(false, nil).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+		fpPk := fpIns.GetBtcPkBIP340()
+
+		err := app.PauseFinalityProvider(fpPk)
+		require.NoError(t, err)
+		_, err = app.GetFinalityProviderInstance(fpPk)
+		require.Error(t, err)
+		storedFp, err := app.GetFinalityProviderStore().GetFinalityProvider(fpPk.MustToBTCPK())
+		require.NoError(t, err)
+		require.Equal(t, proto.FinalityProviderStatus_SAFE_MODE, storedFp.Status)
+
+		// a stopped finality provider cannot be paused
+		err = app.PauseFinalityProvider(fpPk)
+		require.Error(t, err)
+
+		err = app.ResumeFinalityProvider(fpPk, passphrase)
+		require.NoError(t, err)
+		resumedIns, err := app.GetFinalityProviderInstance(fpPk)
+		require.NoError(t, err)
+		require.True(t, resumedIns.IsRunning())
 	})
 }
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const redactedValue = "[REDACTED]"
+
+// secretFields are the names of the request fields that must never be
+// written to the audit log
+var secretFields = map[protoreflect.Name]struct{}{
+	"passphrase":     {},
+	"approval_token": {},
+}
+
+// auditInterceptor records every RPC call to a dedicated audit logger
+type auditInterceptor struct {
+	logger *zap.Logger
+}
+
+func newAuditInterceptor(logger *zap.Logger) *auditInterceptor {
+	return &auditInterceptor{logger: logger}
+}
+
+// unary returns the interceptor auditing unary RPC calls with their parameters
+func (a *auditInterceptor) unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		a.record(ctx, info.FullMethod, start, err, zap.String("params", redactedParams(req)))
+
+		return resp, err
+	}
+}
+
+// stream returns the interceptor auditing streaming RPC calls once the
+// stream is closed
+func (a *auditInterceptor) stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, ss)
+
+		a.record(ss.Context(), info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func (a *auditInterceptor) record(ctx context.Context, method string, start time.Time, err error, fields ...zap.Field) {
+	fields = append(fields,
+		zap.String("method", method),
+		zap.String("caller", callerAddress(ctx)),
+		zap.String("user_agent", callerUserAgent(ctx)),
+		zap.Duration("latency", time.Since(start)),
+		zap.String("code", status.Code(err).String()),
+	)
+	if err != nil {
+		fields = append(fields, zap.String("error", err.Error()))
+	}
+
+	a.logger.Info("rpc call", fields...)
+}
+
+func callerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+
+	return p.Addr.String()
+}
+
+func callerUserAgent(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if ua := md.Get("user-agent"); len(ua) > 0 {
+		return ua[0]
+	}
+
+	return ""
+}
+
+// redactedParams encodes the request as JSON with the secret fields redacted
+func redactedParams(req interface{}) string {
+	msg, ok := req.(protobuf.Message)
+	if !ok {
+		return ""
+	}
+
+	redacted := protobuf.Clone(msg)
+	redactMessage(redacted.ProtoReflect())
+
+	bz, err := protojson.Marshal(redacted)
+	if err != nil {
+		return ""
+	}
+
+	return string(bz)
+}
+
+func redactMessage(m protoreflect.Message) {
+	var secrets []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if _, ok := secretFields[fd.Name()]; ok && fd.Kind() == protoreflect.StringKind && !fd.IsList() {
+			secrets = append(secrets, fd)
+			return true
+		}
+		if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() {
+			redactMessage(v.Message())
+		}
+		return true
+	})
+
+	for _, fd := range secrets {
+		m.Set(fd, protoreflect.ValueOfString(redactedValue))
+	}
+}
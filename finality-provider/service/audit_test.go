@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+func TestRedactedParams(t *testing.T) {
+	req := &proto.RegisterFinalityProviderRequest{
+		BtcPk:         "btc-pk",
+		Passphrase:    "secret passphrase",
+		ApprovalToken: "secret token",
+	}
+
+	params := redactedParams(req)
+	require.Contains(t, params, "btc-pk")
+	require.Contains(t, params, redactedValue)
+	require.NotContains(t, params, "secret")
+
+	// the request handled is left intact
+	require.Equal(t, "secret passphrase", req.Passphrase)
+	require.Equal(t, "secret token", req.ApprovalToken)
+
+	require.Empty(t, redactedParams("not a proto message"))
+}
+
+func TestAuditInterceptor(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	interceptor := newAuditInterceptor(zap.New(core)).unary()
+	info := &grpc.UnaryServerInfo{FullMethod: "/proto.FinalityProviders/RegisterFinalityProvider"}
+	req := &proto.RegisterFinalityProviderRequest{BtcPk: "btc-pk", Passphrase: "secret passphrase"}
+
+	t.Run("a successful call is recorded with the redacted params", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "response", nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "response", resp)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		require.Equal(t, info.FullMethod, fields["method"])
+		require.Equal(t, "OK", fields["code"])
+		require.Equal(t, "unknown", fields["caller"])
+		require.NotContains(t, fields["params"], "secret")
+		require.NotContains(t, fields, "error")
+	})
+
+	t.Run("a failed call is recorded with its error", func(t *testing.T) {
+		handlerErr := errors.New("the call failed")
+		_, err := interceptor(context.Background(), req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, handlerErr
+		})
+		require.ErrorIs(t, err, handlerErr)
+
+		entries := logs.TakeAll()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		require.Equal(t, "Unknown", fields["code"])
+		require.Equal(t, handlerErr.Error(), fields["error"])
+	})
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// persistBufferedBlocks records the blocks received but not voted on before
+// the submissions were drained, so that they are voted on after the next start
+func (fp *FinalityProviderInstance) persistBufferedBlocks(blocks []*types.BlockInfo) {
+	if len(blocks) == 0 {
+		return
+	}
+
+	if err := fp.fpState.s.SaveBufferedBlocks(fp.GetBtcPk(), blocks); err != nil {
+		fp.logger.Error("failed to persist the blocks not voted on before the stop",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Int("count", len(blocks)), zap.Error(err))
+		return
+	}
+
+	fp.logger.Info("persisted the blocks not voted on before the stop",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("start_height", blocks[0].Height),
+		zap.Uint64("end_height", blocks[len(blocks)-1].Height),
+	)
+}
+
+// takeBlocksLeftInPoller returns the blocks left in the channel of the
+// stopped poller, in the ascending order of height
+func (fp *FinalityProviderInstance) takeBlocksLeftInPoller() []*types.BlockInfo {
+	fp.pollerMu.Lock()
+	defer fp.pollerMu.Unlock()
+
+	if fp.poller == nil {
+		return nil
+	}
+
+	var blocks []*types.BlockInfo
+	blockChan := fp.poller.GetBlockInfoChan()
+	for {
+		select {
+		case b := <-blockChan:
+			blocks = append(blocks, b)
+		default:
+			return blocks
+		}
+	}
+}
+
+// resumeBufferedBlocks votes on the blocks persisted on the previous stop.
+// Each block is queried again and skipped if it was rolled back in the
+// meantime. The blocks above the last processed height are processed as if
+// they were polled, and the lower ones, left by the newest-first order, are
+// backfilled
+func (fp *FinalityProviderInstance) resumeBufferedBlocks() {
+	blocks, err := fp.fpState.s.TakeBufferedBlocks(fp.GetBtcPk())
+	if err != nil {
+		fp.logger.Error("failed to get the blocks not voted on before the last stop",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Error(err))
+		return
+	}
+
+	for i, b := range blocks {
+		select {
+		case <-fp.draining:
+			fp.persistBufferedBlocks(blocks[i:])
+			return
+		default:
+		}
+
+		if fp.hasSignedBlock(b) {
+			continue
+		}
+		block, err := fp.cc.QueryBlock(b.Height)
+		if err != nil {
+			fp.logger.Debug("failed to query the block not voted on before the last stop",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", b.Height), zap.Error(err))
+			continue
+		}
+		if !bytes.Equal(block.Hash, b.Hash) {
+			fp.logger.Info("the block not voted on before the last stop is rolled back",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", b.Height))
+			continue
+		}
+
+		if b.Height > fp.GetLastProcessedHeight() {
+			fp.processBlock(block)
+			continue
+		}
+		fp.backfillBlock(block)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestDrainPersistsBufferedBlocks(t *testing.T) {
+	db, err := config.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pollerCfg := config.DefaultChainPollerConfig()
+	fp := &FinalityProviderInstance{
+		fpState:  NewFpState(&store.StoredFinalityProvider{BtcPk: sk.PubKey()}, s),
+		logger:   zap.NewNop(),
+		poller:   NewChainPoller(zap.NewNop(), &pollerCfg, nil, metrics.NewFpMetrics()),
+		draining: make(chan struct{}),
+	}
+
+	// the blocks left in the poller once the submissions are drained
+	var buffered []*types.BlockInfo
+	for h := uint64(10); h < 13; h++ {
+		b := &types.BlockInfo{Height: h, Hash: []byte{byte(h)}}
+		buffered = append(buffered, b)
+		fp.poller.blockInfoChan <- b
+	}
+
+	fp.persistBufferedBlocks(fp.takeBlocksLeftInPoller())
+	require.Empty(t, fp.poller.GetBlockInfoChan())
+
+	// the blocks are persisted again if the instance is stopped before they
+	// are resumed
+	close(fp.draining)
+	fp.resumeBufferedBlocks()
+
+	blocks, err := s.TakeBufferedBlocks(sk.PubKey())
+	require.NoError(t, err)
+	require.Equal(t, buffered, blocks)
+
+	blocks, err = s.TakeBufferedBlocks(sk.PubKey())
+	require.NoError(t, err)
+	require.Empty(t, blocks)
+}
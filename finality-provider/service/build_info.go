@@ -0,0 +1,37 @@
+package service
+
+import (
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/version"
+)
+
+// buildInfo returns the version and the build of the daemon along with the
+// features and the chains of the config, which are served by GetInfo and
+// logged on startup so that they can be attached to the support tickets
+func buildInfo(cfg *fpcfg.Config) *proto.GetInfoResponse {
+	return &proto.GetInfoResponse{
+		Version:    version.Version(),
+		CommitHash: version.CommitHash,
+		BuildDate:  version.BuildDate,
+		GoVersion:  version.GoVersion,
+		BuildTags:  version.Tags(),
+		Features:   cfg.EnabledFeatures(),
+		ChainIds:   cfg.AllChainIDs(),
+	}
+}
+
+// logBuildInfo logs the build info as the startup banner of the daemon
+func logBuildInfo(logger *zap.Logger, info *proto.GetInfoResponse) {
+	logger.Info("Starting the finality provider daemon",
+		zap.String("version", info.Version),
+		zap.String("commit_hash", info.CommitHash),
+		zap.String("build_date", info.BuildDate),
+		zap.String("go_version", info.GoVersion),
+		zap.Strings("build_tags", info.BuildTags),
+		zap.Strings("features", info.Features),
+		zap.Strings("chain_ids", info.ChainIds),
+	)
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/version"
+)
+
+func TestBuildInfo(t *testing.T) {
+	cfg := &fpcfg.Config{
+		BabylonConfig: &fpcfg.BBNConfig{ChainID: "chain-b"},
+		Chains:        map[string]*fpcfg.ChainConfig{"chain-a": {}, "chain-b": {}},
+		ObserverMode:  true,
+		EnableTestRPC: true,
+	}
+
+	info := buildInfo(cfg)
+	require.Equal(t, version.Version(), info.Version)
+	require.Equal(t, version.CommitHash, info.CommitHash)
+	require.Equal(t, version.BuildDate, info.BuildDate)
+	require.Equal(t, []string{"observer-mode", "test-rpc"}, info.Features)
+	// the chain of the babylon config is listed once along with the others
+	require.Equal(t, []string{"chain-a", "chain-b"}, info.ChainIds)
+
+	core, logs := observer.New(zap.InfoLevel)
+	logBuildInfo(zap.New(core), info)
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	require.Equal(t, info.Version, fields["version"])
+	require.Equal(t, []interface{}{"chain-a", "chain-b"}, fields["chain_ids"])
+
+	// a config without optional features reports none
+	require.Empty(t, buildInfo(&fpcfg.Config{}).Features)
+}
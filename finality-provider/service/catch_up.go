@@ -0,0 +1,58 @@
+package service
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// updateCatchUpMode switches the instance to catch-up mode if the last
+// processed height is behind the tip by the catch-up gap, and back to the
+// normal mode once it is within the fast sync gap. In catch-up mode, the
+// newest blocks are voted first and the fast sync fetches and signs the
+// blocks in larger batches
+func (fp *FinalityProviderInstance) updateCatchUpMode(tipBlock *types.BlockInfo) {
+	if fp.cfg.CatchUpGap == 0 {
+		return
+	}
+
+	var blocksBehind uint64
+	if lastProcessedHeight := fp.GetLastProcessedHeight(); tipBlock.Height > lastProcessedHeight {
+		blocksBehind = tipBlock.Height - lastProcessedHeight
+	}
+
+	switch {
+	case !fp.catchingUp.Load() && blocksBehind >= fp.cfg.CatchUpGap:
+		fp.catchingUp.Store(true)
+		fp.logger.Warn("the finality-provider is far behind the tip, switching to catch-up mode",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("tip_height", tipBlock.Height),
+			zap.Uint64("blocks_behind", blocksBehind),
+		)
+		fp.emitEvent(&hooks.Event{Type: hooks.EventCatchUpStarted, Height: tipBlock.Height, BlocksBehind: blocksBehind})
+	case fp.catchingUp.Load() && blocksBehind < fp.cfg.FastSyncGap:
+		fp.catchingUp.Store(false)
+		fp.logger.Info("the finality-provider is back in sync, leaving catch-up mode",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("tip_height", tipBlock.Height),
+			zap.Uint64("blocks_behind", blocksBehind),
+		)
+		fp.emitEvent(&hooks.Event{Type: hooks.EventCatchUpFinished, Height: tipBlock.Height, BlocksBehind: blocksBehind})
+	}
+}
+
+// fastSyncLimit returns the maximum number of blocks to catch up for each
+// round of fast sync, which is larger in catch-up mode
+func (fp *FinalityProviderInstance) fastSyncLimit() uint64 {
+	if fp.catchingUp.Load() && fp.cfg.CatchUpBatchSize > fp.cfg.FastSyncLimit {
+		return fp.cfg.CatchUpBatchSize
+	}
+
+	return fp.cfg.FastSyncLimit
+}
+
+// IsCatchingUp returns true if the instance is in catch-up mode
+func (fp *FinalityProviderInstance) IsCatchingUp() bool {
+	return fp.catchingUp.Load()
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// chanHook passes the events it handles to a channel
+type chanHook chan *hooks.Event
+
+func (h chanHook) Name() string {
+	return "chan"
+}
+
+func (h chanHook) OnEvent(e *hooks.Event) error {
+	h <- e
+	return nil
+}
+
+func TestUpdateCatchUpMode(t *testing.T) {
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	events := make(chanHook, 10)
+	dispatcher := hooks.NewDispatcher(zap.NewNop(), events)
+	t.Cleanup(dispatcher.Stop)
+
+	lastProcessedHeight := uint64(100)
+	fp := &FinalityProviderInstance{
+		cfg: &fpcfg.Config{
+			FastSyncLimit:    10,
+			FastSyncGap:      3,
+			CatchUpGap:       50,
+			CatchUpBatchSize: 100,
+		},
+		fpState:    NewFpState(&store.StoredFinalityProvider{BtcPk: sk.PubKey(), LastProcessedHeight: lastProcessedHeight}, nil),
+		catchingUp: atomic.NewBool(false),
+		hooks:      dispatcher,
+		logger:     zap.NewNop(),
+	}
+	expectEvent := func(eventType hooks.EventType, tipHeight uint64) {
+		select {
+		case e := <-events:
+			require.Equal(t, eventType, e.Type)
+			require.Equal(t, tipHeight, e.Height)
+			require.Equal(t, tipHeight-lastProcessedHeight, e.BlocksBehind)
+			require.Equal(t, fp.GetBtcPkHex(), e.FpBtcPk)
+		case <-time.After(time.Second):
+			t.Fatalf("the event %s is not emitted", eventType)
+		}
+	}
+
+	// the instance is not far enough behind the tip
+	fp.updateCatchUpMode(&types.BlockInfo{Height: lastProcessedHeight + 49})
+	require.False(t, fp.IsCatchingUp())
+	require.Equal(t, uint64(10), fp.fastSyncLimit())
+
+	fp.updateCatchUpMode(&types.BlockInfo{Height: lastProcessedHeight + 50})
+	require.True(t, fp.IsCatchingUp())
+	require.Equal(t, uint64(100), fp.fastSyncLimit())
+	expectEvent(hooks.EventCatchUpStarted, lastProcessedHeight+50)
+
+	// catch-up mode lasts until the instance is within the fast sync gap
+	fp.updateCatchUpMode(&types.BlockInfo{Height: lastProcessedHeight + 3})
+	require.True(t, fp.IsCatchingUp())
+
+	fp.updateCatchUpMode(&types.BlockInfo{Height: lastProcessedHeight + 2})
+	require.False(t, fp.IsCatchingUp())
+	require.Equal(t, uint64(10), fp.fastSyncLimit())
+	expectEvent(hooks.EventCatchUpFinished, lastProcessedHeight+2)
+
+	// catch-up mode is never entered if it is disabled
+	fp.cfg.CatchUpGap = 0
+	fp.updateCatchUpMode(&types.BlockInfo{Height: lastProcessedHeight + 1000})
+	require.False(t, fp.IsCatchingUp())
+	require.Empty(t, events)
+}
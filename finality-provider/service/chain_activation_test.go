@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestIsBelowActivation(t *testing.T) {
+	activatedHeight := uint64(10)
+
+	newInstance := func(t *testing.T) (*FinalityProviderInstance, *mocks.MockClientController) {
+		db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, db.Close())
+		})
+		s, err := store.NewFinalityProviderStore(db)
+		require.NoError(t, err)
+		fpPk := createTestFinalityProvider(t, s, "chain-test")
+		storedFp, err := s.GetFinalityProvider(fpPk.MustToBTCPK())
+		require.NoError(t, err)
+
+		cc := mocks.NewMockClientController(gomock.NewController(t))
+
+		return &FinalityProviderInstance{
+			cfg:         &fpcfg.Config{},
+			fpState:     NewFpState(storedFp, s),
+			cc:          cc,
+			chainParams: newChainParamsCache(cc, 0, zap.NewNop()),
+			metrics:     metrics.NewFpMetrics(),
+			logger:      zap.NewNop(),
+			ctx:         context.Background(),
+		}, cc
+	}
+
+	t.Run("the activation height is only queried until the chain is activated", func(t *testing.T) {
+		fp, cc := newInstance(t)
+		gomock.InOrder(
+			cc.EXPECT().QueryActivatedHeight().Return(uint64(0), nil).Times(1),
+			cc.EXPECT().QueryActivatedHeight().Return(activatedHeight, nil).Times(1),
+		)
+
+		require.False(t, fp.isBelowActivation(&types.BlockInfo{Height: activatedHeight - 1}))
+		require.True(t, fp.isBelowActivation(&types.BlockInfo{Height: activatedHeight - 1}))
+		require.False(t, fp.isBelowActivation(&types.BlockInfo{Height: activatedHeight}))
+	})
+
+	t.Run("the block is processed as usual if the query fails", func(t *testing.T) {
+		fp, cc := newInstance(t)
+		cc.EXPECT().QueryActivatedHeight().Return(uint64(0), errors.New("the query failed")).Times(1)
+
+		require.False(t, fp.isBelowActivation(&types.BlockInfo{Height: 1}))
+	})
+
+	t.Run("a block below the activation height is processed without being voted", func(t *testing.T) {
+		fp, cc := newInstance(t)
+		cc.EXPECT().QueryActivatedHeight().Return(activatedHeight, nil).Times(1)
+
+		// the voting power is never queried, as no other call is expected
+		require.True(t, fp.processBlock(&types.BlockInfo{Height: activatedHeight - 1, Hash: []byte("hash")}))
+		require.Equal(t, activatedHeight-1, fp.GetLastProcessedHeight())
+		require.Zero(t, fp.GetLastVotedHeight())
+	})
+}
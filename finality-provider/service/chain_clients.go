@@ -0,0 +1,154 @@
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/faultinject"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// chainClient is the client controller of a chain configured by its own
+// section, along with the parameters of the chain cached through it
+type chainClient struct {
+	cc          clientcontroller.ClientController
+	chainParams *chainParamsCache
+}
+
+// newChainController creates the client controller of the chain of the given
+// babylon config, wrapped by the relayer and the fault injection if enabled
+func newChainController(cfg *fpcfg.Config, bbnCfg *fpcfg.BBNConfig, logger *zap.Logger) (clientcontroller.ClientController, error) {
+	cc, err := clientcontroller.NewClientController(cfg.ChainName, bbnCfg, &cfg.BTCNetParams, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rpc client for the consumer chain %s: %v", bbnCfg.ChainID, err)
+	}
+
+	if cfg.SubmissionMode == fpcfg.SubmissionModeRelayer {
+		cc, err = clientcontroller.NewRelayerController(cc, cfg.RelayerURL, cfg.RelayerTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the relayer client: %w", err)
+		}
+	}
+
+	if faultinject.Enabled {
+		cc = clientcontroller.NewFaultInjectingController(cc)
+	}
+
+	return cc, nil
+}
+
+// addChainClient makes the finality providers of the given chain go through
+// the given client controller instead of the default one, which must be
+// called before any instance is started
+func (fpm *FinalityProviderManager) addChainClient(chainID string, cc clientcontroller.ClientController) {
+	fpm.chainClients[chainID] = &chainClient{
+		cc:          cc,
+		chainParams: newChainParamsCache(cc, fpm.config.ParamsRefreshInterval, fpm.logger),
+	}
+}
+
+// ccOf returns the client controller of the given chain
+func (fpm *FinalityProviderManager) ccOf(chainID string) clientcontroller.ClientController {
+	if c, ok := fpm.chainClients[chainID]; ok {
+		return c.cc
+	}
+
+	return fpm.cc
+}
+
+// servesChain returns whether the given chain is the default one or is
+// configured by its own section
+func (fpm *FinalityProviderManager) servesChain(chainID string) bool {
+	if chainID == fpm.config.BabylonConfig.ChainID {
+		return true
+	}
+	_, ok := fpm.chainClients[chainID]
+
+	return ok
+}
+
+// resolveChainID returns the given chain ID, or the ID of the default chain
+// if it is empty, failing if the daemon has no client of the chain
+func (fpm *FinalityProviderManager) resolveChainID(chainID string) (string, error) {
+	if chainID == "" {
+		return fpm.config.BabylonConfig.ChainID, nil
+	}
+	if !fpm.servesChain(chainID) {
+		return "", fmt.Errorf("the chain %s is not configured", chainID)
+	}
+
+	return chainID, nil
+}
+
+// chainIDs returns the ID of the default chain followed by the IDs of the
+// chains configured by their own sections
+func (fpm *FinalityProviderManager) chainIDs() []string {
+	chainIDs := []string{fpm.config.BabylonConfig.ChainID}
+	for _, chainID := range fpm.config.ChainIDs() {
+		if chainID != fpm.config.BabylonConfig.ChainID {
+			chainIDs = append(chainIDs, chainID)
+		}
+	}
+
+	return chainIDs
+}
+
+// chainTips queries the tip of each chain at most once, so that the finality
+// providers of the same chain are checked against the same tip
+type chainTips struct {
+	fpm  *FinalityProviderManager
+	tips map[string]*types.BlockInfo
+	errs map[string]error
+}
+
+func (fpm *FinalityProviderManager) newChainTips() *chainTips {
+	return &chainTips{
+		fpm:  fpm,
+		tips: make(map[string]*types.BlockInfo),
+		errs: make(map[string]error),
+	}
+}
+
+// of returns the tip of the given chain, or the error of its first query
+func (t *chainTips) of(chainID string) (*types.BlockInfo, error) {
+	if err, ok := t.errs[chainID]; ok {
+		return nil, err
+	}
+	if tip, ok := t.tips[chainID]; ok {
+		return tip, nil
+	}
+
+	tip, err := t.fpm.ccOf(chainID).QueryBestBlock()
+	if err != nil {
+		err = fmt.Errorf("failed to query the tip of chain %s: %w", chainID, err)
+		t.errs[chainID] = err
+		return nil, err
+	}
+	t.tips[chainID] = tip
+
+	return tip, nil
+}
+
+// chainParamsOf returns the cached parameters of the given chain
+func (fpm *FinalityProviderManager) chainParamsOf(chainID string) *chainParamsCache {
+	if c, ok := fpm.chainClients[chainID]; ok {
+		return c.chainParams
+	}
+
+	return fpm.chainParams
+}
+
+// closeChainClients closes the client controllers of the chains configured by
+// their own sections
+func (fpm *FinalityProviderManager) closeChainClients() error {
+	for chainID, c := range fpm.chainClients {
+		if err := c.cc.Close(); err != nil {
+			return fmt.Errorf("failed to close the client of chain %s: %w", chainID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// chainHaltChecksPerThreshold is the number of times the tip of each chain is
+// checked within the halt threshold
+const chainHaltChecksPerThreshold = 5
+
+// chainHaltDetector tracks the tip of a consumer chain to detect that it stops
+// producing blocks for longer than the threshold. The time is measured by the
+// local clock since the tip last advanced, rather than by the timestamp of the
+// tip, so that a skewed clock is not taken for a halt
+type chainHaltDetector struct {
+	chainID   string
+	cc        clientcontroller.ClientController
+	threshold time.Duration
+
+	mu          sync.Mutex
+	tipHeight   uint64
+	lastAdvance time.Time
+
+	halted *atomic.Bool
+}
+
+func newChainHaltDetector(chainID string, cc clientcontroller.ClientController, threshold time.Duration) *chainHaltDetector {
+	return &chainHaltDetector{
+		chainID:   chainID,
+		cc:        cc,
+		threshold: threshold,
+		halted:    atomic.NewBool(false),
+	}
+}
+
+// observe records the tip height observed at the given time, and returns
+// whether the chain is halted and whether it has just halted or resumed
+func (d *chainHaltDetector) observe(tipHeight uint64, now time.Time) (halted bool, changed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.lastAdvance.IsZero() || tipHeight > d.tipHeight {
+		d.tipHeight = tipHeight
+		d.lastAdvance = now
+	}
+
+	halted = now.Sub(d.lastAdvance) >= d.threshold
+	changed = d.halted.Swap(halted) != halted
+
+	return halted, changed
+}
+
+// isHalted returns true if the chain is halted, which is never the case if
+// the halt is not detected
+func (d *chainHaltDetector) isHalted() bool {
+	if d == nil {
+		return false
+	}
+
+	return d.halted.Load()
+}
+
+// chainHaltOf returns the halt detector shared by the instances of the given
+// chain, or nil if the detection is disabled, which must be called with mu
+// held
+func (fpm *FinalityProviderManager) chainHaltOf(chainID string) *chainHaltDetector {
+	if fpm.config.ChainHaltThreshold == 0 {
+		return nil
+	}
+
+	d, ok := fpm.chainHalts[chainID]
+	if !ok {
+		d = newChainHaltDetector(chainID, fpm.ccOf(chainID), fpm.config.ChainHaltThreshold)
+		fpm.chainHalts[chainID] = d
+	}
+
+	return d
+}
+
+// isChainHalted returns true if the given chain is detected as halted
+func (fpm *FinalityProviderManager) isChainHalted(chainID string) bool {
+	fpm.mu.Lock()
+	defer fpm.mu.Unlock()
+
+	// the detector of a chain without running instances is nil
+	return fpm.chainHalts[chainID].isHalted()
+}
+
+// monitorChainHalt periodically checks whether the chains of the running
+// instances are halted, switching the instances of a halted chain to STANDBY
+// and back once the chain produces blocks again
+func (fpm *FinalityProviderManager) monitorChainHalt() {
+	defer fpm.wg.Done()
+
+	if fpm.config.ChainHaltThreshold == 0 {
+		fpm.logger.Info("the chain halt detection is disabled")
+		return
+	}
+
+	checkTicker := time.NewTicker(fpm.config.ChainHaltThreshold / chainHaltChecksPerThreshold)
+	defer checkTicker.Stop()
+
+	for {
+		select {
+		case <-checkTicker.C:
+			fpm.checkChainHalts()
+		case <-fpm.quit:
+			return
+		}
+	}
+}
+
+func (fpm *FinalityProviderManager) checkChainHalts() {
+	fpm.mu.Lock()
+	detectors := make([]*chainHaltDetector, 0, len(fpm.chainHalts))
+	for _, d := range fpm.chainHalts {
+		detectors = append(detectors, d)
+	}
+	fpm.mu.Unlock()
+
+	for _, d := range detectors {
+		tip, err := d.cc.QueryBestBlock()
+		if err != nil {
+			// an unreachable node does not tell whether the chain is halted
+			fpm.logger.Debug("failed to query the tip to check for a chain halt",
+				zap.String("chain_id", d.chainID), zap.Error(err))
+			continue
+		}
+
+		halted, changed := d.observe(tip.Height, time.Now())
+		if changed && halted {
+			fpm.logger.Warn("the consumer chain has stopped producing blocks, switching the finality providers to standby",
+				zap.String("chain_id", d.chainID),
+				zap.Uint64("tip_height", tip.Height),
+				zap.Duration("threshold", d.threshold))
+		} else if changed {
+			fpm.logger.Info("the consumer chain is producing blocks again, resuming the finality providers",
+				zap.String("chain_id", d.chainID),
+				zap.Uint64("tip_height", tip.Height))
+		}
+
+		// the instances are checked on every tick, so that the ones started
+		// during the halt, or left in standby by a previous run, are switched
+		// as well
+		for _, fpi := range fpm.ListFinalityProviderInstances() {
+			if string(fpi.GetChainID()) != d.chainID {
+				continue
+			}
+			if halted {
+				fpi.enterStandby()
+			} else {
+				fpi.exitStandby()
+			}
+			if !changed {
+				continue
+			}
+			eventType := hooks.EventChainResumed
+			if halted {
+				eventType = hooks.EventChainHalted
+			}
+			fpi.emitEvent(&hooks.Event{Type: eventType, Height: tip.Height})
+		}
+	}
+}
+
+// isChainHalted returns true if the chain of the finality provider is halted,
+// during which nothing is submitted and the retries are suspended, so that
+// they neither run out nor count towards safe mode
+func (fp *FinalityProviderInstance) isChainHalted() bool {
+	return fp.chainHalt.isHalted()
+}
+
+// waitChainResumed waits for an interval of the submission retries before the
+// halted chain is checked again, or returns ErrFinalityProviderShutDown if
+// the instance is stopped
+func (fp *FinalityProviderInstance) waitChainResumed() error {
+	select {
+	case <-time.After(fp.cfg.SubmissionRetryInterval):
+		return nil
+	case <-fp.quit:
+		return ErrFinalityProviderShutDown
+	}
+}
+
+// enterStandby switches the finality provider to STANDBY, remembering its
+// status to be restored once the chain resumes
+func (fp *FinalityProviderInstance) enterStandby() {
+	status := fp.GetStatus()
+	if status == proto.FinalityProviderStatus_STANDBY {
+		return
+	}
+
+	fp.statusBeforeStandby.Store(int32(status))
+	fp.MustSetStatus(proto.FinalityProviderStatus_STANDBY)
+}
+
+// exitStandby restores the status of the finality provider before STANDBY,
+// or REGISTERED if the standby is left by a previous run, which is then
+// updated by the status update
+func (fp *FinalityProviderInstance) exitStandby() {
+	if fp.GetStatus() != proto.FinalityProviderStatus_STANDBY {
+		return
+	}
+
+	fp.MustSetStatus(proto.FinalityProviderStatus(fp.statusBeforeStandby.Load()))
+}
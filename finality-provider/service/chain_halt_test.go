@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainHaltDetector(t *testing.T) {
+	require.False(t, (*chainHaltDetector)(nil).isHalted())
+
+	d := newChainHaltDetector("chain-test", nil, time.Minute)
+	start := time.Now()
+
+	halted, changed := d.observe(10, start)
+	require.False(t, halted)
+	require.False(t, changed)
+
+	// the tip does not advance within the threshold
+	halted, changed = d.observe(10, start.Add(59*time.Second))
+	require.False(t, halted)
+	require.False(t, changed)
+
+	halted, changed = d.observe(10, start.Add(time.Minute))
+	require.True(t, halted)
+	require.True(t, changed)
+	require.True(t, d.isHalted())
+
+	// the halt is only reported once
+	halted, changed = d.observe(10, start.Add(2*time.Minute))
+	require.True(t, halted)
+	require.False(t, changed)
+
+	halted, changed = d.observe(11, start.Add(3*time.Minute))
+	require.False(t, halted)
+	require.True(t, changed)
+	require.False(t, d.isHalted())
+}
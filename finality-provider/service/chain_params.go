@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// chainParamsCache caches the on-chain parameters, which rarely change, so
+// that operations can be validated locally without querying the chain each time
+type chainParamsCache struct {
+	cc              clientcontroller.ClientController
+	refreshInterval time.Duration
+	logger          *zap.Logger
+
+	mu        sync.Mutex
+	params    *types.ChainParams
+	fetchedAt time.Time
+	// activated is the finality activation height, which is 0 until the
+	// chain is activated and never changes after that
+	activated uint64
+}
+
+func newChainParamsCache(cc clientcontroller.ClientController, refreshInterval time.Duration, logger *zap.Logger) *chainParamsCache {
+	return &chainParamsCache{
+		cc:              cc,
+		refreshInterval: refreshInterval,
+		logger:          logger,
+	}
+}
+
+// get returns the cached parameters, which are refreshed if they are older
+// than the refresh interval. The stale parameters are returned if the refresh
+// fails, as they are more likely to be right than nothing
+func (c *chainParamsCache) get() (*types.ChainParams, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.params != nil && time.Since(c.fetchedAt) < c.refreshInterval {
+		return c.params, nil
+	}
+
+	params, err := c.cc.QueryChainParams()
+	if err != nil {
+		if c.params != nil {
+			c.logger.Warn("failed to refresh the chain parameters, using the cached ones",
+				zap.Time("fetched_at", c.fetchedAt), zap.Error(err))
+			return c.params, nil
+		}
+		return nil, fmt.Errorf("failed to query the chain parameters: %w", err)
+	}
+
+	c.params = params
+	c.fetchedAt = time.Now()
+
+	return params, nil
+}
+
+// checkCommission returns an error if the commission is below the minimum
+// commission rate of the chain
+func (c *chainParamsCache) checkCommission(commission *sdkmath.LegacyDec) error {
+	params, err := c.get()
+	if err != nil {
+		return err
+	}
+
+	if !params.MinCommissionRate.IsNil() && commission.LT(params.MinCommissionRate) {
+		return fmt.Errorf("the commission %s is lower than the minimum commission rate %s of the chain",
+			commission.String(), params.MinCommissionRate.String())
+	}
+
+	return nil
+}
+
+// checkNumPubRand returns an error if the number of public randomness in a
+// commit is below the minimum of the chain
+func (c *chainParamsCache) checkNumPubRand(numPubRand uint64) error {
+	params, err := c.get()
+	if err != nil {
+		return err
+	}
+
+	if numPubRand < params.MinPubRand {
+		return fmt.Errorf("the number of public randomness %d is lower than the minimum %d of the chain",
+			numPubRand, params.MinPubRand)
+	}
+
+	return nil
+}
+
+// activatedHeight returns the finality activation height of the chain, below
+// which finality signatures are rejected. It is only queried until the chain
+// is activated
+func (c *chainParamsCache) activatedHeight() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.activated > 0 {
+		return c.activated, nil
+	}
+
+	activated, err := c.cc.QueryActivatedHeight()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query the activated height: %w", err)
+	}
+	c.activated = activated
+
+	return activated, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"fmt"
 	"sync"
 	"time"
@@ -15,18 +16,18 @@ import (
 	"github.com/babylonchain/finality-provider/types"
 )
 
-var (
-	// TODO: Maybe configurable?
-	RtyAttNum = uint(5)
-	RtyAtt    = retry.Attempts(RtyAttNum)
-	RtyDel    = retry.Delay(time.Millisecond * 400)
-	RtyErr    = retry.LastErrorOnly(true)
-)
+var RtyErr = retry.LastErrorOnly(true)
 
-const (
-	// TODO: Maybe configurable?
-	maxFailedCycles = 20
-)
+// queryRetryOpts returns the options of retrying a query of the consumer
+// chain, which calls onRetry after each failed attempt
+func queryRetryOpts(pollerCfg *cfg.ChainPollerConfig, onRetry retry.OnRetryFunc) []retry.Option {
+	return []retry.Option{
+		retry.Attempts(pollerCfg.QueryRetryAttempts),
+		retry.Delay(pollerCfg.QueryRetryDelay),
+		RtyErr,
+		retry.OnRetry(onRetry),
+	}
+}
 
 type skipHeightRequest struct {
 	height uint64
@@ -49,6 +50,25 @@ type ChainPoller struct {
 	skipHeightChan chan *skipHeightRequest
 	nextHeight     uint64
 	logger         *zap.Logger
+
+	// recentHashes keeps the hashes of the recently polled blocks
+	// keyed by height, which are used to detect reorgs
+	recentHashes map[uint64][]byte
+	// pendingForkHeight is the fork height of a reorg detected since the last
+	// polled block, which is handed to the consumer along with the next one
+	pendingForkHeight *uint64
+
+	reorgMu sync.Mutex
+	// reorgBlock is the first block polled after a reorg, along with which the
+	// consumer rewinds to forkHeight
+	reorgBlock *types.BlockInfo
+	forkHeight uint64
+}
+
+// freshBlockQuerier is implemented by the block sources caching the blocks,
+// whose cache is bypassed by the reorg checks
+type freshBlockQuerier interface {
+	queryFreshBlock(height uint64) (*types.BlockInfo, error)
 }
 
 func NewChainPoller(
@@ -65,6 +85,7 @@ func NewChainPoller(
 		metrics:        metrics,
 		blockInfoChan:  make(chan *types.BlockInfo, cfg.BufferSize),
 		skipHeightChan: make(chan *skipHeightRequest),
+		recentHashes:   make(map[uint64][]byte),
 		quit:           make(chan struct{}),
 	}
 }
@@ -134,39 +155,53 @@ func (cp *ChainPoller) latestBlockWithRetry() (*types.BlockInfo, error) {
 			return err
 		}
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, queryRetryOpts(cp.cfg, func(n uint, err error) {
 		cp.logger.Debug(
 			"failed to query the consumer chain for the latest block",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", cp.cfg.QueryRetryAttempts),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return nil, err
 	}
 	return latestBlock, nil
 }
 
 func (cp *ChainPoller) blockWithRetry(height uint64) (*types.BlockInfo, error) {
+	return cp.queryBlockWithRetry(height, cp.cc.QueryBlock)
+}
+
+// freshBlockWithRetry queries the block at the given height from the chain
+// even if the block source caches it, so that a replaced block is seen
+func (cp *ChainPoller) freshBlockWithRetry(height uint64) (*types.BlockInfo, error) {
+	if src, ok := cp.cc.(freshBlockQuerier); ok {
+		return cp.queryBlockWithRetry(height, src.queryFreshBlock)
+	}
+
+	return cp.blockWithRetry(height)
+}
+
+func (cp *ChainPoller) queryBlockWithRetry(height uint64, query func(uint64) (*types.BlockInfo, error)) (*types.BlockInfo, error) {
 	var (
 		block *types.BlockInfo
 		err   error
 	)
 	if err := retry.Do(func() error {
-		block, err = cp.cc.QueryBlock(height)
+		block, err = query(height)
 		if err != nil {
 			return err
 		}
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, queryRetryOpts(cp.cfg, func(n uint, err error) {
 		cp.logger.Debug(
 			"failed to query the consumer chain for the latest block",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", cp.cfg.QueryRetryAttempts),
 			zap.Uint64("height", height),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return nil, err
 	}
 
@@ -233,6 +268,10 @@ func (cp *ChainPoller) pollChain() {
 	var failedCycles uint32
 
 	for {
+		if err := cp.checkReorg(); err != nil {
+			cp.logger.Debug("failed to check reorg of the consumer chain", zap.Error(err))
+		}
+
 		// TODO: Handlig of request cancellation, as otherwise shutdown will be blocked
 		// until request is finished
 		blockToRetrieve := cp.nextHeight
@@ -250,18 +289,19 @@ func (cp *ChainPoller) pollChain() {
 			// notification about data
 			cp.nextHeight = blockToRetrieve + 1
 			failedCycles = 0
+			cp.rememberBlock(block)
+			cp.markReorgBlock(block)
 			cp.metrics.RecordLastPolledHeight(block.Height)
 
 			cp.logger.Info("the poller retrieved the block from the consumer chain",
 				zap.Uint64("height", block.Height))
 
-			// push the data to the channel
-			// Note: if the consumer is too slow -- the buffer is full
-			// the channel will block, and we will stop retrieving data from the node
-			cp.blockInfoChan <- block
+			if !cp.pushBlock(block) {
+				return
+			}
 		}
 
-		if failedCycles > maxFailedCycles {
+		if failedCycles > cp.cfg.MaxFailedCycles {
 			cp.logger.Fatal("the poller has reached the max failed cycles, exiting")
 		}
 
@@ -269,33 +309,212 @@ func (cp *ChainPoller) pollChain() {
 		case <-time.After(cp.cfg.PollInterval):
 
 		case req := <-cp.skipHeightChan:
-			// no need to skip heights if the target height is not higher
-			// than the next height to retrieve
-			targetHeight := req.height
-			if targetHeight <= cp.nextHeight {
-				resp := &skipHeightResponse{
-					err: fmt.Errorf(
-						"the target height %d is not higher than the next height %d to retrieve",
-						targetHeight, cp.nextHeight)}
-				req.resp <- resp
-				continue
+			cp.skipHeight(req)
+
+		case <-cp.quit:
+			return
+		}
+	}
+}
+
+// pushBlock hands the polled block to the consumer. If the buffer is full as
+// the submission stalls, the poller pauses until there is room instead of
+// polling more blocks, while still serving the requests to skip heights. It
+// returns false if the poller is stopped in the meantime
+func (cp *ChainPoller) pushBlock(block *types.BlockInfo) bool {
+	select {
+	case cp.blockInfoChan <- block:
+		return true
+	default:
+	}
+
+	cp.logger.Warn("the buffer of polled blocks is full, pausing the poller until they are processed",
+		zap.Uint64("height", block.Height), zap.Int("buffer_size", cap(cp.blockInfoChan)))
+	pausedAt := time.Now()
+	defer func() {
+		paused := time.Since(pausedAt)
+		cp.metrics.RecordPollerPause(paused)
+		cp.logger.Info("the poller is resumed", zap.Duration("paused", paused))
+	}()
+
+	for {
+		select {
+		case cp.blockInfoChan <- block:
+			return true
+		case req := <-cp.skipHeightChan:
+			cp.skipHeight(req)
+			// the block is dropped if it is skipped
+			if block.Height+1 < cp.nextHeight {
+				return true
 			}
+		case <-cp.quit:
+			return false
+		}
+	}
+}
 
-			// drain blocks that can be skipped from blockInfoChan
-			cp.clearChanBufferUpToHeight(targetHeight)
+// skipHeight moves the next height to retrieve to the requested one, and
+// drops the skipped blocks from the buffer
+func (cp *ChainPoller) skipHeight(req *skipHeightRequest) {
+	// no need to skip heights if the target height is not higher
+	// than the next height to retrieve
+	targetHeight := req.height
+	if targetHeight <= cp.nextHeight {
+		resp := &skipHeightResponse{
+			err: fmt.Errorf(
+				"the target height %d is not higher than the next height %d to retrieve",
+				targetHeight, cp.nextHeight)}
+		req.resp <- resp
+		return
+	}
 
-			// set the next height to the skip height
-			cp.nextHeight = targetHeight
+	// drain blocks that can be skipped from blockInfoChan
+	cp.clearChanBufferUpToHeight(targetHeight)
 
-			cp.logger.Debug("the poller has skipped height(s)",
-				zap.Uint64("next_height", req.height))
+	// set the next height to the skip height
+	cp.nextHeight = targetHeight
 
-			req.resp <- &skipHeightResponse{}
+	cp.logger.Debug("the poller has skipped height(s)",
+		zap.Uint64("next_height", req.height))
 
-		case <-cp.quit:
-			return
+	req.resp <- &skipHeightResponse{}
+}
+
+// rememberBlock keeps the hash of the polled block and forgets the ones
+// beyond the reorg check depth
+func (cp *ChainPoller) rememberBlock(b *types.BlockInfo) {
+	if cp.cfg.ReorgCheckDepth == 0 {
+		return
+	}
+
+	cp.recentHashes[b.Height] = b.Hash
+	for h := range cp.recentHashes {
+		if h+cp.cfg.ReorgCheckDepth <= b.Height {
+			delete(cp.recentHashes, h)
+		}
+	}
+}
+
+// checkReorg compares the hash of the last polled block with the one on the
+// chain, bypassing the cache of the block source. Upon a mismatch, it walks
+// back through the recently polled blocks to find the fork point, rolls the
+// cursor back to the block after it, and drops the stale blocks from the
+// buffer so that the affected heights are re-evaluated. The consumer rewinds
+// to the fork height along with the next polled block. Finality providers
+// never sign a block conflicting with one they have signed at the same
+// height, which is guarded by the signed block records in the store
+func (cp *ChainPoller) checkReorg() error {
+	if cp.cfg.ReorgCheckDepth == 0 || cp.nextHeight == 0 {
+		return nil
+	}
+
+	lastHeight := cp.nextHeight - 1
+	knownHash, ok := cp.recentHashes[lastHeight]
+	if !ok {
+		return nil
+	}
+
+	lastBlock, err := cp.freshBlockWithRetry(lastHeight)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(lastBlock.Hash, knownHash) {
+		return nil
+	}
+
+	// find the highest height at which the polled block is still on the chain
+	forkHeight := lastHeight
+	for forkHeight > 0 {
+		forkHeight--
+		hash, ok := cp.recentHashes[forkHeight]
+		if !ok {
+			// the fork point is beyond the check depth, so we re-evaluate
+			// all the recently polled blocks
+			break
+		}
+		b, err := cp.freshBlockWithRetry(forkHeight)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(b.Hash, hash) {
+			break
 		}
 	}
+
+	for h := range cp.recentHashes {
+		if h > forkHeight {
+			delete(cp.recentHashes, h)
+		}
+	}
+	cp.dropBufferedBlocksAboveHeight(forkHeight)
+	cp.nextHeight = forkHeight + 1
+	if cp.pendingForkHeight == nil || forkHeight < *cp.pendingForkHeight {
+		cp.pendingForkHeight = &forkHeight
+	}
+
+	cp.metrics.IncrementPollerTotalReorgs()
+	cp.logger.Warn("detected a reorg of the consumer chain, rolled back the poller",
+		zap.Uint64("last_polled_height", lastHeight),
+		zap.Uint64("fork_height", forkHeight),
+		zap.Uint64("next_height", cp.nextHeight))
+
+	return nil
+}
+
+// markReorgBlock hands the fork height of the reorg detected since the last
+// polled block to the consumer along with the given block. The lowest fork
+// height is kept if the consumer has not taken the previous one yet
+func (cp *ChainPoller) markReorgBlock(b *types.BlockInfo) {
+	if cp.pendingForkHeight == nil {
+		return
+	}
+
+	cp.reorgMu.Lock()
+	forkHeight := *cp.pendingForkHeight
+	if cp.reorgBlock != nil && cp.forkHeight < forkHeight {
+		forkHeight = cp.forkHeight
+	}
+	cp.reorgBlock = b
+	cp.forkHeight = forkHeight
+	cp.reorgMu.Unlock()
+
+	cp.pendingForkHeight = nil
+}
+
+// TakeForkHeight returns the fork height of the reorg detected before the
+// given block is polled, once, if the block is the first one polled after
+// the reorg
+func (cp *ChainPoller) TakeForkHeight(b *types.BlockInfo) (uint64, bool) {
+	cp.reorgMu.Lock()
+	defer cp.reorgMu.Unlock()
+
+	if cp.reorgBlock == nil || cp.reorgBlock != b {
+		return 0, false
+	}
+	cp.reorgBlock = nil
+
+	return cp.forkHeight, true
+}
+
+// dropBufferedBlocksAboveHeight removes the buffered blocks higher than the given
+// height while keeping the order of the remaining ones
+func (cp *ChainPoller) dropBufferedBlocksAboveHeight(height uint64) {
+	var kept []*types.BlockInfo
+drain:
+	for {
+		select {
+		case block := <-cp.blockInfoChan:
+			if block.Height <= height {
+				kept = append(kept, block)
+			}
+		default:
+			break drain
+		}
+	}
+
+	for _, block := range kept {
+		cp.blockInfoChan <- block
+	}
 }
 
 func (cp *ChainPoller) SkipToHeight(height uint64) error {
@@ -1,6 +1,8 @@
 package service_test
 
 import (
+	"errors"
+	"math"
 	"math/rand"
 	"sync"
 	"testing"
@@ -151,3 +153,258 @@ func FuzzChainPoller_SkipHeight(f *testing.F) {
 		require.Equal(t, skipHeight+1, poller.NextHeight())
 	})
 }
+
+// FuzzChainPoller_Reorg tests that the poller detects a reorg of the recently
+// polled blocks, even through a block source caching them, re-polls the
+// heights from the fork point, and hands the fork height to the consumer
+// along with the first re-polled block only
+func FuzzChainPoller_Reorg(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		startHeight := uint64(r.Int63n(100) + 1)
+		numBlocks := uint64(r.Int63n(10) + 2)
+		tipHeight := startHeight + numBlocks - 1
+
+		var mu sync.Mutex
+		hashes := make(map[uint64][]byte)
+		for h := startHeight; h <= tipHeight; h++ {
+			hashes[h] = testutil.GenRandomByteArray(r, 32)
+		}
+
+		ctl := gomock.NewController(t)
+		mockClientController := mocks.NewMockClientController(ctl)
+		mockClientController.EXPECT().Close().Return(nil).AnyTimes()
+		mockClientController.EXPECT().QueryActivatedHeight().Return(uint64(1), nil).AnyTimes()
+		mockClientController.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: startHeight - 1}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryBlock(gomock.Any()).DoAndReturn(func(height uint64) (*types.BlockInfo, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			hash, ok := hashes[height]
+			if !ok {
+				return nil, errors.New("the block is not produced yet")
+			}
+			return &types.BlockInfo{Height: height, Hash: hash}, nil
+		}).AnyTimes()
+
+		m := metrics.NewFpMetrics()
+		pollerCfg := fpcfg.DefaultChainPollerConfig()
+		pollerCfg.PollInterval = time.Millisecond
+		pollerCfg.ReorgCheckDepth = 100
+		pollerCfg.QueryRetryAttempts = 1
+		pollerCfg.MaxFailedCycles = math.MaxUint32
+		// the cached blocks never expire within the test
+		blockSource := service.NewSharedBlockSource(mockClientController, time.Hour)
+		poller := service.NewChainPoller(zap.NewNop(), &pollerCfg, blockSource, m)
+		require.NoError(t, poller.Start(startHeight))
+		defer func() {
+			require.NoError(t, poller.Stop())
+		}()
+
+		receiveBlock := func(height uint64) *types.BlockInfo {
+			select {
+			case info := <-poller.GetBlockInfoChan():
+				require.Equal(t, height, info.Height)
+				return info
+			case <-time.After(10 * time.Second):
+				t.Fatalf("Failed to get block info")
+			}
+			return nil
+		}
+
+		for h := startHeight; h <= tipHeight; h++ {
+			b := receiveBlock(h)
+			_, ok := poller.TakeForkHeight(b)
+			require.False(t, ok)
+		}
+
+		// the blocks above the fork height are replaced
+		forkHeight := startHeight - 1 + r.Uint64()%numBlocks
+		mu.Lock()
+		for h := forkHeight + 1; h <= tipHeight; h++ {
+			hashes[h] = testutil.GenRandomByteArray(r, 32)
+		}
+		mu.Unlock()
+
+		for h := forkHeight + 1; h <= tipHeight; h++ {
+			b := receiveBlock(h)
+			mu.Lock()
+			require.Equal(t, hashes[h], b.Hash)
+			mu.Unlock()
+			takenForkHeight, ok := poller.TakeForkHeight(b)
+			require.Equal(t, h == forkHeight+1, ok)
+			if ok {
+				require.Equal(t, forkHeight, takenForkHeight)
+				_, ok = poller.TakeForkHeight(b)
+				require.False(t, ok)
+			}
+		}
+	})
+}
+
+// TestChainPoller_Backpressure tests that the poller pauses once its buffer is
+// full instead of polling more blocks, and that it can still be stopped
+func TestChainPoller_Backpressure(t *testing.T) {
+	startHeight := uint64(10)
+	bufferSize := uint32(2)
+
+	ctl := gomock.NewController(t)
+	mockClientController := mocks.NewMockClientController(ctl)
+	mockClientController.EXPECT().Close().Return(nil).AnyTimes()
+	mockClientController.EXPECT().QueryActivatedHeight().Return(uint64(1), nil).AnyTimes()
+	mockClientController.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: startHeight - 1}, nil).AnyTimes()
+	mockClientController.EXPECT().QueryBlock(gomock.Any()).DoAndReturn(func(height uint64) (*types.BlockInfo, error) {
+		return &types.BlockInfo{Height: height}, nil
+	}).AnyTimes()
+
+	m := metrics.NewFpMetrics()
+	pollerCfg := fpcfg.DefaultChainPollerConfig()
+	pollerCfg.PollInterval = time.Millisecond
+	pollerCfg.BufferSize = bufferSize
+	pollerCfg.ReorgCheckDepth = 0
+	poller := service.NewChainPoller(zap.NewNop(), &pollerCfg, mockClientController, m)
+	require.NoError(t, poller.Start(startHeight))
+
+	// the poller holds at most one block beyond the full buffer
+	time.Sleep(100 * time.Millisecond)
+	require.LessOrEqual(t, poller.NextHeight(), startHeight+uint64(bufferSize)+1)
+
+	// the blocks are consumed in order once the submission resumes
+	for i := startHeight; i < startHeight+uint64(bufferSize)+2; i++ {
+		select {
+		case info := <-poller.GetBlockInfoChan():
+			require.Equal(t, i, info.Height)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Failed to get block info")
+		}
+	}
+
+	stopped := make(chan error)
+	go func() {
+		stopped <- poller.Stop()
+	}()
+	select {
+	case err := <-stopped:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatalf("the paused poller is not stopped")
+	}
+}
+
+func TestChainPoller_CheckReorg(t *testing.T) {
+	startHeight := uint64(10)
+	numBlocks := uint64(10)
+
+	testCases := []struct {
+		name               string
+		reorgCheckDepth    uint64
+		forkHeight         uint64
+		expectedNextHeight uint64
+	}{
+		{
+			name:               "the cursor stays put without a reorg",
+			reorgCheckDepth:    100,
+			forkHeight:         startHeight + numBlocks - 1,
+			expectedNextHeight: startHeight + numBlocks,
+		},
+		{
+			name:               "the cursor is rolled back to the block after the fork point",
+			reorgCheckDepth:    100,
+			forkHeight:         startHeight + 4,
+			expectedNextHeight: startHeight + 5,
+		},
+		{
+			name:               "the cursor is rolled back to the check depth if the fork point is beyond it",
+			reorgCheckDepth:    3,
+			forkHeight:         startHeight,
+			expectedNextHeight: startHeight + numBlocks - 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var polled []*types.BlockInfo
+			for h := startHeight; h < startHeight+numBlocks; h++ {
+				polled = append(polled, &types.BlockInfo{Height: h, Hash: []byte{byte(h)}})
+			}
+
+			ctl := gomock.NewController(t)
+			mockClientController := mocks.NewMockClientController(ctl)
+			mockClientController.EXPECT().QueryBlock(gomock.Any()).DoAndReturn(func(height uint64) (*types.BlockInfo, error) {
+				// the blocks above the fork height are replaced on the chain
+				if height > tc.forkHeight {
+					return &types.BlockInfo{Height: height, Hash: []byte("replaced")}, nil
+				}
+				return &types.BlockInfo{Height: height, Hash: []byte{byte(height)}}, nil
+			}).AnyTimes()
+
+			pollerCfg := fpcfg.DefaultChainPollerConfig()
+			pollerCfg.BufferSize = uint32(numBlocks)
+			pollerCfg.ReorgCheckDepth = tc.reorgCheckDepth
+			pollerCfg.QueryRetryAttempts = 1
+			poller := service.NewChainPoller(zap.NewNop(), &pollerCfg, mockClientController, metrics.NewFpMetrics())
+			poller.PollBlocks(polled)
+
+			require.NoError(t, poller.CheckReorg())
+			require.Equal(t, tc.expectedNextHeight, poller.NextHeight())
+
+			// only the blocks below the rolled back cursor remain buffered
+			blockChan := poller.GetBlockInfoChan()
+			require.Len(t, blockChan, int(tc.expectedNextHeight-startHeight))
+			for h := startHeight; h < tc.expectedNextHeight; h++ {
+				require.Equal(t, h, (<-blockChan).Height)
+			}
+		})
+	}
+}
+
+func TestChainPoller_DropBufferedBlocksAboveHeight(t *testing.T) {
+	startHeight := uint64(10)
+	numBlocks := uint64(5)
+
+	testCases := []struct {
+		name           string
+		height         uint64
+		expectedHeight uint64
+	}{
+		{
+			name:           "the blocks above the height are dropped",
+			height:         startHeight + 2,
+			expectedHeight: startHeight + 2,
+		},
+		{
+			name:           "all the blocks are dropped below the buffered ones",
+			height:         startHeight - 1,
+			expectedHeight: startHeight - 1,
+		},
+		{
+			name:           "no block is dropped above the buffered ones",
+			height:         startHeight + numBlocks,
+			expectedHeight: startHeight + numBlocks - 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var polled []*types.BlockInfo
+			for h := startHeight; h < startHeight+numBlocks; h++ {
+				polled = append(polled, &types.BlockInfo{Height: h})
+			}
+
+			pollerCfg := fpcfg.DefaultChainPollerConfig()
+			pollerCfg.BufferSize = uint32(numBlocks)
+			poller := service.NewChainPoller(zap.NewNop(), &pollerCfg, nil, metrics.NewFpMetrics())
+			poller.PollBlocks(polled)
+
+			poller.DropBufferedBlocksAboveHeight(tc.height)
+
+			// the kept blocks stay in order
+			blockChan := poller.GetBlockInfoChan()
+			require.Len(t, blockChan, int(tc.expectedHeight+1-startHeight))
+			for h := startHeight; h <= tc.expectedHeight; h++ {
+				require.Equal(t, h, (<-blockChan).Height)
+			}
+		})
+	}
+}
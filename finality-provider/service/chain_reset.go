@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+// DetectChainReset returns the registered finality providers of the consumer
+// chains that have processed heights beyond the tip of their chain, which
+// means the chain is restarted from a new genesis with the same chain ID,
+// e.g., on a devnet
+func (app *FinalityProviderApp) DetectChainReset() ([]*store.StoredFinalityProvider, error) {
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	tips := app.fpManager.newChainTips()
+	var resetFps []*store.StoredFinalityProvider
+	for _, fp := range storedFps {
+		if !app.fpManager.servesChain(fp.ChainID) || fp.Status == proto.FinalityProviderStatus_CREATED {
+			continue
+		}
+		tip, err := tips.of(fp.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		if fp.LastProcessedHeight > tip.Height {
+			app.logger.Warn("the finality provider has processed heights beyond the tip of the consumer chain",
+				zap.String("btc_pk", fp.GetBIP340BTCPK().MarshalHex()),
+				zap.String("chain_id", fp.ChainID),
+				zap.Uint64("last_processed_height", fp.LastProcessedHeight),
+				zap.Uint64("tip_height", tip.Height),
+			)
+			resetFps = append(resetFps, fp)
+		}
+	}
+
+	return resetFps, nil
+}
+
+// ReRegisterFinalityProviders resets the given finality providers after the
+// consumer chain is reset and registers them to the chain again. The app
+// must be started to register the finality providers
+func (app *FinalityProviderApp) ReRegisterFinalityProviders(fps []*store.StoredFinalityProvider) error {
+	for _, fp := range fps {
+		fpPk := fp.GetBIP340BTCPK()
+		if err := app.fps.ResetFinalityProvider(fp.BtcPk); err != nil {
+			return fmt.Errorf("failed to reset the finality provider %s: %w", fpPk.MarshalHex(), err)
+		}
+		app.fpManager.metrics.RecordFpStatus(fpPk.MarshalHex(), proto.FinalityProviderStatus_CREATED)
+
+		res, err := app.RegisterFinalityProvider(fpPk.MarshalHex())
+		if err != nil {
+			return fmt.Errorf("failed to re-register the finality provider %s: %w", fpPk.MarshalHex(), err)
+		}
+
+		app.logger.Info("re-registered the finality provider after the consumer chain is reset",
+			zap.String("btc_pk", fpPk.MarshalHex()),
+			zap.String("tx_hash", res.TxHash),
+		)
+	}
+
+	return nil
+}
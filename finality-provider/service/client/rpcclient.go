@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 
 	sdkmath "cosmossdk.io/math"
 	bbntypes "github.com/babylonchain/babylon/types"
@@ -17,8 +18,11 @@ type FinalityProviderServiceGRpcClient struct {
 	client proto.FinalityProvidersClient
 }
 
-func NewFinalityProviderServiceGRpcClient(remoteAddr string) (*FinalityProviderServiceGRpcClient, func(), error) {
-	conn, err := grpc.Dial(remoteAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewFinalityProviderServiceGRpcClient connects the daemon at remoteAddr,
+// with the dial options appended to the insecure transport credentials
+func NewFinalityProviderServiceGRpcClient(remoteAddr string, opts ...grpc.DialOption) (*FinalityProviderServiceGRpcClient, func(), error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.Dial(remoteAddr, opts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to build gRPC connection to %s: %w", remoteAddr, err)
 	}
@@ -46,9 +50,16 @@ func (c *FinalityProviderServiceGRpcClient) RegisterFinalityProvider(
 	ctx context.Context,
 	fpPk *bbntypes.BIP340PubKey,
 	passphrase string,
+	approvalToken string,
+	activationHeight uint64,
 ) (*proto.RegisterFinalityProviderResponse, error) {
 
-	req := &proto.RegisterFinalityProviderRequest{BtcPk: fpPk.MarshalHex(), Passphrase: passphrase}
+	req := &proto.RegisterFinalityProviderRequest{
+		BtcPk:            fpPk.MarshalHex(),
+		Passphrase:       passphrase,
+		ApprovalToken:    approvalToken,
+		ActivationHeight: activationHeight,
+	}
 	res, err := c.client.RegisterFinalityProvider(ctx, req)
 	if err != nil {
 		return nil, err
@@ -64,18 +75,19 @@ func (c *FinalityProviderServiceGRpcClient) CreateFinalityProvider(
 	commission *sdkmath.LegacyDec,
 ) (*proto.CreateFinalityProviderResponse, error) {
 
-	descBytes, err := description.Marshal()
-	if err != nil {
-		return nil, err
-	}
-
 	req := &proto.CreateFinalityProviderRequest{
-		KeyName:     keyName,
-		ChainId:     chainID,
-		Passphrase:  passphrase,
-		HdPath:      hdPath,
-		Description: descBytes,
-		Commission:  commission.String(),
+		KeyName:    keyName,
+		ChainId:    chainID,
+		Passphrase: passphrase,
+		HdPath:     hdPath,
+		Commission: commission.String(),
+		StructuredDescription: &proto.Description{
+			Moniker:         description.Moniker,
+			Identity:        description.Identity,
+			Website:         description.Website,
+			SecurityContact: description.SecurityContact,
+			Details:         description.Details,
+		},
 	}
 
 	res, err := c.client.CreateFinalityProvider(ctx, req)
@@ -86,11 +98,12 @@ func (c *FinalityProviderServiceGRpcClient) CreateFinalityProvider(
 	return res, nil
 }
 
-func (c *FinalityProviderServiceGRpcClient) AddFinalitySignature(ctx context.Context, fpPk string, height uint64, appHash []byte) (*proto.AddFinalitySignatureResponse, error) {
+func (c *FinalityProviderServiceGRpcClient) AddFinalitySignature(ctx context.Context, fpPk string, height uint64, appHash []byte, force bool) (*proto.AddFinalitySignatureResponse, error) {
 	req := &proto.AddFinalitySignatureRequest{
 		BtcPk:   fpPk,
 		Height:  height,
 		AppHash: appHash,
+		Force:   force,
 	}
 
 	res, err := c.client.AddFinalitySignature(ctx, req)
@@ -101,6 +114,134 @@ func (c *FinalityProviderServiceGRpcClient) AddFinalitySignature(ctx context.Con
 	return res, nil
 }
 
+func (c *FinalityProviderServiceGRpcClient) SignFinality(ctx context.Context, fpPk string, height uint64, blockHash []byte) (*proto.SignFinalityResponse, error) {
+	req := &proto.SignFinalityRequest{
+		BtcPk:     fpPk,
+		Height:    height,
+		BlockHash: blockHash,
+	}
+
+	res, err := c.client.SignFinality(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// SignMessage signs the message with the chain key of the finality provider
+// as specified by ADR-36
+func (c *FinalityProviderServiceGRpcClient) SignMessage(
+	ctx context.Context,
+	fpPk, passphrase, hdPath string,
+	msg []byte,
+) (*proto.SignMessageResponse, error) {
+	req := &proto.SignMessageRequest{
+		BtcPk:      fpPk,
+		Message:    msg,
+		Passphrase: passphrase,
+		HdPath:     hdPath,
+	}
+
+	return c.client.SignMessage(ctx, req)
+}
+
+// VerifyMessage verifies the ADR-36 signature of the message by the signer
+func (c *FinalityProviderServiceGRpcClient) VerifyMessage(
+	ctx context.Context,
+	signer string,
+	pubKey, msg, sig []byte,
+) (*proto.VerifyMessageResponse, error) {
+	req := &proto.VerifyMessageRequest{
+		Signer:    signer,
+		PubKey:    pubKey,
+		Message:   msg,
+		Signature: sig,
+	}
+
+	return c.client.VerifyMessage(ctx, req)
+}
+
+// ProveKeyOwnership signs the challenge with the BTC key of the finality
+// provider to prove its ownership
+func (c *FinalityProviderServiceGRpcClient) ProveKeyOwnership(
+	ctx context.Context,
+	fpPk string,
+	challenge []byte,
+	passphrase string,
+) (*proto.ProveKeyOwnershipResponse, error) {
+	req := &proto.ProveKeyOwnershipRequest{
+		BtcPk:      fpPk,
+		Challenge:  challenge,
+		Passphrase: passphrase,
+	}
+
+	return c.client.ProveKeyOwnership(ctx, req)
+}
+
+// ExportState returns the encrypted archive of the state of the daemon and
+// the hex of its SHA-256 hash reported by the daemon
+func (c *FinalityProviderServiceGRpcClient) ExportState(ctx context.Context, passphrase string, includeKeys bool, approvalToken string) ([]byte, string, error) {
+	req := &proto.ExportStateRequest{
+		Passphrase:    passphrase,
+		IncludeKeys:   includeKeys,
+		ApprovalToken: approvalToken,
+	}
+
+	stream, err := c.client.ExportState(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		archive []byte
+		hash    string
+	)
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		archive = append(archive, chunk.Data...)
+		if chunk.Sha256 != "" {
+			hash = chunk.Sha256
+		}
+	}
+
+	return archive, hash, nil
+}
+
+// ImportState sends the encrypted archive of the state to the daemon, which
+// verifies the archive against the hash unless it is empty
+func (c *FinalityProviderServiceGRpcClient) ImportState(ctx context.Context, archive []byte, passphrase, hash, approvalToken string) (*proto.ImportStateResponse, error) {
+	stream, err := c.client.ImportState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 1 << 20
+	for start := 0; start == 0 || start < len(archive); start += chunkSize {
+		end := start + chunkSize
+		if end > len(archive) {
+			end = len(archive)
+		}
+		req := &proto.ImportStateRequest{Data: archive[start:end]}
+		if start == 0 {
+			req.Passphrase = passphrase
+			req.Sha256 = hash
+			req.ApprovalToken = approvalToken
+		}
+		if err := stream.Send(req); err != nil {
+			return nil, err
+		}
+	}
+
+	return stream.CloseAndRecv()
+}
+
 func (c *FinalityProviderServiceGRpcClient) QueryFinalityProviderList(ctx context.Context) (*proto.QueryFinalityProviderListResponse, error) {
 	req := &proto.QueryFinalityProviderListRequest{}
 	res, err := c.client.QueryFinalityProviderList(ctx, req)
@@ -121,16 +262,189 @@ func (c *FinalityProviderServiceGRpcClient) QueryFinalityProviderInfo(ctx contex
 	return res, nil
 }
 
+// SearchFinalityProviders lists the finality providers matching the filters
+// of the request
+func (c *FinalityProviderServiceGRpcClient) SearchFinalityProviders(
+	ctx context.Context,
+	req *proto.QueryFinalityProviderListRequest,
+) (*proto.QueryFinalityProviderListResponse, error) {
+	return c.client.QueryFinalityProviderList(ctx, req)
+}
+
+// LookupFinalityProvider queries the finality provider by its chain key or
+// moniker, which must match a single finality provider
+func (c *FinalityProviderServiceGRpcClient) LookupFinalityProvider(
+	ctx context.Context,
+	req *proto.QueryFinalityProviderRequest,
+) (*proto.QueryFinalityProviderResponse, error) {
+	return c.client.QueryFinalityProvider(ctx, req)
+}
+
 func (c *FinalityProviderServiceGRpcClient) SignMessageFromChainKey(
 	ctx context.Context,
 	keyName, passphrase, hdPath string,
 	rawMsgToSign []byte,
+	approvalToken string,
 ) (*proto.SignMessageFromChainKeyResponse, error) {
 	req := &proto.SignMessageFromChainKeyRequest{
-		MsgToSign:  rawMsgToSign,
-		KeyName:    keyName,
-		Passphrase: passphrase,
-		HdPath:     hdPath,
+		MsgToSign:     rawMsgToSign,
+		KeyName:       keyName,
+		Passphrase:    passphrase,
+		HdPath:        hdPath,
+		ApprovalToken: approvalToken,
 	}
 	return c.client.SignMessageFromChainKey(ctx, req)
 }
+
+// SyncState opens the stream of the state of the finality providers, which is
+// used by a standby daemon
+func (c *FinalityProviderServiceGRpcClient) SyncState(ctx context.Context) (proto.FinalityProviders_SyncStateClient, error) {
+	return c.client.SyncState(ctx, &proto.SyncStateRequest{})
+}
+
+func (c *FinalityProviderServiceGRpcClient) QueryFinalizedBlocks(ctx context.Context, chainID string, limit uint64) (*proto.QueryFinalizedBlocksResponse, error) {
+	req := &proto.QueryFinalizedBlocksRequest{Limit: limit, ChainId: chainID}
+	res, err := c.client.QueryFinalizedBlocks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) ExportFinalityProofs(ctx context.Context, chainID string, startHeight, endHeight uint64) (*proto.ExportFinalityProofsResponse, error) {
+	req := &proto.ExportFinalityProofsRequest{StartHeight: startHeight, EndHeight: endHeight, ChainId: chainID}
+	res, err := c.client.ExportFinalityProofs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) QueryNetworkParticipation(ctx context.Context) (*proto.QueryNetworkParticipationResponse, error) {
+	req := &proto.QueryNetworkParticipationRequest{}
+	res, err := c.client.QueryNetworkParticipation(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) QueryVotingHistory(ctx context.Context, fpPkHex string, fromHeight, limit uint64) (*proto.QueryVotingHistoryResponse, error) {
+	req := &proto.QueryVotingHistoryRequest{BtcPk: fpPkHex, FromHeight: fromHeight, Limit: limit}
+	res, err := c.client.QueryVotingHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) ResumeFinalityProvider(ctx context.Context, fpPkHex, passphrase string) (*proto.ResumeFinalityProviderResponse, error) {
+	req := &proto.ResumeFinalityProviderRequest{BtcPk: fpPkHex, Passphrase: passphrase}
+	res, err := c.client.ResumeFinalityProvider(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) PauseFinalityProvider(ctx context.Context, fpPkHex string) (*proto.PauseFinalityProviderResponse, error) {
+	req := &proto.PauseFinalityProviderRequest{BtcPk: fpPkHex}
+	res, err := c.client.PauseFinalityProvider(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) CommitPubRand(ctx context.Context, fpPkHex string, targetHeight uint64) (*proto.CommitPubRandResponse, error) {
+	req := &proto.CommitPubRandRequest{BtcPk: fpPkHex, TargetHeight: targetHeight}
+	res, err := c.client.CommitPubRand(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// TailLogs streams the logs of the daemon at or above the given level, and of
+// the given subsystem if it is not empty, to the handler until the context is
+// cancelled or the handler returns an error
+func (c *FinalityProviderServiceGRpcClient) TailLogs(
+	ctx context.Context,
+	level, subsystem string,
+	handler func(*proto.TailLogsResponse) error,
+) error {
+	req := &proto.TailLogsRequest{Level: level, Subsystem: subsystem}
+	stream, err := c.client.TailLogs(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handler(entry); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *FinalityProviderServiceGRpcClient) SetRewardAddress(ctx context.Context, fpPkHex, rewardAddr string) (*proto.SetRewardAddressResponse, error) {
+	req := &proto.SetRewardAddressRequest{BtcPk: fpPkHex, RewardAddress: rewardAddr}
+	res, err := c.client.SetRewardAddress(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) QueryStatus(ctx context.Context) (*proto.QueryStatusResponse, error) {
+	req := &proto.QueryStatusRequest{}
+	res, err := c.client.QueryStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) ValidateState(ctx context.Context) (*proto.ValidateStateResponse, error) {
+	req := &proto.ValidateStateRequest{}
+	res, err := c.client.ValidateState(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) InjectFault(ctx context.Context, req *proto.InjectFaultRequest) (*proto.InjectFaultResponse, error) {
+	res, err := c.client.InjectFault(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (c *FinalityProviderServiceGRpcClient) ListKeys(ctx context.Context) (*proto.ListKeysResponse, error) {
+	req := &proto.ListKeysRequest{}
+	res, err := c.client.ListKeys(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"time"
+)
+
+// clockSkewChecker measures the skew between the local clock and the timestamp
+// of the tip of the consumer chain. The skew is only measured on a tip produced
+// since the previous check, as the timestamp of a tip that does not advance,
+// e.g., during a chain stall, grows old just like that of a clock running ahead
+type clockSkewChecker struct {
+	maxSkew       time.Duration
+	lastTipHeight uint64
+}
+
+func newClockSkewChecker(maxSkew time.Duration) *clockSkewChecker {
+	return &clockSkewChecker{maxSkew: maxSkew}
+}
+
+// observe returns the skew of the tip at the given height and time observed
+// at the given local time, and whether the skew is measured, which is not the
+// case on the first check or if the tip has not advanced since the previous one
+func (c *clockSkewChecker) observe(tipHeight uint64, blockTime, now time.Time) (skew time.Duration, measured bool) {
+	advanced := c.lastTipHeight != 0 && tipHeight > c.lastTipHeight
+	if tipHeight > c.lastTipHeight {
+		c.lastTipHeight = tipHeight
+	}
+	if !advanced {
+		return 0, false
+	}
+
+	return now.Sub(blockTime), true
+}
+
+// exceeds returns true if the skew is beyond the tolerated maximum in either
+// direction
+func (c *clockSkewChecker) exceeds(skew time.Duration) bool {
+	return skew > c.maxSkew || skew < -c.maxSkew
+}
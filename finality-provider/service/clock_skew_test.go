@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockSkewChecker(t *testing.T) {
+	c := newClockSkewChecker(time.Minute)
+	start := time.Now()
+
+	// the first check only records the tip
+	_, measured := c.observe(10, start, start)
+	require.False(t, measured)
+
+	// the tip stalls, whose timestamp grows old like a clock running ahead
+	_, measured = c.observe(10, start, start.Add(10*time.Minute))
+	require.False(t, measured)
+
+	// the chain resumes with the clock in sync
+	skew, measured := c.observe(11, start.Add(11*time.Minute), start.Add(11*time.Minute+time.Second))
+	require.True(t, measured)
+	require.Equal(t, time.Second, skew)
+	require.False(t, c.exceeds(skew))
+
+	// the clock runs ahead while the chain produces blocks
+	skew, measured = c.observe(12, start.Add(12*time.Minute), start.Add(14*time.Minute))
+	require.True(t, measured)
+	require.True(t, c.exceeds(skew))
+
+	// the clock runs behind
+	skew, measured = c.observe(13, start.Add(16*time.Minute), start.Add(14*time.Minute))
+	require.True(t, measured)
+	require.True(t, c.exceeds(skew))
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+// cloneDetector watches the votes on a consumer chain for the ones of the
+// running finality providers that are not signed by this daemon, which means
+// that another daemon, e.g., a cloned instance, is signing with the same keys.
+// The votes are checked observerDelay blocks behind the tip, by when the
+// blocks signed by this daemon are recorded before their votes are broadcast
+type cloneDetector struct {
+	chainID string
+	cc      clientcontroller.ClientController
+	// nextHeight is the next height to check, which is only accessed by the
+	// monitor of the manager
+	nextHeight uint64
+}
+
+// watchClones starts checking the votes on the given chain, if the detection
+// is enabled, which must be called with mu held
+func (fpm *FinalityProviderManager) watchClones(chainID string) {
+	if !fpm.config.CloneDetection {
+		return
+	}
+	if _, ok := fpm.cloneDetectors[chainID]; ok {
+		return
+	}
+
+	fpm.cloneDetectors[chainID] = &cloneDetector{chainID: chainID, cc: fpm.ccOf(chainID)}
+}
+
+// monitorClones periodically checks the votes of the running finality
+// providers on their chains, and stops the ones voted by another daemon
+func (fpm *FinalityProviderManager) monitorClones() {
+	defer fpm.wg.Done()
+
+	if !fpm.config.CloneDetection {
+		return
+	}
+
+	checkTicker := time.NewTicker(fpm.config.PollerConfig.PollInterval)
+	defer checkTicker.Stop()
+
+	for {
+		select {
+		case <-checkTicker.C:
+			fpm.mu.Lock()
+			detectors := make([]*cloneDetector, 0, len(fpm.cloneDetectors))
+			for _, d := range fpm.cloneDetectors {
+				detectors = append(detectors, d)
+			}
+			fpm.mu.Unlock()
+
+			for _, d := range detectors {
+				if err := fpm.detectClones(d); err != nil {
+					fpm.logger.Debug("failed to check the votes for another daemon",
+						zap.String("chain_id", d.chainID), zap.Error(err))
+				}
+			}
+		case <-fpm.quit:
+			return
+		}
+	}
+}
+
+// detectClones checks the votes at the heights since the previous check. The
+// first check starts from the tip, as the earlier votes may be submitted by
+// the daemon this one replaces
+func (fpm *FinalityProviderManager) detectClones(d *cloneDetector) error {
+	tipBlock, err := d.cc.QueryBestBlock()
+	if err != nil {
+		return err
+	}
+	if tipBlock.Height <= observerDelay {
+		return nil
+	}
+	endHeight := tipBlock.Height - observerDelay
+
+	if d.nextHeight == 0 {
+		d.nextHeight = endHeight
+	}
+	if endHeight >= d.nextHeight+maxObservedBlocksPerCycle {
+		endHeight = d.nextHeight + maxObservedBlocksPerCycle - 1
+	}
+
+	for height := d.nextHeight; height <= endHeight; height++ {
+		votedPks, err := d.cc.QueryVotesAtHeight(height)
+		if err != nil {
+			return err
+		}
+
+		for i := range votedPks {
+			fpi, err := fpm.GetFinalityProviderInstance(&votedPks[i])
+			if err != nil {
+				// the finality provider is not run by this daemon
+				continue
+			}
+
+			_, err = fpm.fps.GetSignedBlockHash(fpi.GetBtcPk(), height)
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, store.ErrSignedBlockNotFound) {
+				return err
+			}
+
+			fpm.stopClonedFinalityProvider(fpi, height)
+		}
+
+		d.nextHeight = height + 1
+	}
+
+	return nil
+}
+
+// stopClonedFinalityProvider switches the finality provider voted by another
+// daemon to safe mode, so that it stays stopped, even across restarts, until
+// the operator makes sure that only one daemon runs it and resumes it. The
+// instance may be removed concurrently since it was looked up, e.g., paused
+// or unloaded, in which case it is left as is
+func (fpm *FinalityProviderManager) stopClonedFinalityProvider(fpi *FinalityProviderInstance, height uint64) {
+	fpm.metrics.IncrementFpTotalClonedVotes(fpi.GetBtcPkHex())
+	fpi.emitEvent(&hooks.Event{Type: hooks.EventCloneDetected, Height: height, Error: ErrCloneDetected.Error()})
+
+	if err := fpm.setFinalityProviderSafeMode(fpi); err != nil {
+		fpm.logger.Error("found a vote not submitted by this daemon but failed to stop the finality provider",
+			zap.String("pk", fpi.GetBtcPkHex()),
+			zap.Uint64("height", height),
+			zap.Error(err))
+		return
+	}
+
+	fpm.logger.Error("found a vote not submitted by this daemon, stopped the finality provider until it is resumed",
+		zap.String("pk", fpi.GetBtcPkHex()),
+		zap.Uint64("height", height),
+		zap.Error(ErrCloneDetected))
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/policy"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestCheckConfirmationDepth(t *testing.T) {
+	testCases := []struct {
+		name        string
+		depth       uint64
+		chainDepths map[string]uint64
+		tipHeight   uint64
+		expectQuery bool
+		expectDefer bool
+	}{
+		{
+			name: "a block is signed right away without a confirmation depth",
+		},
+		{
+			name:        "a block with enough confirmations is signed",
+			depth:       3,
+			tipHeight:   13,
+			expectQuery: true,
+		},
+		{
+			name:        "a block with too few confirmations is deferred",
+			depth:       3,
+			tipHeight:   12,
+			expectQuery: true,
+			expectDefer: true,
+		},
+		{
+			name:        "the confirmation depth of the chain overrides the default one",
+			depth:       3,
+			chainDepths: map[string]uint64{"chain-test": 5},
+			tipHeight:   13,
+			expectQuery: true,
+			expectDefer: true,
+		},
+		{
+			name:        "the confirmation depth of the chain may disable the default one",
+			depth:       3,
+			chainDepths: map[string]uint64{"chain-test": 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			cc := mocks.NewMockClientController(ctl)
+			if tc.expectQuery {
+				cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: tc.tipHeight}, nil).Times(1)
+			}
+
+			sk, err := btcec.NewPrivateKey()
+			require.NoError(t, err)
+			pollerCfg := fpcfg.DefaultChainPollerConfig()
+			pollerCfg.QueryRetryDelay = time.Millisecond
+			fp := &FinalityProviderInstance{
+				cfg: &fpcfg.Config{
+					ConfirmationDepth:       tc.depth,
+					ChainConfirmationDepths: tc.chainDepths,
+					PollerConfig:            &pollerCfg,
+				},
+				fpState: NewFpState(&store.StoredFinalityProvider{BtcPk: sk.PubKey(), ChainID: "chain-test"}, nil),
+				cc:      cc,
+				metrics: metrics.NewFpMetrics(),
+				logger:  zap.NewNop(),
+				ctx:     context.Background(),
+			}
+
+			err = fp.checkConfirmationDepth(&types.BlockInfo{Height: 10})
+			if tc.expectDefer {
+				require.ErrorIs(t, err, policy.ErrSigningDeferred)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
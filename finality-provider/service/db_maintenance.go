@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+// dbStatsInterval is the interval between each collection of the statistics
+// of the database, which walks all the buckets
+const dbStatsInterval = 1 * time.Minute
+
+// dbMaintenanceLoop periodically collects the statistics of the database and
+// compacts it, so that the operators notice the bloat of the store before it
+// slows down the daemon. The compaction is only available if the database
+// can be closed and opened again by the daemon
+func (app *FinalityProviderApp) dbMaintenanceLoop() {
+	defer app.wg.Done()
+
+	app.collectDBStats()
+
+	statsTicker := time.NewTicker(dbStatsInterval)
+	defer statsTicker.Stop()
+
+	// a nil channel never fires if the compaction is disabled
+	var compactionC <-chan time.Time
+	cdb, compactable := app.db.(*store.CompactableDB)
+	if compactable && app.config.DatabaseConfig.CompactionInterval > 0 {
+		compactionTicker := time.NewTicker(app.config.DatabaseConfig.CompactionInterval)
+		defer compactionTicker.Stop()
+		compactionC = compactionTicker.C
+	} else {
+		app.logger.Info("the background compaction of the database is disabled")
+	}
+
+	for {
+		select {
+		case <-statsTicker.C:
+			app.collectDBStats()
+		case <-compactionC:
+			app.compactDB(cdb)
+		case <-app.quit:
+			app.logger.Debug("exiting the database maintenance loop")
+			return
+		}
+	}
+}
+
+func (app *FinalityProviderApp) collectDBStats() {
+	stats, err := store.GetDBStats(app.db, app.config.DatabaseConfig.DBFile())
+	if err != nil {
+		app.logger.Debug("failed to collect the statistics of the database", zap.Error(err))
+		return
+	}
+
+	app.dbStats.Store(stats)
+	app.metrics.RecordDBStats(stats)
+}
+
+func (app *FinalityProviderApp) compactDB(cdb *store.CompactableDB) {
+	var sizeBefore int64
+	if stats := app.dbStats.Load(); stats != nil {
+		sizeBefore = stats.FileSize
+	}
+
+	app.logger.Info("compacting the database", zap.String("file", app.config.DatabaseConfig.DBFile()))
+	start := time.Now()
+	err := cdb.Compact()
+	app.metrics.RecordDBCompaction(err != nil)
+	if err != nil {
+		// the daemon cannot vote without its store
+		if errors.Is(err, store.ErrDatabaseUnavailable) {
+			app.logger.Fatal("the database is unavailable after the compaction", zap.Error(err))
+		}
+		app.logger.Warn("failed to compact the database", zap.Error(err))
+		return
+	}
+
+	duration := time.Since(start)
+
+	app.collectDBStats()
+	var sizeAfter int64
+	if stats := app.dbStats.Load(); stats != nil {
+		sizeAfter = stats.FileSize
+	}
+	app.logger.Info("compacted the database",
+		zap.Int64("size_before", sizeBefore),
+		zap.Int64("size_after", sizeAfter),
+		zap.Duration("duration", duration),
+	)
+}
+
+// DBStats returns the last statistics of the database, which are nil if
+// they have not been collected yet
+func (app *FinalityProviderApp) DBStats() *store.DBStats {
+	return app.dbStats.Load()
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// DiscoverFinalityProviders stores the finality providers whose EOTS keys
+// are held by the EOTS manager and which are registered on the consumer
+// chain but not stored yet, e.g., after the keys are restored from their
+// mnemonics into a fresh home directory. The chains are looked up in the
+// order of the config, the default one first, and the records are built from
+// the first chain the finality provider is registered on with the chain key
+// in the config. The BTC public keys of the discovered finality providers are
+// returned
+func (app *FinalityProviderApp) DiscoverFinalityProviders() ([]string, error) {
+	keys, err := app.eotsManager.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the EOTS keys: %w", err)
+	}
+
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+	storedPks := make(map[string]struct{}, len(storedFps))
+	for _, fp := range storedFps {
+		storedPks[fp.GetBIP340BTCPK().MarshalHex()] = struct{}{}
+	}
+
+	var records []*proto.FinalityProvider
+	for _, k := range keys {
+		pkHex := hex.EncodeToString(k.PubKey)
+		if _, ok := storedPks[pkHex]; ok {
+			continue
+		}
+
+		btcPk, err := schnorr.ParsePubKey(k.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of the EOTS key %s: %w", k.Name, err)
+		}
+		registered, chainID, err := app.lookUpRegistration(btcPk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the registration of %s: %w", pkHex, err)
+		}
+		if registered == nil {
+			app.logger.Debug("the EOTS key is not registered on the consumer chains",
+				zap.String("key_name", k.Name), zap.String("btc_pk", pkHex))
+			continue
+		}
+
+		record, err := app.discoveredRecord(registered, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid registration of %s: %w", pkHex, err)
+		}
+		records = append(records, record)
+	}
+
+	// the finality providers stored in the meantime are skipped
+	existing, err := app.fps.ImportFinalityProviders(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the discovered finality providers: %w", err)
+	}
+	existingPks := make(map[string]struct{}, len(existing))
+	for _, pk := range existing {
+		existingPks[hex.EncodeToString(pk)] = struct{}{}
+	}
+
+	discovered := make([]string, 0, len(records))
+	for _, record := range records {
+		pkHex := hex.EncodeToString(record.BtcPk)
+		if _, ok := existingPks[pkHex]; ok {
+			continue
+		}
+		app.logger.Info("discovered a registered finality provider from the EOTS keys",
+			zap.String("btc_pk", pkHex), zap.String("status", record.Status.String()))
+		discovered = append(discovered, pkHex)
+	}
+
+	return discovered, nil
+}
+
+// lookUpRegistration returns the registration of the finality provider on the
+// first chain it is registered on, along with the ID of the chain, or nil if
+// it is registered on none of the chains
+func (app *FinalityProviderApp) lookUpRegistration(btcPk *btcec.PublicKey) (*types.RegisteredFinalityProvider, string, error) {
+	for _, chainID := range app.fpManager.chainIDs() {
+		registered, err := app.fpManager.ccOf(chainID).QueryRegisteredFinalityProvider(btcPk)
+		if err != nil {
+			return nil, "", fmt.Errorf("chain %s: %w", chainID, err)
+		}
+		if registered != nil {
+			return registered, chainID, nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// discoveredRecord builds the stored record of a finality provider from its
+// registration on the consumer chain. The proof of possession is left empty
+// as it is only needed to register the finality provider
+func (app *FinalityProviderApp) discoveredRecord(registered *types.RegisteredFinalityProvider, chainID string) (*proto.FinalityProvider, error) {
+	var desBytes []byte
+	if registered.Description != nil {
+		bz, err := registered.Description.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("invalid description: %w", err)
+		}
+		desBytes = bz
+	}
+
+	if registered.Commission == nil {
+		return nil, fmt.Errorf("missing commission")
+	}
+
+	status := proto.FinalityProviderStatus_REGISTERED
+	if registered.SlashedBtcHeight > 0 {
+		status = proto.FinalityProviderStatus_SLASHED
+	}
+
+	return &proto.FinalityProvider{
+		ChainPk:     registered.ChainPk,
+		BtcPk:       schnorr.SerializePubKey(registered.BtcPk),
+		Description: desBytes,
+		Commission:  registered.Commission.String(),
+		Pop:         &proto.ProofOfPossession{},
+		KeyName:     app.config.BabylonConfig.Key,
+		ChainId:     chainID,
+		Status:      status,
+	}, nil
+}
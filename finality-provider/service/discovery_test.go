@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestDiscoverFinalityProviders(t *testing.T) {
+	chainID := "chain-test"
+	newRegistered := func(t *testing.T) *types.RegisteredFinalityProvider {
+		sk, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		commission := sdkmath.LegacyNewDecWithPrec(5, 2)
+
+		return &types.RegisteredFinalityProvider{
+			BtcPk:       sk.PubKey(),
+			ChainPk:     secp256k1.GenPrivKey().PubKey().Bytes(),
+			Description: &stakingtypes.Description{Moniker: "discovered"},
+			Commission:  &commission,
+		}
+	}
+	newApp := func(t *testing.T, cc *mocks.MockClientController, em *mocks.MockEOTSManager) (*FinalityProviderApp, *store.FinalityProviderStore) {
+		db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, db.Close())
+		})
+		s, err := store.NewFinalityProviderStore(db)
+		require.NoError(t, err)
+
+		cfg := &fpcfg.Config{BabylonConfig: &fpcfg.BBNConfig{ChainID: chainID, Key: "chain-key"}}
+		return &FinalityProviderApp{
+			config:      cfg,
+			fps:         s,
+			eotsManager: em,
+			logger:      zap.NewNop(),
+			fpManager:   &FinalityProviderManager{fps: s, config: cfg, cc: cc},
+		}, s
+	}
+
+	t.Run("the registered finality providers are stored", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		em := mocks.NewMockEOTSManager(ctl)
+		app, s := newApp(t, cc, em)
+
+		storedPk := createTestFinalityProvider(t, s, chainID)
+		registered := newRegistered(t)
+		slashed := newRegistered(t)
+		slashed.SlashedBtcHeight = 100
+		unregistered := newRegistered(t)
+		em.EXPECT().ListKeys().Return([]*eotstypes.KeyInfo{
+			{Name: "stored", PubKey: storedPk.MustMarshal()},
+			{Name: "registered", PubKey: schnorr.SerializePubKey(registered.BtcPk)},
+			{Name: "slashed", PubKey: schnorr.SerializePubKey(slashed.BtcPk)},
+			{Name: "unregistered", PubKey: schnorr.SerializePubKey(unregistered.BtcPk)},
+		}, nil).Times(1)
+		// the stored finality provider is not queried
+		cc.EXPECT().QueryRegisteredFinalityProvider(registered.BtcPk).Return(registered, nil).Times(1)
+		cc.EXPECT().QueryRegisteredFinalityProvider(slashed.BtcPk).Return(slashed, nil).Times(1)
+		cc.EXPECT().QueryRegisteredFinalityProvider(unregistered.BtcPk).Return(nil, nil).Times(1)
+
+		discovered, err := app.DiscoverFinalityProviders()
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			schnorrPkHex(registered.BtcPk),
+			schnorrPkHex(slashed.BtcPk),
+		}, discovered)
+
+		storedFp, err := s.GetFinalityProvider(registered.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, proto.FinalityProviderStatus_REGISTERED, storedFp.Status)
+		require.Equal(t, chainID, storedFp.ChainID)
+		require.Equal(t, "chain-key", storedFp.KeyName)
+		require.Equal(t, "discovered", storedFp.Description.Moniker)
+		require.Equal(t, registered.ChainPk, storedFp.ChainPk.Key)
+
+		storedFp, err = s.GetFinalityProvider(slashed.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, proto.FinalityProviderStatus_SLASHED, storedFp.Status)
+
+		_, err = s.GetFinalityProvider(unregistered.BtcPk)
+		require.Error(t, err)
+	})
+
+	t.Run("a failed query of the registration fails the discovery", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		em := mocks.NewMockEOTSManager(ctl)
+		app, s := newApp(t, cc, em)
+
+		registered := newRegistered(t)
+		em.EXPECT().ListKeys().Return([]*eotstypes.KeyInfo{
+			{Name: "registered", PubKey: schnorr.SerializePubKey(registered.BtcPk)},
+		}, nil).Times(1)
+		cc.EXPECT().QueryRegisteredFinalityProvider(registered.BtcPk).
+			Return(nil, errors.New("the consumer chain is unreachable")).Times(1)
+
+		_, err := app.DiscoverFinalityProviders()
+		require.Error(t, err)
+		_, err = s.GetFinalityProvider(registered.BtcPk)
+		require.Error(t, err)
+	})
+
+	t.Run("a registration without commission is rejected", func(t *testing.T) {
+		app, _ := newApp(t, nil, nil)
+		registered := newRegistered(t)
+		registered.Commission = nil
+
+		_, err := app.discoveredRecord(registered, chainID)
+		require.Error(t, err)
+	})
+}
+
+func schnorrPkHex(pk *btcec.PublicKey) string {
+	return hex.EncodeToString(schnorr.SerializePubKey(pk))
+}
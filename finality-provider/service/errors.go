@@ -4,4 +4,29 @@ import "errors"
 
 var (
 	ErrFinalityProviderShutDown = errors.New("the finality provider instance is shutting down")
+	ErrChainIDNotAllowed        = errors.New("the chain ID is not in the allowed chain IDs")
+	ErrConflictingBlockHash     = errors.New("the finality provider has signed a different block at the same height")
+	ErrEOTSKeyNotFound          = errors.New("the EOTS key of the finality provider is not found")
+	ErrStandbyMode              = errors.New("the daemon is a standby and does not run finality providers")
+	ErrObserverDisabled         = errors.New("the daemon does not run in observer mode")
+	ErrSafeModeEntered          = errors.New("the finality provider has entered safe mode")
+	ErrNotInSafeMode            = errors.New("the finality provider is not in safe mode")
+	ErrChainReset               = errors.New("the consumer chain is reset with the same chain ID")
+	ErrWatchOnly                = errors.New("the finality provider is watch-only")
+	ErrSignFinalityDisabled     = errors.New("the SignFinality RPC of the daemon is disabled")
+	ErrHeightAboveTip           = errors.New("the height is above the tip of the consumer chain")
+	ErrAlreadyVoted             = errors.New("the finality provider has already voted at the height")
+	ErrTestRPCDisabled          = errors.New("the test RPC of the daemon is disabled")
+	ErrStaticStartHeightTooLow  = errors.New("the static start height is lower than the lowest useful height")
+	ErrInvalidStateArchive      = errors.New("the state archive is invalid or the passphrase is wrong")
+	ErrStateArchiveTooLarge     = errors.New("the state archive is too large to import")
+	ErrAppNotStarted            = errors.New("the finality-provider app is not started")
+	ErrFinalityProviderNotFound = errors.New("no finality provider matches the query")
+	ErrAmbiguousQuery           = errors.New("more than one finality provider matches the query")
+	ErrLedgerChainKey           = errors.New("the chain key of a finality provider cannot be on a Ledger device as it signs the proof of possession")
+	ErrFpAlreadyRegistered      = errors.New("the finality provider is already registered on the consumer chain")
+	ErrPubRandUnavailable       = errors.New("the committed public randomness of the height is not available")
+	ErrLogTailDisabled          = errors.New("the logs of the daemon cannot be tailed")
+	ErrCloneDetected            = errors.New("another daemon is voting with the keys of the finality provider")
+	ErrClockSkewed              = errors.New("the local clock is skewed from the consumer chain")
 )
@@ -0,0 +1,73 @@
+package service
+
+import (
+	bbntypes "github.com/babylonchain/babylon/types"
+
+	"github.com/babylonchain/finality-provider/finality-provider/eventbus"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// FinalityProviderCreatedEvent is published on the event bus after a finality
+// provider is created
+type FinalityProviderCreatedEvent struct {
+	FpInfo *proto.FinalityProviderInfo
+}
+
+// FinalityProviderRegisteredEvent is published on the event bus after a
+// finality provider is registered on the consumer chain
+type FinalityProviderRegisteredEvent struct {
+	BtcPk  *bbntypes.BIP340PubKey
+	TxHash string
+}
+
+// EventBus returns the bus of the app, on which the subsystems embedding the
+// daemon can subscribe to FinalityProviderCreatedEvent,
+// FinalityProviderRegisteredEvent, and the *hooks.Event of the lifecycle of
+// the instances without touching the core handlers
+func (app *FinalityProviderApp) EventBus() *eventbus.Bus {
+	return app.bus
+}
+
+// subscribeHandlers subscribes the core handlers of the requests to the app
+// and the subsystems observing the events to the bus
+func (app *FinalityProviderApp) subscribeHandlers() {
+	eventbus.Subscribe(app.bus, func(req *createFinalityProviderRequest) {
+		res, err := app.handleCreateFinalityProviderRequest(req)
+		if err != nil {
+			req.errResponse <- err
+			return
+		}
+
+		req.successResponse <- res
+		eventbus.Publish(app.bus, &FinalityProviderCreatedEvent{FpInfo: res.FpInfo})
+	})
+	eventbus.Subscribe(app.bus, app.handleRegisterFinalityProviderRequest)
+	eventbus.Subscribe(app.bus, app.handleFinalityProviderRegisteredEvent)
+
+	// metrics
+	eventbus.Subscribe(app.bus, func(ev *FinalityProviderCreatedEvent) {
+		app.metrics.RecordFpStatus(ev.FpInfo.BtcPkHex, proto.FinalityProviderStatus_CREATED)
+	})
+	eventbus.Subscribe(app.bus, func(ev *FinalityProviderRegisteredEvent) {
+		app.metrics.RecordFpStatus(ev.BtcPk.MarshalHex(), proto.FinalityProviderStatus_REGISTERED)
+	})
+}
+
+// busHook publishes the lifecycle events of the instances on the bus
+type busHook struct {
+	bus *eventbus.Bus
+}
+
+func newBusHook(bus *eventbus.Bus) *busHook {
+	return &busHook{bus: bus}
+}
+
+func (h *busHook) Name() string {
+	return "event-bus"
+}
+
+func (h *busHook) OnEvent(e *hooks.Event) error {
+	eventbus.Publish(h.bus, e)
+	return nil
+}
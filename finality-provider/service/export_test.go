@@ -0,0 +1,104 @@
+package service
+
+import (
+	"time"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// ResolvePendingFinalitySigs exposes the resolution of the pending finality
+// signatures run on the start of the instance
+func (fp *FinalityProviderInstance) ResolvePendingFinalitySigs() error {
+	return fp.resolvePendingFinalitySigs()
+}
+
+// ProcessPendingBlocksNewestFirst buffers the given blocks but the first one
+// in the poller, and processes them newest first as the submission loop
+// does, backfilling the older blocks unless skipOlder is set
+func (fp *FinalityProviderInstance) ProcessPendingBlocksNewestFirst(blocks []*types.BlockInfo, skipOlder bool) {
+	fp.cfg.SkipOlderPendingHeights = skipOlder
+	if fp.poller == nil {
+		fp.poller = NewChainPoller(fp.logger, fp.cfg.PollerConfig, fp.cc, fp.metrics)
+	}
+	for _, b := range blocks[1:] {
+		fp.poller.blockInfoChan <- b
+	}
+
+	fp.processPendingBlocksNewestFirst(blocks[0])
+}
+
+// AddChainClient makes the finality providers of the given chain go through
+// the given client controller, as a chain section of the config does
+func (fpm *FinalityProviderManager) AddChainClient(chainID string, cc clientcontroller.ClientController) {
+	fpm.addChainClient(chainID, cc)
+}
+
+// DetectClones exposes a check of the votes for another daemon from the given
+// height, or from the tip if it is 0, and returns the next height to check
+func (app *FinalityProviderApp) DetectClones(nextHeight uint64) (uint64, error) {
+	d := &cloneDetector{chainID: app.config.BabylonConfig.ChainID, cc: app.cc, nextHeight: nextHeight}
+	err := app.fpManager.detectClones(d)
+
+	return d.nextHeight, err
+}
+
+// UnloadIdleInstances exposes the unload of the idle instances run by the
+// status update, where idleSince is the time each instance was first seen
+// INACTIVE
+func (app *FinalityProviderApp) UnloadIdleInstances(idleSince map[string]time.Time) {
+	app.fpManager.unloadIdleInstances(idleSince)
+}
+
+// ReloadActiveInstances exposes the reload of the unloaded instances which
+// regained voting power run by the status update
+func (app *FinalityProviderApp) ReloadActiveInstances() {
+	app.fpManager.reloadActiveInstances()
+}
+
+// IsFinalityProviderUnloaded exposes whether the instance of the finality
+// provider is unloaded as idle
+func (app *FinalityProviderApp) IsFinalityProviderUnloaded(fpPk *bbntypes.BIP340PubKey) bool {
+	return app.fpManager.IsFinalityProviderUnloaded(fpPk)
+}
+
+// NewSharedBlockSource exposes the block source shared by the pollers of the
+// instances of the same chain
+func NewSharedBlockSource(cc clientcontroller.ClientController, ttl time.Duration) clientcontroller.ClientController {
+	return newSharedBlockSource(cc, ttl)
+}
+
+// StateUpdate exposes the update of the state streamed to a standby daemon
+func (app *FinalityProviderApp) StateUpdate(nextHeights map[string]uint64, maxBlocks uint64) (*proto.SyncStateResponse, bool, error) {
+	return app.stateUpdate(nextHeights, maxBlocks)
+}
+
+// ApplyStateUpdate exposes the replication of an update of the state by a
+// standby daemon
+func (app *FinalityProviderApp) ApplyStateUpdate(update *proto.SyncStateResponse) error {
+	return app.applyStateUpdate(update)
+}
+
+// PollBlocks makes the poller take the given blocks as polled ones, buffering
+// them and moving its cursor past the last one
+func (cp *ChainPoller) PollBlocks(blocks []*types.BlockInfo) {
+	for _, b := range blocks {
+		cp.rememberBlock(b)
+		cp.blockInfoChan <- b
+		cp.nextHeight = b.Height + 1
+	}
+}
+
+// CheckReorg exposes the reorg check run before polling the next block
+func (cp *ChainPoller) CheckReorg() error {
+	return cp.checkReorg()
+}
+
+// DropBufferedBlocksAboveHeight exposes the drop of the stale blocks from the
+// buffer upon a reorg
+func (cp *ChainPoller) DropBufferedBlocksAboveHeight(height uint64) {
+	cp.dropBufferedBlocksAboveHeight(height)
+}
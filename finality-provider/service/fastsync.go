@@ -33,7 +33,7 @@ func (fp *FinalityProviderInstance) FastSync(startHeight, endHeight uint64) (*Fa
 	// we may need several rounds to catch-up as we need to limit
 	// the catch-up distance for each round to avoid memory overflow
 	for startHeight <= endHeight {
-		blocks, err := fp.cc.QueryBlocks(startHeight, endHeight, fp.cfg.FastSyncLimit)
+		blocks, err := fp.cc.QueryBlocks(startHeight, endHeight, fp.fastSyncLimit())
 		if err != nil {
 			return nil, err
 		}
@@ -53,6 +53,10 @@ func (fp *FinalityProviderInstance) FastSync(startHeight, endHeight uint64) (*Fa
 			if fp.hasProcessed(b) {
 				continue
 			}
+			// the chain rejects the finality signatures below its activation height
+			if fp.isBelowActivation(b) {
+				continue
+			}
 			// check whether the finality provider has voting power
 			hasVp, err := fp.hasVotingPower(b)
 			if err != nil {
@@ -9,6 +9,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/babylonchain/finality-provider/clientcontroller"
 	"github.com/babylonchain/finality-provider/testutil"
 	"github.com/babylonchain/finality-provider/types"
 )
@@ -27,7 +28,7 @@ func FuzzFastSync_SufficientRandomness(f *testing.F) {
 		currentHeight := finalizedHeight + uint64(r.Int63n(10)+1)
 		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
 		mockClientController.EXPECT().QueryLatestFinalizedBlocks(uint64(1)).Return(nil, nil).AnyTimes()
-		_, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
 		defer cleanUp()
 
 		// commit pub rand
@@ -54,14 +55,22 @@ func FuzzFastSync_SufficientRandomness(f *testing.F) {
 		mockClientController.EXPECT().QueryLatestFinalizedBlocks(uint64(1)).Return([]*types.BlockInfo{finalizedBlock}, nil).AnyTimes()
 		mockClientController.EXPECT().QueryBlocks(finalizedHeight+1, currentHeight, uint64(10)).
 			Return(catchUpBlocks, nil)
-		mockClientController.EXPECT().SubmitBatchFinalitySigs(fpIns.GetBtcPk(), catchUpBlocks, gomock.Any(), gomock.Any(), gomock.Any()).
-			Return(&types.TxResponse{TxHash: expectedTxHash}, nil).AnyTimes()
+		signedTx := &types.SignedTx{Bytes: datagen.GenRandomByteArray(r, 64), Hash: datagen.GenRandomByteArray(r, 32)}
+		mockClientController.EXPECT().SignBatchFinalitySigsTx(fpIns.GetBtcPk(), catchUpBlocks, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(signedTx, nil).Times(1)
+		mockClientController.EXPECT().BroadcastSignedTx(signedTx).
+			Return(&types.TxResponse{TxHash: expectedTxHash}, nil).Times(1)
 		result, err := fpIns.FastSync(finalizedHeight+1, currentHeight)
 		require.NoError(t, err)
 		require.NotNil(t, result)
 		require.Equal(t, expectedTxHash, result.Responses[0].TxHash)
 		require.Equal(t, currentHeight, fpIns.GetLastVotedHeight())
 		require.Equal(t, currentHeight, fpIns.GetLastProcessedHeight())
+
+		// the signed tx of the batch is no longer pending once broadcast
+		pendingSigs, err := app.GetFinalityProviderStore().GetPendingFinalitySigs(fpIns.GetBtcPk())
+		require.NoError(t, err)
+		require.Empty(t, pendingSigs)
 	})
 }
 
@@ -105,6 +114,10 @@ func FuzzFastSync_NoRandomness(f *testing.F) {
 		mockClientController.EXPECT().QueryLatestFinalizedBlocks(uint64(1)).Return([]*types.BlockInfo{finalizedBlock}, nil).AnyTimes()
 		mockClientController.EXPECT().QueryBlocks(finalizedHeight+1, currentHeight, uint64(10)).
 			Return(catchUpBlocks, nil)
+		// the batch is submitted by the client controller if it cannot sign
+		// the tx alone
+		mockClientController.EXPECT().SignBatchFinalitySigsTx(fpIns.GetBtcPk(), catchUpBlocks[:lastHeightWithPubRand-finalizedHeight], gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, clientcontroller.ErrSignedTxUnsupported).Times(1)
 		mockClientController.EXPECT().SubmitBatchFinalitySigs(fpIns.GetBtcPk(), catchUpBlocks[:lastHeightWithPubRand-finalizedHeight], gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(&types.TxResponse{TxHash: expectedTxHash}, nil).AnyTimes()
 		result, err := fpIns.FastSync(finalizedHeight+1, currentHeight)
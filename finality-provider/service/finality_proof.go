@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// maxFinalityProofBlocks is the maximum number of the blocks whose finality
+// proofs are exported at once, as each block takes a search of the txs
+const maxFinalityProofBlocks = 100
+
+// ExportFinalityProofs returns the finality proofs of the blocks from the
+// start height to the end height, or of the block at the start height only if
+// the end height is 0. A proof only includes the signatures on the block at
+// the height, i.e., not the equivocating ones, of the finality providers with
+// voting power at the height, so that light clients can verify the finality
+// of the block against the voting powers. The blocks are of the given consumer
+// chain, or of the default one if the chain ID is empty
+func (app *FinalityProviderApp) ExportFinalityProofs(chainID string, startHeight, endHeight uint64) ([]*proto.FinalityProof, error) {
+	if endHeight == 0 {
+		endHeight = startHeight
+	}
+	if startHeight == 0 || endHeight < startHeight {
+		return nil, fmt.Errorf("invalid range of heights [%d, %d]", startHeight, endHeight)
+	}
+	if endHeight-startHeight >= maxFinalityProofBlocks {
+		return nil, fmt.Errorf("the range of heights [%d, %d] exceeds the maximum of %d blocks",
+			startHeight, endHeight, maxFinalityProofBlocks)
+	}
+
+	chainID, err := app.fpManager.resolveChainID(chainID)
+	if err != nil {
+		return nil, err
+	}
+	cc := app.fpManager.ccOf(chainID)
+
+	blocks, err := cc.QueryBlocks(startHeight, endHeight, endHeight-startHeight+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the blocks: %w", err)
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrHeightAboveTip, startHeight)
+	}
+
+	proofs := make([]*proto.FinalityProof, 0, len(blocks))
+	for _, b := range blocks {
+		powers, err := cc.QueryVotingPowersAtHeight(b.Height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the voting powers at height %d: %w", b.Height, err)
+		}
+		sigs, err := cc.QueryFinalitySigsAtHeight(b.Height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query the finality signatures at height %d: %w", b.Height, err)
+		}
+
+		proof := &proto.FinalityProof{
+			Height:       b.Height,
+			BlockHashHex: hex.EncodeToString(b.Hash),
+			Finalized:    b.Finalized,
+		}
+		powerOf := make(map[string]uint64, len(powers))
+		for _, p := range powers {
+			powerOf[bbntypes.NewBIP340PubKeyFromBTCPK(p.FpBtcPk).MarshalHex()] = p.VotingPower
+			proof.TotalVotingPower += p.VotingPower
+		}
+
+		signed := make(map[string]struct{}, len(sigs))
+		for _, sig := range sigs {
+			pkHex := bbntypes.NewBIP340PubKeyFromBTCPK(sig.FpBtcPk).MarshalHex()
+			power := powerOf[pkHex]
+			if power == 0 || !bytes.Equal(sig.BlockHash, b.Hash) {
+				continue
+			}
+			if _, ok := signed[pkHex]; ok {
+				continue
+			}
+			signed[pkHex] = struct{}{}
+
+			proof.SignedVotingPower += power
+			proof.Sigs = append(proof.Sigs, &proto.FinalityProofSig{
+				BtcPkHex:       pkHex,
+				VotingPower:    power,
+				PubRandHex:     hex.EncodeToString(sig.PubRand),
+				FinalitySigHex: hex.EncodeToString(sig.Sig),
+				TxHash:         sig.TxHash,
+			})
+		}
+		sort.Slice(proof.Sigs, func(i, j int) bool {
+			return proof.Sigs[i].BtcPkHex < proof.Sigs[j].BtcPkHex
+		})
+
+		proofs = append(proofs, proof)
+	}
+
+	return proofs, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/hex"
+	"testing"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestExportFinalityProofs(t *testing.T) {
+	chainID := "chain-test"
+	genPk := func() *btcec.PublicKey {
+		sk, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+		return sk.PubKey()
+	}
+	signerPk, equivocatorPk, powerlessPk, absentPk := genPk(), genPk(), genPk(), genPk()
+	block := &types.BlockInfo{Height: 10, Hash: []byte("block-hash"), Finalized: true}
+
+	newApp := func(cc *mocks.MockClientController) *FinalityProviderApp {
+		cfg := &fpcfg.Config{BabylonConfig: &fpcfg.BBNConfig{ChainID: chainID}}
+		return &FinalityProviderApp{
+			config:    cfg,
+			fpManager: &FinalityProviderManager{config: cfg, cc: cc},
+		}
+	}
+
+	t.Run("only the signatures on the block with voting power are included", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		cc.EXPECT().QueryBlocks(uint64(10), uint64(10), uint64(1)).Return([]*types.BlockInfo{block}, nil).Times(1)
+		cc.EXPECT().QueryVotingPowersAtHeight(uint64(10)).Return([]*types.FinalityProviderPower{
+			{FpBtcPk: signerPk, VotingPower: 60},
+			{FpBtcPk: equivocatorPk, VotingPower: 30},
+			{FpBtcPk: absentPk, VotingPower: 10},
+		}, nil).Times(1)
+		signerSig := &types.FinalitySig{
+			FpBtcPk: signerPk, Height: 10, BlockHash: block.Hash,
+			PubRand: []byte("pub-rand"), Sig: []byte("sig"), TxHash: "tx-hash",
+		}
+		cc.EXPECT().QueryFinalitySigsAtHeight(uint64(10)).Return([]*types.FinalitySig{
+			signerSig,
+			// a signature is counted once
+			signerSig,
+			{FpBtcPk: equivocatorPk, Height: 10, BlockHash: []byte("other-hash")},
+			{FpBtcPk: powerlessPk, Height: 10, BlockHash: block.Hash},
+		}, nil).Times(1)
+
+		proofs, err := newApp(cc).ExportFinalityProofs("", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, proofs, 1)
+		proof := proofs[0]
+		require.Equal(t, uint64(10), proof.Height)
+		require.Equal(t, hex.EncodeToString(block.Hash), proof.BlockHashHex)
+		require.True(t, proof.Finalized)
+		require.Equal(t, uint64(100), proof.TotalVotingPower)
+		require.Equal(t, uint64(60), proof.SignedVotingPower)
+		require.Len(t, proof.Sigs, 1)
+		require.Equal(t, bbntypes.NewBIP340PubKeyFromBTCPK(signerPk).MarshalHex(), proof.Sigs[0].BtcPkHex)
+		require.Equal(t, uint64(60), proof.Sigs[0].VotingPower)
+		require.Equal(t, hex.EncodeToString([]byte("pub-rand")), proof.Sigs[0].PubRandHex)
+		require.Equal(t, hex.EncodeToString([]byte("sig")), proof.Sigs[0].FinalitySigHex)
+		require.Equal(t, "tx-hash", proof.Sigs[0].TxHash)
+	})
+
+	t.Run("a height above the tip is rejected", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		cc.EXPECT().QueryBlocks(uint64(20), uint64(21), uint64(2)).Return(nil, nil).Times(1)
+
+		_, err := newApp(cc).ExportFinalityProofs(chainID, 20, 21)
+		require.ErrorIs(t, err, ErrHeightAboveTip)
+	})
+
+	t.Run("an invalid range of heights is rejected", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		app := newApp(mocks.NewMockClientController(ctl))
+
+		_, err := app.ExportFinalityProofs("", 0, 0)
+		require.Error(t, err)
+		_, err = app.ExportFinalityProofs("", 10, 9)
+		require.Error(t, err)
+		_, err = app.ExportFinalityProofs("", 1, maxFinalityProofBlocks+1)
+		require.Error(t, err)
+		_, err = app.ExportFinalityProofs("chain-unknown", 10, 0)
+		require.Error(t, err)
+	})
+}
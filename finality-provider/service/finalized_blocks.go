@@ -0,0 +1,85 @@
+package service
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+const (
+	defaultFinalizedBlocksLimit = 10
+	maxFinalizedBlocksLimit     = 100
+)
+
+// QueryFinalizedBlocks returns the latest finalized blocks of the given
+// consumer chain, or of the default one if the chain ID is empty, each
+// annotated with the finality providers of the chain in the daemon that have
+// voted on it, so that operators can verify their votes take effect
+func (app *FinalityProviderApp) QueryFinalizedBlocks(chainID string, limit uint64) ([]*proto.FinalizedBlock, error) {
+	if limit == 0 {
+		limit = defaultFinalizedBlocksLimit
+	}
+	if limit > maxFinalizedBlocksLimit {
+		return nil, fmt.Errorf("the limit %d exceeds the maximum %d", limit, maxFinalizedBlocksLimit)
+	}
+
+	chainID, err := app.fpManager.resolveChainID(chainID)
+	if err != nil {
+		return nil, err
+	}
+	cc := app.fpManager.ccOf(chainID)
+
+	localFps, err := app.localFinalityProviders(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := cc.QueryLatestFinalizedBlocks(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the latest finalized blocks: %w", err)
+	}
+
+	finalizedBlocks := make([]*proto.FinalizedBlock, 0, len(blocks))
+	for _, b := range blocks {
+		votes, err := cc.QueryVotesAtHeight(b.Height)
+		if err != nil {
+			return nil, err
+		}
+
+		finalizedBlock := &proto.FinalizedBlock{
+			Height:       b.Height,
+			BlockHashHex: hex.EncodeToString(b.Hash),
+		}
+		for _, pk := range votes {
+			pkHex := pk.MarshalHex()
+			if _, ok := localFps[pkHex]; ok {
+				finalizedBlock.VotedBtcPks = append(finalizedBlock.VotedBtcPks, pkHex)
+			}
+		}
+		finalizedBlock.Contributed = len(finalizedBlock.VotedBtcPks) > 0
+
+		finalizedBlocks = append(finalizedBlocks, finalizedBlock)
+	}
+
+	return finalizedBlocks, nil
+}
+
+// localFinalityProviders returns the hex BTC public keys of the finality
+// providers of the given chain in the daemon
+func (app *FinalityProviderApp) localFinalityProviders(chainID string) (map[string]struct{}, error) {
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the stored finality providers: %w", err)
+	}
+
+	localFps := make(map[string]struct{}, len(storedFps))
+	for _, fp := range storedFps {
+		if fp.ChainID != chainID {
+			continue
+		}
+		localFps[fp.GetBIP340BTCPK().MarshalHex()] = struct{}{}
+	}
+
+	return localFps, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+
+	sdkmath "cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestQueryFinalizedBlocks(t *testing.T) {
+	chainID := "chain-test"
+	db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+
+	// a finality provider of the chain and one of another chain are run by
+	// the daemon, and another one of the chain is not
+	localPk := createTestFinalityProvider(t, s, chainID)
+	otherChainPk := createTestFinalityProvider(t, s, "chain-other")
+	remoteSk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	remotePk := bbntypes.NewBIP340PubKeyFromBTCPK(remoteSk.PubKey())
+
+	cc := mocks.NewMockClientController(gomock.NewController(t))
+	cc.EXPECT().QueryLatestFinalizedBlocks(uint64(defaultFinalizedBlocksLimit)).Return([]*types.BlockInfo{
+		{Height: 11, Hash: []byte{0x11}},
+		{Height: 10, Hash: []byte{0x10}},
+	}, nil).Times(1)
+	cc.EXPECT().QueryVotesAtHeight(uint64(11)).
+		Return([]bbntypes.BIP340PubKey{*remotePk, *localPk, *otherChainPk}, nil).Times(1)
+	cc.EXPECT().QueryVotesAtHeight(uint64(10)).
+		Return([]bbntypes.BIP340PubKey{*remotePk}, nil).Times(1)
+
+	app := &FinalityProviderApp{
+		fps: s,
+		fpManager: &FinalityProviderManager{
+			config: &fpcfg.Config{BabylonConfig: &fpcfg.BBNConfig{ChainID: chainID}},
+			cc:     cc,
+		},
+	}
+
+	blocks, err := app.QueryFinalizedBlocks("", 0)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	require.Equal(t, uint64(11), blocks[0].Height)
+	require.Equal(t, "11", blocks[0].BlockHashHex)
+	require.Equal(t, []string{localPk.MarshalHex()}, blocks[0].VotedBtcPks)
+	require.True(t, blocks[0].Contributed)
+	require.Equal(t, uint64(10), blocks[1].Height)
+	require.Empty(t, blocks[1].VotedBtcPks)
+	require.False(t, blocks[1].Contributed)
+
+	_, err = app.QueryFinalizedBlocks("", maxFinalizedBlocksLimit+1)
+	require.Error(t, err)
+	_, err = app.QueryFinalizedBlocks("chain-unknown", 0)
+	require.Error(t, err)
+}
+
+// createTestFinalityProvider stores a finality provider of the given chain
+// and returns its BTC public key
+func createTestFinalityProvider(t *testing.T, s *store.FinalityProviderStore, chainID string) *bbntypes.BIP340PubKey {
+	btcSk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	chainPk := secp256k1.GenPrivKey().PubKey().(*secp256k1.PubKey)
+	commission := sdkmath.LegacyZeroDec()
+
+	err = s.CreateFinalityProvider(chainPk, btcSk.PubKey(), &stakingtypes.Description{Moniker: chainID},
+		&commission, "key-"+chainID, chainID, []byte("chain sig"), []byte("btc sig"))
+	require.NoError(t, err)
+
+	return bbntypes.NewBIP340PubKeyFromBTCPK(btcSk.PubKey())
+}
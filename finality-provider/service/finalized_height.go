@@ -0,0 +1,103 @@
+package service
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// updateFinalizedHeight advances the finalized height, i.e., the durable
+// cursor of the processing, to the last processed height, which is capped by
+// the given height finalized on the consumer chain. The heights above it may
+// still be rolled back by the chain, so they are never skipped on restart.
+// The records of the blocks signed below it are pruned, as no conflicting
+// block can replace a finalized one, and the randomness below the retention
+// is shredded
+func (fp *FinalityProviderInstance) updateFinalizedHeight(chainFinalizedHeight uint64) {
+	height := min(fp.GetLastProcessedHeight(), chainFinalizedHeight)
+	if height <= fp.GetFinalizedHeight() {
+		return
+	}
+
+	if err := fp.fpState.setFinalizedHeight(height); err != nil {
+		fp.logger.Warn("failed to update the finalized height",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("finalized_height", height), zap.Error(err))
+		return
+	}
+
+	if err := fp.fpState.s.PruneSignedBlockHashes(fp.GetBtcPk(), height); err != nil {
+		fp.logger.Warn("failed to prune the signed blocks below the finalized height",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("finalized_height", height), zap.Error(err))
+	}
+
+	fp.shredRandomness(height)
+}
+
+// rewindToFinalizedHeight rewinds the last processed height to the finalized
+// height before the processing starts, so that the unfinalized heights
+// processed before the restart are processed again in case the chain has
+// rolled them back. The blocks already signed are not voted again
+func (fp *FinalityProviderInstance) rewindToFinalizedHeight() {
+	lastProcessedHeight := fp.GetLastProcessedHeight()
+	finalizedHeight := fp.GetFinalizedHeight()
+	if lastProcessedHeight <= finalizedHeight {
+		return
+	}
+
+	fp.logger.Info("processing the unfinalized heights again from the finalized height",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("finalized_height", finalizedHeight),
+		zap.Uint64("last_processed_height", lastProcessedHeight),
+	)
+	fp.fpState.rewindLastProcessedHeight(finalizedHeight)
+}
+
+// rewindToForkHeight rewinds the last processed height to the fork height if
+// the given block is the first one polled after a reorg of the consumer chain,
+// so that the heights replaced by the reorg are processed again. It is called
+// before the block is processed, i.e., after the blocks polled before the
+// reorg are processed. The blocks already signed are not voted again, and the
+// conflicting ones are refused
+func (fp *FinalityProviderInstance) rewindToForkHeight(b *types.BlockInfo) {
+	forkHeight, ok := fp.poller.TakeForkHeight(b)
+	if !ok || fp.GetLastProcessedHeight() <= forkHeight {
+		return
+	}
+
+	fp.logger.Info("processing the heights replaced by the reorg again from the fork height",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("fork_height", forkHeight),
+		zap.Uint64("last_processed_height", fp.GetLastProcessedHeight()),
+	)
+	fp.fpState.rewindLastProcessedHeight(forkHeight)
+}
+
+// finalizedHeightLoop periodically advances the finalized height as the
+// consumer chain finalizes the processed heights
+func (fp *FinalityProviderInstance) finalizedHeightLoop() {
+	defer fp.wg.Done()
+
+	ticker := time.NewTicker(fp.cfg.FinalizedHeightInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			finalizedBlocks, err := fp.latestFinalizedBlocksWithRetry(1)
+			if err != nil {
+				fp.logger.Debug("failed to query the latest finalized block",
+					zap.String("pk", fp.GetBtcPkHex()), zap.Error(err))
+				continue
+			}
+			if len(finalizedBlocks) == 0 {
+				continue
+			}
+			fp.updateFinalizedHeight(finalizedBlocks[0].Height)
+		case <-fp.quit:
+			fp.logger.Debug("the finalized height loop is closing")
+			return
+		}
+	}
+}
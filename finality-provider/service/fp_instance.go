@@ -1,8 +1,12 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +24,9 @@ import (
 	"github.com/babylonchain/finality-provider/clientcontroller"
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/maintenance"
+	"github.com/babylonchain/finality-provider/finality-provider/policy"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/store"
 	"github.com/babylonchain/finality-provider/metrics"
@@ -40,8 +47,44 @@ type FinalityProviderInstance struct {
 	poller  *ChainPoller
 	metrics *metrics.FpMetrics
 
+	// pollerMu guards starting the poller from the activation loop against
+	// stopping the instance
+	pollerMu sync.Mutex
+	// commitMu serializes the randomness commitments of the commitment loop
+	// and the ones forced through the RPC
+	commitMu sync.Mutex
+
+	// limiter caps the concurrent finality signature submissions, it is
+	// shared among the instances if they are run by a manager
+	limiter *submissionLimiter
+	// signingPolicy is evaluated before each finality signature if it is set
+	signingPolicy *policy.Engine
+	// submissionScript is run after the signing policy if it is set
+	submissionScript *policy.Script
+	// chainParams caches the on-chain parameters, it is shared among the
+	// instances if they are run by a manager
+	chainParams *chainParamsCache
+	// blockSource is the client controller the poller fetches the blocks
+	// through, which is shared among the instances of the same chain if they
+	// are run by a manager
+	blockSource clientcontroller.ClientController
+	// hooks receives the lifecycle events if it is set
+	hooks *hooks.Dispatcher
+	// voteSLO tracks the vote latency if it is set
+	voteSLO *voteSLOTracker
+	// maintenance is the schedule of the maintenance windows, ahead of which
+	// extra randomness is committed
+	maintenance *maintenance.Schedule
+	// chainHalt detects that the chain has stopped producing blocks, which
+	// is nil if the instance is not run by a manager or the detection is
+	// disabled
+	chainHalt *chainHaltDetector
+
 	// passphrase is used to unlock private keys
 	passphrase string
+	// staticStart is the static height after which the chain is polled,
+	// which is validated on the start of the instance
+	staticStart uint64
 
 	laggingTargetChan chan *types.BlockInfo
 	criticalErrChan   chan<- *CriticalError
@@ -49,9 +92,29 @@ type FinalityProviderInstance struct {
 	isStarted *atomic.Bool
 	inSync    *atomic.Bool
 	isLagging *atomic.Bool
+	// catchingUp is true if the instance is far behind the tip
+	catchingUp *atomic.Bool
+	// lowRandRunway is true if the randomness committed ahead of the tip is
+	// below the safety margin
+	lowRandRunway *atomic.Bool
+	// failures is the number of consecutive submissions failed with
+	// non-retryable errors
+	failures *atomic.Uint32
+	// statusBeforeStandby is the status restored once the halted chain
+	// resumes
+	statusBeforeStandby *atomic.Int32
 
 	wg   sync.WaitGroup
 	quit chan struct{}
+	// ctx is cancelled once the instance is stopped, which aborts the calls
+	// to the consumer chain in flight
+	ctxMu  sync.RWMutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	// draining is closed once the instance stops accepting new blocks on
+	// shutdown, after which drainWg tracks the in-flight submissions
+	draining chan struct{}
+	drainWg  sync.WaitGroup
 }
 
 // NewFinalityProviderInstance returns a FinalityProviderInstance instance with the given Babylon public key
@@ -78,7 +141,16 @@ func NewFinalityProviderInstance(
 		return nil, fmt.Errorf("the finality-provider %s has not been registered", sfp.KeyName)
 	}
 
-	return &FinalityProviderInstance{
+	if sfp.WatchOnly {
+		return nil, fmt.Errorf("%w: %s", ErrWatchOnly, fpPk.MarshalHex())
+	}
+
+	maintenanceSchedule, err := maintenance.NewSchedule(cfg.MaintenanceWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	fp := &FinalityProviderInstance{
 		btcPk:           bbntypes.NewBIP340PubKeyFromBTCPK(sfp.BtcPk),
 		chainPk:         sfp.ChainPk,
 		fpState:         NewFpState(sfp, s),
@@ -88,12 +160,39 @@ func NewFinalityProviderInstance(
 		isStarted:       atomic.NewBool(false),
 		inSync:          atomic.NewBool(false),
 		isLagging:       atomic.NewBool(false),
+		catchingUp:      atomic.NewBool(false),
+		lowRandRunway:   atomic.NewBool(false),
+		failures:        atomic.NewUint32(0),
 		criticalErrChan: errChan,
 		passphrase:      passphrase,
 		em:              em,
-		cc:              cc,
 		metrics:         metrics,
-	}, nil
+		limiter:         newSubmissionLimiter(cfg.MaxConcurrentSubmissions, cfg.SubmissionStagger),
+		chainParams:     newChainParamsCache(cc, cfg.ParamsRefreshInterval, logger),
+		maintenance:     maintenanceSchedule,
+		ctx:             context.Background(),
+		cancel:          func() {},
+	}
+	// a hung RPC must not wedge the goroutines of the instance
+	fp.cc = clientcontroller.NewDeadlineController(cc, cfg.ChainCallTimeout, fp.context)
+	// REGISTERED is updated by the status update once the chain resumes
+	fp.statusBeforeStandby = atomic.NewInt32(int32(proto.FinalityProviderStatus_REGISTERED))
+
+	return fp, nil
+}
+
+// context returns the context of the current run of the instance
+func (fp *FinalityProviderInstance) context() context.Context {
+	fp.ctxMu.RLock()
+	defer fp.ctxMu.RUnlock()
+
+	return fp.ctx
+}
+
+// queryRetryOpts are the options of the retried queries of the instance,
+// which stop retrying once the instance is stopped
+func (fp *FinalityProviderInstance) queryRetryOpts(onRetry retry.OnRetryFunc) []retry.Option {
+	return append(queryRetryOpts(fp.cfg.PollerConfig, onRetry), retry.Context(fp.context()))
 }
 
 func (fp *FinalityProviderInstance) Start() error {
@@ -103,6 +202,80 @@ func (fp *FinalityProviderInstance) Start() error {
 
 	fp.logger.Info("Starting finality-provider instance", zap.String("pk", fp.GetBtcPkHex()))
 
+	fp.ctxMu.Lock()
+	fp.ctx, fp.cancel = context.WithCancel(context.Background())
+	fp.ctxMu.Unlock()
+
+	// the pending finality signatures may be signed again, which waits on quit
+	fp.quit = make(chan struct{})
+	fp.draining = make(chan struct{})
+
+	if err := fp.resolvePendingFinalitySigs(); err != nil {
+		return fmt.Errorf("failed to resolve the pending finality signatures of %s: %w", fp.GetBtcPkHex(), err)
+	}
+
+	if !fp.cfg.PollerConfig.AutoChainScanningMode {
+		startHeight, corrected, err := fp.staticStartHeight()
+		if err != nil {
+			return err
+		}
+		if corrected {
+			fp.logger.Warn("raised the static start height to the lowest useful height",
+				zap.String("pk", fp.GetBtcPkHex()),
+				zap.Uint64("configured", fp.cfg.PollerConfig.StaticStartHeight(fp.GetBtcPkHex())),
+				zap.Uint64("corrected", startHeight))
+		}
+		fp.staticStart = startHeight
+	}
+
+	fp.poller = nil
+
+	activated, err := fp.isActivated()
+	if err != nil {
+		return err
+	}
+	if !activated {
+		fp.logger.Info("the finality-provider stays idle until its activation height",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("activation_height", fp.GetActivationHeight()))
+
+		fp.wg.Add(1)
+		go fp.activationLoop()
+
+		return nil
+	}
+
+	if err := fp.startVoting(); err != nil {
+		return err
+	}
+
+	fp.emitEvent(&hooks.Event{Type: hooks.EventInstanceStarted})
+
+	return nil
+}
+
+// startVotingUnlessStopped starts voting from the activation loop, which
+// must not race with the instance being stopped
+func (fp *FinalityProviderInstance) startVotingUnlessStopped() error {
+	fp.pollerMu.Lock()
+	defer fp.pollerMu.Unlock()
+
+	select {
+	case <-fp.draining:
+		return nil
+	default:
+	}
+
+	if err := fp.startVoting(); err != nil {
+		return err
+	}
+
+	fp.emitEvent(&hooks.Event{Type: hooks.EventInstanceStarted})
+
+	return nil
+}
+
+// startVoting starts polling blocks, voting and committing randomness
+func (fp *FinalityProviderInstance) startVoting() error {
 	startHeight, err := fp.bootstrap()
 	if err != nil {
 		return fmt.Errorf("failed to bootstrap the finality-provider %s: %w", fp.GetBtcPkHex(), err)
@@ -111,7 +284,11 @@ func (fp *FinalityProviderInstance) Start() error {
 	fp.logger.Info("the finality-provider has been bootstrapped",
 		zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", startHeight))
 
-	poller := NewChainPoller(fp.logger, fp.cfg.PollerConfig, fp.cc, fp.metrics)
+	blockSource := fp.blockSource
+	if blockSource == nil {
+		blockSource = fp.cc
+	}
+	poller := NewChainPoller(fp.logger.Named("poller"), fp.cfg.PollerConfig, blockSource, fp.metrics)
 
 	if err := poller.Start(startHeight + 1); err != nil {
 		return fmt.Errorf("failed to start the poller: %w", err)
@@ -121,24 +298,82 @@ func (fp *FinalityProviderInstance) Start() error {
 
 	fp.laggingTargetChan = make(chan *types.BlockInfo, 1)
 
-	fp.quit = make(chan struct{})
-
 	fp.wg.Add(1)
+	fp.drainWg.Add(1)
 	go fp.finalitySigSubmissionLoop()
 	fp.wg.Add(1)
+	fp.drainWg.Add(1)
 	go fp.randomnessCommitmentLoop()
 	fp.wg.Add(1)
 	go fp.checkLaggingLoop()
+	fp.wg.Add(1)
+	go fp.finalizedHeightLoop()
 
 	return nil
 }
 
+// isActivated returns true if the consumer chain has reached the activation
+// height of the finality provider
+func (fp *FinalityProviderInstance) isActivated() (bool, error) {
+	activationHeight := fp.GetActivationHeight()
+	if activationHeight == 0 {
+		return true, nil
+	}
+
+	latestBlock, err := fp.getLatestBlockWithRetry()
+	if err != nil {
+		return false, err
+	}
+
+	return latestBlock.Height >= activationHeight, nil
+}
+
+// activationLoop keeps the finality provider idle until the consumer chain
+// reaches its activation height, and then starts voting
+func (fp *FinalityProviderInstance) activationLoop() {
+	defer fp.wg.Done()
+
+	ticker := time.NewTicker(fp.cfg.PollerConfig.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			activated, err := fp.isActivated()
+			if err != nil {
+				fp.logger.Debug("failed to check the activation height",
+					zap.String("pk", fp.GetBtcPkHex()), zap.Error(err))
+				continue
+			}
+			if !activated {
+				continue
+			}
+
+			fp.logger.Info("the finality-provider has reached its activation height",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("activation_height", fp.GetActivationHeight()))
+
+			if err := fp.startVotingUnlessStopped(); err != nil {
+				fp.reportCriticalErr(err)
+				return
+			}
+
+			return
+		case <-fp.quit:
+			fp.logger.Info("the activation loop is closing")
+			return
+		}
+	}
+}
+
 func (fp *FinalityProviderInstance) bootstrap() (uint64, error) {
+	fp.rewindToFinalizedHeight()
+
 	latestBlock, err := fp.getLatestBlockWithRetry()
 	if err != nil {
 		return 0, err
 	}
 
+	fp.updateCatchUpMode(latestBlock)
 	if fp.checkLagging(latestBlock) {
 		_, err := fp.tryFastSync(latestBlock)
 		if err != nil && !clientcontroller.IsExpected(err) {
@@ -159,17 +394,35 @@ func (fp *FinalityProviderInstance) Stop() error {
 		return fmt.Errorf("the finality-provider %s has already stopped", fp.GetBtcPkHex())
 	}
 
-	if err := fp.poller.Stop(); err != nil {
-		return fmt.Errorf("failed to stop the poller: %w", err)
+	fp.logger.Info("stopping finality-provider instance", zap.String("pk", fp.GetBtcPkHex()))
+
+	// stop accepting new blocks. The poller is not started if the instance
+	// has not reached its activation height yet
+	fp.pollerMu.Lock()
+	if fp.poller != nil {
+		if err := fp.poller.Stop(); err != nil {
+			fp.pollerMu.Unlock()
+			return fmt.Errorf("failed to stop the poller: %w", err)
+		}
 	}
+	close(fp.draining)
+	fp.pollerMu.Unlock()
 
-	fp.logger.Info("stopping finality-provider instance", zap.String("pk", fp.GetBtcPkHex()))
+	fp.drainSubmissions()
 
 	close(fp.quit)
+	fp.ctxMu.RLock()
+	fp.cancel()
+	fp.ctxMu.RUnlock()
 	fp.wg.Wait()
 
+	// the blocks still buffered are voted on after the next start
+	fp.persistBufferedBlocks(fp.takeBlocksLeftInPoller())
+
 	fp.logger.Info("the finality-provider instance %s is successfully stopped", zap.String("pk", fp.GetBtcPkHex()))
 
+	fp.emitEvent(&hooks.Event{Type: hooks.EventInstanceStopped})
+
 	return nil
 }
 
@@ -179,71 +432,28 @@ func (fp *FinalityProviderInstance) IsRunning() bool {
 
 func (fp *FinalityProviderInstance) finalitySigSubmissionLoop() {
 	defer fp.wg.Done()
+	defer fp.drainWg.Done()
+
+	fp.resumeBufferedBlocks()
 
 	for {
+		// the blocks already buffered are not processed once draining
 		select {
-		case b := <-fp.poller.GetBlockInfoChan():
-			fp.logger.Debug(
-				"the finality-provider received a new block, start processing",
-				zap.String("pk", fp.GetBtcPkHex()),
-				zap.Uint64("height", b.Height),
-			)
-
-			// check whether the block has been processed before
-			if fp.hasProcessed(b) {
-				continue
-			}
-			// check whether the finality provider has voting power
-			hasVp, err := fp.hasVotingPower(b)
-			if err != nil {
-				fp.reportCriticalErr(err)
-				continue
-			}
-			if !hasVp {
-				// the finality provider does not have voting power
-				// and it will never will at this block
-				fp.MustSetLastProcessedHeight(b.Height)
-				fp.metrics.IncrementFpTotalBlocksWithoutVotingPower(fp.GetBtcPkHex())
-				continue
-			}
-			// check whether the randomness has been committed
-			// the retry will end if max retry times is reached
-			// or the target block is finalized
-			isFinalized, err := fp.retryCheckRandomnessUntilBlockFinalized(b)
-			if err != nil {
-				if !errors.Is(err, ErrFinalityProviderShutDown) {
-					fp.reportCriticalErr(err)
-				}
-				break
-			}
-			// the block is finalized, no need to submit finality signature
-			if isFinalized {
-				fp.MustSetLastProcessedHeight(b.Height)
-				continue
-			}
+		case <-fp.draining:
+			fp.logger.Info("the finality signature submission loop is drained")
+			return
+		default:
+		}
 
-			// use the copy of the block to avoid the impact to other receivers
-			nextBlock := *b
-			res, err := fp.retrySubmitFinalitySignatureUntilBlockFinalized(&nextBlock)
-			if err != nil {
-				fp.metrics.IncrementFpTotalFailedVotes(fp.GetBtcPkHex())
-				if !errors.Is(err, ErrFinalityProviderShutDown) {
-					fp.reportCriticalErr(err)
-				}
-				continue
-			}
-			if res == nil {
-				// this can happen when a finality signature is not needed
-				// either if the block is already submitted or the signature
-				// is already submitted
+		select {
+		case b := <-fp.poller.GetBlockInfoChan():
+			fp.metrics.RecordFpBlockQueueDepth(fp.GetBtcPkHex(), len(fp.poller.GetBlockInfoChan()))
+			fp.rewindToForkHeight(b)
+			if fp.cfg.SubmissionOrder == fpcfg.SubmissionOrderNewestFirst || fp.catchingUp.Load() {
+				fp.processPendingBlocksNewestFirst(b)
 				continue
 			}
-			fp.logger.Info(
-				"successfully submitted a finality signature to the consumer chain",
-				zap.String("pk", fp.GetBtcPkHex()),
-				zap.Uint64("height", b.Height),
-				zap.String("tx_hash", res.TxHash),
-			)
+			fp.processBlock(b)
 
 		case targetBlock := <-fp.laggingTargetChan:
 			res, err := fp.tryFastSync(targetBlock)
@@ -279,15 +489,274 @@ func (fp *FinalityProviderInstance) finalitySigSubmissionLoop() {
 					)
 				}
 			}
+		case <-fp.draining:
+			fp.logger.Info("the finality signature submission loop is drained")
+			return
+		}
+	}
+}
+
+// drainSubmissions waits for the in-flight submissions of finality signatures
+// and randomness to finish within the drain timeout, after which they are
+// aborted. A finality signature being broadcast is persisted as pending, so
+// that it is resolved on the next start even if it is aborted, and the blocks
+// not voted on yet are persisted once the instance is stopped
+func (fp *FinalityProviderInstance) drainSubmissions() {
+	drained := make(chan struct{})
+	go func() {
+		fp.drainWg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(fp.cfg.ShutdownDrainTimeout):
+		fp.logger.Warn("the in-flight submissions are not finished within the drain timeout, aborting them",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Duration("timeout", fp.cfg.ShutdownDrainTimeout))
+	}
+}
+
+// processBlock votes on the given block if the finality provider has voting
+// power and committed randomness at its height. It returns false if the block
+// can be neither voted on nor skipped, e.g., on a failed submission
+func (fp *FinalityProviderInstance) processBlock(b *types.BlockInfo) bool {
+	fp.logger.Debug(
+		"the finality-provider received a new block, start processing",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("height", b.Height),
+	)
+
+	// check whether the block has been processed before
+	if fp.hasProcessed(b) {
+		return true
+	}
+	// the unfinalized heights are processed again after a restart, whose
+	// blocks are not voted again if they are not rolled back
+	if fp.hasSignedBlock(b) {
+		fp.MustSetLastProcessedHeight(b.Height)
+		return true
+	}
+	// the chain rejects the finality signatures below its activation height
+	if fp.isBelowActivation(b) {
+		fp.MustSetLastProcessedHeight(b.Height)
+		return true
+	}
+	// check whether the finality provider has voting power
+	hasVp, err := fp.hasVotingPower(b)
+	if err != nil {
+		fp.reportCriticalErr(err)
+		return false
+	}
+	if !hasVp {
+		// the finality provider does not have voting power
+		// and it will never will at this block
+		fp.MustSetLastProcessedHeight(b.Height)
+		fp.metrics.IncrementFpTotalBlocksWithoutVotingPower(fp.GetBtcPkHex())
+		return true
+	}
+	// check whether the randomness has been committed
+	// the retry will end if max retry times is reached
+	// or the target block is finalized
+	isFinalized, err := fp.retryCheckRandomnessUntilBlockFinalized(b)
+	if err != nil {
+		if !errors.Is(err, ErrFinalityProviderShutDown) {
+			fp.reportCriticalErr(err)
+		}
+		return false
+	}
+	// the block is finalized, no need to submit finality signature
+	if isFinalized {
+		fp.MustSetLastProcessedHeight(b.Height)
+		fp.updateFinalizedHeight(b.Height)
+		return true
+	}
+
+	// use the copy of the block to avoid the impact to other receivers
+	nextBlock := *b
+	res, err := fp.retrySubmitFinalitySignatureUntilBlockFinalized(&nextBlock)
+	if err != nil {
+		if errors.Is(err, ErrConflictingBlockHash) {
+			// the consumer chain has reorganized after the finality provider
+			// voted at this height, so the new block must not be signed
+			fp.logger.Error("refused to sign a conflicting block",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", b.Height), zap.Error(err))
+			fp.MustSetLastProcessedHeight(b.Height)
+			return true
+		}
+		if errors.Is(err, policy.ErrSigningDenied) {
+			fp.logger.Warn("the signing policy denied the finality signature",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", b.Height), zap.Error(err))
+			fp.MustSetLastProcessedHeight(b.Height)
+			return true
+		}
+		if errors.Is(err, ErrPubRandUnavailable) {
+			// nothing is signed at this height, which can never be voted
+			// without the proof of the committed randomness
+			fp.logger.Error("skipped the height without committed public randomness",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", b.Height), zap.Error(err))
+			fp.metrics.IncrementFpTotalFailedVotes(fp.GetBtcPkHex())
+			fp.emitEvent(&hooks.Event{Type: hooks.EventError, Height: b.Height, Error: err.Error()})
+			fp.MustSetLastProcessedHeight(b.Height)
+			return true
+		}
+		fp.metrics.IncrementFpTotalFailedVotes(fp.GetBtcPkHex())
+		if !errors.Is(err, ErrFinalityProviderShutDown) && !fp.handleSubmissionFailure(err) {
+			fp.reportCriticalErr(err)
+		}
+		return false
+	}
+	if res == nil {
+		// this can happen when a finality signature is not needed
+		// either if the block is already submitted or the signature
+		// is already submitted
+		return true
+	}
+	fp.logger.Info(
+		"successfully submitted a finality signature to the consumer chain",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("height", b.Height),
+		zap.String("tx_hash", res.TxHash),
+	)
+
+	return true
+}
+
+// processPendingBlocksNewestFirst votes on the newest block among the given
+// block and the blocks buffered in the poller, and then backfills the older
+// ones from the newest to the oldest unless skipping is configured
+func (fp *FinalityProviderInstance) processPendingBlocksNewestFirst(b *types.BlockInfo) {
+	pending := fp.drainPendingBlocks(b)
+	newest := pending[len(pending)-1]
+	older := pending[:len(pending)-1]
+
+	if !fp.processBlock(newest) {
+		// the older blocks are not voted on while the vote on the newest one,
+		// which the finality provider needs most, is failing
+		if len(older) > 0 {
+			fp.logger.Warn("stopped the backfill of the older pending heights as the newest one failed",
+				zap.String("pk", fp.GetBtcPkHex()),
+				zap.Uint64("height", newest.Height),
+				zap.Uint64("start_height", older[0].Height),
+				zap.Uint64("end_height", older[len(older)-1].Height),
+			)
+		}
+		return
+	}
+
+	if len(older) == 0 {
+		return
+	}
+
+	if fp.cfg.SkipOlderPendingHeights {
+		fp.logger.Info(
+			"skipped older pending heights after voting on the newest one",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("start_height", older[0].Height),
+			zap.Uint64("end_height", older[len(older)-1].Height),
+		)
+		return
+	}
+
+	for i := len(older) - 1; i >= 0; i-- {
+		select {
 		case <-fp.quit:
-			fp.logger.Info("the finality signature submission loop is closing")
+			// the backfill is aborted by the drain timeout
+			fp.persistBufferedBlocks(older[:i+1])
 			return
+		default:
+		}
+		fp.backfillBlock(older[i])
+	}
+}
+
+// drainPendingBlocks returns the given block together with the blocks that
+// are already buffered in the poller, in the ascending order of height. At
+// most cap(blockChan) blocks are drained besides the given one, as the poller
+// keeps filling the buffer while it is drained. The blocks polled in the
+// meantime are left buffered for the next round of the submission loop
+func (fp *FinalityProviderInstance) drainPendingBlocks(b *types.BlockInfo) []*types.BlockInfo {
+	blockChan := fp.poller.GetBlockInfoChan()
+	pending := []*types.BlockInfo{b}
+	for len(pending) <= cap(blockChan) {
+		select {
+		case next := <-blockChan:
+			fp.rewindToForkHeight(next)
+			pending = append(pending, next)
+		default:
+			return pending
 		}
 	}
+
+	return pending
+}
+
+// backfillBlock votes on a block that is lower than the last processed height
+// because a newer block was prioritised. The state of the finality provider is
+// not updated as it already reflects the newer block. A backfill is best-effort
+// and is given up if the block is finalized or the randomness is missing
+func (fp *FinalityProviderInstance) backfillBlock(b *types.BlockInfo) {
+	hasVp, err := fp.hasVotingPower(b)
+	if err != nil || !hasVp {
+		return
+	}
+	hasRand, err := fp.hasRandomness(b)
+	if err != nil || !hasRand {
+		return
+	}
+	finalized, err := fp.checkBlockFinalization(b.Height)
+	if err != nil || finalized {
+		return
+	}
+
+	// use the copy of the block to avoid the impact to other receivers
+	backfilledBlock := *b
+	res, err := fp.sendFinalitySignature(&backfilledBlock)
+	if err != nil {
+		if clientcontroller.IsExpected(err) {
+			// the height has been voted before, e.g., by fast sync
+			return
+		}
+		if errors.Is(err, ErrConflictingBlockHash) {
+			fp.logger.Error("refused to backfill a conflicting block",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", b.Height), zap.Error(err))
+			return
+		}
+		if errors.Is(err, policy.ErrSigningDenied) || errors.Is(err, policy.ErrSigningDeferred) {
+			fp.logger.Info("the signing policy rejected the backfill",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", b.Height), zap.Error(err))
+			return
+		}
+		fp.metrics.IncrementFpTotalFailedVotes(fp.GetBtcPkHex())
+		if clientcontroller.IsUnrecoverable(err) {
+			fp.reportCriticalErr(err)
+			return
+		}
+		fp.logger.Debug(
+			"failed to backfill finality signature",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("height", b.Height),
+			zap.Error(err),
+		)
+		return
+	}
+
+	fp.metrics.RecordFpVoteTime(fp.GetBtcPkHex())
+	fp.metrics.IncrementFpTotalVotedBlocks(fp.GetBtcPkHex())
+
+	fp.logger.Info(
+		"successfully backfilled a finality signature to the consumer chain",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("height", b.Height),
+		zap.String("tx_hash", res.TxHash),
+	)
 }
 
 func (fp *FinalityProviderInstance) randomnessCommitmentLoop() {
 	defer fp.wg.Done()
+	defer fp.drainWg.Done()
+
+	// fill the gaps left by an outdated state before committing more
+	fp.repairRandGaps()
 
 	commitRandTicker := time.NewTicker(fp.cfg.RandomnessCommitInterval)
 	defer commitRandTicker.Stop()
@@ -295,6 +764,13 @@ func (fp *FinalityProviderInstance) randomnessCommitmentLoop() {
 	for {
 		select {
 		case <-commitRandTicker.C:
+			// the committed randomness is not consumed while the chain is
+			// halted, whose commits would only fail
+			if fp.isChainHalted() {
+				fp.logger.Debug("skipping the randomness commitment as the chain is halted",
+					zap.String("pk", fp.GetBtcPkHex()))
+				continue
+			}
 			tipBlock, err := fp.getLatestBlockWithRetry()
 			if err != nil {
 				fp.reportCriticalErr(err)
@@ -303,7 +779,9 @@ func (fp *FinalityProviderInstance) randomnessCommitmentLoop() {
 			txRes, err := fp.retryCommitPubRandUntilBlockFinalized(tipBlock)
 			if err != nil {
 				fp.metrics.IncrementFpTotalFailedRandomness(fp.GetBtcPkHex())
-				fp.reportCriticalErr(err)
+				if !fp.handleSubmissionFailure(err) {
+					fp.reportCriticalErr(err)
+				}
 				continue
 			}
 			// txRes could be nil if no need to commit more randomness
@@ -315,8 +793,8 @@ func (fp *FinalityProviderInstance) randomnessCommitmentLoop() {
 				)
 			}
 
-		case <-fp.quit:
-			fp.logger.Info("the randomness commitment loop is closing")
+		case <-fp.draining:
+			fp.logger.Info("the randomness commitment loop is drained")
 			return
 		}
 	}
@@ -351,6 +829,7 @@ func (fp *FinalityProviderInstance) checkLaggingLoop() {
 				continue
 			}
 
+			fp.updateCatchUpMode(latestBlock)
 			if fp.checkLagging(latestBlock) {
 				fp.isLagging.Store(true)
 				fp.laggingTargetChan <- latestBlock
@@ -382,6 +861,7 @@ func (fp *FinalityProviderInstance) tryFastSync(targetBlock *types.BlockInfo) (*
 	}
 
 	lastFinalizedHeight := lastFinalizedBlocks[0].Height
+	fp.updateFinalizedHeight(lastFinalizedHeight)
 	lastProcessedHeight := fp.GetLastProcessedHeight()
 
 	// get the startHeight from the maximum of the lastVotedHeight and
@@ -416,6 +896,28 @@ func (fp *FinalityProviderInstance) hasProcessed(b *types.BlockInfo) bool {
 	return false
 }
 
+// isBelowActivation returns true if the block is below the finality
+// activation height of the chain. If the height cannot be queried, the block
+// is processed as usual, leaving the check to the chain
+func (fp *FinalityProviderInstance) isBelowActivation(b *types.BlockInfo) bool {
+	activatedHeight, err := fp.chainParams.activatedHeight()
+	if err != nil {
+		fp.logger.Debug("failed to get the finality activation height",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Error(err))
+		return false
+	}
+
+	if b.Height < activatedHeight {
+		fp.logger.Debug("skipping a block below the finality activation height",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("height", b.Height),
+			zap.Uint64("activated_height", activatedHeight))
+		return true
+	}
+
+	return false
+}
+
 func (fp *FinalityProviderInstance) hasVotingPower(b *types.BlockInfo) (bool, error) {
 	power, err := fp.GetVotingPowerWithRetry(b.Height)
 	if err != nil {
@@ -453,12 +955,23 @@ func (fp *FinalityProviderInstance) hasRandomness(b *types.BlockInfo) (bool, err
 }
 
 func (fp *FinalityProviderInstance) reportCriticalErr(err error) {
+	fp.emitEvent(&hooks.Event{Type: hooks.EventError, Error: err.Error()})
 	fp.criticalErrChan <- &CriticalError{
 		err:     err,
 		fpBtcPk: fp.GetBtcPkBIP340(),
 	}
 }
 
+// emitEvent delivers the lifecycle event of the instance to the hooks, if any
+func (fp *FinalityProviderInstance) emitEvent(e *hooks.Event) {
+	if fp.hooks == nil {
+		return
+	}
+
+	e.FpBtcPk = fp.GetBtcPkHex()
+	fp.hooks.Dispatch(e)
+}
+
 // checkLagging returns true if the lasted voted height is behind by a configured gap
 func (fp *FinalityProviderInstance) checkLagging(currentBlock *types.BlockInfo) bool {
 	return currentBlock.Height >= fp.GetLastProcessedHeight()+fp.cfg.FastSyncGap
@@ -490,9 +1003,13 @@ func (fp *FinalityProviderInstance) retryCheckRandomnessUntilBlockFinalized(targ
 				zap.Error(err),
 			)
 
-			numRetries += 1
-			if numRetries > uint32(fp.cfg.MaxSubmissionRetries) {
-				return false, fmt.Errorf("reached max failed cycles with err: %w", err)
+			// the checks are retried without counting while the chain is
+			// halted, until it resumes
+			if !fp.isChainHalted() {
+				numRetries += 1
+				if numRetries > uint32(fp.cfg.MaxSubmissionRetries) {
+					return false, fmt.Errorf("reached max failed cycles with err: %w", err)
+				}
 			}
 		} else if !hasRand {
 			fp.logger.Debug(
@@ -502,9 +1019,11 @@ func (fp *FinalityProviderInstance) retryCheckRandomnessUntilBlockFinalized(targ
 				zap.Uint64("target_block_height", targetBlock.Height),
 			)
 
-			numRetries += 1
-			if numRetries > uint32(fp.cfg.MaxSubmissionRetries) {
-				return false, fmt.Errorf("reached max retries but randomness still not existed")
+			if !fp.isChainHalted() {
+				numRetries += 1
+				if numRetries > uint32(fp.cfg.MaxSubmissionRetries) {
+					return false, fmt.Errorf("reached max retries but randomness still not existed")
+				}
 			}
 		} else {
 			// the randomness has been successfully committed
@@ -543,6 +1062,14 @@ func (fp *FinalityProviderInstance) retrySubmitFinalitySignatureUntilBlockFinali
 	// we break the for loop if the block is finalized or the signature is successfully submitted
 	// error will be returned if maximum retries have been reached or the query to the consumer chain fails
 	for {
+		// nothing is broadcast while the chain is halted, and the
+		// submission is retried once it resumes
+		if fp.isChainHalted() {
+			if err := fp.waitChainResumed(); err != nil {
+				return nil, err
+			}
+			continue
+		}
 		// error will be returned if max retries have been reached
 		res, err := fp.SubmitFinalitySignature(targetBlock)
 		if err != nil {
@@ -555,7 +1082,8 @@ func (fp *FinalityProviderInstance) retrySubmitFinalitySignatureUntilBlockFinali
 				zap.Error(err),
 			)
 
-			if clientcontroller.IsUnrecoverable(err) {
+			if clientcontroller.IsUnrecoverable(err) || errors.Is(err, ErrConflictingBlockHash) ||
+				errors.Is(err, ErrPubRandUnavailable) {
 				return nil, err
 			}
 
@@ -563,9 +1091,17 @@ func (fp *FinalityProviderInstance) retrySubmitFinalitySignatureUntilBlockFinali
 				return nil, nil
 			}
 
-			failedCycles += 1
-			if failedCycles > uint32(fp.cfg.MaxSubmissionRetries) {
-				return nil, fmt.Errorf("reached max failed cycles with err: %w", err)
+			if errors.Is(err, policy.ErrSigningDenied) {
+				return nil, err
+			}
+
+			// a deferred signature is retried without counting as a failure,
+			// until the block is finalized
+			if !errors.Is(err, policy.ErrSigningDeferred) {
+				failedCycles += 1
+				if failedCycles > uint32(fp.cfg.MaxSubmissionRetries) {
+					return nil, fmt.Errorf("reached max failed cycles with err: %w", err)
+				}
 			}
 		} else {
 			// the signature has been successfully submitted
@@ -613,6 +1149,12 @@ func (fp *FinalityProviderInstance) retryCommitPubRandUntilBlockFinalized(target
 	// we break the for loop if the block is finalized or the public rand is successfully committed
 	// error will be returned if maximum retries have been reached or the query to the consumer chain fails
 	for {
+		if fp.isChainHalted() {
+			if err := fp.waitChainResumed(); err != nil {
+				return nil, nil
+			}
+			continue
+		}
 		// error will be returned if max retries have been reached
 		// TODO: CommitPubRand also includes saving all inclusion proofs of public randomness
 		// this part should not be retried here. We need to separate the function into
@@ -670,16 +1212,23 @@ func (fp *FinalityProviderInstance) retryCommitPubRandUntilBlockFinalized(target
 // commits the public randomness for the managed finality providers,
 // and save the randomness pair to DB
 func (fp *FinalityProviderInstance) CommitPubRand(tipHeight uint64) (*types.TxResponse, error) {
+	fp.commitMu.Lock()
+	defer fp.commitMu.Unlock()
+
 	lastCommittedHeight, err := fp.GetLastCommittedHeight()
 	if err != nil {
 		return nil, err
 	}
+	fp.recordRandRunway(lastCommittedHeight, tipHeight)
+
+	minRandHeightGap, preparing := fp.randHeightGap()
+	numPubRand := fp.cfg.NumPubRand
 
 	var startHeight uint64
 	if lastCommittedHeight == uint64(0) {
 		// the finality-provider has never submitted public rand before
 		startHeight = tipHeight + 1
-	} else if lastCommittedHeight < fp.cfg.MinRandHeightGap+tipHeight {
+	} else if lastCommittedHeight < minRandHeightGap+tipHeight {
 		// (should not use subtraction because they are in the type of uint64)
 		// we are running out of the randomness
 		startHeight = lastCommittedHeight + 1
@@ -693,77 +1242,343 @@ func (fp *FinalityProviderInstance) CommitPubRand(tipHeight uint64) (*types.TxRe
 		return nil, nil
 	}
 
+	// ahead of a maintenance window, commit enough randomness to reach the
+	// raised gap at once if possible
+	if targetHeight := tipHeight + minRandHeightGap; preparing && targetHeight >= startHeight+numPubRand {
+		numPubRand = targetHeight - startHeight + 1
+		if fp.cfg.NumPubRandMax > 0 && numPubRand > fp.cfg.NumPubRandMax {
+			numPubRand = fp.cfg.NumPubRandMax
+		}
+	}
+
+	// the commit would be rejected by the chain anyway
+	if err := fp.chainParams.checkNumPubRand(numPubRand); err != nil {
+		return nil, err
+	}
+
+	if err := fp.seedRandomness(startHeight); err != nil {
+		return nil, err
+	}
+
+	res, numPubRand, err := fp.commitPubRandChunked(startHeight, numPubRand)
+	if err != nil {
+		return nil, err
+	}
+
+	fp.onPubRandCommitted(res, lastCommittedHeight, startHeight, numPubRand, tipHeight)
+
+	return res, nil
+}
+
+// onPubRandCommitted emits the event and updates the metrics once the public
+// randomness of the given range is committed
+func (fp *FinalityProviderInstance) onPubRandCommitted(
+	res *types.TxResponse,
+	lastCommittedHeight, startHeight, numPubRand, tipHeight uint64,
+) {
+	fp.resetSubmissionFailures()
+	fp.emitEvent(&hooks.Event{Type: hooks.EventPubRandCommitted, Height: startHeight, NumPubRand: numPubRand, TxHash: res.TxHash})
+
+	// Update metrics
+	fp.metrics.RecordFpRandomnessTime(fp.GetBtcPkHex())
+	fp.metrics.RecordFpLastCommittedRandomnessHeight(fp.GetBtcPkHex(), lastCommittedHeight)
+	fp.metrics.AddToFpTotalCommittedRandomness(fp.GetBtcPkHex(), float64(numPubRand))
+	fp.recordRandRunway(startHeight+numPubRand-1, tipHeight)
+}
+
+// commitPubRandRange generates, stores and commits the public randomness of
+// the given range of heights, and returns the number of public randomness
+// committed
+func (fp *FinalityProviderInstance) commitPubRandRange(startHeight, numPubRand uint64) (*types.TxResponse, uint64, error) {
 	// generate a list of Schnorr randomness pairs
 	// NOTE: currently, calling this will create and save a list of randomness
 	// in case of failure, randomness that has been created will be overwritten
 	// for safety reason as the same randomness must not be used twice
-	pubRandList, err := fp.getPubRandList(startHeight, fp.cfg.NumPubRand)
+	pubRandList, err := fp.getPubRandList(startHeight, numPubRand)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate randomness: %w", err)
+		return nil, 0, fmt.Errorf("failed to generate randomness: %w", err)
 	}
-	numPubRand := uint64(len(pubRandList))
+	numPubRand = uint64(len(pubRandList))
 
 	// generate commitment and proof for each public randomness
 	commitment, proofList := types.GetPubRandCommitAndProofs(pubRandList)
 
 	// store them to database
 	if err := fp.pubRandState.AddPubRandProofList(pubRandList, proofList); err != nil {
-		return nil, fmt.Errorf("failed to save public randomness to DB: %w", err)
+		return nil, 0, fmt.Errorf("failed to save public randomness to DB: %w", err)
 	}
 
 	// sign the commitment
 	schnorrSig, err := fp.signPubRandCommit(startHeight, numPubRand, commitment)
 	if err != nil {
-		return nil, fmt.Errorf("failed to sign the Schnorr signature: %w", err)
+		return nil, 0, fmt.Errorf("failed to sign the Schnorr signature: %w", err)
 	}
 
 	res, err := fp.cc.CommitPubRandList(fp.GetBtcPk(), startHeight, numPubRand, commitment, schnorrSig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to commit public randomness to the consumer chain: %w", err)
+		return nil, 0, fmt.Errorf("failed to commit public randomness to the consumer chain: %w", err)
 	}
 
-	// Update metrics
-	fp.metrics.RecordFpRandomnessTime(fp.GetBtcPkHex())
-	fp.metrics.RecordFpLastCommittedRandomnessHeight(fp.GetBtcPkHex(), lastCommittedHeight)
-	fp.metrics.AddToFpTotalCommittedRandomness(fp.GetBtcPkHex(), float64(len(pubRandList)))
+	return res, numPubRand, nil
+}
 
-	return res, nil
+// randHeightGap returns the minimum gap between the last committed height and
+// the tip height, below which more randomness is committed, and whether it is
+// raised ahead of a maintenance window so that the randomness lasts through it.
+// Within the window, only the randomness needed to keep voting is committed
+func (fp *FinalityProviderInstance) randHeightGap() (uint64, bool) {
+	now := time.Now()
+	if fp.maintenance.InWindow(now) || !fp.maintenance.Upcoming(now, fp.cfg.MaintenanceLeadTime) {
+		return fp.cfg.MinRandHeightGap, false
+	}
+
+	fp.logger.Debug("preparing the randomness for an upcoming maintenance window",
+		zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("rand_height_gap", fp.cfg.MaintenanceRandHeightGap))
+
+	return fp.cfg.MaintenanceRandHeightGap, true
 }
 
 // SubmitFinalitySignature builds and sends a finality signature over the given block to the consumer chain
 func (fp *FinalityProviderInstance) SubmitFinalitySignature(b *types.BlockInfo) (*types.TxResponse, error) {
-	sig, err := fp.signFinalitySig(b)
+	res, err := fp.sendFinalitySignature(b)
 	if err != nil {
 		return nil, err
 	}
 
-	// get public randomness at the height
-	prList, err := fp.getPubRandList(b.Height, 1)
+	// update DB
+	fp.MustUpdateStateAfterFinalitySigSubmission(b.Height)
+
+	fp.resetSubmissionFailures()
+	fp.recordVoteSubmission(res, b)
+	fp.emitEvent(&hooks.Event{Type: hooks.EventVoteSubmitted, Height: b.Height, TxHash: res.TxHash})
+	fp.recordVoteLatency(b)
+
+	// update metrics
+	fp.metrics.RecordFpVoteTime(fp.GetBtcPkHex())
+	fp.metrics.IncrementFpTotalVotedBlocks(fp.GetBtcPkHex())
+
+	return res, nil
+}
+
+// recordVoteSubmission records the tx including the finality signatures over
+// the given blocks, so that whether a vote was submitted can be settled
+// locally. The votes are already submitted, so a failure is only logged
+func (fp *FinalityProviderInstance) recordVoteSubmission(res *types.TxResponse, blocks ...*types.BlockInfo) {
+	now := time.Now().Unix()
+	subs := make([]*proto.VoteSubmission, 0, len(blocks))
+	for _, b := range blocks {
+		subs = append(subs, &proto.VoteSubmission{
+			BtcPk:           fp.btcPk.MustMarshal(),
+			Height:          b.Height,
+			TxHash:          res.TxHash,
+			InclusionHeight: res.Height,
+			SubmittedAt:     now,
+		})
+	}
+
+	if err := fp.fpState.s.SaveVoteSubmissions(subs); err != nil {
+		fp.logger.Warn("failed to record the submission of the finality signatures",
+			zap.String("pk", fp.GetBtcPkHex()), zap.String("tx_hash", res.TxHash), zap.Error(err))
+	}
+}
+
+// sendFinalitySignature signs the given block and sends the finality signature
+// to the consumer chain without updating the state of the finality provider
+func (fp *FinalityProviderInstance) sendFinalitySignature(b *types.BlockInfo) (*types.TxResponse, error) {
+	if err := fp.checkSigningPolicy(b); err != nil {
+		return nil, err
+	}
+
+	// the tx of an earlier submission whose outcome is unknown is settled
+	// first, as the block signed again would be sent in another tx
+	res, err := fp.settlePendingFinalitySig(b.Height)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get public randomness list: %v", err)
+		return nil, fmt.Errorf("failed to settle the pending finality signature: %w", err)
+	}
+	if res != nil {
+		return res, nil
 	}
-	pubRand := prList[0]
 
-	// get inclusion proof
-	proofBytes, err := fp.pubRandState.GetPubRandProof(pubRand)
+	// get public randomness at the height with its inclusion proof
+	prList, proofList, err := fp.preparePubRand(b.Height, 1)
+	if err != nil {
+		return nil, err
+	}
+	pubRand, proofBytes := prList[0], proofList[0]
+
+	if err := fp.checkAndSaveSignedBlock(b); err != nil {
+		return nil, err
+	}
+
+	if err := fp.waitVoteJitter(); err != nil {
+		return nil, err
+	}
+
+	if err := fp.limiter.acquire(fp.quit); err != nil {
+		return nil, err
+	}
+	// the slot is freed once the broadcast is accepted, rather than after
+	// the inclusion of the tx
+	release := fp.limiter.releaseOnce()
+	defer release()
+
+	sig, err := fp.signFinalitySig(b)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get inclusion proof of public randomness: %v", err)
+		return nil, err
+	}
+
+	tx, err := fp.cc.SignFinalitySigTx(fp.GetBtcPk(), b, pubRand, proofBytes, sig.ToModNScalar())
+	if errors.Is(err, clientcontroller.ErrSignedTxUnsupported) {
+		// the tx is built and tracked by the recipient of the signature
+		res, err := fp.cc.SubmitFinalitySig(fp.GetBtcPk(), b, pubRand, proofBytes, sig.ToModNScalar())
+		if err != nil {
+			return nil, fmt.Errorf("failed to send finality signature to the consumer chain: %w", err)
+		}
+		return res, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the tx of the finality signature: %w", err)
+	}
+	tx.Accepted = release
+
+	// persist the signed tx until its inclusion is confirmed
+	if err := fp.trackPendingFinalitySig(tx, b); err != nil {
+		return nil, fmt.Errorf("failed to track the pending finality signature: %w", err)
 	}
 
 	// send finality signature to the consumer chain
-	res, err := fp.cc.SubmitFinalitySig(fp.GetBtcPk(), b, pubRand, proofBytes, sig.ToModNScalar())
+	res, err = fp.broadcastPendingFinalitySig(tx, b.Height)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send finality signature to the consumer chain: %w", err)
 	}
 
-	// update DB
-	fp.MustUpdateStateAfterFinalitySigSubmission(b.Height)
+	return res, nil
+}
 
-	// update metrics
-	fp.metrics.RecordFpVoteTime(fp.GetBtcPkHex())
-	fp.metrics.IncrementFpTotalVotedBlocks(fp.GetBtcPkHex())
+// SignFinality signs the given block and returns the finality signature with
+// the public randomness and its inclusion proof, leaving the broadcast to the
+// caller. The block is recorded as signed to refuse conflicting signatures
+func (fp *FinalityProviderInstance) SignFinality(b *types.BlockInfo) (*proto.SignFinalityResponse, error) {
+	if fp.isBelowActivation(b) {
+		return nil, fmt.Errorf("the height %d is below the finality activation height of the chain", b.Height)
+	}
 
-	return res, nil
+	if err := fp.checkSigningPolicy(b); err != nil {
+		return nil, err
+	}
+
+	prList, proofList, err := fp.preparePubRand(b.Height, 1)
+	if err != nil {
+		return nil, err
+	}
+	pubRand, proofBytes := prList[0], proofList[0]
+
+	if err := fp.checkAndSaveSignedBlock(b); err != nil {
+		return nil, err
+	}
+
+	sig, err := fp.signFinalitySig(b)
+	if err != nil {
+		return nil, err
+	}
+
+	pubRandBytes := pubRand.Bytes()
+	sigBytes := sig.ToModNScalar().Bytes()
+
+	return &proto.SignFinalityResponse{
+		Sig:     sigBytes[:],
+		PubRand: pubRandBytes[:],
+		Proof:   proofBytes,
+	}, nil
+}
+
+// checkSigningPolicy evaluates the signing policy and the submission script,
+// if any, before signing the given block
+func (fp *FinalityProviderInstance) checkSigningPolicy(b *types.BlockInfo) error {
+	if err := fp.checkConfirmationDepth(b); err != nil {
+		return err
+	}
+
+	if fp.signingPolicy != nil {
+		if err := fp.evaluateSigningPolicy(b); err != nil {
+			return err
+		}
+	}
+
+	if fp.submissionScript != nil {
+		return fp.submissionScript.Evaluate(&policy.ScriptRequest{
+			FpBtcPkHex:          fp.GetBtcPkHex(),
+			ChainID:             string(fp.GetChainID()),
+			Status:              fp.GetStatus().String(),
+			LastVotedHeight:     fp.GetLastVotedHeight(),
+			LastProcessedHeight: fp.GetLastProcessedHeight(),
+			Height:              b.Height,
+			BlockHash:           b.Hash,
+		})
+	}
+
+	return nil
+}
+
+// checkConfirmationDepth defers the signature over the given block until
+// the configured number of blocks of its chain is produced on top of it
+func (fp *FinalityProviderInstance) checkConfirmationDepth(b *types.BlockInfo) error {
+	depth := fp.cfg.ChainConfirmationDepth(string(fp.GetChainID()))
+	if depth == 0 {
+		return nil
+	}
+
+	tip, err := fp.getLatestBlockWithRetry()
+	if err != nil {
+		return err
+	}
+	if tip.Height < b.Height+depth {
+		return fmt.Errorf("%w: block %d has less than %d confirmations at tip %d",
+			policy.ErrSigningDeferred, b.Height, depth, tip.Height)
+	}
+
+	return nil
+}
+
+func (fp *FinalityProviderInstance) evaluateSigningPolicy(b *types.BlockInfo) error {
+	req := &policy.SignRequest{
+		FpBtcPkHex: fp.GetBtcPkHex(),
+		ChainID:    string(fp.GetChainID()),
+		Height:     b.Height,
+	}
+	if fp.signingPolicy.MinConfirmations() > 0 {
+		tip, err := fp.getLatestBlockWithRetry()
+		if err != nil {
+			return err
+		}
+		req.TipHeight = tip.Height
+	}
+
+	return fp.signingPolicy.Evaluate(req)
+}
+
+// waitVoteJitter sleeps for a random duration bounded by the configured max vote jitter
+// so that finality providers sharing the same infrastructure do not broadcast their
+// votes at the same moment
+func (fp *FinalityProviderInstance) waitVoteJitter() error {
+	if fp.cfg.MaxVoteJitter <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(randomJitter(fp.cfg.MaxVoteJitter))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-fp.quit:
+		return ErrFinalityProviderShutDown
+	}
+}
+
+// randomJitter returns a random duration in [0, maxJitter)
+func randomJitter(maxJitter time.Duration) time.Duration {
+	return time.Duration(rand.Int63n(int64(maxJitter)))
 }
 
 // SubmitBatchFinalitySignatures builds and sends a finality signature over the given block to the consumer chain
@@ -773,41 +1588,158 @@ func (fp *FinalityProviderInstance) SubmitBatchFinalitySignatures(blocks []*type
 		return nil, fmt.Errorf("should not submit batch finality signature with zero block")
 	}
 
-	// get public randomness list
-	prList, err := fp.getPubRandList(blocks[0].Height, uint64(len(blocks)))
+	res, err := fp.sendBatchFinalitySignatures(blocks)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get public randomness list: %v", err)
+		return nil, err
 	}
-	// get proof list
-	// TODO: how to recover upon having an error in GetPubRandProofList?
-	proofBytesList, err := fp.pubRandState.GetPubRandProofList(prList)
+
+	// update DB
+	highBlock := blocks[len(blocks)-1]
+	fp.MustUpdateStateAfterFinalitySigSubmission(highBlock.Height)
+
+	fp.resetSubmissionFailures()
+	fp.emitEvent(&hooks.Event{
+		Type:       hooks.EventVoteSubmitted,
+		Height:     highBlock.Height,
+		FromHeight: blocks[0].Height,
+		TxHash:     res.TxHash,
+	})
+	fp.recordVoteLatency(blocks...)
+
+	return res, nil
+}
+
+// sendBatchFinalitySignatures signs the given blocks and sends the finality
+// signatures to the consumer chain in one tx without updating the state of the
+// finality provider. The blocks whose pending finality signatures are settled
+// are left out of the batch, and the submission of each tx is recorded
+func (fp *FinalityProviderInstance) sendBatchFinalitySignatures(blocks []*types.BlockInfo) (*types.TxResponse, error) {
+	// the txs of earlier submissions whose outcomes are unknown are settled
+	// first, as the blocks signed again would be sent in another tx
+	var (
+		res     *types.TxResponse
+		pending []*types.BlockInfo
+	)
+	for _, b := range blocks {
+		settled, err := fp.settlePendingFinalitySig(b.Height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to settle the pending finality signature: %w", err)
+		}
+		if settled != nil {
+			fp.recordVoteSubmission(settled, b)
+			res = settled
+			continue
+		}
+		pending = append(pending, b)
+	}
+	if len(pending) == 0 {
+		return res, nil
+	}
+
+	if err := fp.limiter.acquire(fp.quit); err != nil {
+		return nil, err
+	}
+	release := fp.limiter.releaseOnce()
+	defer release()
+
+	// get public randomness list with the inclusion proofs, over the heights
+	// between the lowest and highest pending blocks
+	startHeight := pending[0].Height
+	numPubRand := pending[len(pending)-1].Height - startHeight + 1
+	allPrList, allProofList, err := fp.preparePubRand(startHeight, numPubRand)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get public randomness inclusion proof list: %v", err)
+		return nil, err
 	}
 
 	// sign blocks
-	sigList := make([]*btcec.ModNScalar, 0, len(blocks))
-	for _, b := range blocks {
+	prList := make([]*btcec.FieldVal, 0, len(pending))
+	proofBytesList := make([][]byte, 0, len(pending))
+	sigList := make([]*btcec.ModNScalar, 0, len(pending))
+	heights := make([]uint64, 0, len(pending))
+	for _, b := range pending {
+		if err := fp.checkSigningPolicy(b); err != nil {
+			return nil, err
+		}
+		if err := fp.checkAndSaveSignedBlock(b); err != nil {
+			return nil, err
+		}
 		eotsSig, err := fp.signFinalitySig(b)
 		if err != nil {
 			return nil, err
 		}
+		prList = append(prList, allPrList[b.Height-startHeight])
+		proofBytesList = append(proofBytesList, allProofList[b.Height-startHeight])
 		sigList = append(sigList, eotsSig.ToModNScalar())
+		heights = append(heights, b.Height)
 	}
 
-	// send finality signature to the consumer chain
-	res, err := fp.cc.SubmitBatchFinalitySigs(fp.GetBtcPk(), blocks, prList, proofBytesList, sigList)
+	tx, err := fp.cc.SignBatchFinalitySigsTx(fp.GetBtcPk(), pending, prList, proofBytesList, sigList)
+	if errors.Is(err, clientcontroller.ErrSignedTxUnsupported) {
+		// the tx is built and tracked by the recipient of the signatures
+		res, err = fp.cc.SubmitBatchFinalitySigs(fp.GetBtcPk(), pending, prList, proofBytesList, sigList)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send a batch of finality signatures to the consumer chain: %w", err)
+		}
+		fp.recordVoteSubmission(res, pending...)
+		return res, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to send a batch of finality signatures to the consumer chain: %w", err)
+		return nil, fmt.Errorf("failed to sign the tx of the batch of finality signatures: %w", err)
 	}
+	tx.Accepted = release
 
-	// update DB
-	highBlock := blocks[len(blocks)-1]
-	fp.MustUpdateStateAfterFinalitySigSubmission(highBlock.Height)
+	// persist the signed tx until its inclusion is confirmed
+	if err := fp.trackPendingFinalitySig(tx, pending...); err != nil {
+		return nil, fmt.Errorf("failed to track the pending finality signatures: %w", err)
+	}
+
+	// send finality signatures to the consumer chain
+	res, err = fp.broadcastPendingFinalitySig(tx, heights...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send a batch of finality signatures to the consumer chain: %w", err)
+	}
+	fp.recordVoteSubmission(res, pending...)
 
 	return res, nil
 }
 
+// checkManualFinalitySig guards the manual submission of a finality signature
+// against stale or conflicting blocks, which could otherwise get the finality
+// provider slashed. Only the check of the height against the tip is kept if
+// force is set
+func (fp *FinalityProviderInstance) checkManualFinalitySig(b *types.BlockInfo, force bool) error {
+	tip, err := fp.getLatestBlockWithRetry()
+	if err != nil {
+		return err
+	}
+	if b.Height > tip.Height {
+		return fmt.Errorf("%w: height %d, tip %d", ErrHeightAboveTip, b.Height, tip.Height)
+	}
+
+	if force {
+		fp.logger.Warn("forcing a manual finality signature, which may get the finality provider slashed",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("height", b.Height),
+			zap.String("hash", hex.EncodeToString(b.Hash)))
+		return nil
+	}
+
+	if b.Height <= fp.GetLastVotedHeight() {
+		return fmt.Errorf("%w: height %d, last voted height %d", ErrAlreadyVoted, b.Height, fp.GetLastVotedHeight())
+	}
+
+	block, err := fp.cc.QueryBlock(b.Height)
+	if err != nil {
+		return fmt.Errorf("failed to query the block at height %d: %w", b.Height, err)
+	}
+	if !bytes.Equal(block.Hash, b.Hash) {
+		return fmt.Errorf("%w: height %d, chain hash %s, new hash %s", ErrConflictingBlockHash,
+			b.Height, hex.EncodeToString(block.Hash), hex.EncodeToString(b.Hash))
+	}
+
+	return fp.checkAndSaveSignedBlock(b)
+}
+
 // TestSubmitFinalitySignatureAndExtractPrivKey is exposed for presentation/testing purpose to allow manual sending finality signature
 // this API is the same as SubmitFinalitySignature except that we don't constraint the voting height and update status
 // Note: this should not be used in the submission loop
@@ -846,6 +1778,7 @@ func (fp *FinalityProviderInstance) TestSubmitFinalitySignatureAndExtractPrivKey
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to send finality signature to the consumer chain: %w", err)
 	}
+	fp.recordVoteSubmission(res, b)
 
 	// try to extract the private key
 	var privKey *btcec.PrivateKey
@@ -868,9 +1801,43 @@ func (fp *FinalityProviderInstance) TestSubmitFinalitySignatureAndExtractPrivKey
 	return res, privKey, nil
 }
 
+// staticStartHeight returns the static height after which the chain is
+// polled. A height below the last voted height or the finality activation
+// height of the chain would only lead to useless heights being processed, so
+// it is either rejected or, if auto-correction is enabled, raised to the
+// lowest useful height, in which case corrected is true
+func (fp *FinalityProviderInstance) staticStartHeight() (height uint64, corrected bool, err error) {
+	configured := fp.cfg.PollerConfig.StaticStartHeight(fp.GetBtcPkHex())
+
+	activatedHeight, err := fp.chainParams.activatedHeight()
+	if err != nil {
+		return 0, false, err
+	}
+
+	// the poller starts from the block after the start height
+	lowest := fp.GetLastVotedHeight()
+	if activatedHeight > lowest+1 {
+		lowest = activatedHeight - 1
+	}
+	if configured >= lowest {
+		return configured, false, nil
+	}
+
+	if !fp.cfg.PollerConfig.AutoCorrectStartHeight {
+		return 0, false, fmt.Errorf("%w: the static start height %d of %s is below its last voted height %d "+
+			"or the finality activation height %d of the chain, set the start height to at least %d "+
+			"or enable autocorrectstartheight",
+			ErrStaticStartHeightTooLow, configured, fp.GetBtcPkHex(), fp.GetLastVotedHeight(),
+			activatedHeight, lowest)
+	}
+
+	return lowest, true, nil
+}
+
 func (fp *FinalityProviderInstance) getPollerStartingHeight() (uint64, error) {
+	// the static start height is validated once on the start of the instance
 	if !fp.cfg.PollerConfig.AutoChainScanningMode {
-		return fp.cfg.PollerConfig.StaticChainScanningStartHeight, nil
+		return fp.staticStart, nil
 	}
 
 	// Set initial block to the maximum of
@@ -888,6 +1855,7 @@ func (fp *FinalityProviderInstance) getPollerStartingHeight() (uint64, error) {
 		return 0, err
 	}
 	if len(latestFinalisedBlock) != 0 {
+		fp.updateFinalizedHeight(latestFinalisedBlock[0].Height)
 		if latestFinalisedBlock[0].Height > initialBlockToGet {
 			initialBlockToGet = latestFinalisedBlock[0].Height
 		}
@@ -931,14 +1899,14 @@ func (fp *FinalityProviderInstance) lastCommittedPublicRandWithRetry(count uint6
 		}
 		response = resp
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, fp.queryRetryOpts(func(n uint, err error) {
 		fp.logger.Debug(
 			"failed to query babylon for the last committed public randomness",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", fp.cfg.PollerConfig.QueryRetryAttempts),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -953,14 +1921,14 @@ func (fp *FinalityProviderInstance) latestFinalizedBlocksWithRetry(count uint64)
 		}
 		response = latestFinalisedBlock
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, fp.queryRetryOpts(func(n uint, err error) {
 		fp.logger.Debug(
 			"failed to query babylon for the latest finalised blocks",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", fp.cfg.PollerConfig.QueryRetryAttempts),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return nil, err
 	}
 	return response, nil
@@ -978,14 +1946,14 @@ func (fp *FinalityProviderInstance) getLatestBlockWithRetry() (*types.BlockInfo,
 			return err
 		}
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, fp.queryRetryOpts(func(n uint, err error) {
 		fp.logger.Debug(
 			"failed to query the consumer chain for the latest block",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", fp.cfg.PollerConfig.QueryRetryAttempts),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return nil, err
 	}
 	fp.metrics.RecordBabylonTipHeight(latestBlock.Height)
@@ -1005,14 +1973,14 @@ func (fp *FinalityProviderInstance) GetVotingPowerWithRetry(height uint64) (uint
 			return err
 		}
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, fp.queryRetryOpts(func(n uint, err error) {
 		fp.logger.Debug(
 			"failed to query the voting power",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", fp.cfg.PollerConfig.QueryRetryAttempts),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return 0, err
 	}
 
@@ -1031,14 +1999,14 @@ func (fp *FinalityProviderInstance) GetFinalityProviderSlashedWithRetry() (bool,
 			return err
 		}
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, fp.queryRetryOpts(func(n uint, err error) {
 		fp.logger.Debug(
 			"failed to query the finality-provider",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", fp.cfg.PollerConfig.QueryRetryAttempts),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return false, err
 	}
 
@@ -1,15 +1,19 @@
 package service_test
 
 import (
+	"errors"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/babylonchain/babylon/crypto/eots"
 	"github.com/babylonchain/babylon/testutil/datagen"
 	ftypes "github.com/babylonchain/babylon/x/finality/types"
 	"github.com/babylonchain/finality-provider/clientcontroller"
@@ -48,6 +52,45 @@ func FuzzCommitPubRandList(f *testing.F) {
 	})
 }
 
+// FuzzForceCommitPubRand tests that the public randomness is committed on
+// demand up to the target height, and only if it is not committed yet
+func FuzzForceCommitPubRand(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+2)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), gomock.Any()).
+			Return(uint64(0), nil).AnyTimes()
+		_, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+
+		numPubRand := uint64(r.Int63n(50) + 1)
+		targetHeight := currentHeight + numPubRand
+		expectedTxHash := testutil.GenRandomHexStr(r, 32)
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(nil, nil).Times(1)
+		mockClientController.EXPECT().
+			CommitPubRandList(fpIns.GetBtcPk(), currentHeight+1, numPubRand, gomock.Any(), gomock.Any()).
+			Return(&types.TxResponse{TxHash: expectedTxHash}, nil).Times(1)
+		res, err := fpIns.ForceCommitPubRand(targetHeight)
+		require.NoError(t, err)
+		require.Equal(t, expectedTxHash, res.TxHash)
+		require.Equal(t, currentHeight+1, res.StartHeight)
+		require.Equal(t, numPubRand, res.NumPubRand)
+		require.Equal(t, targetHeight, res.LastCommittedHeight)
+
+		// nothing is committed once the randomness reaches the target height
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).
+			Return(map[uint64]*ftypes.PubRandCommitResponse{currentHeight + 1: {NumPubRand: numPubRand}}, nil).AnyTimes()
+		res, err = fpIns.ForceCommitPubRand(targetHeight)
+		require.NoError(t, err)
+		require.Empty(t, res.TxHash)
+		require.Equal(t, targetHeight, res.LastCommittedHeight)
+	})
+}
+
 func FuzzSubmitFinalitySig(f *testing.F) {
 	testutil.AddRandomSeedsToFuzzer(f, 10)
 	f.Fuzz(func(t *testing.T, seed int64) {
@@ -85,8 +128,11 @@ func FuzzSubmitFinalitySig(f *testing.F) {
 			Hash:   testutil.GenRandomByteArray(r, 32),
 		}
 		expectedTxHash := testutil.GenRandomHexStr(r, 32)
+		signedTx := &types.SignedTx{Bytes: datagen.GenRandomByteArray(r, 64), Hash: datagen.GenRandomByteArray(r, 32)}
 		mockClientController.EXPECT().
-			SubmitFinalitySig(fpIns.GetBtcPk(), nextBlock, gomock.Any(), gomock.Any(), gomock.Any()).
+			SignFinalitySigTx(fpIns.GetBtcPk(), nextBlock, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(signedTx, nil).AnyTimes()
+		mockClientController.EXPECT().BroadcastSignedTx(signedTx).
 			Return(&types.TxResponse{TxHash: expectedTxHash}, nil).AnyTimes()
 		providerRes, err := fpIns.SubmitFinalitySignature(nextBlock)
 		require.NoError(t, err)
@@ -98,6 +144,295 @@ func FuzzSubmitFinalitySig(f *testing.F) {
 	})
 }
 
+// FuzzRefuseConflictingBlock tests that the finality provider never signs a
+// block conflicting with the one it has signed at the same height, e.g.,
+// after a reorg of the consumer chain
+func FuzzRefuseConflictingBlock(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+1)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+		fpStore := app.GetFinalityProviderStore()
+
+		// commit pub rand
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(nil, nil).Times(1)
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+		_, err := fpIns.CommitPubRand(randomStartingHeight)
+		require.NoError(t, err)
+		lastCommittedPubRandMap := map[uint64]*ftypes.PubRandCommitResponse{
+			randomStartingHeight + 25: {
+				NumPubRand: 1000,
+				Commitment: datagen.GenRandomByteArray(r, 32),
+			},
+		}
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(lastCommittedPubRandMap, nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(fpIns.GetBtcPk(), gomock.Any()).
+			Return(uint64(1), nil).AnyTimes()
+
+		// the block is signed and recorded as signed
+		signedBlock := &types.BlockInfo{
+			Height: randomStartingHeight + 1,
+			Hash:   testutil.GenRandomByteArray(r, 32),
+		}
+		signedTx := &types.SignedTx{Bytes: datagen.GenRandomByteArray(r, 64), Hash: datagen.GenRandomByteArray(r, 32)}
+		mockClientController.EXPECT().
+			SignFinalitySigTx(fpIns.GetBtcPk(), signedBlock, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(signedTx, nil).Times(1)
+		mockClientController.EXPECT().BroadcastSignedTx(signedTx).
+			Return(&types.TxResponse{TxHash: testutil.GenRandomHexStr(r, 32)}, nil).Times(1)
+		_, err = fpIns.SubmitFinalitySignature(signedBlock)
+		require.NoError(t, err)
+		signedHash, err := fpStore.GetSignedBlockHash(fpIns.GetBtcPk(), signedBlock.Height)
+		require.NoError(t, err)
+		require.Equal(t, signedBlock.Hash, signedHash)
+
+		// the block replacing it at the same height is refused without
+		// being signed, as no other call is expected
+		conflictingBlock := &types.BlockInfo{
+			Height: signedBlock.Height,
+			Hash:   testutil.GenRandomByteArray(r, 32),
+		}
+		_, err = fpIns.SubmitFinalitySignature(conflictingBlock)
+		require.ErrorIs(t, err, service.ErrConflictingBlockHash)
+		signedHash, err = fpStore.GetSignedBlockHash(fpIns.GetBtcPk(), signedBlock.Height)
+		require.NoError(t, err)
+		require.Equal(t, signedBlock.Hash, signedHash)
+	})
+}
+
+// FuzzSignFinality tests that the finality signature returned by the
+// SignFinality RPC is valid and recorded without being broadcast
+func FuzzSignFinality(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+1)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+
+		// the RPC is disabled by default
+		_, err := app.SignFinality(fpIns.GetBtcPkBIP340(), randomStartingHeight+1, testutil.GenRandomByteArray(r, 32))
+		require.ErrorIs(t, err, service.ErrSignFinalityDisabled)
+
+		// commit pub rand
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(nil, nil).Times(1)
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+		_, err = fpIns.CommitPubRand(randomStartingHeight)
+		require.NoError(t, err)
+
+		// the block is signed without any tx, as no other call is expected
+		block := &types.BlockInfo{
+			Height: randomStartingHeight + 1,
+			Hash:   testutil.GenRandomByteArray(r, 32),
+		}
+		res, err := fpIns.SignFinality(block)
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Proof)
+		var pubRand btcec.FieldVal
+		pubRand.SetByteSlice(res.PubRand)
+		var sig btcec.ModNScalar
+		sig.SetByteSlice(res.Sig)
+		msg := append(sdk.Uint64ToBigEndian(block.Height), block.Hash...)
+		require.NoError(t, eots.Verify(fpIns.GetBtcPk(), &pubRand, msg, &sig))
+
+		signedHash, err := app.GetFinalityProviderStore().GetSignedBlockHash(fpIns.GetBtcPk(), block.Height)
+		require.NoError(t, err)
+		require.Equal(t, block.Hash, signedHash)
+
+		// a conflicting block at the same height is refused
+		_, err = fpIns.SignFinality(&types.BlockInfo{Height: block.Height, Hash: testutil.GenRandomByteArray(r, 32)})
+		require.ErrorIs(t, err, service.ErrConflictingBlockHash)
+	})
+}
+
+// FuzzResolvePendingFinalitySigs tests that the signed tx of a finality
+// signature whose broadcast has an unknown outcome is settled on restart
+func FuzzResolvePendingFinalitySigs(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+1)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+		fpStore := app.GetFinalityProviderStore()
+
+		// commit pub rand
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(nil, nil).Times(1)
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+		_, err := fpIns.CommitPubRand(randomStartingHeight)
+		require.NoError(t, err)
+		lastCommittedPubRandMap := map[uint64]*ftypes.PubRandCommitResponse{
+			randomStartingHeight + 25: {
+				NumPubRand: 1000,
+				Commitment: datagen.GenRandomByteArray(r, 32),
+			},
+		}
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(lastCommittedPubRandMap, nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(fpIns.GetBtcPk(), gomock.Any()).
+			Return(uint64(1), nil).AnyTimes()
+
+		// the broadcast times out, so the tx is kept as pending
+		nextBlock := &types.BlockInfo{
+			Height: randomStartingHeight + 1,
+			Hash:   testutil.GenRandomByteArray(r, 32),
+		}
+		signedTx := &types.SignedTx{Bytes: datagen.GenRandomByteArray(r, 64), Hash: datagen.GenRandomByteArray(r, 32)}
+		mockClientController.EXPECT().
+			SignFinalitySigTx(fpIns.GetBtcPk(), nextBlock, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(signedTx, nil).Times(1)
+		mockClientController.EXPECT().BroadcastSignedTx(signedTx).
+			Return(nil, clientcontroller.ErrChainCallTimeout).Times(1)
+		_, err = fpIns.SubmitFinalitySignature(nextBlock)
+		require.ErrorIs(t, err, clientcontroller.ErrChainCallTimeout)
+		pendingSigs, err := fpStore.GetPendingFinalitySigs(fpIns.GetBtcPk())
+		require.NoError(t, err)
+		require.Len(t, pendingSigs, 1)
+		require.Equal(t, signedTx.Bytes, pendingSigs[0].TxBytes)
+		require.Equal(t, signedTx.Hash, pendingSigs[0].TxHash)
+		require.Less(t, fpIns.GetLastVotedHeight(), nextBlock.Height)
+
+		expectedTxHash := testutil.GenRandomHexStr(r, 32)
+		resolvable := true
+		switch r.Intn(4) {
+		case 0:
+			// the tx has been included before the restart
+			mockClientController.EXPECT().QueryTx(signedTx.Hash).
+				Return(&types.TxResponse{TxHash: expectedTxHash}, nil).Times(1)
+		case 1:
+			// the tx is not included, so it is broadcast again as is
+			mockClientController.EXPECT().QueryTx(signedTx.Hash).
+				Return(nil, clientcontroller.ErrTxNotFound).Times(1)
+			mockClientController.EXPECT().BroadcastSignedTx(signedTx).
+				Return(&types.TxResponse{TxHash: expectedTxHash}, nil).Times(1)
+		case 2:
+			// the tx is dropped, so the block is signed again
+			mockClientController.EXPECT().QueryTx(signedTx.Hash).
+				Return(nil, clientcontroller.ErrTxNotFound).Times(1)
+			mockClientController.EXPECT().BroadcastSignedTx(signedTx).
+				Return(nil, clientcontroller.ErrTxDropped).Times(1)
+			resignedTx := &types.SignedTx{Bytes: datagen.GenRandomByteArray(r, 64), Hash: datagen.GenRandomByteArray(r, 32)}
+			mockClientController.EXPECT().
+				SignFinalitySigTx(fpIns.GetBtcPk(), nextBlock, gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(resignedTx, nil).Times(1)
+			mockClientController.EXPECT().BroadcastSignedTx(resignedTx).
+				Return(&types.TxResponse{TxHash: expectedTxHash}, nil).Times(1)
+		case 3:
+			// the tx can be neither looked up nor broadcast again
+			resolvable = false
+			mockClientController.EXPECT().QueryTx(signedTx.Hash).
+				Return(nil, errors.New("connection refused")).Times(1)
+		}
+
+		err = fpIns.ResolvePendingFinalitySigs()
+		if !resolvable {
+			require.Error(t, err)
+			require.Less(t, fpIns.GetLastVotedHeight(), nextBlock.Height)
+			pendingSigs, err = fpStore.GetPendingFinalitySigs(fpIns.GetBtcPk())
+			require.NoError(t, err)
+			require.Len(t, pendingSigs, 1)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, nextBlock.Height, fpIns.GetLastVotedHeight())
+		pendingSigs, err = fpStore.GetPendingFinalitySigs(fpIns.GetBtcPk())
+		require.NoError(t, err)
+		require.Empty(t, pendingSigs)
+	})
+}
+
+// FuzzProcessPendingBlocksNewestFirst tests that the newest of the pending
+// blocks is voted first, and the older ones are then backfilled from the
+// newest to the oldest unless they are skipped or the vote on the newest one
+// fails
+func FuzzProcessPendingBlocksNewestFirst(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		numPending := uint64(r.Int63n(5) + 2)
+		currentHeight := randomStartingHeight + numPending
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		_, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+
+		// commit pub rand
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(nil, nil).Times(1)
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+		_, err := fpIns.CommitPubRand(randomStartingHeight)
+		require.NoError(t, err)
+		lastCommittedPubRandMap := map[uint64]*ftypes.PubRandCommitResponse{
+			randomStartingHeight + 25: {
+				NumPubRand: 1000,
+				Commitment: datagen.GenRandomByteArray(r, 32),
+			},
+		}
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(lastCommittedPubRandMap, nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(fpIns.GetBtcPk(), gomock.Any()).
+			Return(uint64(1), nil).AnyTimes()
+
+		pending := make([]*types.BlockInfo, 0, numPending)
+		for h := randomStartingHeight + 1; h <= currentHeight; h++ {
+			pending = append(pending, &types.BlockInfo{Height: h, Hash: testutil.GenRandomByteArray(r, 32)})
+		}
+		skipOlder := r.Intn(2) == 0
+		newestFails := r.Intn(3) == 0
+
+		if newestFails {
+			// the older blocks are not backfilled once the vote on the
+			// newest one fails
+			mockClientController.EXPECT().
+				SignFinalitySigTx(fpIns.GetBtcPk(), pending[len(pending)-1], gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(nil, ftypes.ErrInvalidFinalitySig).Times(1)
+
+			fpIns.ProcessPendingBlocksNewestFirst(pending, skipOlder)
+
+			require.Less(t, fpIns.GetLastVotedHeight(), pending[0].Height)
+			require.Less(t, fpIns.GetLastProcessedHeight(), pending[0].Height)
+			return
+		}
+
+		// the skipped blocks are not expected to be signed
+		votedBlocks := []*types.BlockInfo{pending[len(pending)-1]}
+		if !skipOlder {
+			for i := len(pending) - 2; i >= 0; i-- {
+				votedBlocks = append(votedBlocks, pending[i])
+			}
+		}
+		signCalls := make([]*gomock.Call, 0, len(votedBlocks))
+		for _, b := range votedBlocks {
+			signedTx := &types.SignedTx{Bytes: datagen.GenRandomByteArray(r, 64), Hash: datagen.GenRandomByteArray(r, 32)}
+			signCalls = append(signCalls, mockClientController.EXPECT().
+				SignFinalitySigTx(fpIns.GetBtcPk(), b, gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(signedTx, nil).Times(1))
+			mockClientController.EXPECT().BroadcastSignedTx(signedTx).
+				Return(&types.TxResponse{TxHash: testutil.GenRandomHexStr(r, 32)}, nil).Times(1)
+		}
+		gomock.InOrder(signCalls...)
+
+		fpIns.ProcessPendingBlocksNewestFirst(pending, skipOlder)
+
+		// the backfill does not move the state back from the newest block
+		require.Equal(t, currentHeight, fpIns.GetLastVotedHeight())
+		require.Equal(t, currentHeight, fpIns.GetLastProcessedHeight())
+	})
+}
+
 func startFinalityProviderAppWithRegisteredFp(t *testing.T, r *rand.Rand, cc clientcontroller.ClientController, startingHeight uint64) (*service.FinalityProviderApp, *service.FinalityProviderInstance, func()) {
 	logger := zap.NewNop()
 	// create an EOTS manager
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// fpFilter selects the finality providers by their chain key or moniker, as
+// the operators often only have the chain address at hand. The unset fields
+// match all the finality providers
+type fpFilter struct {
+	chainPk []byte
+	address []byte
+	// moniker is matched as a case-insensitive substring
+	moniker string
+}
+
+func (f *fpFilter) isEmpty() bool {
+	return f.chainPk == nil && f.address == nil && f.moniker == ""
+}
+
+func (f *fpFilter) matches(fpInfo *proto.FinalityProviderInfo) bool {
+	if f.chainPk != nil || f.address != nil {
+		chainPk, err := hex.DecodeString(fpInfo.ChainPkHex)
+		if err != nil {
+			return false
+		}
+		if f.chainPk != nil && !bytes.Equal(chainPk, f.chainPk) {
+			return false
+		}
+		if f.address != nil && !bytes.Equal((&secp256k1.PubKey{Key: chainPk}).Address(), f.address) {
+			return false
+		}
+	}
+
+	if f.moniker != "" {
+		if fpInfo.Description == nil ||
+			!strings.Contains(strings.ToLower(fpInfo.Description.Moniker), strings.ToLower(f.moniker)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FindFinalityProviders returns the finality providers matching the filter
+func (app *FinalityProviderApp) FindFinalityProviders(filter *fpFilter) ([]*proto.FinalityProviderInfo, error) {
+	fpsInfo, err := app.ListAllFinalityProvidersInfo()
+	if err != nil {
+		return nil, err
+	}
+	if filter.isEmpty() {
+		return fpsInfo, nil
+	}
+
+	matched := make([]*proto.FinalityProviderInfo, 0, len(fpsInfo))
+	for _, fpInfo := range fpsInfo {
+		if filter.matches(fpInfo) {
+			matched = append(matched, fpInfo)
+		}
+	}
+
+	return matched, nil
+}
+
+// FindFinalityProvider returns the information of the single finality
+// provider matching the filter
+func (app *FinalityProviderApp) FindFinalityProvider(filter *fpFilter) (*proto.FinalityProviderInfo, error) {
+	matched, err := app.FindFinalityProviders(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, ErrFinalityProviderNotFound
+	case 1:
+	default:
+		btcPks := make([]string, 0, len(matched))
+		for _, fpInfo := range matched {
+			btcPks = append(btcPks, fpInfo.BtcPkHex)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousQuery, strings.Join(btcPks, ", "))
+	}
+
+	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(matched[0].BtcPkHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.GetFinalityProviderInfo(fpPk)
+}
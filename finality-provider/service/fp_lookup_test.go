@@ -0,0 +1,117 @@
+package service
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+func TestFpFilter(t *testing.T) {
+	chainPk := secp256k1.GenPrivKey().PubKey().(*secp256k1.PubKey)
+	otherChainPk := secp256k1.GenPrivKey().PubKey().(*secp256k1.PubKey)
+	fpInfo := &proto.FinalityProviderInfo{
+		ChainPkHex:  hex.EncodeToString(chainPk.Key),
+		Description: &proto.Description{Moniker: "My Validator"},
+	}
+
+	testCases := []struct {
+		name    string
+		filter  *fpFilter
+		matches bool
+	}{
+		{
+			name:    "an empty filter matches all",
+			filter:  &fpFilter{},
+			matches: true,
+		},
+		{
+			name:    "the chain key matches",
+			filter:  &fpFilter{chainPk: chainPk.Key},
+			matches: true,
+		},
+		{
+			name:   "another chain key does not match",
+			filter: &fpFilter{chainPk: otherChainPk.Key},
+		},
+		{
+			name:    "the address of the chain key matches",
+			filter:  &fpFilter{address: chainPk.Address()},
+			matches: true,
+		},
+		{
+			name:   "the address of another chain key does not match",
+			filter: &fpFilter{address: otherChainPk.Address()},
+		},
+		{
+			name:    "a part of the moniker matches in any case",
+			filter:  &fpFilter{moniker: "my validator"},
+			matches: true,
+		},
+		{
+			name:   "all the fields set should match",
+			filter: &fpFilter{chainPk: chainPk.Key, moniker: "other"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.matches, tc.filter.matches(fpInfo))
+		})
+	}
+
+	require.True(t, (&fpFilter{}).isEmpty())
+	require.False(t, (&fpFilter{moniker: "my"}).isEmpty())
+}
+
+func TestRPCValidatorLookup(t *testing.T) {
+	chainPk := secp256k1.GenPrivKey().PubKey().(*secp256k1.PubKey)
+	addr, err := bech32.ConvertAndEncode("bbn", chainPk.Address())
+	require.NoError(t, err)
+
+	var valid rpcValidator
+	require.Nil(t, valid.chainPk("chain_pk", ""))
+	require.Nil(t, valid.address("address", ""))
+	require.Equal(t, []byte(chainPk.Key), valid.chainPk("chain_pk", hex.EncodeToString(chainPk.Key)))
+	require.Equal(t, []byte(chainPk.Address()), valid.address("address", addr))
+	require.NoError(t, valid.err())
+
+	var v rpcValidator
+	require.Nil(t, v.chainPk("chain_pk", hex.EncodeToString(chainPk.Key[1:])))
+	require.Nil(t, v.address("address", "not-an-address"))
+	require.Len(t, v.violations, 2)
+	require.Error(t, v.err())
+}
+
+func TestFindFinalityProvider(t *testing.T) {
+	db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+	// the monikers of the finality providers are their chain IDs
+	fpPk := createTestFinalityProvider(t, s, "chain-a")
+	createTestFinalityProvider(t, s, "chain-b")
+	app := &FinalityProviderApp{fpManager: &FinalityProviderManager{fps: s}}
+
+	fps, err := app.FindFinalityProviders(&fpFilter{moniker: "chain"})
+	require.NoError(t, err)
+	require.Len(t, fps, 2)
+
+	fpInfo, err := app.FindFinalityProvider(&fpFilter{moniker: "CHAIN-A"})
+	require.NoError(t, err)
+	require.Equal(t, fpPk.MarshalHex(), fpInfo.BtcPkHex)
+
+	_, err = app.FindFinalityProvider(&fpFilter{moniker: "chain"})
+	require.ErrorIs(t, err, ErrAmbiguousQuery)
+	_, err = app.FindFinalityProvider(&fpFilter{moniker: "chain-c"})
+	require.ErrorIs(t, err, ErrFinalityProviderNotFound)
+}
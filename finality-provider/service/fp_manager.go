@@ -1,6 +1,8 @@
 package service
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
@@ -9,12 +11,15 @@ import (
 	"github.com/avast/retry-go/v4"
 	bbntypes "github.com/babylonchain/babylon/types"
 	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/babylonchain/finality-provider/clientcontroller"
 	"github.com/babylonchain/finality-provider/eotsmanager"
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/policy"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/store"
 	"github.com/babylonchain/finality-provider/metrics"
@@ -40,6 +45,9 @@ type FinalityProviderManager struct {
 
 	// running finality-provider instances map keyed by the hex string of the BTC public key
 	fpis map[string]*FinalityProviderInstance
+	// unloaded are the finality providers whose idle instances are unloaded
+	// from memory, keyed by the hex string of the BTC public key
+	unloaded map[string]*unloadedInstance
 
 	// needed for initiating finality-provider instances
 	fps          *store.FinalityProviderStore
@@ -51,6 +59,39 @@ type FinalityProviderManager struct {
 
 	metrics *metrics.FpMetrics
 
+	// limiter is shared by all the running instances to cap and stagger
+	// their finality signature submissions
+	limiter *submissionLimiter
+
+	// signingPolicy is evaluated before each finality signature, which is
+	// nil if no signing policy is configured
+	signingPolicy *policy.Engine
+	// submissionScript decides on each finality signature after the signing
+	// policy, which is nil if no script is configured
+	submissionScript *policy.Script
+
+	// chainParams caches the on-chain parameters shared by the instances
+	chainParams *chainParamsCache
+	// chainClients are the clients of the chains configured by their own
+	// sections, keyed by chain ID, which are used by the instances of the
+	// chains instead of cc. They are only set before the manager is started
+	chainClients map[string]*chainClient
+	// blockSources are the block sources shared by the pollers of the
+	// instances, keyed by chain ID, which are guarded by mu
+	blockSources map[string]*sharedBlockSource
+	// chainHalts are the halt detectors shared by the instances, keyed by
+	// chain ID, which are guarded by mu
+	chainHalts map[string]*chainHaltDetector
+	// cloneDetectors watch the votes of the instances, keyed by chain ID,
+	// which are guarded by mu
+	cloneDetectors map[string]*cloneDetector
+
+	// hooks delivers the lifecycle events of the instances to the hooks
+	hooks *hooks.Dispatcher
+	// voteSLO tracks the vote latency of the instances, which is nil if the
+	// SLO is not tracked
+	voteSLO *voteSLOTracker
+
 	criticalErrChan chan *CriticalError
 
 	quit chan struct{}
@@ -65,18 +106,55 @@ func NewFinalityProviderManager(
 	metrics *metrics.FpMetrics,
 	logger *zap.Logger,
 ) (*FinalityProviderManager, error) {
+	var signingPolicy *policy.Engine
+	if config.SigningPolicyFile != "" {
+		var err error
+		signingPolicy, err = policy.NewEngine(config.SigningPolicyFile, config.PolicyReloadInterval, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the signing policy: %w", err)
+		}
+	}
+
+	var submissionScript *policy.Script
+	if config.SubmissionScript != "" {
+		var err error
+		submissionScript, err = policy.NewScript(config.SubmissionScript, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the submission script: %w", err)
+		}
+	}
+
+	configuredHooks := make([]hooks.Hook, 0, len(config.HookCommands)+len(config.HookURLs))
+	for _, hookCmd := range config.HookCommands {
+		configuredHooks = append(configuredHooks, hooks.NewExecHook(hookCmd, config.HookTimeout))
+	}
+	for _, hookURL := range config.HookURLs {
+		configuredHooks = append(configuredHooks, hooks.NewWebhookHook(hookURL, config.HookTimeout))
+	}
+
 	return &FinalityProviderManager{
-		fpis:            make(map[string]*FinalityProviderInstance),
-		criticalErrChan: make(chan *CriticalError),
-		isStarted:       atomic.NewBool(false),
-		fps:             fps,
-		pubRandStore:    pubRandStore,
-		config:          config,
-		cc:              cc,
-		em:              em,
-		metrics:         metrics,
-		logger:          logger,
-		quit:            make(chan struct{}),
+		fpis:             make(map[string]*FinalityProviderInstance),
+		unloaded:         make(map[string]*unloadedInstance),
+		criticalErrChan:  make(chan *CriticalError),
+		isStarted:        atomic.NewBool(false),
+		fps:              fps,
+		pubRandStore:     pubRandStore,
+		config:           config,
+		cc:               cc,
+		em:               em,
+		metrics:          metrics,
+		limiter:          newSubmissionLimiter(config.MaxConcurrentSubmissions, config.SubmissionStagger),
+		signingPolicy:    signingPolicy,
+		submissionScript: submissionScript,
+		hooks:            hooks.NewDispatcher(logger, configuredHooks...),
+		voteSLO:          newVoteSLOTracker(config.VoteSLOTarget, config.VoteSLOMaxLatency, config.VoteSLOWindow),
+		chainParams:      newChainParamsCache(cc, config.ParamsRefreshInterval, logger),
+		chainClients:     make(map[string]*chainClient),
+		blockSources:     make(map[string]*sharedBlockSource),
+		chainHalts:       make(map[string]*chainHaltDetector),
+		cloneDetectors:   make(map[string]*cloneDetector),
+		logger:           logger,
+		quit:             make(chan struct{}),
 	}, nil
 }
 
@@ -105,6 +183,18 @@ func (fpm *FinalityProviderManager) monitorCriticalErr() {
 					zap.String("pk", criticalErr.fpBtcPk.MarshalHex()))
 				continue
 			}
+			if errors.Is(criticalErr.err, ErrSafeModeEntered) {
+				if err := fpm.setFinalityProviderSafeMode(fpi); err != nil {
+					fpm.logger.Error("failed to switch the finality-provider to safe mode",
+						zap.String("pk", criticalErr.fpBtcPk.MarshalHex()), zap.Error(err))
+					continue
+				}
+				fpm.logger.Error("the finality-provider has stopped broadcasting until it is resumed",
+					zap.String("pk", criticalErr.fpBtcPk.MarshalHex()), zap.Error(criticalErr.err))
+				continue
+			}
+			// deliver the error to the hooks before the daemon exits
+			fpm.hooks.Stop()
 			fpm.logger.Fatal(instanceTerminatingMsg,
 				zap.String("pk", criticalErr.fpBtcPk.MarshalHex()), zap.Error(criticalErr.err))
 		case <-fpm.quit:
@@ -119,6 +209,8 @@ func (fpm *FinalityProviderManager) monitorCriticalErr() {
 // 1. if power == 0 and slashed_height == 0, if status == ACTIVE, change to INACTIVE, otherwise remain the same
 // 2. if power == 0 and slashed_height > 0, set status to SLASHED and stop and remove the finality-provider instance
 // 3. if power > 0 (slashed_height must > 0), set status to ACTIVE
+// The instances staying INACTIVE for the idle unload delay are then unloaded,
+// and the unloaded ones regaining voting power are reloaded
 // NOTE: once error occurs, we log and continue as the status update is not critical to the entire program
 func (fpm *FinalityProviderManager) monitorStatusUpdate() {
 	defer fpm.wg.Done()
@@ -131,16 +223,33 @@ func (fpm *FinalityProviderManager) monitorStatusUpdate() {
 	statusUpdateTicker := time.NewTicker(fpm.config.StatusUpdateInterval)
 	defer statusUpdateTicker.Stop()
 
+	idleSince := make(map[string]time.Time)
+
 	for {
 		select {
 		case <-statusUpdateTicker.C:
-			latestBlock, err := fpm.getLatestBlockWithRetry()
-			if err != nil {
-				fpm.logger.Debug("failed to get the latest block", zap.Error(err))
-				continue
-			}
+			// the tip of each chain is queried once, and the finality
+			// providers of a chain whose tip is unknown are skipped
+			latestBlocks := make(map[string]*types.BlockInfo)
 			fpis := fpm.ListFinalityProviderInstances()
 			for _, fpi := range fpis {
+				// the status is kept in STANDBY while the chain is halted
+				if fpi.isChainHalted() {
+					continue
+				}
+				chainID := string(fpi.GetChainID())
+				latestBlock, ok := latestBlocks[chainID]
+				if !ok {
+					b, err := fpm.getLatestBlockWithRetry(chainID)
+					if err != nil {
+						fpm.logger.Debug("failed to get the latest block", zap.String("chain_id", chainID), zap.Error(err))
+					}
+					latestBlocks[chainID] = b
+					latestBlock = b
+				}
+				if latestBlock == nil {
+					continue
+				}
 				oldStatus := fpi.GetStatus()
 				power, err := fpi.GetVotingPowerWithRetry(latestBlock.Height)
 				if err != nil {
@@ -194,6 +303,8 @@ func (fpm *FinalityProviderManager) monitorStatusUpdate() {
 					)
 				}
 			}
+			fpm.unloadIdleInstances(idleSince)
+			fpm.reloadActiveInstances()
 		case <-fpm.quit:
 			return
 		}
@@ -208,6 +319,17 @@ func (fpm *FinalityProviderManager) setFinalityProviderSlashed(fpi *FinalityProv
 }
 
 func (fpm *FinalityProviderManager) StartFinalityProvider(fpPk *bbntypes.BIP340PubKey, passphrase string) error {
+	eotsKeys, err := fpm.listEOTSKeys()
+	if err != nil {
+		return err
+	}
+
+	return fpm.startFinalityProvider(fpPk, passphrase, eotsKeys)
+}
+
+// startFinalityProvider starts the instance of the finality provider, whose
+// EOTS key is checked against the given keys held by the EOTS manager
+func (fpm *FinalityProviderManager) startFinalityProvider(fpPk *bbntypes.BIP340PubKey, passphrase string, eotsKeys map[string]struct{}) error {
 	if !fpm.isStarted.Load() {
 		fpm.isStarted.Store(true)
 
@@ -216,13 +338,21 @@ func (fpm *FinalityProviderManager) StartFinalityProvider(fpPk *bbntypes.BIP340P
 
 		fpm.wg.Add(1)
 		go fpm.monitorStatusUpdate()
+
+		fpm.wg.Add(1)
+		go fpm.monitorChainHalt()
+
+		fpm.wg.Add(1)
+		go fpm.monitorClones()
 	}
 
-	if fpm.numOfRunningFinalityProviders() >= int(fpm.config.MaxNumFinalityProviders) {
+	// compared as uint64 as a large maximum overflows an int on 32-bit
+	// platforms
+	if uint64(fpm.numOfRunningFinalityProviders()) >= uint64(fpm.config.MaxNumFinalityProviders) {
 		return fmt.Errorf("reaching maximum number of running finality providers %v", fpm.config.MaxNumFinalityProviders)
 	}
 
-	if err := fpm.addFinalityProviderInstance(fpPk, passphrase); err != nil {
+	if err := fpm.addFinalityProviderInstance(fpPk, passphrase, eotsKeys); err != nil {
 		return err
 	}
 
@@ -238,6 +368,12 @@ func (fpm *FinalityProviderManager) StartAll() error {
 
 		fpm.wg.Add(1)
 		go fpm.monitorStatusUpdate()
+
+		fpm.wg.Add(1)
+		go fpm.monitorChainHalt()
+
+		fpm.wg.Add(1)
+		go fpm.monitorClones()
 	}
 
 	storedFps, err := fpm.fps.GetAllStoredFinalityProviders()
@@ -245,14 +381,41 @@ func (fpm *FinalityProviderManager) StartAll() error {
 		return err
 	}
 
+	// the keys are listed once rather than per finality provider, as
+	// listing them from a remote EOTS manager is a round trip
+	eotsKeys, err := fpm.listEOTSKeys()
+	if err != nil {
+		return err
+	}
+
 	for _, fp := range storedFps {
-		if fp.Status == proto.FinalityProviderStatus_CREATED || fp.Status == proto.FinalityProviderStatus_SLASHED {
+		if fp.Status == proto.FinalityProviderStatus_CREATED ||
+			fp.Status == proto.FinalityProviderStatus_SLASHED ||
+			fp.Status == proto.FinalityProviderStatus_SAFE_MODE {
 			fpm.logger.Info("the finality provider cannot be started with status",
 				zap.String("btc-pk", fp.GetBIP340BTCPK().MarshalHex()),
 				zap.String("status", fp.Status.String()))
 			continue
 		}
-		if err := fpm.StartFinalityProvider(fp.GetBIP340BTCPK(), ""); err != nil {
+		if fp.WatchOnly {
+			fpm.logger.Info("the finality provider is watch-only and not started",
+				zap.String("btc-pk", fp.GetBIP340BTCPK().MarshalHex()))
+			continue
+		}
+		if fp.Status == proto.FinalityProviderStatus_INACTIVE && fpm.idleUnloadEnabled() {
+			fpm.mu.Lock()
+			fpm.markUnloaded(fp.GetBIP340BTCPK(), fp.ChainID, "")
+			fpm.mu.Unlock()
+			fpm.logger.Info("the inactive finality provider is left unloaded until it regains voting power",
+				zap.String("btc-pk", fp.GetBIP340BTCPK().MarshalHex()))
+			continue
+		}
+		if err := fpm.startFinalityProvider(fp.GetBIP340BTCPK(), "", eotsKeys); err != nil {
+			if errors.Is(err, ErrEOTSKeyNotFound) {
+				fpm.logger.Error("refusing to start the finality provider without an EOTS key",
+					zap.String("btc-pk", fp.GetBIP340BTCPK().MarshalHex()))
+				continue
+			}
 			return err
 		}
 	}
@@ -265,25 +428,45 @@ func (fpm *FinalityProviderManager) Stop() error {
 		return fmt.Errorf("the finality-provider manager has already stopped")
 	}
 
-	var stopErr error
+	var (
+		stopErr error
+		errMu   sync.Mutex
+		stopWg  sync.WaitGroup
+	)
 
+	// the instances are stopped concurrently so that their in-flight
+	// submissions are drained within the same timeout
 	for _, fpi := range fpm.fpis {
 		if !fpi.IsRunning() {
 			continue
 		}
-		if err := fpi.Stop(); err != nil {
-			stopErr = err
-			break
-		}
-		fpm.metrics.DecrementRunningFpGauge()
+		stopWg.Add(1)
+		go func(fpi *FinalityProviderInstance) {
+			defer stopWg.Done()
+			if err := fpi.Stop(); err != nil {
+				errMu.Lock()
+				stopErr = err
+				errMu.Unlock()
+				return
+			}
+			fpm.metrics.DecrementRunningFpGauge()
+		}(fpi)
 	}
+	stopWg.Wait()
 
 	close(fpm.quit)
 	fpm.wg.Wait()
 
+	fpm.hooks.Stop()
+
 	return stopErr
 }
 
+// RegisterHook adds a hook invoked on the lifecycle events of the instances
+func (fpm *FinalityProviderManager) RegisterHook(h hooks.Hook) {
+	fpm.hooks.Register(h)
+}
+
 func (fpm *FinalityProviderManager) ListFinalityProviderInstances() []*FinalityProviderInstance {
 	fpm.mu.Lock()
 	defer fpm.mu.Unlock()
@@ -296,8 +479,11 @@ func (fpm *FinalityProviderManager) ListFinalityProviderInstances() []*FinalityP
 	return fpisList
 }
 
+// AllFinalityProviders returns the information of all the stored finality
+// providers, which is read from the replica of the store as it serves the
+// query RPCs
 func (fpm *FinalityProviderManager) AllFinalityProviders() ([]*proto.FinalityProviderInfo, error) {
-	storedFps, err := fpm.fps.GetAllStoredFinalityProviders()
+	storedFps, err := fpm.fps.GetAllFinalityProvidersFromReplica()
 	if err != nil {
 		return nil, err
 	}
@@ -316,8 +502,10 @@ func (fpm *FinalityProviderManager) AllFinalityProviders() ([]*proto.FinalityPro
 	return fpsInfo, nil
 }
 
+// FinalityProviderInfo returns the information of the finality provider,
+// which is read from the replica of the store as it serves the query RPCs
 func (fpm *FinalityProviderManager) FinalityProviderInfo(fpPk *bbntypes.BIP340PubKey) (*proto.FinalityProviderInfo, error) {
-	storedFp, err := fpm.fps.GetFinalityProvider(fpPk.MustToBTCPK())
+	storedFp, err := fpm.fps.GetFinalityProviderFromReplica(fpPk.MustToBTCPK())
 	if err != nil {
 		return nil, err
 	}
@@ -328,9 +516,36 @@ func (fpm *FinalityProviderManager) FinalityProviderInfo(fpPk *bbntypes.BIP340Pu
 		fpInfo.IsRunning = true
 	}
 
+	if storedFp.Status != proto.FinalityProviderStatus_CREATED {
+		fpm.fillVotingPower(fpInfo, storedFp.ChainID, storedFp.BtcPk)
+	}
+
 	return fpInfo, nil
 }
 
+// fillVotingPower sets the voting power of the finality provider at the tip
+// of its consumer chain, which is left zero if the chain is unreachable so
+// that the local information is still served
+func (fpm *FinalityProviderManager) fillVotingPower(fpInfo *proto.FinalityProviderInfo, chainID string, btcPk *btcec.PublicKey) {
+	cc := fpm.ccOf(chainID)
+	tipBlock, err := cc.QueryBestBlock()
+	if err != nil {
+		fpm.logger.Warn("failed to query the best block for the voting power",
+			zap.String("pk", fpInfo.BtcPkHex), zap.Error(err))
+		return
+	}
+
+	power, err := cc.QueryFinalityProviderVotingPower(btcPk, tipBlock.Height)
+	if err != nil {
+		fpm.logger.Warn("failed to query the voting power",
+			zap.String("pk", fpInfo.BtcPkHex), zap.Error(err))
+		return
+	}
+
+	fpInfo.VotingPower = power
+	fpInfo.VotingPowerHeight = tipBlock.Height
+}
+
 func (fpm *FinalityProviderManager) IsFinalityProviderRunning(fpPk *bbntypes.BIP340PubKey) bool {
 	fpm.mu.Lock()
 	defer fpm.mu.Unlock()
@@ -383,6 +598,7 @@ func (fpm *FinalityProviderManager) numOfRunningFinalityProviders() int {
 func (fpm *FinalityProviderManager) addFinalityProviderInstance(
 	pk *bbntypes.BIP340PubKey,
 	passphrase string,
+	eotsKeys map[string]struct{},
 ) error {
 	fpm.mu.Lock()
 	defer fpm.mu.Unlock()
@@ -392,41 +608,90 @@ func (fpm *FinalityProviderManager) addFinalityProviderInstance(
 		return fmt.Errorf("finality-provider instance already exists")
 	}
 
-	fpIns, err := NewFinalityProviderInstance(pk, fpm.config, fpm.fps, fpm.pubRandStore, fpm.cc, fpm.em, fpm.metrics, passphrase, fpm.criticalErrChan, fpm.logger)
+	if _, ok := eotsKeys[pkHex]; !ok {
+		return fmt.Errorf("%w: %s", ErrEOTSKeyNotFound, pkHex)
+	}
+
+	storedFp, err := fpm.fps.GetFinalityProvider(pk.MustToBTCPK())
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the finality-provider %s from DB: %w", pkHex, err)
+	}
+
+	fpIns, err := NewFinalityProviderInstance(pk, fpm.config, fpm.fps, fpm.pubRandStore, fpm.ccOf(storedFp.ChainID), fpm.em, fpm.metrics, passphrase, fpm.criticalErrChan, fpm.logger)
 	if err != nil {
 		return fmt.Errorf("failed to create finality-provider %s instance: %w", pkHex, err)
 	}
+	fpIns.limiter = fpm.limiter
+	fpIns.signingPolicy = fpm.signingPolicy
+	fpIns.submissionScript = fpm.submissionScript
+	fpIns.hooks = fpm.hooks
+	fpIns.voteSLO = fpm.voteSLO
+	fpIns.chainParams = fpm.chainParamsOf(storedFp.ChainID)
+	fpIns.blockSource = fpm.blockSourceOf(string(fpIns.GetChainID()))
+	fpIns.chainHalt = fpm.chainHaltOf(string(fpIns.GetChainID()))
+	fpm.watchClones(string(fpIns.GetChainID()))
 
 	if err := fpIns.Start(); err != nil {
 		return fmt.Errorf("failed to start finality-provider %s instance: %w", pkHex, err)
 	}
 
 	fpm.fpis[pkHex] = fpIns
+	delete(fpm.unloaded, pkHex)
 	fpm.metrics.IncrementRunningFpGauge()
 
 	return nil
 }
 
-func (fpm *FinalityProviderManager) getLatestBlockWithRetry() (*types.BlockInfo, error) {
+// blockSourceOf returns the block source shared by the pollers of the
+// instances of the given chain, which must be called with mu held
+func (fpm *FinalityProviderManager) blockSourceOf(chainID string) *sharedBlockSource {
+	src, ok := fpm.blockSources[chainID]
+	if !ok {
+		src = newSharedBlockSource(fpm.ccOf(chainID), fpm.config.PollerConfig.PollInterval)
+		fpm.blockSources[chainID] = src
+	}
+
+	return src
+}
+
+// listEOTSKeys returns the hex BTC public keys of the keys held by the EOTS
+// manager, which is not called with mu held as the EOTS manager may be remote
+func (fpm *FinalityProviderManager) listEOTSKeys() (map[string]struct{}, error) {
+	keys, err := fpm.em.ListKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the EOTS keys: %w", err)
+	}
+
+	pkHexes := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		pkHexes[hex.EncodeToString(k.PubKey)] = struct{}{}
+	}
+
+	return pkHexes, nil
+}
+
+// getLatestBlockWithRetry returns the tip of the given chain
+func (fpm *FinalityProviderManager) getLatestBlockWithRetry(chainID string) (*types.BlockInfo, error) {
 	var (
 		latestBlock *types.BlockInfo
 		err         error
 	)
 
+	cc := fpm.ccOf(chainID)
 	if err := retry.Do(func() error {
-		latestBlock, err = fpm.cc.QueryBestBlock()
+		latestBlock, err = cc.QueryBestBlock()
 		if err != nil {
 			return err
 		}
 		return nil
-	}, RtyAtt, RtyDel, RtyErr, retry.OnRetry(func(n uint, err error) {
+	}, queryRetryOpts(fpm.config.PollerConfig, func(n uint, err error) {
 		fpm.logger.Debug(
 			"failed to query the consumer chain for the latest block",
 			zap.Uint("attempt", n+1),
-			zap.Uint("max_attempts", RtyAttNum),
+			zap.Uint("max_attempts", fpm.config.PollerConfig.QueryRetryAttempts),
 			zap.Error(err),
 		)
-	})); err != nil {
+	})...); err != nil {
 		return nil, err
 	}
 
@@ -10,9 +10,11 @@ import (
 
 	"github.com/babylonchain/babylon/testutil/datagen"
 	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/babylonchain/finality-provider/clientcontroller"
@@ -58,10 +60,12 @@ func FuzzStatusUpdate(f *testing.F) {
 		mockClientController.EXPECT().QueryActivatedHeight().Return(uint64(1), nil).AnyTimes()
 		mockClientController.EXPECT().QueryBlock(gomock.Any()).Return(currentBlockRes, nil).AnyTimes()
 		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().QueryChainParams().Return(&types.ChainParams{}, nil).AnyTimes()
 
 		votingPower := uint64(r.Intn(2))
 		mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), currentHeight).Return(votingPower, nil).AnyTimes()
-		mockClientController.EXPECT().SubmitFinalitySig(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&types.TxResponse{TxHash: ""}, nil).AnyTimes()
+		mockClientController.EXPECT().SignFinalitySigTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(newSignedTx).AnyTimes()
+		mockClientController.EXPECT().BroadcastSignedTx(gomock.Any()).Return(&types.TxResponse{TxHash: ""}, nil).AnyTimes()
 		var slashedHeight uint64
 		if votingPower == 0 {
 			mockClientController.EXPECT().QueryFinalityProviderSlashed(gomock.Any()).Return(true, nil).AnyTimes()
@@ -86,6 +90,214 @@ func FuzzStatusUpdate(f *testing.F) {
 	})
 }
 
+// FuzzStatusUpdateOfChains tests that the status of the finality providers
+// of the chains configured by their own sections is updated at the tip of
+// their chain through the client of the chain
+func FuzzStatusUpdateOfChains(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		ctl := gomock.NewController(t)
+		defaultCC := mocks.NewMockClientController(ctl)
+		otherCC := mocks.NewMockClientController(ctl)
+		vm, registerFp, cleanUp := newFinalityProviderManager(t, defaultCC)
+		defer cleanUp()
+
+		otherChainID := datagen.GenRandomHexStr(r, 10)
+		vm.AddChainClient(otherChainID, otherCC)
+		defaultFpPk := registerFp(r, datagen.GenRandomHexStr(r, 10))
+		otherFpPk := registerFp(r, otherChainID)
+
+		// the chains are at different heights, so that a finality provider
+		// queried at the tip of the other chain fails the test
+		defaultHeight := uint64(r.Int63n(100) + 1)
+		otherHeight := defaultHeight + uint64(r.Int63n(100)+1)
+		for cc, height := range map[*mocks.MockClientController]uint64{defaultCC: defaultHeight, otherCC: otherHeight} {
+			b := &types.BlockInfo{Height: height, Hash: datagen.GenRandomByteArray(r, 32)}
+			cc.EXPECT().QueryBestBlock().Return(b, nil).AnyTimes()
+			cc.EXPECT().Close().Return(nil).AnyTimes()
+			cc.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+			cc.EXPECT().QueryActivatedHeight().Return(uint64(1), nil).AnyTimes()
+			cc.EXPECT().QueryBlock(gomock.Any()).Return(b, nil).AnyTimes()
+			cc.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), uint64(1)).Return(nil, nil).AnyTimes()
+			cc.EXPECT().QueryChainParams().Return(&types.ChainParams{}, nil).AnyTimes()
+			cc.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), height).Return(uint64(1), nil).AnyTimes()
+			cc.EXPECT().SignFinalitySigTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(newSignedTx).AnyTimes()
+			cc.EXPECT().BroadcastSignedTx(gomock.Any()).Return(&types.TxResponse{TxHash: ""}, nil).AnyTimes()
+		}
+
+		for _, fpPk := range []*bbntypes.BIP340PubKey{defaultFpPk, otherFpPk} {
+			err := vm.StartFinalityProvider(fpPk, passphrase)
+			require.NoError(t, err)
+			fpIns, err := vm.GetFinalityProviderInstance(fpPk)
+			require.NoError(t, err)
+			// stop the finality-provider as we are testing static functionalities
+			err = fpIns.Stop()
+			require.NoError(t, err)
+			waitForStatus(t, fpIns, proto.FinalityProviderStatus_ACTIVE)
+		}
+
+		fpInfo, err := vm.FinalityProviderInfo(otherFpPk)
+		require.NoError(t, err)
+		require.Equal(t, otherHeight, fpInfo.VotingPowerHeight)
+		fpInfo, err = vm.FinalityProviderInfo(defaultFpPk)
+		require.NoError(t, err)
+		require.Equal(t, defaultHeight, fpInfo.VotingPowerHeight)
+	})
+}
+
+// FuzzDetectClones tests that a running finality provider voted at a height
+// it has not signed is switched to safe mode, while its own votes and the
+// votes of the finality providers not run by the daemon are ignored
+func FuzzDetectClones(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		// the tip is far enough from the starting height for the votes
+		// checked behind the tip
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+10)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryBlock(gomock.Any()).Return(&types.BlockInfo{Height: currentHeight}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&types.TxResponse{TxHash: ""}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), gomock.Any()).Return(uint64(0), nil).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderSlashed(gomock.Any()).Return(false, nil).AnyTimes()
+		votes := make(map[uint64][]bbntypes.BIP340PubKey)
+		mockClientController.EXPECT().QueryVotesAtHeight(gomock.Any()).DoAndReturn(func(height uint64) ([]bbntypes.BIP340PubKey, error) {
+			return votes[height], nil
+		}).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+		fpPk := fpIns.GetBtcPkBIP340()
+
+		err := app.StartHandlingFinalityProvider(fpPk, passphrase)
+		require.NoError(t, err)
+		runningIns, err := app.GetFinalityProviderInstance(fpPk)
+		require.NoError(t, err)
+		// stop the finality-provider as the votes are checked directly
+		err = runningIns.Stop()
+		require.NoError(t, err)
+
+		// the first check starts from the tip
+		nextHeight, err := app.DetectClones(0)
+		require.NoError(t, err)
+		require.Less(t, nextHeight, currentHeight)
+		endHeight := nextHeight - 1
+
+		// the votes at the signed heights are submitted by this daemon
+		_, otherBtcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+		otherPk := bbntypes.NewBIP340PubKeyFromBTCPK(otherBtcPk)
+		clonedHeight := randomStartingHeight + uint64(r.Int63n(int64(endHeight-randomStartingHeight+1)))
+		for h := randomStartingHeight; h <= endHeight; h++ {
+			votes[h] = []bbntypes.BIP340PubKey{*otherPk}
+			if h == clonedHeight {
+				continue
+			}
+			votes[h] = append(votes[h], *fpPk)
+			err = app.GetFinalityProviderStore().SaveSignedBlockHash(fpPk.MustToBTCPK(), h, datagen.GenRandomByteArray(r, 32))
+			require.NoError(t, err)
+		}
+		nextHeight, err = app.DetectClones(randomStartingHeight)
+		require.NoError(t, err)
+		require.Equal(t, endHeight+1, nextHeight)
+		_, err = app.GetFinalityProviderInstance(fpPk)
+		require.NoError(t, err)
+
+		// the vote at the height not signed is submitted by another daemon
+		votes[clonedHeight] = append(votes[clonedHeight], *fpPk)
+		nextHeight, err = app.DetectClones(randomStartingHeight)
+		require.NoError(t, err)
+		require.Equal(t, endHeight+1, nextHeight)
+		_, err = app.GetFinalityProviderInstance(fpPk)
+		require.Error(t, err)
+		storedFp, err := app.GetFinalityProviderStore().GetFinalityProvider(fpPk.MustToBTCPK())
+		require.NoError(t, err)
+		require.Equal(t, proto.FinalityProviderStatus_SAFE_MODE, storedFp.Status)
+	})
+}
+
+// FuzzIdleUnload tests that the instance of a finality provider INACTIVE for
+// the idle unload delay is unloaded, and that it is reloaded once it regains
+// voting power, or left unloaded as SLASHED once it is slashed
+func FuzzIdleUnload(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+1)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		mockClientController.EXPECT().QueryBlock(gomock.Any()).Return(&types.BlockInfo{Height: currentHeight}, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLatestFinalizedBlocks(gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+		mockClientController.EXPECT().CommitPubRandList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&types.TxResponse{TxHash: ""}, nil).AnyTimes()
+		// the instances query the voting power concurrently with the test
+		votingPower := atomic.NewUint64(0)
+		slashed := atomic.NewBool(false)
+		mockClientController.EXPECT().QueryFinalityProviderVotingPower(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ *btcec.PublicKey, _ uint64) (uint64, error) {
+				return votingPower.Load(), nil
+			}).AnyTimes()
+		mockClientController.EXPECT().QueryFinalityProviderSlashed(gomock.Any()).DoAndReturn(
+			func(_ *btcec.PublicKey) (bool, error) {
+				return slashed.Load(), nil
+			}).AnyTimes()
+		app, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+		fpPk := fpIns.GetBtcPkBIP340()
+
+		// the instances are unloaded and reloaded by the test, as the
+		// status update runs at a longer interval than the test
+		app.GetConfig().IdleUnloadDelay = time.Hour
+		err := app.StartHandlingFinalityProvider(fpPk, passphrase)
+		require.NoError(t, err)
+		runningIns, err := app.GetFinalityProviderInstance(fpPk)
+		require.NoError(t, err)
+		err = runningIns.SetStatus(proto.FinalityProviderStatus_INACTIVE)
+		require.NoError(t, err)
+
+		// the instance is not unloaded until it is INACTIVE for the delay
+		idleSince := make(map[string]time.Time)
+		app.UnloadIdleInstances(idleSince)
+		require.Contains(t, idleSince, fpPk.MarshalHex())
+		require.False(t, app.IsFinalityProviderUnloaded(fpPk))
+		require.True(t, runningIns.IsRunning())
+
+		idleSince[fpPk.MarshalHex()] = time.Now().Add(-2 * time.Hour)
+		app.UnloadIdleInstances(idleSince)
+		require.True(t, app.IsFinalityProviderUnloaded(fpPk))
+		require.False(t, runningIns.IsRunning())
+		require.Empty(t, app.ListFinalityProviderInstances())
+
+		// the instance stays unloaded while it has no voting power
+		app.ReloadActiveInstances()
+		require.True(t, app.IsFinalityProviderUnloaded(fpPk))
+
+		if r.Intn(2) == 0 {
+			votingPower.Store(1)
+			app.ReloadActiveInstances()
+			require.False(t, app.IsFinalityProviderUnloaded(fpPk))
+			require.Len(t, app.ListFinalityProviderInstances(), 1)
+			require.True(t, app.ListFinalityProviderInstances()[0].IsRunning())
+		} else {
+			slashed.Store(true)
+			app.ReloadActiveInstances()
+			require.False(t, app.IsFinalityProviderUnloaded(fpPk))
+			require.Empty(t, app.ListFinalityProviderInstances())
+			storedFp, err := app.GetFinalityProviderStore().GetFinalityProvider(fpPk.MustToBTCPK())
+			require.NoError(t, err)
+			require.Equal(t, proto.FinalityProviderStatus_SLASHED, storedFp.Status)
+		}
+	})
+}
+
 func waitForStatus(t *testing.T, fpIns *service.FinalityProviderInstance, s proto.FinalityProviderStatus) {
 	require.Eventually(t,
 		func() bool {
@@ -94,6 +306,16 @@ func waitForStatus(t *testing.T, fpIns *service.FinalityProviderInstance, s prot
 }
 
 func newFinalityProviderManagerWithRegisteredFp(t *testing.T, r *rand.Rand, cc clientcontroller.ClientController) (*service.FinalityProviderManager, *bbntypes.BIP340PubKey, func()) {
+	vm, registerFp, cleanUp := newFinalityProviderManager(t, cc)
+	btcPk := registerFp(r, datagen.GenRandomHexStr(r, 10))
+
+	return vm, btcPk, cleanUp
+}
+
+// newFinalityProviderManager creates a finality provider manager along with a
+// function that stores a registered finality provider of the given chain
+func newFinalityProviderManager(t *testing.T, cc clientcontroller.ClientController) (
+	*service.FinalityProviderManager, func(r *rand.Rand, chainID string) *bbntypes.BIP340PubKey, func()) {
 	logger := zap.NewNop()
 	// create an EOTS manager
 	eotsHomeDir := filepath.Join(t.TempDir(), "eots-home")
@@ -129,35 +351,38 @@ func newFinalityProviderManagerWithRegisteredFp(t *testing.T, r *rand.Rand, cc c
 	require.NoError(t, err)
 
 	// create registered finality-provider
-	keyName := datagen.GenRandomHexStr(r, 10)
-	chainID := datagen.GenRandomHexStr(r, 10)
-	kc, err := keyring.NewChainKeyringControllerWithKeyring(kr, keyName, input)
-	require.NoError(t, err)
-	btcPkBytes, err := em.CreateKey(keyName, passphrase, hdPath)
-	require.NoError(t, err)
-	btcPk, err := bbntypes.NewBIP340PubKey(btcPkBytes)
-	require.NoError(t, err)
-	keyInfo, err := kc.CreateChainKey(passphrase, hdPath, "")
-	require.NoError(t, err)
-	bbnPk := &secp256k1.PubKey{Key: keyInfo.PublicKey.SerializeCompressed()}
-	fpRecord, err := em.KeyRecord(btcPk.MustMarshal(), passphrase)
-	require.NoError(t, err)
-	pop, err := kc.CreatePop(fpRecord.PrivKey, passphrase)
-	require.NoError(t, err)
+	registerFp := func(r *rand.Rand, chainID string) *bbntypes.BIP340PubKey {
+		keyName := datagen.GenRandomHexStr(r, 10)
+		kc, err := keyring.NewChainKeyringControllerWithKeyring(kr, keyName, input)
+		require.NoError(t, err)
+		btcPkBytes, err := em.CreateKey(keyName, passphrase, hdPath)
+		require.NoError(t, err)
+		btcPk, err := bbntypes.NewBIP340PubKey(btcPkBytes)
+		require.NoError(t, err)
+		keyInfo, err := kc.CreateChainKey(passphrase, hdPath, "")
+		require.NoError(t, err)
+		bbnPk := &secp256k1.PubKey{Key: keyInfo.PublicKey.SerializeCompressed()}
+		fpRecord, err := em.KeyRecord(btcPk.MustMarshal(), passphrase)
+		require.NoError(t, err)
+		pop, err := kc.CreatePop(fpRecord.PrivKey, passphrase)
+		require.NoError(t, err)
 
-	err = fpStore.CreateFinalityProvider(
-		bbnPk,
-		btcPk.MustToBTCPK(),
-		testutil.RandomDescription(r),
-		testutil.ZeroCommissionRate(),
-		keyName,
-		chainID,
-		pop.BabylonSig,
-		pop.BtcSig,
-	)
-	require.NoError(t, err)
-	err = fpStore.SetFpStatus(btcPk.MustToBTCPK(), proto.FinalityProviderStatus_REGISTERED)
-	require.NoError(t, err)
+		err = fpStore.CreateFinalityProvider(
+			bbnPk,
+			btcPk.MustToBTCPK(),
+			testutil.RandomDescription(r),
+			testutil.ZeroCommissionRate(),
+			keyName,
+			chainID,
+			pop.BabylonSig,
+			pop.BtcSig,
+		)
+		require.NoError(t, err)
+		err = fpStore.SetFpStatus(btcPk.MustToBTCPK(), proto.FinalityProviderStatus_REGISTERED)
+		require.NoError(t, err)
+
+		return btcPk
+	}
 
 	cleanUp := func() {
 		err = vm.Stop()
@@ -172,5 +397,11 @@ func newFinalityProviderManagerWithRegisteredFp(t *testing.T, r *rand.Rand, cc c
 		require.NoError(t, err)
 	}
 
-	return vm, btcPk, cleanUp
+	return vm, registerFp, cleanUp
+}
+
+// newSignedTx returns a distinct signed tx on each call, as the instance
+// signing it sets its Accepted
+func newSignedTx(*btcec.PublicKey, *types.BlockInfo, *btcec.FieldVal, []byte, *btcec.ModNScalar) (*types.SignedTx, error) {
+	return &types.SignedTx{}, nil
 }
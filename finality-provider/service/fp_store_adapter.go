@@ -1,6 +1,10 @@
 package service
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"sync"
 
 	sdkmath "cosmossdk.io/math"
@@ -11,8 +15,11 @@ import (
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"go.uber.org/zap"
 
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/types"
 )
 
 type createFinalityProviderResponse struct {
@@ -37,6 +44,7 @@ type registerFinalityProviderRequest struct {
 	pop             *btcstakingtypes.ProofOfPossession
 	description     *stakingtypes.Description
 	commission      *sdkmath.LegacyDec
+	chainID         string
 	errResponse     chan error
 	successResponse chan *RegisterFinalityProviderResponse
 }
@@ -58,6 +66,16 @@ type CreateFinalityProviderResult struct {
 	FpInfo *proto.FinalityProviderInfo
 }
 
+// StateValidationResult is the outcome of checking the stored finality
+// providers against the keys held by the EOTS manager
+type StateValidationResult struct {
+	// FpsMissingKeys are the hex BTC public keys of the stored
+	// finality providers that have no key in the EOTS manager
+	FpsMissingKeys []string
+	// OrphanedKeys are the EOTS keys that have no stored finality provider
+	OrphanedKeys []*eotstypes.KeyInfo
+}
+
 type fpState struct {
 	mu sync.Mutex
 	fp *store.StoredFinalityProvider
@@ -94,6 +112,26 @@ func (fps *fpState) setLastProcessedHeight(height uint64) error {
 	return fps.s.SetFpLastProcessedHeight(fps.fp.BtcPk, height)
 }
 
+func (fps *fpState) setFinalizedHeight(height uint64) error {
+	fps.mu.Lock()
+	if fps.fp.FinalizedHeight < height {
+		fps.fp.FinalizedHeight = height
+	}
+	fps.mu.Unlock()
+	return fps.s.SetFpFinalizedHeight(fps.fp.BtcPk, height)
+}
+
+// rewindLastProcessedHeight rewinds the last processed height in memory only,
+// so that the heights after it are processed again, while the stored height
+// keeps the highest processed one
+func (fps *fpState) rewindLastProcessedHeight(height uint64) {
+	fps.mu.Lock()
+	defer fps.mu.Unlock()
+	if fps.fp.LastProcessedHeight > height {
+		fps.fp.LastProcessedHeight = height
+	}
+}
+
 func (fps *fpState) setLastProcessedAndVotedHeight(height uint64) error {
 	fps.mu.Lock()
 	fps.fp.LastVotedHeight = height
@@ -130,12 +168,33 @@ func (fp *FinalityProviderInstance) GetLastProcessedHeight() uint64 {
 	return fp.fpState.getStoreFinalityProvider().LastProcessedHeight
 }
 
+func (fp *FinalityProviderInstance) GetFinalizedHeight() uint64 {
+	return fp.fpState.getStoreFinalityProvider().FinalizedHeight
+}
+
+func (fp *FinalityProviderInstance) GetActivationHeight() uint64 {
+	return fp.fpState.getStoreFinalityProvider().ActivationHeight
+}
+
 func (fp *FinalityProviderInstance) GetChainID() []byte {
 	return []byte(fp.fpState.getStoreFinalityProvider().ChainID)
 }
 
 func (fp *FinalityProviderInstance) SetStatus(s proto.FinalityProviderStatus) error {
-	return fp.fpState.setStatus(s)
+	oldStatus := fp.GetStatus()
+	if err := fp.fpState.setStatus(s); err != nil {
+		return err
+	}
+
+	if oldStatus != s {
+		fp.emitEvent(&hooks.Event{
+			Type:      hooks.EventStatusChanged,
+			OldStatus: oldStatus.String(),
+			Status:    s.String(),
+		})
+	}
+
+	return nil
 }
 
 func (fp *FinalityProviderInstance) MustSetStatus(s proto.FinalityProviderStatus) {
@@ -169,3 +228,34 @@ func (fp *FinalityProviderInstance) MustUpdateStateAfterFinalitySigSubmission(he
 	fp.metrics.RecordFpLastVotedHeight(fp.GetBtcPkHex(), height)
 	fp.metrics.RecordFpLastProcessedHeight(fp.GetBtcPkHex(), height)
 }
+
+// hasSignedBlock returns true if the given block has been signed, i.e., the
+// height is processed again after a restart and needs no vote
+func (fp *FinalityProviderInstance) hasSignedBlock(b *types.BlockInfo) bool {
+	signedHash, err := fp.fpState.s.GetSignedBlockHash(fp.GetBtcPk(), b.Height)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(signedHash, b.Hash)
+}
+
+// checkAndSaveSignedBlock refuses to sign the given block if a different block
+// has been signed at the same height, e.g., before a reorg of the consumer chain,
+// as signing two blocks at the same height leaks the EOTS private key.
+// Otherwise, the block is recorded as signed
+func (fp *FinalityProviderInstance) checkAndSaveSignedBlock(b *types.BlockInfo) error {
+	signedHash, err := fp.fpState.s.GetSignedBlockHash(fp.GetBtcPk(), b.Height)
+	if err == nil {
+		if !bytes.Equal(signedHash, b.Hash) {
+			return fmt.Errorf("%w: height %d, signed hash %s, new hash %s", ErrConflictingBlockHash,
+				b.Height, hex.EncodeToString(signedHash), hex.EncodeToString(b.Hash))
+		}
+		return nil
+	}
+	if !errors.Is(err, store.ErrSignedBlockNotFound) {
+		return err
+	}
+
+	return fp.fpState.s.SaveSignedBlockHash(fp.GetBtcPk(), b.Height, b.Hash)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	sdkmath "cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+// genesisUint64 is a uint64 encoded either as a JSON number or as a JSON
+// string, the latter of which is how the genesis encodes 64-bit integers
+type genesisUint64 uint64
+
+func (u *genesisUint64) UnmarshalJSON(bz []byte) error {
+	v, err := strconv.ParseUint(string(bytes.Trim(bz, `"`)), 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = genesisUint64(v)
+
+	return nil
+}
+
+// genesisFinalityProvider is a finality provider registered on the consumer
+// chain as it is exported in the genesis or queried from the chain
+type genesisFinalityProvider struct {
+	Description *stakingtypes.Description `json:"description"`
+	Commission  string                    `json:"commission"`
+	BabylonPk   *secp256k1.PubKey         `json:"babylon_pk"`
+	BtcPk       string                    `json:"btc_pk"`
+	Pop         *struct {
+		BabylonSig []byte `json:"babylon_sig"`
+		BtcSig     []byte `json:"btc_sig"`
+	} `json:"pop"`
+	SlashedBabylonHeight genesisUint64 `json:"slashed_babylon_height"`
+	SlashedBtcHeight     genesisUint64 `json:"slashed_btc_height"`
+}
+
+// genesisExport is either a genesis of the consumer chain or a snapshot of
+// its finality providers
+type genesisExport struct {
+	ChainID  string `json:"chain_id"`
+	AppState *struct {
+		BTCStaking *struct {
+			FinalityProviders []*genesisFinalityProvider `json:"finality_providers"`
+		} `json:"btcstaking"`
+	} `json:"app_state"`
+	FinalityProviders []*genesisFinalityProvider `json:"finality_providers"`
+}
+
+// GenesisImportResult lists the BTC public keys of the finality providers
+// by the outcome of the import
+type GenesisImportResult struct {
+	Imported []string `json:"imported"`
+	// Existing are the finality providers that are already stored
+	Existing []string `json:"existing"`
+	// MissingKeys are the finality providers not imported as their EOTS
+	// keys are not held by the EOTS manager
+	MissingKeys []string `json:"missing_keys"`
+}
+
+// parseGenesisFinalityProviders parses the finality providers from a genesis,
+// a snapshot of the form {"finality_providers": [...]}, or a plain list of
+// finality providers. The chain ID is empty unless the genesis specifies it
+func parseGenesisFinalityProviders(bz []byte) ([]*genesisFinalityProvider, string, error) {
+	bz = bytes.TrimSpace(bz)
+	if len(bz) > 0 && bz[0] == '[' {
+		var fps []*genesisFinalityProvider
+		if err := json.Unmarshal(bz, &fps); err != nil {
+			return nil, "", fmt.Errorf("invalid list of finality providers: %w", err)
+		}
+		return fps, "", nil
+	}
+
+	var export genesisExport
+	if err := json.Unmarshal(bz, &export); err != nil {
+		return nil, "", fmt.Errorf("invalid genesis: %w", err)
+	}
+	if export.AppState != nil && export.AppState.BTCStaking != nil {
+		return export.AppState.BTCStaking.FinalityProviders, export.ChainID, nil
+	}
+
+	return export.FinalityProviders, export.ChainID, nil
+}
+
+func (gfp *genesisFinalityProvider) toProto(keyName, chainID string, watchOnly bool) (*proto.FinalityProvider, error) {
+	btcPk, err := bbntypes.NewBIP340PubKeyFromHex(gfp.BtcPk)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BTC public key %s: %w", gfp.BtcPk, err)
+	}
+	btcPubKey, err := btcPk.ToBTCPK()
+	if err != nil {
+		return nil, fmt.Errorf("invalid BTC public key %s: %w", gfp.BtcPk, err)
+	}
+
+	if gfp.BabylonPk == nil || len(gfp.BabylonPk.Key) != secp256k1.PubKeySize {
+		return nil, fmt.Errorf("invalid chain public key of %s", gfp.BtcPk)
+	}
+	if gfp.Pop == nil || len(gfp.Pop.BabylonSig) == 0 || len(gfp.Pop.BtcSig) == 0 {
+		return nil, fmt.Errorf("missing proof of possession of %s", gfp.BtcPk)
+	}
+
+	commission, err := sdkmath.LegacyNewDecFromStr(gfp.Commission)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commission of %s: %w", gfp.BtcPk, err)
+	}
+
+	description := gfp.Description
+	if description == nil {
+		description = &stakingtypes.Description{}
+	}
+	desBytes, err := description.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("invalid description of %s: %w", gfp.BtcPk, err)
+	}
+
+	status := proto.FinalityProviderStatus_REGISTERED
+	if gfp.SlashedBabylonHeight > 0 || gfp.SlashedBtcHeight > 0 {
+		status = proto.FinalityProviderStatus_SLASHED
+	}
+
+	return &proto.FinalityProvider{
+		ChainPk:     gfp.BabylonPk.Key,
+		BtcPk:       schnorr.SerializePubKey(btcPubKey),
+		Description: desBytes,
+		Commission:  commission.String(),
+		Pop: &proto.ProofOfPossession{
+			ChainSig: gfp.Pop.BabylonSig,
+			BtcSig:   gfp.Pop.BtcSig,
+		},
+		KeyName:   keyName,
+		ChainId:   chainID,
+		Status:    status,
+		WatchOnly: watchOnly,
+	}, nil
+}
+
+// ImportGenesisFinalityProviders pre-populates the store with the finality
+// providers registered in the genesis or the snapshot of the consumer chain.
+// In watch-only mode, all of them are imported to be tracked without being
+// run. Otherwise, em must be set and only the finality providers whose EOTS
+// keys are held by it are imported to be run by the daemon, with keyName as
+// the chain key
+func ImportGenesisFinalityProviders(
+	fps *store.FinalityProviderStore,
+	em eotsmanager.EOTSManager,
+	genesis []byte,
+	keyName, chainID string,
+	watchOnly bool,
+) (*GenesisImportResult, error) {
+	genesisFps, genesisChainID, err := parseGenesisFinalityProviders(genesis)
+	if err != nil {
+		return nil, err
+	}
+	if genesisChainID != "" && genesisChainID != chainID {
+		return nil, fmt.Errorf("the genesis is of chain %s rather than %s", genesisChainID, chainID)
+	}
+
+	res := &GenesisImportResult{
+		Imported:    make([]string, 0),
+		Existing:    make([]string, 0),
+		MissingKeys: make([]string, 0),
+	}
+
+	keys := make(map[string]struct{})
+	if !watchOnly {
+		keyInfos, err := em.ListKeys()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the EOTS keys: %w", err)
+		}
+		for _, k := range keyInfos {
+			keys[hex.EncodeToString(k.PubKey)] = struct{}{}
+		}
+	}
+
+	records := make([]*proto.FinalityProvider, 0, len(genesisFps))
+	for _, gfp := range genesisFps {
+		fp, err := gfp.toProto(keyName, chainID, watchOnly)
+		if err != nil {
+			return nil, err
+		}
+		pkHex := hex.EncodeToString(fp.BtcPk)
+		if _, ok := keys[pkHex]; !watchOnly && !ok {
+			res.MissingKeys = append(res.MissingKeys, pkHex)
+			continue
+		}
+		records = append(records, fp)
+	}
+
+	existing, err := fps.ImportFinalityProviders(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the imported finality providers: %w", err)
+	}
+	existingPks := make(map[string]struct{}, len(existing))
+	for _, pk := range existing {
+		pkHex := hex.EncodeToString(pk)
+		existingPks[pkHex] = struct{}{}
+		res.Existing = append(res.Existing, pkHex)
+	}
+	for _, fp := range records {
+		pkHex := hex.EncodeToString(fp.BtcPk)
+		if _, ok := existingPks[pkHex]; !ok {
+			res.Imported = append(res.Imported, pkHex)
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	eotstypes "github.com/babylonchain/finality-provider/eotsmanager/types"
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+)
+
+func newGenesisFinalityProvider(t *testing.T) (*genesisFinalityProvider, *btcec.PublicKey) {
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	gfp := &genesisFinalityProvider{
+		Commission: "0.05",
+		BabylonPk:  secp256k1.GenPrivKey().PubKey().(*secp256k1.PubKey),
+		BtcPk:      hex.EncodeToString(schnorr.SerializePubKey(sk.PubKey())),
+	}
+	gfp.Pop = &struct {
+		BabylonSig []byte `json:"babylon_sig"`
+		BtcSig     []byte `json:"btc_sig"`
+	}{BabylonSig: []byte("babylon-sig"), BtcSig: []byte("btc-sig")}
+
+	return gfp, sk.PubKey()
+}
+
+func TestParseGenesisFinalityProviders(t *testing.T) {
+	gfp, _ := newGenesisFinalityProvider(t)
+	fpJSON, err := json.Marshal(gfp)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name            string
+		genesis         string
+		expectedChainID string
+	}{
+		{
+			name:            "a genesis",
+			genesis:         fmt.Sprintf(`{"chain_id": "chain-test", "app_state": {"btcstaking": {"finality_providers": [%s]}}}`, fpJSON),
+			expectedChainID: "chain-test",
+		},
+		{
+			name:    "a snapshot",
+			genesis: fmt.Sprintf(`{"finality_providers": [%s]}`, fpJSON),
+		},
+		{
+			name:    "a list of finality providers",
+			genesis: fmt.Sprintf(` [%s]`, fpJSON),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fps, chainID, err := parseGenesisFinalityProviders([]byte(tc.genesis))
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedChainID, chainID)
+			require.Len(t, fps, 1)
+			require.Equal(t, gfp.BtcPk, fps[0].BtcPk)
+		})
+	}
+
+	// the genesis encodes the heights as strings
+	var slashed genesisFinalityProvider
+	require.NoError(t, json.Unmarshal([]byte(`{"slashed_babylon_height": "10", "slashed_btc_height": 20}`), &slashed))
+	require.Equal(t, genesisUint64(10), slashed.SlashedBabylonHeight)
+	require.Equal(t, genesisUint64(20), slashed.SlashedBtcHeight)
+}
+
+func TestImportGenesisFinalityProviders(t *testing.T) {
+	chainID := "chain-test"
+	ownedFp, ownedPk := newGenesisFinalityProvider(t)
+	otherFp, otherPk := newGenesisFinalityProvider(t)
+	otherFp.SlashedBtcHeight = 100
+	genesis, err := json.Marshal(map[string]interface{}{
+		"finality_providers": []*genesisFinalityProvider{ownedFp, otherFp},
+	})
+	require.NoError(t, err)
+
+	newStore := func(t *testing.T) *store.FinalityProviderStore {
+		db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, db.Close())
+		})
+		s, err := store.NewFinalityProviderStore(db)
+		require.NoError(t, err)
+
+		return s
+	}
+
+	t.Run("only the finality providers with the EOTS keys are imported", func(t *testing.T) {
+		s := newStore(t)
+		em := mocks.NewMockEOTSManager(gomock.NewController(t))
+		em.EXPECT().ListKeys().Return([]*eotstypes.KeyInfo{{Name: "key", PubKey: schnorr.SerializePubKey(ownedPk)}}, nil).Times(2)
+
+		res, err := ImportGenesisFinalityProviders(s, em, genesis, "chain-key", chainID, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{ownedFp.BtcPk}, res.Imported)
+		require.Empty(t, res.Existing)
+		require.Equal(t, []string{otherFp.BtcPk}, res.MissingKeys)
+
+		stored, err := s.GetFinalityProvider(ownedPk)
+		require.NoError(t, err)
+		require.Equal(t, "chain-key", stored.KeyName)
+		require.Equal(t, chainID, stored.ChainID)
+		require.Equal(t, proto.FinalityProviderStatus_REGISTERED, stored.Status)
+		require.False(t, stored.WatchOnly)
+
+		// the import is idempotent
+		res, err = ImportGenesisFinalityProviders(s, em, genesis, "chain-key", chainID, false)
+		require.NoError(t, err)
+		require.Empty(t, res.Imported)
+		require.Equal(t, []string{ownedFp.BtcPk}, res.Existing)
+	})
+
+	t.Run("all the finality providers are imported in watch-only mode", func(t *testing.T) {
+		s := newStore(t)
+
+		res, err := ImportGenesisFinalityProviders(s, nil, genesis, "", chainID, true)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{ownedFp.BtcPk, otherFp.BtcPk}, res.Imported)
+
+		stored, err := s.GetFinalityProvider(otherPk)
+		require.NoError(t, err)
+		require.True(t, stored.WatchOnly)
+		require.Equal(t, proto.FinalityProviderStatus_SLASHED, stored.Status)
+	})
+
+	t.Run("the genesis of another chain is rejected", func(t *testing.T) {
+		s := newStore(t)
+		otherGenesis := []byte(`{"chain_id": "chain-other", "app_state": {"btcstaking": {"finality_providers": []}}}`)
+
+		_, err := ImportGenesisFinalityProviders(s, nil, otherGenesis, "", chainID, true)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+// healthCheckTimeout bounds each check that talks to another service, so
+// that a hanging dependency fails the probe instead of blocking it
+const healthCheckTimeout = 5 * time.Second
+
+const healthCheckOK = "ok"
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+	DB     *dbHealth         `json:"db,omitempty"`
+}
+
+// dbHealth are the statistics of the database reported by the probes, which
+// do not fail them but show the bloat of the store
+type dbHealth struct {
+	FileSizeBytes  int64             `json:"file_size_bytes"`
+	BucketKeys     map[string]uint64 `json:"bucket_keys"`
+	LastCompaction *time.Time        `json:"last_compaction,omitempty"`
+}
+
+func newDBHealth(stats *store.DBStats) *dbHealth {
+	if stats == nil {
+		return nil
+	}
+
+	h := &dbHealth{
+		FileSizeBytes: stats.FileSize,
+		BucketKeys:    stats.BucketKeys,
+	}
+	if !stats.LastCompaction.IsZero() {
+		h.LastCompaction = &stats.LastCompaction
+	}
+
+	return h
+}
+
+// Liveness checks that the app is running and its store is readable, a
+// failure of which can only be recovered by restarting the daemon
+func (app *FinalityProviderApp) Liveness() map[string]error {
+	checks := make(map[string]error)
+
+	select {
+	case <-app.quit:
+		checks["app"] = ErrFinalityProviderShutDown
+	default:
+		checks["app"] = nil
+	}
+
+	_, err := app.fps.GetAllFinalityProviderRecords()
+	checks["store_readable"] = err
+
+	return checks
+}
+
+// Readiness checks that the daemon can actually vote, i.e., the consumer chain
+// and the EOTS manager are reachable, the store is writable and all the
+// registered finality providers are running
+func (app *FinalityProviderApp) Readiness() map[string]error {
+	checks := make(map[string]error)
+
+	checks["chain"] = withTimeout(healthCheckTimeout, func() error {
+		_, err := app.cc.QueryBestBlock()
+		return err
+	})
+	checks["eots_manager"] = withTimeout(healthCheckTimeout, func() error {
+		_, err := app.eotsManager.ListKeys()
+		return err
+	})
+	checks["store_writable"] = app.fps.CheckWritable()
+
+	// a standby does not run any finality provider by design
+	if !app.IsStandby() {
+		checks["instances"] = app.checkInstancesRunning()
+	}
+
+	return checks
+}
+
+func (app *FinalityProviderApp) checkInstancesRunning() error {
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return err
+	}
+
+	var notRunning []string
+	for _, fp := range storedFps {
+		switch fp.Status {
+		case proto.FinalityProviderStatus_REGISTERED,
+			proto.FinalityProviderStatus_ACTIVE,
+			proto.FinalityProviderStatus_INACTIVE,
+			proto.FinalityProviderStatus_STANDBY:
+			// the idle instances are unloaded on purpose
+			if !app.fpManager.IsFinalityProviderRunning(fp.GetBIP340BTCPK()) &&
+				!app.fpManager.IsFinalityProviderUnloaded(fp.GetBIP340BTCPK()) {
+				notRunning = append(notRunning, fp.GetBIP340BTCPK().MarshalHex())
+			}
+		}
+	}
+
+	if len(notRunning) > 0 {
+		return fmt.Errorf("the finality providers are not running: %s", strings.Join(notRunning, ", "))
+	}
+
+	return nil
+}
+
+func withTimeout(timeout time.Duration, check func() error) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- check()
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %v", timeout)
+	}
+}
+
+// startHealthServer serves the /livez and /readyz probes, which respond with
+// 200 if all the checks pass and 503 otherwise
+func startHealthServer(addr string, app *FinalityProviderApp, logger *zap.Logger) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", healthHandler(app.Liveness, app.DBStats))
+	mux.HandleFunc("/readyz", healthHandler(app.Readiness, app.DBStats))
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: healthCheckTimeout,
+	}
+
+	go func() {
+		logger.Info("Health probe server is starting", zap.String("addr", addr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Health probe server failed to start", zap.Error(err))
+		}
+	}()
+
+	return server
+}
+
+func healthHandler(checkFn func() map[string]error, dbStatsFn func() *store.DBStats) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res := healthResponse{
+			Status: healthCheckOK,
+			Checks: make(map[string]string),
+			DB:     newDBHealth(dbStatsFn()),
+		}
+		for name, err := range checkFn() {
+			if err != nil {
+				res.Status = "fail"
+				res.Checks[name] = err.Error()
+				continue
+			}
+			res.Checks[name] = healthCheckOK
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if res.Status != healthCheckOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(res)
+	}
+}
+
+func stopHealthServer(server *http.Server, logger *zap.Logger) {
+	if err := server.Shutdown(context.Background()); err != nil {
+		logger.Error("Health probe server shutdown failed", zap.Error(err))
+	}
+}
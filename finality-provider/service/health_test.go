@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+func TestHealthHandler(t *testing.T) {
+	dbStats := func() *store.DBStats {
+		return &store.DBStats{FileSize: 1024, BucketKeys: map[string]uint64{"finalityProviders": 2}}
+	}
+
+	testCases := []struct {
+		name           string
+		checks         map[string]error
+		expectedCode   int
+		expectedStatus string
+	}{
+		{
+			name:           "the probe passes if all the checks pass",
+			checks:         map[string]error{"chain": nil, "store_writable": nil},
+			expectedCode:   http.StatusOK,
+			expectedStatus: healthCheckOK,
+		},
+		{
+			name:           "the probe fails if any check fails",
+			checks:         map[string]error{"chain": errors.New("the chain is unreachable"), "store_writable": nil},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: "fail",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := healthHandler(func() map[string]error { return tc.checks }, dbStats)
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tc.expectedCode, rec.Code)
+			var res healthResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			require.Equal(t, tc.expectedStatus, res.Status)
+			require.Len(t, res.Checks, len(tc.checks))
+			for name, err := range tc.checks {
+				if err != nil {
+					require.Equal(t, err.Error(), res.Checks[name])
+					continue
+				}
+				require.Equal(t, healthCheckOK, res.Checks[name])
+			}
+			require.Equal(t, int64(1024), res.DB.FileSizeBytes)
+			require.Nil(t, res.DB.LastCompaction)
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	checkErr := errors.New("the check failed")
+	require.ErrorIs(t, withTimeout(time.Second, func() error { return checkErr }), checkErr)
+
+	release := make(chan struct{})
+	defer close(release)
+	err := withTimeout(10*time.Millisecond, func() error {
+		<-release
+		return nil
+	})
+	require.ErrorContains(t, err, "timed out")
+}
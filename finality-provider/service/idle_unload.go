@@ -0,0 +1,197 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// unloadedInstance is a finality provider whose idle instance is unloaded
+// from memory, which is kept to reload the instance once it is needed again
+type unloadedInstance struct {
+	pk      *bbntypes.BIP340PubKey
+	chainID string
+	// passphrase is the one the instance was started with
+	passphrase string
+}
+
+// idleUnloadEnabled returns whether the idle instances are unloaded, which
+// needs the status update to reload them once they regain voting power
+func (fpm *FinalityProviderManager) idleUnloadEnabled() bool {
+	return fpm.config.IdleUnloadDelay > 0 && fpm.config.StatusUpdateInterval > 0
+}
+
+// IsFinalityProviderUnloaded returns whether the instance of the finality
+// provider is unloaded from memory as it is idle
+func (fpm *FinalityProviderManager) IsFinalityProviderUnloaded(fpPk *bbntypes.BIP340PubKey) bool {
+	fpm.mu.Lock()
+	defer fpm.mu.Unlock()
+
+	_, unloaded := fpm.unloaded[fpPk.MarshalHex()]
+	return unloaded
+}
+
+// markUnloaded records the finality provider as unloaded without running its
+// instance, e.g., on startup, which must be called with mu held
+func (fpm *FinalityProviderManager) markUnloaded(pk *bbntypes.BIP340PubKey, chainID, passphrase string) {
+	fpm.unloaded[pk.MarshalHex()] = &unloadedInstance{
+		pk:         pk,
+		chainID:    chainID,
+		passphrase: passphrase,
+	}
+}
+
+// unloadFinalityProviderInstance stops the instance of the idle finality
+// provider and drops it from memory. The finality provider stays queryable
+// from the store, and its instance is reloaded by the status update once it
+// regains voting power, or on demand by the RPCs needing it
+func (fpm *FinalityProviderManager) unloadFinalityProviderInstance(fpi *FinalityProviderInstance) error {
+	fpm.mu.Lock()
+	defer fpm.mu.Unlock()
+
+	keyHex := fpi.GetBtcPkHex()
+	if _, exists := fpm.fpis[keyHex]; !exists {
+		return fmt.Errorf("cannot find the finality-provider instance with PK: %s", keyHex)
+	}
+	if fpi.IsRunning() {
+		if err := fpi.Stop(); err != nil {
+			return fmt.Errorf("failed to stop the finality-provider instance %s: %w", keyHex, err)
+		}
+	}
+
+	delete(fpm.fpis, keyHex)
+	fpm.metrics.DecrementRunningFpGauge()
+	fpm.markUnloaded(fpi.GetBtcPkBIP340(), string(fpi.GetChainID()), fpi.passphrase)
+
+	return nil
+}
+
+// reloadFinalityProviderInstance starts the instance of the unloaded finality
+// provider again and returns it
+func (fpm *FinalityProviderManager) reloadFinalityProviderInstance(fpPk *bbntypes.BIP340PubKey) (*FinalityProviderInstance, error) {
+	fpm.mu.Lock()
+	u, unloaded := fpm.unloaded[fpPk.MarshalHex()]
+	fpm.mu.Unlock()
+	if !unloaded {
+		return fpm.GetFinalityProviderInstance(fpPk)
+	}
+
+	if err := fpm.StartFinalityProvider(u.pk, u.passphrase); err != nil {
+		// the instance may have been reloaded concurrently
+		if fpi, getErr := fpm.GetFinalityProviderInstance(fpPk); getErr == nil {
+			return fpi, nil
+		}
+		return nil, fmt.Errorf("failed to reload the finality-provider instance %s: %w", fpPk.MarshalHex(), err)
+	}
+
+	fpm.logger.Info("reloaded the unloaded finality-provider instance", zap.String("pk", fpPk.MarshalHex()))
+
+	return fpm.GetFinalityProviderInstance(fpPk)
+}
+
+// unloadIdleInstances unloads the instances that have stayed INACTIVE for the
+// idle unload delay, as observed by the status update, where idleSince is the
+// time each instance was first seen INACTIVE
+func (fpm *FinalityProviderManager) unloadIdleInstances(idleSince map[string]time.Time) {
+	if !fpm.idleUnloadEnabled() {
+		return
+	}
+
+	now := time.Now()
+	seen := make(map[string]struct{})
+	for _, fpi := range fpm.ListFinalityProviderInstances() {
+		pkHex := fpi.GetBtcPkHex()
+		if fpi.GetStatus() != proto.FinalityProviderStatus_INACTIVE {
+			continue
+		}
+		seen[pkHex] = struct{}{}
+
+		since, ok := idleSince[pkHex]
+		if !ok {
+			idleSince[pkHex] = now
+			continue
+		}
+		if now.Sub(since) < fpm.config.IdleUnloadDelay {
+			continue
+		}
+
+		if err := fpm.unloadFinalityProviderInstance(fpi); err != nil {
+			fpm.logger.Warn("failed to unload the idle finality-provider instance",
+				zap.String("pk", pkHex), zap.Error(err))
+			continue
+		}
+		fpm.logger.Info("unloaded the idle finality-provider instance",
+			zap.String("pk", pkHex), zap.Duration("inactive_for", now.Sub(since)))
+	}
+
+	// the instances no longer INACTIVE or no longer running start over
+	for pkHex := range idleSince {
+		if _, ok := seen[pkHex]; !ok {
+			delete(idleSince, pkHex)
+		}
+	}
+}
+
+// reloadActiveInstances reloads the unloaded instances of the finality
+// providers that have regained voting power, and marks the ones slashed in
+// the meantime as SLASHED, which are not reloaded
+func (fpm *FinalityProviderManager) reloadActiveInstances() {
+	fpm.mu.Lock()
+	unloaded := make([]*unloadedInstance, 0, len(fpm.unloaded))
+	for _, u := range fpm.unloaded {
+		unloaded = append(unloaded, u)
+	}
+	fpm.mu.Unlock()
+
+	tipHeights := make(map[string]uint64)
+	for _, u := range unloaded {
+		cc := fpm.ccOf(u.chainID)
+		tipHeight, ok := tipHeights[u.chainID]
+		if !ok {
+			tip, err := cc.QueryBestBlock()
+			if err != nil {
+				fpm.logger.Debug("failed to get the latest block", zap.String("chain_id", u.chainID), zap.Error(err))
+				continue
+			}
+			tipHeight = tip.Height
+			tipHeights[u.chainID] = tipHeight
+		}
+
+		btcPk := u.pk.MustToBTCPK()
+		power, err := cc.QueryFinalityProviderVotingPower(btcPk, tipHeight)
+		if err != nil {
+			fpm.logger.Debug("failed to get the voting power of the unloaded finality provider",
+				zap.String("pk", u.pk.MarshalHex()), zap.Error(err))
+			continue
+		}
+		if power > 0 {
+			if _, err := fpm.reloadFinalityProviderInstance(u.pk); err != nil {
+				fpm.logger.Warn("failed to reload the finality provider which regained voting power",
+					zap.String("pk", u.pk.MarshalHex()), zap.Error(err))
+			}
+			continue
+		}
+
+		slashed, err := cc.QueryFinalityProviderSlashed(btcPk)
+		if err != nil {
+			fpm.logger.Debug("failed to get the slashed height of the unloaded finality provider",
+				zap.String("pk", u.pk.MarshalHex()), zap.Error(err))
+			continue
+		}
+		if slashed {
+			if err := fpm.fps.SetFpStatus(btcPk, proto.FinalityProviderStatus_SLASHED); err != nil {
+				fpm.logger.Warn("failed to set the status of the unloaded finality provider to SLASHED",
+					zap.String("pk", u.pk.MarshalHex()), zap.Error(err))
+				continue
+			}
+			fpm.mu.Lock()
+			delete(fpm.unloaded, u.pk.MarshalHex())
+			fpm.mu.Unlock()
+			fpm.logger.Debug("the unloaded finality-provider is slashed", zap.String("pk", u.pk.MarshalHex()))
+		}
+	}
+}
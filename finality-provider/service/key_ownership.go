@@ -0,0 +1,43 @@
+package service
+
+import (
+	"fmt"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// ProveKeyOwnership signs the challenge of a third party with the BTC key of
+// the finality provider to prove its ownership. The signed hash is tagged, so
+// the proof cannot be used as the proof-of-possession or any other signature
+// of the key, whatever the challenge
+func (app *FinalityProviderApp) ProveKeyOwnership(
+	fpPk *bbntypes.BIP340PubKey,
+	challenge []byte,
+	passphrase string,
+) (*proto.ProveKeyOwnershipResponse, error) {
+	// only the keys of the finality providers of the daemon are used
+	if _, err := app.fps.GetFinalityProvider(fpPk.MustToBTCPK()); err != nil {
+		return nil, err
+	}
+
+	btcPk := fpPk.MustToBTCPK()
+	sig, err := app.eotsManager.SignSchnorrSig(fpPk.MustMarshal(), types.KeyOwnershipProofHash(btcPk, challenge), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the challenge: %w", err)
+	}
+
+	// the EOTS manager may be remote, so the proof is checked before it is
+	// handed to the third party
+	if err := types.VerifyKeyOwnershipProof(btcPk, challenge, sig.Serialize()); err != nil {
+		return nil, err
+	}
+
+	return &proto.ProveKeyOwnershipResponse{
+		BtcPkHex:  fpPk.MarshalHex(),
+		Tag:       types.KeyOwnershipProofTag,
+		Signature: sig.Serialize(),
+	}, nil
+}
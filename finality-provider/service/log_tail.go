@@ -0,0 +1,23 @@
+package service
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	"github.com/babylonchain/finality-provider/log"
+)
+
+// SetLogTail sets the tail of the logs of the daemon to be streamed through
+// the TailLogs RPC, which must be called before the app is started
+func (app *FinalityProviderApp) SetLogTail(tail *log.Tail) {
+	app.logTail = tail
+}
+
+// TailLogs subscribes to the logs of the daemon at or above the given level,
+// and of the given subsystem if it is not empty
+func (app *FinalityProviderApp) TailLogs(level zapcore.Level, subsystem string) (*log.TailSubscription, error) {
+	if app.logTail == nil {
+		return nil, ErrLogTailDisabled
+	}
+
+	return app.logTail.Subscribe(level, subsystem), nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/babylonchain/finality-provider/log"
+)
+
+func TestTailLogs(t *testing.T) {
+	app := &FinalityProviderApp{}
+	_, err := app.TailLogs(zapcore.InfoLevel, "")
+	require.ErrorIs(t, err, ErrLogTailDisabled)
+
+	tail := log.NewTail()
+	app.SetLogTail(tail)
+	sub, err := app.TailLogs(zapcore.InfoLevel, "")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	tail.Wrap(zap.NewNop()).Info("the daemon is started")
+	entry := <-sub.Entries()
+	require.Equal(t, "the daemon is started", entry.Message)
+
+	var v rpcValidator
+	require.Equal(t, zapcore.InfoLevel, v.logLevel("level", ""))
+	require.Equal(t, zapcore.DebugLevel, v.logLevel("level", "debug"))
+	require.NoError(t, v.err())
+	v.logLevel("level", "verbose")
+	require.Error(t, v.err())
+}
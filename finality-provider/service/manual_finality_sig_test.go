@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestCheckManualFinalitySig(t *testing.T) {
+	tipHeight := uint64(100)
+	lastVotedHeight := uint64(50)
+	chainHash := []byte("chain hash")
+
+	testCases := []struct {
+		name      string
+		height    uint64
+		hash      []byte
+		force     bool
+		expectErr error
+	}{
+		{
+			name:   "a block on the chain above the last voted height is accepted",
+			height: lastVotedHeight + 1,
+			hash:   chainHash,
+		},
+		{
+			name:      "a height above the tip is refused",
+			height:    tipHeight + 1,
+			hash:      chainHash,
+			expectErr: ErrHeightAboveTip,
+		},
+		{
+			name:      "a height above the tip is refused even if forced",
+			height:    tipHeight + 1,
+			hash:      chainHash,
+			force:     true,
+			expectErr: ErrHeightAboveTip,
+		},
+		{
+			name:      "a height already voted is refused",
+			height:    lastVotedHeight,
+			hash:      chainHash,
+			expectErr: ErrAlreadyVoted,
+		},
+		{
+			name:      "a hash differing from the chain is refused",
+			height:    lastVotedHeight + 1,
+			hash:      []byte("other hash"),
+			expectErr: ErrConflictingBlockHash,
+		},
+		{
+			name:   "a height already voted is accepted if forced",
+			height: lastVotedHeight,
+			hash:   chainHash,
+			force:  true,
+		},
+		{
+			name:   "a hash differing from the chain is accepted if forced",
+			height: lastVotedHeight + 1,
+			hash:   []byte("other hash"),
+			force:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fp := newManualSigTestInstance(t, tipHeight, lastVotedHeight, chainHash)
+
+			b := &types.BlockInfo{Height: tc.height, Hash: tc.hash}
+			err := fp.checkManualFinalitySig(b, tc.force)
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("a block conflicting with the one signed at the height is refused", func(t *testing.T) {
+		fp := newManualSigTestInstance(t, tipHeight, lastVotedHeight, chainHash)
+		height := lastVotedHeight + 1
+		require.NoError(t, fp.fpState.s.SaveSignedBlockHash(fp.GetBtcPk(), height, []byte("signed hash")))
+
+		err := fp.checkManualFinalitySig(&types.BlockInfo{Height: height, Hash: chainHash}, false)
+		require.ErrorIs(t, err, ErrConflictingBlockHash)
+	})
+}
+
+// newManualSigTestInstance creates an instance that has voted up to the given
+// height on a chain whose blocks all have the given hash
+func newManualSigTestInstance(t *testing.T, tipHeight, lastVotedHeight uint64, chainHash []byte) *FinalityProviderInstance {
+	db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+
+	cc := mocks.NewMockClientController(gomock.NewController(t))
+	cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: tipHeight}, nil).AnyTimes()
+	cc.EXPECT().QueryBlock(gomock.Any()).DoAndReturn(func(height uint64) (*types.BlockInfo, error) {
+		return &types.BlockInfo{Height: height, Hash: chainHash}, nil
+	}).AnyTimes()
+
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pollerCfg := fpcfg.DefaultChainPollerConfig()
+	pollerCfg.QueryRetryAttempts = 1
+
+	return &FinalityProviderInstance{
+		cfg:     &fpcfg.Config{PollerConfig: &pollerCfg},
+		fpState: NewFpState(&store.StoredFinalityProvider{BtcPk: sk.PubKey(), LastVotedHeight: lastVotedHeight}, s),
+		cc:      cc,
+		metrics: metrics.NewFpMetrics(),
+		logger:  zap.NewNop(),
+		ctx:     context.Background(),
+	}
+}
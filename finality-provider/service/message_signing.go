@@ -0,0 +1,68 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	fpkr "github.com/babylonchain/finality-provider/keyring"
+	"github.com/babylonchain/finality-provider/util/securemem"
+)
+
+// SignMessage signs an arbitrary message with the chain key of the finality
+// provider as specified by ADR-36, so that the operator can prove the control
+// of the account without exporting the key. The signed sign doc can never be
+// broadcast as a tx
+func (app *FinalityProviderApp) SignMessage(
+	fpPk *bbntypes.BIP340PubKey,
+	passphrase, hdPath string,
+	msg []byte,
+) (*proto.SignMessageResponse, error) {
+	fp, err := app.fps.GetFinalityProvider(fpPk.MustToBTCPK())
+	if err != nil {
+		return nil, err
+	}
+
+	_, chainSk, err := app.loadChainKeyring(fp.KeyName, passphrase, hdPath)
+	if err != nil {
+		return nil, err
+	}
+	defer securemem.Zero(chainSk.Key)
+
+	// the key of the name may have been replaced in the keyring
+	if !bytes.Equal(chainSk.PubKey().Bytes(), fp.ChainPk.Bytes()) {
+		return nil, fmt.Errorf("the key %s in the keyring is not the chain key of the finality provider %s",
+			fp.KeyName, fpPk.MarshalHex())
+	}
+
+	signer, sig, err := fpkr.SignADR36(chainSk, app.config.BabylonConfig.AccountPrefix, msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the message: %w", err)
+	}
+
+	return &proto.SignMessageResponse{
+		Signer:    signer,
+		PubKey:    fp.ChainPk.Bytes(),
+		Signature: sig,
+	}, nil
+}
+
+// VerifyMessage verifies the ADR-36 signature of an arbitrary message by the
+// signer. An invalid signature is reported with its reason instead of an
+// error
+func (app *FinalityProviderApp) VerifyMessage(signer string, pubKey, msg, sig []byte) (*proto.VerifyMessageResponse, error) {
+	pk := &secp256k1.PubKey{Key: pubKey}
+	err := fpkr.VerifyADR36(pk, signer, msg, sig)
+	if errors.Is(err, fpkr.ErrInvalidMessageSignature) {
+		return &proto.VerifyMessageResponse{Valid: false, Reason: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.VerifyMessageResponse{Valid: true}, nil
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+const (
+	// observerDelay is the number of blocks the observer stays behind the tip,
+	// giving the votes on a block time to be included on chain
+	observerDelay = 3
+	// maxObservedBlocksPerCycle bounds the queries of each observation
+	maxObservedBlocksPerCycle = 20
+)
+
+// networkObserver tracks the participation of all the finality providers on
+// the consumer chain over the most recent blocks, helping operators tell
+// whether missed votes are local or network-wide
+type networkObserver struct {
+	cc     clientcontroller.ClientController
+	window int
+	logger *zap.Logger
+
+	mu sync.Mutex
+	// records are the observed blocks in ascending order of height
+	records    []*blockParticipation
+	nextHeight uint64
+}
+
+// blockParticipation is the participation of the finality providers at a height
+type blockParticipation struct {
+	height uint64
+	// active are the hex BTC public keys of the finality providers with
+	// voting power at the height
+	active []string
+	voted  map[string]struct{}
+}
+
+func newNetworkObserver(cc clientcontroller.ClientController, window uint32, logger *zap.Logger) *networkObserver {
+	return &networkObserver{
+		cc:     cc,
+		window: int(window),
+		logger: logger,
+	}
+}
+
+// observe records the participation at the heights since the previous
+// observation, up to observerDelay blocks behind the tip
+func (o *networkObserver) observe() error {
+	tipBlock, err := o.cc.QueryBestBlock()
+	if err != nil {
+		return err
+	}
+	if tipBlock.Height <= observerDelay {
+		return nil
+	}
+	endHeight := tipBlock.Height - observerDelay
+
+	o.mu.Lock()
+	nextHeight := o.nextHeight
+	o.mu.Unlock()
+
+	// start with a full window on the first observation
+	if nextHeight == 0 {
+		nextHeight = 1
+		if endHeight > uint64(o.window) {
+			nextHeight = endHeight - uint64(o.window) + 1
+		}
+	}
+	if endHeight >= nextHeight+maxObservedBlocksPerCycle {
+		endHeight = nextHeight + maxObservedBlocksPerCycle - 1
+	}
+
+	for height := nextHeight; height <= endHeight; height++ {
+		record, err := o.observeHeight(height)
+		if err != nil {
+			return err
+		}
+
+		o.mu.Lock()
+		o.records = append(o.records, record)
+		if len(o.records) > o.window {
+			o.records = o.records[len(o.records)-o.window:]
+		}
+		o.nextHeight = height + 1
+		o.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (o *networkObserver) observeHeight(height uint64) (*blockParticipation, error) {
+	activeFps, err := o.cc.QueryActiveFinalityProvidersAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := o.cc.QueryVotesAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &blockParticipation{
+		height: height,
+		active: make([]string, 0, len(activeFps)),
+		voted:  make(map[string]struct{}, len(votes)),
+	}
+	for _, pk := range activeFps {
+		record.active = append(record.active, pk.MarshalHex())
+	}
+	for _, pk := range votes {
+		record.voted[pk.MarshalHex()] = struct{}{}
+	}
+
+	return record, nil
+}
+
+// report compares the participation of the given local finality providers
+// with the whole network over the observed blocks
+func (o *networkObserver) report(localFps map[string]struct{}) *proto.QueryNetworkParticipationResponse {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	res := &proto.QueryNetworkParticipationResponse{}
+	if len(o.records) == 0 {
+		return res
+	}
+	res.StartHeight = o.records[0].height
+	res.EndHeight = o.records[len(o.records)-1].height
+
+	byFp := make(map[string]*proto.FinalityProviderParticipation)
+	var order []string
+	var networkActive, networkVoted, localActive, localVoted uint64
+	for _, record := range o.records {
+		for _, pkHex := range record.active {
+			p, ok := byFp[pkHex]
+			if !ok {
+				_, isLocal := localFps[pkHex]
+				p = &proto.FinalityProviderParticipation{
+					BtcPkHex:           pkHex,
+					BtcPkCompressedHex: types.CompressedBTCPkHex(pkHex),
+					IsLocal:            isLocal,
+				}
+				byFp[pkHex] = p
+				order = append(order, pkHex)
+			}
+			_, voted := record.voted[pkHex]
+
+			p.ActiveBlocks++
+			networkActive++
+			if p.IsLocal {
+				localActive++
+			}
+			if voted {
+				p.VotedBlocks++
+				networkVoted++
+				if p.IsLocal {
+					localVoted++
+				}
+			}
+		}
+	}
+
+	for _, pkHex := range order {
+		p := byFp[pkHex]
+		p.Participation = ratio(p.VotedBlocks, p.ActiveBlocks)
+		res.FinalityProviders = append(res.FinalityProviders, p)
+	}
+	res.NetworkParticipation = ratio(networkVoted, networkActive)
+	res.LocalParticipation = ratio(localVoted, localActive)
+
+	return res
+}
+
+func ratio(n, d uint64) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// networkObserverLoop observes the participation of the finality providers
+// until the app is stopped
+func (app *FinalityProviderApp) networkObserverLoop() {
+	defer app.wg.Done()
+
+	ticker := time.NewTicker(app.config.PollerConfig.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := app.observer.observe(); err != nil {
+				app.logger.Debug("failed to observe the participation of the finality providers", zap.Error(err))
+			}
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// QueryNetworkParticipation reports the participation of the finality
+// providers in the daemon against all the finality providers on the default
+// consumer chain, which is only available in observer mode
+func (app *FinalityProviderApp) QueryNetworkParticipation() (*proto.QueryNetworkParticipationResponse, error) {
+	if app.observer == nil {
+		return nil, ErrObserverDisabled
+	}
+
+	localFps, err := app.localFinalityProviders(app.config.BabylonConfig.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.observer.report(localFps), nil
+}
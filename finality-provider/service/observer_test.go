@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestNetworkObserver(t *testing.T) {
+	localSk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	localPk := bbntypes.NewBIP340PubKeyFromBTCPK(localSk.PubKey())
+	remoteSk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	remotePk := bbntypes.NewBIP340PubKeyFromBTCPK(remoteSk.PubKey())
+
+	// both finality providers are active at all the heights, and the remote
+	// one only votes at the heights up to 7
+	cc := mocks.NewMockClientController(gomock.NewController(t))
+	cc.EXPECT().QueryActiveFinalityProvidersAtHeight(gomock.Any()).
+		Return([]bbntypes.BIP340PubKey{*localPk, *remotePk}, nil).AnyTimes()
+	cc.EXPECT().QueryVotesAtHeight(gomock.Any()).DoAndReturn(func(height uint64) ([]bbntypes.BIP340PubKey, error) {
+		if height <= 7 {
+			return []bbntypes.BIP340PubKey{*localPk, *remotePk}, nil
+		}
+		return []bbntypes.BIP340PubKey{*localPk}, nil
+	}).AnyTimes()
+
+	window := uint32(5)
+	o := newNetworkObserver(cc, window, zap.NewNop())
+	localFps := map[string]struct{}{localPk.MarshalHex(): {}}
+
+	// nothing is reported before the first observation
+	res := o.report(localFps)
+	require.Empty(t, res.FinalityProviders)
+
+	// the first observation starts with a full window behind the tip
+	cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: 10 + observerDelay}, nil).Times(1)
+	require.NoError(t, o.observe())
+	res = o.report(localFps)
+	require.Equal(t, uint64(6), res.StartHeight)
+	require.Equal(t, uint64(10), res.EndHeight)
+	require.Len(t, res.FinalityProviders, 2)
+	require.True(t, res.FinalityProviders[0].IsLocal)
+	require.Equal(t, uint64(5), res.FinalityProviders[0].ActiveBlocks)
+	require.Equal(t, 1.0, res.FinalityProviders[0].Participation)
+	require.False(t, res.FinalityProviders[1].IsLocal)
+	require.Equal(t, uint64(2), res.FinalityProviders[1].VotedBlocks)
+	require.Equal(t, 0.4, res.FinalityProviders[1].Participation)
+	require.Equal(t, 0.7, res.NetworkParticipation)
+	require.Equal(t, 1.0, res.LocalParticipation)
+
+	// the following observation moves the window forward
+	cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: 12 + observerDelay}, nil).Times(1)
+	require.NoError(t, o.observe())
+	res = o.report(localFps)
+	require.Equal(t, uint64(8), res.StartHeight)
+	require.Equal(t, uint64(12), res.EndHeight)
+	require.Zero(t, res.FinalityProviders[1].VotedBlocks)
+	require.Equal(t, 0.5, res.NetworkParticipation)
+}
+
+func TestQueryNetworkParticipationDisabled(t *testing.T) {
+	app := &FinalityProviderApp{}
+
+	_, err := app.QueryNetworkParticipation()
+	require.ErrorIs(t, err, ErrObserverDisabled)
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// trackPendingFinalitySig persists the signed tx of the finality signatures
+// over the given blocks before it is broadcast, so that the same tx is looked
+// up or broadcast again until its inclusion is confirmed, even if the daemon
+// stops in between. The tx of a batch is tracked at each of its heights
+func (fp *FinalityProviderInstance) trackPendingFinalitySig(tx *types.SignedTx, blocks ...*types.BlockInfo) error {
+	now := time.Now().Unix()
+	sigs := make([]*proto.PendingFinalitySig, 0, len(blocks))
+	for _, b := range blocks {
+		sigs = append(sigs, &proto.PendingFinalitySig{
+			BtcPk:         fp.btcPk.MustMarshal(),
+			Height:        b.Height,
+			BlockHash:     b.Hash,
+			BroadcastTime: now,
+			TxHash:        tx.Hash,
+			TxBytes:       tx.Bytes,
+		})
+	}
+
+	return fp.fpState.s.AddPendingFinalitySigs(sigs)
+}
+
+// untrackPendingFinalitySig removes the pending finality signature at the given
+// height once its tx is included, or will never be included
+func (fp *FinalityProviderInstance) untrackPendingFinalitySig(height uint64) {
+	err := fp.fpState.s.RemovePendingFinalitySig(fp.GetBtcPk(), height)
+	if err != nil && !errors.Is(err, store.ErrPendingFinalitySigNotFound) {
+		fp.logger.Error("failed to remove the pending finality signature",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", height), zap.Error(err))
+	}
+}
+
+// broadcastPendingFinalitySig broadcasts the tracked tx of the finality
+// signatures at the given heights. The pending finality signatures are kept if
+// the outcome of the broadcast is unknown, e.g., on a timeout, so that the tx
+// is settled before the blocks are signed again
+func (fp *FinalityProviderInstance) broadcastPendingFinalitySig(tx *types.SignedTx, heights ...uint64) (*types.TxResponse, error) {
+	res, err := fp.cc.BroadcastSignedTx(tx)
+	if err != nil && !clientcontroller.IsTxRejected(err) {
+		return nil, err
+	}
+
+	for _, height := range heights {
+		fp.untrackPendingFinalitySig(height)
+	}
+
+	return res, err
+}
+
+// lookUpPendingFinalitySig returns the response of the tx of the pending
+// finality signature if the tx is included. The tx included with a failure,
+// or missing from a record of an older version, is reported as rejected, as
+// broadcasting it again is useless
+func (fp *FinalityProviderInstance) lookUpPendingFinalitySig(ps *proto.PendingFinalitySig) (res *types.TxResponse, rejected bool, err error) {
+	if len(ps.TxBytes) == 0 {
+		return nil, true, nil
+	}
+
+	res, err = fp.cc.QueryTx(ps.TxHash)
+	switch {
+	case err == nil:
+		return res, false, nil
+	case errors.Is(err, clientcontroller.ErrTxNotFound):
+		return nil, false, nil
+	case clientcontroller.IsTxRejected(err):
+		fp.logger.Debug("the tx of the pending finality signature failed",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height), zap.Error(err))
+		return nil, true, nil
+	default:
+		return nil, false, fmt.Errorf("failed to look up the tx of the pending finality signature: %w", err)
+	}
+}
+
+// rebroadcastPendingFinalitySig broadcasts the tx of the pending finality
+// signature again unless it is rejected. It returns nil once the tx will never
+// be included, in which case the pending finality signature is removed and
+// the block has to be signed again
+func (fp *FinalityProviderInstance) rebroadcastPendingFinalitySig(ps *proto.PendingFinalitySig, rejected bool) (*types.TxResponse, error) {
+	if !rejected {
+		res, err := fp.broadcastPendingFinalitySig(&types.SignedTx{Bytes: ps.TxBytes, Hash: ps.TxHash}, ps.Height)
+		if err == nil || !clientcontroller.IsTxRejected(err) {
+			return res, err
+		}
+		fp.logger.Debug("the tx of the pending finality signature is rejected",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height), zap.Error(err))
+	}
+
+	fp.untrackPendingFinalitySig(ps.Height)
+
+	return nil, nil
+}
+
+// settlePendingFinalitySig settles the tx of the pending finality signature at
+// the given height, if any, before the block is signed again. It returns the
+// response of the tx once it is included, or nil if there is no tx to settle
+// or the tx will never be included
+func (fp *FinalityProviderInstance) settlePendingFinalitySig(height uint64) (*types.TxResponse, error) {
+	ps, err := fp.fpState.s.GetPendingFinalitySig(fp.GetBtcPk(), height)
+	if err != nil {
+		if errors.Is(err, store.ErrPendingFinalitySigNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res, rejected, err := fp.lookUpPendingFinalitySig(ps)
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		fp.untrackPendingFinalitySig(height)
+		return res, nil
+	}
+
+	return fp.rebroadcastPendingFinalitySig(ps, rejected)
+}
+
+// resolvePendingFinalitySigs handles the finality signatures whose txs were
+// broadcast but not confirmed before the daemon stopped. The txs included in
+// the meantime are confirmed, and the others are either broadcast again or
+// abandoned depending on the pending tx policy. The finality signatures of a
+// batch are resolved at each of their heights. An error is returned if any of
+// them can be neither confirmed nor submitted again, after the others are
+// resolved, as the instance would otherwise sign over a tx in flight
+func (fp *FinalityProviderInstance) resolvePendingFinalitySigs() error {
+	pendingSigs, err := fp.fpState.s.GetPendingFinalitySigs(fp.GetBtcPk())
+	if err != nil {
+		return fmt.Errorf("failed to get the pending finality signatures: %w", err)
+	}
+
+	var errs []error
+	for _, ps := range pendingSigs {
+		if err := fp.resolvePendingFinalitySig(ps); err != nil {
+			fp.logger.Error("failed to resolve the pending finality signature",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height), zap.Error(err))
+			errs = append(errs, fmt.Errorf("height %d: %w", ps.Height, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (fp *FinalityProviderInstance) resolvePendingFinalitySig(ps *proto.PendingFinalitySig) error {
+	b := &types.BlockInfo{
+		Height: ps.Height,
+		Hash:   ps.BlockHash,
+	}
+
+	// whatever the policy, the tx may have been included before the restart
+	res, rejected, err := fp.lookUpPendingFinalitySig(ps)
+	if err != nil {
+		return err
+	}
+	if res != nil {
+		fp.logger.Info("the pending finality signature has been confirmed",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height),
+			zap.String("tx_hash", res.TxHash))
+		fp.confirmPendingFinalitySig(b, res)
+		return nil
+	}
+
+	abandon, err := fp.shouldAbandonPendingFinalitySig(ps)
+	if err != nil {
+		return err
+	}
+	if abandon {
+		fp.untrackPendingFinalitySig(ps.Height)
+		return nil
+	}
+
+	res, err = fp.rebroadcastPendingFinalitySig(ps, rejected)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		// the tx will never be included, so the block is signed again into
+		// a tx with a valid sequence
+		res, err = fp.SubmitFinalitySignature(b)
+		if err != nil {
+			return err
+		}
+		fp.logger.Info("successfully signed the pending finality signature again",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height),
+			zap.String("tx_hash", res.TxHash))
+		return nil
+	}
+
+	fp.logger.Info("successfully resubmitted the pending finality signature",
+		zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height),
+		zap.String("tx_hash", res.TxHash))
+	fp.confirmPendingFinalitySig(b, res)
+
+	return nil
+}
+
+// shouldAbandonPendingFinalitySig returns whether the pending finality
+// signature, whose tx is not included, is abandoned rather than submitted
+// again
+func (fp *FinalityProviderInstance) shouldAbandonPendingFinalitySig(ps *proto.PendingFinalitySig) (bool, error) {
+	broadcastTime := time.Unix(ps.BroadcastTime, 0)
+
+	if fp.cfg.PendingTxPolicy == fpcfg.PendingTxPolicyAbandon {
+		fp.logger.Warn("abandoning the pending finality signature",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height))
+		return true, nil
+	}
+
+	if fp.cfg.PendingTxMaxAge > 0 && time.Since(broadcastTime) > fp.cfg.PendingTxMaxAge {
+		fp.logger.Warn("abandoning the expired pending finality signature",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height),
+			zap.Time("broadcast_time", broadcastTime))
+		return true, nil
+	}
+
+	finalized, err := fp.checkBlockFinalization(ps.Height)
+	if err != nil {
+		return false, err
+	}
+	if finalized {
+		fp.logger.Info("the block of the pending finality signature is already finalized",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("height", ps.Height))
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// confirmPendingFinalitySig updates the state of the finality provider once
+// the tx of the pending finality signature is included
+func (fp *FinalityProviderInstance) confirmPendingFinalitySig(b *types.BlockInfo, res *types.TxResponse) {
+	fp.untrackPendingFinalitySig(b.Height)
+	fp.MustUpdateStateAfterFinalitySigSubmission(b.Height)
+	fp.recordVoteSubmission(res, b)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// commitPubRandChunked commits the public randomness of the given range of
+// heights in chunks of contiguous heights of at most the chunk size of the
+// config. A chunk rejected by the chain for exceeding its max tx size or gas
+// limit is halved and retried, down to the minimum number of public randomness
+// of the chain. Before every chunk but the first, the last committed height on
+// the chain is checked to be right below the chunk, so that no gap is left
+// between the chunks. It returns the response of the last chunk and the number
+// of public randomness committed
+func (fp *FinalityProviderInstance) commitPubRandChunked(startHeight, numPubRand uint64) (*types.TxResponse, uint64, error) {
+	chunkSize := numPubRand
+	if fp.cfg.PubRandChunkSize > 0 && chunkSize > fp.cfg.PubRandChunkSize {
+		chunkSize = fp.cfg.PubRandChunkSize
+	}
+
+	var (
+		res       *types.TxResponse
+		committed uint64
+	)
+	endHeight := startHeight + numPubRand - 1
+	for height := startHeight; height <= endHeight; {
+		if height > startHeight {
+			if err := fp.checkPubRandContiguity(height); err != nil {
+				return nil, committed, err
+			}
+		}
+
+		n := min(chunkSize, endHeight-height+1)
+		// the last chunk is extended to the minimum of the chain, which
+		// merely commits more randomness ahead
+		if err := fp.chainParams.checkNumPubRand(n); err != nil {
+			n = chunkSize
+		}
+
+		chunkRes, n, err := fp.commitPubRandRange(height, n)
+		if err != nil {
+			if clientcontroller.IsTxOversized(err) {
+				if halved, ok := fp.halvePubRandChunk(chunkSize); ok {
+					fp.logger.Warn("the public randomness commit exceeds the limits of the chain, retrying in smaller chunks",
+						zap.String("pk", fp.GetBtcPkHex()),
+						zap.Uint64("start_height", height),
+						zap.Uint64("chunk_size", halved),
+						zap.Error(err))
+					chunkSize = halved
+					continue
+				}
+			}
+			return nil, committed, err
+		}
+
+		if height > startHeight || height+n <= endHeight {
+			fp.logger.Info("committed a chunk of the public randomness",
+				zap.String("pk", fp.GetBtcPkHex()),
+				zap.Uint64("start_height", height),
+				zap.Uint64("num_pub_rand", n))
+		}
+		res = chunkRes
+		committed += n
+		height += n
+	}
+
+	return res, committed, nil
+}
+
+// halvePubRandChunk returns the half of the chunk size, and false if it would
+// drop below the minimum number of public randomness of the chain
+func (fp *FinalityProviderInstance) halvePubRandChunk(chunkSize uint64) (uint64, bool) {
+	halved := chunkSize / 2
+	if halved == 0 {
+		return 0, false
+	}
+	if err := fp.chainParams.checkNumPubRand(halved); err != nil {
+		return 0, false
+	}
+
+	return halved, true
+}
+
+// checkPubRandContiguity checks that the last committed height on the chain is
+// right below the height of the next chunk
+func (fp *FinalityProviderInstance) checkPubRandContiguity(nextHeight uint64) error {
+	lastCommittedHeight, err := fp.GetLastCommittedHeight()
+	if err != nil {
+		return fmt.Errorf("failed to query the last committed height to check the contiguity of the chunks: %w", err)
+	}
+	if lastCommittedHeight+1 != nextHeight {
+		return fmt.Errorf("the chunks of the public randomness are not contiguous: the last committed height is %d, "+
+			"while the next chunk starts at %d", lastCommittedHeight, nextHeight)
+	}
+
+	return nil
+}
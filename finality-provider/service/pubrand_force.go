@@ -0,0 +1,101 @@
+package service
+
+import (
+	"fmt"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// CommitPubRand commits the public randomness of the running finality
+// provider right away instead of waiting for the commitment loop, which is
+// useful before a planned maintenance or after tuning the lookahead
+func (app *FinalityProviderApp) CommitPubRand(
+	fpPk *bbntypes.BIP340PubKey,
+	targetHeight uint64,
+) (*proto.CommitPubRandResponse, error) {
+	fpi, err := app.GetFinalityProviderInstance(fpPk)
+	if err != nil {
+		return nil, err
+	}
+
+	return fpi.ForceCommitPubRand(targetHeight)
+}
+
+// ForceCommitPubRand commits the public randomness from the last committed
+// height up to the target height, or up to the normal lookahead of the tip if
+// the target height is zero, regardless of whether the runway is running out.
+// The number of public randomness is capped by NumPubRandMax if it is set, and
+// nothing is committed if the randomness already reaches the target height
+func (fp *FinalityProviderInstance) ForceCommitPubRand(targetHeight uint64) (*proto.CommitPubRandResponse, error) {
+	fp.commitMu.Lock()
+	defer fp.commitMu.Unlock()
+
+	tipBlock, err := fp.getLatestBlockWithRetry()
+	if err != nil {
+		return nil, err
+	}
+	lastCommittedHeight, err := fp.GetLastCommittedHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	startHeight := lastCommittedHeight + 1
+	if lastCommittedHeight == 0 {
+		// the finality-provider has never submitted public rand before
+		startHeight = tipBlock.Height + 1
+	}
+
+	if targetHeight == 0 {
+		randHeightGap, _ := fp.randHeightGap()
+		targetHeight = tipBlock.Height + randHeightGap
+		// commit at least as much as the commitment loop does
+		if minTarget := startHeight + fp.cfg.NumPubRand - 1; targetHeight < minTarget {
+			targetHeight = minTarget
+		}
+	}
+	if targetHeight < startHeight {
+		fp.logger.Info("the public randomness is already committed up to the target height",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("target_height", targetHeight),
+			zap.Uint64("last_committed_height", lastCommittedHeight),
+		)
+		return &proto.CommitPubRandResponse{LastCommittedHeight: lastCommittedHeight}, nil
+	}
+
+	numPubRand := targetHeight - startHeight + 1
+	if fp.cfg.NumPubRandMax > 0 && numPubRand > fp.cfg.NumPubRandMax {
+		numPubRand = fp.cfg.NumPubRandMax
+	}
+	// the commit would be rejected by the chain anyway
+	if err := fp.chainParams.checkNumPubRand(numPubRand); err != nil {
+		return nil, err
+	}
+
+	if err := fp.seedRandomness(startHeight); err != nil {
+		return nil, err
+	}
+
+	res, numPubRand, err := fp.commitPubRandChunked(startHeight, numPubRand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit public randomness from height %d: %w", startHeight, err)
+	}
+
+	fp.onPubRandCommitted(res, lastCommittedHeight, startHeight, numPubRand, tipBlock.Height)
+
+	fp.logger.Info("successfully committed public randomness on demand",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("start_height", startHeight),
+		zap.Uint64("num_pub_rand", numPubRand),
+		zap.String("tx_hash", res.TxHash),
+	)
+
+	return &proto.CommitPubRandResponse{
+		TxHash:              res.TxHash,
+		StartHeight:         startHeight,
+		NumPubRand:          numPubRand,
+		LastCommittedHeight: startHeight + numPubRand - 1,
+	}, nil
+}
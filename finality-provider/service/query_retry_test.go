@@ -0,0 +1,47 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestQueryRetries(t *testing.T) {
+	queryErr := errors.New("the consumer chain is unreachable")
+	pollerCfg := fpcfg.DefaultChainPollerConfig()
+	pollerCfg.QueryRetryAttempts = 3
+	pollerCfg.QueryRetryDelay = time.Millisecond
+
+	t.Run("a query succeeds within the configured attempts", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		gomock.InOrder(
+			cc.EXPECT().QueryBestBlock().Return(nil, queryErr).Times(2),
+			cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: 10}, nil).Times(1),
+		)
+		cp := NewChainPoller(zap.NewNop(), &pollerCfg, cc, metrics.NewFpMetrics())
+
+		block, err := cp.latestBlockWithRetry()
+		require.NoError(t, err)
+		require.Equal(t, uint64(10), block.Height)
+	})
+
+	t.Run("a query fails after the configured attempts", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		cc.EXPECT().QueryBlock(uint64(10)).Return(nil, queryErr).Times(int(pollerCfg.QueryRetryAttempts))
+		cp := NewChainPoller(zap.NewNop(), &pollerCfg, cc, metrics.NewFpMetrics())
+
+		_, err := cp.blockWithRetry(10)
+		require.ErrorIs(t, err, queryErr)
+	})
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	ftypes "github.com/babylonchain/babylon/x/finality/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+)
+
+// randGap is a range of heights between two commits of public randomness
+// that is not covered by any commit
+type randGap struct {
+	startHeight uint64
+	endHeight   uint64
+}
+
+func (g randGap) numHeights() uint64 {
+	return g.endHeight - g.startHeight + 1
+}
+
+// findRandGaps returns the gaps between the given commits of public
+// randomness keyed by start height, in the ascending order of height
+func findRandGaps(commits map[uint64]*ftypes.PubRandCommitResponse) []randGap {
+	startHeights := make([]uint64, 0, len(commits))
+	for startHeight := range commits {
+		startHeights = append(startHeights, startHeight)
+	}
+	sort.Slice(startHeights, func(i, j int) bool { return startHeights[i] < startHeights[j] })
+
+	var gaps []randGap
+	for i := 1; i < len(startHeights); i++ {
+		prev := startHeights[i-1]
+		prevEnd := prev + commits[prev].NumPubRand - 1
+		if startHeights[i] > prevEnd+1 {
+			gaps = append(gaps, randGap{startHeight: prevEnd + 1, endHeight: startHeights[i] - 1})
+		}
+	}
+
+	return gaps
+}
+
+// repairRandGaps scans the last commits of public randomness for gaps, e.g.,
+// left by a restore from an outdated backup, and commits the randomness of
+// the heights of the gaps ahead of the tip. The heights up to the tip are
+// already produced and cannot be voted anymore, and the heights the chain
+// refuses to commit, e.g., as Babylon only accepts the commits after the
+// last one, are marked as unmitigable
+func (fp *FinalityProviderInstance) repairRandGaps() {
+	if fp.cfg.RandGapScanDepth == 0 {
+		return
+	}
+
+	commits, err := fp.lastCommittedPublicRandWithRetry(fp.cfg.RandGapScanDepth)
+	if err != nil {
+		fp.logger.Warn("failed to query the public randomness commits to scan for gaps",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Error(err))
+		return
+	}
+
+	gaps := findRandGaps(commits)
+	if len(gaps) == 0 {
+		fp.metrics.RecordFpUnmitigableRandGapHeights(fp.GetBtcPkHex(), 0)
+		return
+	}
+
+	tipBlock, err := fp.getLatestBlockWithRetry()
+	if err != nil {
+		fp.logger.Warn("failed to query the tip to repair the public randomness gaps",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Error(err))
+		return
+	}
+
+	var unmitigable uint64
+	for _, gap := range gaps {
+		if gap.startHeight <= tipBlock.Height {
+			produced := randGap{startHeight: gap.startHeight, endHeight: min(gap.endHeight, tipBlock.Height)}
+			fp.markUnmitigableRandGap(produced, "the heights are already produced")
+			unmitigable += produced.numHeights()
+			if gap.endHeight <= tipBlock.Height {
+				continue
+			}
+			gap.startHeight = tipBlock.Height + 1
+		}
+
+		if unfilled, err := fp.fillRandGap(gap); err != nil {
+			fp.markUnmitigableRandGap(unfilled, err.Error())
+			unmitigable += unfilled.numHeights()
+		}
+	}
+
+	fp.metrics.RecordFpUnmitigableRandGapHeights(fp.GetBtcPkHex(), unmitigable)
+}
+
+// fillRandGap commits the public randomness of the heights of the gap in
+// commits of at most the maximum number of public randomness, and returns the
+// part of the gap left unfilled on error
+func (fp *FinalityProviderInstance) fillRandGap(gap randGap) (randGap, error) {
+	maxNumPubRand := fp.cfg.NumPubRandMax
+	if maxNumPubRand == 0 {
+		maxNumPubRand = fp.cfg.NumPubRand
+	}
+
+	for startHeight := gap.startHeight; startHeight <= gap.endHeight; {
+		numPubRand := min(gap.endHeight-startHeight+1, maxNumPubRand)
+		unfilled := randGap{startHeight: startHeight, endHeight: gap.endHeight}
+		if err := fp.chainParams.checkNumPubRand(numPubRand); err != nil {
+			return unfilled, err
+		}
+
+		res, numPubRand, err := fp.commitPubRandChunked(startHeight, numPubRand)
+		if err != nil {
+			return unfilled, fmt.Errorf("failed to commit the public randomness from height %d: %w", startHeight, err)
+		}
+
+		fp.logger.Info("filled a gap between the public randomness commits",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("start_height", startHeight),
+			zap.Uint64("num_pub_rand", numPubRand),
+			zap.String("tx_hash", res.TxHash))
+		fp.emitEvent(&hooks.Event{Type: hooks.EventRandGapRepaired, Height: startHeight, NumPubRand: numPubRand, TxHash: res.TxHash})
+		fp.metrics.AddToFpTotalCommittedRandomness(fp.GetBtcPkHex(), float64(numPubRand))
+
+		startHeight += numPubRand
+	}
+
+	return randGap{}, nil
+}
+
+// markUnmitigableRandGap reports the gap that cannot be filled, whose heights
+// cannot be voted
+func (fp *FinalityProviderInstance) markUnmitigableRandGap(gap randGap, reason string) {
+	fp.logger.Error("the finality provider has no public randomness committed for a range of heights, which cannot be voted",
+		zap.String("pk", fp.GetBtcPkHex()),
+		zap.Uint64("start_height", gap.startHeight),
+		zap.Uint64("end_height", gap.endHeight),
+		zap.String("reason", reason))
+	fp.emitEvent(&hooks.Event{Type: hooks.EventRandGapUnmitigable, Height: gap.startHeight, NumPubRand: gap.numHeights(), Error: reason})
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	ftypes "github.com/babylonchain/babylon/x/finality/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRandGaps(t *testing.T) {
+	require.Empty(t, findRandGaps(nil))
+
+	commits := map[uint64]*ftypes.PubRandCommitResponse{
+		1:   {NumPubRand: 100},
+		101: {NumPubRand: 100},
+		// heights 201-250 are missing
+		251: {NumPubRand: 50},
+		// height 301 is missing
+		302: {NumPubRand: 100},
+	}
+
+	gaps := findRandGaps(commits)
+	require.Equal(t, []randGap{
+		{startHeight: 201, endHeight: 250},
+		{startHeight: 301, endHeight: 301},
+	}, gaps)
+	require.Equal(t, uint64(50), gaps[0].numHeights())
+	require.Equal(t, uint64(1), gaps[1].numHeights())
+}
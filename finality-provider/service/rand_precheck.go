@@ -0,0 +1,72 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/cometbft/cometbft/crypto/tmhash"
+	cmtcrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+// preparePubRand returns the public randomness of the given heights along
+// with their inclusion proofs, which are checked against the randomness
+// before anything is signed at the heights. Since the proofs are persisted
+// when the randomness is committed, a missing or mismatching proof means the
+// heights are not covered by a range committed by this finality provider,
+// which is reported by ErrPubRandUnavailable instead of failing at broadcast
+func (fp *FinalityProviderInstance) preparePubRand(startHeight uint64, num uint64) ([]*btcec.FieldVal, [][]byte, error) {
+	prList, err := fp.getPubRandList(startHeight, num)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get public randomness list: %w", err)
+	}
+
+	proofBytesList, err := fp.pubRandState.GetPubRandProofList(prList)
+	if err != nil {
+		if errors.Is(err, store.ErrPubRandProofNotFound) {
+			return nil, nil, fmt.Errorf("%w: no inclusion proof is found for heights [%d, %d]",
+				ErrPubRandUnavailable, startHeight, startHeight+num-1)
+		}
+		return nil, nil, fmt.Errorf("failed to get public randomness inclusion proof list: %w", err)
+	}
+
+	for i := range prList {
+		height := startHeight + uint64(i)
+		if err := checkPubRandProof(prList[i], proofBytesList[i]); err != nil {
+			return nil, nil, fmt.Errorf("%w: the inclusion proof at height %d is invalid: %v",
+				ErrPubRandUnavailable, height, err)
+		}
+	}
+
+	return prList, proofBytesList, nil
+}
+
+// checkPubRandProof checks that the given serialized inclusion proof is well
+// formed and proves the inclusion of the given public randomness
+func checkPubRandProof(pubRand *btcec.FieldVal, proofBytes []byte) error {
+	var protoProof cmtcrypto.Proof
+	if err := protoProof.Unmarshal(proofBytes); err != nil {
+		return fmt.Errorf("failed to unmarshal the proof: %w", err)
+	}
+	proof, err := merkle.ProofFromProto(&protoProof)
+	if err != nil {
+		return err
+	}
+	if proof.Index >= proof.Total {
+		return fmt.Errorf("the index %d is out of the range of %d leaves", proof.Index, proof.Total)
+	}
+
+	pubRandBytes := bbntypes.NewSchnorrPubRandFromFieldVal(pubRand).MustMarshal()
+	// the leaves of the Merkle tree are prefixed with 0x00 as per RFC 6962
+	leafHash := tmhash.Sum(append([]byte{0}, pubRandBytes...))
+	if !bytes.Equal(proof.LeafHash, leafHash) {
+		return fmt.Errorf("the proof does not commit to the public randomness")
+	}
+
+	return nil
+}
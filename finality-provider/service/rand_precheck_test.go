@@ -0,0 +1,156 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/cometbft/cometbft/crypto/merkle"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+)
+
+// genPubRandProofs returns random public randomness with their inclusion
+// proofs in the Merkle tree of a commitment
+func genPubRandProofs(t *testing.T, num int) ([]*btcec.FieldVal, []*merkle.Proof) {
+	prList := make([]*btcec.FieldVal, 0, num)
+	prBytesList := make([][]byte, 0, num)
+	for i := 0; i < num; i++ {
+		var bz [32]byte
+		_, err := rand.Read(bz[:])
+		require.NoError(t, err)
+		var pr btcec.FieldVal
+		pr.SetBytes(&bz)
+		prList = append(prList, &pr)
+		prBytesList = append(prBytesList, bbntypes.NewSchnorrPubRandFromFieldVal(&pr).MustMarshal())
+	}
+	_, proofs := merkle.ProofsFromByteSlices(prBytesList)
+
+	return prList, proofs
+}
+
+func TestCheckPubRandProof(t *testing.T) {
+	prList, proofs := genPubRandProofs(t, 4)
+	proofBytes, err := proofs[1].ToProto().Marshal()
+	require.NoError(t, err)
+	outOfRange := *proofs[1]
+	outOfRange.Index = outOfRange.Total
+	outOfRangeBytes, err := outOfRange.ToProto().Marshal()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		pubRand    *btcec.FieldVal
+		proofBytes []byte
+		expectErr  bool
+	}{
+		{
+			name:       "the proof of the public randomness is valid",
+			pubRand:    prList[1],
+			proofBytes: proofBytes,
+		},
+		{
+			name:       "the proof of other public randomness is rejected",
+			pubRand:    prList[2],
+			proofBytes: proofBytes,
+			expectErr:  true,
+		},
+		{
+			name:       "a malformed proof is rejected",
+			pubRand:    prList[1],
+			proofBytes: []byte("not a proof"),
+			expectErr:  true,
+		},
+		{
+			name:       "a proof out of the range of the leaves is rejected",
+			pubRand:    prList[1],
+			proofBytes: outOfRangeBytes,
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkPubRandProof(tc.pubRand, tc.proofBytes)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPreparePubRand(t *testing.T) {
+	startHeight := uint64(100)
+	db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+	require.NoError(t, err)
+	defer db.Close()
+	prStore, err := store.NewPubRandProofStore(db)
+	require.NoError(t, err)
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	newInstance := func(em *mocks.MockEOTSManager) *FinalityProviderInstance {
+		return &FinalityProviderInstance{
+			btcPk:        bbntypes.NewBIP340PubKeyFromBTCPK(sk.PubKey()),
+			fpState:      NewFpState(&store.StoredFinalityProvider{BtcPk: sk.PubKey(), ChainID: "chain-test"}, nil),
+			pubRandState: NewPubRandState(prStore),
+			em:           em,
+		}
+	}
+
+	t.Run("the committed public randomness is returned with its proofs", func(t *testing.T) {
+		prList, proofs := genPubRandProofs(t, 3)
+		require.NoError(t, prStore.AddPubRandProofList(prList, proofs))
+		ctl := gomock.NewController(t)
+		em := mocks.NewMockEOTSManager(ctl)
+		em.EXPECT().CreateRandomnessPairList(gomock.Any(), []byte("chain-test"), startHeight, uint32(3), gomock.Any()).
+			Return(prList, nil).Times(1)
+
+		gotPrList, proofBytesList, err := newInstance(em).preparePubRand(startHeight, 3)
+		require.NoError(t, err)
+		require.Equal(t, prList, gotPrList)
+		require.Len(t, proofBytesList, 3)
+	})
+
+	t.Run("the public randomness without a proof is unavailable", func(t *testing.T) {
+		prList, _ := genPubRandProofs(t, 1)
+		ctl := gomock.NewController(t)
+		em := mocks.NewMockEOTSManager(ctl)
+		em.EXPECT().CreateRandomnessPairList(gomock.Any(), gomock.Any(), startHeight, uint32(1), gomock.Any()).
+			Return(prList, nil).Times(1)
+
+		_, _, err := newInstance(em).preparePubRand(startHeight, 1)
+		require.ErrorIs(t, err, ErrPubRandUnavailable)
+	})
+
+	t.Run("the public randomness with the proof of another is unavailable", func(t *testing.T) {
+		prList, proofs := genPubRandProofs(t, 2)
+		require.NoError(t, prStore.AddPubRandProofList(prList[:1], proofs[1:]))
+		ctl := gomock.NewController(t)
+		em := mocks.NewMockEOTSManager(ctl)
+		em.EXPECT().CreateRandomnessPairList(gomock.Any(), gomock.Any(), startHeight, uint32(1), gomock.Any()).
+			Return(prList[:1], nil).Times(1)
+
+		_, _, err := newInstance(em).preparePubRand(startHeight, 1)
+		require.ErrorIs(t, err, ErrPubRandUnavailable)
+	})
+
+	t.Run("a failure of the EOTS manager is not reported as unavailable", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		em := mocks.NewMockEOTSManager(ctl)
+		em.EXPECT().CreateRandomnessPairList(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("the EOTS manager is unreachable")).Times(1)
+
+		_, _, err := newInstance(em).preparePubRand(startHeight, 1)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrPubRandUnavailable)
+	})
+}
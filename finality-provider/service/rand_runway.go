@@ -0,0 +1,42 @@
+package service
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+)
+
+// recordRandRunway records the number of heights with randomness committed
+// ahead of the tip, and fires an event when it drops below the safety margin
+// or gets back to it. Nothing is fired before the first commit
+func (fp *FinalityProviderInstance) recordRandRunway(lastCommittedHeight, tipHeight uint64) {
+	var runway uint64
+	if lastCommittedHeight > tipHeight {
+		runway = lastCommittedHeight - tipHeight
+	}
+	fp.metrics.RecordFpRandomnessRunway(fp.GetBtcPkHex(), runway)
+
+	if fp.cfg.RandRunwayMargin == 0 || lastCommittedHeight == 0 {
+		return
+	}
+
+	low := runway < fp.cfg.RandRunwayMargin
+	if fp.lowRandRunway.Swap(low) == low {
+		return
+	}
+
+	if low {
+		fp.logger.Warn("the randomness runway is below the safety margin",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint64("runway", runway),
+			zap.Uint64("margin", fp.cfg.RandRunwayMargin),
+			zap.Uint64("last_committed_height", lastCommittedHeight),
+			zap.Uint64("tip_height", tipHeight))
+		fp.emitEvent(&hooks.Event{Type: hooks.EventRandRunwayLow, Height: tipHeight, RandRunway: runway})
+		return
+	}
+
+	fp.logger.Info("the randomness runway is back to the safety margin",
+		zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("runway", runway))
+	fp.emitEvent(&hooks.Event{Type: hooks.EventRandRunwayRecovered, Height: tipHeight, RandRunway: runway})
+}
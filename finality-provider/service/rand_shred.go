@@ -0,0 +1,40 @@
+package service
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// seedRandomness makes the EOTS manager derive the randomness committed from
+// the given height on from seeds, which are shredded once the heights are
+// finalized, if the randomness retention is set. Only the first call sets the
+// start height, which is above the last committed height so that no
+// randomness committed before is derived differently
+func (fp *FinalityProviderInstance) seedRandomness(startHeight uint64) error {
+	if fp.cfg.RandomnessRetention == 0 {
+		return nil
+	}
+
+	if err := fp.em.SeedRandomness(fp.btcPk.MustMarshal(), fp.GetChainID(), startHeight); err != nil {
+		return fmt.Errorf("failed to seed the randomness from height %d: %w", startHeight, err)
+	}
+
+	return nil
+}
+
+// shredRandomness shreds the randomness of the heights more than the
+// randomness retention below the given finalized height, so that a later
+// compromise of the EOTS key cannot forge the signatures of these heights
+func (fp *FinalityProviderInstance) shredRandomness(finalizedHeight uint64) {
+	retention := fp.cfg.RandomnessRetention
+	if retention == 0 || finalizedHeight <= retention {
+		return
+	}
+
+	belowHeight := finalizedHeight - retention
+	if err := fp.em.ShredRandomness(fp.btcPk.MustMarshal(), fp.GetChainID(), belowHeight); err != nil {
+		fp.logger.Warn("failed to shred the randomness below the retention",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Uint64("below_height", belowHeight), zap.Error(err))
+	}
+}
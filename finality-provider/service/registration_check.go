@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// AlreadyRegisteredError is returned when the BTC public key of the finality
+// provider to register is already registered on the consumer chain, possibly
+// by another operator, with the record on the chain
+type AlreadyRegisteredError struct {
+	Record *types.RegisteredFinalityProvider
+	// SameChainKey shows whether the finality provider is registered by the
+	// chain key of the local one, i.e., a previous registration of the
+	// daemon has succeeded
+	SameChainKey bool
+}
+
+func (e *AlreadyRegisteredError) Error() string {
+	var moniker, commission string
+	if e.Record.Description != nil {
+		moniker = e.Record.Description.Moniker
+	}
+	if e.Record.Commission != nil {
+		commission = e.Record.Commission.String()
+	}
+	by := "another chain key"
+	if e.SameChainKey {
+		by = "the same chain key"
+	}
+
+	return fmt.Sprintf("%s by %s: btc_pk %s, chain_pk %s, moniker %q, commission %s, slashed_btc_height %d",
+		ErrFpAlreadyRegistered.Error(), by,
+		bbntypes.NewBIP340PubKeyFromBTCPK(e.Record.BtcPk).MarshalHex(),
+		hex.EncodeToString(e.Record.ChainPk),
+		moniker, commission, e.Record.SlashedBtcHeight)
+}
+
+func (e *AlreadyRegisteredError) Unwrap() error {
+	return ErrFpAlreadyRegistered
+}
+
+// checkNotRegistered looks up the finality provider on its consumer chain
+// before it is registered, so that a registration bound to fail is not
+// broadcast
+func (app *FinalityProviderApp) checkNotRegistered(fp *store.StoredFinalityProvider) error {
+	record, err := app.fpManager.ccOf(fp.ChainID).QueryRegisteredFinalityProvider(fp.BtcPk)
+	if err != nil {
+		return fmt.Errorf("failed to check whether the finality provider is registered: %w", err)
+	}
+	if record == nil {
+		return nil
+	}
+
+	return &AlreadyRegisteredError{
+		Record:       record,
+		SameChainKey: bytes.Equal(record.ChainPk, fp.ChainPk.Key),
+	}
+}
+
+// MonikerCollisions returns the hex BTC public keys of the finality providers
+// other than the given one which are registered on the given consumer chain
+// with the same moniker, ignoring the case and the surrounding spaces. The
+// monikers are not unique on chain, so a collision is only a warning for the
+// operator
+func (app *FinalityProviderApp) MonikerCollisions(chainID string, btcPk *btcec.PublicKey, moniker string) ([]string, error) {
+	moniker = strings.TrimSpace(moniker)
+	if moniker == "" {
+		return nil, nil
+	}
+
+	registered, err := app.fpManager.ccOf(chainID).QueryRegisteredFinalityProviders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the registered finality providers: %w", err)
+	}
+
+	var collisions []string
+	for _, r := range registered {
+		if r.Description == nil || r.BtcPk.IsEqual(btcPk) {
+			continue
+		}
+		if strings.EqualFold(strings.TrimSpace(r.Description.Moniker), moniker) {
+			collisions = append(collisions, bbntypes.NewBIP340PubKeyFromBTCPK(r.BtcPk).MarshalHex())
+		}
+	}
+	sort.Strings(collisions)
+
+	return collisions, nil
+}
+
+// warnMonikerCollisions logs the finality providers registered with the same
+// moniker as the one to register, which does not fail the registration
+func (app *FinalityProviderApp) warnMonikerCollisions(fp *store.StoredFinalityProvider) {
+	if fp.Description == nil {
+		return
+	}
+
+	collisions, err := app.MonikerCollisions(fp.ChainID, fp.BtcPk, fp.Description.Moniker)
+	if err != nil {
+		app.logger.Warn("failed to check the moniker against the registered finality providers",
+			zap.String("moniker", fp.Description.Moniker), zap.Error(err))
+		return
+	}
+	if len(collisions) > 0 {
+		app.logger.Warn("the moniker is already used by registered finality providers",
+			zap.String("btc_pk", fp.GetBIP340BTCPK().MarshalHex()),
+			zap.String("moniker", fp.Description.Moniker),
+			zap.Strings("registered_btc_pks", collisions))
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+var ErrPrivKeyAccessDisabled = errors.New("access to the EOTS private key is disabled as a remote signer is required")
+
+var _ eotsmanager.EOTSManager = &remoteSignerEOTSManager{}
+
+// remoteSignerEOTSManager wraps a remote EOTS manager and refuses
+// to hand out private keys so that they never leave the signer
+type remoteSignerEOTSManager struct {
+	eotsmanager.EOTSManager
+}
+
+func newRemoteSignerEOTSManager(em eotsmanager.EOTSManager) (*remoteSignerEOTSManager, error) {
+	if _, ok := em.(*eotsmanager.LocalEOTSManager); ok {
+		return nil, errors.New("a remote EOTS manager is required but a local one is given")
+	}
+
+	return &remoteSignerEOTSManager{EOTSManager: em}, nil
+}
+
+func (em *remoteSignerEOTSManager) KeyRecord(_ []byte, _ string) (*types.KeyRecord, error) {
+	return nil, ErrPrivKeyAccessDisabled
+}
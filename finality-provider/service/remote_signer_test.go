@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+)
+
+func TestRemoteSignerEOTSManager(t *testing.T) {
+	t.Run("a local EOTS manager is refused", func(t *testing.T) {
+		_, err := newRemoteSignerEOTSManager(&eotsmanager.LocalEOTSManager{})
+		require.Error(t, err)
+	})
+
+	t.Run("the private key is never handed out", func(t *testing.T) {
+		// the wrapped EOTS manager is not reached, which the mock would fail on
+		em, err := newRemoteSignerEOTSManager(mocks.NewMockEOTSManager(gomock.NewController(t)))
+		require.NoError(t, err)
+
+		record, err := em.KeyRecord([]byte("pk"), "passphrase")
+		require.ErrorIs(t, err, ErrPrivKeyAccessDisabled)
+		require.Nil(t, record)
+	})
+
+	t.Run("the signing is left to the remote signer", func(t *testing.T) {
+		mockEm := mocks.NewMockEOTSManager(gomock.NewController(t))
+		sig := &schnorr.Signature{}
+		mockEm.EXPECT().SignSchnorrSig([]byte("pk"), []byte("msg"), "passphrase").Return(sig, nil).Times(1)
+		em, err := newRemoteSignerEOTSManager(mockEm)
+		require.NoError(t, err)
+
+		res, err := em.SignSchnorrSig([]byte("pk"), []byte("msg"), "passphrase")
+		require.NoError(t, err)
+		require.Equal(t, sig, res)
+	})
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"go.uber.org/zap"
+)
+
+// SetRewardAddress sets the address receiving the rewards of the finality
+// provider on the consumer chain and returns the tx hash. The tx is signed by
+// the key of the daemon, which must be the chain key of the finality provider
+func (app *FinalityProviderApp) SetRewardAddress(fpPk *bbntypes.BIP340PubKey, rewardAddr string) (string, error) {
+	if app.IsStandby() {
+		return "", ErrStandbyMode
+	}
+
+	hrp, _, err := bech32.DecodeAndConvert(rewardAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid reward address %s: %w", rewardAddr, err)
+	}
+	if hrp != app.config.BabylonConfig.AccountPrefix {
+		return "", fmt.Errorf("invalid reward address %s: the prefix should be %s", rewardAddr, app.config.BabylonConfig.AccountPrefix)
+	}
+
+	btcPk := fpPk.MustToBTCPK()
+	storedFp, err := app.fps.GetFinalityProvider(btcPk)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := app.fpManager.ccOf(storedFp.ChainID).SetRewardAddress(storedFp.ChainPk.Key, rewardAddr)
+	if err != nil {
+		return "", fmt.Errorf("failed to set the reward address: %w", err)
+	}
+
+	if err := app.fps.SetFpRewardAddress(btcPk, rewardAddr); err != nil {
+		return "", err
+	}
+
+	app.logger.Info("the reward address of the finality provider is set",
+		zap.String("pk", fpPk.MarshalHex()),
+		zap.String("reward_address", rewardAddr),
+		zap.String("tx_hash", res.TxHash))
+
+	return res.TxHash, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestSetRewardAddress(t *testing.T) {
+	chainID := "chain-test"
+	db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+	require.NoError(t, err)
+	defer db.Close()
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+	fpPk := createTestFinalityProvider(t, s, chainID)
+	storedFp, err := s.GetFinalityProvider(fpPk.MustToBTCPK())
+	require.NoError(t, err)
+
+	rewardAddr, err := bech32.ConvertAndEncode("bbn", []byte("reward-address-bytes"))
+	require.NoError(t, err)
+	otherPrefixAddr, err := bech32.ConvertAndEncode("cosmos", []byte("reward-address-bytes"))
+	require.NoError(t, err)
+
+	newApp := func(cc *mocks.MockClientController, standbyOf string) *FinalityProviderApp {
+		cfg := &fpcfg.Config{
+			StandbyOf:     standbyOf,
+			BabylonConfig: &fpcfg.BBNConfig{ChainID: chainID, AccountPrefix: "bbn"},
+		}
+		return &FinalityProviderApp{
+			config:    cfg,
+			fps:       s,
+			logger:    zap.NewNop(),
+			fpManager: &FinalityProviderManager{fps: s, config: cfg, cc: cc},
+		}
+	}
+
+	t.Run("the reward address is set and stored", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		cc.EXPECT().SetRewardAddress(storedFp.ChainPk.Key, rewardAddr).
+			Return(&types.TxResponse{TxHash: "tx-hash"}, nil).Times(1)
+
+		txHash, err := newApp(cc, "").SetRewardAddress(fpPk, rewardAddr)
+		require.NoError(t, err)
+		require.Equal(t, "tx-hash", txHash)
+
+		storedFp, err := s.GetFinalityProvider(fpPk.MustToBTCPK())
+		require.NoError(t, err)
+		require.Equal(t, rewardAddr, storedFp.RewardAddress)
+	})
+
+	t.Run("a failed tx leaves the stored reward address intact", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		newAddr, err := bech32.ConvertAndEncode("bbn", []byte("new-reward-address"))
+		require.NoError(t, err)
+		cc.EXPECT().SetRewardAddress(storedFp.ChainPk.Key, newAddr).
+			Return(nil, errors.New("insufficient fees")).Times(1)
+
+		_, err = newApp(cc, "").SetRewardAddress(fpPk, newAddr)
+		require.Error(t, err)
+
+		storedFp, err := s.GetFinalityProvider(fpPk.MustToBTCPK())
+		require.NoError(t, err)
+		require.Equal(t, rewardAddr, storedFp.RewardAddress)
+	})
+
+	t.Run("an invalid reward address is rejected", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		app := newApp(mocks.NewMockClientController(ctl), "")
+
+		_, err := app.SetRewardAddress(fpPk, "not-an-address")
+		require.Error(t, err)
+		_, err = app.SetRewardAddress(fpPk, otherPrefixAddr)
+		require.Error(t, err)
+	})
+
+	t.Run("a standby does not set the reward address", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		app := newApp(mocks.NewMockClientController(ctl), "127.0.0.1:12581")
+
+		_, err := app.SetRewardAddress(fpPk, rewardAddr)
+		require.ErrorIs(t, err, ErrStandbyMode)
+	})
+}
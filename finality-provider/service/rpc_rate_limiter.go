@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// clientLimiterIdleTimeout is the duration after which the limiter of a
+	// client that has not sent any request is dropped
+	clientLimiterIdleTimeout = 10 * time.Minute
+	// clientLimiterPruneInterval is the minimum interval between each
+	// removal of idle client limiters
+	clientLimiterPruneInterval = time.Minute
+)
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rpcRateLimiter rejects RPC calls exceeding the global or per-client token
+// buckets, so that a misbehaving client cannot starve the processing loops
+type rpcRateLimiter struct {
+	// global is nil if the global rate is unlimited
+	global *rate.Limiter
+
+	// clientLimit is zero if the per-client rate is unlimited
+	clientLimit rate.Limit
+	clientBurst int
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastPrune time.Time
+}
+
+// newRPCRateLimiter returns a rate limiter with the given requests per second
+// and bursts, where a zero rate means unlimited
+func newRPCRateLimiter(globalRate float64, globalBurst int, clientRate float64, clientBurst int) *rpcRateLimiter {
+	l := &rpcRateLimiter{
+		clientLimit: rate.Limit(clientRate),
+		clientBurst: clientBurst,
+		clients:     make(map[string]*clientLimiter),
+	}
+	if globalRate > 0 {
+		l.global = rate.NewLimiter(rate.Limit(globalRate), globalBurst)
+	}
+
+	return l
+}
+
+// allow returns a ResourceExhausted error if the call from the caller in the
+// context exceeds any of the rate limits. The global limit is checked first,
+// so that a call refused by it does not spend the token of the client, and
+// the global token of a call refused by the client limit is handed back, so
+// that a single client cannot exhaust the global limit
+func (l *rpcRateLimiter) allow(ctx context.Context) error {
+	// the reservation is cancelled at the time it is made, as the tokens of
+	// a reservation already due are not restored
+	now := time.Now()
+	var globalRes *rate.Reservation
+	if l.global != nil {
+		globalRes = l.global.ReserveN(now, 1)
+		if !globalRes.OK() || globalRes.DelayFrom(now) > 0 {
+			globalRes.CancelAt(now)
+			return status.Error(codes.ResourceExhausted, "the global rate limit is exceeded")
+		}
+	}
+
+	if l.clientLimit > 0 && !l.clientLimiter(callerHost(ctx)).Allow() {
+		if globalRes != nil {
+			globalRes.CancelAt(now)
+		}
+		return status.Error(codes.ResourceExhausted, "the client rate limit is exceeded")
+	}
+
+	return nil
+}
+
+func (l *rpcRateLimiter) clientLimiter(client string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastPrune) > clientLimiterPruneInterval {
+		for c, cl := range l.clients {
+			if now.Sub(cl.lastSeen) > clientLimiterIdleTimeout {
+				delete(l.clients, c)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	cl, ok := l.clients[client]
+	if !ok {
+		cl = &clientLimiter{limiter: rate.NewLimiter(l.clientLimit, l.clientBurst)}
+		l.clients[client] = cl
+	}
+	cl.lastSeen = now
+
+	return cl.limiter
+}
+
+func (l *rpcRateLimiter) unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := l.allow(ctx); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (l *rpcRateLimiter) stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := l.allow(ss.Context()); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// callerHost returns the host of the caller without the port, so that the
+// connections of the same client share a rate limit
+func callerHost(ctx context.Context) string {
+	addr := callerAddress(ctx)
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
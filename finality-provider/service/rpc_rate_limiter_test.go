@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func callerCtx(ip string, port int) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: port}})
+}
+
+func requireRateLimited(t *testing.T, err error) {
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRPCRateLimiter(t *testing.T) {
+	clientA := callerCtx("10.0.0.1", 1000)
+	clientB := callerCtx("10.0.0.2", 1000)
+
+	t.Run("the calls beyond the global burst are refused", func(t *testing.T) {
+		l := newRPCRateLimiter(1, 2, 0, 0)
+
+		require.NoError(t, l.allow(clientA))
+		require.NoError(t, l.allow(clientB))
+		requireRateLimited(t, l.allow(clientA))
+		requireRateLimited(t, l.allow(clientB))
+	})
+
+	t.Run("the calls beyond the burst of a client are refused", func(t *testing.T) {
+		l := newRPCRateLimiter(0, 0, 1, 1)
+
+		require.NoError(t, l.allow(clientA))
+		requireRateLimited(t, l.allow(clientA))
+		// the connections of the same host share the limit
+		requireRateLimited(t, l.allow(callerCtx("10.0.0.1", 2000)))
+		require.NoError(t, l.allow(clientB))
+	})
+
+	t.Run("a call refused by the global limit does not spend the token of the client", func(t *testing.T) {
+		l := newRPCRateLimiter(1, 1, 1, 1)
+
+		require.NoError(t, l.allow(clientA))
+		requireRateLimited(t, l.allow(clientB))
+		require.NotContains(t, l.clients, callerHost(clientB))
+	})
+
+	t.Run("a call refused by the client limit does not spend the global token", func(t *testing.T) {
+		l := newRPCRateLimiter(1, 2, 1, 1)
+
+		require.NoError(t, l.allow(clientA))
+		for i := 0; i < 5; i++ {
+			requireRateLimited(t, l.allow(clientA))
+		}
+		require.NoError(t, l.allow(clientB))
+		requireRateLimited(t, l.allow(callerCtx("10.0.0.3", 1000)))
+	})
+
+	t.Run("the idle client limiters are pruned", func(t *testing.T) {
+		l := newRPCRateLimiter(0, 0, 1, 1)
+		require.NoError(t, l.allow(clientA))
+		require.NoError(t, l.allow(clientB))
+		l.clients[callerHost(clientA)].lastSeen = time.Now().Add(-clientLimiterIdleTimeout - time.Second)
+		l.lastPrune = time.Now().Add(-clientLimiterPruneInterval - time.Second)
+
+		l.clientLimiter("10.0.0.3")
+		require.NotContains(t, l.clients, callerHost(clientA))
+		require.Contains(t, l.clients, callerHost(clientB))
+		require.Contains(t, l.clients, "10.0.0.3")
+
+		// the pruned client starts with a full burst again
+		require.NoError(t, l.allow(clientA))
+	})
+}
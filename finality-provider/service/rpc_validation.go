@@ -0,0 +1,237 @@
+package service
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// maxChainIDLength is the maximum length of the chain IDs of CometBFT
+const maxChainIDLength = 50
+
+// chainIDPattern is the charset of the chain IDs, e.g., bbn-test-3
+var chainIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// rpcValidator collects the violations of the parameters of an RPC request,
+// so that all the malformed fields are reported at once at the RPC boundary
+// rather than surfacing as errors deep in the daemon
+type rpcValidator struct {
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+func (v *rpcValidator) addViolation(field, format string, args ...interface{}) {
+	v.violations = append(v.violations, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// err returns an InvalidArgument error carrying the violations as the details,
+// or nil if there is no violation
+func (v *rpcValidator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(v.violations))
+	for _, fv := range v.violations {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", fv.Field, fv.Description))
+	}
+	st := status.New(codes.InvalidArgument, "invalid request: "+strings.Join(msgs, "; "))
+
+	stWithDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v.violations})
+	if err != nil {
+		return st.Err()
+	}
+
+	return stWithDetails.Err()
+}
+
+// btcPk returns the BTC public key of the given hex string, which is either
+// the x-only or the compressed encoding and normalized to BIP-340
+func (v *rpcValidator) btcPk(field, pkHex string) *bbntypes.BIP340PubKey {
+	if pkHex == "" {
+		v.addViolation(field, "should not be empty")
+		return nil
+	}
+
+	pk, err := types.ParseBTCPkHex(pkHex)
+	if err != nil {
+		v.addViolation(field, "should be the hex string of a 32-byte BIP-340 or 33-byte compressed public key: %v", err)
+		return nil
+	}
+
+	return pk
+}
+
+// chainPk returns the chain public key of the given hex string, which is
+// optional
+func (v *rpcValidator) chainPk(field, pkHex string) []byte {
+	if pkHex == "" {
+		return nil
+	}
+
+	pk, err := hex.DecodeString(pkHex)
+	if err != nil || len(pk) != secp256k1.PubKeySize {
+		v.addViolation(field, "should be the hex string of a %d-byte compressed secp256k1 public key", secp256k1.PubKeySize)
+		return nil
+	}
+
+	return pk
+}
+
+// chainPkBytes checks that the field is a compressed secp256k1 public key
+func (v *rpcValidator) chainPkBytes(field string, pk []byte) {
+	if len(pk) != secp256k1.PubKeySize {
+		v.addViolation(field, "should be a %d-byte compressed secp256k1 public key", secp256k1.PubKeySize)
+	}
+}
+
+// address returns the account address of the given bech32 string, which is
+// optional. The prefix is not checked as the daemon may serve any chain
+func (v *rpcValidator) address(field, addr string) []byte {
+	if addr == "" {
+		return nil
+	}
+
+	_, addrBytes, err := bech32.DecodeAndConvert(addr)
+	if err != nil {
+		v.addViolation(field, "should be a bech32 address: %v", err)
+		return nil
+	}
+
+	return addrBytes
+}
+
+// commission returns the commission rate of the given string, which should be
+// between 0 and 1
+func (v *rpcValidator) commission(field, rate string) *math.LegacyDec {
+	commission, err := math.LegacyNewDecFromStr(rate)
+	if err != nil {
+		v.addViolation(field, "should be a decimal: %v", err)
+		return nil
+	}
+	if commission.IsNegative() || commission.GT(math.LegacyOneDec()) {
+		v.addViolation(field, "should be between 0 and 1, got %s", rate)
+		return nil
+	}
+
+	return &commission
+}
+
+// description returns the description of the given bytes, whose fields should
+// be within the limits of the consumer chain
+func (v *rpcValidator) description(field string, desBytes []byte) *stakingtypes.Description {
+	var des stakingtypes.Description
+	if err := des.Unmarshal(desBytes); err != nil {
+		v.addViolation(field, "should be an encoded description: %v", err)
+		return nil
+	}
+
+	return v.descriptionFields(field, &des)
+}
+
+// structuredDescription returns the description of the given fields, which
+// should be within the limits of the consumer chain
+func (v *rpcValidator) structuredDescription(field string, des *proto.Description) *stakingtypes.Description {
+	return v.descriptionFields(field, &stakingtypes.Description{
+		Moniker:         des.Moniker,
+		Identity:        des.Identity,
+		Website:         des.Website,
+		SecurityContact: des.SecurityContact,
+		Details:         des.Details,
+	})
+}
+
+// descriptionFields checks the fields of the description against the limits
+// of the consumer chain
+func (v *rpcValidator) descriptionFields(field string, des *stakingtypes.Description) *stakingtypes.Description {
+	valid := true
+	for _, f := range []struct {
+		name   string
+		value  string
+		maxLen int
+	}{
+		{"moniker", des.Moniker, stakingtypes.MaxMonikerLength},
+		{"identity", des.Identity, stakingtypes.MaxIdentityLength},
+		{"website", des.Website, stakingtypes.MaxWebsiteLength},
+		{"security_contact", des.SecurityContact, stakingtypes.MaxSecurityContactLength},
+		{"details", des.Details, stakingtypes.MaxDetailsLength},
+	} {
+		if len(f.value) > f.maxLen {
+			v.addViolation(field+"."+f.name, "should be at most %d characters, got %d", f.maxLen, len(f.value))
+			valid = false
+		}
+	}
+	if !valid {
+		return nil
+	}
+
+	return des
+}
+
+// hdPath checks the syntax of the derivation path, which is optional, by
+// deriving a key along it from a throwaway seed as the keyring does
+func (v *rpcValidator) hdPath(field, path string) {
+	if path == "" {
+		return
+	}
+	masterKey, chainCode := hd.ComputeMastersFromSeed(make([]byte, 32))
+	if _, err := hd.DerivePrivateKeyForPath(masterKey, chainCode, path); err != nil {
+		v.addViolation(field, "should be a derivation path, e.g., m/44'/118'/0'/0/0: %v", err)
+	}
+}
+
+// chainID checks the charset and the length of the chain ID
+func (v *rpcValidator) chainID(field, chainID string) {
+	switch {
+	case chainID == "":
+		v.addViolation(field, "should not be empty")
+	case len(chainID) > maxChainIDLength:
+		v.addViolation(field, "should be at most %d characters, got %d", maxChainIDLength, len(chainID))
+	case !chainIDPattern.MatchString(chainID):
+		v.addViolation(field, "should only contain letters, digits, '.', '_' and '-', got %q", chainID)
+	}
+}
+
+// logLevel parses the log level, which is info if not set
+func (v *rpcValidator) logLevel(field, level string) zapcore.Level {
+	if level == "" {
+		return zapcore.InfoLevel
+	}
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		v.addViolation(field, "should be one of debug, info, warn and error, got %q", level)
+	}
+
+	return lvl
+}
+
+// nonEmpty checks that the field is set
+func (v *rpcValidator) nonEmpty(field, value string) {
+	if value == "" {
+		v.addViolation(field, "should not be empty")
+	}
+}
+
+// nonEmptyBytes checks that the field is set
+func (v *rpcValidator) nonEmptyBytes(field string, value []byte) {
+	if len(value) == 0 {
+		v.addViolation(field, "should not be empty")
+	}
+}
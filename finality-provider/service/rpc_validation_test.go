@@ -0,0 +1,58 @@
+package service
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+func TestRPCValidator(t *testing.T) {
+	var valid rpcValidator
+	valid.chainID("chain_id", "bbn-test-3")
+	valid.hdPath("hd_path", "m/44'/118'/0'/0/0")
+	valid.hdPath("hd_path", "")
+	require.NotNil(t, valid.commission("commission", "0.05"))
+	des := valid.structuredDescription("structured_description", &proto.Description{Moniker: "my-name", Website: "https://example.com"})
+	require.NotNil(t, des)
+	require.Equal(t, "my-name", des.Moniker)
+	require.Equal(t, "https://example.com", des.Website)
+	// both encodings of a BTC public key are normalized to BIP-340
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	xOnlyPk := valid.btcPk("btc_pk", bbntypes.NewBIP340PubKeyFromBTCPK(sk.PubKey()).MarshalHex())
+	compressedPk := valid.btcPk("btc_pk", hex.EncodeToString(sk.PubKey().SerializeCompressed()))
+	require.NotNil(t, xOnlyPk)
+	require.NotNil(t, compressedPk)
+	require.Equal(t, xOnlyPk.MarshalHex(), compressedPk.MarshalHex())
+	require.NoError(t, valid.err())
+
+	var v rpcValidator
+	v.chainID("chain_id", "bbn test")
+	v.hdPath("hd_path", "m/44'/x")
+	require.Nil(t, v.commission("commission", "1.5"))
+	require.Nil(t, v.btcPk("btc_pk", "not-hex"))
+	require.Nil(t, v.structuredDescription("structured_description", &proto.Description{Moniker: strings.Repeat("m", 71)}))
+
+	err = v.err()
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+	badReq, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+
+	fields := make([]string, 0, len(badReq.FieldViolations))
+	for _, fv := range badReq.FieldViolations {
+		fields = append(fields, fv.Field)
+	}
+	require.Equal(t, []string{"chain_id", "hd_path", "commission", "btc_pk", "structured_description.moniker"}, fields)
+}
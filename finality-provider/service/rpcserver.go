@@ -4,25 +4,24 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"sync"
-	"sync/atomic"
+	"time"
 
-	"cosmossdk.io/math"
 	bbntypes "github.com/babylonchain/babylon/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/babylonchain/finality-provider/finality-provider/approval"
+	"github.com/babylonchain/finality-provider/finality-provider/faultinject"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	"github.com/babylonchain/finality-provider/types"
-	"github.com/babylonchain/finality-provider/version"
 )
 
 // rpcServer is the main RPC server for the Finality Provider daemon that handles
 // gRPC incoming requests.
 type rpcServer struct {
-	started  int32
-	shutdown int32
-
 	proto.UnimplementedFinalityProvidersServer
 
 	app *FinalityProviderApp
@@ -42,22 +41,10 @@ func newRPCServer(
 	}
 }
 
-// Start signals that the RPC server starts accepting requests.
-func (r *rpcServer) Start() error {
-	if atomic.AddInt32(&r.started, 1) != 1 {
-		return nil
-	}
-
-	return nil
-}
-
 // Stop signals that the RPC server should attempt a graceful shutdown and
-// cancel any outstanding requests.
+// cancel any outstanding requests, which is called once by the lifecycle of
+// the server
 func (r *rpcServer) Stop() error {
-	if atomic.AddInt32(&r.shutdown, 1) != 1 {
-		return nil
-	}
-
 	close(r.quit)
 
 	r.wg.Wait()
@@ -73,25 +60,32 @@ func (r *rpcServer) RegisterWithGrpcServer(grpcServer *grpc.Server) error {
 	return nil
 }
 
-// GetInfo returns general information relating to the active daemon
+// GetInfo returns general information relating to the active daemon, i.e.,
+// its version, its build, the enabled features and the configured chains
 func (r *rpcServer) GetInfo(context.Context, *proto.GetInfoRequest) (*proto.GetInfoResponse, error) {
 
-	return &proto.GetInfoResponse{
-		Version: version.Version(),
-	}, nil
+	return buildInfo(r.app.GetConfig()), nil
 }
 
 // CreateFinalityProvider generates a finality-provider object and saves it in the database
 func (r *rpcServer) CreateFinalityProvider(ctx context.Context, req *proto.CreateFinalityProviderRequest) (
 	*proto.CreateFinalityProviderResponse, error) {
 
-	commissionRate, err := math.LegacyNewDecFromStr(req.Commission)
-	if err != nil {
-		return nil, err
+	var v rpcValidator
+	v.nonEmpty("key_name", req.KeyName)
+	v.chainID("chain_id", req.ChainId)
+	v.hdPath("hd_path", req.HdPath)
+	var description *stakingtypes.Description
+	if req.StructuredDescription != nil {
+		if len(req.Description) > 0 {
+			v.addViolation("description", "should not be set along with structured_description")
+		}
+		description = v.structuredDescription("structured_description", req.StructuredDescription)
+	} else {
+		description = v.description("description", req.Description)
 	}
-
-	var description stakingtypes.Description
-	if err := description.Unmarshal(req.Description); err != nil {
+	commissionRate := v.commission("commission", req.Commission)
+	if err := v.err(); err != nil {
 		return nil, err
 	}
 
@@ -100,16 +94,29 @@ func (r *rpcServer) CreateFinalityProvider(ctx context.Context, req *proto.Creat
 		req.ChainId,
 		req.Passphrase,
 		req.HdPath,
-		&description,
-		&commissionRate,
+		description,
+		commissionRate,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// the collisions are only reported, as the monikers are not unique on
+	// the consumer chain
+	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(result.FpInfo.BtcPkHex)
+	if err != nil {
+		return nil, err
+	}
+	collisions, err := r.app.MonikerCollisions(req.ChainId, fpPk.MustToBTCPK(), description.Moniker)
+	if err != nil {
+		r.app.logger.Warn("failed to check the moniker against the registered finality providers",
+			zap.String("moniker", description.Moniker), zap.Error(err))
+	}
+
 	return &proto.CreateFinalityProviderResponse{
-		FinalityProvider: result.FpInfo,
+		FinalityProvider:  result.FpInfo,
+		MonikerCollisions: collisions,
 	}, nil
 
 }
@@ -118,11 +125,27 @@ func (r *rpcServer) CreateFinalityProvider(ctx context.Context, req *proto.Creat
 func (r *rpcServer) RegisterFinalityProvider(ctx context.Context, req *proto.RegisterFinalityProviderRequest) (
 	*proto.RegisterFinalityProviderResponse, error) {
 
-	txRes, err := r.app.RegisterFinalityProvider(req.BtcPk)
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+	// the approvals are signed over the BIP-340 encoding
+	fpPkHex := fpPk.MarshalHex()
+
+	if err := r.app.CheckApproval(req.ApprovalToken, approval.OperationRegisterFinalityProvider, fpPkHex); err != nil {
+		return nil, err
+	}
+
+	txRes, err := r.app.RegisterFinalityProvider(fpPkHex)
 	if err != nil {
 		return nil, fmt.Errorf("failed to register the finality-provider to Babylon: %w", err)
 	}
 
+	if err := r.app.SetActivationHeight(txRes.btcPubKey, req.ActivationHeight); err != nil {
+		return nil, fmt.Errorf("failed to set the activation height of the finality-provider: %w", err)
+	}
+
 	// the finality-provider instance should be started right after registration
 	if err := r.app.StartHandlingFinalityProvider(txRes.btcPubKey, req.Passphrase); err != nil {
 		return nil, fmt.Errorf("failed to start the registered finality-provider %s: %w", hex.EncodeToString(txRes.bbnPubKey.Key), err)
@@ -136,8 +159,15 @@ func (r *rpcServer) RegisterFinalityProvider(ctx context.Context, req *proto.Reg
 func (r *rpcServer) AddFinalitySignature(ctx context.Context, req *proto.AddFinalitySignatureRequest) (
 	*proto.AddFinalitySignatureResponse, error) {
 
-	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(req.BtcPk)
-	if err != nil {
+	// the extraction of the private key is only for presentation and testing
+	if !r.app.config.EnableTestRPC {
+		return nil, ErrTestRPCDisabled
+	}
+
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	v.nonEmptyBytes("app_hash", req.AppHash)
+	if err := v.err(); err != nil {
 		return nil, err
 	}
 
@@ -151,6 +181,10 @@ func (r *rpcServer) AddFinalitySignature(ctx context.Context, req *proto.AddFina
 		Hash:   req.AppHash,
 	}
 
+	if err := fpi.checkManualFinalitySig(b, req.Force); err != nil {
+		return nil, err
+	}
+
 	txRes, privKey, err := fpi.TestSubmitFinalitySignatureAndExtractPrivKey(b)
 	if err != nil {
 		return nil, err
@@ -166,6 +200,7 @@ func (r *rpcServer) AddFinalitySignature(ctx context.Context, req *proto.AddFina
 		if err != nil {
 			return nil, err
 		}
+		defer localPrivKey.Zero()
 		localSkHex := localPrivKey.Key.String()
 		localSkNegateHex := localPrivKey.Key.Negate().String()
 		if res.ExtractedSkHex == localSkHex {
@@ -182,15 +217,52 @@ func (r *rpcServer) AddFinalitySignature(ctx context.Context, req *proto.AddFina
 	return res, nil
 }
 
-// QueryFinalityProvider queries the information of the finality-provider
+// SignFinality returns the finality signature over a block without broadcasting it
+func (r *rpcServer) SignFinality(ctx context.Context, req *proto.SignFinalityRequest) (
+	*proto.SignFinalityResponse, error) {
+
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	v.nonEmptyBytes("block_hash", req.BlockHash)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return r.app.SignFinality(fpPk, req.Height, req.BlockHash)
+}
+
+// QueryFinalityProvider queries the information of the finality-provider by
+// its BTC public key, or otherwise by its chain key or moniker
 func (r *rpcServer) QueryFinalityProvider(ctx context.Context, req *proto.QueryFinalityProviderRequest) (
 	*proto.QueryFinalityProviderResponse, error) {
 
-	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(req.BtcPk)
-	if err != nil {
+	var v rpcValidator
+	if req.BtcPk != "" {
+		fpPk := v.btcPk("btc_pk", req.BtcPk)
+		if err := v.err(); err != nil {
+			return nil, err
+		}
+		fp, err := r.app.GetFinalityProviderInfo(fpPk)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.QueryFinalityProviderResponse{FinalityProvider: fp}, nil
+	}
+
+	filter := &fpFilter{
+		chainPk: v.chainPk("chain_pk", req.ChainPk),
+		address: v.address("address", req.Address),
+		moniker: req.Moniker,
+	}
+	if len(v.violations) == 0 && filter.isEmpty() {
+		v.addViolation("btc_pk", "one of btc_pk, chain_pk, address and moniker should be set")
+	}
+	if err := v.err(); err != nil {
 		return nil, err
 	}
-	fp, err := r.app.GetFinalityProviderInfo(fpPk)
+
+	fp, err := r.app.FindFinalityProvider(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -198,11 +270,22 @@ func (r *rpcServer) QueryFinalityProvider(ctx context.Context, req *proto.QueryF
 	return &proto.QueryFinalityProviderResponse{FinalityProvider: fp}, nil
 }
 
-// QueryFinalityProviderList queries the information of a list of finality providers
+// QueryFinalityProviderList queries the information of a list of finality
+// providers, which are filtered by their chain key or moniker if requested
 func (r *rpcServer) QueryFinalityProviderList(ctx context.Context, req *proto.QueryFinalityProviderListRequest) (
 	*proto.QueryFinalityProviderListResponse, error) {
 
-	fps, err := r.app.ListAllFinalityProvidersInfo()
+	var v rpcValidator
+	filter := &fpFilter{
+		chainPk: v.chainPk("chain_pk", req.ChainPk),
+		address: v.address("address", req.Address),
+		moniker: req.Moniker,
+	}
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	fps, err := r.app.FindFinalityProviders(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -213,6 +296,19 @@ func (r *rpcServer) QueryFinalityProviderList(ctx context.Context, req *proto.Qu
 // SignMessageFromChainKey signs a message from the chain keyring.
 func (r *rpcServer) SignMessageFromChainKey(ctx context.Context, req *proto.SignMessageFromChainKeyRequest) (
 	*proto.SignMessageFromChainKeyResponse, error) {
+	var v rpcValidator
+	v.nonEmpty("key_name", req.KeyName)
+	v.hdPath("hd_path", req.HdPath)
+	v.nonEmptyBytes("msg_to_sign", req.MsgToSign)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	payload := approval.SignMessagePayload(req.KeyName, req.MsgToSign)
+	if err := r.app.CheckApproval(req.ApprovalToken, approval.OperationSignMessageFromChainKey, payload); err != nil {
+		return nil, err
+	}
+
 	signature, err := r.app.SignRawMsg(req.KeyName, req.Passphrase, req.HdPath, req.MsgToSign)
 	if err != nil {
 		return nil, err
@@ -220,3 +316,371 @@ func (r *rpcServer) SignMessageFromChainKey(ctx context.Context, req *proto.Sign
 
 	return &proto.SignMessageFromChainKeyResponse{Signature: signature}, nil
 }
+
+// SignMessage signs an arbitrary message with the chain key of a finality
+// provider as specified by ADR-36
+func (r *rpcServer) SignMessage(ctx context.Context, req *proto.SignMessageRequest) (
+	*proto.SignMessageResponse, error) {
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	v.hdPath("hd_path", req.HdPath)
+	v.nonEmptyBytes("message", req.Message)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return r.app.SignMessage(fpPk, req.Passphrase, req.HdPath, req.Message)
+}
+
+// VerifyMessage verifies an ADR-36 signature of an arbitrary message
+func (r *rpcServer) VerifyMessage(ctx context.Context, req *proto.VerifyMessageRequest) (
+	*proto.VerifyMessageResponse, error) {
+	var v rpcValidator
+	v.address("signer", req.Signer)
+	v.nonEmpty("signer", req.Signer)
+	v.chainPkBytes("pub_key", req.PubKey)
+	v.nonEmptyBytes("message", req.Message)
+	v.nonEmptyBytes("signature", req.Signature)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return r.app.VerifyMessage(req.Signer, req.PubKey, req.Message, req.Signature)
+}
+
+// ProveKeyOwnership signs a challenge with the BTC key of a finality provider
+// to prove the ownership of the key
+func (r *rpcServer) ProveKeyOwnership(ctx context.Context, req *proto.ProveKeyOwnershipRequest) (
+	*proto.ProveKeyOwnershipResponse, error) {
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	v.nonEmptyBytes("challenge", req.Challenge)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return r.app.ProveKeyOwnership(fpPk, req.Challenge, req.Passphrase)
+}
+
+// ValidateState checks the stored finality providers against the keys in the
+// EOTS manager and reports the orphaned records
+func (r *rpcServer) ValidateState(ctx context.Context, req *proto.ValidateStateRequest) (
+	*proto.ValidateStateResponse, error) {
+
+	res, err := r.app.ValidateState()
+	if err != nil {
+		return nil, err
+	}
+
+	orphanedKeys := make([]*proto.OrphanedEOTSKey, 0, len(res.OrphanedKeys))
+	for _, k := range res.OrphanedKeys {
+		btcPkHex := hex.EncodeToString(k.PubKey)
+		orphanedKeys = append(orphanedKeys, &proto.OrphanedEOTSKey{
+			KeyName:            k.Name,
+			BtcPkHex:           btcPkHex,
+			BtcPkCompressedHex: types.CompressedBTCPkHex(btcPkHex),
+		})
+	}
+
+	return &proto.ValidateStateResponse{
+		FpsMissingKeys: res.FpsMissingKeys,
+		OrphanedKeys:   orphanedKeys,
+	}, nil
+}
+
+// SyncState streams the state of the finality providers to a standby daemon
+func (r *rpcServer) SyncState(req *proto.SyncStateRequest, stream proto.FinalityProviders_SyncStateServer) error {
+	return r.app.StreamState(stream.Context(), stream.Send)
+}
+
+// ExportState streams the encrypted archive of the state of the daemon in
+// chunks, the last of which carries the hash of the whole archive
+func (r *rpcServer) ExportState(req *proto.ExportStateRequest, stream proto.FinalityProviders_ExportStateServer) error {
+	payload := approval.ExportStatePayload(req.IncludeKeys)
+	if err := r.app.CheckApproval(req.ApprovalToken, approval.OperationExportState, payload); err != nil {
+		return err
+	}
+
+	archive, err := r.app.ExportState(req.Passphrase, req.IncludeKeys)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(archive); start += stateChunkSize {
+		end := start + stateChunkSize
+		if end > len(archive) {
+			end = len(archive)
+		}
+		chunk := &proto.ExportStateResponse{Data: archive[start:end]}
+		if end == len(archive) {
+			chunk.Sha256 = sha256Hex(archive)
+		}
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ImportState receives the encrypted archive of the state in chunks and
+// stages it to be restored on the next start of the daemon. The stream is
+// aborted once the archive exceeds the max import state size
+func (r *rpcServer) ImportState(stream proto.FinalityProviders_ImportStateServer) error {
+	var (
+		archive       []byte
+		passphrase    string
+		expectedHash  string
+		approvalToken string
+	)
+	maxSize := r.app.config.MaxImportStateSize
+	for i := 0; ; i++ {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			passphrase = chunk.Passphrase
+			expectedHash = chunk.Sha256
+			approvalToken = chunk.ApprovalToken
+		}
+		if uint64(len(archive))+uint64(len(chunk.Data)) > maxSize {
+			return fmt.Errorf("%w: the archive exceeds %d bytes", ErrStateArchiveTooLarge, maxSize)
+		}
+		archive = append(archive, chunk.Data...)
+	}
+
+	// the approval is over the archive as received, not the expected hash
+	if err := r.app.CheckApproval(approvalToken, approval.OperationImportState, sha256Hex(archive)); err != nil {
+		return err
+	}
+
+	res, err := r.app.ImportState(archive, passphrase, expectedHash)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(res)
+}
+
+// QueryFinalizedBlocks queries the latest finalized blocks annotated with the
+// votes of the finality providers in the daemon
+func (r *rpcServer) QueryFinalizedBlocks(ctx context.Context, req *proto.QueryFinalizedBlocksRequest) (
+	*proto.QueryFinalizedBlocksResponse, error) {
+
+	blocks, err := r.app.QueryFinalizedBlocks(req.ChainId, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.QueryFinalizedBlocksResponse{Blocks: blocks}, nil
+}
+
+// ExportFinalityProofs exports the finality proofs of the blocks in a range of
+// heights fetched from the consumer chain
+func (r *rpcServer) ExportFinalityProofs(ctx context.Context, req *proto.ExportFinalityProofsRequest) (
+	*proto.ExportFinalityProofsResponse, error) {
+
+	proofs, err := r.app.ExportFinalityProofs(req.ChainId, req.StartHeight, req.EndHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ExportFinalityProofsResponse{Proofs: proofs}, nil
+}
+
+// QueryNetworkParticipation reports the participation of all the finality
+// providers on the consumer chain
+func (r *rpcServer) QueryNetworkParticipation(ctx context.Context, req *proto.QueryNetworkParticipationRequest) (
+	*proto.QueryNetworkParticipationResponse, error) {
+
+	return r.app.QueryNetworkParticipation()
+}
+
+// QueryVotingHistory queries the indexed votes and randomness commits of a
+// finality provider
+func (r *rpcServer) QueryVotingHistory(ctx context.Context, req *proto.QueryVotingHistoryRequest) (
+	*proto.QueryVotingHistoryResponse, error) {
+
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return r.app.QueryVotingHistory(fpPk.MarshalHex(), req.FromHeight, req.Limit)
+}
+
+// ResumeFinalityProvider restarts a finality provider in safe mode
+func (r *rpcServer) ResumeFinalityProvider(ctx context.Context, req *proto.ResumeFinalityProviderRequest) (
+	*proto.ResumeFinalityProviderResponse, error) {
+
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.app.ResumeFinalityProvider(fpPk, req.Passphrase); err != nil {
+		return nil, err
+	}
+
+	return &proto.ResumeFinalityProviderResponse{}, nil
+}
+
+// PauseFinalityProvider stops a running finality provider until it is
+// resumed
+func (r *rpcServer) PauseFinalityProvider(ctx context.Context, req *proto.PauseFinalityProviderRequest) (
+	*proto.PauseFinalityProviderResponse, error) {
+
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.app.PauseFinalityProvider(fpPk); err != nil {
+		return nil, err
+	}
+
+	return &proto.PauseFinalityProviderResponse{}, nil
+}
+
+// SetRewardAddress sets the address receiving the rewards of a finality
+// provider
+func (r *rpcServer) SetRewardAddress(ctx context.Context, req *proto.SetRewardAddressRequest) (
+	*proto.SetRewardAddressResponse, error) {
+
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	v.nonEmpty("reward_address", req.RewardAddress)
+	v.address("reward_address", req.RewardAddress)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	txHash, err := r.app.SetRewardAddress(fpPk, req.RewardAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.SetRewardAddressResponse{TxHash: txHash}, nil
+}
+
+// QueryStatus returns the live status of all the finality providers of the
+// daemon
+func (r *rpcServer) QueryStatus(ctx context.Context, req *proto.QueryStatusRequest) (
+	*proto.QueryStatusResponse, error) {
+
+	return r.app.QueryStatus()
+}
+
+// CommitPubRand commits the public randomness of a finality provider right
+// away, optionally up to a target height
+func (r *rpcServer) CommitPubRand(ctx context.Context, req *proto.CommitPubRandRequest) (
+	*proto.CommitPubRandResponse, error) {
+
+	var v rpcValidator
+	fpPk := v.btcPk("btc_pk", req.BtcPk)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return r.app.CommitPubRand(fpPk, req.TargetHeight)
+}
+
+// TailLogs streams the live logs of the daemon until the client cancels the
+// stream or the server shuts down
+func (r *rpcServer) TailLogs(req *proto.TailLogsRequest, stream proto.FinalityProviders_TailLogsServer) error {
+	var v rpcValidator
+	level := v.logLevel("level", req.Level)
+	if err := v.err(); err != nil {
+		return err
+	}
+
+	sub, err := r.app.TailLogs(level, req.Subsystem)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case entry := <-sub.Entries():
+			err := stream.Send(&proto.TailLogsResponse{
+				Timestamp: entry.Time.UnixNano(),
+				Level:     entry.Level.String(),
+				Subsystem: entry.Subsystem,
+				Message:   entry.Message,
+				Fields:    entry.Fields,
+				Dropped:   sub.Dropped(),
+			})
+			if err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// InjectFault injects or clears a fault for chaos testing
+func (r *rpcServer) InjectFault(ctx context.Context, req *proto.InjectFaultRequest) (
+	*proto.InjectFaultResponse, error) {
+
+	if !faultinject.Enabled {
+		return nil, faultinject.ErrDisabled
+	}
+
+	if req.Clear {
+		faultinject.Clear(faultinject.Kind(req.Kind))
+	} else {
+		kind, err := faultinject.ParseKind(req.Kind)
+		if err != nil {
+			return nil, err
+		}
+		err = faultinject.Inject(faultinject.Fault{
+			Kind:  kind,
+			Times: req.Times,
+			Delay: time.Duration(req.DelayMs) * time.Millisecond,
+		})
+		if err != nil {
+			return nil, err
+		}
+		r.app.logger.Warn("injected a fault",
+			zap.String("kind", req.Kind),
+			zap.Uint32("times", req.Times),
+			zap.Uint64("delay_ms", req.DelayMs),
+		)
+	}
+
+	active := faultinject.Active()
+	res := &proto.InjectFaultResponse{ActiveFaults: make([]*proto.InjectedFault, 0, len(active))}
+	for _, f := range active {
+		res.ActiveFaults = append(res.ActiveFaults, &proto.InjectedFault{
+			Kind:    string(f.Kind),
+			Times:   f.Times,
+			DelayMs: uint64(f.Delay.Milliseconds()),
+		})
+	}
+
+	return res, nil
+}
+
+// ListKeys lists the EOTS keys with the finality providers using them and
+// their usage
+func (r *rpcServer) ListKeys(ctx context.Context, req *proto.ListKeysRequest) (
+	*proto.ListKeysResponse, error) {
+
+	keys, err := r.app.ListKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.ListKeysResponse{Keys: keys}, nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+func TestAddFinalitySignatureTestRPC(t *testing.T) {
+	req := &proto.AddFinalitySignatureRequest{BtcPk: "not-hex", Height: 1}
+
+	t.Run("the test RPC is refused unless enabled", func(t *testing.T) {
+		r := newRPCServer(&FinalityProviderApp{config: &fpcfg.Config{EnableTestRPC: false}})
+
+		_, err := r.AddFinalitySignature(context.Background(), req)
+		require.ErrorIs(t, err, ErrTestRPCDisabled)
+	})
+
+	t.Run("the enabled test RPC validates the request", func(t *testing.T) {
+		r := newRPCServer(&FinalityProviderApp{config: &fpcfg.Config{EnableTestRPC: true}})
+
+		_, err := r.AddFinalitySignature(context.Background(), req)
+		require.NotErrorIs(t, err, ErrTestRPCDisabled)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
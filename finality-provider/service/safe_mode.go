@@ -0,0 +1,118 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// handleSubmissionFailure counts the consecutive submissions failed with
+// non-retryable errors, and switches the finality provider to safe mode once
+// they reach the threshold, so that it stops burning fees on submissions that
+// keep failing. It returns false if the failure is not handled, i.e., safe
+// mode is disabled or the finality provider is slashed, in which case the
+// error is critical
+func (fp *FinalityProviderInstance) handleSubmissionFailure(err error) bool {
+	if fp.cfg.SafeModeThreshold == 0 {
+		return false
+	}
+	// a slashed finality provider is terminated by the manager instead
+	if strings.Contains(err.Error(), btcstakingtypes.ErrFpAlreadySlashed.Error()) {
+		return false
+	}
+
+	failures := fp.failures.Inc()
+	if failures < fp.cfg.SafeModeThreshold {
+		fp.logger.Error("the submission failed with a non-retryable error",
+			zap.String("pk", fp.GetBtcPkHex()),
+			zap.Uint32("consecutive_failures", failures),
+			zap.Uint32("safe_mode_threshold", fp.cfg.SafeModeThreshold),
+			zap.Error(err),
+		)
+		fp.emitEvent(&hooks.Event{Type: hooks.EventError, Error: err.Error()})
+		return true
+	}
+
+	fp.reportCriticalErr(fmt.Errorf("%w after %d consecutive failures, the last of which: %v",
+		ErrSafeModeEntered, failures, err))
+
+	return true
+}
+
+// resetSubmissionFailures is called after every successful submission
+func (fp *FinalityProviderInstance) resetSubmissionFailures() {
+	fp.failures.Store(0)
+}
+
+// setFinalityProviderSafeMode stops the instance of the finality provider,
+// which stays in safe mode until it is resumed explicitly, even across
+// restarts of the daemon. The instance is stopped before the status is set,
+// otherwise its status update may overwrite safe mode. It fails if the
+// instance is already removed, e.g., paused or unloaded concurrently
+func (fpm *FinalityProviderManager) setFinalityProviderSafeMode(fpi *FinalityProviderInstance) error {
+	if err := fpm.removeFinalityProviderInstance(fpi.GetBtcPkBIP340()); err != nil {
+		return fmt.Errorf("failed to stop the finality-provider %s entering safe mode: %w", fpi.GetBtcPkHex(), err)
+	}
+	if err := fpm.fps.SetFpStatus(fpi.GetBtcPk(), proto.FinalityProviderStatus_SAFE_MODE); err != nil {
+		return fmt.Errorf("the finality-provider %s is stopped but failed to enter safe mode: %w", fpi.GetBtcPkHex(), err)
+	}
+
+	return nil
+}
+
+// ResumeFinalityProvider restarts the finality provider in safe mode. Its
+// status is set back to REGISTERED, which is then updated by the status
+// update of the running instance
+func (app *FinalityProviderApp) ResumeFinalityProvider(fpPk *bbntypes.BIP340PubKey, passphrase string) error {
+	if app.IsStandby() {
+		return ErrStandbyMode
+	}
+
+	btcPk := fpPk.MustToBTCPK()
+	storedFp, err := app.fps.GetFinalityProvider(btcPk)
+	if err != nil {
+		return err
+	}
+	if storedFp.Status != proto.FinalityProviderStatus_SAFE_MODE {
+		return fmt.Errorf("%w: the status is %s", ErrNotInSafeMode, storedFp.Status.String())
+	}
+
+	if err := app.fps.SetFpStatus(btcPk, proto.FinalityProviderStatus_REGISTERED); err != nil {
+		return err
+	}
+
+	app.logger.Info("resuming the finality provider from safe mode", zap.String("pk", fpPk.MarshalHex()))
+
+	return app.fpManager.StartFinalityProvider(fpPk, passphrase)
+}
+
+// PauseFinalityProvider stops the running finality provider by switching it
+// to safe mode, so that it stays stopped across restarts of the daemon until
+// it is resumed by ResumeFinalityProvider
+func (app *FinalityProviderApp) PauseFinalityProvider(fpPk *bbntypes.BIP340PubKey) error {
+	if app.IsStandby() {
+		return ErrStandbyMode
+	}
+
+	// the instance is stopped before the status is set, otherwise its status
+	// update may overwrite safe mode. An unloaded instance is reloaded first
+	if _, err := app.GetFinalityProviderInstance(fpPk); err != nil {
+		return err
+	}
+	if err := app.fpManager.removeFinalityProviderInstance(fpPk); err != nil {
+		return err
+	}
+	if err := app.fps.SetFpStatus(fpPk.MustToBTCPK(), proto.FinalityProviderStatus_SAFE_MODE); err != nil {
+		return fmt.Errorf("the finality provider is stopped but failed to enter safe mode: %w", err)
+	}
+
+	app.logger.Info("the finality provider is paused until it is resumed", zap.String("pk", fpPk.MarshalHex()))
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+func newSafeModeTestInstance(t *testing.T, threshold uint32) (*FinalityProviderInstance, chan *CriticalError) {
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	errChan := make(chan *CriticalError, 1)
+
+	return &FinalityProviderInstance{
+		cfg:             &fpcfg.Config{SafeModeThreshold: threshold},
+		fpState:         NewFpState(&store.StoredFinalityProvider{BtcPk: sk.PubKey()}, nil),
+		failures:        atomic.NewUint32(0),
+		criticalErrChan: errChan,
+		logger:          zap.NewNop(),
+	}, errChan
+}
+
+func TestHandleSubmissionFailure(t *testing.T) {
+	submissionErr := errors.New("the submission failed")
+
+	t.Run("the failures are critical if safe mode is disabled", func(t *testing.T) {
+		fp, errChan := newSafeModeTestInstance(t, 0)
+
+		require.False(t, fp.handleSubmissionFailure(submissionErr))
+		require.Empty(t, errChan)
+	})
+
+	t.Run("a slashed finality provider does not enter safe mode", func(t *testing.T) {
+		fp, errChan := newSafeModeTestInstance(t, 1)
+
+		require.False(t, fp.handleSubmissionFailure(btcstakingtypes.ErrFpAlreadySlashed))
+		require.Empty(t, errChan)
+	})
+
+	t.Run("safe mode is entered after the consecutive failures", func(t *testing.T) {
+		fp, errChan := newSafeModeTestInstance(t, 3)
+
+		for i := 0; i < 2; i++ {
+			require.True(t, fp.handleSubmissionFailure(submissionErr))
+			require.Empty(t, errChan)
+		}
+		require.True(t, fp.handleSubmissionFailure(submissionErr))
+		require.Len(t, errChan, 1)
+		criticalErr := <-errChan
+		require.ErrorIs(t, criticalErr.err, ErrSafeModeEntered)
+		require.Equal(t, fp.GetBtcPkHex(), criticalErr.fpBtcPk.MarshalHex())
+	})
+
+	t.Run("a successful submission resets the failures", func(t *testing.T) {
+		fp, errChan := newSafeModeTestInstance(t, 2)
+
+		require.True(t, fp.handleSubmissionFailure(submissionErr))
+		fp.resetSubmissionFailures()
+		require.True(t, fp.handleSubmissionFailure(submissionErr))
+		require.Empty(t, errChan)
+	})
+}
@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"sync"
-	"sync/atomic"
 
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/signal"
@@ -13,15 +13,16 @@ import (
 	"google.golang.org/grpc"
 
 	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/lifecycle"
+	"github.com/babylonchain/finality-provider/log"
 	"github.com/babylonchain/finality-provider/metrics"
+	"github.com/babylonchain/finality-provider/profiling"
 )
 
 // Server is the main daemon construct for the Finality Provider server. It handles
 // spinning up the RPC sever, the database, and any other components that the
 // Taproot Asset server needs to function.
 type Server struct {
-	started int32
-
 	cfg    *fpcfg.Config
 	logger *zap.Logger
 
@@ -29,6 +30,11 @@ type Server struct {
 	db          kvdb.Backend
 	interceptor signal.Interceptor
 
+	// lifecycle starts the components of the daemon in the order of store,
+	// metrics, app, i.e., the EOTS manager, the clients of the chains and
+	// the finality-provider instances, and RPC, and stops them in reverse
+	lifecycle *lifecycle.Graph
+
 	quit chan struct{}
 }
 
@@ -40,6 +46,7 @@ func NewFinalityProviderServer(cfg *fpcfg.Config, l *zap.Logger, fpa *FinalityPr
 		rpcServer:   newRPCServer(fpa),
 		db:          db,
 		interceptor: sig,
+		lifecycle:   lifecycle.New(l, defaultComponentTimeout),
 		quit:        make(chan struct{}, 1),
 	}
 }
@@ -47,60 +54,214 @@ func NewFinalityProviderServer(cfg *fpcfg.Config, l *zap.Logger, fpa *FinalityPr
 // RunUntilShutdown runs the main EOTS manager server loop until a signal is
 // received to shut down the process.
 func (s *Server) RunUntilShutdown() error {
-	if atomic.AddInt32(&s.started, 1) != 1 {
-		return nil
+	logBuildInfo(s.logger, buildInfo(s.cfg))
+
+	if err := s.addComponents(); err != nil {
+		return err
 	}
 
-	// Start the metrics server.
-	promAddr, err := s.cfg.Metrics.Address()
-	if err != nil {
-		return fmt.Errorf("failed to get prometheus address: %w", err)
+	if err := s.lifecycle.Start(context.Background()); err != nil {
+		return fmt.Errorf("failed to start the daemon: %w", err)
 	}
-	metricsServer := metrics.Start(promAddr, s.logger)
-
-	defer func() {
-		s.logger.Info("Shutdown complete")
-	}()
-
-	defer func() {
-		s.logger.Info("Closing database...")
-		s.db.Close()
-		s.logger.Info("Database closed")
-		metricsServer.Stop(context.Background())
-		s.logger.Info("Metrics server stopped")
-	}()
-
-	listenAddr := s.cfg.RpcListener
-	// we create listeners from the RPCListeners defined
-	// in the config.
-	lis, err := net.Listen("tcp", listenAddr)
-	if err != nil {
-		return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+
+	s.logger.Info("Finality Provider Daemon is fully active!")
+
+	// Wait for shutdown signal from either a graceful server stop or from
+	// the interrupt handler, or for the app to request a halt.
+	var haltErr error
+	select {
+	case <-s.interceptor.ShutdownChannel():
+	case haltErr = <-s.rpcServer.app.Halted():
+		s.logger.Error("the finality-provider app requested the daemon to halt", zap.Error(haltErr))
 	}
-	defer lis.Close()
 
-	grpcServer := grpc.NewServer()
-	defer grpcServer.Stop()
+	// no new request is accepted while the finality providers drain their
+	// in-flight submissions, which must finish before the database is
+	// closed and flushed
+	s.logger.Info("Stopping the daemon...",
+		zap.Duration("drain_timeout", s.cfg.ShutdownDrainTimeout))
+	if err := s.lifecycle.Stop(context.Background()); err != nil {
+		s.logger.Error("failed to stop the daemon cleanly", zap.Error(err))
+	}
+
+	s.logger.Info("Shutdown complete")
+
+	return haltErr
+}
 
-	if err := s.rpcServer.RegisterWithGrpcServer(grpcServer); err != nil {
-		return fmt.Errorf("failed to register gRPC server: %w", err)
+// addComponents adds the components of the daemon to the lifecycle
+func (s *Server) addComponents() error {
+	var metricsServer *metrics.Server
+	components := []lifecycle.Component{
+		{
+			Name: "store",
+			Stop: func(context.Context) error {
+				s.logger.Info("Closing database...")
+				if err := s.db.Close(); err != nil {
+					return err
+				}
+				s.logger.Info("Database closed")
+				return nil
+			},
+		},
+		{
+			Name: "metrics",
+			Start: func(context.Context) error {
+				promAddr, err := s.cfg.Metrics.Address()
+				if err != nil {
+					return fmt.Errorf("failed to get prometheus address: %w", err)
+				}
+				metricsServer = metrics.Start(promAddr, s.logger)
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				metricsServer.Stop(ctx)
+				s.logger.Info("Metrics server stopped")
+				return nil
+			},
+		},
+		{
+			// the app is usually started before the server, in which case
+			// only its stop is left to the server
+			Name:      "app",
+			DependsOn: []string{"store"},
+			Start: func(context.Context) error {
+				return s.rpcServer.app.Start()
+			},
+			Stop: func(context.Context) error {
+				return s.rpcServer.app.Stop()
+			},
+			Timeout: s.cfg.ShutdownDrainTimeout + 2*defaultComponentTimeout,
+		},
+		s.rpcComponent(),
 	}
 
-	// All the necessary components have been registered, so we can
-	// actually start listening for requests.
-	if err := s.startGrpcListen(grpcServer, []net.Listener{lis}); err != nil {
-		return fmt.Errorf("failed to start gRPC listener: %v", err)
+	if s.cfg.Profiling.Enabled() {
+		var profiler *profiling.Profiler
+		components = append(components, lifecycle.Component{
+			Name: "profiling",
+			Start: func(context.Context) error {
+				var err error
+				profiler, err = profiling.Start(s.cfg.Profiling, "fpd", s.logger)
+				if err != nil {
+					return fmt.Errorf("failed to start the profiling: %w", err)
+				}
+				return nil
+			},
+			Stop: func(context.Context) error {
+				profiler.Stop()
+				return nil
+			},
+		})
 	}
 
-	s.logger.Info("Finality Provider Daemon is fully active!")
+	// the health probes are stopped before the database is closed
+	if s.cfg.HealthListener != "" {
+		var healthServer *http.Server
+		components = append(components, lifecycle.Component{
+			Name:      "health",
+			DependsOn: []string{"app"},
+			Start: func(context.Context) error {
+				healthServer = startHealthServer(s.cfg.HealthListener, s.rpcServer.app, s.logger)
+				return nil
+			},
+			Stop: func(context.Context) error {
+				stopHealthServer(healthServer, s.logger)
+				return nil
+			},
+		})
+	}
 
-	// Wait for shutdown signal from either a graceful server stop or from
-	// the interrupt handler.
-	<-s.interceptor.ShutdownChannel()
+	for _, c := range components {
+		if err := s.lifecycle.Add(c); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// rpcComponent returns the component of the RPC server, which is stopped
+// first so that no new request is accepted while the daemon stops
+func (s *Server) rpcComponent() lifecycle.Component {
+	var (
+		grpcServer  *grpc.Server
+		auditLogger *zap.Logger
+	)
+
+	return lifecycle.Component{
+		Name:      "rpc",
+		DependsOn: []string{"app"},
+		Start: func(context.Context) error {
+			listenAddr := s.cfg.RpcListener
+			// we create listeners from the RPCListeners defined
+			// in the config.
+			lis, err := net.Listen("tcp", listenAddr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", listenAddr, err)
+			}
+
+			opts := s.cfg.GRPCConfig.ServerOptions()
+			if s.cfg.AuditLogFile != "" {
+				auditLogger, err = log.NewAuditLoggerWithFile(s.cfg.AuditLogFile)
+				if err != nil {
+					lis.Close()
+					return fmt.Errorf("failed to create the audit logger: %w", err)
+				}
+
+				audit := newAuditInterceptor(auditLogger)
+				opts = append(opts,
+					grpc.ChainUnaryInterceptor(audit.unary()),
+					grpc.ChainStreamInterceptor(audit.stream()),
+				)
+				s.logger.Info("RPC audit log enabled", zap.String("file", s.cfg.AuditLogFile))
+			}
+
+			if s.cfg.RPCRateLimit > 0 || s.cfg.RPCClientRateLimit > 0 {
+				limiter := newRPCRateLimiter(
+					s.cfg.RPCRateLimit, s.cfg.RPCRateBurst,
+					s.cfg.RPCClientRateLimit, s.cfg.RPCClientRateBurst,
+				)
+				// the rate limiter runs after the audit interceptor so that the
+				// rejected calls are audited as well
+				opts = append(opts,
+					grpc.ChainUnaryInterceptor(limiter.unary()),
+					grpc.ChainStreamInterceptor(limiter.stream()),
+				)
+				s.logger.Info("RPC rate limits enabled",
+					zap.Float64("global_rate", s.cfg.RPCRateLimit),
+					zap.Float64("client_rate", s.cfg.RPCClientRateLimit),
+				)
+			}
+
+			grpcServer = grpc.NewServer(opts...)
+
+			if err := s.rpcServer.RegisterWithGrpcServer(grpcServer); err != nil {
+				lis.Close()
+				return fmt.Errorf("failed to register gRPC server: %w", err)
+			}
+
+			// All the necessary components have been registered, so we can
+			// actually start listening for requests.
+			if err := s.startGrpcListen(grpcServer, []net.Listener{lis}); err != nil {
+				return fmt.Errorf("failed to start gRPC listener: %v", err)
+			}
+
+			return nil
+		},
+		Stop: func(context.Context) error {
+			grpcServer.Stop()
+			if err := s.rpcServer.Stop(); err != nil {
+				return err
+			}
+			if auditLogger != nil {
+				_ = auditLogger.Sync()
+			}
+			return nil
+		},
+	}
+}
+
 // startGrpcListen starts the GRPC server on the passed listeners.
 func (s *Server) startGrpcListen(grpcServer *grpc.Server, listeners []net.Listener) error {
 
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// sharedBlockSource is the client controller through which the pollers of the
+// instances of the same chain fetch the blocks. Each block is fetched from the
+// chain once and fanned out to all the pollers asking for it within the poll
+// interval, so that the query load does not grow with the number of instances.
+// The pollers keep their own cursors, so the instances can be at different
+// heights. The cached blocks expire after the poll interval, and the reorg
+// checks of the pollers bypass the cache so that they see the blocks replaced
+// by the chain right away
+type sharedBlockSource struct {
+	clientcontroller.ClientController
+
+	ttl time.Duration
+
+	mu     sync.Mutex
+	blocks map[uint64]*blockFetch
+	best   *blockFetch
+}
+
+// blockFetch is a block fetched, or being fetched, from the chain
+type blockFetch struct {
+	done      chan struct{}
+	block     *types.BlockInfo
+	err       error
+	fetchedAt time.Time
+}
+
+func newSharedBlockSource(cc clientcontroller.ClientController, ttl time.Duration) *sharedBlockSource {
+	return &sharedBlockSource{
+		ClientController: cc,
+		ttl:              ttl,
+		blocks:           make(map[uint64]*blockFetch),
+	}
+}
+
+// QueryBlock returns the block at the given height, which is only queried
+// from the chain if no other poller has fetched it within the poll interval
+func (s *sharedBlockSource) QueryBlock(height uint64) (*types.BlockInfo, error) {
+	s.mu.Lock()
+	f, ok := s.blocks[height]
+	if !ok || f.expired(s.ttl) {
+		s.pruneExpired()
+		f = s.startFetch(func() (*types.BlockInfo, error) {
+			return s.ClientController.QueryBlock(height)
+		})
+		s.blocks[height] = f
+	}
+	s.mu.Unlock()
+
+	<-f.done
+
+	return f.block, f.err
+}
+
+// queryFreshBlock queries the block at the given height from the chain even
+// if it is cached, and caches it in place of the cached one, so that the
+// pollers re-polling the height after a reorg get the block on the chain
+func (s *sharedBlockSource) queryFreshBlock(height uint64) (*types.BlockInfo, error) {
+	s.mu.Lock()
+	f := s.startFetch(func() (*types.BlockInfo, error) {
+		return s.ClientController.QueryBlock(height)
+	})
+	s.blocks[height] = f
+	s.mu.Unlock()
+
+	<-f.done
+
+	return f.block, f.err
+}
+
+// QueryBestBlock returns the tip of the chain, which is only queried from the
+// chain if no other poller has fetched it within the poll interval
+func (s *sharedBlockSource) QueryBestBlock() (*types.BlockInfo, error) {
+	s.mu.Lock()
+	f := s.best
+	if f == nil || f.expired(s.ttl) {
+		f = s.startFetch(s.ClientController.QueryBestBlock)
+		s.best = f
+	}
+	s.mu.Unlock()
+
+	<-f.done
+
+	return f.block, f.err
+}
+
+// Close is a no-op as the client controller is shared by the instances and
+// closed by the app
+func (s *sharedBlockSource) Close() error {
+	return nil
+}
+
+// startFetch runs the query in the background and returns the fetch the
+// concurrent callers wait on. The failed fetches expire immediately so that
+// the errors are not shared beyond the concurrent callers
+func (s *sharedBlockSource) startFetch(query func() (*types.BlockInfo, error)) *blockFetch {
+	f := &blockFetch{done: make(chan struct{})}
+	go func() {
+		f.block, f.err = query()
+		if f.err == nil {
+			f.fetchedAt = time.Now()
+		}
+		close(f.done)
+	}()
+
+	return f
+}
+
+// pruneExpired drops the expired blocks, which must be called with the lock
+// held
+func (s *sharedBlockSource) pruneExpired() {
+	for height, f := range s.blocks {
+		if f.expired(s.ttl) {
+			delete(s.blocks, height)
+		}
+	}
+}
+
+// expired returns whether the fetch completed longer than the ttl ago or
+// failed. A fetch in progress never expires
+func (f *blockFetch) expired(ttl time.Duration) bool {
+	select {
+	case <-f.done:
+		return f.err != nil || time.Since(f.fetchedAt) >= ttl
+	default:
+		return false
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestSharedBlockSource(t *testing.T) {
+	height := uint64(10)
+
+	t.Run("a block is fetched once for all the pollers", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		release := make(chan struct{})
+		cc.EXPECT().QueryBlock(height).DoAndReturn(func(height uint64) (*types.BlockInfo, error) {
+			<-release
+			return &types.BlockInfo{Height: height}, nil
+		}).Times(1)
+		s := newSharedBlockSource(cc, time.Hour)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				b, err := s.QueryBlock(height)
+				require.NoError(t, err)
+				require.Equal(t, height, b.Height)
+			}()
+		}
+		close(release)
+		wg.Wait()
+
+		// the cached block is returned afterwards
+		b, err := s.QueryBlock(height)
+		require.NoError(t, err)
+		require.Equal(t, height, b.Height)
+	})
+
+	t.Run("an expired block is fetched again", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		cc.EXPECT().QueryBlock(height).Return(&types.BlockInfo{Height: height}, nil).Times(2)
+		cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: height}, nil).Times(2)
+		ttl := 10 * time.Millisecond
+		s := newSharedBlockSource(cc, ttl)
+
+		for i := 0; i < 2; i++ {
+			_, err := s.QueryBlock(height)
+			require.NoError(t, err)
+			_, err = s.QueryBlock(height)
+			require.NoError(t, err)
+			_, err = s.QueryBestBlock()
+			require.NoError(t, err)
+			_, err = s.QueryBestBlock()
+			require.NoError(t, err)
+			time.Sleep(2 * ttl)
+		}
+	})
+
+	t.Run("a failed fetch is not cached", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		gomock.InOrder(
+			cc.EXPECT().QueryBlock(height).Return(nil, errors.New("the block is not produced yet")),
+			cc.EXPECT().QueryBlock(height).Return(&types.BlockInfo{Height: height}, nil),
+		)
+		s := newSharedBlockSource(cc, time.Hour)
+
+		_, err := s.QueryBlock(height)
+		require.Error(t, err)
+		b, err := s.QueryBlock(height)
+		require.NoError(t, err)
+		require.Equal(t, height, b.Height)
+	})
+
+	t.Run("a fresh block replaces the cached one", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		oldBlock := &types.BlockInfo{Height: height, Hash: []byte("old")}
+		newBlock := &types.BlockInfo{Height: height, Hash: []byte("new")}
+		gomock.InOrder(
+			cc.EXPECT().QueryBlock(height).Return(oldBlock, nil),
+			cc.EXPECT().QueryBlock(height).Return(newBlock, nil),
+		)
+		s := newSharedBlockSource(cc, time.Hour)
+
+		b, err := s.QueryBlock(height)
+		require.NoError(t, err)
+		require.Equal(t, oldBlock, b)
+		b, err = s.queryFreshBlock(height)
+		require.NoError(t, err)
+		require.Equal(t, newBlock, b)
+		b, err = s.QueryBlock(height)
+		require.NoError(t, err)
+		require.Equal(t, newBlock, b)
+	})
+
+	t.Run("the shared client controller is not closed", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		s := newSharedBlockSource(mocks.NewMockClientController(ctl), time.Hour)
+
+		require.NoError(t, s.Close())
+	})
+}
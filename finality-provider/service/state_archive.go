@@ -0,0 +1,504 @@
+package service
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/argon2"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/util"
+)
+
+const (
+	// StateRestoreDirName is the directory under the home directory where
+	// an imported state archive is staged until the daemon is restarted
+	StateRestoreDirName = "restore"
+
+	// stateChunkSize is the size of each chunk of the archive streamed over
+	// the RPC, which is well below the default limit of gRPC messages
+	stateChunkSize = 1 << 20
+
+	stateArchiveMagic   = "FPDSTATE1"
+	stateArchiveVersion = 1
+
+	// the argon2id parameters deriving the key that encrypts the archive
+	stateArchiveSaltLen         = 16
+	stateArchiveKeyLen          = 32
+	stateArchiveArgon2Time      = 3
+	stateArchiveArgon2MemoryKiB = 64 * 1024
+	stateArchiveArgon2Threads   = 4
+
+	stateManifestFile = "manifest.json"
+	stateConfigFile   = "fpd.conf"
+	stateDBFile       = "finality-provider.db"
+	stateKeyringDir   = "keyring"
+
+	// preRestoreSuffix is appended to the files replaced by a restore so
+	// that the restore can be rolled back by hand
+	preRestoreSuffix = ".pre-restore"
+)
+
+// stateManifest describes the files in a state archive
+type stateManifest struct {
+	Version   int    `json:"version"`
+	CreatedAt int64  `json:"created_at"`
+	ChainID   string `json:"chain_id"`
+	// KeyringBackend is the backend of the keyring in the archive, which
+	// is empty if the keys are not included
+	KeyringBackend string `json:"keyring_backend,omitempty"`
+	// Files maps the files in the archive to the hex of their SHA-256 hashes
+	Files map[string]string `json:"files"`
+}
+
+// ExportState returns an archive of the config, the store, and optionally
+// the chain keys of the daemon, encrypted under the passphrase
+func (app *FinalityProviderApp) ExportState(passphrase string, includeKeys bool) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("the passphrase to encrypt the archive is empty")
+	}
+	homePath := app.config.HomePath()
+	if homePath == "" {
+		return nil, fmt.Errorf("the config of the daemon is not loaded from a home directory")
+	}
+
+	files := make(map[string][]byte)
+	cfgBytes, err := os.ReadFile(fpcfg.ConfigFile(homePath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the config: %w", err)
+	}
+	files[stateConfigFile] = cfgBytes
+
+	var db bytes.Buffer
+	if err := app.fps.Backup(&db); err != nil {
+		return nil, fmt.Errorf("failed to back up the database: %w", err)
+	}
+	files[stateDBFile] = db.Bytes()
+
+	manifest := &stateManifest{
+		Version:   stateArchiveVersion,
+		CreatedAt: time.Now().Unix(),
+		ChainID:   app.config.BabylonConfig.ChainID,
+		Files:     make(map[string]string),
+	}
+
+	if includeKeys {
+		keyringDir, err := keyringDirOf(app.config.BabylonConfig.KeyDirectory, app.config.BabylonConfig.KeyringBackend)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(keyringDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the keyring: %w", err)
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				continue
+			}
+			bz, err := os.ReadFile(filepath.Join(keyringDir, e.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the keyring: %w", err)
+			}
+			files[path.Join(stateKeyringDir, e.Name())] = bz
+		}
+		manifest.KeyringBackend = app.config.BabylonConfig.KeyringBackend
+	}
+
+	for name, bz := range files {
+		manifest.Files[name] = sha256Hex(bz)
+	}
+
+	archive, err := writeStateArchive(manifest, files)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptStateArchive(archive, passphrase)
+}
+
+// ImportState verifies the encrypted archive exported by ExportState and
+// stages it under the home directory to be restored by ApplyStagedRestore
+// on the next start of the daemon. The SHA-256 hash of the archive is
+// verified against expectedHash unless it is empty
+func (app *FinalityProviderApp) ImportState(archive []byte, passphrase, expectedHash string) (*proto.ImportStateResponse, error) {
+	homePath := app.config.HomePath()
+	if homePath == "" {
+		return nil, fmt.Errorf("the config of the daemon is not loaded from a home directory")
+	}
+
+	if expectedHash != "" && !strings.EqualFold(sha256Hex(archive), expectedHash) {
+		return nil, fmt.Errorf("%w: the SHA-256 hash of the archive is not %s", ErrInvalidStateArchive, expectedHash)
+	}
+
+	plain, err := decryptStateArchive(archive, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	manifest, files, err := readStateArchive(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	restoreDir := filepath.Join(homePath, StateRestoreDirName)
+	if err := os.RemoveAll(restoreDir); err != nil {
+		return nil, fmt.Errorf("failed to clean up the restore directory: %w", err)
+	}
+	names := make([]string, 0, len(files))
+	for name, bz := range files {
+		p := filepath.Join(restoreDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create the restore directory: %w", err)
+		}
+		if err := os.WriteFile(p, bz, 0600); err != nil {
+			return nil, fmt.Errorf("failed to stage %s: %w", name, err)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// the manifest is written last so that a partially staged archive is
+	// never restored
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(restoreDir, stateManifestFile), manifestBytes, 0600); err != nil {
+		return nil, fmt.Errorf("failed to stage the manifest: %w", err)
+	}
+
+	app.logger.Info("staged the imported state to be restored on the next start",
+		zap.String("chain_id", manifest.ChainID),
+		zap.Int("num_files", len(names)),
+		zap.String("restore_dir", restoreDir),
+	)
+
+	return &proto.ImportStateResponse{
+		ChainId:    manifest.ChainID,
+		CreatedAt:  manifest.CreatedAt,
+		Files:      names,
+		RestoreDir: restoreDir,
+	}, nil
+}
+
+// ApplyStagedRestore restores the state staged by ImportState under the home
+// directory, if any, and returns whether the state is restored. It must be
+// called before the config is loaded and the database is opened. The files
+// replaced by the restore are kept with the .pre-restore suffix, and a
+// partially staged state is discarded
+func ApplyStagedRestore(homePath string) (bool, error) {
+	restoreDir := filepath.Join(homePath, StateRestoreDirName)
+	if !util.FileExists(restoreDir) {
+		return false, nil
+	}
+
+	manifestFile := filepath.Join(restoreDir, stateManifestFile)
+	if !util.FileExists(manifestFile) {
+		return false, os.RemoveAll(restoreDir)
+	}
+	manifestBytes, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the staged manifest: %w", err)
+	}
+	var manifest stateManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return false, fmt.Errorf("%w: invalid staged manifest: %w", ErrInvalidStateArchive, err)
+	}
+
+	// verify all the staged files before replacing anything
+	files := make(map[string][]byte, len(manifest.Files))
+	for name, hash := range manifest.Files {
+		if !isValidStateFileName(name) {
+			return false, fmt.Errorf("%w: unexpected file %s", ErrInvalidStateArchive, name)
+		}
+		bz, err := os.ReadFile(filepath.Join(restoreDir, filepath.FromSlash(name)))
+		if err != nil {
+			return false, fmt.Errorf("failed to read the staged %s: %w", name, err)
+		}
+		if sha256Hex(bz) != hash {
+			return false, fmt.Errorf("%w: the staged %s is corrupted", ErrInvalidStateArchive, name)
+		}
+		files[name] = bz
+	}
+	if _, ok := files[stateConfigFile]; !ok {
+		return false, fmt.Errorf("%w: missing staged config", ErrInvalidStateArchive)
+	}
+	if _, ok := files[stateDBFile]; !ok {
+		return false, fmt.Errorf("%w: missing staged database", ErrInvalidStateArchive)
+	}
+
+	// the config is restored first as it locates the database and the keys
+	if err := replaceFile(fpcfg.ConfigFile(homePath), files[stateConfigFile]); err != nil {
+		return false, err
+	}
+	cfg, err := fpcfg.LoadConfig(homePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to load the restored config: %w", err)
+	}
+
+	dbFile := filepath.Join(cfg.DatabaseConfig.DBPath, cfg.DatabaseConfig.DBFileName)
+	if err := replaceFile(dbFile, files[stateDBFile]); err != nil {
+		return false, err
+	}
+
+	if manifest.KeyringBackend != "" {
+		keyringDir, err := keyringDirOf(cfg.BabylonConfig.KeyDirectory, manifest.KeyringBackend)
+		if err != nil {
+			return false, err
+		}
+		for name, bz := range files {
+			if path.Dir(name) != stateKeyringDir {
+				continue
+			}
+			if err := replaceFile(filepath.Join(keyringDir, path.Base(name)), bz); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if err := os.RemoveAll(restoreDir); err != nil {
+		return false, fmt.Errorf("failed to clean up the restore directory: %w", err)
+	}
+
+	return true, nil
+}
+
+// keyringDirOf returns the directory of the keyring under the key directory,
+// which is only known for the backends storing the keys in files
+func keyringDirOf(keyDir, backend string) (string, error) {
+	switch backend {
+	case keyring.BackendTest, keyring.BackendFile:
+		return filepath.Join(keyDir, "keyring-"+backend), nil
+	default:
+		return "", fmt.Errorf("the keys of the %s keyring backend cannot be exported or restored", backend)
+	}
+}
+
+// isValidStateFileName checks the name of a file in a state archive, which
+// also prevents the archive from writing outside the restore directory
+func isValidStateFileName(name string) bool {
+	switch name {
+	case stateConfigFile, stateDBFile:
+		return true
+	}
+	dir, base := path.Split(name)
+
+	return dir == stateKeyringDir+"/" && base != "" && base != "." && base != ".."
+}
+
+// replaceFile writes bz to the file, keeping the replaced file if any
+func replaceFile(file string, bz []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("failed to create the directory of %s: %w", file, err)
+	}
+	if util.FileExists(file) {
+		if err := os.Rename(file, file+preRestoreSuffix); err != nil {
+			return fmt.Errorf("failed to keep the replaced %s: %w", file, err)
+		}
+	}
+	if err := os.WriteFile(file, bz, 0600); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// writeStateArchive writes the manifest and the files into a gzipped tarball
+func writeStateArchive(manifest *stateManifest, files map[string][]byte) ([]byte, error) {
+	manifestBytes, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	writeFile := func(name string, bz []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(bz)),
+			ModTime: time.Unix(manifest.CreatedAt, 0),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(bz)
+		return err
+	}
+
+	if err := writeFile(stateManifestFile, manifestBytes); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+	for _, name := range names {
+		if err := writeFile(name, files[name]); err != nil {
+			return nil, fmt.Errorf("failed to write the archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+	if err := gw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write the archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readStateArchive reads the gzipped tarball written by writeStateArchive
+// and verifies the files against the manifest
+func readStateArchive(archive []byte) (*stateManifest, map[string][]byte, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidStateArchive, err)
+	}
+	tr := tar.NewReader(gr)
+
+	var manifest *stateManifest
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidStateArchive, err)
+		}
+		bz, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidStateArchive, err)
+		}
+
+		if hdr.Name == stateManifestFile {
+			manifest = &stateManifest{}
+			if err := json.Unmarshal(bz, manifest); err != nil {
+				return nil, nil, fmt.Errorf("%w: invalid manifest: %w", ErrInvalidStateArchive, err)
+			}
+			continue
+		}
+		if !isValidStateFileName(hdr.Name) {
+			return nil, nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidStateArchive, hdr.Name)
+		}
+		files[hdr.Name] = bz
+	}
+
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("%w: missing manifest", ErrInvalidStateArchive)
+	}
+	if manifest.Version != stateArchiveVersion {
+		return nil, nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidStateArchive, manifest.Version)
+	}
+	if len(files) != len(manifest.Files) {
+		return nil, nil, fmt.Errorf("%w: the files do not match the manifest", ErrInvalidStateArchive)
+	}
+	for name, hash := range manifest.Files {
+		bz, ok := files[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: missing file %s", ErrInvalidStateArchive, name)
+		}
+		if sha256Hex(bz) != hash {
+			return nil, nil, fmt.Errorf("%w: the file %s is corrupted", ErrInvalidStateArchive, name)
+		}
+	}
+	if _, ok := files[stateConfigFile]; !ok {
+		return nil, nil, fmt.Errorf("%w: missing config", ErrInvalidStateArchive)
+	}
+	if _, ok := files[stateDBFile]; !ok {
+		return nil, nil, fmt.Errorf("%w: missing database", ErrInvalidStateArchive)
+	}
+
+	return manifest, files, nil
+}
+
+// encryptStateArchive encrypts the archive with AES-256-GCM under a key
+// derived from the passphrase with argon2id. The encrypted archive is
+// the magic, the salt, and the nonce followed by the ciphertext
+func encryptStateArchive(archive []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, stateArchiveSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newStateArchiveAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(stateArchiveMagic)+len(salt)+len(nonce)+len(archive)+aead.Overhead())
+	out = append(out, stateArchiveMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, archive, []byte(stateArchiveMagic)), nil
+}
+
+// decryptStateArchive decrypts the archive encrypted by encryptStateArchive,
+// which also authenticates it
+func decryptStateArchive(encrypted []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(encrypted, []byte(stateArchiveMagic)) {
+		return nil, fmt.Errorf("%w: not a state archive", ErrInvalidStateArchive)
+	}
+	encrypted = encrypted[len(stateArchiveMagic):]
+	if len(encrypted) < stateArchiveSaltLen {
+		return nil, fmt.Errorf("%w: truncated archive", ErrInvalidStateArchive)
+	}
+	salt, encrypted := encrypted[:stateArchiveSaltLen], encrypted[stateArchiveSaltLen:]
+
+	aead, err := newStateArchiveAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: truncated archive", ErrInvalidStateArchive)
+	}
+	nonce, ciphertext := encrypted[:aead.NonceSize()], encrypted[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(stateArchiveMagic))
+	if err != nil {
+		return nil, ErrInvalidStateArchive
+	}
+
+	return plain, nil
+}
+
+func newStateArchiveAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), salt,
+		stateArchiveArgon2Time, stateArchiveArgon2MemoryKiB, stateArchiveArgon2Threads, stateArchiveKeyLen)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func sha256Hex(bz []byte) string {
+	h := sha256.Sum256(bz)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/util"
+)
+
+func TestStateArchive(t *testing.T) {
+	passphrase := "archive passphrase"
+	files := map[string][]byte{
+		stateConfigFile: []byte("config"),
+		stateDBFile:     []byte("database"),
+	}
+	newManifest := func(files map[string][]byte) *stateManifest {
+		manifest := &stateManifest{Version: stateArchiveVersion, ChainID: "chain-test", Files: make(map[string]string)}
+		for name, bz := range files {
+			manifest.Files[name] = sha256Hex(bz)
+		}
+		return manifest
+	}
+
+	t.Run("an archive is read back after its encryption", func(t *testing.T) {
+		archive, err := writeStateArchive(newManifest(files), files)
+		require.NoError(t, err)
+		encrypted, err := encryptStateArchive(archive, passphrase)
+		require.NoError(t, err)
+
+		_, err = decryptStateArchive(encrypted, "wrong passphrase")
+		require.ErrorIs(t, err, ErrInvalidStateArchive)
+		encrypted[len(encrypted)-1] ^= 1
+		_, err = decryptStateArchive(encrypted, passphrase)
+		require.ErrorIs(t, err, ErrInvalidStateArchive)
+		encrypted[len(encrypted)-1] ^= 1
+
+		plain, err := decryptStateArchive(encrypted, passphrase)
+		require.NoError(t, err)
+		manifest, readFiles, err := readStateArchive(plain)
+		require.NoError(t, err)
+		require.Equal(t, "chain-test", manifest.ChainID)
+		require.Equal(t, files, readFiles)
+	})
+
+	t.Run("a file not matching the manifest is rejected", func(t *testing.T) {
+		manifest := newManifest(files)
+		manifest.Files[stateDBFile] = sha256Hex([]byte("other database"))
+		archive, err := writeStateArchive(manifest, files)
+		require.NoError(t, err)
+
+		_, _, err = readStateArchive(archive)
+		require.ErrorIs(t, err, ErrInvalidStateArchive)
+	})
+
+	t.Run("a file outside the restore directory is rejected", func(t *testing.T) {
+		evilFiles := map[string][]byte{
+			stateConfigFile:    []byte("config"),
+			stateDBFile:        []byte("database"),
+			"keyring/../../fp": []byte("evil"),
+		}
+		archive, err := writeStateArchive(newManifest(evilFiles), evilFiles)
+		require.NoError(t, err)
+
+		_, _, err = readStateArchive(archive)
+		require.ErrorIs(t, err, ErrInvalidStateArchive)
+	})
+}
+
+func TestIsValidStateFileName(t *testing.T) {
+	require.True(t, isValidStateFileName(stateConfigFile))
+	require.True(t, isValidStateFileName(stateDBFile))
+	require.True(t, isValidStateFileName("keyring/key.info"))
+	require.False(t, isValidStateFileName("keyring/"))
+	require.False(t, isValidStateFileName("keyring/.."))
+	require.False(t, isValidStateFileName("keyring/sub/key.info"))
+	require.False(t, isValidStateFileName("../fpd.conf"))
+}
+
+func TestExportAndRestoreState(t *testing.T) {
+	passphrase := "archive passphrase"
+	homePath := t.TempDir()
+	defaultCfg := fpcfg.DefaultConfigWithHome(homePath)
+	require.NoError(t, fpcfg.WriteConfigFile(&defaultCfg, homePath))
+	cfg, err := fpcfg.LoadConfig(homePath)
+	require.NoError(t, err)
+
+	db, err := cfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+	exportedPk := createTestFinalityProvider(t, s, cfg.BabylonConfig.ChainID)
+	app := &FinalityProviderApp{config: cfg, fps: s, logger: zap.NewNop()}
+
+	_, err = app.ExportState("", false)
+	require.Error(t, err)
+	archive, err := app.ExportState(passphrase, false)
+	require.NoError(t, err)
+
+	// the finality provider created after the export is rolled back
+	laterPk := createTestFinalityProvider(t, s, cfg.BabylonConfig.ChainID)
+
+	_, err = app.ImportState(archive, passphrase, sha256Hex([]byte("other archive")))
+	require.ErrorIs(t, err, ErrInvalidStateArchive)
+	_, err = app.ImportState(archive, "wrong passphrase", "")
+	require.ErrorIs(t, err, ErrInvalidStateArchive)
+	res, err := app.ImportState(archive, passphrase, sha256Hex(archive))
+	require.NoError(t, err)
+	require.Equal(t, []string{stateConfigFile, stateDBFile}, res.Files)
+	require.Equal(t, cfg.BabylonConfig.ChainID, res.ChainId)
+
+	// the staged state is restored on the next start
+	require.NoError(t, db.Close())
+	restored, err := ApplyStagedRestore(homePath)
+	require.NoError(t, err)
+	require.True(t, restored)
+	require.False(t, util.FileExists(filepath.Join(homePath, StateRestoreDirName)))
+	dbFile := filepath.Join(cfg.DatabaseConfig.DBPath, cfg.DatabaseConfig.DBFileName)
+	require.True(t, util.FileExists(dbFile+preRestoreSuffix))
+
+	db, err = cfg.DatabaseConfig.GetDbBackend()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	s, err = store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+	_, err = s.GetFinalityProvider(exportedPk.MustToBTCPK())
+	require.NoError(t, err)
+	_, err = s.GetFinalityProvider(laterPk.MustToBTCPK())
+	require.Error(t, err)
+
+	// nothing is restored once the staged state is applied
+	restored, err = ApplyStagedRestore(homePath)
+	require.NoError(t, err)
+	require.False(t, restored)
+
+	// a partially staged state is discarded
+	restoreDir := filepath.Join(homePath, StateRestoreDirName)
+	require.NoError(t, os.MkdirAll(restoreDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(restoreDir, stateDBFile), []byte("database"), 0600))
+	restored, err = ApplyStagedRestore(homePath)
+	require.NoError(t, err)
+	require.False(t, restored)
+	require.False(t, util.FileExists(restoreDir))
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/version"
+)
+
+// StateFileName is the name of the state file of the daemon in the data
+// directory
+const StateFileName = "fpd-state.json"
+
+const (
+	daemonStatusRunning = "running"
+	daemonStatusStopped = "stopped"
+)
+
+// DaemonState is the state of the daemon written to the state file for the
+// external tools
+type DaemonState struct {
+	Version           string                   `json:"version"`
+	Pid               int                      `json:"pid"`
+	Status            string                   `json:"status"`
+	Standby           bool                     `json:"standby"`
+	StartedAt         time.Time                `json:"started_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+	FinalityProviders []*FinalityProviderState `json:"finality_providers"`
+}
+
+// FinalityProviderState is the state of a finality provider in the state
+// file
+type FinalityProviderState struct {
+	BtcPkHex            string `json:"btc_pk_hex"`
+	ChainID             string `json:"chain_id"`
+	Status              string `json:"status"`
+	IsRunning           bool   `json:"is_running"`
+	LastVotedHeight     uint64 `json:"last_voted_height"`
+	LastProcessedHeight uint64 `json:"last_processed_height"`
+	FinalizedHeight     uint64 `json:"finalized_height"`
+}
+
+// stateFileLoop periodically writes the state of the daemon to the state
+// file, and the stopped status once the daemon is stopping
+func (app *FinalityProviderApp) stateFileLoop() {
+	defer app.wg.Done()
+
+	if app.config.StateFileInterval == 0 {
+		app.logger.Info("the state file is disabled")
+		return
+	}
+
+	startedAt := time.Now()
+	stateFile := filepath.Join(app.config.DatabaseConfig.DBPath, StateFileName)
+	write := func(status string) {
+		if err := app.writeStateFile(stateFile, status, startedAt); err != nil {
+			app.logger.Warn("failed to write the state file", zap.String("file", stateFile), zap.Error(err))
+		}
+	}
+
+	write(daemonStatusRunning)
+
+	ticker := time.NewTicker(app.config.StateFileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			write(daemonStatusRunning)
+		case <-app.quit:
+			write(daemonStatusStopped)
+			return
+		}
+	}
+}
+
+// writeStateFile writes the state into a temporary file which then replaces
+// the state file, so that the readers never see a partial state
+func (app *FinalityProviderApp) writeStateFile(stateFile, status string, startedAt time.Time) error {
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return err
+	}
+
+	state := &DaemonState{
+		Version:           version.Version(),
+		Pid:               os.Getpid(),
+		Status:            status,
+		Standby:           app.IsStandby(),
+		StartedAt:         startedAt,
+		UpdatedAt:         time.Now(),
+		FinalityProviders: make([]*FinalityProviderState, 0, len(storedFps)),
+	}
+	for _, fp := range storedFps {
+		state.FinalityProviders = append(state.FinalityProviders, &FinalityProviderState{
+			BtcPkHex:            fp.GetBIP340BTCPK().MarshalHex(),
+			ChainID:             fp.ChainID,
+			Status:              fp.Status.String(),
+			IsRunning:           status == daemonStatusRunning && app.fpManager.IsFinalityProviderRunning(fp.GetBIP340BTCPK()),
+			LastVotedHeight:     fp.LastVotedHeight,
+			LastProcessedHeight: fp.LastProcessedHeight,
+			FinalizedHeight:     fp.FinalizedHeight,
+		})
+	}
+
+	bz, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpFile := stateFile + ".tmp"
+	if err := os.WriteFile(tmpFile, bz, 0644); err != nil {
+		return fmt.Errorf("failed to write the state: %w", err)
+	}
+
+	return os.Rename(tmpFile, stateFile)
+}
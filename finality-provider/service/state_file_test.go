@@ -0,0 +1,120 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+func readStateFile(t *testing.T, stateFile string) *DaemonState {
+	bz, err := os.ReadFile(stateFile)
+	require.NoError(t, err)
+	var state DaemonState
+	require.NoError(t, json.Unmarshal(bz, &state))
+
+	return &state
+}
+
+func newStateFileTestApp(t *testing.T, interval time.Duration) (*FinalityProviderApp, *store.FinalityProviderStore) {
+	dbCfg := fpcfg.DefaultDBConfigWithHomePath(t.TempDir())
+	db, err := dbCfg.GetDbBackend()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+
+	cfg := &fpcfg.Config{DatabaseConfig: dbCfg, StateFileInterval: interval}
+	return &FinalityProviderApp{
+		config:    cfg,
+		fps:       s,
+		fpManager: &FinalityProviderManager{fps: s, config: cfg, fpis: make(map[string]*FinalityProviderInstance)},
+		quit:      make(chan struct{}),
+		logger:    zap.NewNop(),
+	}, s
+}
+
+func TestWriteStateFile(t *testing.T) {
+	app, s := newStateFileTestApp(t, time.Hour)
+	runningPk := createTestFinalityProvider(t, s, "chain-test")
+	stoppedPk := createTestFinalityProvider(t, s, "chain-test")
+	require.NoError(t, s.SetFpLastVotedHeight(runningPk.MustToBTCPK(), 10))
+	app.fpManager.fpis[runningPk.MarshalHex()] = &FinalityProviderInstance{}
+
+	stateFile := filepath.Join(t.TempDir(), StateFileName)
+	startedAt := time.Now().Add(-time.Minute)
+
+	t.Run("the running finality providers are reported", func(t *testing.T) {
+		require.NoError(t, app.writeStateFile(stateFile, daemonStatusRunning, startedAt))
+		_, err := os.Stat(stateFile + ".tmp")
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		state := readStateFile(t, stateFile)
+		require.Equal(t, daemonStatusRunning, state.Status)
+		require.Equal(t, os.Getpid(), state.Pid)
+		require.False(t, state.Standby)
+		require.True(t, startedAt.Equal(state.StartedAt))
+		require.Len(t, state.FinalityProviders, 2)
+		for _, fp := range state.FinalityProviders {
+			require.Equal(t, "chain-test", fp.ChainID)
+			require.Equal(t, proto.FinalityProviderStatus_CREATED.String(), fp.Status)
+			switch fp.BtcPkHex {
+			case runningPk.MarshalHex():
+				require.True(t, fp.IsRunning)
+				require.Equal(t, uint64(10), fp.LastVotedHeight)
+			case stoppedPk.MarshalHex():
+				require.False(t, fp.IsRunning)
+			default:
+				t.Fatalf("unexpected finality provider %s", fp.BtcPkHex)
+			}
+		}
+	})
+
+	t.Run("no finality provider is running once the daemon is stopped", func(t *testing.T) {
+		require.NoError(t, app.writeStateFile(stateFile, daemonStatusStopped, startedAt))
+
+		state := readStateFile(t, stateFile)
+		require.Equal(t, daemonStatusStopped, state.Status)
+		for _, fp := range state.FinalityProviders {
+			require.False(t, fp.IsRunning)
+		}
+	})
+}
+
+func TestStateFileLoop(t *testing.T) {
+	t.Run("the state file is not written if disabled", func(t *testing.T) {
+		app, _ := newStateFileTestApp(t, 0)
+
+		app.wg.Add(1)
+		app.stateFileLoop()
+
+		_, err := os.Stat(filepath.Join(app.config.DatabaseConfig.DBPath, StateFileName))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("the stopped status is written once the daemon stops", func(t *testing.T) {
+		app, _ := newStateFileTestApp(t, time.Hour)
+		stateFile := filepath.Join(app.config.DatabaseConfig.DBPath, StateFileName)
+
+		app.wg.Add(1)
+		go app.stateFileLoop()
+
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(stateFile)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, daemonStatusRunning, readStateFile(t, stateFile).Status)
+
+		close(app.quit)
+		app.wg.Wait()
+		require.Equal(t, daemonStatusStopped, readStateFile(t, stateFile).Status)
+	})
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	dc "github.com/babylonchain/finality-provider/finality-provider/service/client"
+)
+
+const (
+	// syncStateInterval is the interval between each update of the state
+	// streamed to a standby daemon
+	syncStateInterval = time.Second
+	// standbyReconnectInterval is the interval between each attempt of a
+	// standby daemon to reconnect to the active daemon
+	standbyReconnectInterval = 5 * time.Second
+	// maxSyncedBlocksPerUpdate is the maximum number of the signed blocks in
+	// an update of the state, so that the replay of the whole history to a
+	// new standby daemon is split into messages within the gRPC size limit
+	maxSyncedBlocksPerUpdate = 1000
+)
+
+// StreamState sends the state of the finality providers to a standby daemon
+// until the context is done. The first updates replay all the signed blocks
+// in pages sent back to back, and each following update only contains those
+// signed since the previous one
+func (app *FinalityProviderApp) StreamState(ctx context.Context, send func(*proto.SyncStateResponse) error) error {
+	// the next signed height to send of each finality provider
+	nextHeights := make(map[string]uint64)
+
+	ticker := time.NewTicker(syncStateInterval)
+	defer ticker.Stop()
+
+	for {
+		update, more, err := app.stateUpdate(nextHeights, maxSyncedBlocksPerUpdate)
+		if err != nil {
+			return err
+		}
+		if err := send(update); err != nil {
+			return err
+		}
+
+		if more {
+			// the next page is sent right away unless the stream is over
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-app.quit:
+				return ErrFinalityProviderShutDown
+			default:
+			}
+			continue
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-app.quit:
+			return ErrFinalityProviderShutDown
+		}
+	}
+}
+
+// stateUpdate returns the update of the state with at most maxBlocks blocks
+// signed since the given next heights, which are moved past the returned
+// blocks, and whether more signed blocks are left to send
+func (app *FinalityProviderApp) stateUpdate(nextHeights map[string]uint64, maxBlocks uint64) (*proto.SyncStateResponse, bool, error) {
+	fps, err := app.fps.GetAllFinalityProviderRecords()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get the stored finality providers: %w", err)
+	}
+
+	update := &proto.SyncStateResponse{FinalityProviders: fps}
+	for _, fp := range fps {
+		remaining := maxBlocks - uint64(len(update.SignedBlocks))
+		if remaining == 0 {
+			return update, true, nil
+		}
+
+		pkHex := hex.EncodeToString(fp.BtcPk)
+		signedBlocks, err := app.fps.GetSignedBlocksFrom(fp.BtcPk, nextHeights[pkHex], remaining)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get the signed blocks of %s: %w", pkHex, err)
+		}
+		if len(signedBlocks) == 0 {
+			continue
+		}
+
+		update.SignedBlocks = append(update.SignedBlocks, signedBlocks...)
+		nextHeights[pkHex] = signedBlocks[len(signedBlocks)-1].Height + 1
+	}
+
+	// the page may be full with no block left, in which case the next
+	// update is empty
+	return update, uint64(len(update.SignedBlocks)) == maxBlocks, nil
+}
+
+// IsStandby returns true if the daemon replicates the state of an active
+// daemon instead of running the finality providers
+func (app *FinalityProviderApp) IsStandby() bool {
+	return app.config.StandbyOf != ""
+}
+
+// standbySyncLoop replicates the state of the active daemon until the app
+// is stopped, reconnecting whenever the stream breaks
+func (app *FinalityProviderApp) standbySyncLoop() {
+	defer app.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-app.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		if err := app.syncStateFromActive(ctx); err != nil && ctx.Err() == nil {
+			app.logger.Warn("the state sync with the active daemon is interrupted",
+				zap.String("active", app.config.StandbyOf), zap.Error(err))
+		}
+
+		select {
+		case <-time.After(standbyReconnectInterval):
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+func (app *FinalityProviderApp) syncStateFromActive(ctx context.Context) error {
+	client, cleanUp, err := dc.NewFinalityProviderServiceGRpcClient(app.config.StandbyOf, app.config.GRPCConfig.DialOptions()...)
+	if err != nil {
+		return err
+	}
+	defer cleanUp()
+
+	stream, err := client.SyncState(ctx)
+	if err != nil {
+		return err
+	}
+
+	app.logger.Info("syncing the state from the active daemon", zap.String("active", app.config.StandbyOf))
+
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if err := app.applyStateUpdate(update); err != nil {
+			return err
+		}
+	}
+}
+
+func (app *FinalityProviderApp) applyStateUpdate(update *proto.SyncStateResponse) error {
+	for _, fp := range update.FinalityProviders {
+		if err := app.fps.MergeFinalityProviderRecord(fp); err != nil {
+			return fmt.Errorf("failed to save the finality provider %s: %w", hex.EncodeToString(fp.BtcPk), err)
+		}
+	}
+
+	for _, b := range update.SignedBlocks {
+		if err := app.fps.SaveSignedBlock(b); err != nil {
+			return fmt.Errorf("failed to save the block signed by %s at height %d: %w",
+				hex.EncodeToString(b.BtcPk), b.Height, err)
+		}
+	}
+
+	if len(update.SignedBlocks) > 0 {
+		app.logger.Debug("replicated the signed blocks from the active daemon",
+			zap.Int("count", len(update.SignedBlocks)))
+	}
+
+	return nil
+}
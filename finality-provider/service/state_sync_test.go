@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/babylonchain/babylon/testutil/datagen"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzStateUpdatePaging tests that the replay of the signed blocks to a
+// standby daemon is split into pages of a bounded number of blocks, which
+// together replicate all the signed blocks
+func FuzzStateUpdatePaging(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		randomStartingHeight := uint64(r.Int63n(100) + 1)
+		currentHeight := randomStartingHeight + uint64(r.Int63n(10)+2)
+		mockClientController := testutil.PrepareMockedClientController(t, r, randomStartingHeight, currentHeight)
+		activeApp, fpIns, cleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer cleanUp()
+		standbyApp, _, standbyCleanUp := startFinalityProviderAppWithRegisteredFp(t, r, mockClientController, randomStartingHeight)
+		defer standbyCleanUp()
+
+		numBlocks := uint64(r.Intn(20) + 1)
+		maxBlocks := uint64(r.Intn(5) + 1)
+		hashes := make(map[uint64][]byte)
+		for h := uint64(1); h <= numBlocks; h++ {
+			hashes[h] = datagen.GenRandomByteArray(r, 32)
+			err := activeApp.GetFinalityProviderStore().SaveSignedBlockHash(fpIns.GetBtcPk(), h, hashes[h])
+			require.NoError(t, err)
+		}
+
+		nextHeights := make(map[string]uint64)
+		var replayed []*proto.SignedBlock
+		for {
+			update, more, err := activeApp.StateUpdate(nextHeights, maxBlocks)
+			require.NoError(t, err)
+			require.LessOrEqual(t, uint64(len(update.SignedBlocks)), maxBlocks)
+			require.NotEmpty(t, update.FinalityProviders)
+			err = standbyApp.ApplyStateUpdate(update)
+			require.NoError(t, err)
+			replayed = append(replayed, update.SignedBlocks...)
+			if !more {
+				break
+			}
+		}
+
+		require.Len(t, replayed, int(numBlocks))
+		for i, b := range replayed {
+			require.Equal(t, uint64(i+1), b.Height)
+		}
+		for h, hash := range hashes {
+			signedHash, err := standbyApp.GetFinalityProviderStore().GetSignedBlockHash(fpIns.GetBtcPk(), h)
+			require.NoError(t, err)
+			require.Equal(t, hash, signedHash)
+		}
+
+		// the blocks signed after the replay are sent in the next update
+		nextHash := datagen.GenRandomByteArray(r, 32)
+		err := activeApp.GetFinalityProviderStore().SaveSignedBlockHash(fpIns.GetBtcPk(), numBlocks+1, nextHash)
+		require.NoError(t, err)
+		update, more, err := activeApp.StateUpdate(nextHeights, maxBlocks)
+		require.NoError(t, err)
+		require.Equal(t, maxBlocks == 1, more)
+		require.Len(t, update.SignedBlocks, 1)
+		require.Equal(t, numBlocks+1, update.SignedBlocks[0].Height)
+	})
+}
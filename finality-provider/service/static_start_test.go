@@ -0,0 +1,107 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+)
+
+func TestStaticStartHeight(t *testing.T) {
+	testCases := []struct {
+		name            string
+		configured      uint64
+		override        uint64
+		lastVoted       uint64
+		activatedHeight uint64
+		autoCorrect     bool
+		expectedHeight  uint64
+		expectCorrected bool
+		expectErr       error
+	}{
+		{
+			name:            "a start height above the lowest useful height is kept",
+			configured:      100,
+			lastVoted:       50,
+			activatedHeight: 10,
+			expectedHeight:  100,
+		},
+		{
+			name:            "the start height of the finality provider overrides the configured one",
+			configured:      20,
+			override:        100,
+			lastVoted:       50,
+			activatedHeight: 10,
+			expectedHeight:  100,
+		},
+		{
+			name:            "a start height below the last voted height is rejected",
+			configured:      20,
+			lastVoted:       50,
+			activatedHeight: 10,
+			expectErr:       ErrStaticStartHeightTooLow,
+		},
+		{
+			name:            "a start height below the activation height of the chain is rejected",
+			configured:      20,
+			activatedHeight: 100,
+			expectErr:       ErrStaticStartHeightTooLow,
+		},
+		{
+			name:            "a start height below the last voted height is raised",
+			configured:      20,
+			lastVoted:       50,
+			activatedHeight: 10,
+			autoCorrect:     true,
+			expectedHeight:  50,
+			expectCorrected: true,
+		},
+		{
+			name:            "a start height below the activation height of the chain is raised",
+			configured:      20,
+			lastVoted:       50,
+			activatedHeight: 100,
+			autoCorrect:     true,
+			expectedHeight:  99,
+			expectCorrected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			cc := mocks.NewMockClientController(ctl)
+			cc.EXPECT().QueryActivatedHeight().Return(tc.activatedHeight, nil).Times(1)
+
+			sk, err := btcec.NewPrivateKey()
+			require.NoError(t, err)
+			fp := &FinalityProviderInstance{
+				cfg: &fpcfg.Config{PollerConfig: &fpcfg.ChainPollerConfig{
+					StaticChainScanningStartHeight: tc.configured,
+					AutoCorrectStartHeight:         tc.autoCorrect,
+				}},
+				fpState:     NewFpState(&store.StoredFinalityProvider{BtcPk: sk.PubKey(), LastVotedHeight: tc.lastVoted}, nil),
+				chainParams: newChainParamsCache(cc, 0, zap.NewNop()),
+				logger:      zap.NewNop(),
+			}
+			if tc.override > 0 {
+				fp.cfg.PollerConfig.StaticStartHeights = map[string]uint64{fp.GetBtcPkHex(): tc.override}
+			}
+
+			height, corrected, err := fp.staticStartHeight()
+			if tc.expectErr != nil {
+				require.ErrorIs(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedHeight, height)
+			require.Equal(t, tc.expectCorrected, corrected)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// QueryStatus returns the tip of the default consumer chain and the live
+// status of all the finality providers of the daemon, each queried on its own
+// chain. The consumer chains are queried on a best-effort basis, so the fields
+// they fail to return are left empty instead of failing the whole status
+func (app *FinalityProviderApp) QueryStatus() (*proto.QueryStatusResponse, error) {
+	storedFps, err := app.fps.GetAllStoredFinalityProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	tips := app.fpManager.newChainTips()
+	var tipHeight uint64
+	tip, err := tips.of(app.config.BabylonConfig.ChainID)
+	if err != nil {
+		app.logger.Debug("failed to query the tip of the consumer chain", zap.Error(err))
+	} else {
+		tipHeight = tip.Height
+	}
+
+	statuses := make([]*proto.FinalityProviderStatusInfo, 0, len(storedFps))
+	for _, sfp := range storedFps {
+		fpInfo := sfp.ToFinalityProviderInfo()
+		fpInfo.IsRunning = app.fpManager.IsFinalityProviderRunning(sfp.GetBIP340BTCPK())
+
+		status := &proto.FinalityProviderStatusInfo{
+			FinalityProvider:    fpInfo,
+			LastProcessedHeight: sfp.LastProcessedHeight,
+		}
+
+		pendingSigs, err := app.fps.GetPendingFinalitySigs(sfp.BtcPk)
+		if err != nil {
+			return nil, err
+		}
+		status.PendingFinalitySigs = pendingSigs
+
+		cc := app.fpManager.ccOf(sfp.ChainID)
+		lastCommittedHeight, err := app.queryLastCommittedHeight(cc, sfp.BtcPk)
+		if err != nil {
+			app.logger.Debug("failed to query the last committed height",
+				zap.String("pk", fpInfo.BtcPkHex), zap.Error(err))
+		} else {
+			status.LastCommittedRandHeight = lastCommittedHeight
+			// the runway is left empty if the tip of the chain is unknown
+			if fpTip, err := tips.of(sfp.ChainID); err == nil && lastCommittedHeight > fpTip.Height {
+				status.RandRunway = lastCommittedHeight - fpTip.Height
+			}
+		}
+
+		addr, err := sdk.Bech32ifyAddressBytes(app.config.BabylonConfig.AccountPrefix, sfp.ChainPk.Address())
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain key of the finality provider %s: %w", fpInfo.BtcPkHex, err)
+		}
+		balance, err := cc.QueryBalance(addr)
+		if err != nil {
+			app.logger.Debug("failed to query the balance",
+				zap.String("pk", fpInfo.BtcPkHex), zap.String("address", addr), zap.Error(err))
+		} else {
+			status.Balance = balance.String()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return &proto.QueryStatusResponse{
+		TipHeight:         tipHeight,
+		FinalityProviders: statuses,
+	}, nil
+}
+
+// queryLastCommittedHeight returns the last height with public randomness
+// committed by the finality provider, or zero if nothing is committed. Unlike
+// the instances, it is not retried as the status is polled
+func (app *FinalityProviderApp) queryLastCommittedHeight(cc clientcontroller.ClientController, fpPk *btcec.PublicKey) (uint64, error) {
+	pubRandCommitMap, err := cc.QueryLastCommittedPublicRand(fpPk, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	var lastCommittedHeight uint64
+	for startHeight, resp := range pubRandCommitMap {
+		lastCommittedHeight = startHeight + resp.NumPubRand - 1
+	}
+
+	return lastCommittedHeight, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	finalitytypes "github.com/babylonchain/babylon/x/finality/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil/mocks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestQueryStatus(t *testing.T) {
+	chainID := "chain-test"
+	db, err := fpcfg.DefaultDBConfigWithHomePath(t.TempDir()).GetDbBackend()
+	require.NoError(t, err)
+	defer db.Close()
+	s, err := store.NewFinalityProviderStore(db)
+	require.NoError(t, err)
+	fpPk := createTestFinalityProvider(t, s, chainID)
+	storedFp, err := s.GetFinalityProvider(fpPk.MustToBTCPK())
+	require.NoError(t, err)
+	addr, err := sdk.Bech32ifyAddressBytes("bbn", storedFp.ChainPk.Address())
+	require.NoError(t, err)
+
+	newApp := func(cc *mocks.MockClientController) *FinalityProviderApp {
+		cfg := &fpcfg.Config{BabylonConfig: &fpcfg.BBNConfig{ChainID: chainID, AccountPrefix: "bbn"}}
+		return &FinalityProviderApp{
+			config:    cfg,
+			fps:       s,
+			logger:    zap.NewNop(),
+			fpManager: &FinalityProviderManager{fps: s, config: cfg, cc: cc, fpis: make(map[string]*FinalityProviderInstance)},
+		}
+	}
+
+	t.Run("the live status of the finality providers is returned", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		// the tip is queried once for all the finality providers of a chain
+		cc.EXPECT().QueryBestBlock().Return(&types.BlockInfo{Height: 120}, nil).Times(1)
+		cc.EXPECT().QueryLastCommittedPublicRand(fpPk.MustToBTCPK(), uint64(1)).
+			Return(map[uint64]*finalitytypes.PubRandCommitResponse{100: {NumPubRand: 50}}, nil).Times(1)
+		cc.EXPECT().QueryBalance(addr).Return(sdk.NewCoins(sdk.NewInt64Coin("ubbn", 1000)), nil).Times(1)
+
+		res, err := newApp(cc).QueryStatus()
+		require.NoError(t, err)
+		require.Equal(t, uint64(120), res.TipHeight)
+		require.Len(t, res.FinalityProviders, 1)
+		status := res.FinalityProviders[0]
+		require.Equal(t, fpPk.MarshalHex(), status.FinalityProvider.BtcPkHex)
+		require.False(t, status.FinalityProvider.IsRunning)
+		require.Equal(t, uint64(149), status.LastCommittedRandHeight)
+		require.Equal(t, uint64(29), status.RandRunway)
+		require.Equal(t, "1000ubbn", status.Balance)
+	})
+
+	t.Run("the fields the chain fails to return are left empty", func(t *testing.T) {
+		ctl := gomock.NewController(t)
+		cc := mocks.NewMockClientController(ctl)
+		queryErr := errors.New("the consumer chain is unreachable")
+		cc.EXPECT().QueryBestBlock().Return(nil, queryErr).Times(1)
+		cc.EXPECT().QueryLastCommittedPublicRand(gomock.Any(), gomock.Any()).
+			Return(map[uint64]*finalitytypes.PubRandCommitResponse{100: {NumPubRand: 50}}, nil).Times(1)
+		cc.EXPECT().QueryBalance(addr).Return(nil, queryErr).Times(1)
+
+		res, err := newApp(cc).QueryStatus()
+		require.NoError(t, err)
+		require.Zero(t, res.TipHeight)
+		require.Len(t, res.FinalityProviders, 1)
+		status := res.FinalityProviders[0]
+		require.Equal(t, uint64(149), status.LastCommittedRandHeight)
+		// the runway is unknown without the tip
+		require.Zero(t, status.RandRunway)
+		require.Empty(t, status.Balance)
+	})
+}
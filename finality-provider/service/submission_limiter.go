@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// submissionLimiter is shared by the finality-provider instances of a manager
+// to cap how many of them sign and submit finality signatures at the same time
+// and to stagger their submissions, so that many instances within the same
+// daemon do not hit the RPC endpoint and the mempool in a burst
+type submissionLimiter struct {
+	// slots is nil if the number of concurrent submissions is unlimited
+	slots chan struct{}
+
+	stagger time.Duration
+
+	mu sync.Mutex
+	// nextSubmission is the earliest time the next submission can start
+	nextSubmission time.Time
+}
+
+func newSubmissionLimiter(maxConcurrent uint32, stagger time.Duration) *submissionLimiter {
+	l := &submissionLimiter{stagger: stagger}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	return l
+}
+
+// acquire blocks until a submission slot is available and the stagger interval
+// since the previous submission has passed. It returns ErrFinalityProviderShutDown
+// if quit is closed while waiting. Each successful acquire must be followed by a
+// release
+func (l *submissionLimiter) acquire(quit <-chan struct{}) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-quit:
+			return ErrFinalityProviderShutDown
+		}
+	}
+
+	if l.stagger <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	startAt := l.nextSubmission
+	if startAt.Before(now) {
+		startAt = now
+	}
+	l.nextSubmission = startAt.Add(l.stagger)
+	l.mu.Unlock()
+
+	wait := time.Until(startAt)
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-quit:
+		l.release()
+		return ErrFinalityProviderShutDown
+	}
+}
+
+// release frees the submission slot taken by acquire
+func (l *submissionLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// releaseOnce returns a release of the submission slot taken by acquire which
+// frees the slot only on its first call, so that the slot is freed as soon as
+// the broadcast is accepted while the submission still awaits its inclusion
+func (l *submissionLimiter) releaseOnce() func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(l.release)
+	}
+}
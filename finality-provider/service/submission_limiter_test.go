@@ -0,0 +1,92 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmissionLimiter(t *testing.T) {
+	t.Run("the concurrent submissions are capped", func(t *testing.T) {
+		l := newSubmissionLimiter(2, 0)
+
+		var (
+			wg          sync.WaitGroup
+			mu          sync.Mutex
+			inFlight    int
+			maxInFlight int
+		)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, l.acquire(nil))
+				defer l.release()
+
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		require.LessOrEqual(t, maxInFlight, 2)
+	})
+
+	t.Run("the submissions are staggered", func(t *testing.T) {
+		stagger := 20 * time.Millisecond
+		l := newSubmissionLimiter(0, stagger)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, l.acquire(nil))
+			l.release()
+		}
+		require.GreaterOrEqual(t, time.Since(start), 2*stagger)
+	})
+
+	t.Run("the slot is freed once the wait is aborted", func(t *testing.T) {
+		l := newSubmissionLimiter(1, time.Hour)
+		require.NoError(t, l.acquire(nil))
+
+		// the slot is taken
+		quit := make(chan struct{})
+		close(quit)
+		require.ErrorIs(t, l.acquire(quit), ErrFinalityProviderShutDown)
+		l.release()
+
+		// the slot is taken but the stagger interval has not passed
+		quit = make(chan struct{})
+		errChan := make(chan error)
+		go func() {
+			errChan <- l.acquire(quit)
+		}()
+		close(quit)
+		require.ErrorIs(t, <-errChan, ErrFinalityProviderShutDown)
+		require.Empty(t, l.slots)
+	})
+
+	t.Run("the slot is freed once on the accepted broadcast", func(t *testing.T) {
+		l := newSubmissionLimiter(2, 0)
+		require.NoError(t, l.acquire(nil))
+		require.NoError(t, l.acquire(nil))
+
+		// the broadcast is accepted, and the submission returns afterwards
+		release := l.releaseOnce()
+		release()
+		require.Len(t, l.slots, 1)
+		release()
+		require.Len(t, l.slots, 1)
+
+		l.release()
+		require.Empty(t, l.slots)
+	})
+}
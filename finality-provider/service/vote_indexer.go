@@ -0,0 +1,315 @@
+package service
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	bbntypes "github.com/babylonchain/babylon/types"
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+const (
+	defaultVotingHistoryLimit = 100
+	maxVotingHistoryLimit     = 1000
+	// maxPendingVoteTxs bounds the hashes of the txs of the submitted votes
+	// kept until their heights are indexed, e.g., while the chain cannot be
+	// queried
+	maxPendingVoteTxs = 10000
+)
+
+// voteKey identifies the vote of a finality provider at a height
+type voteKey struct {
+	fpBtcPkHex string
+	height     uint64
+}
+
+// voteIndexer indexes the votes of the finality providers in the daemon
+// accepted by the consumer chain, so that their voting history is served
+// locally instead of querying archive nodes. The votes are taken from the
+// chain, which also catches the votes submitted before a restart or by
+// another daemon, and the hashes of the txs are taken from the events of
+// the instances. The randomness commits are indexed from the events only,
+// as they are emitted once the commits are included on chain. The votes of
+// each chain are indexed through the client of the chain.
+//
+// The votes are polled per height rather than subscribed to, as the events
+// of a subscription are lost while the node is disconnected or the daemon
+// is stopped, which would have to be backfilled by the same queries anyway,
+// and the txs of another daemon voting with the same keys are not signed by
+// the account of this daemon. The queries are bounded per cycle and go to
+// the node that the daemon polls blocks from rather than to archive nodes
+type voteIndexer struct {
+	ccOf   func(chainID string) clientcontroller.ClientController
+	fps    *store.FinalityProviderStore
+	logger *zap.Logger
+
+	mu sync.Mutex
+	// voteTxs are the hashes of the txs of the submitted votes that are
+	// not indexed yet, of which at most maxPendingVoteTxs are kept
+	voteTxs map[voteKey]string
+}
+
+func newVoteIndexer(ccOf func(chainID string) clientcontroller.ClientController, fps *store.FinalityProviderStore, logger *zap.Logger) *voteIndexer {
+	return &voteIndexer{
+		ccOf:    ccOf,
+		fps:     fps,
+		logger:  logger,
+		voteTxs: make(map[voteKey]string),
+	}
+}
+
+func (idx *voteIndexer) Name() string {
+	return "vote-indexer"
+}
+
+// OnEvent collects the submitted votes and the randomness commits of the
+// instances
+func (idx *voteIndexer) OnEvent(e *hooks.Event) error {
+	switch e.Type {
+	case hooks.EventVoteSubmitted:
+		return idx.onVoteSubmitted(e)
+	case hooks.EventPubRandCommitted:
+		btcPk, err := hex.DecodeString(e.FpBtcPk)
+		if err != nil {
+			return err
+		}
+
+		return idx.fps.SaveIndexedPubRandCommit(&proto.IndexedPubRandCommit{
+			BtcPk:       btcPk,
+			StartHeight: e.Height,
+			NumPubRand:  e.NumPubRand,
+			TxHash:      e.TxHash,
+		})
+	default:
+		return nil
+	}
+}
+
+func (idx *voteIndexer) onVoteSubmitted(e *hooks.Event) error {
+	fromHeight := e.FromHeight
+	if fromHeight == 0 {
+		fromHeight = e.Height
+	}
+
+	btcPk, err := bbntypes.NewBIP340PubKeyFromHex(e.FpBtcPk)
+	if err != nil {
+		return err
+	}
+	fp, err := idx.fps.GetFinalityProvider(btcPk.MustToBTCPK())
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	lastIndexedHeight, err := idx.fps.GetLastIndexedHeight(fp.ChainID)
+	if err != nil {
+		return err
+	}
+
+	for height := fromHeight; height <= e.Height; height++ {
+		// the height is already indexed, and the vote would be missed
+		// otherwise, which is included on chain once submitted
+		if lastIndexedHeight != 0 && height <= lastIndexedHeight {
+			if err := idx.fps.SaveIndexedVote(&proto.IndexedVote{BtcPk: btcPk.MustMarshal(), Height: height, TxHash: e.TxHash}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		idx.addVoteTx(voteKey{fpBtcPkHex: e.FpBtcPk, height: height}, e.TxHash)
+	}
+
+	return nil
+}
+
+// addVoteTx keeps the hash of the tx of the submitted vote until its height
+// is indexed. Once maxPendingVoteTxs is exceeded, the hashes of the lowest
+// heights are dropped, whose votes are still indexed without the hashes. It
+// must be called with mu held
+func (idx *voteIndexer) addVoteTx(key voteKey, txHash string) {
+	idx.voteTxs[key] = txHash
+	if len(idx.voteTxs) <= maxPendingVoteTxs {
+		return
+	}
+
+	// a tenth is dropped at once so that the eviction is not run on every
+	// vote once the limit is reached
+	keys := make([]voteKey, 0, len(idx.voteTxs))
+	for k := range idx.voteTxs {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].height < keys[j].height
+	})
+	numDropped := len(keys) - maxPendingVoteTxs + maxPendingVoteTxs/10
+	for _, k := range keys[:numDropped] {
+		delete(idx.voteTxs, k)
+	}
+
+	idx.logger.Warn("dropped the tx hashes of the submitted votes not indexed yet",
+		zap.Int("count", numDropped),
+		zap.Uint64("max_height", keys[numDropped-1].height))
+}
+
+// index indexes the votes of the given local finality providers of the given
+// chain at the heights since the previous cycle, up to observerDelay blocks
+// behind the tip. The first cycle starts from the tip, as the history of the
+// daemon before enabling the indexer is left to archive nodes
+func (idx *voteIndexer) index(chainID string, localFps map[string]struct{}) error {
+	cc := idx.ccOf(chainID)
+	tipBlock, err := cc.QueryBestBlock()
+	if err != nil {
+		return err
+	}
+	if tipBlock.Height <= observerDelay {
+		return nil
+	}
+	endHeight := tipBlock.Height - observerDelay
+
+	lastIndexedHeight, err := idx.fps.GetLastIndexedHeight(chainID)
+	if err != nil {
+		return err
+	}
+	nextHeight := lastIndexedHeight + 1
+	if lastIndexedHeight == 0 {
+		nextHeight = endHeight
+	}
+	if endHeight >= nextHeight+maxObservedBlocksPerCycle {
+		endHeight = nextHeight + maxObservedBlocksPerCycle - 1
+	}
+
+	for height := nextHeight; height <= endHeight; height++ {
+		if err := idx.indexHeight(cc, chainID, height, localFps); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (idx *voteIndexer) indexHeight(cc clientcontroller.ClientController, chainID string, height uint64, localFps map[string]struct{}) error {
+	votedPks, err := cc.QueryVotesAtHeight(height)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	var votes []*proto.IndexedVote
+	for _, pk := range votedPks {
+		pkHex := pk.MarshalHex()
+		if _, ok := localFps[pkHex]; !ok {
+			continue
+		}
+
+		votes = append(votes, &proto.IndexedVote{
+			BtcPk:  pk,
+			Height: height,
+			TxHash: idx.voteTxs[voteKey{fpBtcPkHex: pkHex, height: height}],
+		})
+	}
+
+	if err := idx.fps.SaveIndexedVotes(chainID, height, votes); err != nil {
+		return err
+	}
+
+	// the submitted votes not on chain are not accepted, e.g., duplicates
+	for key := range idx.voteTxs {
+		if _, ok := localFps[key.fpBtcPkHex]; ok && key.height <= height {
+			delete(idx.voteTxs, key)
+		}
+	}
+
+	return nil
+}
+
+// voteIndexerLoop indexes the votes of the finality providers until the
+// app is stopped
+func (app *FinalityProviderApp) voteIndexerLoop() {
+	defer app.wg.Done()
+
+	ticker := time.NewTicker(app.config.PollerConfig.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, chainID := range app.fpManager.chainIDs() {
+				localFps, err := app.localFinalityProviders(chainID)
+				if err != nil {
+					app.logger.Debug("failed to get the finality providers to index the votes", zap.Error(err))
+					continue
+				}
+				if err := app.indexer.index(chainID, localFps); err != nil {
+					app.logger.Debug("failed to index the votes of the finality providers",
+						zap.String("chain_id", chainID), zap.Error(err))
+				}
+			}
+		case <-app.quit:
+			return
+		}
+	}
+}
+
+// QueryVotingHistory returns the finality signatures submitted by the daemon
+// for the finality provider from the given height, along with its indexed
+// votes and randomness commits if the vote indexer is enabled
+func (app *FinalityProviderApp) QueryVotingHistory(fpPkHex string, fromHeight, limit uint64) (*proto.QueryVotingHistoryResponse, error) {
+	if limit == 0 {
+		limit = defaultVotingHistoryLimit
+	}
+	if limit > maxVotingHistoryLimit {
+		return nil, fmt.Errorf("the limit %d exceeds the maximum %d", limit, maxVotingHistoryLimit)
+	}
+
+	fpPk, err := bbntypes.NewBIP340PubKeyFromHex(fpPkHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BTC public key %s: %w", fpPkHex, err)
+	}
+	btcPk := fpPk.MustMarshal()
+
+	subs, err := app.fps.GetVoteSubmissions(btcPk, fromHeight, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the vote submissions: %w", err)
+	}
+	res := &proto.QueryVotingHistoryResponse{Submissions: subs}
+	if app.indexer == nil {
+		return res, nil
+	}
+
+	res.Votes, err = app.fps.GetIndexedVotes(btcPk, fromHeight, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the indexed votes: %w", err)
+	}
+	res.PubRandCommits, err = app.fps.GetIndexedPubRandCommits(btcPk, fromHeight, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the indexed randomness commits: %w", err)
+	}
+	// the finality providers not in the daemon are of the default chain
+	chainID := app.config.BabylonConfig.ChainID
+	fp, err := app.fps.GetFinalityProvider(fpPk.MustToBTCPK())
+	switch {
+	case err == nil:
+		chainID = fp.ChainID
+	case !errors.Is(err, store.ErrFinalityProviderNotFound):
+		return nil, err
+	}
+	res.LastIndexedHeight, err = app.fps.GetLastIndexedHeight(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVoteIndexerBoundsVoteTxs(t *testing.T) {
+	idx := newVoteIndexer(nil, nil, zap.NewNop())
+
+	for h := uint64(1); h <= maxPendingVoteTxs; h++ {
+		idx.addVoteTx(voteKey{fpBtcPkHex: "fp", height: h}, "tx")
+	}
+	require.Len(t, idx.voteTxs, maxPendingVoteTxs)
+
+	// the hashes of the lowest heights are dropped once the limit is exceeded
+	idx.addVoteTx(voteKey{fpBtcPkHex: "fp", height: maxPendingVoteTxs + 1}, "tx")
+	require.Len(t, idx.voteTxs, maxPendingVoteTxs-maxPendingVoteTxs/10)
+	lowestKept := uint64(maxPendingVoteTxs/10 + 2)
+	require.NotContains(t, idx.voteTxs, voteKey{fpBtcPkHex: "fp", height: lowestKept - 1})
+	require.Contains(t, idx.voteTxs, voteKey{fpBtcPkHex: "fp", height: lowestKept})
+	require.Contains(t, idx.voteTxs, voteKey{fpBtcPkHex: "fp", height: maxPendingVoteTxs + 1})
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	fpcfg "github.com/babylonchain/finality-provider/finality-provider/config"
+)
+
+func TestVoteJitter(t *testing.T) {
+	maxJitter := 10 * time.Millisecond
+	for i := 0; i < 1000; i++ {
+		jitter := randomJitter(maxJitter)
+		require.GreaterOrEqual(t, jitter, time.Duration(0))
+		require.Less(t, jitter, maxJitter)
+	}
+
+	// the jitter is disabled
+	fp := &FinalityProviderInstance{cfg: &fpcfg.Config{}}
+	require.NoError(t, fp.waitVoteJitter())
+
+	// the wait is aborted once the instance stops
+	fp = &FinalityProviderInstance{
+		cfg:  &fpcfg.Config{MaxVoteJitter: time.Hour},
+		quit: make(chan struct{}),
+	}
+	close(fp.quit)
+	require.ErrorIs(t, fp.waitVoteJitter(), ErrFinalityProviderShutDown)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+// voteSLOTracker computes the rolling compliance of the vote latency of each
+// finality provider against the SLO, e.g., 95% of votes within 1 block. The
+// latency of a vote is the number of blocks produced after the voted block by
+// the time the vote is submitted
+type voteSLOTracker struct {
+	// target is the minimum ratio of the votes within maxLatency
+	target     float64
+	maxLatency uint64
+	// window is the number of the most recent votes the compliance is computed
+	// over, a breach is only reported once the window is full
+	window int
+
+	mu   sync.Mutex
+	byFp map[string]*voteLatencyWindow
+}
+
+type voteLatencyWindow struct {
+	// withinSLO is a ring buffer of whether each recent vote is within the SLO
+	withinSLO []bool
+	next      int
+	count     int
+	numWithin int
+	breached  bool
+}
+
+// newVoteSLOTracker returns nil if the target is 0, i.e., the SLO is not tracked
+func newVoteSLOTracker(target float64, maxLatency uint64, window uint32) *voteSLOTracker {
+	if target <= 0 {
+		return nil
+	}
+
+	return &voteSLOTracker{
+		target:     target,
+		maxLatency: maxLatency,
+		window:     int(window),
+		byFp:       make(map[string]*voteLatencyWindow),
+	}
+}
+
+// record adds the latency of a vote by the finality provider, and returns the
+// compliance of its window as well as the event to fire if the vote changes
+// whether the SLO is breached, which is empty otherwise
+func (t *voteSLOTracker) record(fpBtcPkHex string, latency uint64) (float64, hooks.EventType) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.byFp[fpBtcPkHex]
+	if !ok {
+		w = &voteLatencyWindow{withinSLO: make([]bool, t.window)}
+		t.byFp[fpBtcPkHex] = w
+	}
+
+	within := latency <= t.maxLatency
+	if w.count == t.window {
+		// evict the oldest vote
+		if w.withinSLO[w.next] {
+			w.numWithin--
+		}
+	} else {
+		w.count++
+	}
+	w.withinSLO[w.next] = within
+	if within {
+		w.numWithin++
+	}
+	w.next = (w.next + 1) % t.window
+
+	compliance := float64(w.numWithin) / float64(w.count)
+	if w.count < t.window {
+		return compliance, ""
+	}
+
+	switch {
+	case !w.breached && compliance < t.target:
+		w.breached = true
+		return compliance, hooks.EventVoteSLOBreached
+	case w.breached && compliance >= t.target:
+		w.breached = false
+		return compliance, hooks.EventVoteSLORecovered
+	default:
+		return compliance, ""
+	}
+}
+
+// recordVoteLatency records the latency of the votes over the given blocks
+// against the tip of the consumer chain, if the SLO is tracked
+func (fp *FinalityProviderInstance) recordVoteLatency(blocks ...*types.BlockInfo) {
+	if fp.voteSLO == nil {
+		return
+	}
+
+	tipBlock, err := fp.cc.QueryBestBlock()
+	if err != nil {
+		fp.logger.Debug("failed to query the tip to measure the vote latency",
+			zap.String("pk", fp.GetBtcPkHex()), zap.Error(err))
+		return
+	}
+
+	for _, b := range blocks {
+		var latency uint64
+		if tipBlock.Height > b.Height {
+			latency = tipBlock.Height - b.Height
+		}
+		fp.metrics.RecordFpVoteLatency(fp.GetBtcPkHex(), latency)
+
+		compliance, eventType := fp.voteSLO.record(fp.GetBtcPkHex(), latency)
+		fp.metrics.RecordFpVoteSLOCompliance(fp.GetBtcPkHex(), compliance)
+
+		switch eventType {
+		case hooks.EventVoteSLOBreached:
+			fp.logger.Warn("the vote latency SLO is breached",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Float64("compliance", compliance),
+				zap.Float64("target", fp.voteSLO.target))
+			fp.emitEvent(&hooks.Event{Type: eventType, Height: b.Height, Compliance: compliance})
+		case hooks.EventVoteSLORecovered:
+			fp.logger.Info("the vote latency SLO is met again",
+				zap.String("pk", fp.GetBtcPkHex()), zap.Float64("compliance", compliance))
+			fp.emitEvent(&hooks.Event{Type: eventType, Height: b.Height, Compliance: compliance})
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/hooks"
+)
+
+func TestVoteSLOTracker(t *testing.T) {
+	require.Nil(t, newVoteSLOTracker(0, 1, 4))
+
+	// 75% of the last 4 votes within 1 block
+	tracker := newVoteSLOTracker(0.75, 1, 4)
+	fpPk := "fp"
+
+	// no breach is reported until the window is full
+	compliance, event := tracker.record(fpPk, 5)
+	require.Equal(t, 0.0, compliance)
+	require.Empty(t, event)
+	for i := 0; i < 2; i++ {
+		_, event = tracker.record(fpPk, 1)
+		require.Empty(t, event)
+	}
+
+	compliance, event = tracker.record(fpPk, 0)
+	require.Equal(t, 0.75, compliance)
+	require.Empty(t, event)
+
+	// the slow vote is evicted
+	compliance, event = tracker.record(fpPk, 2)
+	require.Equal(t, 0.75, compliance)
+	require.Empty(t, event)
+
+	compliance, event = tracker.record(fpPk, 3)
+	require.Equal(t, 0.5, compliance)
+	require.Equal(t, hooks.EventVoteSLOBreached, event)
+
+	// the breach is only reported once
+	_, event = tracker.record(fpPk, 1)
+	require.Empty(t, event)
+
+	_, event = tracker.record(fpPk, 1)
+	require.Empty(t, event)
+	compliance, event = tracker.record(fpPk, 1)
+	require.Equal(t, 0.75, compliance)
+	require.Equal(t, hooks.EventVoteSLORecovered, event)
+
+	// the finality providers are tracked separately
+	compliance, event = tracker.record("other-fp", 0)
+	require.Equal(t, 1.0, compliance)
+	require.Empty(t, event)
+}
@@ -0,0 +1,11 @@
+package store
+
+import (
+	"io"
+)
+
+// Backup writes a consistent copy of the whole database, including the
+// signed blocks and the public randomness, to w
+func (s *FinalityProviderStore) Backup(w io.Writer) error {
+	return s.db.Copy(w)
+}
@@ -0,0 +1,83 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+var (
+	// mapping pk || height -> hash of a block received by the finality
+	// provider but not voted on before the daemon stopped
+	bufferedBlockBucketName = []byte("bufferedBlocks")
+)
+
+// SaveBufferedBlocks records the blocks that the finality provider received
+// but had not processed when its submissions were drained, so that they are
+// voted on after the next start
+func (s *FinalityProviderStore) SaveBufferedBlocks(btcPk *btcec.PublicKey, blocks []*types.BlockInfo) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	pkBytes := schnorr.SerializePubKey(btcPk)
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(bufferedBlockBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		for _, b := range blocks {
+			if err := bucket.Put(fpHeightKey(pkBytes, b.Height), b.Hash); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// TakeBufferedBlocks removes and returns the blocks buffered by the finality
+// provider, in ascending order of height
+func (s *FinalityProviderStore) TakeBufferedBlocks(btcPk *btcec.PublicKey) ([]*types.BlockInfo, error) {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	var blocks []*types.BlockInfo
+
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		// the batch may be retried, in which case the blocks are read again
+		blocks = nil
+
+		bucket := tx.ReadWriteBucket(bufferedBlockBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		var keys [][]byte
+		c := bucket.ReadCursor()
+		for k, v := c.Seek(pkBytes); k != nil && bytes.HasPrefix(k, pkBytes); k, v = c.Next() {
+			keys = append(keys, append([]byte{}, k...))
+			blocks = append(blocks, &types.BlockInfo{
+				Height: binary.BigEndian.Uint64(k[len(pkBytes):]),
+				Hash:   append([]byte{}, v...),
+			})
+		}
+
+		for _, k := range keys {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
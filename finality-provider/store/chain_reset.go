@@ -0,0 +1,99 @@
+package store
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// ResetFinalityProvider brings the finality provider back to the CREATED
+// status after the consumer chain is reset, so that it can be registered
+// again. The heights it has processed and the blocks it has signed on the
+// chain before the reset are forgotten
+func (s *FinalityProviderStore) ResetFinalityProvider(btcPk *btcec.PublicKey) error {
+	return s.resetFinalityProvider(btcPk, func(*proto.FinalityProvider) {})
+}
+
+// MigrateFinalityProviderChainID moves the finality provider to the consumer
+// chain with the given chain ID after an upgrade of the chain changing its
+// ID. The keys of the finality provider are kept, while it is reset as in
+// ResetFinalityProvider as the heights of the old chain do not apply
+func (s *FinalityProviderStore) MigrateFinalityProviderChainID(btcPk *btcec.PublicKey, chainID string) error {
+	return s.resetFinalityProvider(btcPk, func(fp *proto.FinalityProvider) {
+		fp.ChainId = chainID
+	})
+}
+
+// resetFinalityProvider resets the finality provider after applying the given
+// update to it
+func (s *FinalityProviderStore) resetFinalityProvider(btcPk *btcec.PublicKey, update func(*proto.FinalityProvider)) error {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		fpBucket := tx.ReadWriteBucket(finalityProviderBucketName)
+		if fpBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		fpFromDb := fpBucket.Get(pkBytes)
+		if fpFromDb == nil {
+			return ErrFinalityProviderNotFound
+		}
+
+		var storedFp proto.FinalityProvider
+		if err := pm.Unmarshal(fpFromDb, &storedFp); err != nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		update(&storedFp)
+		storedFp.Status = proto.FinalityProviderStatus_CREATED
+		storedFp.LastVotedHeight = 0
+		storedFp.LastProcessedHeight = 0
+		storedFp.FinalizedHeight = 0
+		storedFp.ActivationHeight = 0
+		storedFp.RegistrationTxHash = ""
+		if err := saveFinalityProvider(fpBucket, &storedFp); err != nil {
+			return err
+		}
+
+		for _, bucketName := range [][]byte{signedBlockBucketName, bufferedBlockBucketName, pendingFinalitySigBucketName, voteSubmissionBucketName} {
+			bucket := tx.ReadWriteBucket(bucketName)
+			if bucket == nil {
+				return ErrCorruptedFinalityProviderDb
+			}
+			if err := deleteWithPrefix(bucket, pkBytes); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.replica.invalidate()
+
+	return nil
+}
+
+func deleteWithPrefix(bucket walletdb.ReadWriteBucket, prefix []byte) error {
+	var keys [][]byte
+	c := bucket.ReadCursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
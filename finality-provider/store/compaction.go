@@ -0,0 +1,157 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// CompactableDB is a bolt database that can be compacted while the daemon is
+// running. A bolt file only shrinks when it is rewritten, so the compaction
+// closes the database, rewrites the file and opens it again, while the
+// transactions wait for it to finish
+type CompactableDB struct {
+	mu  sync.RWMutex
+	db  kvdb.Backend
+	cfg kvdb.BoltBackendConfig
+}
+
+var _ walletdb.BatchDB = (*CompactableDB)(nil)
+
+// NewCompactableDB wraps the bolt database opened with the given config
+func NewCompactableDB(db kvdb.Backend, cfg *kvdb.BoltBackendConfig) *CompactableDB {
+	return &CompactableDB{db: db, cfg: *cfg}
+}
+
+// Compact rewrites the database file without its free pages. The database is
+// opened again without compaction if the compaction fails, and an error is
+// only returned if it cannot be opened at all
+func (c *CompactableDB) Compact() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.db.Close(); err != nil {
+		return fmt.Errorf("failed to close the database before the compaction: %w", err)
+	}
+
+	compactCfg := c.cfg
+	compactCfg.AutoCompact = true
+	compactCfg.AutoCompactMinAge = 0
+	db, compactErr := kvdb.GetBoltBackend(&compactCfg)
+	if compactErr == nil {
+		c.db = db
+		return nil
+	}
+
+	openCfg := c.cfg
+	openCfg.AutoCompact = false
+	db, err := kvdb.GetBoltBackend(&openCfg)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open the database after the compaction failed with %v: %w",
+			ErrDatabaseUnavailable, compactErr, err)
+	}
+	c.db = db
+
+	return fmt.Errorf("failed to compact the database: %w", compactErr)
+}
+
+func (c *CompactableDB) BeginReadTx() (walletdb.ReadTx, error) {
+	c.mu.RLock()
+	tx, err := c.db.BeginReadTx()
+	if err != nil {
+		c.mu.RUnlock()
+		return nil, err
+	}
+
+	return &compactableReadTx{ReadTx: tx, release: c.releaseOnce()}, nil
+}
+
+func (c *CompactableDB) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	c.mu.RLock()
+	tx, err := c.db.BeginReadWriteTx()
+	if err != nil {
+		c.mu.RUnlock()
+		return nil, err
+	}
+
+	return &compactableReadWriteTx{ReadWriteTx: tx, release: c.releaseOnce()}, nil
+}
+
+func (c *CompactableDB) Copy(w io.Writer) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.db.Copy(w)
+}
+
+func (c *CompactableDB) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.db.Close()
+}
+
+func (c *CompactableDB) PrintStats() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.db.PrintStats()
+}
+
+func (c *CompactableDB) View(f func(tx walletdb.ReadTx) error, reset func()) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.db.View(f, reset)
+}
+
+func (c *CompactableDB) Update(f func(tx walletdb.ReadWriteTx) error, reset func()) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.db.Update(f, reset)
+}
+
+func (c *CompactableDB) Batch(f func(tx walletdb.ReadWriteTx) error) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return kvdb.Batch(c.db, f)
+}
+
+// releaseOnce returns a func releasing the read lock taken for a transaction,
+// which is safe to call more than once
+func (c *CompactableDB) releaseOnce() func() {
+	var once sync.Once
+	return func() {
+		once.Do(c.mu.RUnlock)
+	}
+}
+
+type compactableReadTx struct {
+	walletdb.ReadTx
+	release func()
+}
+
+func (tx *compactableReadTx) Rollback() error {
+	defer tx.release()
+	return tx.ReadTx.Rollback()
+}
+
+type compactableReadWriteTx struct {
+	walletdb.ReadWriteTx
+	release func()
+}
+
+func (tx *compactableReadWriteTx) Commit() error {
+	defer tx.release()
+	return tx.ReadWriteTx.Commit()
+}
+
+func (tx *compactableReadWriteTx) Rollback() error {
+	defer tx.release()
+	return tx.ReadWriteTx.Rollback()
+}
@@ -0,0 +1,76 @@
+package store_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/config"
+	fpstore "github.com/babylonchain/finality-provider/finality-provider/store"
+)
+
+func TestCompactableDB(t *testing.T) {
+	cfg := config.DefaultDBConfigWithHomePath(t.TempDir())
+	boltDb, err := cfg.GetDbBackend()
+	require.NoError(t, err)
+	db := fpstore.NewCompactableDB(boltDb, cfg.DBConfigToBoltBackendConfig())
+	defer func() {
+		require.NoError(t, db.Close())
+	}()
+
+	bucketName := []byte("bucket")
+	nestedName := []byte("nested")
+	value := make([]byte, 1024)
+	err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(bucketName)
+		if err != nil {
+			return err
+		}
+		nested, err := bucket.CreateBucket(nestedName)
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < 1000; i++ {
+			k := make([]byte, 8)
+			binary.BigEndian.PutUint64(k, i)
+			if err := bucket.Put(k, value); err != nil {
+				return err
+			}
+			if err := nested.Put(k, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+
+	stats, err := fpstore.GetDBStats(db, cfg.DBFile())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2000), stats.BucketKeys[string(bucketName)])
+	require.True(t, stats.LastCompaction.IsZero())
+
+	// the deleted keys leave free pages in the file
+	err = kvdb.Update(db, func(tx kvdb.RwTx) error {
+		return tx.ReadWriteBucket(bucketName).DeleteNestedBucket(nestedName)
+	}, func() {})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Compact())
+
+	compactedStats, err := fpstore.GetDBStats(db, cfg.DBFile())
+	require.NoError(t, err)
+	require.Equal(t, uint64(1000), compactedStats.BucketKeys[string(bucketName)])
+	require.Less(t, compactedStats.FileSize, stats.FileSize)
+	require.False(t, compactedStats.LastCompaction.IsZero())
+
+	// the database is usable after the compaction
+	err = kvdb.View(db, func(tx kvdb.RTx) error {
+		k := make([]byte, 8)
+		require.Equal(t, value, tx.ReadBucket(bucketName).Get(k))
+		return nil
+	}, func() {})
+	require.NoError(t, err)
+}
@@ -0,0 +1,103 @@
+package store
+
+import (
+	"encoding/binary"
+	"os"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// DBStats are the statistics of the database showing its bloat
+type DBStats struct {
+	// FileSize is the size of the database file in bytes, including the
+	// free pages that are only reclaimed by a compaction
+	FileSize int64
+	// BucketKeys is the number of keys in each top level bucket, including
+	// the keys of the nested buckets
+	BucketKeys map[string]uint64
+	// LastCompaction is the time of the last compaction, which is zero if
+	// the database has never been compacted
+	LastCompaction time.Time
+}
+
+// GetDBStats returns the statistics of the database stored in dbFile
+func GetDBStats(db kvdb.Backend, dbFile string) (*DBStats, error) {
+	info, err := os.Stat(dbFile)
+	if err != nil {
+		return nil, err
+	}
+
+	lastCompaction, err := lastCompactionTime(dbFile)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketKeys := make(map[string]uint64)
+	err = kvdb.View(db, func(tx kvdb.RTx) error {
+		return tx.ForEachBucket(func(name []byte) error {
+			count, err := countKeys(tx.ReadBucket(name))
+			if err != nil {
+				return err
+			}
+			bucketKeys[string(name)] = count
+
+			return nil
+		})
+	}, func() {
+		bucketKeys = make(map[string]uint64)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DBStats{
+		FileSize:       info.Size(),
+		BucketKeys:     bucketKeys,
+		LastCompaction: lastCompaction,
+	}, nil
+}
+
+func countKeys(bucket walletdb.ReadBucket) (uint64, error) {
+	if bucket == nil {
+		return 0, nil
+	}
+
+	var count uint64
+	err := bucket.ForEach(func(k, v []byte) error {
+		// a nil value is a nested bucket
+		if v == nil {
+			if nested := bucket.NestedReadBucket(k); nested != nil {
+				n, err := countKeys(nested)
+				if err != nil {
+					return err
+				}
+				count += n
+				return nil
+			}
+		}
+		count++
+
+		return nil
+	})
+
+	return count, err
+}
+
+// lastCompactionTime reads the time of the last compaction, which is recorded
+// by kvdb next to the database file
+func lastCompactionTime(dbFile string) (time.Time, error) {
+	bz, err := os.ReadFile(dbFile + kvdb.LastCompactionFileNameSuffix)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if len(bz) != 8 {
+		return time.Time{}, nil
+	}
+
+	return time.Unix(0, int64(binary.BigEndian.Uint64(bz))), nil
+}
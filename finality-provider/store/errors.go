@@ -6,15 +6,27 @@ var (
 	// ErrCorruptedFinalityProviderDb For some reason, db on disk representation have changed
 	ErrCorruptedFinalityProviderDb = errors.New("finality provider db is corrupted")
 
+	// ErrIncompatibleDbSchema The db is written by a newer binary with an incompatible schema
+	ErrIncompatibleDbSchema = errors.New("incompatible finality provider db schema")
+
 	// ErrFinalityProviderNotFound The finality provider we try update is not found in db
 	ErrFinalityProviderNotFound = errors.New("finality provider not found")
 
 	// ErrDuplicateFinalityProvider The finality provider we try to add already exists in db
 	ErrDuplicateFinalityProvider = errors.New("finality provider already exists")
 
+	// ErrPendingFinalitySigNotFound The pending finality signature we try to fetch is not found in db
+	ErrPendingFinalitySigNotFound = errors.New("pending finality signature not found")
+
+	// ErrSignedBlockNotFound The finality provider has not signed any block at the height
+	ErrSignedBlockNotFound = errors.New("signed block not found")
+
 	// ErrCorruptedPubRandProofDb For some reason, db on disk representation have changed
 	ErrCorruptedPubRandProofDb = errors.New("public randomness proof db is corrupted")
 
+	// ErrDatabaseUnavailable The database cannot be opened again after it is closed for a compaction
+	ErrDatabaseUnavailable = errors.New("the database is unavailable")
+
 	// ErrPubRandProofNotFound The finality provider we try update is not found in db
 	ErrPubRandProofNotFound = errors.New("public randomness proof not found")
 )
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"encoding/binary"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+// SetSchemaVersion overwrites the header of the db to simulate a db written
+// by another binary
+func (s *FinalityProviderStore) SetSchemaVersion(schemaVersion uint64, minVersion string) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(metadataBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, schemaVersion)
+		if err := bucket.Put(schemaVersionKey, v); err != nil {
+			return err
+		}
+
+		return bucket.Put(minDaemonVersionKey, []byte(minVersion))
+	})
+}
+
+// SetLegacyLastIndexedHeight writes the last indexed height as written before
+// the votes were indexed per chain
+func (s *FinalityProviderStore) SetLegacyLastIndexedHeight(height uint64) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(voteIndexBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, height)
+
+		return bucket.Put(legacyLastIndexedHeightKey, v)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	pm "google.golang.org/protobuf/proto"
 
+	"github.com/babylonchain/finality-provider/finality-provider/faultinject"
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
 )
 
@@ -22,11 +23,16 @@ var (
 
 type FinalityProviderStore struct {
 	db kvdb.Backend
+	// replica serves the finality providers to the query RPCs
+	replica *fpReplica
 }
 
 // NewFinalityProviderStore returns a new store backed by db
 func NewFinalityProviderStore(db kvdb.Backend) (*FinalityProviderStore, error) {
-	store := &FinalityProviderStore{db}
+	store := &FinalityProviderStore{db: db, replica: newFpReplica()}
+	if err := store.checkSchemaVersion(); err != nil {
+		return nil, err
+	}
 	if err := store.initBuckets(); err != nil {
 		return nil, err
 	}
@@ -36,7 +42,39 @@ func NewFinalityProviderStore(db kvdb.Backend) (*FinalityProviderStore, error) {
 
 func (s *FinalityProviderStore) initBuckets() error {
 	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
-		_, err := tx.CreateTopLevelBucket(finalityProviderBucketName)
+		if _, err := tx.CreateTopLevelBucket(finalityProviderBucketName); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTopLevelBucket(pendingFinalitySigBucketName); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTopLevelBucket(signedBlockBucketName); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTopLevelBucket(bufferedBlockBucketName); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTopLevelBucket(healthProbeBucketName); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTopLevelBucket(indexedVoteBucketName); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTopLevelBucket(indexedPubRandCommitBucketName); err != nil {
+			return err
+		}
+
+		if _, err := tx.CreateTopLevelBucket(voteSubmissionBucketName); err != nil {
+			return err
+		}
+
+		_, err := tx.CreateTopLevelBucket(voteIndexBucketName)
 		return err
 	})
 }
@@ -73,7 +111,7 @@ func (s *FinalityProviderStore) CreateFinalityProvider(
 func (s *FinalityProviderStore) createFinalityProviderInternal(
 	fp *proto.FinalityProvider,
 ) error {
-	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
 		fpBucket := tx.ReadWriteBucket(finalityProviderBucketName)
 		if fpBucket == nil {
 			return ErrCorruptedFinalityProviderDb
@@ -86,6 +124,12 @@ func (s *FinalityProviderStore) createFinalityProviderInternal(
 
 		return saveFinalityProvider(fpBucket, fp)
 	})
+	if err != nil {
+		return err
+	}
+	s.replica.put(fp)
+
+	return nil
 }
 
 func saveFinalityProvider(
@@ -101,7 +145,7 @@ func saveFinalityProvider(
 		return err
 	}
 
-	return fpBucket.Put(fp.BtcPk, marshalled)
+	return fpBucket.Put(fp.BtcPk, faultinject.MaybeCorrupt(marshalled))
 }
 
 func (s *FinalityProviderStore) SetFpStatus(btcPk *btcec.PublicKey, status proto.FinalityProviderStatus) error {
@@ -113,6 +157,39 @@ func (s *FinalityProviderStore) SetFpStatus(btcPk *btcec.PublicKey, status proto
 	return s.setFinalityProviderState(btcPk, setFpStatus)
 }
 
+// SetFpActivationHeight sets the height of the consumer chain before which
+// the finality provider stays idle
+func (s *FinalityProviderStore) SetFpActivationHeight(btcPk *btcec.PublicKey, activationHeight uint64) error {
+	setFpActivationHeight := func(fp *proto.FinalityProvider) error {
+		fp.ActivationHeight = activationHeight
+		return nil
+	}
+
+	return s.setFinalityProviderState(btcPk, setFpActivationHeight)
+}
+
+// SetFpRegistrationTxHash sets the hash of the tx registering the finality
+// provider on the consumer chain
+func (s *FinalityProviderStore) SetFpRegistrationTxHash(btcPk *btcec.PublicKey, txHash string) error {
+	setFpRegistrationTxHash := func(fp *proto.FinalityProvider) error {
+		fp.RegistrationTxHash = txHash
+		return nil
+	}
+
+	return s.setFinalityProviderState(btcPk, setFpRegistrationTxHash)
+}
+
+// SetFpRewardAddress sets the address receiving the rewards of the finality
+// provider
+func (s *FinalityProviderStore) SetFpRewardAddress(btcPk *btcec.PublicKey, rewardAddress string) error {
+	setFpRewardAddress := func(fp *proto.FinalityProvider) error {
+		fp.RewardAddress = rewardAddress
+		return nil
+	}
+
+	return s.setFinalityProviderState(btcPk, setFpRewardAddress)
+}
+
 // SetFpLastVotedHeight sets the last voted height to the stored last voted height and last processed height
 // only if it is larger than the stored one. This is to ensure the stored state to increase monotonically
 func (s *FinalityProviderStore) SetFpLastVotedHeight(btcPk *btcec.PublicKey, lastVotedHeight uint64) error {
@@ -144,12 +221,29 @@ func (s *FinalityProviderStore) SetFpLastProcessedHeight(btcPk *btcec.PublicKey,
 	return s.setFinalityProviderState(btcPk, setFpLastProcessedHeight)
 }
 
+// SetFpFinalizedHeight sets the finalized height of the finality provider
+// only if it is larger than the stored one, as the finalized heights of the
+// consumer chain are never rolled back
+func (s *FinalityProviderStore) SetFpFinalizedHeight(btcPk *btcec.PublicKey, finalizedHeight uint64) error {
+	setFpFinalizedHeight := func(fp *proto.FinalityProvider) error {
+		if fp.FinalizedHeight < finalizedHeight {
+			fp.FinalizedHeight = finalizedHeight
+		}
+
+		return nil
+	}
+
+	return s.setFinalityProviderState(btcPk, setFpFinalizedHeight)
+}
+
 func (s *FinalityProviderStore) setFinalityProviderState(
 	btcPk *btcec.PublicKey,
 	stateTransitionFn func(provider *proto.FinalityProvider) error,
 ) error {
 	pkBytes := schnorr.SerializePubKey(btcPk)
-	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+	// the batch may be retried, so only the last saved state is replicated
+	var savedFp *proto.FinalityProvider
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
 		fpBucket := tx.ReadWriteBucket(finalityProviderBucketName)
 		if fpBucket == nil {
 			return ErrCorruptedFinalityProviderDb
@@ -168,9 +262,16 @@ func (s *FinalityProviderStore) setFinalityProviderState(
 		if err := stateTransitionFn(&storedFp); err != nil {
 			return err
 		}
+		savedFp = &storedFp
 
 		return saveFinalityProvider(fpBucket, &storedFp)
 	})
+	if err != nil {
+		return err
+	}
+	s.replica.put(savedFp)
+
+	return nil
 }
 
 func (s *FinalityProviderStore) GetFinalityProvider(btcPk *btcec.PublicKey) (*StoredFinalityProvider, error) {
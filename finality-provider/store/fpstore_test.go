@@ -9,8 +9,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
 	fpstore "github.com/babylonchain/finality-provider/finality-provider/store"
 	"github.com/babylonchain/finality-provider/testutil"
+	"github.com/babylonchain/finality-provider/types"
 )
 
 // FuzzFinalityProvidersStore tests save and list finality providers properly
@@ -74,5 +76,173 @@ func FuzzFinalityProvidersStore(f *testing.F) {
 		require.NoError(t, err)
 		_, err = vs.GetFinalityProvider(randomBtcPk)
 		require.ErrorIs(t, err, fpstore.ErrFinalityProviderNotFound)
+
+		// reset the registered finality provider after a chain reset
+		height := uint64(r.Int63n(1000) + 1)
+		err = vs.SetFpStatus(fp.BtcPk, proto.FinalityProviderStatus_ACTIVE)
+		require.NoError(t, err)
+		err = vs.SetFpLastVotedHeight(fp.BtcPk, height)
+		require.NoError(t, err)
+		err = vs.SaveSignedBlockHash(fp.BtcPk, height, datagen.GenRandomByteArray(r, 32))
+		require.NoError(t, err)
+		// the finalized height never moves backwards
+		err = vs.SetFpFinalizedHeight(fp.BtcPk, height)
+		require.NoError(t, err)
+		err = vs.SetFpFinalizedHeight(fp.BtcPk, height-1)
+		require.NoError(t, err)
+		actualFp, err = vs.GetFinalityProvider(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, height, actualFp.FinalizedHeight)
+		// the replica serving the queries follows the writes
+		replicaFp, err := vs.GetFinalityProviderFromReplica(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, actualFp, replicaFp)
+		err = vs.ResetFinalityProvider(fp.BtcPk)
+		require.NoError(t, err)
+		actualFp, err = vs.GetFinalityProvider(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, proto.FinalityProviderStatus_CREATED, actualFp.Status)
+		replicaFps, err := vs.GetAllFinalityProvidersFromReplica()
+		require.NoError(t, err)
+		require.Equal(t, []*fpstore.StoredFinalityProvider{actualFp}, replicaFps)
+		_, err = vs.GetFinalityProviderFromReplica(randomBtcPk)
+		require.ErrorIs(t, err, fpstore.ErrFinalityProviderNotFound)
+		require.Zero(t, actualFp.LastVotedHeight)
+		require.Zero(t, actualFp.LastProcessedHeight)
+		require.Zero(t, actualFp.FinalizedHeight)
+		_, err = vs.GetSignedBlockHash(fp.BtcPk, height)
+		require.ErrorIs(t, err, fpstore.ErrSignedBlockNotFound)
+
+		// migrate the finality provider to a new chain ID
+		err = vs.SetFpLastVotedHeight(fp.BtcPk, height)
+		require.NoError(t, err)
+		replicaFp, err = vs.GetFinalityProviderFromReplica(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, height, replicaFp.LastVotedHeight)
+		err = vs.MigrateFinalityProviderChainID(fp.BtcPk, fp.ChainID+"-2")
+		require.NoError(t, err)
+		actualFp, err = vs.GetFinalityProvider(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, fp.ChainID+"-2", actualFp.ChainID)
+		require.Equal(t, fp.KeyName, actualFp.KeyName)
+		require.Equal(t, fp.ChainPk, actualFp.ChainPk)
+		require.Equal(t, proto.FinalityProviderStatus_CREATED, actualFp.Status)
+		require.Zero(t, actualFp.LastVotedHeight)
 	})
 }
+
+// FuzzPruneSignedBlockHashes tests that the hashes of the signed blocks are
+// pruned below the given height of the given finality provider only
+func FuzzPruneSignedBlockHashes(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		cfg := config.DefaultDBConfigWithHomePath(t.TempDir())
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, fpdb.Close())
+		}()
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+
+		fp := testutil.GenRandomFinalityProvider(r, t)
+		otherFp := testutil.GenRandomFinalityProvider(r, t)
+		startHeight := r.Uint64()%1000 + 1
+		numHeights := uint64(r.Intn(20) + 1)
+		for h := startHeight; h < startHeight+numHeights; h++ {
+			require.NoError(t, vs.SaveSignedBlockHash(fp.BtcPk, h, datagen.GenRandomByteArray(r, 32)))
+			require.NoError(t, vs.SaveSignedBlockHash(otherFp.BtcPk, h, datagen.GenRandomByteArray(r, 32)))
+		}
+
+		pruneHeight := startHeight + r.Uint64()%(numHeights+1)
+		require.NoError(t, vs.PruneSignedBlockHashes(fp.BtcPk, pruneHeight))
+
+		for h := startHeight; h < startHeight+numHeights; h++ {
+			_, err := vs.GetSignedBlockHash(fp.BtcPk, h)
+			if h < pruneHeight {
+				require.ErrorIs(t, err, fpstore.ErrSignedBlockNotFound)
+			} else {
+				require.NoError(t, err)
+			}
+			_, err = vs.GetSignedBlockHash(otherFp.BtcPk, h)
+			require.NoError(t, err)
+		}
+	})
+}
+
+// FuzzBufferedBlocks tests that the buffered blocks of a finality provider are
+// taken once in ascending order of height
+func FuzzBufferedBlocks(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		cfg := config.DefaultDBConfigWithHomePath(t.TempDir())
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, fpdb.Close())
+		}()
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+
+		fp := testutil.GenRandomFinalityProvider(r, t)
+		otherFp := testutil.GenRandomFinalityProvider(r, t)
+		startHeight := r.Uint64()%1000 + 1
+		numHeights := uint64(r.Intn(20) + 1)
+		var blocks []*types.BlockInfo
+		for h := startHeight; h < startHeight+numHeights; h++ {
+			blocks = append(blocks, &types.BlockInfo{Height: h, Hash: datagen.GenRandomByteArray(r, 32)})
+		}
+		// the blocks are saved out of order
+		shuffled := append([]*types.BlockInfo{}, blocks...)
+		r.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		require.NoError(t, vs.SaveBufferedBlocks(fp.BtcPk, shuffled))
+		require.NoError(t, vs.SaveBufferedBlocks(otherFp.BtcPk, blocks[:1]))
+
+		taken, err := vs.TakeBufferedBlocks(fp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, blocks, taken)
+
+		taken, err = vs.TakeBufferedBlocks(fp.BtcPk)
+		require.NoError(t, err)
+		require.Empty(t, taken)
+
+		taken, err = vs.TakeBufferedBlocks(otherFp.BtcPk)
+		require.NoError(t, err)
+		require.Equal(t, blocks[:1], taken)
+	})
+}
+
+// TestSchemaVersion tests that the db written by a newer binary is refused
+func TestSchemaVersion(t *testing.T) {
+	homePath := t.TempDir()
+	cfg := config.DefaultDBConfigWithHomePath(homePath)
+
+	fpdb, err := cfg.GetDbBackend()
+	require.NoError(t, err)
+	defer func() {
+		err := fpdb.Close()
+		require.NoError(t, err)
+	}()
+
+	// a fresh db is stamped with the schema of the binary
+	vs, err := fpstore.NewFinalityProviderStore(fpdb)
+	require.NoError(t, err)
+	schemaVersion, err := vs.GetSchemaVersion()
+	require.NoError(t, err)
+	require.Equal(t, fpstore.SchemaVersion, schemaVersion)
+
+	// the store can be reopened by the same binary
+	_, err = fpstore.NewFinalityProviderStore(fpdb)
+	require.NoError(t, err)
+
+	// a db written by a newer binary is refused
+	err = vs.SetSchemaVersion(fpstore.SchemaVersion+1, "100.0.0")
+	require.NoError(t, err)
+	_, err = fpstore.NewFinalityProviderStore(fpdb)
+	require.ErrorIs(t, err, fpstore.ErrIncompatibleDbSchema)
+	require.ErrorContains(t, err, "100.0.0")
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// ImportFinalityProviders saves the finality providers imported from a
+// genesis or a snapshot of the consumer chain in a single batch. The ones
+// that are already stored are skipped and their BTC public keys returned
+func (s *FinalityProviderStore) ImportFinalityProviders(fps []*proto.FinalityProvider) ([][]byte, error) {
+	var skipped [][]byte
+
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		// the batch may be retried, so the skipped keys are collected again
+		skipped = nil
+
+		fpBucket := tx.ReadWriteBucket(finalityProviderBucketName)
+		if fpBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		for _, fp := range fps {
+			if fpBucket.Get(fp.BtcPk) != nil {
+				skipped = append(skipped, fp.BtcPk)
+				continue
+			}
+			if err := saveFinalityProvider(fpBucket, fp); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.replica.invalidate()
+
+	return skipped, nil
+}
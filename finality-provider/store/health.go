@@ -0,0 +1,30 @@
+package store
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// holds the time of the last write probe
+	healthProbeBucketName = []byte("healthProbe")
+	healthProbeKey        = []byte("lastProbe")
+)
+
+// CheckWritable writes the current time to the store to check that the
+// store is still writable, e.g., the disk is not full
+func (s *FinalityProviderStore) CheckWritable() error {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(time.Now().Unix()))
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(healthProbeBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return bucket.Put(healthProbeKey, v)
+	})
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+var (
+	// mapping pk || height -> proto.PendingFinalitySig
+	pendingFinalitySigBucketName = []byte("pendingFinalitySigs")
+)
+
+// fpHeightKey returns the key of a record of the finality provider at the given height
+func fpHeightKey(pkBytes []byte, height uint64) []byte {
+	key := make([]byte, len(pkBytes)+8)
+	copy(key, pkBytes)
+	binary.BigEndian.PutUint64(key[len(pkBytes):], height)
+	return key
+}
+
+// AddPendingFinalitySig records the signed tx of a finality signature that is
+// about to be broadcast so that it can be resolved until its inclusion is
+// confirmed, even across restarts of the daemon
+func (s *FinalityProviderStore) AddPendingFinalitySig(sig *proto.PendingFinalitySig) error {
+	return s.AddPendingFinalitySigs([]*proto.PendingFinalitySig{sig})
+}
+
+// AddPendingFinalitySigs records the pending finality signatures at once,
+// e.g., those of the heights of a batch sharing the same tx
+func (s *FinalityProviderStore) AddPendingFinalitySigs(sigs []*proto.PendingFinalitySig) error {
+	marshalledSigs := make([][]byte, 0, len(sigs))
+	for _, sig := range sigs {
+		if sig == nil {
+			return fmt.Errorf("cannot save nil pending finality signature")
+		}
+		marshalled, err := pm.Marshal(sig)
+		if err != nil {
+			return err
+		}
+		marshalledSigs = append(marshalledSigs, marshalled)
+	}
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(pendingFinalitySigBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		for i, sig := range sigs {
+			if err := bucket.Put(fpHeightKey(sig.BtcPk, sig.Height), marshalledSigs[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// RemovePendingFinalitySig removes the pending finality signature of the given
+// finality provider at the given height
+func (s *FinalityProviderStore) RemovePendingFinalitySig(btcPk *btcec.PublicKey, height uint64) error {
+	key := fpHeightKey(schnorr.SerializePubKey(btcPk), height)
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(pendingFinalitySigBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		if bucket.Get(key) == nil {
+			return ErrPendingFinalitySigNotFound
+		}
+
+		return bucket.Delete(key)
+	})
+}
+
+// GetPendingFinalitySig returns the pending finality signature of the given
+// finality provider at the given height
+func (s *FinalityProviderStore) GetPendingFinalitySig(btcPk *btcec.PublicKey, height uint64) (*proto.PendingFinalitySig, error) {
+	key := fpHeightKey(schnorr.SerializePubKey(btcPk), height)
+	var sig *proto.PendingFinalitySig
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(pendingFinalitySigBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		v := bucket.Get(key)
+		if v == nil {
+			return ErrPendingFinalitySigNotFound
+		}
+
+		var ps proto.PendingFinalitySig
+		if err := pm.Unmarshal(v, &ps); err != nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+		sig = &ps
+
+		return nil
+	}, func() {
+		sig = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// GetPendingFinalitySigs returns the pending finality signatures of the given
+// finality provider in the ascending order of height
+func (s *FinalityProviderStore) GetPendingFinalitySigs(btcPk *btcec.PublicKey) ([]*proto.PendingFinalitySig, error) {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	var sigs []*proto.PendingFinalitySig
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(pendingFinalitySigBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			if !bytes.HasPrefix(k, pkBytes) {
+				return nil
+			}
+
+			var sig proto.PendingFinalitySig
+			if err := pm.Unmarshal(v, &sig); err != nil {
+				return ErrCorruptedFinalityProviderDb
+			}
+			sigs = append(sigs, &sig)
+
+			return nil
+		})
+	}, func() {
+		sigs = nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sigs, nil
+}
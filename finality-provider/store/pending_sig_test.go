@@ -0,0 +1,101 @@
+package store_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/babylonchain/babylon/testutil/datagen"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	fpstore "github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzPendingFinalitySigs tests adding, listing and removing pending finality signatures
+func FuzzPendingFinalitySigs(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		cfg := config.DefaultDBConfigWithHomePath(t.TempDir())
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, fpdb.Close())
+		}()
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+
+		_, btcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+		_, otherBtcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+
+		startHeight := r.Uint64()%1000 + 1
+		numSigs := r.Intn(10) + 1
+		for i := 0; i < numSigs; i++ {
+			err := vs.AddPendingFinalitySig(&proto.PendingFinalitySig{
+				BtcPk:   schnorr.SerializePubKey(btcPk),
+				Height:  startHeight + uint64(i),
+				TxBytes: datagen.GenRandomByteArray(r, 32),
+			})
+			require.NoError(t, err)
+		}
+		err = vs.AddPendingFinalitySig(&proto.PendingFinalitySig{
+			BtcPk:  schnorr.SerializePubKey(otherBtcPk),
+			Height: startHeight,
+		})
+		require.NoError(t, err)
+
+		sigs, err := vs.GetPendingFinalitySigs(btcPk)
+		require.NoError(t, err)
+		require.Len(t, sigs, numSigs)
+		for i, sig := range sigs {
+			require.Equal(t, startHeight+uint64(i), sig.Height)
+		}
+
+		ps, err := vs.GetPendingFinalitySig(btcPk, startHeight)
+		require.NoError(t, err)
+		require.Equal(t, sigs[0].TxBytes, ps.TxBytes)
+
+		err = vs.RemovePendingFinalitySig(btcPk, startHeight)
+		require.NoError(t, err)
+		_, err = vs.GetPendingFinalitySig(btcPk, startHeight)
+		require.ErrorIs(t, err, fpstore.ErrPendingFinalitySigNotFound)
+		err = vs.RemovePendingFinalitySig(btcPk, startHeight)
+		require.ErrorIs(t, err, fpstore.ErrPendingFinalitySigNotFound)
+
+		sigs, err = vs.GetPendingFinalitySigs(btcPk)
+		require.NoError(t, err)
+		require.Len(t, sigs, numSigs-1)
+
+		// the heights of a batch sharing the same tx are added at once
+		batchTxBytes := datagen.GenRandomByteArray(r, 32)
+		batchStartHeight := startHeight + uint64(numSigs)
+		batch := make([]*proto.PendingFinalitySig, 0, numSigs)
+		for i := 0; i < numSigs; i++ {
+			batch = append(batch, &proto.PendingFinalitySig{
+				BtcPk:   schnorr.SerializePubKey(btcPk),
+				Height:  batchStartHeight + uint64(i),
+				TxBytes: batchTxBytes,
+			})
+		}
+		err = vs.AddPendingFinalitySigs(append(batch, nil))
+		require.Error(t, err)
+		sigs, err = vs.GetPendingFinalitySigs(btcPk)
+		require.NoError(t, err)
+		require.Len(t, sigs, numSigs-1)
+
+		err = vs.AddPendingFinalitySigs(batch)
+		require.NoError(t, err)
+		sigs, err = vs.GetPendingFinalitySigs(btcPk)
+		require.NoError(t, err)
+		require.Len(t, sigs, 2*numSigs-1)
+		for _, sig := range sigs[numSigs-1:] {
+			require.Equal(t, batchTxBytes, sig.TxBytes)
+		}
+	})
+}
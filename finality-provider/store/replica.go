@@ -0,0 +1,181 @@
+package store
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// fpReplica is an in-memory replica of the bucket of the finality providers
+// serving the query RPCs, so that the polling of the dashboards does not open
+// a read transaction for each request, as the long-lived read transactions
+// hold back the writes on the signing path, e.g., bbolt cannot grow its
+// memory map while they are open. The replica is updated once a write of a
+// finality provider is committed, and reloaded from the database after the
+// writes of many finality providers at once
+type fpReplica struct {
+	mu     sync.RWMutex
+	loaded bool
+	fps    map[string]*proto.FinalityProvider
+	// generation is increased on each update, so that a reload racing with
+	// a write does not install the state read before the write
+	generation uint64
+}
+
+func newFpReplica() *fpReplica {
+	return &fpReplica{}
+}
+
+// put updates the finality provider in the replica after its write is
+// committed, which is left to the next reload if the replica is not loaded
+func (r *fpReplica) put(fp *proto.FinalityProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	if r.loaded {
+		r.fps[string(fp.BtcPk)] = pm.Clone(fp).(*proto.FinalityProvider)
+	}
+}
+
+// invalidate makes the replica reload from the database on the next read
+func (r *fpReplica) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.loaded = false
+	r.fps = nil
+}
+
+// snapshot returns the finality providers in the replica, and false if it is
+// not loaded. The returned finality providers must not be modified
+func (r *fpReplica) snapshot() ([]*proto.FinalityProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.loaded {
+		return nil, false
+	}
+
+	fps := make([]*proto.FinalityProvider, 0, len(r.fps))
+	for _, fp := range r.fps {
+		fps = append(fps, fp)
+	}
+	// in the order of the keys in the database
+	sort.Slice(fps, func(i, j int) bool {
+		return bytes.Compare(fps[i].BtcPk, fps[j].BtcPk) < 0
+	})
+
+	return fps, true
+}
+
+// loadReplica reads all the finality providers from the database into the
+// replica, unless a write is committed in the meantime
+func (s *FinalityProviderStore) loadReplica() ([]*proto.FinalityProvider, error) {
+	s.replica.mu.RLock()
+	generation := s.replica.generation
+	s.replica.mu.RUnlock()
+
+	var fps []*proto.FinalityProvider
+	err := s.db.View(func(tx kvdb.RTx) error {
+		fpBucket := tx.ReadBucket(finalityProviderBucketName)
+		if fpBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return fpBucket.ForEach(func(k, v []byte) error {
+			var fpProto proto.FinalityProvider
+			if err := pm.Unmarshal(v, &fpProto); err != nil {
+				return ErrCorruptedFinalityProviderDb
+			}
+			fps = append(fps, &fpProto)
+
+			return nil
+		})
+	}, func() {
+		fps = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.replica.mu.Lock()
+	defer s.replica.mu.Unlock()
+	if s.replica.generation == generation {
+		s.replica.fps = make(map[string]*proto.FinalityProvider, len(fps))
+		for _, fp := range fps {
+			s.replica.fps[string(fp.BtcPk)] = fp
+		}
+		s.replica.loaded = true
+	}
+
+	return fps, nil
+}
+
+// replicaSnapshot returns the finality providers in the replica, loading it
+// from the database first if needed
+func (s *FinalityProviderStore) replicaSnapshot() ([]*proto.FinalityProvider, error) {
+	if fps, ok := s.replica.snapshot(); ok {
+		return fps, nil
+	}
+
+	return s.loadReplica()
+}
+
+// GetAllFinalityProvidersFromReplica returns all the stored finality
+// providers from the in-memory replica of the store, which is meant for the
+// query RPCs rather than the signing path
+func (s *FinalityProviderStore) GetAllFinalityProvidersFromReplica() ([]*StoredFinalityProvider, error) {
+	fps, err := s.replicaSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	storedFps := make([]*StoredFinalityProvider, 0, len(fps))
+	for _, fp := range fps {
+		storedFp, err := protoFpToStoredFinalityProvider(fp)
+		if err != nil {
+			return nil, err
+		}
+		storedFps = append(storedFps, storedFp)
+	}
+
+	return storedFps, nil
+}
+
+// GetFinalityProviderFromReplica returns the stored finality provider from
+// the in-memory replica of the store, which is meant for the query RPCs
+// rather than the signing path
+func (s *FinalityProviderStore) GetFinalityProviderFromReplica(btcPk *btcec.PublicKey) (*StoredFinalityProvider, error) {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+
+	s.replica.mu.RLock()
+	fp, found := s.replica.fps[string(pkBytes)]
+	loaded := s.replica.loaded
+	s.replica.mu.RUnlock()
+	if !loaded {
+		fps, err := s.loadReplica()
+		if err != nil {
+			return nil, err
+		}
+		for _, loadedFp := range fps {
+			if bytes.Equal(loadedFp.BtcPk, pkBytes) {
+				fp, found = loadedFp, true
+				break
+			}
+		}
+	}
+	if !found {
+		return nil, ErrFinalityProviderNotFound
+	}
+
+	return protoFpToStoredFinalityProvider(fp)
+}
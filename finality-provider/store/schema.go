@@ -0,0 +1,81 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+const (
+	// SchemaVersion is the version of the db schema written by this binary,
+	// which is bumped whenever the layout of the db changes incompatibly
+	SchemaVersion uint64 = 1
+	// minDaemonVersion is the minimum version of fpd supporting SchemaVersion
+	minDaemonVersion = "0.2.2"
+)
+
+var (
+	// holds the header of the db, i.e., the schema version and the minimum
+	// version of fpd supporting it
+	metadataBucketName  = []byte("metadata")
+	schemaVersionKey    = []byte("schemaVersion")
+	minDaemonVersionKey = []byte("minDaemonVersion")
+)
+
+// checkSchemaVersion refuses the db written by a newer binary with an
+// incompatible schema, and stamps the db with the schema of this binary
+// otherwise. The db without a header predates the schema versioning and
+// has the same layout as the first schema version
+func (s *FinalityProviderStore) checkSchemaVersion() error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(metadataBucketName)
+		if err != nil {
+			return err
+		}
+
+		if v := bucket.Get(schemaVersionKey); v != nil {
+			if len(v) != 8 {
+				return ErrCorruptedFinalityProviderDb
+			}
+			storedVersion := binary.BigEndian.Uint64(v)
+			if storedVersion > SchemaVersion {
+				return fmt.Errorf("%w: the db has schema version %d but this binary supports up to %d, "+
+					"upgrade fpd to %s or later, or restore a backup of the db taken before the upgrade",
+					ErrIncompatibleDbSchema, storedVersion, SchemaVersion, bucket.Get(minDaemonVersionKey))
+			}
+			if storedVersion == SchemaVersion {
+				return nil
+			}
+		}
+
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, SchemaVersion)
+		if err := bucket.Put(schemaVersionKey, v); err != nil {
+			return err
+		}
+
+		return bucket.Put(minDaemonVersionKey, []byte(minDaemonVersion))
+	})
+}
+
+// GetSchemaVersion returns the schema version of the db
+func (s *FinalityProviderStore) GetSchemaVersion() (uint64, error) {
+	var schemaVersion uint64
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(metadataBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		v := bucket.Get(schemaVersionKey)
+		if len(v) != 8 {
+			return ErrCorruptedFinalityProviderDb
+		}
+		schemaVersion = binary.BigEndian.Uint64(v)
+
+		return nil
+	}, func() {})
+
+	return schemaVersion, err
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// mapping pk || height -> hash of the block signed by the finality provider
+	signedBlockBucketName = []byte("signedBlocks")
+)
+
+// SaveSignedBlockHash records the hash of the block that the finality provider
+// signs at the given height, which protects it from signing a conflicting
+// block at the same height, e.g., after a reorg of the consumer chain
+func (s *FinalityProviderStore) SaveSignedBlockHash(btcPk *btcec.PublicKey, height uint64, hash []byte) error {
+	key := fpHeightKey(schnorr.SerializePubKey(btcPk), height)
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(signedBlockBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return bucket.Put(key, hash)
+	})
+}
+
+// GetSignedBlockHash returns the hash of the block that the finality provider
+// has signed at the given height
+func (s *FinalityProviderStore) GetSignedBlockHash(btcPk *btcec.PublicKey, height uint64) ([]byte, error) {
+	key := fpHeightKey(schnorr.SerializePubKey(btcPk), height)
+	var hash []byte
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(signedBlockBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		v := bucket.Get(key)
+		if v == nil {
+			return ErrSignedBlockNotFound
+		}
+		hash = make([]byte, len(v))
+		copy(hash, v)
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hash, nil
+}
+
+// PruneSignedBlockHashes removes the hashes of the blocks signed by the
+// finality provider below the given height, which are finalized and thus can
+// never be replaced by a conflicting block
+func (s *FinalityProviderStore) PruneSignedBlockHashes(btcPk *btcec.PublicKey, belowHeight uint64) error {
+	pkBytes := schnorr.SerializePubKey(btcPk)
+	endKey := fpHeightKey(pkBytes, belowHeight)
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(signedBlockBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		var keys [][]byte
+		c := bucket.ReadCursor()
+		for k, _ := c.Seek(pkBytes); k != nil && bytes.HasPrefix(k, pkBytes) && bytes.Compare(k, endKey) < 0; k, _ = c.Next() {
+			keys = append(keys, append([]byte{}, k...))
+		}
+
+		for _, k := range keys {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+// GetAllFinalityProviderRecords returns the raw records of all the stored
+// finality providers, which are replicated to a standby daemon as they are
+func (s *FinalityProviderStore) GetAllFinalityProviderRecords() ([]*proto.FinalityProvider, error) {
+	var records []*proto.FinalityProvider
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		fpBucket := tx.ReadBucket(finalityProviderBucketName)
+		if fpBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return fpBucket.ForEach(func(k, v []byte) error {
+			var fpProto proto.FinalityProvider
+			if err := pm.Unmarshal(v, &fpProto); err != nil {
+				return ErrCorruptedFinalityProviderDb
+			}
+			records = append(records, &fpProto)
+
+			return nil
+		})
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// MergeFinalityProviderRecord saves the record replicated from the active
+// daemon. The record is created if it does not exist, otherwise the stored
+// heights are only increased and the status and activation height are taken
+// from the record
+func (s *FinalityProviderStore) MergeFinalityProviderRecord(fp *proto.FinalityProvider) error {
+	err := kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		fpBucket := tx.ReadWriteBucket(finalityProviderBucketName)
+		if fpBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		fpFromDb := fpBucket.Get(fp.BtcPk)
+		if fpFromDb == nil {
+			return saveFinalityProvider(fpBucket, fp)
+		}
+
+		var storedFp proto.FinalityProvider
+		if err := pm.Unmarshal(fpFromDb, &storedFp); err != nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		if storedFp.LastVotedHeight < fp.LastVotedHeight {
+			storedFp.LastVotedHeight = fp.LastVotedHeight
+		}
+		if storedFp.LastProcessedHeight < fp.LastProcessedHeight {
+			storedFp.LastProcessedHeight = fp.LastProcessedHeight
+		}
+		storedFp.Status = fp.Status
+		storedFp.ActivationHeight = fp.ActivationHeight
+
+		return saveFinalityProvider(fpBucket, &storedFp)
+	})
+	if err != nil {
+		return err
+	}
+	s.replica.invalidate()
+
+	return nil
+}
+
+// GetSignedBlocksFrom returns at most limit blocks signed by the finality
+// provider with heights no lower than the given height, in ascending order of
+// height
+func (s *FinalityProviderStore) GetSignedBlocksFrom(btcPk []byte, fromHeight, limit uint64) ([]*proto.SignedBlock, error) {
+	var signedBlocks []*proto.SignedBlock
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(signedBlockBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		c := bucket.ReadCursor()
+		for k, v := c.Seek(fpHeightKey(btcPk, fromHeight)); k != nil && bytes.HasPrefix(k, btcPk) && uint64(len(signedBlocks)) < limit; k, v = c.Next() {
+			hash := make([]byte, len(v))
+			copy(hash, v)
+			signedBlocks = append(signedBlocks, &proto.SignedBlock{
+				BtcPk:     btcPk,
+				Height:    binary.BigEndian.Uint64(k[len(btcPk):]),
+				BlockHash: hash,
+			})
+		}
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return signedBlocks, nil
+}
+
+// SaveSignedBlock saves the signed block replicated from the active daemon
+func (s *FinalityProviderStore) SaveSignedBlock(b *proto.SignedBlock) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(signedBlockBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return bucket.Put(fpHeightKey(b.BtcPk, b.Height), b.BlockHash)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
 	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	"github.com/babylonchain/finality-provider/types"
 )
 
 type StoredFinalityProvider struct {
@@ -24,7 +25,14 @@ type StoredFinalityProvider struct {
 	ChainID             string
 	LastVotedHeight     uint64
 	LastProcessedHeight uint64
-	Status              proto.FinalityProviderStatus
+	// FinalizedHeight is the highest processed height that is also
+	// finalized on the consumer chain, after which the processing resumes
+	FinalizedHeight    uint64
+	Status             proto.FinalityProviderStatus
+	ActivationHeight   uint64
+	RegistrationTxHash string
+	WatchOnly          bool
+	RewardAddress      string
 }
 
 func protoFpToStoredFinalityProvider(fp *proto.FinalityProvider) (*StoredFinalityProvider, error) {
@@ -57,7 +65,12 @@ func protoFpToStoredFinalityProvider(fp *proto.FinalityProvider) (*StoredFinalit
 		ChainID:             fp.ChainId,
 		LastVotedHeight:     fp.LastVotedHeight,
 		LastProcessedHeight: fp.LastProcessedHeight,
+		FinalizedHeight:     fp.FinalizedHeight,
 		Status:              fp.Status,
+		ActivationHeight:    fp.ActivationHeight,
+		RegistrationTxHash:  fp.RegistrationTxHash,
+		WatchOnly:           fp.WatchOnly,
+		RewardAddress:       fp.RewardAddress,
 	}, nil
 }
 
@@ -71,8 +84,9 @@ func (sfp *StoredFinalityProvider) GetBIP340BTCPK() *bbn.BIP340PubKey {
 
 func (sfp *StoredFinalityProvider) ToFinalityProviderInfo() *proto.FinalityProviderInfo {
 	return &proto.FinalityProviderInfo{
-		ChainPkHex: sfp.GetChainPkHexString(),
-		BtcPkHex:   sfp.GetBIP340BTCPK().MarshalHex(),
+		ChainPkHex:         sfp.GetChainPkHexString(),
+		BtcPkHex:           sfp.GetBIP340BTCPK().MarshalHex(),
+		BtcPkCompressedHex: types.CompressedBTCPkHex(sfp.GetBIP340BTCPK().MarshalHex()),
 		Description: &proto.Description{
 			Moniker:         sfp.Description.Moniker,
 			Identity:        sfp.Description.Identity,
@@ -80,8 +94,13 @@ func (sfp *StoredFinalityProvider) ToFinalityProviderInfo() *proto.FinalityProvi
 			SecurityContact: sfp.Description.SecurityContact,
 			Details:         sfp.Description.Details,
 		},
-		Commission:      sfp.Commission.String(),
-		LastVotedHeight: sfp.LastVotedHeight,
-		Status:          sfp.Status.String(),
+		Commission:         sfp.Commission.String(),
+		LastVotedHeight:    sfp.LastVotedHeight,
+		Status:             sfp.Status.String(),
+		ActivationHeight:   sfp.ActivationHeight,
+		RegistrationTxHash: sfp.RegistrationTxHash,
+		WatchOnly:          sfp.WatchOnly,
+		RewardAddress:      sfp.RewardAddress,
+		FinalizedHeight:    sfp.FinalizedHeight,
 	}
 }
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+var (
+	// mapping pk || height -> proto.IndexedVote
+	indexedVoteBucketName = []byte("indexedVotes")
+	// mapping pk || start height -> proto.IndexedPubRandCommit
+	indexedPubRandCommitBucketName = []byte("indexedPubRandCommits")
+	// holds the height up to which the votes are indexed for each chain
+	voteIndexBucketName = []byte("voteIndex")
+	// the last indexed height written before the votes were indexed per
+	// chain, which is moved to the key of the default chain
+	legacyLastIndexedHeightKey = []byte("lastIndexedHeight")
+)
+
+// lastIndexedHeightKey returns the key of the last indexed height of the
+// given chain
+func lastIndexedHeightKey(chainID string) []byte {
+	return append([]byte("lastIndexedHeight/"), []byte(chainID)...)
+}
+
+// SaveIndexedVotes saves the accepted votes at the given height of the given
+// chain and moves the last indexed height of the chain to it in one go, so
+// that a height is never indexed twice
+func (s *FinalityProviderStore) SaveIndexedVotes(chainID string, height uint64, votes []*proto.IndexedVote) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		voteBucket := tx.ReadWriteBucket(indexedVoteBucketName)
+		indexBucket := tx.ReadWriteBucket(voteIndexBucketName)
+		if voteBucket == nil || indexBucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		for _, v := range votes {
+			if err := saveIndexedVote(voteBucket, v); err != nil {
+				return err
+			}
+		}
+
+		heightBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(heightBytes, height)
+
+		return indexBucket.Put(lastIndexedHeightKey(chainID), heightBytes)
+	})
+}
+
+// MigrateLastIndexedHeight moves the last indexed height written before the
+// votes were indexed per chain to the given chain, which is a no-op if there
+// is nothing to move
+func (s *FinalityProviderStore) MigrateLastIndexedHeight(chainID string) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(voteIndexBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		v := bucket.Get(legacyLastIndexedHeightKey)
+		if v == nil {
+			return nil
+		}
+		if bucket.Get(lastIndexedHeightKey(chainID)) == nil {
+			if err := bucket.Put(lastIndexedHeightKey(chainID), append([]byte(nil), v...)); err != nil {
+				return err
+			}
+		}
+
+		return bucket.Delete(legacyLastIndexedHeightKey)
+	})
+}
+
+// SaveIndexedVote saves an accepted vote at a height that is already indexed,
+// e.g., a vote submitted late
+func (s *FinalityProviderStore) SaveIndexedVote(v *proto.IndexedVote) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(indexedVoteBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return saveIndexedVote(bucket, v)
+	})
+}
+
+func saveIndexedVote(bucket kvdb.RwBucket, v *proto.IndexedVote) error {
+	vBytes, err := pm.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put(fpHeightKey(v.BtcPk, v.Height), vBytes)
+}
+
+// SaveIndexedPubRandCommit saves an accepted commit of public randomness
+func (s *FinalityProviderStore) SaveIndexedPubRandCommit(c *proto.IndexedPubRandCommit) error {
+	cBytes, err := pm.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(indexedPubRandCommitBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		return bucket.Put(fpHeightKey(c.BtcPk, c.StartHeight), cBytes)
+	})
+}
+
+// GetLastIndexedHeight returns the height up to which the votes of the given
+// chain are indexed, which is 0 if nothing is indexed yet
+func (s *FinalityProviderStore) GetLastIndexedHeight(chainID string) (uint64, error) {
+	var height uint64
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(voteIndexBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		v := bucket.Get(lastIndexedHeightKey(chainID))
+		if v == nil {
+			return nil
+		}
+		if len(v) != 8 {
+			return ErrCorruptedFinalityProviderDb
+		}
+		height = binary.BigEndian.Uint64(v)
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return height, nil
+}
+
+// GetIndexedVotes returns at most limit indexed votes of the finality provider
+// with heights no lower than the given height, in ascending order of height
+func (s *FinalityProviderStore) GetIndexedVotes(btcPk []byte, fromHeight, limit uint64) ([]*proto.IndexedVote, error) {
+	var votes []*proto.IndexedVote
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(indexedVoteBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		c := bucket.ReadCursor()
+		for k, v := c.Seek(fpHeightKey(btcPk, fromHeight)); k != nil && bytes.HasPrefix(k, btcPk) && uint64(len(votes)) < limit; k, v = c.Next() {
+			var vote proto.IndexedVote
+			if err := pm.Unmarshal(v, &vote); err != nil {
+				return ErrCorruptedFinalityProviderDb
+			}
+			votes = append(votes, &vote)
+		}
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// GetIndexedPubRandCommits returns at most limit indexed commits of the
+// finality provider with start heights no lower than the given height, in
+// ascending order of start height
+func (s *FinalityProviderStore) GetIndexedPubRandCommits(btcPk []byte, fromHeight, limit uint64) ([]*proto.IndexedPubRandCommit, error) {
+	var commits []*proto.IndexedPubRandCommit
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(indexedPubRandCommitBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		c := bucket.ReadCursor()
+		for k, v := c.Seek(fpHeightKey(btcPk, fromHeight)); k != nil && bytes.HasPrefix(k, btcPk) && uint64(len(commits)) < limit; k, v = c.Next() {
+			var commit proto.IndexedPubRandCommit
+			if err := pm.Unmarshal(v, &commit); err != nil {
+				return ErrCorruptedFinalityProviderDb
+			}
+			commits = append(commits, &commit)
+		}
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
@@ -0,0 +1,166 @@
+package store_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/babylonchain/babylon/testutil/datagen"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/finality-provider/config"
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+	fpstore "github.com/babylonchain/finality-provider/finality-provider/store"
+	"github.com/babylonchain/finality-provider/testutil"
+)
+
+// FuzzVotingHistory tests indexing and querying the votes of finality providers
+func FuzzVotingHistory(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		cfg := config.DefaultDBConfigWithHomePath(t.TempDir())
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, fpdb.Close())
+		}()
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+
+		_, btcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+		_, otherBtcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+		pkBytes := schnorr.SerializePubKey(btcPk)
+		otherPkBytes := schnorr.SerializePubKey(otherBtcPk)
+
+		chainID := "chain-test"
+		otherChainID := "other-chain-test"
+
+		// the last indexed height of a single chain daemon is moved to the
+		// default chain
+		legacyHeight := r.Uint64()%1000 + 1
+		require.NoError(t, vs.SetLegacyLastIndexedHeight(legacyHeight))
+		require.NoError(t, vs.MigrateLastIndexedHeight(chainID))
+		lastIndexedHeight, err := vs.GetLastIndexedHeight(chainID)
+		require.NoError(t, err)
+		require.Equal(t, legacyHeight, lastIndexedHeight)
+		lastIndexedHeight, err = vs.GetLastIndexedHeight(otherChainID)
+		require.NoError(t, err)
+		require.Zero(t, lastIndexedHeight)
+
+		startHeight := legacyHeight + 1
+		numHeights := uint64(r.Intn(10) + 1)
+		for h := startHeight; h < startHeight+numHeights; h++ {
+			err := vs.SaveIndexedVotes(chainID, h, []*proto.IndexedVote{
+				{BtcPk: pkBytes, Height: h, TxHash: testutil.GenRandomHexStr(r, 32)},
+				{BtcPk: otherPkBytes, Height: h},
+			})
+			require.NoError(t, err)
+		}
+
+		lastIndexedHeight, err = vs.GetLastIndexedHeight(chainID)
+		require.NoError(t, err)
+		require.Equal(t, startHeight+numHeights-1, lastIndexedHeight)
+		lastIndexedHeight, err = vs.GetLastIndexedHeight(otherChainID)
+		require.NoError(t, err)
+		require.Zero(t, lastIndexedHeight)
+
+		votes, err := vs.GetIndexedVotes(pkBytes, 0, 1000)
+		require.NoError(t, err)
+		require.Len(t, votes, int(numHeights))
+		for i, v := range votes {
+			require.Equal(t, startHeight+uint64(i), v.Height)
+			require.Equal(t, pkBytes, v.BtcPk)
+		}
+
+		votes, err = vs.GetIndexedVotes(pkBytes, startHeight+1, 1)
+		require.NoError(t, err)
+		if numHeights > 1 {
+			require.Len(t, votes, 1)
+			require.Equal(t, startHeight+1, votes[0].Height)
+		} else {
+			require.Empty(t, votes)
+		}
+
+		err = vs.SaveIndexedPubRandCommit(&proto.IndexedPubRandCommit{
+			BtcPk:       pkBytes,
+			StartHeight: startHeight,
+			NumPubRand:  100,
+		})
+		require.NoError(t, err)
+		commits, err := vs.GetIndexedPubRandCommits(pkBytes, startHeight, 1000)
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+		commits, err = vs.GetIndexedPubRandCommits(otherPkBytes, 0, 1000)
+		require.NoError(t, err)
+		require.Empty(t, commits)
+	})
+}
+
+// FuzzVoteSubmissions tests recording and querying the submitted finality
+// signatures of finality providers
+func FuzzVoteSubmissions(f *testing.F) {
+	testutil.AddRandomSeedsToFuzzer(f, 10)
+	f.Fuzz(func(t *testing.T, seed int64) {
+		r := rand.New(rand.NewSource(seed))
+
+		cfg := config.DefaultDBConfigWithHomePath(t.TempDir())
+		fpdb, err := cfg.GetDbBackend()
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, fpdb.Close())
+		}()
+		vs, err := fpstore.NewFinalityProviderStore(fpdb)
+		require.NoError(t, err)
+
+		_, btcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+		_, otherBtcPk, err := datagen.GenRandomBTCKeyPair(r)
+		require.NoError(t, err)
+		pkBytes := schnorr.SerializePubKey(btcPk)
+		otherPkBytes := schnorr.SerializePubKey(otherBtcPk)
+
+		// a batch of signatures submitted in one tx
+		startHeight := r.Uint64()%1000 + 1
+		numHeights := uint64(r.Intn(10) + 1)
+		txHash := testutil.GenRandomHexStr(r, 32)
+		inclusionHeight := startHeight + numHeights + r.Uint64()%10
+		var subs []*proto.VoteSubmission
+		for h := startHeight; h < startHeight+numHeights; h++ {
+			subs = append(subs, &proto.VoteSubmission{
+				BtcPk:           pkBytes,
+				Height:          h,
+				TxHash:          txHash,
+				InclusionHeight: inclusionHeight,
+			})
+		}
+		require.NoError(t, vs.SaveVoteSubmissions(subs))
+
+		res, err := vs.GetVoteSubmissions(pkBytes, 0, 1000)
+		require.NoError(t, err)
+		require.Len(t, res, int(numHeights))
+		for i, sub := range res {
+			require.Equal(t, startHeight+uint64(i), sub.Height)
+			require.Equal(t, txHash, sub.TxHash)
+			require.Equal(t, inclusionHeight, sub.InclusionHeight)
+		}
+
+		// a later submission at the same height overwrites the record
+		resubmittedTxHash := testutil.GenRandomHexStr(r, 32)
+		err = vs.SaveVoteSubmissions([]*proto.VoteSubmission{
+			{BtcPk: pkBytes, Height: startHeight, TxHash: resubmittedTxHash},
+		})
+		require.NoError(t, err)
+		res, err = vs.GetVoteSubmissions(pkBytes, startHeight, 1)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, resubmittedTxHash, res[0].TxHash)
+
+		res, err = vs.GetVoteSubmissions(otherPkBytes, 0, 1000)
+		require.NoError(t, err)
+		require.Empty(t, res)
+	})
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"bytes"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	pm "google.golang.org/protobuf/proto"
+
+	"github.com/babylonchain/finality-provider/finality-provider/proto"
+)
+
+var (
+	// mapping pk || height -> proto.VoteSubmission
+	voteSubmissionBucketName = []byte("voteSubmissions")
+)
+
+// SaveVoteSubmissions records the finality signatures submitted in one tx,
+// overwriting the records of a previous submission at the same heights
+func (s *FinalityProviderStore) SaveVoteSubmissions(subs []*proto.VoteSubmission) error {
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(voteSubmissionBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		for _, sub := range subs {
+			subBytes, err := pm.Marshal(sub)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(fpHeightKey(sub.BtcPk, sub.Height), subBytes); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetVoteSubmissions returns at most limit recorded submissions of the
+// finality provider with heights no lower than the given height, in
+// ascending order of height
+func (s *FinalityProviderStore) GetVoteSubmissions(btcPk []byte, fromHeight, limit uint64) ([]*proto.VoteSubmission, error) {
+	var subs []*proto.VoteSubmission
+
+	err := s.db.View(func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(voteSubmissionBucketName)
+		if bucket == nil {
+			return ErrCorruptedFinalityProviderDb
+		}
+
+		c := bucket.ReadCursor()
+		for k, v := c.Seek(fpHeightKey(btcPk, fromHeight)); k != nil && bytes.HasPrefix(k, btcPk) && uint64(len(subs)) < limit; k, v = c.Next() {
+			var sub proto.VoteSubmission
+			if err := pm.Unmarshal(v, &sub); err != nil {
+				return ErrCorruptedFinalityProviderDb
+			}
+			subs = append(subs, &sub)
+		}
+
+		return nil
+	}, func() {})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
@@ -27,6 +27,7 @@ func NewEOTSServerHandler(t *testing.T, cfg *config.Config, eotsHomeDir string)
 	logger := zap.NewNop()
 	eotsManager, err := eotsmanager.NewLocalEOTSManager(eotsHomeDir, cfg.KeyringBackend, dbBackend, logger)
 	require.NoError(t, err)
+	eotsManager.SetAllowDoubleSign(cfg.AllowDoubleSign)
 
 	eotsServer := service.NewEOTSManagerServer(cfg, logger, eotsManager, dbBackend, shutdownInterceptor)
 
@@ -0,0 +1,114 @@
+package keyring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+// adr36MsgType is the amino type of the message signed in the arbitrary
+// sign docs of ADR-36
+const adr36MsgType = "sign/MsgSignData"
+
+var ErrInvalidMessageSignature = errors.New("invalid message signature")
+
+// the fields of the sign doc are declared in the alphabetical order of their
+// JSON names, so that the marshalled sign doc is the sorted amino JSON
+type adr36SignDoc struct {
+	AccountNumber string     `json:"account_number"`
+	ChainID       string     `json:"chain_id"`
+	Fee           adr36Fee   `json:"fee"`
+	Memo          string     `json:"memo"`
+	Msgs          []adr36Msg `json:"msgs"`
+	Sequence      string     `json:"sequence"`
+}
+
+type adr36Fee struct {
+	Amount []struct{} `json:"amount"`
+	Gas    string     `json:"gas"`
+}
+
+type adr36Msg struct {
+	Type  string        `json:"type"`
+	Value adr36MsgValue `json:"value"`
+}
+
+type adr36MsgValue struct {
+	Data   string `json:"data"`
+	Signer string `json:"signer"`
+}
+
+// ADR36SignBytes returns the bytes signed by the signer to sign the arbitrary
+// data as specified by ADR-36, which are the amino JSON of a sign doc with an
+// empty chain ID, zero account number and sequence, and no fee. Such a sign
+// doc can never be broadcast as a tx, and is the one signed by the wallets,
+// e.g., Keplr, so the signatures can be verified by the same tools
+func ADR36SignBytes(signer string, data []byte) ([]byte, error) {
+	doc := adr36SignDoc{
+		AccountNumber: "0",
+		ChainID:       "",
+		Fee: adr36Fee{
+			Amount: []struct{}{},
+			Gas:    "0",
+		},
+		Memo: "",
+		Msgs: []adr36Msg{{
+			Type: adr36MsgType,
+			Value: adr36MsgValue{
+				Data:   base64.StdEncoding.EncodeToString(data),
+				Signer: signer,
+			},
+		}},
+		Sequence: "0",
+	}
+
+	return json.Marshal(doc)
+}
+
+// SignADR36 signs the data with the chain key as specified by ADR-36, where
+// the signer is the address of the key with the given bech32 prefix
+func SignADR36(sk *sdksecp256k1.PrivKey, accountPrefix string, data []byte) (string, []byte, error) {
+	signer, err := bech32.ConvertAndEncode(accountPrefix, sk.PubKey().Address())
+	if err != nil {
+		return "", nil, err
+	}
+
+	signBytes, err := ADR36SignBytes(signer, data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sig, err := sk.Sign(signBytes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signer, sig, nil
+}
+
+// VerifyADR36 verifies the ADR-36 signature of the data by the signer, which
+// must be the address of the public key
+func VerifyADR36(pk *sdksecp256k1.PubKey, signer string, data, sig []byte) error {
+	_, addr, err := bech32.DecodeAndConvert(signer)
+	if err != nil {
+		return fmt.Errorf("invalid signer address %s: %w", signer, err)
+	}
+	if !bytes.Equal(pk.Address(), addr) {
+		return fmt.Errorf("%w: the public key does not match the signer address %s", ErrInvalidMessageSignature, signer)
+	}
+
+	signBytes, err := ADR36SignBytes(signer, data)
+	if err != nil {
+		return err
+	}
+	if !pk.VerifySignature(signBytes, sig) {
+		return fmt.Errorf("%w: the signature does not match the data and the signer", ErrInvalidMessageSignature)
+	}
+
+	return nil
+}
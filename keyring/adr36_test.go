@@ -0,0 +1,35 @@
+package keyring_test
+
+import (
+	"errors"
+	"testing"
+
+	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/stretchr/testify/require"
+
+	fpkr "github.com/babylonchain/finality-provider/keyring"
+)
+
+func TestADR36(t *testing.T) {
+	sk := sdksecp256k1.GenPrivKey()
+	pk := sk.PubKey().(*sdksecp256k1.PubKey)
+	data := []byte("listing: my-finality-provider")
+
+	signer, sig, err := fpkr.SignADR36(sk, "bbn", data)
+	require.NoError(t, err)
+	require.NoError(t, fpkr.VerifyADR36(pk, signer, data, sig))
+
+	// the sign doc is the sorted amino JSON of ADR-36
+	signBytes, err := fpkr.ADR36SignBytes("bbn1signer", []byte("hello"))
+	require.NoError(t, err)
+	require.Equal(t, `{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"",`+
+		`"msgs":[{"type":"sign/MsgSignData","value":{"data":"aGVsbG8=","signer":"bbn1signer"}}],"sequence":"0"}`,
+		string(signBytes))
+
+	err = fpkr.VerifyADR36(pk, signer, []byte("another message"), sig)
+	require.True(t, errors.Is(err, fpkr.ErrInvalidMessageSignature))
+
+	otherPk := sdksecp256k1.GenPrivKey().PubKey().(*sdksecp256k1.PubKey)
+	err = fpkr.VerifyADR36(otherPk, signer, data, sig)
+	require.True(t, errors.Is(err, fpkr.ErrInvalidMessageSignature))
+}
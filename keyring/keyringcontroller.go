@@ -4,21 +4,31 @@ import (
 	"fmt"
 	"strings"
 
+	bbntypes "github.com/babylonchain/babylon/types"
 	bstypes "github.com/babylonchain/babylon/x/btcstaking/types"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/cometbft/cometbft/crypto/tmhash"
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	sdksecp256k1 "github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	"github.com/cosmos/go-bip39"
 
 	"github.com/babylonchain/finality-provider/types"
+	"github.com/babylonchain/finality-provider/util/securemem"
 )
 
 const (
 	secp256k1Type       = "secp256k1"
 	mnemonicEntropySize = 256
+	// cosmosCoinType is the BIP-44 coin type of the keys on the Ledger devices
+	cosmosCoinType = 118
 )
 
+// LedgerSignMode is the sign mode of the txs signed on the Ledger devices, as
+// the Cosmos app of the devices only signs the amino JSON of the txs
+const LedgerSignMode = "amino-json"
+
 type ChainKeyringController struct {
 	kr     keyring.Keyring
 	fpName string
@@ -118,6 +128,57 @@ func (kc *ChainKeyringController) CreateChainKey(passphrase, hdPath, mnemonic st
 	}
 }
 
+// CreateLedgerChainKey saves a reference to the chain key derived at the
+// given account and index on a Ledger device, whose private key never leaves
+// the device. The binary must be built with the ledger build tag
+func (kc *ChainKeyringController) CreateLedgerChainKey(hrp string, account, index uint32) (*types.ChainKeyInfo, error) {
+	_, ledgerAlgos := kc.kr.SupportedAlgorithms()
+	algo, err := keyring.NewSigningAlgoFromString(secp256k1Type, ledgerAlgos)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := kc.kr.SaveLedgerKey(kc.fpName, algo, hrp, cosmosCoinType, account, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save the key on the Ledger device: %w", err)
+	}
+
+	accAddress, err := record.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	pk, err := record.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+	btcPk, err := btcec.ParsePubKey(pk.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.ChainKeyInfo{
+		Name:       kc.fpName,
+		AccAddress: accAddress,
+		PublicKey:  btcPk,
+	}, nil
+}
+
+// IsLedgerKey returns whether the chain key is on a Ledger device
+func (kc *ChainKeyringController) IsLedgerKey() (bool, error) {
+	return IsLedgerKey(kc.kr, kc.fpName)
+}
+
+// IsLedgerKey returns whether the key with the given name in the keyring is
+// on a Ledger device
+func IsLedgerKey(kr keyring.Keyring, name string) (bool, error) {
+	k, err := kr.Key(name)
+	if err != nil {
+		return false, fmt.Errorf("failed to get the key %s: %w", name, err)
+	}
+
+	return k.GetType() == keyring.TypeLedger, nil
+}
+
 // CreatePop creates proof-of-possession of Babylon and BTC public keys
 // the input is the bytes of BTC public key used to sign
 // this requires both keys created beforehand
@@ -126,10 +187,45 @@ func (kc *ChainKeyringController) CreatePop(btcPrivKey *btcec.PrivateKey, passph
 	if err != nil {
 		return nil, err
 	}
+	defer securemem.Zero(bbnPrivKey.Key)
 
 	return bstypes.NewPoP(bbnPrivKey, btcPrivKey)
 }
 
+// CreatePopWithBTCSigner creates proof-of-possession of Babylon and BTC public keys
+// in the same way as CreatePop, except that the BTC signature is produced by the given
+// signing function so that the BTC private key does not need to be loaded
+func (kc *ChainKeyringController) CreatePopWithBTCSigner(
+	btcPk *btcec.PublicKey,
+	passphrase string,
+	signBTC func(msg []byte) (*schnorr.Signature, error),
+) (*bstypes.ProofOfPossession, error) {
+	bbnPrivKey, err := kc.GetChainPrivKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer securemem.Zero(bbnPrivKey.Key)
+
+	// BabylonSig = sign(sk_Babylon, pk_BTC)
+	bip340Pk := bbntypes.NewBIP340PubKeyFromBTCPK(btcPk)
+	babylonSig, err := bbnPrivKey.Sign(*bip340Pk)
+	if err != nil {
+		return nil, err
+	}
+
+	// BtcSig = schnorr_sign(sk_BTC, hash(BabylonSig))
+	btcSig, err := signBTC(tmhash.Sum(babylonSig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign the Babylon signature with the BTC key: %w", err)
+	}
+
+	return &bstypes.ProofOfPossession{
+		BtcSigType: bstypes.BTCSigType_BIP340,
+		BabylonSig: babylonSig,
+		BtcSig:     bbntypes.NewBIP340SignatureFromBTCSig(btcSig).MustMarshal(),
+	}, nil
+}
+
 func (kc *ChainKeyringController) GetChainPrivKey(passphrase string) (*sdksecp256k1.PrivKey, error) {
 	kc.input.Reset(passphrase)
 	k, err := kc.kr.Key(kc.fpName)
@@ -137,6 +233,11 @@ func (kc *ChainKeyringController) GetChainPrivKey(passphrase string) (*sdksecp25
 		return nil, fmt.Errorf("failed to get private key: %w", err)
 	}
 
+	// the keys on a Ledger device cannot be loaded
+	if k.GetLocal() == nil {
+		return nil, fmt.Errorf("the key %s is not stored locally and its private key cannot be loaded", kc.fpName)
+	}
+
 	privKeyCached := k.GetLocal().PrivKey.GetCachedValue()
 
 	switch v := privKeyCached.(type) {
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
@@ -64,5 +65,61 @@ func FuzzCreatePoP(f *testing.F) {
 		require.NoError(t, err)
 		err = pop.Verify(bbnPk, btcPk, &chaincfg.SimNetParams)
 		require.NoError(t, err)
+
+		// the PoP signed by the EOTS manager without loading the BTC private
+		// key is valid as well
+		popWithSigner, err := kc.CreatePopWithBTCSigner(btcPk.MustToBTCPK(), passphrase, func(msg []byte) (*schnorr.Signature, error) {
+			return em.SignSchnorrSig(btcPkBytes, msg, passphrase)
+		})
+		require.NoError(t, err)
+		err = popWithSigner.Verify(bbnPk, btcPk, &chaincfg.SimNetParams)
+		require.NoError(t, err)
+	})
+}
+
+func TestLedgerKey(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sdkCtx := testutil.GenSdkContext(r, t)
+
+	t.Run("a local key is not on a Ledger device", func(t *testing.T) {
+		kc, err := fpkr.NewChainKeyringController(sdkCtx, "local-key", keyring.BackendTest)
+		require.NoError(t, err)
+		_, err = kc.CreateChainKey(passphrase, hdPath, "")
+		require.NoError(t, err)
+
+		isLedger, err := kc.IsLedgerKey()
+		require.NoError(t, err)
+		require.False(t, isLedger)
+	})
+
+	t.Run("the private key of a key stored elsewhere cannot be loaded", func(t *testing.T) {
+		kc, err := fpkr.NewChainKeyringController(sdkCtx, "offline-key", keyring.BackendTest)
+		require.NoError(t, err)
+		_, err = kc.GetKeyring().SaveOfflineKey("offline-key", secp256k1.GenPrivKey().PubKey())
+		require.NoError(t, err)
+
+		isLedger, err := fpkr.IsLedgerKey(kc.GetKeyring(), "offline-key")
+		require.NoError(t, err)
+		require.False(t, isLedger)
+		_, err = kc.GetChainPrivKey(passphrase)
+		require.Error(t, err)
+	})
+
+	t.Run("a missing key is reported", func(t *testing.T) {
+		kc, err := fpkr.NewChainKeyringController(sdkCtx, "missing-key", keyring.BackendTest)
+		require.NoError(t, err)
+
+		_, err = kc.IsLedgerKey()
+		require.Error(t, err)
+	})
+
+	t.Run("a Ledger key cannot be added without a device", func(t *testing.T) {
+		kc, err := fpkr.NewChainKeyringController(sdkCtx, "ledger-key", keyring.BackendTest)
+		require.NoError(t, err)
+
+		_, err = kc.CreateLedgerChainKey("bbn", 0, 0)
+		require.Error(t, err)
+		_, err = kc.IsLedgerKey()
+		require.Error(t, err)
 	})
 }
@@ -75,3 +75,17 @@ func NewRootLoggerWithFile(logFile string, level string) (*zap.Logger, error) {
 	}
 	return logger, nil
 }
+
+// NewAuditLoggerWithFile creates a logger writing JSON records only to the
+// given file, which is kept apart from the main log as an audit trail
+func NewAuditLoggerWithFile(auditFile string) (*zap.Logger, error) {
+	if err := util.MakeDirectory(filepath.Dir(auditFile)); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(auditFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRootLogger("json", "info", f)
+}
@@ -0,0 +1,196 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// tailBufferSize is the number of the entries buffered for each subscriber,
+// beyond which the entries are dropped for the subscriber instead of blocking
+// the logging
+const tailBufferSize = 256
+
+// Entry is a log entry delivered to the subscribers tailing the logs
+type Entry struct {
+	Time  time.Time
+	Level zapcore.Level
+	// Subsystem is the name of the logger, which is empty for the root logger
+	Subsystem string
+	Message   string
+	// Fields are the JSON-encoded fields of the entry
+	Fields string
+}
+
+// Tail fans the log entries out to the subscribers tailing the logs. The
+// entries are delivered regardless of the level of the logger, so that the
+// subscribers can tail the debug logs without restarting the daemon
+type Tail struct {
+	mu   sync.RWMutex
+	subs map[*TailSubscription]struct{}
+}
+
+func NewTail() *Tail {
+	return &Tail{subs: make(map[*TailSubscription]struct{})}
+}
+
+// TailSubscription receives the entries at or above its level and of its
+// subsystem, if set, including the subsystems nested in it
+type TailSubscription struct {
+	tail      *Tail
+	level     zapcore.Level
+	subsystem string
+	entries   chan *Entry
+
+	mu      sync.Mutex
+	dropped uint64
+}
+
+// Subscribe starts delivering the entries matching the given filters, which
+// must be stopped by Unsubscribe
+func (t *Tail) Subscribe(level zapcore.Level, subsystem string) *TailSubscription {
+	sub := &TailSubscription{
+		tail:      t,
+		level:     level,
+		subsystem: subsystem,
+		entries:   make(chan *Entry, tailBufferSize),
+	}
+
+	t.mu.Lock()
+	t.subs[sub] = struct{}{}
+	t.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe stops delivering the entries to the subscription
+func (s *TailSubscription) Unsubscribe() {
+	s.tail.mu.Lock()
+	delete(s.tail.subs, s)
+	s.tail.mu.Unlock()
+}
+
+// Entries returns the channel of the entries delivered to the subscription
+func (s *TailSubscription) Entries() <-chan *Entry {
+	return s.entries
+}
+
+// Dropped returns and resets the number of the entries dropped since the last
+// call as the subscriber could not keep up
+func (s *TailSubscription) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := s.dropped
+	s.dropped = 0
+
+	return dropped
+}
+
+func (s *TailSubscription) matches(level zapcore.Level, subsystem string) bool {
+	if level < s.level {
+		return false
+	}
+
+	return s.subsystem == "" || subsystem == s.subsystem || strings.HasPrefix(subsystem, s.subsystem+".")
+}
+
+// Wrap returns the given logger teeing its entries to the tail
+func (t *Tail) Wrap(logger *zap.Logger) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, &tailCore{tail: t})
+	}))
+}
+
+func (t *Tail) enabled(level zapcore.Level) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for sub := range t.subs {
+		if level >= sub.level {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Tail) publish(entry *Entry) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for sub := range t.subs {
+		if !sub.matches(entry.Level, entry.Subsystem) {
+			continue
+		}
+		select {
+		case sub.entries <- entry:
+		default:
+			sub.mu.Lock()
+			sub.dropped++
+			sub.mu.Unlock()
+		}
+	}
+}
+
+// tailCore is the core of the logger delivering the entries to the tail
+type tailCore struct {
+	tail   *Tail
+	fields []zapcore.Field
+}
+
+func (c *tailCore) Enabled(level zapcore.Level) bool {
+	return c.tail.enabled(level)
+}
+
+func (c *tailCore) With(fields []zapcore.Field) zapcore.Core {
+	return &tailCore{
+		tail:   c.tail,
+		fields: append(c.fields[:len(c.fields):len(c.fields)], fields...),
+	}
+}
+
+func (c *tailCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+func (c *tailCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	var fieldsJSON string
+	if len(enc.Fields) > 0 {
+		bz, err := json.Marshal(enc.Fields)
+		if err != nil {
+			return err
+		}
+		fieldsJSON = string(bz)
+	}
+
+	c.tail.publish(&Entry{
+		Time:      ent.Time,
+		Level:     ent.Level,
+		Subsystem: ent.LoggerName,
+		Message:   ent.Message,
+		Fields:    fieldsJSON,
+	})
+
+	return nil
+}
+
+func (c *tailCore) Sync() error {
+	return nil
+}
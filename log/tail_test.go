@@ -0,0 +1,77 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTail(t *testing.T) {
+	tail := NewTail()
+	// the entries are delivered regardless of the level of the logger
+	logger := tail.Wrap(zap.NewNop()).Named("service")
+
+	t.Run("the entries are filtered by level and subsystem", func(t *testing.T) {
+		warnSub := tail.Subscribe(zapcore.WarnLevel, "")
+		defer warnSub.Unsubscribe()
+		pollerSub := tail.Subscribe(zapcore.DebugLevel, "service.poller")
+		defer pollerSub.Unsubscribe()
+
+		logger.Debug("debug message")
+		logger.Warn("warn message", zap.Uint64("height", 10))
+		logger.Named("poller").Debug("poller message")
+		logger.Named("pollerx").Error("other subsystem message")
+		logger.Named("poller").Named("reorg").Info("nested subsystem message")
+
+		entry := <-warnSub.Entries()
+		require.Equal(t, "warn message", entry.Message)
+		require.Equal(t, zapcore.WarnLevel, entry.Level)
+		require.Equal(t, "service", entry.Subsystem)
+		require.JSONEq(t, `{"height": 10}`, entry.Fields)
+		entry = <-warnSub.Entries()
+		require.Equal(t, "other subsystem message", entry.Message)
+		require.Empty(t, warnSub.Entries())
+
+		entry = <-pollerSub.Entries()
+		require.Equal(t, "poller message", entry.Message)
+		require.Empty(t, entry.Fields)
+		entry = <-pollerSub.Entries()
+		require.Equal(t, "nested subsystem message", entry.Message)
+		require.Equal(t, "service.poller.reorg", entry.Subsystem)
+		require.Empty(t, pollerSub.Entries())
+	})
+
+	t.Run("the fields of the logger are included", func(t *testing.T) {
+		sub := tail.Subscribe(zapcore.InfoLevel, "")
+		defer sub.Unsubscribe()
+
+		logger.With(zap.String("pk", "fp-pk")).Info("message", zap.Int("attempt", 1))
+
+		entry := <-sub.Entries()
+		require.JSONEq(t, `{"pk": "fp-pk", "attempt": 1}`, entry.Fields)
+	})
+
+	t.Run("the entries beyond the buffer are dropped", func(t *testing.T) {
+		sub := tail.Subscribe(zapcore.InfoLevel, "")
+		defer sub.Unsubscribe()
+
+		for i := 0; i < tailBufferSize+3; i++ {
+			logger.Info("message")
+		}
+
+		require.Len(t, sub.Entries(), tailBufferSize)
+		require.Equal(t, uint64(3), sub.Dropped())
+		require.Zero(t, sub.Dropped())
+	})
+
+	t.Run("no entry is delivered after unsubscribing", func(t *testing.T) {
+		sub := tail.Subscribe(zapcore.DebugLevel, "")
+		sub.Unsubscribe()
+
+		logger.Error("message")
+		require.Empty(t, sub.Entries())
+		require.False(t, tail.enabled(zapcore.ErrorLevel))
+	})
+}
@@ -17,6 +17,20 @@ type FpMetrics struct {
 	babylonTipHeight     prometheus.Gauge
 	lastPolledHeight     prometheus.Gauge
 	pollerStartingHeight prometheus.Gauge
+	clockSkewSeconds     prometheus.Gauge
+	pollerTotalReorgs    prometheus.Counter
+	// pollerTotalPauses and pollerPausedSeconds track the backpressure from
+	// the finality signature submission to the pollers
+	pollerTotalPauses   prometheus.Counter
+	pollerPausedSeconds prometheus.Counter
+	// remote EOTS manager metrics
+	eotsCallLatency *prometheus.HistogramVec
+	// database metrics
+	dbFileSizeBytes          prometheus.Gauge
+	dbBucketKeys             *prometheus.GaugeVec
+	dbLastCompactionSeconds  prometheus.Gauge
+	dbTotalCompactions       prometheus.Counter
+	dbTotalFailedCompactions prometheus.Counter
 	// single finality provider metrics
 	fpStatus                        *prometheus.GaugeVec
 	fpSecondsSinceLastVote          *prometheus.GaugeVec
@@ -24,11 +38,17 @@ type FpMetrics struct {
 	fpLastVotedHeight               *prometheus.GaugeVec
 	fpLastProcessedHeight           *prometheus.GaugeVec
 	fpLastCommittedRandomnessHeight *prometheus.GaugeVec
+	fpRandomnessRunway              *prometheus.GaugeVec
+	fpUnmitigableRandGapHeights     *prometheus.GaugeVec
 	fpTotalBlocksWithoutVotingPower *prometheus.CounterVec
 	fpTotalVotedBlocks              *prometheus.GaugeVec
 	fpTotalCommittedRandomness      *prometheus.GaugeVec
 	fpTotalFailedVotes              *prometheus.CounterVec
 	fpTotalFailedRandomness         *prometheus.CounterVec
+	fpTotalClonedVotes              *prometheus.CounterVec
+	fpVoteLatencyBlocks             *prometheus.HistogramVec
+	fpVoteSLOCompliance             *prometheus.GaugeVec
+	fpBlockQueueDepth               *prometheus.GaugeVec
 	// time keeper
 	mu                     sync.Mutex
 	previousVoteByFp       map[string]*time.Time
@@ -65,6 +85,22 @@ func NewFpMetrics() *FpMetrics {
 				Name: "poller_starting_height",
 				Help: "The initial block height when the poller started operation",
 			}),
+			clockSkewSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "clock_skew_seconds",
+				Help: "The difference between the local clock and the timestamp of the latest block of the consumer chain",
+			}),
+			pollerTotalReorgs: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "poller_total_reorgs",
+				Help: "The total number of reorgs of the consumer chain detected by the poller",
+			}),
+			pollerTotalPauses: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "poller_total_pauses",
+				Help: "The total number of times a poller paused as its buffer was full of blocks not processed yet",
+			}),
+			pollerPausedSeconds: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "poller_paused_seconds_total",
+				Help: "The total time the pollers spent paused as their buffers were full of blocks not processed yet",
+			}),
 			fpSecondsSinceLastVote: prometheus.NewGaugeVec(
 				prometheus.GaugeOpts{
 					Name: "fp_seconds_since_last_vote",
@@ -121,6 +157,20 @@ func NewFpMetrics() *FpMetrics {
 				},
 				[]string{"fp_btc_pk_hex"},
 			),
+			fpRandomnessRunway: prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: "fp_randomness_runway_blocks",
+					Help: "The number of heights with randomness committed ahead of the tip by a finality provider.",
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
+			fpUnmitigableRandGapHeights: prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: "fp_unmitigable_randomness_gap_heights",
+					Help: "The number of heights within the gaps between the randomness commits of a finality provider that cannot be filled.",
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
 			fpTotalFailedVotes: prometheus.NewCounterVec(
 				prometheus.CounterOpts{
 					Name: "fp_total_failed_votes",
@@ -135,6 +185,63 @@ func NewFpMetrics() *FpMetrics {
 				},
 				[]string{"fp_btc_pk_hex"},
 			),
+			fpTotalClonedVotes: prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "fp_total_cloned_votes",
+					Help: "The total number of votes of a finality provider found on the consumer chain that were not submitted by this daemon.",
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
+			fpVoteLatencyBlocks: prometheus.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Name:    "fp_vote_latency_blocks",
+					Help:    "The number of blocks produced after the voted block by the time the vote is submitted by a finality provider.",
+					Buckets: []float64{0, 1, 2, 3, 5, 10, 20},
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
+			fpVoteSLOCompliance: prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: "fp_vote_slo_compliance",
+					Help: "The ratio of the recent votes by a finality provider within the vote latency SLO.",
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
+			fpBlockQueueDepth: prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Name: "fp_block_queue_depth",
+					Help: "The number of polled blocks waiting to be processed by a finality provider.",
+				},
+				[]string{"fp_btc_pk_hex"},
+			),
+			eotsCallLatency: prometheus.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Name:    "eots_call_latency_seconds",
+					Help:    "The latency of the calls to the remote EOTS manager.",
+					Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+				},
+				[]string{"method", "code"},
+			),
+			dbFileSizeBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "db_file_size_bytes",
+				Help: "The size of the database file, including the free pages reclaimed by a compaction",
+			}),
+			dbBucketKeys: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Name: "db_bucket_keys",
+				Help: "The number of keys in each top level bucket of the database",
+			}, []string{"bucket"}),
+			dbLastCompactionSeconds: prometheus.NewGauge(prometheus.GaugeOpts{
+				Name: "db_last_compaction_timestamp_seconds",
+				Help: "The unix time of the last compaction of the database, which is 0 if it has never been compacted",
+			}),
+			dbTotalCompactions: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "db_total_compactions",
+				Help: "The total number of compactions of the database while the daemon is running",
+			}),
+			dbTotalFailedCompactions: prometheus.NewCounter(prometheus.CounterOpts{
+				Name: "db_total_failed_compactions",
+				Help: "The total number of failed compactions of the database while the daemon is running",
+			}),
 			mu: sync.Mutex{},
 		}
 
@@ -144,6 +251,10 @@ func NewFpMetrics() *FpMetrics {
 		prometheus.MustRegister(fpMetricsInstance.babylonTipHeight)
 		prometheus.MustRegister(fpMetricsInstance.lastPolledHeight)
 		prometheus.MustRegister(fpMetricsInstance.pollerStartingHeight)
+		prometheus.MustRegister(fpMetricsInstance.clockSkewSeconds)
+		prometheus.MustRegister(fpMetricsInstance.pollerTotalReorgs)
+		prometheus.MustRegister(fpMetricsInstance.pollerTotalPauses)
+		prometheus.MustRegister(fpMetricsInstance.pollerPausedSeconds)
 		prometheus.MustRegister(fpMetricsInstance.fpSecondsSinceLastVote)
 		prometheus.MustRegister(fpMetricsInstance.fpSecondsSinceLastRandomness)
 		prometheus.MustRegister(fpMetricsInstance.fpLastVotedHeight)
@@ -152,8 +263,20 @@ func NewFpMetrics() *FpMetrics {
 		prometheus.MustRegister(fpMetricsInstance.fpTotalVotedBlocks)
 		prometheus.MustRegister(fpMetricsInstance.fpTotalCommittedRandomness)
 		prometheus.MustRegister(fpMetricsInstance.fpLastCommittedRandomnessHeight)
+		prometheus.MustRegister(fpMetricsInstance.fpRandomnessRunway)
+		prometheus.MustRegister(fpMetricsInstance.fpUnmitigableRandGapHeights)
 		prometheus.MustRegister(fpMetricsInstance.fpTotalFailedVotes)
 		prometheus.MustRegister(fpMetricsInstance.fpTotalFailedRandomness)
+		prometheus.MustRegister(fpMetricsInstance.fpTotalClonedVotes)
+		prometheus.MustRegister(fpMetricsInstance.fpVoteLatencyBlocks)
+		prometheus.MustRegister(fpMetricsInstance.fpVoteSLOCompliance)
+		prometheus.MustRegister(fpMetricsInstance.fpBlockQueueDepth)
+		prometheus.MustRegister(fpMetricsInstance.eotsCallLatency)
+		prometheus.MustRegister(fpMetricsInstance.dbFileSizeBytes)
+		prometheus.MustRegister(fpMetricsInstance.dbBucketKeys)
+		prometheus.MustRegister(fpMetricsInstance.dbLastCompactionSeconds)
+		prometheus.MustRegister(fpMetricsInstance.dbTotalCompactions)
+		prometheus.MustRegister(fpMetricsInstance.dbTotalFailedCompactions)
 	})
 	return fpMetricsInstance
 }
@@ -188,6 +311,56 @@ func (fm *FpMetrics) RecordPollerStartingHeight(height uint64) {
 	fm.pollerStartingHeight.Set(float64(height))
 }
 
+// RecordClockSkew records the difference between the local clock and the timestamp of the latest block
+func (fm *FpMetrics) RecordClockSkew(skew time.Duration) {
+	fm.clockSkewSeconds.Set(skew.Seconds())
+}
+
+// RecordDBStats records the size, the number of keys per bucket, and the time
+// of the last compaction of the database
+func (fm *FpMetrics) RecordDBStats(stats *store.DBStats) {
+	fm.dbFileSizeBytes.Set(float64(stats.FileSize))
+	for bucket, keys := range stats.BucketKeys {
+		fm.dbBucketKeys.WithLabelValues(bucket).Set(float64(keys))
+	}
+	if !stats.LastCompaction.IsZero() {
+		fm.dbLastCompactionSeconds.Set(float64(stats.LastCompaction.Unix()))
+	}
+}
+
+// RecordDBCompaction records a compaction of the database
+func (fm *FpMetrics) RecordDBCompaction(failed bool) {
+	if failed {
+		fm.dbTotalFailedCompactions.Inc()
+		return
+	}
+	fm.dbTotalCompactions.Inc()
+}
+
+// IncrementPollerTotalReorgs increments the total number of reorgs detected by the poller
+func (fm *FpMetrics) IncrementPollerTotalReorgs() {
+	fm.pollerTotalReorgs.Inc()
+}
+
+// RecordPollerPause records a pause of a poller waiting for its buffer to
+// have room for the polled block
+func (fm *FpMetrics) RecordPollerPause(paused time.Duration) {
+	fm.pollerTotalPauses.Inc()
+	fm.pollerPausedSeconds.Add(paused.Seconds())
+}
+
+// RecordFpBlockQueueDepth records the number of polled blocks waiting to be
+// processed by a finality provider
+func (fm *FpMetrics) RecordFpBlockQueueDepth(fpBtcPkHex string, depth int) {
+	fm.fpBlockQueueDepth.WithLabelValues(fpBtcPkHex).Set(float64(depth))
+}
+
+// RecordEotsCallLatency records the latency of a call to the remote EOTS manager
+// with the gRPC status code of the call
+func (fm *FpMetrics) RecordEotsCallLatency(method, code string, latency time.Duration) {
+	fm.eotsCallLatency.WithLabelValues(method, code).Observe(latency.Seconds())
+}
+
 // RecordFpSecondsSinceLastVote records the seconds since the last finality sig vote by a finality provider
 func (fm *FpMetrics) RecordFpSecondsSinceLastVote(fpBtcPkHex string, seconds float64) {
 	fm.fpSecondsSinceLastVote.WithLabelValues(fpBtcPkHex).Set(seconds)
@@ -213,6 +386,16 @@ func (fm *FpMetrics) RecordFpLastCommittedRandomnessHeight(fpBtcPkHex string, he
 	fm.fpLastCommittedRandomnessHeight.WithLabelValues(fpBtcPkHex).Set(float64(height))
 }
 
+// RecordFpRandomnessRunway records the number of heights with randomness committed ahead of the tip by a finality provider
+func (fm *FpMetrics) RecordFpRandomnessRunway(fpBtcPkHex string, runway uint64) {
+	fm.fpRandomnessRunway.WithLabelValues(fpBtcPkHex).Set(float64(runway))
+}
+
+// RecordFpUnmitigableRandGapHeights records the number of heights within the gaps between the randomness commits of a finality provider that cannot be filled
+func (fm *FpMetrics) RecordFpUnmitigableRandGapHeights(fpBtcPkHex string, heights uint64) {
+	fm.fpUnmitigableRandGapHeights.WithLabelValues(fpBtcPkHex).Set(float64(heights))
+}
+
 // IncrementFpTotalBlocksWithoutVotingPower increments the total number of blocks without voting power for a finality provider
 func (fm *FpMetrics) IncrementFpTotalBlocksWithoutVotingPower(fpBtcPkHex string) {
 	fm.fpTotalBlocksWithoutVotingPower.WithLabelValues(fpBtcPkHex).Inc()
@@ -238,11 +421,26 @@ func (fm *FpMetrics) IncrementFpTotalFailedVotes(fpBtcPkHex string) {
 	fm.fpTotalFailedVotes.WithLabelValues(fpBtcPkHex).Inc()
 }
 
+// IncrementFpTotalClonedVotes increments the total number of votes by a finality provider not submitted by this daemon
+func (fm *FpMetrics) IncrementFpTotalClonedVotes(fpBtcPkHex string) {
+	fm.fpTotalClonedVotes.WithLabelValues(fpBtcPkHex).Inc()
+}
+
 // IncrementFpTotalFailedRandomness increments the total number of failed randomness commitments by a finality provider
 func (fm *FpMetrics) IncrementFpTotalFailedRandomness(fpBtcPkHex string) {
 	fm.fpTotalFailedRandomness.WithLabelValues(fpBtcPkHex).Inc()
 }
 
+// RecordFpVoteLatency records the latency in blocks of a vote by a finality provider
+func (fm *FpMetrics) RecordFpVoteLatency(fpBtcPkHex string, latency uint64) {
+	fm.fpVoteLatencyBlocks.WithLabelValues(fpBtcPkHex).Observe(float64(latency))
+}
+
+// RecordFpVoteSLOCompliance records the rolling compliance of the vote latency of a finality provider
+func (fm *FpMetrics) RecordFpVoteSLOCompliance(fpBtcPkHex string, compliance float64) {
+	fm.fpVoteSLOCompliance.WithLabelValues(fpBtcPkHex).Set(compliance)
+}
+
 // RecordFpVoteTime records the time of a finality sig vote by a finality provider
 func (fm *FpMetrics) RecordFpVoteTime(fpBtcPkHex string) {
 	fm.mu.Lock()
@@ -0,0 +1,86 @@
+package profiling
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPushInterval = time.Minute
+	defaultCPUDuration  = 10 * time.Second
+)
+
+// Config configures the continuous profiling of a daemon, which pushes the
+// profiles to a Pyroscope server on a schedule and/or serves the pprof
+// endpoints to be scraped, e.g., by Parca
+type Config struct {
+	PushURL      string        `long:"pushurl" description:"The URL of the Pyroscope server the profiles are pushed to, e.g., http://127.0.0.1:4040; Empty if the profiles are not pushed" secret:"true"`
+	AuthToken    string        `long:"authtoken" description:"The bearer token authenticating the pushes to the Pyroscope server" secret:"true"`
+	PushInterval time.Duration `long:"pushinterval" description:"The interval at which the profiles are pushed"`
+	CPUDuration  time.Duration `long:"cpuduration" description:"The duration of the CPU profile of each push, which should be shorter than the push interval"`
+	Labels       []string      `long:"label" description:"A key=value label attached to the pushed profiles, e.g., host=fp-1; can be specified multiple times"`
+	Listener     string        `long:"listener" description:"The listener of the pprof HTTP endpoints to be scraped, e.g., 127.0.0.1:6060; Empty if the endpoints are not served"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		PushInterval: defaultPushInterval,
+		CPUDuration:  defaultCPUDuration,
+	}
+}
+
+// Enabled returns whether the profiles are pushed or served
+func (cfg *Config) Enabled() bool {
+	return cfg.PushURL != "" || cfg.Listener != ""
+}
+
+// Validate fills the options missing from the config files predating them
+// with the defaults
+func (cfg *Config) Validate() error {
+	if cfg.PushInterval == 0 {
+		cfg.PushInterval = defaultPushInterval
+	}
+	if cfg.CPUDuration == 0 {
+		cfg.CPUDuration = defaultCPUDuration
+	}
+	if cfg.PushInterval < 0 || cfg.CPUDuration < 0 {
+		return fmt.Errorf("invalid profiling interval: should not be negative")
+	}
+	if cfg.CPUDuration >= cfg.PushInterval {
+		return fmt.Errorf("invalid profiling CPU duration %v: should be shorter than the push interval %v",
+			cfg.CPUDuration, cfg.PushInterval)
+	}
+
+	if cfg.PushURL != "" {
+		if _, err := url.ParseRequestURI(cfg.PushURL); err != nil {
+			return fmt.Errorf("invalid profiling push URL: %w", err)
+		}
+	}
+	if _, err := parseLabels(cfg.Labels); err != nil {
+		return err
+	}
+	if cfg.Listener != "" {
+		if _, err := net.ResolveTCPAddr("tcp", cfg.Listener); err != nil {
+			return fmt.Errorf("invalid profiling listener %s: %w", cfg.Listener, err)
+		}
+	}
+
+	return nil
+}
+
+// parseLabels parses the key=value labels
+func parseLabels(labels []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(labels))
+	for _, l := range labels {
+		k, v, ok := strings.Cut(l, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid profiling label %q: should be key=value", l)
+		}
+		parsed[k] = v
+	}
+
+	return parsed, nil
+}
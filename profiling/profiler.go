@@ -0,0 +1,224 @@
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/pprof"
+	"net/url"
+	"runtime"
+	rpprof "runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	pushTimeout = 30 * time.Second
+	// cpuSampleRate is the rate of the CPU profiles of the Go runtime
+	cpuSampleRate = 100
+)
+
+// snapshotProfiles are the profiles pushed as a snapshot next to the CPU
+// profile, which are the ones revealing a slow memory or goroutine growth
+var snapshotProfiles = []string{"heap", "goroutine"}
+
+// Profiler pushes the profiles of the daemon to a Pyroscope server on a
+// schedule and serves the pprof endpoints, as configured
+type Profiler struct {
+	cfg        *Config
+	appName    string
+	labels     string
+	httpClient *http.Client
+	server     *http.Server
+	logger     *zap.Logger
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Start starts the profiling of the daemon with the given application name,
+// which is a no-op if the profiling is disabled
+func Start(cfg *Config, appName string, logger *zap.Logger) (*Profiler, error) {
+	labels, err := parseLabels(cfg.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Profiler{
+		cfg:        cfg,
+		appName:    appName,
+		labels:     formatLabels(labels),
+		httpClient: &http.Client{Timeout: pushTimeout},
+		logger:     logger,
+		quit:       make(chan struct{}),
+	}
+
+	if cfg.Listener != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+		p.server = &http.Server{Addr: cfg.Listener, Handler: mux}
+
+		go func() {
+			logger.Info("the pprof endpoints are served", zap.String("addr", cfg.Listener))
+			if err := p.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("the pprof server failed", zap.Error(err))
+			}
+		}()
+	}
+
+	if cfg.PushURL != "" {
+		p.wg.Add(1)
+		go p.pushLoop()
+		logger.Info("the profiles are pushed to the Pyroscope server",
+			zap.Duration("interval", cfg.PushInterval))
+	}
+
+	return p, nil
+}
+
+// Stop stops pushing and serving the profiles
+func (p *Profiler) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+
+	if p.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), pushTimeout)
+		defer cancel()
+		if err := p.server.Shutdown(ctx); err != nil {
+			p.logger.Error("failed to stop the pprof server", zap.Error(err))
+		}
+	}
+}
+
+func (p *Profiler) pushLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.PushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.collectAndPush(); err != nil {
+				p.logger.Warn("failed to push the profiles", zap.Error(err))
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// collectAndPush records a CPU profile for the configured duration and
+// pushes it with the snapshots of the other profiles
+func (p *Profiler) collectAndPush() error {
+	from := time.Now()
+	var cpu bytes.Buffer
+	// the CPU profile is skipped if another one is being recorded, e.g.,
+	// through the pprof endpoints
+	if err := rpprof.StartCPUProfile(&cpu); err != nil {
+		p.logger.Debug("skipping the CPU profile", zap.Error(err))
+	} else {
+		select {
+		case <-time.After(p.cfg.CPUDuration):
+		case <-p.quit:
+		}
+		rpprof.StopCPUProfile()
+		if err := p.push("cpu", from, time.Now(), &cpu); err != nil {
+			return err
+		}
+	}
+
+	// the heap profile reflects the state as of the last garbage collection
+	runtime.GC()
+	for _, name := range snapshotProfiles {
+		var buf bytes.Buffer
+		if err := rpprof.Lookup(name).WriteTo(&buf, 0); err != nil {
+			return fmt.Errorf("failed to write the %s profile: %w", name, err)
+		}
+		if err := p.push(name, from, time.Now(), &buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// push uploads the profile in the pprof format to the ingestion API of the
+// Pyroscope server
+func (p *Profiler) push(profileType string, from, until time.Time, profile io.Reader) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fw, profile); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(p.cfg.PushURL)
+	if err != nil {
+		return err
+	}
+	u = u.JoinPath("ingest")
+	q := u.Query()
+	q.Set("name", fmt.Sprintf("%s.%s%s", p.appName, profileType, p.labels))
+	q.Set("from", strconv.FormatInt(from.Unix(), 10))
+	q.Set("until", strconv.FormatInt(until.Unix(), 10))
+	q.Set("format", "pprof")
+	q.Set("spyName", "gospy")
+	q.Set("sampleRate", strconv.Itoa(cpuSampleRate))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if p.cfg.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+p.cfg.AuthToken)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push the %s profile: %w", profileType, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("failed to push the %s profile: %s: %s", profileType, res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+// formatLabels formats the labels in the application name syntax of
+// Pyroscope, i.e., {k1=v1,k2=v2}
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,65 @@
+package profiling
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPushProfile(t *testing.T) {
+	type ingest struct {
+		path, name, format, auth string
+		profile                  []byte
+	}
+	received := make(chan ingest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, _, err := r.FormFile("profile")
+		require.NoError(t, err)
+		profile, err := io.ReadAll(f)
+		require.NoError(t, err)
+		received <- ingest{
+			path:    r.URL.Path,
+			name:    r.URL.Query().Get("name"),
+			format:  r.URL.Query().Get("format"),
+			auth:    r.Header.Get("Authorization"),
+			profile: profile,
+		}
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.PushURL = srv.URL
+	cfg.AuthToken = "token"
+	cfg.Labels = []string{"region=eu", "host=fp-1"}
+	require.NoError(t, cfg.Validate())
+
+	p, err := Start(cfg, "fpd", zap.NewNop())
+	require.NoError(t, err)
+	defer p.Stop()
+
+	now := time.Now()
+	require.NoError(t, p.push("heap", now, now, bytes.NewReader([]byte("pprof"))))
+
+	got := <-received
+	require.Equal(t, "/ingest", got.path)
+	require.Equal(t, "fpd.heap{host=fp-1,region=eu}", got.name)
+	require.Equal(t, "pprof", got.format)
+	require.Equal(t, "Bearer token", got.auth)
+	require.Equal(t, []byte("pprof"), got.profile)
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CPUDuration = cfg.PushInterval
+	require.Error(t, cfg.Validate())
+
+	cfg = DefaultConfig()
+	cfg.Labels = []string{"host"}
+	require.Error(t, cfg.Validate())
+}
@@ -0,0 +1,742 @@
+package fakes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	bbntypes "github.com/babylonchain/babylon/types"
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	finalitytypes "github.com/babylonchain/babylon/x/finality/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/types"
+)
+
+var _ clientcontroller.ClientController = &ClientController{}
+
+// ClientController is an in-memory consumer chain, whose blocks are produced
+// and finalized by the test. The finality signatures and randomness commits
+// are recorded as they are submitted without being verified
+type ClientController struct {
+	Faults
+
+	mu              sync.Mutex
+	blocks          []*types.BlockInfo
+	activatedHeight uint64
+	params          *types.ChainParams
+	numTxs          uint64
+	// reorgs is the number of reorgs, which makes the hashes of the
+	// replaced blocks differ
+	reorgs uint64
+
+	// the following are keyed by the hex BTC public keys
+	registered     map[string]*types.RegisteredFinalityProvider
+	votingPower    map[string]uint64
+	slashed        map[string]bool
+	pubRandCommits map[string]map[uint64]*finalitytypes.PubRandCommitResponse
+	// votes maps the heights to the finality providers voted on them
+	votes map[uint64]map[string]struct{}
+	// sigs maps the heights to the finality signatures on them
+	sigs map[uint64]map[string]*types.FinalitySig
+	// balances are keyed by the bech32 addresses
+	balances map[string]sdk.Coins
+
+	// the following are keyed by the hex hashes of the txs
+	signedVotes map[string][]*signedVote
+	includedTxs map[string]*types.TxResponse
+}
+
+// signedVote is the finality signature in a signed tx
+type signedVote struct {
+	fpPk    *btcec.PublicKey
+	block   *types.BlockInfo
+	pubRand *btcec.FieldVal
+	sig     *btcec.ModNScalar
+}
+
+// NewClientController returns a chain without any block, whose finality is
+// activated at the given height
+func NewClientController(activatedHeight uint64) *ClientController {
+	return &ClientController{
+		activatedHeight: activatedHeight,
+		params: &types.ChainParams{
+			MinCommissionRate: sdkmath.LegacyZeroDec(),
+		},
+		registered:     make(map[string]*types.RegisteredFinalityProvider),
+		votingPower:    make(map[string]uint64),
+		slashed:        make(map[string]bool),
+		pubRandCommits: make(map[string]map[uint64]*finalitytypes.PubRandCommitResponse),
+		votes:          make(map[uint64]map[string]struct{}),
+		sigs:           make(map[uint64]map[string]*types.FinalitySig),
+		balances:       make(map[string]sdk.Coins),
+		signedVotes:    make(map[string][]*signedVote),
+		includedTxs:    make(map[string]*types.TxResponse),
+	}
+}
+
+// ProduceBlocks appends the given number of blocks to the chain and returns them
+func (cc *ClientController) ProduceBlocks(num int) []*types.BlockInfo {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	produced := make([]*types.BlockInfo, 0, num)
+	for i := 0; i < num; i++ {
+		height := uint64(len(cc.blocks)) + 1
+		hash := sha256.Sum256(sdk.Uint64ToBigEndian(height))
+		b := &types.BlockInfo{Height: height, Hash: hash[:]}
+		cc.blocks = append(cc.blocks, b)
+		produced = append(produced, copyBlock(b))
+	}
+
+	return produced
+}
+
+// Reorg replaces the given number of blocks at the tip with blocks of other
+// hashes and drops the votes on them. The finalized blocks are never replaced,
+// so the returned blocks may be fewer
+func (cc *ClientController) Reorg(depth uint64) []*types.BlockInfo {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.reorgs++
+	var replaced []*types.BlockInfo
+	for i := len(cc.blocks) - 1; i >= 0 && uint64(len(cc.blocks)-i) <= depth; i-- {
+		b := cc.blocks[i]
+		if b.Finalized {
+			break
+		}
+		hash := sha256.Sum256(append(sdk.Uint64ToBigEndian(b.Height), sdk.Uint64ToBigEndian(cc.reorgs)...))
+		b.Hash = hash[:]
+		delete(cc.votes, b.Height)
+		replaced = append(replaced, copyBlock(b))
+	}
+
+	return replaced
+}
+
+// FinalizeBlocks finalizes the blocks up to the given height
+func (cc *ClientController) FinalizeBlocks(height uint64) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	for _, b := range cc.blocks {
+		if b.Height > height {
+			break
+		}
+		b.Finalized = true
+	}
+}
+
+// SetVotingPower sets the voting power of the finality provider at all heights
+func (cc *ClientController) SetVotingPower(fpPk *btcec.PublicKey, power uint64) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.votingPower[pkHex(fpPk)] = power
+}
+
+// SetSlashed marks the finality provider as slashed, after which its
+// submissions fail
+func (cc *ClientController) SetSlashed(fpPk *btcec.PublicKey) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.slashed[pkHex(fpPk)] = true
+	cc.votingPower[pkHex(fpPk)] = 0
+}
+
+// SetChainParams sets the parameters returned by QueryChainParams
+func (cc *ClientController) SetChainParams(params *types.ChainParams) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.params = params
+}
+
+// SetBalance sets the balance of the address returned by QueryBalance
+func (cc *ClientController) SetBalance(address string, balance sdk.Coins) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.balances[address] = balance
+}
+
+// VotedHeights returns the heights the finality provider has voted on in
+// ascending order
+func (cc *ClientController) VotedHeights(fpPk *btcec.PublicKey) []uint64 {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	var heights []uint64
+	for height, voters := range cc.votes {
+		if _, ok := voters[pkHex(fpPk)]; ok {
+			heights = append(heights, height)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	return heights
+}
+
+func (cc *ClientController) RegisterFinalityProvider(
+	chainPk []byte,
+	fpPk *btcec.PublicKey,
+	pop []byte,
+	commission *sdkmath.LegacyDec,
+	description []byte,
+) (*types.TxResponse, error) {
+	if err := cc.fault("RegisterFinalityProvider"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if _, ok := cc.registered[pkHex(fpPk)]; ok {
+		return nil, fmt.Errorf("the finality provider %s is already registered", pkHex(fpPk))
+	}
+	var des stakingtypes.Description
+	if err := des.Unmarshal(description); err != nil {
+		return nil, fmt.Errorf("invalid description: %w", err)
+	}
+	cc.registered[pkHex(fpPk)] = &types.RegisteredFinalityProvider{
+		BtcPk:       fpPk,
+		ChainPk:     chainPk,
+		Description: &des,
+		Commission:  commission,
+	}
+
+	return cc.newTxResponse(), nil
+}
+
+func (cc *ClientController) QueryRegisteredFinalityProvider(fpPk *btcec.PublicKey) (*types.RegisteredFinalityProvider, error) {
+	if err := cc.fault("QueryRegisteredFinalityProvider"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	fp, ok := cc.registered[pkHex(fpPk)]
+	if !ok {
+		return nil, nil
+	}
+	registered := *fp
+	if cc.slashed[pkHex(fpPk)] {
+		registered.SlashedBtcHeight = 1
+	}
+
+	return &registered, nil
+}
+
+func (cc *ClientController) QueryRegisteredFinalityProviders() ([]*types.RegisteredFinalityProvider, error) {
+	if err := cc.fault("QueryRegisteredFinalityProviders"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	registered := make([]*types.RegisteredFinalityProvider, 0, len(cc.registered))
+	for pk, fp := range cc.registered {
+		r := *fp
+		if cc.slashed[pk] {
+			r.SlashedBtcHeight = 1
+		}
+		registered = append(registered, &r)
+	}
+
+	return registered, nil
+}
+
+func (cc *ClientController) SetRewardAddress(chainPk []byte, rewardAddr string) (*types.TxResponse, error) {
+	if err := cc.fault("SetRewardAddress"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.newTxResponse(), nil
+}
+
+func (cc *ClientController) CommitPubRandList(fpPk *btcec.PublicKey, startHeight uint64, numPubRand uint64, commitment []byte, sig *schnorr.Signature) (*types.TxResponse, error) {
+	if err := cc.fault("CommitPubRandList"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if err := cc.checkNotSlashed(fpPk); err != nil {
+		return nil, err
+	}
+	if cc.pubRandCommits[pkHex(fpPk)] == nil {
+		cc.pubRandCommits[pkHex(fpPk)] = make(map[uint64]*finalitytypes.PubRandCommitResponse)
+	}
+	cc.pubRandCommits[pkHex(fpPk)][startHeight] = &finalitytypes.PubRandCommitResponse{
+		NumPubRand: numPubRand,
+		Commitment: commitment,
+	}
+
+	return cc.newTxResponse(), nil
+}
+
+func (cc *ClientController) SubmitFinalitySig(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.TxResponse, error) {
+	if err := cc.fault("SubmitFinalitySig"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if err := cc.checkNotSlashed(fpPk); err != nil {
+		return nil, err
+	}
+	res := cc.newTxResponse()
+	cc.addVote(fpPk, block, pubRand, sig, res.TxHash)
+
+	return res, nil
+}
+
+// SignFinalitySigTx returns a tx carrying the finality signature, which is
+// only voted once the tx is broadcast
+func (cc *ClientController) SignFinalitySigTx(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types.SignedTx, error) {
+	if err := cc.fault("SignFinalitySigTx"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.signVotes([]*signedVote{{
+		fpPk:    fpPk,
+		block:   copyBlock(block),
+		pubRand: pubRand,
+		sig:     sig,
+	}}), nil
+}
+
+// SignBatchFinalitySigsTx returns a tx carrying the finality signatures,
+// which are only voted once the tx is broadcast
+func (cc *ClientController) SignBatchFinalitySigsTx(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.SignedTx, error) {
+	if err := cc.fault("SignBatchFinalitySigsTx"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	votes := make([]*signedVote, 0, len(blocks))
+	for i, b := range blocks {
+		votes = append(votes, &signedVote{
+			fpPk:    fpPk,
+			block:   copyBlock(b),
+			pubRand: pubRandList[i],
+			sig:     sigs[i],
+		})
+	}
+
+	return cc.signVotes(votes), nil
+}
+
+// signVotes returns a new tx carrying the given votes
+func (cc *ClientController) signVotes(votes []*signedVote) *types.SignedTx {
+	cc.numTxs++
+	txBytes := sdk.Uint64ToBigEndian(cc.numTxs)
+	hash := sha256.Sum256(txBytes)
+	cc.signedVotes[hex.EncodeToString(hash[:])] = votes
+
+	return &types.SignedTx{Bytes: txBytes, Hash: hash[:]}
+}
+
+// BroadcastSignedTx includes the signed tx, which is included only once
+func (cc *ClientController) BroadcastSignedTx(tx *types.SignedTx) (*types.TxResponse, error) {
+	if err := cc.fault("BroadcastSignedTx"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	txHash := hex.EncodeToString(tx.Hash)
+	if res, ok := cc.includedTxs[txHash]; ok {
+		return res, nil
+	}
+	votes, ok := cc.signedVotes[txHash]
+	if !ok {
+		return nil, fmt.Errorf("unknown tx %s", txHash)
+	}
+	for _, vote := range votes {
+		if err := cc.checkNotSlashed(vote.fpPk); err != nil {
+			return nil, err
+		}
+	}
+
+	if tx.Accepted != nil {
+		tx.Accepted()
+	}
+
+	res := cc.newTxResponse()
+	res.TxHash = txHash
+	for _, vote := range votes {
+		cc.addVote(vote.fpPk, vote.block, vote.pubRand, vote.sig, res.TxHash)
+	}
+	cc.includedTxs[txHash] = res
+
+	return res, nil
+}
+
+func (cc *ClientController) QueryTx(hash []byte) (*types.TxResponse, error) {
+	if err := cc.fault("QueryTx"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	res, ok := cc.includedTxs[hex.EncodeToString(hash)]
+	if !ok {
+		return nil, clientcontroller.ErrTxNotFound
+	}
+
+	return res, nil
+}
+
+func (cc *ClientController) SubmitBatchFinalitySigs(fpPk *btcec.PublicKey, blocks []*types.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types.TxResponse, error) {
+	if err := cc.fault("SubmitBatchFinalitySigs"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if err := cc.checkNotSlashed(fpPk); err != nil {
+		return nil, err
+	}
+	res := cc.newTxResponse()
+	for i, b := range blocks {
+		cc.addVote(fpPk, b, pubRandList[i], sigs[i], res.TxHash)
+	}
+
+	return res, nil
+}
+
+func (cc *ClientController) QueryFinalityProviderVotingPower(fpPk *btcec.PublicKey, blockHeight uint64) (uint64, error) {
+	if err := cc.fault("QueryFinalityProviderVotingPower"); err != nil {
+		return 0, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.votingPower[pkHex(fpPk)], nil
+}
+
+func (cc *ClientController) QueryFinalityProviderSlashed(fpPk *btcec.PublicKey) (bool, error) {
+	if err := cc.fault("QueryFinalityProviderSlashed"); err != nil {
+		return false, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.slashed[pkHex(fpPk)], nil
+}
+
+func (cc *ClientController) QueryLatestFinalizedBlocks(count uint64) ([]*types.BlockInfo, error) {
+	if err := cc.fault("QueryLatestFinalizedBlocks"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	var blocks []*types.BlockInfo
+	for i := len(cc.blocks) - 1; i >= 0 && uint64(len(blocks)) < count; i-- {
+		if cc.blocks[i].Finalized {
+			blocks = append(blocks, copyBlock(cc.blocks[i]))
+		}
+	}
+
+	return blocks, nil
+}
+
+func (cc *ClientController) QueryVotesAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	if err := cc.fault("QueryVotesAtHeight"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return pksFromHex(cc.votes[height])
+}
+
+func (cc *ClientController) QueryActiveFinalityProvidersAtHeight(height uint64) ([]bbntypes.BIP340PubKey, error) {
+	if err := cc.fault("QueryActiveFinalityProvidersAtHeight"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	active := make(map[string]struct{})
+	for fpPkHex, power := range cc.votingPower {
+		if power > 0 {
+			active[fpPkHex] = struct{}{}
+		}
+	}
+
+	return pksFromHex(active)
+}
+
+func (cc *ClientController) QueryVotingPowersAtHeight(height uint64) ([]*types.FinalityProviderPower, error) {
+	if err := cc.fault("QueryVotingPowersAtHeight"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	var powers []*types.FinalityProviderPower
+	for fpPkHex, power := range cc.votingPower {
+		if power == 0 {
+			continue
+		}
+		pk, err := bbntypes.NewBIP340PubKeyFromHex(fpPkHex)
+		if err != nil {
+			return nil, err
+		}
+		btcPk, err := pk.ToBTCPK()
+		if err != nil {
+			return nil, err
+		}
+		powers = append(powers, &types.FinalityProviderPower{FpBtcPk: btcPk, VotingPower: power})
+	}
+
+	return powers, nil
+}
+
+func (cc *ClientController) QueryFinalitySigsAtHeight(height uint64) ([]*types.FinalitySig, error) {
+	if err := cc.fault("QueryFinalitySigsAtHeight"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	sigs := make([]*types.FinalitySig, 0, len(cc.sigs[height]))
+	for _, sig := range cc.sigs[height] {
+		copied := *sig
+		sigs = append(sigs, &copied)
+	}
+
+	return sigs, nil
+}
+
+func (cc *ClientController) QueryLastCommittedPublicRand(fpPk *btcec.PublicKey, count uint64) (map[uint64]*finalitytypes.PubRandCommitResponse, error) {
+	if err := cc.fault("QueryLastCommittedPublicRand"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	commits := cc.pubRandCommits[pkHex(fpPk)]
+	startHeights := make([]uint64, 0, len(commits))
+	for h := range commits {
+		startHeights = append(startHeights, h)
+	}
+	sort.Slice(startHeights, func(i, j int) bool {
+		return startHeights[i] > startHeights[j]
+	})
+
+	res := make(map[uint64]*finalitytypes.PubRandCommitResponse)
+	for _, h := range startHeights {
+		if uint64(len(res)) >= count {
+			break
+		}
+		res[h] = commits[h]
+	}
+
+	return res, nil
+}
+
+func (cc *ClientController) QueryBlock(height uint64) (*types.BlockInfo, error) {
+	if err := cc.fault("QueryBlock"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if height == 0 || height > uint64(len(cc.blocks)) {
+		return nil, fmt.Errorf("the block at height %d is not found", height)
+	}
+
+	return copyBlock(cc.blocks[height-1]), nil
+}
+
+func (cc *ClientController) QueryBlocks(startHeight, endHeight, limit uint64) ([]*types.BlockInfo, error) {
+	if err := cc.fault("QueryBlocks"); err != nil {
+		return nil, err
+	}
+	if endHeight < startHeight {
+		return nil, fmt.Errorf("the startHeight %v should not be higher than the endHeight %v", startHeight, endHeight)
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	var blocks []*types.BlockInfo
+	for h := startHeight; h <= endHeight && h <= uint64(len(cc.blocks)) && uint64(len(blocks)) < limit; h++ {
+		if h == 0 {
+			continue
+		}
+		blocks = append(blocks, copyBlock(cc.blocks[h-1]))
+	}
+
+	return blocks, nil
+}
+
+func (cc *ClientController) QueryBestBlock() (*types.BlockInfo, error) {
+	if err := cc.fault("QueryBestBlock"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if len(cc.blocks) == 0 {
+		return nil, fmt.Errorf("no block is produced yet")
+	}
+
+	return copyBlock(cc.blocks[len(cc.blocks)-1]), nil
+}
+
+func (cc *ClientController) QueryBestBlockTime() (*types.BlockTime, error) {
+	if err := cc.fault("QueryBestBlockTime"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if len(cc.blocks) == 0 {
+		return nil, fmt.Errorf("no block is produced yet")
+	}
+
+	return &types.BlockTime{Height: cc.blocks[len(cc.blocks)-1].Height, Time: time.Now()}, nil
+}
+
+func (cc *ClientController) QueryActivatedHeight() (uint64, error) {
+	if err := cc.fault("QueryActivatedHeight"); err != nil {
+		return 0, err
+	}
+
+	return cc.activatedHeight, nil
+}
+
+func (cc *ClientController) QueryChainParams() (*types.ChainParams, error) {
+	if err := cc.fault("QueryChainParams"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.params, nil
+}
+
+func (cc *ClientController) QueryBalance(address string) (sdk.Coins, error) {
+	if err := cc.fault("QueryBalance"); err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	return cc.balances[address], nil
+}
+
+func (cc *ClientController) Close() error {
+	return nil
+}
+
+func (cc *ClientController) checkNotSlashed(fpPk *btcec.PublicKey) error {
+	if cc.slashed[pkHex(fpPk)] {
+		return fmt.Errorf("%w: %s", btcstakingtypes.ErrFpAlreadySlashed, pkHex(fpPk))
+	}
+
+	return nil
+}
+
+func (cc *ClientController) addVote(fpPk *btcec.PublicKey, block *types.BlockInfo, pubRand *btcec.FieldVal, sig *btcec.ModNScalar, txHash string) {
+	height := block.Height
+	if cc.votes[height] == nil {
+		cc.votes[height] = make(map[string]struct{})
+		cc.sigs[height] = make(map[string]*types.FinalitySig)
+	}
+	cc.votes[height][pkHex(fpPk)] = struct{}{}
+
+	pubRandBytes := pubRand.Bytes()
+	sigBytes := sig.Bytes()
+	cc.sigs[height][pkHex(fpPk)] = &types.FinalitySig{
+		FpBtcPk:   fpPk,
+		Height:    height,
+		BlockHash: block.Hash,
+		PubRand:   pubRandBytes[:],
+		Sig:       sigBytes[:],
+		TxHash:    txHash,
+	}
+}
+
+func (cc *ClientController) newTxResponse() *types.TxResponse {
+	cc.numTxs++
+	hash := sha256.Sum256(sdk.Uint64ToBigEndian(cc.numTxs))
+
+	res := &types.TxResponse{TxHash: hex.EncodeToString(hash[:])}
+	// the tx is included in the block following the tip
+	if len(cc.blocks) > 0 {
+		res.Height = cc.blocks[len(cc.blocks)-1].Height + 1
+	}
+
+	return res
+}
+
+func copyBlock(b *types.BlockInfo) *types.BlockInfo {
+	copied := *b
+	return &copied
+}
+
+func pkHex(pk *btcec.PublicKey) string {
+	return hex.EncodeToString(schnorr.SerializePubKey(pk))
+}
+
+func pksFromHex(pkSet map[string]struct{}) ([]bbntypes.BIP340PubKey, error) {
+	pks := make([]bbntypes.BIP340PubKey, 0, len(pkSet))
+	for fpPkHex := range pkSet {
+		pk, err := bbntypes.NewBIP340PubKeyFromHex(fpPkHex)
+		if err != nil {
+			return nil, err
+		}
+		pks = append(pks, *pk)
+	}
+	sort.Slice(pks, func(i, j int) bool {
+		return pks[i].MarshalHex() < pks[j].MarshalHex()
+	})
+
+	return pks, nil
+}
@@ -0,0 +1,208 @@
+package fakes
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/babylonchain/babylon/crypto/eots"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+
+	"github.com/babylonchain/finality-provider/eotsmanager"
+	"github.com/babylonchain/finality-provider/eotsmanager/randgenerator"
+	"github.com/babylonchain/finality-provider/eotsmanager/types"
+)
+
+var _ eotsmanager.EOTSManager = &EOTSManager{}
+
+// EOTSManager is an in-memory EOTS manager, which derives the randomness in
+// the same way as the local EOTS manager and ignores the passphrases
+type EOTSManager struct {
+	Faults
+
+	mu sync.Mutex
+	// keys maps the hex BTC public keys to the key records
+	keys map[string]*types.KeyRecord
+	// signedHeights records the heights signed by each key
+	signedHeights map[string]map[uint64]struct{}
+}
+
+func NewEOTSManager() *EOTSManager {
+	return &EOTSManager{
+		keys:          make(map[string]*types.KeyRecord),
+		signedHeights: make(map[string]map[uint64]struct{}),
+	}
+}
+
+// AddKey adds the given private key with the name and returns its BIP-340
+// public key
+func (em *EOTSManager) AddKey(name string, privKey *btcec.PrivateKey) []byte {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	pk := schnorr.SerializePubKey(privKey.PubKey())
+	em.keys[hex.EncodeToString(pk)] = &types.KeyRecord{Name: name, PrivKey: privKey}
+
+	return pk
+}
+
+func (em *EOTSManager) CreateKey(name, passphrase, hdPath string) ([]byte, error) {
+	if err := em.fault("CreateKey"); err != nil {
+		return nil, err
+	}
+
+	em.mu.Lock()
+	for _, record := range em.keys {
+		if record.Name == name {
+			em.mu.Unlock()
+			return nil, fmt.Errorf("the key name %s is taken", name)
+		}
+	}
+	em.mu.Unlock()
+
+	privKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return em.AddKey(name, privKey), nil
+}
+
+func (em *EOTSManager) CreateRandomnessPairList(uid []byte, chainID []byte, startHeight uint64, num uint32, passphrase string) ([]*btcec.FieldVal, error) {
+	if err := em.fault("CreateRandomnessPairList"); err != nil {
+		return nil, err
+	}
+
+	record, err := em.keyRecord(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	prList := make([]*btcec.FieldVal, 0, num)
+	for i := uint32(0); i < num; i++ {
+		_, pubRand := randgenerator.GenerateRandomness(record.PrivKey.Serialize(), chainID, startHeight+uint64(i))
+		prList = append(prList, pubRand)
+	}
+
+	return prList, nil
+}
+
+// SeedRandomness is a no-op as the randomness is always derived from the key
+func (em *EOTSManager) SeedRandomness(uid []byte, chainID []byte, startHeight uint64) error {
+	if err := em.fault("SeedRandomness"); err != nil {
+		return err
+	}
+
+	_, err := em.keyRecord(uid)
+
+	return err
+}
+
+// ShredRandomness is a no-op as the randomness is always derived from the key
+func (em *EOTSManager) ShredRandomness(uid []byte, chainID []byte, belowHeight uint64) error {
+	return em.fault("ShredRandomness")
+}
+
+func (em *EOTSManager) KeyRecord(uid []byte, passphrase string) (*types.KeyRecord, error) {
+	if err := em.fault("KeyRecord"); err != nil {
+		return nil, err
+	}
+
+	return em.keyRecord(uid)
+}
+
+func (em *EOTSManager) SignEOTS(uid []byte, chainID []byte, msg []byte, height uint64, passphrase string) (*btcec.ModNScalar, error) {
+	if err := em.fault("SignEOTS"); err != nil {
+		return nil, err
+	}
+
+	record, err := em.keyRecord(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	privRand, _ := randgenerator.GenerateRandomness(record.PrivKey.Serialize(), chainID, height)
+	sig, err := eots.Sign(record.PrivKey, privRand, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	em.mu.Lock()
+	pkHex := hex.EncodeToString(uid)
+	if em.signedHeights[pkHex] == nil {
+		em.signedHeights[pkHex] = make(map[uint64]struct{})
+	}
+	em.signedHeights[pkHex][height] = struct{}{}
+	em.mu.Unlock()
+
+	return sig, nil
+}
+
+func (em *EOTSManager) SignSchnorrSig(uid []byte, msg []byte, passphrase string) (*schnorr.Signature, error) {
+	if err := em.fault("SignSchnorrSig"); err != nil {
+		return nil, err
+	}
+
+	record, err := em.keyRecord(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return schnorr.Sign(record.PrivKey, msg)
+}
+
+func (em *EOTSManager) ListKeys() ([]*types.KeyInfo, error) {
+	if err := em.fault("ListKeys"); err != nil {
+		return nil, err
+	}
+
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	keys := make([]*types.KeyInfo, 0, len(em.keys))
+	for _, record := range em.keys {
+		keys = append(keys, &types.KeyInfo{
+			Name:   record.Name,
+			PubKey: schnorr.SerializePubKey(record.PrivKey.PubKey()),
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name < keys[j].Name
+	})
+
+	return keys, nil
+}
+
+func (em *EOTSManager) Close() error {
+	return nil
+}
+
+// SignedHeights returns the heights signed with the key in ascending order
+func (em *EOTSManager) SignedHeights(uid []byte) []uint64 {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	heights := make([]uint64, 0, len(em.signedHeights[hex.EncodeToString(uid)]))
+	for h := range em.signedHeights[hex.EncodeToString(uid)] {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	return heights
+}
+
+func (em *EOTSManager) keyRecord(uid []byte) (*types.KeyRecord, error) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	record, ok := em.keys[hex.EncodeToString(uid)]
+	if !ok {
+		return nil, fmt.Errorf("the EOTS key %s is not found", hex.EncodeToString(uid))
+	}
+
+	return record, nil
+}
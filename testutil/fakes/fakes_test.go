@@ -0,0 +1,145 @@
+package fakes_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/babylonchain/babylon/crypto/eots"
+	btcstakingtypes "github.com/babylonchain/babylon/x/btcstaking/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/clientcontroller"
+	"github.com/babylonchain/finality-provider/testutil/fakes"
+)
+
+func TestFaults(t *testing.T) {
+	cc := fakes.NewClientController(1)
+	cc.ProduceBlocks(1)
+	faultErr := errors.New("the injected fault")
+
+	// the given number of calls fail
+	cc.InjectFault("QueryBestBlock", faultErr, 2)
+	for i := 0; i < 2; i++ {
+		_, err := cc.QueryBestBlock()
+		require.ErrorIs(t, err, faultErr)
+	}
+	_, err := cc.QueryBestBlock()
+	require.NoError(t, err)
+
+	// all the calls fail until the fault is cleared
+	cc.InjectFault("QueryBestBlock", faultErr, 0)
+	for i := 0; i < 5; i++ {
+		_, err := cc.QueryBestBlock()
+		require.ErrorIs(t, err, faultErr)
+	}
+	// the other methods are not affected
+	_, err = cc.QueryBlock(1)
+	require.NoError(t, err)
+	cc.ClearFault("QueryBestBlock")
+	_, err = cc.QueryBestBlock()
+	require.NoError(t, err)
+}
+
+func TestClientController(t *testing.T) {
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	fpPk := sk.PubKey()
+	var pubRand btcec.FieldVal
+	var sig btcec.ModNScalar
+
+	t.Run("a signed vote is included once it is broadcast", func(t *testing.T) {
+		cc := fakes.NewClientController(1)
+		blocks := cc.ProduceBlocks(3)
+
+		tx, err := cc.SignBatchFinalitySigsTx(fpPk, blocks[:2], []*btcec.FieldVal{&pubRand, &pubRand}, nil, []*btcec.ModNScalar{&sig, &sig})
+		require.NoError(t, err)
+		require.Empty(t, cc.VotedHeights(fpPk))
+		_, err = cc.QueryTx(tx.Hash)
+		require.ErrorIs(t, err, clientcontroller.ErrTxNotFound)
+
+		var accepted int
+		tx.Accepted = func() {
+			accepted++
+		}
+		res, err := cc.BroadcastSignedTx(tx)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1, 2}, cc.VotedHeights(fpPk))
+		included, err := cc.QueryTx(tx.Hash)
+		require.NoError(t, err)
+		require.Equal(t, res, included)
+
+		// a tx broadcast again is included only once
+		again, err := cc.BroadcastSignedTx(tx)
+		require.NoError(t, err)
+		require.Equal(t, res, again)
+		require.Equal(t, 1, accepted)
+	})
+
+	t.Run("a reorg replaces the unfinalized blocks and drops their votes", func(t *testing.T) {
+		cc := fakes.NewClientController(1)
+		blocks := cc.ProduceBlocks(4)
+		for _, b := range blocks {
+			_, err := cc.SubmitFinalitySig(fpPk, b, &pubRand, nil, &sig)
+			require.NoError(t, err)
+		}
+		cc.FinalizeBlocks(2)
+
+		replaced := cc.Reorg(3)
+		require.Len(t, replaced, 2)
+		for _, b := range replaced {
+			require.NotEqual(t, blocks[b.Height-1].Hash, b.Hash)
+		}
+		require.Equal(t, []uint64{1, 2}, cc.VotedHeights(fpPk))
+
+		finalized, err := cc.QueryLatestFinalizedBlocks(10)
+		require.NoError(t, err)
+		require.Len(t, finalized, 2)
+		require.Equal(t, uint64(2), finalized[0].Height)
+	})
+
+	t.Run("the submissions of a slashed finality provider fail", func(t *testing.T) {
+		cc := fakes.NewClientController(1)
+		blocks := cc.ProduceBlocks(1)
+		cc.SetVotingPower(fpPk, 10)
+		cc.SetSlashed(fpPk)
+
+		_, err := cc.SubmitFinalitySig(fpPk, blocks[0], &pubRand, nil, &sig)
+		require.ErrorIs(t, err, btcstakingtypes.ErrFpAlreadySlashed)
+		power, err := cc.QueryFinalityProviderVotingPower(fpPk, 1)
+		require.NoError(t, err)
+		require.Zero(t, power)
+	})
+}
+
+func TestEOTSManager(t *testing.T) {
+	em := fakes.NewEOTSManager()
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	uid := em.AddKey("key", sk)
+	chainID := []byte("chain-test")
+	msg := []byte("the block to sign")
+	startHeight := uint64(10)
+
+	_, err = em.CreateKey("key", "", "")
+	require.Error(t, err)
+
+	// the signatures are verified with the randomness committed
+	prList, err := em.CreateRandomnessPairList(uid, chainID, startHeight, 3, "")
+	require.NoError(t, err)
+	for i, pubRand := range prList {
+		height := startHeight + uint64(i)
+		sig, err := em.SignEOTS(uid, chainID, msg, height, "")
+		require.NoError(t, err)
+		require.NoError(t, eots.Verify(sk.PubKey(), pubRand, msg, sig))
+	}
+	require.Equal(t, []uint64{10, 11, 12}, em.SignedHeights(uid))
+
+	keys, err := em.ListKeys()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, uid, keys[0].PubKey)
+
+	_, err = em.SignEOTS([]byte("unknown"), chainID, msg, startHeight, "")
+	require.Error(t, err)
+}
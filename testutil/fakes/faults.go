@@ -0,0 +1,65 @@
+// Package fakes provides in-memory implementations of the client controller
+// and the EOTS manager with scripted behaviors, which allow testing the
+// integrations with the finality provider without a consumer chain or an
+// EOTS manager daemon. Failures are injected into the calls by method name
+package fakes
+
+import (
+	"sync"
+)
+
+// Faults injects failures into the calls of a fake by method name
+type Faults struct {
+	mu     sync.Mutex
+	faults map[string]*fault
+}
+
+type fault struct {
+	err error
+	// times is the number of calls left to fail, where a negative value
+	// means all the calls fail
+	times int
+}
+
+// InjectFault makes the next given number of calls of the method fail with
+// the error. All the calls fail until the fault is cleared if times is not
+// positive
+func (f *Faults) InjectFault(method string, err error, times int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.faults == nil {
+		f.faults = make(map[string]*fault)
+	}
+	if times <= 0 {
+		times = -1
+	}
+	f.faults[method] = &fault{err: err, times: times}
+}
+
+// ClearFault stops the injected failures of the method
+func (f *Faults) ClearFault(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.faults, method)
+}
+
+// fault returns the injected error of the call of the method, if any
+func (f *Faults) fault(method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ft, ok := f.faults[method]
+	if !ok {
+		return nil
+	}
+	if ft.times > 0 {
+		ft.times--
+		if ft.times == 0 {
+			delete(f.faults, method)
+		}
+	}
+
+	return ft.err
+}
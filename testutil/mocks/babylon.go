@@ -8,10 +8,12 @@ import (
 	reflect "reflect"
 
 	math "cosmossdk.io/math"
-	types "github.com/babylonchain/babylon/x/finality/types"
-	types0 "github.com/babylonchain/finality-provider/types"
+	types "github.com/babylonchain/babylon/types"
+	types0 "github.com/babylonchain/babylon/x/finality/types"
+	types1 "github.com/babylonchain/finality-provider/types"
 	btcec "github.com/btcsuite/btcd/btcec/v2"
 	schnorr "github.com/btcsuite/btcd/btcec/v2/schnorr"
+	types2 "github.com/cosmos/cosmos-sdk/types"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -38,6 +40,21 @@ func (m *MockClientController) EXPECT() *MockClientControllerMockRecorder {
 	return m.recorder
 }
 
+// BroadcastSignedTx mocks base method.
+func (m *MockClientController) BroadcastSignedTx(tx *types1.SignedTx) (*types1.TxResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BroadcastSignedTx", tx)
+	ret0, _ := ret[0].(*types1.TxResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BroadcastSignedTx indicates an expected call of BroadcastSignedTx.
+func (mr *MockClientControllerMockRecorder) BroadcastSignedTx(tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BroadcastSignedTx", reflect.TypeOf((*MockClientController)(nil).BroadcastSignedTx), tx)
+}
+
 // Close mocks base method.
 func (m *MockClientController) Close() error {
 	m.ctrl.T.Helper()
@@ -53,10 +70,10 @@ func (mr *MockClientControllerMockRecorder) Close() *gomock.Call {
 }
 
 // CommitPubRandList mocks base method.
-func (m *MockClientController) CommitPubRandList(fpPk *btcec.PublicKey, startHeight, numPubRand uint64, commitment []byte, sig *schnorr.Signature) (*types0.TxResponse, error) {
+func (m *MockClientController) CommitPubRandList(fpPk *btcec.PublicKey, startHeight, numPubRand uint64, commitment []byte, sig *schnorr.Signature) (*types1.TxResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CommitPubRandList", fpPk, startHeight, numPubRand, commitment, sig)
-	ret0, _ := ret[0].(*types0.TxResponse)
+	ret0, _ := ret[0].(*types1.TxResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -82,11 +99,41 @@ func (mr *MockClientControllerMockRecorder) QueryActivatedHeight() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryActivatedHeight", reflect.TypeOf((*MockClientController)(nil).QueryActivatedHeight))
 }
 
+// QueryActiveFinalityProvidersAtHeight mocks base method.
+func (m *MockClientController) QueryActiveFinalityProvidersAtHeight(height uint64) ([]types.BIP340PubKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryActiveFinalityProvidersAtHeight", height)
+	ret0, _ := ret[0].([]types.BIP340PubKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryActiveFinalityProvidersAtHeight indicates an expected call of QueryActiveFinalityProvidersAtHeight.
+func (mr *MockClientControllerMockRecorder) QueryActiveFinalityProvidersAtHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryActiveFinalityProvidersAtHeight", reflect.TypeOf((*MockClientController)(nil).QueryActiveFinalityProvidersAtHeight), height)
+}
+
+// QueryBalance mocks base method.
+func (m *MockClientController) QueryBalance(address string) (types2.Coins, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryBalance", address)
+	ret0, _ := ret[0].(types2.Coins)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBalance indicates an expected call of QueryBalance.
+func (mr *MockClientControllerMockRecorder) QueryBalance(address interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBalance", reflect.TypeOf((*MockClientController)(nil).QueryBalance), address)
+}
+
 // QueryBestBlock mocks base method.
-func (m *MockClientController) QueryBestBlock() (*types0.BlockInfo, error) {
+func (m *MockClientController) QueryBestBlock() (*types1.BlockInfo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueryBestBlock")
-	ret0, _ := ret[0].(*types0.BlockInfo)
+	ret0, _ := ret[0].(*types1.BlockInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -97,11 +144,26 @@ func (mr *MockClientControllerMockRecorder) QueryBestBlock() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBestBlock", reflect.TypeOf((*MockClientController)(nil).QueryBestBlock))
 }
 
+// QueryBestBlockTime mocks base method.
+func (m *MockClientController) QueryBestBlockTime() (*types1.BlockTime, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryBestBlockTime")
+	ret0, _ := ret[0].(*types1.BlockTime)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryBestBlockTime indicates an expected call of QueryBestBlockTime.
+func (mr *MockClientControllerMockRecorder) QueryBestBlockTime() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBestBlockTime", reflect.TypeOf((*MockClientController)(nil).QueryBestBlockTime))
+}
+
 // QueryBlock mocks base method.
-func (m *MockClientController) QueryBlock(height uint64) (*types0.BlockInfo, error) {
+func (m *MockClientController) QueryBlock(height uint64) (*types1.BlockInfo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueryBlock", height)
-	ret0, _ := ret[0].(*types0.BlockInfo)
+	ret0, _ := ret[0].(*types1.BlockInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -113,10 +175,10 @@ func (mr *MockClientControllerMockRecorder) QueryBlock(height interface{}) *gomo
 }
 
 // QueryBlocks mocks base method.
-func (m *MockClientController) QueryBlocks(startHeight, endHeight, limit uint64) ([]*types0.BlockInfo, error) {
+func (m *MockClientController) QueryBlocks(startHeight, endHeight, limit uint64) ([]*types1.BlockInfo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueryBlocks", startHeight, endHeight, limit)
-	ret0, _ := ret[0].([]*types0.BlockInfo)
+	ret0, _ := ret[0].([]*types1.BlockInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -127,6 +189,21 @@ func (mr *MockClientControllerMockRecorder) QueryBlocks(startHeight, endHeight,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryBlocks", reflect.TypeOf((*MockClientController)(nil).QueryBlocks), startHeight, endHeight, limit)
 }
 
+// QueryChainParams mocks base method.
+func (m *MockClientController) QueryChainParams() (*types1.ChainParams, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryChainParams")
+	ret0, _ := ret[0].(*types1.ChainParams)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryChainParams indicates an expected call of QueryChainParams.
+func (mr *MockClientControllerMockRecorder) QueryChainParams() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryChainParams", reflect.TypeOf((*MockClientController)(nil).QueryChainParams))
+}
+
 // QueryFinalityProviderSlashed mocks base method.
 func (m *MockClientController) QueryFinalityProviderSlashed(fpPk *btcec.PublicKey) (bool, error) {
 	m.ctrl.T.Helper()
@@ -157,11 +234,26 @@ func (mr *MockClientControllerMockRecorder) QueryFinalityProviderVotingPower(fpP
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryFinalityProviderVotingPower", reflect.TypeOf((*MockClientController)(nil).QueryFinalityProviderVotingPower), fpPk, blockHeight)
 }
 
+// QueryFinalitySigsAtHeight mocks base method.
+func (m *MockClientController) QueryFinalitySigsAtHeight(height uint64) ([]*types1.FinalitySig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryFinalitySigsAtHeight", height)
+	ret0, _ := ret[0].([]*types1.FinalitySig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryFinalitySigsAtHeight indicates an expected call of QueryFinalitySigsAtHeight.
+func (mr *MockClientControllerMockRecorder) QueryFinalitySigsAtHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryFinalitySigsAtHeight", reflect.TypeOf((*MockClientController)(nil).QueryFinalitySigsAtHeight), height)
+}
+
 // QueryLastCommittedPublicRand mocks base method.
-func (m *MockClientController) QueryLastCommittedPublicRand(fpPk *btcec.PublicKey, count uint64) (map[uint64]*types.PubRandCommitResponse, error) {
+func (m *MockClientController) QueryLastCommittedPublicRand(fpPk *btcec.PublicKey, count uint64) (map[uint64]*types0.PubRandCommitResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueryLastCommittedPublicRand", fpPk, count)
-	ret0, _ := ret[0].(map[uint64]*types.PubRandCommitResponse)
+	ret0, _ := ret[0].(map[uint64]*types0.PubRandCommitResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -173,10 +265,10 @@ func (mr *MockClientControllerMockRecorder) QueryLastCommittedPublicRand(fpPk, c
 }
 
 // QueryLatestFinalizedBlocks mocks base method.
-func (m *MockClientController) QueryLatestFinalizedBlocks(count uint64) ([]*types0.BlockInfo, error) {
+func (m *MockClientController) QueryLatestFinalizedBlocks(count uint64) ([]*types1.BlockInfo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "QueryLatestFinalizedBlocks", count)
-	ret0, _ := ret[0].([]*types0.BlockInfo)
+	ret0, _ := ret[0].([]*types1.BlockInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -187,11 +279,86 @@ func (mr *MockClientControllerMockRecorder) QueryLatestFinalizedBlocks(count int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryLatestFinalizedBlocks", reflect.TypeOf((*MockClientController)(nil).QueryLatestFinalizedBlocks), count)
 }
 
+// QueryRegisteredFinalityProvider mocks base method.
+func (m *MockClientController) QueryRegisteredFinalityProvider(fpPk *btcec.PublicKey) (*types1.RegisteredFinalityProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryRegisteredFinalityProvider", fpPk)
+	ret0, _ := ret[0].(*types1.RegisteredFinalityProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryRegisteredFinalityProvider indicates an expected call of QueryRegisteredFinalityProvider.
+func (mr *MockClientControllerMockRecorder) QueryRegisteredFinalityProvider(fpPk interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRegisteredFinalityProvider", reflect.TypeOf((*MockClientController)(nil).QueryRegisteredFinalityProvider), fpPk)
+}
+
+// QueryRegisteredFinalityProviders mocks base method.
+func (m *MockClientController) QueryRegisteredFinalityProviders() ([]*types1.RegisteredFinalityProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryRegisteredFinalityProviders")
+	ret0, _ := ret[0].([]*types1.RegisteredFinalityProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryRegisteredFinalityProviders indicates an expected call of QueryRegisteredFinalityProviders.
+func (mr *MockClientControllerMockRecorder) QueryRegisteredFinalityProviders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryRegisteredFinalityProviders", reflect.TypeOf((*MockClientController)(nil).QueryRegisteredFinalityProviders))
+}
+
+// QueryTx mocks base method.
+func (m *MockClientController) QueryTx(hash []byte) (*types1.TxResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryTx", hash)
+	ret0, _ := ret[0].(*types1.TxResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryTx indicates an expected call of QueryTx.
+func (mr *MockClientControllerMockRecorder) QueryTx(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTx", reflect.TypeOf((*MockClientController)(nil).QueryTx), hash)
+}
+
+// QueryVotesAtHeight mocks base method.
+func (m *MockClientController) QueryVotesAtHeight(height uint64) ([]types.BIP340PubKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryVotesAtHeight", height)
+	ret0, _ := ret[0].([]types.BIP340PubKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryVotesAtHeight indicates an expected call of QueryVotesAtHeight.
+func (mr *MockClientControllerMockRecorder) QueryVotesAtHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryVotesAtHeight", reflect.TypeOf((*MockClientController)(nil).QueryVotesAtHeight), height)
+}
+
+// QueryVotingPowersAtHeight mocks base method.
+func (m *MockClientController) QueryVotingPowersAtHeight(height uint64) ([]*types1.FinalityProviderPower, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryVotingPowersAtHeight", height)
+	ret0, _ := ret[0].([]*types1.FinalityProviderPower)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryVotingPowersAtHeight indicates an expected call of QueryVotingPowersAtHeight.
+func (mr *MockClientControllerMockRecorder) QueryVotingPowersAtHeight(height interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryVotingPowersAtHeight", reflect.TypeOf((*MockClientController)(nil).QueryVotingPowersAtHeight), height)
+}
+
 // RegisterFinalityProvider mocks base method.
-func (m *MockClientController) RegisterFinalityProvider(chainPk []byte, fpPk *btcec.PublicKey, pop []byte, commission *math.LegacyDec, description []byte) (*types0.TxResponse, error) {
+func (m *MockClientController) RegisterFinalityProvider(chainPk []byte, fpPk *btcec.PublicKey, pop []byte, commission *math.LegacyDec, description []byte) (*types1.TxResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RegisterFinalityProvider", chainPk, fpPk, pop, commission, description)
-	ret0, _ := ret[0].(*types0.TxResponse)
+	ret0, _ := ret[0].(*types1.TxResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -202,11 +369,56 @@ func (mr *MockClientControllerMockRecorder) RegisterFinalityProvider(chainPk, fp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterFinalityProvider", reflect.TypeOf((*MockClientController)(nil).RegisterFinalityProvider), chainPk, fpPk, pop, commission, description)
 }
 
+// SetRewardAddress mocks base method.
+func (m *MockClientController) SetRewardAddress(chainPk []byte, rewardAddr string) (*types1.TxResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRewardAddress", chainPk, rewardAddr)
+	ret0, _ := ret[0].(*types1.TxResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRewardAddress indicates an expected call of SetRewardAddress.
+func (mr *MockClientControllerMockRecorder) SetRewardAddress(chainPk, rewardAddr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRewardAddress", reflect.TypeOf((*MockClientController)(nil).SetRewardAddress), chainPk, rewardAddr)
+}
+
+// SignBatchFinalitySigsTx mocks base method.
+func (m *MockClientController) SignBatchFinalitySigsTx(fpPk *btcec.PublicKey, blocks []*types1.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types1.SignedTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignBatchFinalitySigsTx", fpPk, blocks, pubRandList, proofList, sigs)
+	ret0, _ := ret[0].(*types1.SignedTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignBatchFinalitySigsTx indicates an expected call of SignBatchFinalitySigsTx.
+func (mr *MockClientControllerMockRecorder) SignBatchFinalitySigsTx(fpPk, blocks, pubRandList, proofList, sigs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignBatchFinalitySigsTx", reflect.TypeOf((*MockClientController)(nil).SignBatchFinalitySigsTx), fpPk, blocks, pubRandList, proofList, sigs)
+}
+
+// SignFinalitySigTx mocks base method.
+func (m *MockClientController) SignFinalitySigTx(fpPk *btcec.PublicKey, block *types1.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types1.SignedTx, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignFinalitySigTx", fpPk, block, pubRand, proof, sig)
+	ret0, _ := ret[0].(*types1.SignedTx)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignFinalitySigTx indicates an expected call of SignFinalitySigTx.
+func (mr *MockClientControllerMockRecorder) SignFinalitySigTx(fpPk, block, pubRand, proof, sig interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignFinalitySigTx", reflect.TypeOf((*MockClientController)(nil).SignFinalitySigTx), fpPk, block, pubRand, proof, sig)
+}
+
 // SubmitBatchFinalitySigs mocks base method.
-func (m *MockClientController) SubmitBatchFinalitySigs(fpPk *btcec.PublicKey, blocks []*types0.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types0.TxResponse, error) {
+func (m *MockClientController) SubmitBatchFinalitySigs(fpPk *btcec.PublicKey, blocks []*types1.BlockInfo, pubRandList []*btcec.FieldVal, proofList [][]byte, sigs []*btcec.ModNScalar) (*types1.TxResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SubmitBatchFinalitySigs", fpPk, blocks, pubRandList, proofList, sigs)
-	ret0, _ := ret[0].(*types0.TxResponse)
+	ret0, _ := ret[0].(*types1.TxResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -218,10 +430,10 @@ func (mr *MockClientControllerMockRecorder) SubmitBatchFinalitySigs(fpPk, blocks
 }
 
 // SubmitFinalitySig mocks base method.
-func (m *MockClientController) SubmitFinalitySig(fpPk *btcec.PublicKey, block *types0.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types0.TxResponse, error) {
+func (m *MockClientController) SubmitFinalitySig(fpPk *btcec.PublicKey, block *types1.BlockInfo, pubRand *btcec.FieldVal, proof []byte, sig *btcec.ModNScalar) (*types1.TxResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SubmitFinalitySig", fpPk, block, pubRand, proof, sig)
-	ret0, _ := ret[0].(*types0.TxResponse)
+	ret0, _ := ret[0].(*types1.TxResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
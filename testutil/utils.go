@@ -3,6 +3,7 @@ package testutil
 import (
 	"math/rand"
 	"testing"
+	"time"
 
 	sdkmath "cosmossdk.io/math"
 	"github.com/golang/mock/gomock"
@@ -38,6 +39,10 @@ func PrepareMockedClientController(t *testing.T, r *rand.Rand, startHeight, curr
 	mockClientController.EXPECT().Close().Return(nil).AnyTimes()
 	mockClientController.EXPECT().QueryBestBlock().Return(currentBlockRes, nil).AnyTimes()
 	mockClientController.EXPECT().QueryActivatedHeight().Return(uint64(1), nil).AnyTimes()
+	mockClientController.EXPECT().QueryBestBlockTime().Return(&types.BlockTime{Height: currentHeight, Time: time.Now()}, nil).AnyTimes()
+	mockClientController.EXPECT().QueryChainParams().Return(&types.ChainParams{
+		MinCommissionRate: sdkmath.LegacyZeroDec(),
+	}, nil).AnyTimes()
 
 	return mockClientController
 }
@@ -1,7 +1,15 @@
 package types
 
+import "time"
+
 type BlockInfo struct {
 	Height    uint64
 	Hash      []byte
 	Finalized bool
 }
+
+// BlockTime is the height and the timestamp of a block
+type BlockTime struct {
+	Height uint64
+	Time   time.Time
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	bbn "github.com/babylonchain/babylon/types"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// ParseBTCPkHex parses the hex string of a BTC public key in either the
+// 32-byte x-only encoding of BIP-340 or the 33-byte compressed encoding, as
+// the integrators often have the latter at hand. The key is normalized to
+// BIP-340, which drops the parity of the y-coordinate
+func ParseBTCPkHex(pkHex string) (*bbn.BIP340PubKey, error) {
+	pkBytes, err := hex.DecodeString(pkHex)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(pkBytes) {
+	case schnorr.PubKeyBytesLen:
+		return bbn.NewBIP340PubKey(pkBytes)
+	case btcec.PubKeyBytesLenCompressed:
+		pk, err := btcec.ParsePubKey(pkBytes)
+		if err != nil {
+			return nil, err
+		}
+		return bbn.NewBIP340PubKeyFromBTCPK(pk), nil
+	default:
+		return nil, fmt.Errorf("expected %d bytes in the BIP-340 encoding or %d bytes in the compressed encoding, got %d",
+			schnorr.PubKeyBytesLen, btcec.PubKeyBytesLenCompressed, len(pkBytes))
+	}
+}
+
+// CompressedBTCPkHex returns the hex string of the 33-byte compressed
+// encoding of the BIP-340 public key, whose y-coordinate is the even one as
+// BIP-340 implies, or an empty string if the key is invalid
+func CompressedBTCPkHex(bip340PkHex string) string {
+	pk, err := bbn.NewBIP340PubKeyFromHex(bip340PkHex)
+	if err != nil {
+		return ""
+	}
+	btcPk, err := pk.ToBTCPK()
+	if err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(btcPk.SerializeCompressed())
+}
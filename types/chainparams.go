@@ -0,0 +1,15 @@
+package types
+
+import (
+	sdkmath "cosmossdk.io/math"
+)
+
+// ChainParams are the on-chain parameters that finality providers must
+// comply with, which are checked locally before broadcasting
+type ChainParams struct {
+	// MinCommissionRate is the minimum commission rate of finality providers
+	MinCommissionRate sdkmath.LegacyDec
+
+	// MinPubRand is the minimum number of public randomness in each commit
+	MinPubRand uint64
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// FinalitySig is a finality signature accepted by the consumer chain
+type FinalitySig struct {
+	FpBtcPk *btcec.PublicKey
+	Height  uint64
+	// BlockHash is the hash of the signed block, which differs from the
+	// hash of the block at the height if the signature is an equivocation
+	BlockHash []byte
+	// PubRand and Sig are the 32-byte public randomness and EOTS signature
+	PubRand []byte
+	Sig     []byte
+	// TxHash is the hash of the tx including the signature
+	TxHash string
+}
+
+// FinalityProviderPower is the voting power of a finality provider at a height
+type FinalityProviderPower struct {
+	FpBtcPk     *btcec.PublicKey
+	VotingPower uint64
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+// KeyOwnershipProofTag is the tag of the BIP-340 tagged hash signed to prove
+// the ownership of a BTC key. The tag separates the proofs from any other
+// message signed with the key, e.g., the proof-of-possession, which is over
+// the plain SHA-256 of the chain address, so a challenge chosen by a third
+// party can never yield a signature valid for another purpose. The EOTS
+// signatures use the committed randomness instead of the BIP-340 nonce, so
+// they are separated by construction
+const KeyOwnershipProofTag = "babylon/finality-provider/btc-key-ownership"
+
+var ErrInvalidKeyOwnershipProof = errors.New("invalid BTC key ownership proof")
+
+// KeyOwnershipProofHash returns the hash signed to prove the ownership of the
+// BTC key over the challenge, which commits to the key as well so that a
+// proof cannot be presented for another key
+func KeyOwnershipProofHash(btcPk *btcec.PublicKey, challenge []byte) []byte {
+	tagHash := sha256.Sum256([]byte(KeyOwnershipProofTag))
+
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	h.Write(schnorr.SerializePubKey(btcPk))
+	h.Write(challenge)
+
+	return h.Sum(nil)
+}
+
+// VerifyKeyOwnershipProof verifies the BIP-340 signature proving the ownership
+// of the BTC key over the challenge
+func VerifyKeyOwnershipProof(btcPk *btcec.PublicKey, challenge, sigBytes []byte) error {
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidKeyOwnershipProof, err)
+	}
+	if !sig.Verify(KeyOwnershipProofHash(btcPk, challenge), btcPk) {
+		return fmt.Errorf("%w: the signature does not match the key and the challenge", ErrInvalidKeyOwnershipProof)
+	}
+
+	return nil
+}
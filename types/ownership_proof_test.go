@@ -0,0 +1,38 @@
+package types_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/types"
+)
+
+func TestKeyOwnershipProof(t *testing.T) {
+	sk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	challenge := []byte("listing-site-nonce-42")
+
+	sig, err := schnorr.Sign(sk, types.KeyOwnershipProofHash(sk.PubKey(), challenge))
+	require.NoError(t, err)
+	require.NoError(t, types.VerifyKeyOwnershipProof(sk.PubKey(), challenge, sig.Serialize()))
+
+	err = types.VerifyKeyOwnershipProof(sk.PubKey(), []byte("another challenge"), sig.Serialize())
+	require.True(t, errors.Is(err, types.ErrInvalidKeyOwnershipProof))
+
+	otherSk, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	err = types.VerifyKeyOwnershipProof(otherSk.PubKey(), challenge, sig.Serialize())
+	require.True(t, errors.Is(err, types.ErrInvalidKeyOwnershipProof))
+
+	// a signature over the plain hash of the challenge is not a proof
+	plainHash := sha256.Sum256(challenge)
+	plainSig, err := schnorr.Sign(sk, plainHash[:])
+	require.NoError(t, err)
+	err = types.VerifyKeyOwnershipProof(sk.PubKey(), challenge, plainSig.Serialize())
+	require.True(t, errors.Is(err, types.ErrInvalidKeyOwnershipProof))
+}
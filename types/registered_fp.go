@@ -0,0 +1,21 @@
+package types
+
+import (
+	sdkmath "cosmossdk.io/math"
+	"github.com/btcsuite/btcd/btcec/v2"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+// RegisteredFinalityProvider is the record of a finality provider registered
+// on the consumer chain
+type RegisteredFinalityProvider struct {
+	BtcPk *btcec.PublicKey
+	// ChainPk is the compressed secp256k1 PK of the account registering the
+	// finality provider
+	ChainPk     []byte
+	Description *stakingtypes.Description
+	Commission  *sdkmath.LegacyDec
+	// SlashedBtcHeight is the BTC height at which the finality provider is
+	// slashed, which is zero if it is not slashed
+	SlashedBtcHeight uint64
+}
@@ -6,5 +6,18 @@ import (
 
 type TxResponse struct {
 	TxHash string
+	// Height is the height of the block including the tx, which is 0 if
+	// it is unknown
+	Height uint64
 	Events []provider.RelayerEvent
 }
+
+// SignedTx is a tx that is signed but not broadcast yet, which is kept as is
+// so that the same tx is looked up by its hash or broadcast again
+type SignedTx struct {
+	Bytes []byte
+	Hash  []byte
+	// Accepted, if not nil, is called once the node accepts the broadcast of
+	// the tx, before its inclusion is awaited
+	Accepted func()
+}
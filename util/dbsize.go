@@ -0,0 +1,29 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CheckDBFileSize returns an error if the bolt database file is too large to
+// be mapped into memory on this platform, instead of bbolt failing to map it
+// with an obscure error. A missing file is not an error as the database is
+// created then
+func CheckDBFileSize(dbPath, dbFileName string) error {
+	dbFile := filepath.Join(dbPath, dbFileName)
+	info, err := os.Stat(dbFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if info.Size() > MaxDBFileSize {
+		return fmt.Errorf("the database %s of %d bytes exceeds the maximum size %d of this platform, "+
+			"compact it with autocompact on a 64-bit host or keep running it there", dbFile, info.Size(), MaxDBFileSize)
+	}
+
+	return nil
+}
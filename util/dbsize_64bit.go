@@ -0,0 +1,7 @@
+//go:build !(386 || arm || mips || mipsle)
+
+package util
+
+// MaxDBFileSize is the size of the largest bolt database that can be mapped
+// into memory, which is the limit of bbolt on 64-bit platforms
+const MaxDBFileSize int64 = 0xFFFFFFFFFFFF
@@ -0,0 +1,32 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/util"
+)
+
+func TestCheckDBFileSize(t *testing.T) {
+	dbPath := t.TempDir()
+	dbFileName := "test.db"
+
+	// a missing database is created
+	require.NoError(t, util.CheckDBFileSize(dbPath, dbFileName))
+
+	f, err := os.Create(filepath.Join(dbPath, dbFileName))
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, util.CheckDBFileSize(dbPath, dbFileName))
+
+	// the limit of 64-bit platforms is beyond the files of the test
+	// filesystems, so the oversized database is only checked on 32-bit ones
+	if util.MaxDBFileSize > 1<<32 {
+		t.Skip("the oversized database is only checked on 32-bit platforms")
+	}
+	require.NoError(t, f.Truncate(util.MaxDBFileSize+1))
+	require.Error(t, util.CheckDBFileSize(dbPath, dbFileName))
+}
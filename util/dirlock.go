@@ -0,0 +1,91 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DirLockFileName is the name of the file locked in a directory by LockDir
+const DirLockFileName = "daemon.lock"
+
+// ErrDirLocked is returned by LockDir if the directory is locked by another
+// process
+var ErrDirLocked = errors.New("the directory is locked by another process")
+
+// DirLock is an exclusive lock on a directory, which is held until it is
+// released or the process exits
+type DirLock struct {
+	file *os.File
+}
+
+// LockDir takes an exclusive lock on the directory, which is created if it
+// does not exist, and records the PID of the process in the lock file. The
+// lock is advisory and held on the open file, so it is released by the OS if
+// the process crashes and a stale lock file never blocks a restart
+func LockDir(dir string) (*DirLock, error) {
+	if err := MakeDirectory(dir); err != nil {
+		return nil, err
+	}
+
+	lockPath := filepath.Join(dir, DirLockFileName)
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock file %s: %w", lockPath, err)
+	}
+
+	if err := tryLockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%w: %s is in use by the process with PID %s, "+
+			"two daemons must never share the same directory as it risks double signing: %w",
+			ErrDirLocked, dir, readLockPid(lockPath), err)
+	}
+
+	if err := writeLockPid(f); err != nil {
+		unlockFile(f)
+		f.Close()
+		return nil, fmt.Errorf("failed to write the lock file %s: %w", lockPath, err)
+	}
+
+	return &DirLock{file: f}, nil
+}
+
+// Release releases the lock. The lock file is kept, as removing it could let
+// another process lock a new file while this one is still locked
+func (l *DirLock) Release() error {
+	if err := unlockFile(l.file); err != nil {
+		l.file.Close()
+		return err
+	}
+
+	return l.file.Close()
+}
+
+func writeLockPid(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// readLockPid returns the PID recorded in the lock file, or "unknown" if it
+// cannot be read
+func readLockPid(lockPath string) string {
+	bz, err := os.ReadFile(lockPath)
+	if err != nil {
+		return "unknown"
+	}
+	pid := strings.TrimSpace(string(bz))
+	if pid == "" {
+		return "unknown"
+	}
+
+	return pid
+}
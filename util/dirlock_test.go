@@ -0,0 +1,35 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/util"
+)
+
+func TestLockDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+
+	lock, err := util.LockDir(dir)
+	require.NoError(t, err)
+
+	bz, err := os.ReadFile(filepath.Join(dir, util.DirLockFileName))
+	require.NoError(t, err)
+	require.Equal(t, strconv.Itoa(os.Getpid()), strings.TrimSpace(string(bz)))
+
+	// a second lock on the same directory fails while the first one is held
+	_, err = util.LockDir(dir)
+	require.ErrorIs(t, err, util.ErrDirLocked)
+	require.Contains(t, err.Error(), strconv.Itoa(os.Getpid()))
+
+	// the directory can be locked again once released
+	require.NoError(t, lock.Release())
+	lock, err = util.LockDir(dir)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
@@ -0,0 +1,236 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+const (
+	// SecretTag marks the string fields of a config that may reference a
+	// secret by a URI instead of holding it in plaintext
+	SecretTag = "secret"
+
+	secretSchemeFile  = "file"
+	secretSchemeVault = "vault"
+	secretSchemeAWSSM = "aws-sm"
+
+	secretResolveTimeout = 30 * time.Second
+)
+
+// ResolveSecrets replaces the secret URIs in the fields of the config tagged
+// with `secret:"true"`, or in the values of such map fields, by the secrets
+// they reference, descending into the config groups. The fields not holding
+// a secret URI are left as is.
+// The supported URIs are:
+//   - file://<path>, whose content is the secret
+//   - vault://<path>#<field>, read from the HashiCorp Vault at VAULT_ADDR
+//     with the token VAULT_TOKEN, e.g., vault://secret/data/fpd#token
+//   - aws-sm://<secret id>[?region=<region>][#<json key>], read from the
+//     AWS Secrets Manager with the default credential chain
+func ResolveSecrets(cfg interface{}) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the config should be a pointer to a struct")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+
+	return resolveSecrets(ctx, v.Elem())
+}
+
+func resolveSecrets(ctx context.Context, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, fv := t.Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if _, isGroup := field.Tag.Lookup("group"); isGroup {
+			if fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := resolveSecrets(ctx, fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if field.Tag.Get(SecretTag) != "true" {
+			continue
+		}
+		switch {
+		case fv.Kind() == reflect.String:
+			secret, err := resolveSecretValue(ctx, fv.String())
+			if err != nil {
+				return fmt.Errorf("failed to resolve the secret of %s: %w", field.Name, err)
+			}
+			fv.SetString(secret)
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String:
+			for j := 0; j < fv.Len(); j++ {
+				secret, err := resolveSecretValue(ctx, fv.Index(j).String())
+				if err != nil {
+					return fmt.Errorf("failed to resolve the secret of %s: %w", field.Name, err)
+				}
+				fv.Index(j).SetString(secret)
+			}
+		case fv.Kind() == reflect.Map && fv.Type().Elem().Kind() == reflect.String:
+			iter := fv.MapRange()
+			for iter.Next() {
+				secret, err := resolveSecretValue(ctx, iter.Value().String())
+				if err != nil {
+					return fmt.Errorf("failed to resolve the secret of %s: %w", field.Name, err)
+				}
+				fv.SetMapIndex(iter.Key(), reflect.ValueOf(secret).Convert(fv.Type().Elem()))
+			}
+		default:
+			return fmt.Errorf("the secret field %s should be a string, a list or a map of strings", field.Name)
+		}
+	}
+
+	return nil
+}
+
+// resolveSecretValue resolves the value if it is a secret URI, or returns it
+// as is otherwise
+func resolveSecretValue(ctx context.Context, value string) (string, error) {
+	scheme, _, found := strings.Cut(value, "://")
+	if !found {
+		return value, nil
+	}
+
+	switch scheme {
+	case secretSchemeFile, secretSchemeVault, secretSchemeAWSSM:
+		return ResolveSecret(ctx, value)
+	default:
+		return value, nil
+	}
+}
+
+// ResolveSecret returns the secret referenced by the URI
+func ResolveSecret(ctx context.Context, uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid secret URI: %w", err)
+	}
+
+	switch u.Scheme {
+	case secretSchemeFile:
+		return resolveFileSecret(u)
+	case secretSchemeVault:
+		return resolveVaultSecret(ctx, u)
+	case secretSchemeAWSSM:
+		return resolveAWSSecret(ctx, u)
+	default:
+		return "", fmt.Errorf("unsupported secret URI scheme %s", u.Scheme)
+	}
+}
+
+func resolveFileSecret(u *url.URL) (string, error) {
+	// file://relative/path has the first segment parsed as the host
+	path := CleanAndExpandPath(u.Host + u.Path)
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the secret file: %w", err)
+	}
+
+	return strings.TrimRight(string(bz), "\r\n"), nil
+}
+
+func resolveVaultSecret(ctx context.Context, u *url.URL) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN should be set to read secrets from Vault")
+	}
+	if u.Fragment == "" {
+		return "", fmt.Errorf("the field of the Vault secret should be given after #")
+	}
+
+	path := strings.TrimPrefix(u.Host+u.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the Vault secret: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to read the Vault secret %s: status %s", path, resp.Status)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid response from Vault: %w", err)
+	}
+
+	// the KV version 2 engine nests the secret in another data field
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	secret, ok := data[u.Fragment].(string)
+	if !ok {
+		return "", fmt.Errorf("the Vault secret %s has no string field %s", path, u.Fragment)
+	}
+
+	return secret, nil
+}
+
+func resolveAWSSecret(ctx context.Context, u *url.URL) (string, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create the AWS session: %w", err)
+	}
+	awsCfg := aws.NewConfig()
+	if region := u.Query().Get("region"); region != "" {
+		awsCfg = awsCfg.WithRegion(region)
+	}
+
+	secretID := strings.TrimPrefix(u.Host+u.Path, "/")
+	out, err := secretsmanager.New(sess, awsCfg).GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read the AWS secret %s: %w", secretID, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("the AWS secret %s is not a string", secretID)
+	}
+
+	if u.Fragment == "" {
+		return *out.SecretString, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(*out.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("the AWS secret %s is not a JSON object: %w", secretID, err)
+	}
+	secret, ok := fields[u.Fragment].(string)
+	if !ok {
+		return "", fmt.Errorf("the AWS secret %s has no string key %s", secretID, u.Fragment)
+	}
+
+	return secret, nil
+}
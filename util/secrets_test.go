@@ -0,0 +1,49 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/util"
+)
+
+type testGroupConfig struct {
+	Token string `long:"token" secret:"true"`
+}
+
+type testConfig struct {
+	Plain  string           `long:"plain"`
+	URL    string           `long:"url" secret:"true"`
+	URLs   []string         `long:"urls" secret:"true"`
+	Group  *testGroupConfig `group:"group" namespace:"group"`
+	Absent *testGroupConfig `group:"absent" namespace:"absent"`
+}
+
+func TestResolveSecrets(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secretFile, []byte("s3cret\n"), 0600)
+	require.NoError(t, err)
+	secretURI := "file://" + secretFile
+
+	cfg := &testConfig{
+		Plain: secretURI,
+		URL:   "http://127.0.0.1:26657",
+		URLs:  []string{secretURI, "http://127.0.0.1:8080"},
+		Group: &testGroupConfig{Token: secretURI},
+	}
+	err = util.ResolveSecrets(cfg)
+	require.NoError(t, err)
+
+	// only the tagged fields holding secret URIs are resolved
+	require.Equal(t, secretURI, cfg.Plain)
+	require.Equal(t, "http://127.0.0.1:26657", cfg.URL)
+	require.Equal(t, []string{"s3cret", "http://127.0.0.1:8080"}, cfg.URLs)
+	require.Equal(t, "s3cret", cfg.Group.Token)
+
+	cfg.URL = "file://" + filepath.Join(t.TempDir(), "missing")
+	err = util.ResolveSecrets(cfg)
+	require.Error(t, err)
+}
@@ -0,0 +1,17 @@
+//go:build !unix
+
+package securemem
+
+import (
+	"errors"
+)
+
+var errLockUnsupported = errors.New("locking memory is not supported on this platform")
+
+func allocLocked(_ int) ([]byte, error) {
+	return nil, errLockUnsupported
+}
+
+func freeLocked(_ []byte) error {
+	return errLockUnsupported
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package securemem
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// allocLocked maps anonymous pages that are locked in memory, which are
+// neither moved by the Go runtime nor swapped to disk
+func allocLocked(size int) ([]byte, error) {
+	b, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Mlock(b); err != nil {
+		_ = unix.Munmap(b)
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func freeLocked(b []byte) error {
+	if err := unix.Munlock(b); err != nil {
+		return err
+	}
+
+	return unix.Munmap(b)
+}
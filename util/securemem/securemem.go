@@ -0,0 +1,75 @@
+// Package securemem holds secrets, e.g., private keys and passphrases, in
+// memory that is wiped after use and optionally locked so that it is never
+// swapped to disk.
+//
+// Note that the secrets passed around as Go strings cannot be wiped, as the
+// strings are immutable, so the secrets should be kept in byte slices as far
+// as the APIs in use allow
+package securemem
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Zero wipes the bytes in place
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	runtime.KeepAlive(b)
+}
+
+// Buffer is a fixed-size buffer of a secret, which is wiped when destroyed
+type Buffer struct {
+	b      []byte
+	locked bool
+}
+
+// NewBuffer allocates a zeroed buffer of the given size. If lock is set, the
+// buffer is allocated outside the Go heap and locked in memory, which fails
+// if the platform does not support it or the limit of locked memory, e.g.,
+// RLIMIT_MEMLOCK on Linux, is reached
+func NewBuffer(size int, lock bool) (*Buffer, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("the buffer size should be positive, got %d", size)
+	}
+
+	if !lock {
+		return &Buffer{b: make([]byte, size)}, nil
+	}
+
+	b, err := allocLocked(size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %d bytes in memory: %w", size, err)
+	}
+
+	return &Buffer{b: b, locked: true}, nil
+}
+
+// Bytes returns the content of the buffer, which must not be used after the
+// buffer is destroyed
+func (buf *Buffer) Bytes() []byte {
+	return buf.b
+}
+
+// Locked tells whether the buffer is locked in memory
+func (buf *Buffer) Locked() bool {
+	return buf.locked
+}
+
+// Destroy wipes and releases the buffer. It is safe to destroy a buffer more
+// than once
+func (buf *Buffer) Destroy() {
+	if buf == nil || buf.b == nil {
+		return
+	}
+
+	Zero(buf.b)
+	if buf.locked {
+		// the buffer is already wiped, so a failure to release it only
+		// leaks the zeroed pages
+		_ = freeLocked(buf.b)
+	}
+	buf.b = nil
+}
@@ -0,0 +1,31 @@
+package securemem_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/babylonchain/finality-provider/util/securemem"
+)
+
+func TestBuffer(t *testing.T) {
+	secret := []byte("secret")
+	securemem.Zero(secret)
+	require.Equal(t, make([]byte, len(secret)), secret)
+
+	for _, lock := range []bool{false, true} {
+		buf, err := securemem.NewBuffer(32, lock)
+		if lock && err != nil {
+			t.Skipf("locking memory is not available: %v", err)
+		}
+		require.NoError(t, err)
+		require.Equal(t, lock, buf.Locked())
+		require.Len(t, buf.Bytes(), 32)
+
+		copy(buf.Bytes(), "secret")
+		buf.Destroy()
+		require.Nil(t, buf.Bytes())
+		// destroying the buffer again is a no-op
+		buf.Destroy()
+	}
+}
@@ -28,6 +28,11 @@ var (
 	// GoVersion stores the go version that the executable was compiled
 	// with.
 	GoVersion string
+
+	// BuildDate stores the date of this build. This should be set using the
+	// -ldflags during compilation, and falls back to the time of the commit
+	// otherwise.
+	BuildDate string
 )
 
 // semanticAlphabet is the set of characters that are permitted for use in an
@@ -122,6 +127,11 @@ func init() {
 			case "vcs.revision":
 				CommitHash = setting.Value
 
+			case "vcs.time":
+				if BuildDate == "" {
+					BuildDate = setting.Value
+				}
+
 			case "-tags":
 				RawTags = setting.Value
 			}